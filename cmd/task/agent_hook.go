@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// agentHookHandler maps one executor's native hook payload onto the task
+// transitions the Claude hooks drive.
+type agentHookHandler func(database *db.DB, taskID int64, event string, payload []byte) error
+
+// agentHookHandlers lists the executors `ty agent-hook` understands. Claude
+// keeps its own `claude-hook` command.
+var agentHookHandlers = map[string]agentHookHandler{
+	"codex": handleCodexHook,
+}
+
+func newAgentHookCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "agent-hook",
+		Short:  "Handle lifecycle hook callbacks from non-Claude executors",
+		Hidden: true, // Internal use only - invoked by executor hook configs
+		Run: func(cmd *cobra.Command, args []string) {
+			executorName, _ := cmd.Flags().GetString("executor")
+			event, _ := cmd.Flags().GetString("event")
+			if err := handleAgentHook(executorName, event, os.Stdin); err != nil {
+				// Don't print errors - hooks should be silent
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().String("executor", "", "Executor that fired the hook (codex)")
+	cmd.Flags().String("event", "", "Hook event type (PreToolUse, PostToolUse, Stop, ...)")
+	return cmd
+}
+
+// handleAgentHook reads a hook payload from r and applies it to the task named
+// by WORKTREE_TASK_ID. Unknown executors and events are ignored.
+func handleAgentHook(executorName, event string, r io.Reader) error {
+	handler, ok := agentHookHandlers[executorName]
+	if !ok {
+		return nil
+	}
+	// Sessions not launched by ty have no task to update.
+	taskIDStr := os.Getenv("WORKTREE_TASK_ID")
+	if taskIDStr == "" {
+		return nil
+	}
+	var taskID int64
+	if _, err := fmt.Sscanf(taskIDStr, "%d", &taskID); err != nil {
+		return fmt.Errorf("invalid WORKTREE_TASK_ID: %s", taskIDStr)
+	}
+
+	payload, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("read hook input: %w", err)
+	}
+
+	database, err := openTaskDB(db.DefaultPath())
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer database.Close()
+
+	return handler(database, taskID, event, payload)
+}
+
+// codexHookInput is the JSON Codex sends to its lifecycle hooks. Tool events
+// use the same snake_case fields as Claude's hooks.
+type codexHookInput struct {
+	SessionID            string          `json:"session_id"`
+	Cwd                  string          `json:"cwd"`
+	HookEventName        string          `json:"hook_event_name"`
+	ToolName             string          `json:"tool_name,omitempty"`
+	ToolInput            json.RawMessage `json:"tool_input,omitempty"`
+	ToolResponse         json.RawMessage `json:"tool_response,omitempty"`
+	LastAssistantMessage string          `json:"last_assistant_message,omitempty"`
+}
+
+// handleCodexHook applies a Codex hook. Codex has no Notification hook, so
+// Stop (end of turn) is what marks a task blocked; the next prompt or tool call
+// puts it back to processing.
+func handleCodexHook(database *db.DB, taskID int64, event string, payload []byte) error {
+	var input codexHookInput
+	if err := json.Unmarshal(payload, &input); err != nil {
+		return fmt.Errorf("decode codex hook input: %w", err)
+	}
+	claudeInput := &ClaudeHookInput{
+		SessionID:            input.SessionID,
+		Cwd:                  input.Cwd,
+		HookEventName:        event,
+		ToolName:             input.ToolName,
+		ToolInput:            input.ToolInput,
+		ToolResponse:         input.ToolResponse,
+		LastAssistantMessage: input.LastAssistantMessage,
+	}
+
+	switch event {
+	case "SessionStart":
+		recordCodexSession(database, taskID, input.SessionID)
+		return nil
+	case "UserPromptSubmit", "PreToolUse":
+		return resumeBlockedTask(database, taskID)
+	case "PostToolUse":
+		return handlePostToolUseHook(database, taskID, claudeInput)
+	case "Stop":
+		claudeInput.StopReason = "end_turn"
+		return handleStopHook(database, taskID, claudeInput)
+	default:
+		// Unknown hook type, ignore
+		return nil
+	}
+}
+
+// recordCodexSession stores the Codex session ID so the task can resume it.
+func recordCodexSession(database *db.DB, taskID int64, sessionID string) {
+	if sessionID == "" {
+		return
+	}
+	task, err := database.GetTask(taskID)
+	if err != nil || task == nil || task.ClaudeSessionID == sessionID {
+		return
+	}
+	database.UpdateTaskClaudeSessionID(taskID, sessionID)
+	database.AppendTaskLog(taskID, "system", "Codex session: "+sessionID)
+}
+
+// resumeBlockedTask moves a started task that is blocked back to processing,
+// as the Claude PreToolUse hook does when the agent starts working again.
+func resumeBlockedTask(database *db.DB, taskID int64) error {
+	task, err := database.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+	if task == nil || task.StartedAt == nil {
+		return nil
+	}
+	if task.Status == db.StatusBlocked {
+		database.UpdateTaskStatus(taskID, db.StatusProcessing)
+		database.AppendTaskLog(taskID, "system", "Agent resumed working")
+	}
+	return nil
+}
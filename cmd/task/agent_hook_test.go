@@ -0,0 +1,81 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestHandleCodexHook(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	id := createTestTasks(t, database, 1)[0]
+	if err := database.UpdateTaskStatus(id, db.StatusProcessing); err != nil {
+		t.Fatal(err)
+	}
+
+	status := func() string {
+		t.Helper()
+		task, err := database.GetTask(id)
+		if err != nil || task == nil {
+			t.Fatalf("get task: %v", err)
+		}
+		return task.Status
+	}
+
+	if err := handleCodexHook(database, id, "SessionStart", []byte(`{"session_id":"abc-123","hook_event_name":"SessionStart"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if task, _ := database.GetTask(id); task.ClaudeSessionID != "abc-123" {
+		t.Errorf("session id = %q, want it recorded", task.ClaudeSessionID)
+	}
+
+	// End of turn: Codex is waiting on the user.
+	if err := handleCodexHook(database, id, "Stop", []byte(`{"hook_event_name":"Stop","last_assistant_message":"Done?"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if got := status(); got != db.StatusBlocked {
+		t.Errorf("after Stop status = %q, want blocked", got)
+	}
+	// Its last message is the question carried on task.blocked.
+	events, err := database.ListEventsAfter(0, "task.blocked")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) == 0 || events[len(events)-1].Message != "Done?" {
+		t.Errorf("task.blocked events = %+v, want the last one to carry %q", events, "Done?")
+	}
+
+	// The next tool call means it is working again.
+	if err := handleCodexHook(database, id, "PreToolUse", []byte(`{"tool_name":"Bash","tool_input":{"command":"go test ./..."}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if got := status(); got != db.StatusProcessing {
+		t.Errorf("after PreToolUse status = %q, want processing", got)
+	}
+
+	if err := handleCodexHook(database, id, "PostToolUse", []byte(`{"tool_name":"Bash","tool_input":{"command":"go test ./..."}}`)); err != nil {
+		t.Fatal(err)
+	}
+	logs, _ := database.GetTaskLogs(id, 10)
+	if len(logs) == 0 || logs[0].LineType != "tool" || !strings.Contains(logs[0].Content, "go test") {
+		t.Errorf("expected a tool log line, got %+v", logs)
+	}
+
+	// Unknown events are ignored, malformed input is an error.
+	if err := handleCodexHook(database, id, "SomethingNew", []byte(`{}`)); err != nil {
+		t.Errorf("unknown event: %v", err)
+	}
+	if err := handleCodexHook(database, id, "Stop", []byte(`not json`)); err == nil {
+		t.Error("expected an error for malformed input")
+	}
+}
+
+func TestHandleAgentHookIgnoresUnknownExecutor(t *testing.T) {
+	t.Setenv("WORKTREE_TASK_ID", "1")
+	if err := handleAgentHook("nope", "Stop", strings.NewReader("{}")); err != nil {
+		t.Errorf("expected unknown executors to be ignored, got %v", err)
+	}
+}
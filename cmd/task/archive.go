@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/bborn/workflow/internal/config"
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/executor"
+	"github.com/spf13/cobra"
+)
+
+func newArchiveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "archive <task-id>",
+		Short:             "Archive a task, saving its worktree",
+		ValidArgsFunction: completeTaskIDs,
+		Long: `Archives a task: stops its agent, saves any uncommitted worktree changes to a
+git ref, and removes the worktree. Archived tasks are hidden from the board
+and 'ty list' (use 'ty list --all' to see them). 'ty unarchive' restores the
+task, its worktree, and the status it had.
+
+Examples:
+  ty archive 42
+  ty bulk archive 1 2 3`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid task ID: "+args[0]))
+				os.Exit(1)
+			}
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			task, err := database.GetTask(taskID)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if task == nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Task #%d not found", taskID)))
+				os.Exit(1)
+			}
+			if task.Status == db.StatusArchived {
+				fmt.Println(dimStyle.Render(fmt.Sprintf("Task #%d is already archived", taskID)))
+				return
+			}
+
+			if err := archiveTask(database, task); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			outln(successStyle.Render(fmt.Sprintf("Archived task #%d: %s", taskID, task.Title)))
+		},
+	}
+}
+
+func newUnarchiveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "unarchive <task-id>",
+		Short:             "Restore an archived task to its previous status",
+		ValidArgsFunction: completeTaskIDs,
+		Long: `Restores an archived task: recreates its worktree (with the changes saved when
+it was archived) and returns it to the status it had. A task archived while
+processing comes back blocked, since archiving stopped its agent; tasks whose
+previous status is unknown come back to the backlog.
+
+Examples:
+  ty unarchive 42`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid task ID: "+args[0]))
+				os.Exit(1)
+			}
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			task, err := database.GetTask(taskID)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if task == nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Task #%d not found", taskID)))
+				os.Exit(1)
+			}
+			if task.Status != db.StatusArchived {
+				fmt.Println(dimStyle.Render(fmt.Sprintf("Task #%d is not archived", taskID)))
+				return
+			}
+
+			exec := executor.New(database, config.New(database))
+			if task.HasArchiveState() {
+				if err := exec.UnarchiveWorktree(task); err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: unarchive worktree: "+err.Error()))
+					os.Exit(1)
+				}
+			}
+			status, err := database.UnarchiveTask(taskID)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if task, _ := database.GetTask(taskID); task != nil {
+				exec.NotifyTaskChange("status_changed", task)
+			}
+			outln(successStyle.Render(fmt.Sprintf("Unarchived task #%d to %s: %s", taskID, status, task.Title)))
+		},
+	}
+}
+
+// archiveTask archives a task, then stops its agent and saves and removes its
+// worktree. The status change is recorded first so a failed cleanup never
+// leaves a task that looks active.
+func archiveTask(database *db.DB, task *db.Task) error {
+	if err := database.ArchiveTask(task.ID); err != nil {
+		return err
+	}
+
+	exec := executor.New(database, config.New(database))
+	if archived, _ := database.GetTask(task.ID); archived != nil {
+		exec.NotifyTaskChange("status_changed", archived)
+	}
+
+	exec.KillClaudeProcess(task.ID)
+	killSessionAcrossDaemons(int(task.ID))
+	if task.WorktreePath != "" {
+		if err := exec.ArchiveWorktree(task); err != nil {
+			fmt.Fprintln(os.Stderr, dimStyle.Render(fmt.Sprintf("Warning: could not archive worktree: %v", err)))
+		}
+	}
+	return nil
+}
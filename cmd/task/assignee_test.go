@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func TestResolveAssignee(t *testing.T) {
+	t.Setenv("TASKYOU_USER", "alice")
+	tests := map[string]string{
+		"me":     "alice",
+		" bob ":  "bob",
+		"none":   "",
+		"":       "",
+		"nobody": "nobody",
+	}
+	for in, want := range tests {
+		if got := resolveAssignee(in); got != want {
+			t.Errorf("resolveAssignee(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
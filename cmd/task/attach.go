@@ -0,0 +1,284 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/bborn/workflow/internal/config"
+	"github.com/bborn/workflow/internal/db"
+	"github.com/spf13/cobra"
+)
+
+func newAttachCmd() *cobra.Command {
+	attachCmd := &cobra.Command{
+		Use:   "attach <task-id> <file>...",
+		Short: "Attach files to a task",
+		Long: `Copies files into the task. When the task runs, its attachments are written
+to .claude/attachments/task-<id>/ in the worktree and listed in the prompt
+({{attachments}} in task type instructions).
+
+Files larger than the max_attachment_size setting (default 10MB) are refused.
+Attaching a file whose content is already attached to the task is a no-op.
+
+Examples:
+  ty attach 42 screenshot.png
+  ty attach 42 spec.md notes.txt
+  ty attachments list 42
+  ty attach remove 42 screenshot.png`,
+		Args: cobra.MinimumNArgs(2),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completeTaskIDs(cmd, args, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveDefault
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid task ID: "+args[0]))
+				os.Exit(1)
+			}
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			task, err := database.GetTask(taskID)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if task == nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Task #%d not found", taskID)))
+				os.Exit(1)
+			}
+
+			maxBytes := maxAttachmentBytes(database)
+			failed := false
+			for _, path := range args[1:] {
+				a, duplicate, err := attachFile(database, taskID, path, maxBytes)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					failed = true
+					continue
+				}
+				if duplicate {
+					fmt.Println(dimStyle.Render(fmt.Sprintf("%s is already attached to task #%d as %s", path, taskID, a.Filename)))
+					continue
+				}
+				outln(successStyle.Render(fmt.Sprintf("Attached %s (%s) to task #%d", a.Filename, formatAttachmentSize(a.Size), taskID)))
+			}
+			if failed {
+				os.Exit(1)
+			}
+		},
+	}
+
+	removeCmd := &cobra.Command{
+		Use:   "remove <task-id> <filename|attachment-id>",
+		Short: "Remove an attachment from a task",
+		Long: `Removes an attachment, named by its filename or by the ID shown in
+'ty attachments list'.
+
+Examples:
+  ty attach remove 42 screenshot.png
+  ty attach remove 42 7`,
+		Aliases:           []string{"rm"},
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeTaskIDs,
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid task ID: "+args[0]))
+				os.Exit(1)
+			}
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			a, err := findTaskAttachment(database, taskID, args[1])
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if err := database.DeleteAttachment(a.ID); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			outln(successStyle.Render(fmt.Sprintf("Removed %s from task #%d", a.Filename, taskID)))
+		},
+	}
+	attachCmd.AddCommand(removeCmd)
+
+	return attachCmd
+}
+
+func newAttachmentsCmd() *cobra.Command {
+	attachmentsCmd := &cobra.Command{
+		Use:   "attachments",
+		Short: "List task attachments",
+		Long: `List the files attached to a task. Add files with 'ty attach'.
+
+Examples:
+  ty attachments list 42
+  ty attachments list 42 --json`,
+	}
+
+	listCmd := &cobra.Command{
+		Use:               "list <task-id>",
+		Short:             "List a task's attachments",
+		Aliases:           []string{"ls"},
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTaskIDs,
+		Run: func(cmd *cobra.Command, args []string) {
+			outputJSON, _ := cmd.Flags().GetBool("json")
+			taskID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid task ID: "+args[0]))
+				os.Exit(1)
+			}
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			attachments, err := database.ListAttachments(taskID)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			if outputJSON {
+				out := make([]map[string]interface{}, 0, len(attachments))
+				for _, a := range attachments {
+					out = append(out, map[string]interface{}{
+						"id":           a.ID,
+						"task_id":      a.TaskID,
+						"filename":     a.Filename,
+						"mime_type":    a.MimeType,
+						"size":         a.Size,
+						"content_hash": a.ContentHash,
+						"created_at":   a.CreatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+					})
+				}
+				data, _ := json.MarshalIndent(out, "", "  ")
+				fmt.Println(string(data))
+				return
+			}
+
+			if len(attachments) == 0 {
+				fmt.Println(dimStyle.Render(fmt.Sprintf("Task #%d has no attachments", taskID)))
+				return
+			}
+			for _, a := range attachments {
+				fmt.Printf("%s  %s  %s  %s\n",
+					dimStyle.Render(fmt.Sprintf("%4d", a.ID)),
+					boldStyle.Render(a.Filename),
+					formatAttachmentSize(a.Size),
+					dimStyle.Render(a.CreatedAt.Time.Format("2006-01-02 15:04")))
+			}
+		},
+	}
+	listCmd.Flags().Bool("json", false, "Output in JSON format")
+	attachmentsCmd.AddCommand(listCmd)
+
+	return attachmentsCmd
+}
+
+// maxAttachmentBytes returns the max_attachment_size setting in bytes.
+func maxAttachmentBytes(database *db.DB) int64 {
+	mb := config.DefaultMaxAttachmentSize
+	if val, err := database.GetSetting(config.SettingMaxAttachmentSize); err == nil && val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n > 0 {
+			mb = n
+		}
+	}
+	return int64(mb) << 20
+}
+
+// attachFile copies the file at path into the task's attachments. If the
+// task already has an attachment with the same content, that attachment is
+// returned with duplicate set and nothing is added.
+func attachFile(database *db.DB, taskID int64, path string, maxBytes int64) (a *db.Attachment, duplicate bool, err error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false, err
+	}
+	if info.IsDir() {
+		return nil, false, fmt.Errorf("%s is a directory", path)
+	}
+	if info.Size() > maxBytes {
+		return nil, false, fmt.Errorf("%s is %s; the limit is %s (see the max_attachment_size setting)",
+			path, formatAttachmentSize(info.Size()), formatAttachmentSize(maxBytes))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	existing, err := database.FindAttachmentByHash(taskID, db.AttachmentHash(data))
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return existing, true, nil
+	}
+
+	mimeType := mime.TypeByExtension(filepath.Ext(path))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	a, err = database.AddAttachment(taskID, filepath.Base(path), mimeType, data)
+	return a, false, err
+}
+
+// findTaskAttachment resolves a filename or attachment ID among a task's
+// attachments.
+func findTaskAttachment(database *db.DB, taskID int64, ref string) (*db.Attachment, error) {
+	attachments, err := database.ListAttachments(taskID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range attachments {
+		if a.Filename == ref {
+			return a, nil
+		}
+	}
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		for _, a := range attachments {
+			if a.ID == id {
+				return a, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("task #%d has no attachment %q", taskID, ref)
+}
+
+// formatAttachmentSize renders a byte count as "512 B", "1.5 KB", "10.0 MB".
+func formatAttachmentSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bborn/workflow/internal/config"
+)
+
+func TestAttachFile(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	ids := createTestTasks(t, database, 1)
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "spec.md")
+	if err := os.WriteFile(spec, []byte("# Spec\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a, duplicate, err := attachFile(database, ids[0], spec, 1<<20)
+	if err != nil {
+		t.Fatalf("attachFile: %v", err)
+	}
+	if duplicate || a.Filename != "spec.md" || a.Size != 7 || a.ContentHash == "" {
+		t.Errorf("unexpected attachment: %+v (duplicate=%v)", a, duplicate)
+	}
+
+	// Same content under another name is deduped.
+	copyPath := filepath.Join(dir, "copy.md")
+	if err := os.WriteFile(copyPath, []byte("# Spec\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	again, duplicate, err := attachFile(database, ids[0], copyPath, 1<<20)
+	if err != nil {
+		t.Fatalf("attachFile again: %v", err)
+	}
+	if !duplicate || again.ID != a.ID {
+		t.Errorf("expected duplicate of #%d, got %+v (duplicate=%v)", a.ID, again, duplicate)
+	}
+	if n, _ := database.CountAttachments(ids[0]); n != 1 {
+		t.Errorf("expected 1 attachment, got %d", n)
+	}
+
+	big := filepath.Join(dir, "big.bin")
+	if err := os.WriteFile(big, make([]byte, 2048), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := attachFile(database, ids[0], big, 1024); err == nil || !strings.Contains(err.Error(), "max_attachment_size") {
+		t.Errorf("expected a size limit error, got %v", err)
+	}
+	if _, _, err := attachFile(database, ids[0], dir, 1<<20); err == nil {
+		t.Errorf("expected an error attaching a directory")
+	}
+
+	found, err := findTaskAttachment(database, ids[0], "spec.md")
+	if err != nil || found.ID != a.ID {
+		t.Errorf("find by name: %+v, %v", found, err)
+	}
+	if _, err := findTaskAttachment(database, ids[0], "missing.md"); err == nil {
+		t.Errorf("expected an error for a missing attachment")
+	}
+}
+
+func TestMaxAttachmentBytes(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	if got := maxAttachmentBytes(database); got != config.DefaultMaxAttachmentSize<<20 {
+		t.Errorf("default: got %d", got)
+	}
+	database.SetSetting(config.SettingMaxAttachmentSize, "2")
+	if got := maxAttachmentBytes(database); got != 2<<20 {
+		t.Errorf("configured: got %d", got)
+	}
+}
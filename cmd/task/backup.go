@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/spf13/cobra"
+)
+
+func newBackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup [path]",
+		Short: "Back up the task database",
+		Long: `Writes a consistent snapshot of the task database to a single file. It is
+safe to run while the daemon is working: the snapshot is taken in one read
+transaction and includes writes still in the WAL.
+
+With no path the backup goes to a timestamped file (tasks-YYYYMMDD-HHMMSS.db)
+in the backups directory next to the database. A path that is an existing
+directory gets a timestamped file inside it; any other path is used as the
+file name and must not exist yet.
+
+--keep N then deletes all but the newest N timestamped backups in that
+directory. Other files there are never touched.
+
+Examples:
+  ty backup
+  ty backup --keep 7
+  ty backup ~/Dropbox/ty/
+  ty backup /tmp/before-upgrade.db`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			keep, _ := cmd.Flags().GetInt("keep")
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			dest := backupDest(args, time.Now())
+
+			// Pending migrations don't stop a backup: backing up is what to do
+			// before 'ty migrate up' applies them.
+			database, err := db.OpenWithPendingMigrations(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			err = database.Backup(dest)
+			database.Close()
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			var pruned []string
+			if keep > 0 {
+				if pruned, err = db.PruneBackups(filepath.Dir(dest), keep); err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Warning: "+err.Error()))
+				}
+			}
+
+			if outputJSON {
+				if pruned == nil {
+					pruned = []string{}
+				}
+				jsonBytes, _ := json.Marshal(map[string]interface{}{
+					"path":   dest,
+					"pruned": pruned,
+				})
+				fmt.Println(string(jsonBytes))
+				return
+			}
+			size := ""
+			if info, err := os.Stat(dest); err == nil {
+				size = fmt.Sprintf(" (%s)", formatAttachmentSize(info.Size()))
+			}
+			outln(successStyle.Render("Backed up to " + dest + size))
+			for _, p := range pruned {
+				fmt.Println(dimStyle.Render("Removed old backup " + p))
+			}
+		},
+	}
+	cmd.Flags().Int("keep", 0, "Keep only the newest N timestamped backups in the target directory (0 = keep all)")
+	cmd.Flags().Bool("json", false, "Output in JSON format")
+	return cmd
+}
+
+// backupDest resolves ty backup's optional path argument to the file to write.
+func backupDest(args []string, now time.Time) string {
+	if len(args) == 0 {
+		return filepath.Join(db.DefaultBackupDir(), db.BackupFileName(now))
+	}
+	path := args[0]
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return filepath.Join(path, db.BackupFileName(now))
+	}
+	return path
+}
+
+func newRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <file>",
+		Short: "Replace the task database with a backup",
+		Long: `Replaces the task database with a file written by 'ty backup'.
+
+The backup is checked first: it must pass SQLite's integrity check, be a ty
+database, and not come from a newer ty. Then the daemon is stopped, the
+current database is itself backed up to the backups directory (so a restore
+can be undone), and the backup is swapped in atomically. Migrations an older
+backup is missing are applied to the restored database. A daemon that was
+running is started again afterwards.
+
+Close the TUI and any other ty processes before restoring.
+
+Examples:
+  ty restore ~/.local/share/task/backups/tasks-20260101-090000.db
+  ty restore /tmp/before-upgrade.db --force`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			force, _ := cmd.Flags().GetBool("force")
+			src := args[0]
+			if abs, err := filepath.Abs(src); err == nil {
+				src = abs
+			}
+			dest := db.DefaultPath()
+
+			version, err := db.VerifyBackup(src)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			if !force {
+				fmt.Printf("Replace %s with %s (schema version %d)? [y/N] ", dest, src, version)
+				reader := bufio.NewReader(os.Stdin)
+				response, _ := reader.ReadString('\n')
+				response = strings.TrimSpace(strings.ToLower(response))
+				if response != "y" && response != "yes" {
+					fmt.Println("Cancelled")
+					return
+				}
+			}
+
+			pidFile := getPidFilePath()
+			wasRunning, wasDangerous := false, false
+			if pid, err := readPidFile(pidFile); err == nil && processExists(pid) {
+				wasRunning = true
+				if mode, err := os.ReadFile(pidFile + ".mode"); err == nil {
+					wasDangerous = strings.TrimSpace(string(mode)) == "dangerous"
+				}
+				fmt.Println(dimStyle.Render("Stopping daemon..."))
+				if err := stopDaemon(); err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+				if !waitForExit(pid, 10*time.Second) {
+					fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Error: daemon (pid %d) did not exit; nothing was restored", pid)))
+					os.Exit(1)
+				}
+			}
+
+			if _, err := os.Stat(dest); err == nil {
+				current, err := db.OpenWithPendingMigrations(dest)
+				if err == nil {
+					safety := filepath.Join(db.DefaultBackupDir(), db.BackupFileName(time.Now()))
+					err = current.Backup(safety)
+					current.Close()
+					if err == nil {
+						fmt.Println(dimStyle.Render("Saved the current database to " + safety))
+					}
+				}
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: could not back up the current database, nothing was restored: "+err.Error()))
+					os.Exit(1)
+				}
+			}
+
+			if err := db.RestoreBackup(src, dest); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			// Apply any migrations an older backup is missing, so a problem
+			// shows up now rather than in the daemon's log. The backup file
+			// itself is left as it was.
+			restored, err := db.OpenWithPendingMigrations(dest)
+			if err == nil {
+				_, err = restored.ApplyPendingMigrations()
+				restored.Close()
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: restored database does not open: "+err.Error()))
+				os.Exit(1)
+			}
+			outln(successStyle.Render("Restored " + dest + " from " + src))
+
+			if wasRunning {
+				if err := ensureDaemonRunning(wasDangerous); err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Warning: could not restart the daemon: "+err.Error()))
+					return
+				}
+				fmt.Println(dimStyle.Render("Daemon restarted"))
+			}
+		},
+	}
+	cmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
+	return cmd
+}
+
+// waitForExit polls until process pid is gone or timeout passes, and reports
+// whether it exited.
+func waitForExit(pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for processExists(pid) {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return true
+}
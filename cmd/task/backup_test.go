@@ -0,0 +1,26 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestBackupDest(t *testing.T) {
+	t.Setenv("WORKTREE_DB_PATH", filepath.Join(t.TempDir(), "tasks.db"))
+	now := time.Date(2026, 3, 4, 5, 6, 7, 0, time.Local)
+
+	if got, want := backupDest(nil, now), filepath.Join(db.DefaultBackupDir(), "tasks-20260304-050607.db"); got != want {
+		t.Errorf("default dest = %q, want %q", got, want)
+	}
+	dir := t.TempDir()
+	if got, want := backupDest([]string{dir}, now), filepath.Join(dir, "tasks-20260304-050607.db"); got != want {
+		t.Errorf("directory dest = %q, want %q", got, want)
+	}
+	file := filepath.Join(dir, "before-upgrade.db")
+	if got := backupDest([]string{file}, now); got != file {
+		t.Errorf("file dest = %q, want %q", got, file)
+	}
+}
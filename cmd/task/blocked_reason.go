@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/bborn/workflow/internal/db"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// blockedReasonLabels are the short labels shown next to a blocked task.
+var blockedReasonLabels = map[string]string{
+	db.BlockedNeedsInput:      "needs input",
+	db.BlockedNeedsPermission: "needs permission",
+	db.BlockedError:           "error",
+	db.BlockedDependency:      "waiting on deps",
+}
+
+// blockedReasonStyle colors a blocked reason so failures stand out from
+// tasks that are only waiting: errors red, input and permission amber,
+// dependencies dim.
+func blockedReasonStyle(reason string) lipgloss.Style {
+	switch reason {
+	case db.BlockedError:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#EF4444")).Bold(true)
+	case db.BlockedNeedsInput, db.BlockedNeedsPermission:
+		return warnStyle
+	default:
+		return dimStyle
+	}
+}
+
+// formatBlockedReason renders a blocked reason as "(needs input)", or "" when
+// the reason is unset. Only blocked tasks carry one.
+func formatBlockedReason(reason string) string {
+	label, ok := blockedReasonLabels[reason]
+	if !ok {
+		return ""
+	}
+	return blockedReasonStyle(reason).Render("(" + label + ")")
+}
+
+// completeFlagBlockedReasons provides completions for --blocked-reason.
+func completeFlagBlockedReasons(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return db.BlockedReasons(), cobra.ShellCompDirectiveNoFileComp
+}
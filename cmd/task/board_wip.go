@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/web"
+)
+
+// boardWIP returns the processing count of every project with a WIP limit,
+// or only of project when it is set. Counts cover all processing tasks,
+// whatever window the board itself is limited to.
+func boardWIP(database *db.DB, project string) ([]web.BoardWIP, error) {
+	projects, err := database.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	counts, err := database.CountTasksBy(db.ListTasksOptions{Status: db.StatusProcessing}, "project")
+	if err != nil {
+		return nil, err
+	}
+	processing := make(map[string]int, len(counts))
+	for _, c := range counts {
+		processing[c.Key] = c.Count
+	}
+
+	var wip []web.BoardWIP
+	for _, p := range projects {
+		if p.WIPLimit <= 0 || (project != "" && p.Name != project) {
+			continue
+		}
+		wip = append(wip, web.BoardWIP{Project: p.Name, Processing: processing[p.Name], Limit: p.WIPLimit})
+	}
+	return wip, nil
+}
+
+// boardColumnHeader renders a column's heading. On a single-project board the
+// In Progress count is shown against the project's limit ("In Progress (2/3)");
+// otherwise each limited project's usage follows the count.
+func boardColumnHeader(column web.BoardColumn, project string) string {
+	if len(column.WIP) == 0 {
+		return fmt.Sprintf("%s (%d)", column.Label, column.Count)
+	}
+	if project != "" && len(column.WIP) == 1 {
+		w := column.WIP[0]
+		return fmt.Sprintf("%s (%d/%d)", column.Label, w.Processing, w.Limit)
+	}
+	parts := make([]string, 0, len(column.WIP))
+	for _, w := range column.WIP {
+		parts = append(parts, fmt.Sprintf("%s %d/%d", w.Project, w.Processing, w.Limit))
+	}
+	return fmt.Sprintf("%s (%d) · WIP %s", column.Label, column.Count, strings.Join(parts, ", "))
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/web"
+)
+
+func TestBoardWIPHeaders(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	if err := database.CreateProject(&db.Project{Name: "app", Path: t.TempDir(), WIPLimit: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.CreateProject(&db.Project{Name: "free", Path: t.TempDir()}); err != nil {
+		t.Fatal(err)
+	}
+	for _, project := range []string{"app", "app", "free"} {
+		task := &db.Task{Title: "t", Status: db.StatusProcessing, Project: project}
+		if err := database.CreateTask(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wip, err := boardWIP(database, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wip) != 1 || wip[0] != (web.BoardWIP{Project: "app", Processing: 2, Limit: 3}) {
+		t.Fatalf("boardWIP = %+v", wip)
+	}
+	if wip, _ := boardWIP(database, "free"); len(wip) != 0 {
+		t.Errorf("unlimited project has WIP entries: %+v", wip)
+	}
+
+	column := web.BoardColumn{Status: db.StatusProcessing, Label: "In Progress", Count: 3, WIP: wip}
+	if got := boardColumnHeader(column, ""); got != "In Progress (3) · WIP app 2/3" {
+		t.Errorf("all-projects header = %q", got)
+	}
+	column.Count = 2
+	if got := boardColumnHeader(column, "app"); got != "In Progress (2/3)" {
+		t.Errorf("single-project header = %q", got)
+	}
+	if got := boardColumnHeader(web.BoardColumn{Label: "Done", Count: 4}, ""); got != "Done (4)" {
+		t.Errorf("plain header = %q", got)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/events"
+)
+
+// digestGroups are the transitions `ty board --digest` reports, in display
+// order, keyed by the event_log type that records them.
+var digestGroups = []struct {
+	Key       string
+	Label     string
+	EventType string
+}{
+	{"created", "Created", events.TaskCreated},
+	{"started", "Started", events.TaskStarted},
+	{"blocked", "Blocked", events.TaskBlocked},
+	{"completed", "Completed", events.TaskCompleted},
+}
+
+// digestEntry is a task that underwent a transition inside the window.
+type digestEntry struct {
+	ID      int64     `json:"id"`
+	Title   string    `json:"title"`
+	Project string    `json:"project,omitempty"`
+	Status  string    `json:"status"`
+	At      time.Time `json:"at"`
+	// Count is how often the transition happened in the window (a task can be
+	// blocked and unblocked several times); At is the latest occurrence.
+	Count int `json:"count"`
+}
+
+// boardDigest groups recent activity by transition for standups and status
+// reports, in contrast to the board's current-column view.
+type boardDigest struct {
+	Since  time.Time                 `json:"since"`
+	Groups map[string][]*digestEntry `json:"groups"`
+}
+
+// buildBoardDigest reads the event_log from since onward. Tasks deleted since
+// are left out; everything else is shown with its current status.
+func buildBoardDigest(database *db.DB, since time.Time) (*boardDigest, error) {
+	groupFor := make(map[string]string, len(digestGroups))
+	eventTypes := make([]string, 0, len(digestGroups))
+	for _, g := range digestGroups {
+		groupFor[g.EventType] = g.Key
+		eventTypes = append(eventTypes, g.EventType)
+	}
+
+	transitions, err := database.ListTaskTransitions(since, eventTypes...)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := &boardDigest{Since: since, Groups: make(map[string][]*digestEntry)}
+	for _, g := range digestGroups {
+		digest.Groups[g.Key] = []*digestEntry{}
+	}
+
+	tasks := make(map[int64]*db.Task)
+	entries := make(map[string]*digestEntry)
+	for _, tr := range transitions {
+		task, ok := tasks[tr.TaskID]
+		if !ok {
+			if task, err = database.GetTask(tr.TaskID); err != nil {
+				return nil, err
+			}
+			tasks[tr.TaskID] = task
+		}
+		if task == nil {
+			continue
+		}
+		key := groupFor[tr.EventType]
+		entryKey := fmt.Sprintf("%s/%d", key, tr.TaskID)
+		if e := entries[entryKey]; e != nil {
+			e.Count++
+			e.At = tr.CreatedAt.Time
+			continue
+		}
+		e := &digestEntry{
+			ID:      task.ID,
+			Title:   task.Title,
+			Project: task.Project,
+			Status:  task.Status,
+			At:      tr.CreatedAt.Time,
+			Count:   1,
+		}
+		entries[entryKey] = e
+		digest.Groups[key] = append(digest.Groups[key], e)
+	}
+
+	for _, group := range digest.Groups {
+		sort.SliceStable(group, func(i, j int) bool { return group[i].At.After(group[j].At) })
+	}
+	return digest, nil
+}
+
+// printBoardDigest renders the digest as text. limit caps each group (0 = all).
+func printBoardDigest(digest *boardDigest, window string, limit int) {
+	if _, err := parseRelativeDuration(window); err == nil {
+		fmt.Println(boldStyle.Render(fmt.Sprintf("Activity in the last %s", window)) +
+			dimStyle.Render(" (since "+digest.Since.Format("2006-01-02 15:04")+")"))
+	} else {
+		fmt.Println(boldStyle.Render("Activity since " + digest.Since.Format("2006-01-02 15:04")))
+	}
+	fmt.Println(strings.Repeat("─", 50))
+	for _, g := range digestGroups {
+		entries := digest.Groups[g.Key]
+		fmt.Printf("%s (%d)\n", g.Label, len(entries))
+		if len(entries) == 0 {
+			fmt.Println("  (none)")
+			fmt.Println()
+			continue
+		}
+		shown := entries
+		if limit > 0 && len(shown) > limit {
+			shown = shown[:limit]
+		}
+		for _, e := range shown {
+			line := fmt.Sprintf("- #%d %s", e.ID, e.Title)
+			if e.Project != "" {
+				line += fmt.Sprintf(" [%s]", e.Project)
+			}
+			if e.Count > 1 {
+				line += fmt.Sprintf(" ×%d", e.Count)
+			}
+			line += dimStyle.Render(fmt.Sprintf(" • %s, now %s", e.At.Format("Jan 2 15:04"), e.Status))
+			fmt.Println("  " + line)
+		}
+		if len(entries) > len(shown) {
+			fmt.Printf("  … +%d more\n", len(entries)-len(shown))
+		}
+		fmt.Println()
+	}
+}
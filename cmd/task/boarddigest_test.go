@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestBuildBoardDigest(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	since := time.Now().Add(-time.Minute)
+	ids := createTestTasks(t, database, 3)
+
+	// ids[0]: started, then blocked twice (via a retry), then done.
+	for _, status := range []string{db.StatusProcessing, db.StatusBlocked, db.StatusProcessing, db.StatusBlocked, db.StatusDone} {
+		if err := database.UpdateTaskStatus(ids[0], status); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// ids[1]: started only.
+	if err := database.UpdateTaskStatus(ids[1], db.StatusProcessing); err != nil {
+		t.Fatal(err)
+	}
+	// ids[2]: deleted, so it drops out of the digest.
+	if err := database.DeleteTask(ids[2]); err != nil {
+		t.Fatal(err)
+	}
+
+	digest, err := buildBoardDigest(database, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(digest.Groups["created"]); got != 2 {
+		t.Errorf("created = %d, want 2", got)
+	}
+	if got := len(digest.Groups["started"]); got != 2 {
+		t.Errorf("started = %d, want 2", got)
+	}
+	blocked := digest.Groups["blocked"]
+	if len(blocked) != 1 || blocked[0].ID != ids[0] || blocked[0].Count != 2 {
+		t.Errorf("blocked = %+v, want task %d twice", blocked, ids[0])
+	}
+	completed := digest.Groups["completed"]
+	if len(completed) != 1 || completed[0].ID != ids[0] || completed[0].Status != db.StatusDone {
+		t.Errorf("completed = %+v, want task %d", completed, ids[0])
+	}
+
+	// Nothing happened after now.
+	empty, err := buildBoardDigest(database, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, g := range digestGroups {
+		if len(empty.Groups[g.Key]) != 0 {
+			t.Errorf("expected empty %s group, got %d", g.Key, len(empty.Groups[g.Key]))
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -53,19 +54,29 @@ Examples:
 	}
 
 	// bulk status <status> <task-id> [task-id...]
+	// bulk status <status> --project/--type/--status/--older-than
 	bulkStatusCmd := &cobra.Command{
-		Use:               "status <status> <task-id> [task-id...]",
+		Use:               "status <status> [task-id...]",
 		Short:             "Set status on multiple tasks",
 		ValidArgsFunction: completeStatusThenMultipleTaskIDs,
-		Long: `Change the status of multiple tasks at once.
+		Long: `Change the status of multiple tasks at once, either listed by ID or
+selected with filters.
 
 Valid statuses: backlog, queued, processing, blocked, done, archived.
 
+Filters (--project, --type, --status, --older-than) select every matching
+task and move them all in a single transaction: if any update fails, none are
+applied. Without --status, only open tasks (not done or archived) match.
+--older-than matches tasks last updated longer ago than the given duration.
+Archiving tasks selected by filter requires --yes.
+
 Examples:
   ty bulk status done 10 11 12
   ty bulk status backlog 5 6
-  ty bulk status archived 1 2 3`,
-		Args: cobra.MinimumNArgs(2),
+  ty bulk status archived 1 2 3
+  ty bulk status queued --project myapp --status backlog
+  ty bulk status archived --status done --older-than 168h --yes`,
+		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			status := strings.ToLower(strings.TrimSpace(args[0]))
 			if !isValidStatus(status) {
@@ -73,6 +84,26 @@ Examples:
 				os.Exit(1)
 			}
 
+			filter, hasFilter, err := bulkStatusFilterFromFlags(cmd)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if hasFilter {
+				if len(args) > 1 {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: pass task IDs or filters, not both"))
+					os.Exit(1)
+				}
+				yes, _ := cmd.Flags().GetBool("yes")
+				dryRun, _ := cmd.Flags().GetBool("dry-run")
+				runBulkStatusByFilter(status, filter, yes, dryRun)
+				return
+			}
+			if len(args) < 2 {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: pass task IDs or at least one of --project, --type, --status, --older-than"))
+				os.Exit(1)
+			}
+
 			ids, err := parseTaskIDs(args[1:])
 			if err != nil {
 				fmt.Fprintln(os.Stderr, errorStyle.Render(err.Error()))
@@ -99,13 +130,23 @@ Examples:
 					failed++
 					continue
 				}
-				fmt.Println(successStyle.Render(fmt.Sprintf("Task #%d moved to %s", id, status)))
+				outln(successStyle.Render(fmt.Sprintf("Task #%d moved to %s", id, status)))
 				succeeded++
 			}
 
 			printBulkSummary("status change", succeeded, failed)
 		},
 	}
+	bulkStatusCmd.Flags().String("project", "", "Select tasks in this project")
+	bulkStatusCmd.Flags().String("type", "", "Select tasks of this type")
+	bulkStatusCmd.Flags().String("status", "", "Select tasks with this current status")
+	bulkStatusCmd.Flags().Duration("older-than", 0, "Select tasks last updated longer ago than this (e.g. 168h)")
+	bulkStatusCmd.Flags().BoolP("yes", "y", false, "Confirm archiving tasks selected by filter")
+	bulkStatusCmd.Flags().Bool("dry-run", false, "Show which tasks would change without changing them")
+	bulkStatusCmd.RegisterFlagCompletionFunc("project", completeProjectNames)
+	bulkStatusCmd.RegisterFlagCompletionFunc("status", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return validStatuses(), cobra.ShellCompDirectiveNoFileComp
+	})
 	bulkCmd.AddCommand(bulkStatusCmd)
 
 	// bulk delete <task-id> [task-id...]
@@ -164,7 +205,7 @@ Examples:
 					failed++
 					continue
 				}
-				fmt.Println(successStyle.Render(fmt.Sprintf("Trashed task #%d", id)))
+				outln(successStyle.Render(fmt.Sprintf("Trashed task #%d", id)))
 				succeeded++
 			}
 
@@ -217,7 +258,7 @@ Examples:
 					failed++
 					continue
 				}
-				fmt.Println(successStyle.Render(fmt.Sprintf("Closed task #%d: %s", id, task.Title)))
+				outln(successStyle.Render(fmt.Sprintf("Closed task #%d: %s", id, task.Title)))
 				succeeded++
 			}
 
@@ -289,7 +330,7 @@ Examples:
 				if executeDangerous {
 					msg += " (dangerous mode)"
 				}
-				fmt.Println(successStyle.Render(msg))
+				outln(successStyle.Render(msg))
 				succeeded++
 			}
 
@@ -338,12 +379,12 @@ Examples:
 					fmt.Println(dimStyle.Render(fmt.Sprintf("Task #%d is already archived, skipping", id)))
 					continue
 				}
-				if err := database.UpdateTaskStatus(id, db.StatusArchived); err != nil {
+				if err := database.ArchiveTask(id); err != nil {
 					fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Error archiving task #%d: %v", id, err)))
 					failed++
 					continue
 				}
-				fmt.Println(successStyle.Render(fmt.Sprintf("Archived task #%d: %s", id, task.Title)))
+				outln(successStyle.Render(fmt.Sprintf("Archived task #%d: %s", id, task.Title)))
 				succeeded++
 			}
 
@@ -355,13 +396,108 @@ Examples:
 	return bulkCmd
 }
 
+// bulkStatusFilter selects the tasks for a filtered bulk status change.
+type bulkStatusFilter struct {
+	project   string
+	taskType  string
+	status    string
+	olderThan time.Duration
+}
+
+// bulkStatusFilterFromFlags reads the selector flags; ok reports whether any
+// selector was given.
+func bulkStatusFilterFromFlags(cmd *cobra.Command) (f bulkStatusFilter, ok bool, err error) {
+	f.project, _ = cmd.Flags().GetString("project")
+	f.taskType, _ = cmd.Flags().GetString("type")
+	f.status, _ = cmd.Flags().GetString("status")
+	f.olderThan, _ = cmd.Flags().GetDuration("older-than")
+	f.status = strings.ToLower(strings.TrimSpace(f.status))
+	if f.status != "" && !isValidStatus(f.status) {
+		return f, false, fmt.Errorf("invalid --status %q, must be one of: %s", f.status, strings.Join(validStatuses(), ", "))
+	}
+	if f.olderThan < 0 {
+		return f, false, fmt.Errorf("--older-than must not be negative")
+	}
+	ok = f.project != "" || f.taskType != "" || f.status != "" || f.olderThan > 0
+	return f, ok, nil
+}
+
+// selectBulkStatusTasks returns the tasks matching f that are not already in
+// the target status.
+func selectBulkStatusTasks(database *db.DB, f bulkStatusFilter, target string, now time.Time) ([]*db.Task, error) {
+	opts := db.ListTasksOptions{
+		Project: f.project,
+		Type:    f.taskType,
+		Status:  f.status,
+		Limit:   100000,
+	}
+	if f.olderThan > 0 {
+		opts.UpdatedBefore = now.Add(-f.olderThan)
+	}
+	tasks, err := database.ListTasks(opts)
+	if err != nil {
+		return nil, err
+	}
+	var selected []*db.Task
+	for _, t := range tasks {
+		if t.Status != target {
+			selected = append(selected, t)
+		}
+	}
+	return selected, nil
+}
+
+// runBulkStatusByFilter moves every task matching f to status in a single
+// transaction.
+func runBulkStatusByFilter(status string, f bulkStatusFilter, yes, dryRun bool) {
+	database, err := db.Open(db.DefaultPath())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+		os.Exit(1)
+	}
+	defer database.Close()
+
+	tasks, err := selectBulkStatusTasks(database, f, status, time.Now())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+		os.Exit(1)
+	}
+	if len(tasks) == 0 {
+		fmt.Println(dimStyle.Render("No matching tasks"))
+		return
+	}
+
+	ids := make([]int64, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
+		fmt.Printf("  #%d [%s] %s\n", t.ID, t.Status, t.Title)
+	}
+	if dryRun {
+		fmt.Println(dimStyle.Render(fmt.Sprintf("\nWould move %d task(s) to %s", len(tasks), status)))
+		return
+	}
+	if status == db.StatusArchived && !yes {
+		fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("\nRefusing to archive %d task(s) without --yes", len(tasks))))
+		os.Exit(1)
+	}
+
+	if err := database.UpdateTasksStatus(ids, status); err != nil {
+		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()+" (no tasks were changed)"))
+		os.Exit(1)
+	}
+	outln(successStyle.Render(fmt.Sprintf("\nMoved %d task(s) to %s", len(tasks), status)))
+	if status == db.StatusQueued {
+		ensureDaemonForQueuedWork()
+	}
+}
+
 // printBulkSummary prints a summary line for bulk operations.
 func printBulkSummary(operation string, succeeded, failed int) {
 	if succeeded+failed == 0 {
 		return
 	}
 	if failed == 0 {
-		fmt.Println(successStyle.Render(fmt.Sprintf("\nBulk %s complete: %d succeeded", operation, succeeded)))
+		outln(successStyle.Render(fmt.Sprintf("\nBulk %s complete: %d succeeded", operation, succeeded)))
 	} else {
 		fmt.Println(dimStyle.Render(fmt.Sprintf("\nBulk %s complete: %d succeeded, %d failed", operation, succeeded, failed)))
 	}
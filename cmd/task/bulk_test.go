@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/bborn/workflow/internal/db"
 )
@@ -257,3 +258,45 @@ func TestBulkHandlesMissingTasks(t *testing.T) {
 		t.Error("expected nil for non-existent task")
 	}
 }
+
+func TestSelectBulkStatusTasks(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	ids := createTestTasks(t, database, 3)
+	if err := database.UpdateTaskStatus(ids[1], db.StatusDone); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.UpdateTaskStatus(ids[2], db.StatusDone); err != nil {
+		t.Fatal(err)
+	}
+	// Only the second task has been done for more than a week.
+	if _, err := database.Exec("UPDATE tasks SET updated_at = datetime('now', '-10 days') WHERE id = ?", ids[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := selectBulkStatusTasks(database, bulkStatusFilter{status: db.StatusDone, olderThan: 7 * 24 * time.Hour}, db.StatusArchived, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ID != ids[1] {
+		t.Errorf("expected only task #%d, got %v", ids[1], got)
+	}
+
+	// Without --status only open tasks match, and tasks already in the target
+	// status are left out.
+	got, err = selectBulkStatusTasks(database, bulkStatusFilter{taskType: db.TypeCode}, db.StatusQueued, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ID != ids[0] {
+		t.Errorf("expected only open task #%d, got %v", ids[0], got)
+	}
+	got, err = selectBulkStatusTasks(database, bulkStatusFilter{taskType: db.TypeCode}, db.StatusBacklog, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected no tasks to change, got %v", got)
+	}
+}
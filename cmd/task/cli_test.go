@@ -1483,6 +1483,35 @@ func TestUnescapeNewlines(t *testing.T) {
 	}
 }
 
+// TestReadBodyFile checks that a body loaded with --body-file is used
+// verbatim: literal backslash-n sequences are not turned into newlines the way
+// --body input is.
+func TestReadBodyFile(t *testing.T) {
+	content := "Run `printf 'a\\nb'` and check\nthe output.\n\nSecond paragraph.\n"
+	path := filepath.Join(t.TempDir(), "body.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readBodyFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Run `printf 'a\\nb'` and check\nthe output.\n\nSecond paragraph."
+	if got != want {
+		t.Errorf("readBodyFile = %q, want %q", got, want)
+	}
+
+	got, err = readBodyFile("-", strings.NewReader("from stdin\\n\n"))
+	if err != nil || got != "from stdin\\n" {
+		t.Errorf("readBodyFile(-) = %q, %v", got, err)
+	}
+
+	if _, err := readBodyFile(filepath.Join(t.TempDir(), "missing.md"), nil); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
 // TestTailModelUpdate tests the tailModel Update method
 func TestTailModelUpdate(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -1833,3 +1862,57 @@ func TestShouldSubmitInput(t *testing.T) {
 		})
 	}
 }
+
+// TestClaudeHookSessionBoundaries tests that SessionStart/SessionEnd log
+// boundary markers and that SessionEnd records the transcript's usage.
+func TestClaudeHookSessionBoundaries(t *testing.T) {
+	tmpDir := t.TempDir()
+	database, err := db.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	task := &db.Task{Title: "Session task", Status: db.StatusProcessing, Type: db.TypeCode, Model: db.ModelOpus}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+
+	handleSessionStartHook(database, task.ID, &ClaudeHookInput{Source: "startup"})
+
+	transcript := filepath.Join(tmpDir, "transcript.jsonl")
+	line := `{"type":"assistant","timestamp":"2026-01-02T10:00:00Z","message":{"id":"msg_1","model":"claude-sonnet-4-5","usage":{"input_tokens":1000,"output_tokens":200}}}`
+	if err := os.WriteFile(transcript, []byte(line+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	handleSessionEndHook(database, task.ID, &ClaudeHookInput{TranscriptPath: transcript, Reason: "prompt_input_exit"})
+
+	fetched, err := database.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if fetched.InputTokens != 1000 || fetched.OutputTokens != 200 || fetched.CostUSD == 0 {
+		t.Errorf("usage = %d in / %d out ($%f), want 1000 / 200 with a cost", fetched.InputTokens, fetched.OutputTokens, fetched.CostUSD)
+	}
+
+	// A missing transcript still logs the end marker.
+	handleSessionEndHook(database, task.ID, &ClaudeHookInput{TranscriptPath: filepath.Join(tmpDir, "gone.jsonl")})
+
+	logs, err := database.GetTaskLogs(task.ID, 10)
+	if err != nil {
+		t.Fatalf("GetTaskLogs() error = %v", err)
+	}
+	var contents []string
+	for _, l := range logs {
+		contents = append(contents, l.Content)
+	}
+	all := strings.Join(contents, "\n")
+	for _, want := range []string{"Session started (model: opus, startup)", "Session ended (prompt_input_exit) · 1.0k in / 200 out"} {
+		if !strings.Contains(all, want) {
+			t.Errorf("logs missing %q, got:\n%s", want, all)
+		}
+	}
+	if n := strings.Count(all, "Session ended"); n != 2 {
+		t.Errorf("got %d session end markers, want 2", n)
+	}
+}
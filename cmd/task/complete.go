@@ -95,17 +95,17 @@ func newCompleteCmd() *cobra.Command {
 				fmt.Fprintf(os.Stderr, "\n%s\n", dimStyle.Render("Fix the problem, then run ty complete again."))
 				return fmt.Errorf("completion rejected by verify command")
 			case completion.KindGateParked:
-				fmt.Println(successStyle.Render(fmt.Sprintf("Task #%d output saved.", taskID)))
+				outln(successStyle.Render(fmt.Sprintf("Task #%d output saved.", taskID)))
 				fmt.Println("This is a human-review gate — it is now 'blocked' awaiting approval.")
 				fmt.Println(dimStyle.Render(fmt.Sprintf("Approve with: ty close %d   (releases the next phase)", taskID)))
 			case completion.KindPRReview:
-				fmt.Println(successStyle.Render(fmt.Sprintf("Task #%d finished — PR #%d is up for review.", taskID, outcome.PRNumber)))
+				outln(successStyle.Render(fmt.Sprintf("Task #%d finished — PR #%d is up for review.", taskID, outcome.PRNumber)))
 				if outcome.PRURL != "" {
 					fmt.Println(dimStyle.Render("  " + outcome.PRURL))
 				}
 				fmt.Println("It is now 'blocked' awaiting a human merge, and moves to 'done' automatically once the PR merges or closes.")
 			default:
-				fmt.Println(successStyle.Render(fmt.Sprintf("Task #%d marked done.", taskID)))
+				outln(successStyle.Render(fmt.Sprintf("Task #%d marked done.", taskID)))
 			}
 			return nil
 		},
@@ -117,6 +117,14 @@ func completeSettingKeys(cmd *cobra.Command, args []string, toComplete string) (
 			"idle_suspend_timeout\tIdle timeout before suspending (e.g. 6h)",
 			"http_api_port\tPort for the daemon-hosted HTTP API (default 8080)",
 			"http_api_disabled\tDisable the daemon-hosted HTTP API (true/false)",
+			"max_concurrent_tasks\tHow many tasks the daemon runs at once (default 3)",
+			"max_retries\tTimes to auto-retry a failed task (default 0 = never)",
+			"max_attachment_size\tLargest file ty attach accepts, in MB (default 10)",
+			"pr_cache_ttl\tHow long cached PR status is reused (default 60s)",
+			"notifications_enabled\tDesktop notifications from the daemon (true/false)",
+			"notify_on\tEvents to notify on (e.g. task.blocked,task.completed)",
+			"secret_storage\tWhere API keys are stored (plaintext, keychain, passphrase)",
+			"default_model\tClaude model for tasks that don't pick one (e.g. sonnet)",
 		}, cobra.ShellCompDirectiveNoFileComp
 	}
 	return nil, cobra.ShellCompDirectiveNoFileComp
@@ -147,6 +155,11 @@ func completeFlagExecutors(cmd *cobra.Command, args []string, toComplete string)
 	}, cobra.ShellCompDirectiveNoFileComp
 }
 
+// completeFlagModels provides completions for --model flag values.
+func completeFlagModels(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return db.ModelOptions(), cobra.ShellCompDirectiveNoFileComp
+}
+
 // completeFlagTypes provides completions for --type flag values.
 func completeFlagTypes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	comps, directive := fetchTypeCompletions()
@@ -162,6 +175,11 @@ func completeFlagStatuses(cmd *cobra.Command, args []string, toComplete string)
 	return validStatuses(), cobra.ShellCompDirectiveNoFileComp
 }
 
+// completeListFormats provides completions for ty list's --format flag.
+func completeListFormats(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return []string{listFormatTable, listFormatCSV, listFormatTSV, listFormatGoTemplate, "oneline", "wide"}, cobra.ShellCompDirectiveNoFileComp
+}
+
 // fetchTaskCompletions opens the DB and returns task ID completions.
 func fetchTaskCompletions(toComplete string) ([]string, cobra.ShellCompDirective) {
 	database, err := db.Open(db.DefaultPath())
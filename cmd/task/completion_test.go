@@ -2,6 +2,7 @@ package main
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -84,8 +85,13 @@ func TestCompleteSettingKeys(t *testing.T) {
 	if directive != cobra.ShellCompDirectiveNoFileComp {
 		t.Errorf("expected NoFileComp directive")
 	}
-	if len(completions) != 5 {
-		t.Errorf("expected 5 setting keys, got %d", len(completions))
+	if len(completions) != len(settingKeys) {
+		t.Errorf("expected %d setting keys, got %d", len(settingKeys), len(completions))
+	}
+	for i, key := range settingKeys {
+		if i < len(completions) && !strings.HasPrefix(completions[i], key+"\t") {
+			t.Errorf("completion %d = %q, want %s", i, completions[i], key)
+		}
 	}
 
 	// After first arg, no more completions
@@ -0,0 +1,429 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bborn/workflow/internal/config"
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/notify"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// settingKeys lists the settings `ty settings set` and `ty config edit`
+// accept, in display order.
+var settingKeys = []string{
+	"anthropic_api_key",
+	"autocomplete_enabled",
+	"idle_suspend_timeout",
+	config.SettingHTTPAPIPort,
+	config.SettingHTTPAPIDisabled,
+	config.SettingMaxConcurrentTasks,
+	config.SettingMaxRetries,
+	config.SettingMaxAttachmentSize,
+	config.SettingPRCacheTTL,
+	config.SettingNotificationsEnabled,
+	config.SettingNotifyOn,
+	db.SettingSecretStorage,
+	db.SettingDefaultModel,
+}
+
+// errUnknownSetting is returned by validateSetting for keys not in
+// settingKeys.
+var errUnknownSetting = errors.New("unknown setting")
+
+// validateSetting checks value for the setting key and returns it in the
+// form to store.
+func validateSetting(key, value string) (string, error) {
+	switch key {
+	case "anthropic_api_key":
+		if !strings.HasPrefix(value, "sk-ant-") {
+			return "", errors.New("invalid API key format. Should start with 'sk-ant-'")
+		}
+	case "autocomplete_enabled", config.SettingHTTPAPIDisabled, config.SettingNotificationsEnabled:
+		if value != "true" && value != "false" {
+			return "", errors.New("value must be 'true' or 'false'")
+		}
+	case "idle_suspend_timeout":
+		if _, err := time.ParseDuration(value); err != nil {
+			return "", errors.New("invalid duration format. Examples: 6h, 30m, 24h, 1h30m")
+		}
+	case config.SettingHTTPAPIPort:
+		if p, err := strconv.Atoi(value); err != nil || p < 1 || p > 65535 {
+			return "", errors.New("value must be a port number between 1 and 65535")
+		}
+	case config.SettingNotifyOn:
+		types, err := notify.ParseEventList(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid notify_on: %w", err)
+		}
+		value = strings.Join(types, ",")
+	case config.SettingMaxConcurrentTasks:
+		if n, err := strconv.Atoi(value); err != nil || n < 1 {
+			return "", errors.New("value must be a positive integer")
+		}
+	case config.SettingMaxRetries:
+		if n, err := strconv.Atoi(value); err != nil || n < 0 {
+			return "", errors.New("value must be a non-negative integer")
+		}
+	case config.SettingPRCacheTTL:
+		if d, err := time.ParseDuration(value); err != nil || d < 0 {
+			return "", errors.New("invalid duration format. Examples: 60s, 5m, 0s (always fetch)")
+		}
+	case config.SettingMaxAttachmentSize:
+		if n, err := strconv.Atoi(value); err != nil || n < 1 {
+			return "", errors.New("value must be a positive number of megabytes")
+		}
+	case db.SettingDefaultModel:
+		if !db.IsValidModel(value) {
+			return "", errors.New(invalidModelMessage)
+		}
+	case db.SettingSecretStorage:
+		if !slices.Contains(db.SecretStorageModes(), value) {
+			return "", errors.New("value must be one of: " + strings.Join(db.SecretStorageModes(), ", "))
+		}
+	default:
+		return "", errUnknownSetting
+	}
+	return value, nil
+}
+
+// saveSetting stores a validated setting, routing secrets through the
+// configured secret storage and migrating stored secrets when
+// secret_storage itself changes.
+func saveSetting(database *db.DB, key, value string) error {
+	if db.IsSecretSetting(key) {
+		storage, err := database.SetSecretSetting(key, value)
+		if err != nil {
+			return err
+		}
+		outln(successStyle.Render("Setting saved: "+key) + dimStyle.Render(" ("+storage+")"))
+		warnSecretFallback(database, storage)
+		return nil
+	}
+
+	if err := database.SetSetting(key, value); err != nil {
+		return err
+	}
+	outln(successStyle.Render("Setting saved: " + key))
+
+	if key == db.SettingSecretStorage {
+		migrated, storage, err := database.MigrateSecretSettings()
+		if err != nil {
+			return fmt.Errorf("migrate stored secrets: %w", err)
+		}
+		if migrated > 0 {
+			outln(successStyle.Render(fmt.Sprintf("Moved %d stored secret(s) to %s storage", migrated, storage)))
+		}
+		warnSecretFallback(database, storage)
+	}
+	return nil
+}
+
+// configDoc is the file `ty config edit` opens in the editor.
+type configDoc struct {
+	Settings map[string]string        `yaml:"settings"`
+	Projects map[string]configProject `yaml:"projects,omitempty"`
+}
+
+type configProject struct {
+	Instructions string `yaml:"instructions"`
+}
+
+const configEditHeader = `# ty configuration. Save and close the editor to apply your changes.
+# An empty setting uses the default. anthropic_api_key is never shown:
+# leave it empty to keep the current key, or paste a new one.
+`
+
+func newConfigCmd() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Edit settings and project instructions",
+	}
+
+	editCmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Edit settings and project instructions in $EDITOR",
+		Long: `Opens every setting and each project's instructions as one YAML file in
+$EDITOR. Changes are written back when you save and close the editor.
+
+Values are validated the same way as 'ty settings set'. If anything is
+invalid nothing is saved; the error is printed and the edited file is kept
+so you can fix it and retry with --file.
+
+Examples:
+  ty config edit
+  EDITOR="code --wait" ty config edit
+  ty config edit --file /tmp/ty-config-123.yaml   # Retry a rejected edit`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			file, _ := cmd.Flags().GetString("file")
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			current, err := loadConfigDoc(database)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			path := file
+			if path == "" {
+				data, err := yaml.Marshal(current)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+				f, err := os.CreateTemp("", "ty-config-*.yaml")
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+				path = f.Name()
+				f.WriteString(configEditHeader)
+				f.Write(data)
+				f.Close()
+			}
+
+			if err := runEditor(path); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Editor failed: "+err.Error()))
+				os.Exit(1)
+			}
+
+			edited, err := os.ReadFile(path)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			changes, err := diffConfigDoc(database, current, edited)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				fmt.Fprintln(os.Stderr, dimStyle.Render("Nothing was saved. Your edits are in "+path))
+				fmt.Fprintln(os.Stderr, dimStyle.Render("Fix them with: ty config edit --file "+path))
+				os.Exit(1)
+			}
+			if err := applyConfigChanges(database, changes); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			os.Remove(path)
+			if changes.empty() {
+				fmt.Println(dimStyle.Render("No changes"))
+			}
+		},
+	}
+	editCmd.Flags().String("file", "", "Edit this previously rejected file instead of the current configuration")
+	configCmd.AddCommand(editCmd)
+
+	return configCmd
+}
+
+// loadConfigDoc builds the editable view of the current configuration.
+func loadConfigDoc(database *db.DB) (*configDoc, error) {
+	doc := &configDoc{Settings: map[string]string{}, Projects: map[string]configProject{}}
+	for _, key := range settingKeys {
+		if db.IsSecretSetting(key) {
+			doc.Settings[key] = ""
+			continue
+		}
+		val, _ := database.GetSetting(key)
+		doc.Settings[key] = val
+	}
+	projects, err := database.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		doc.Projects[p.Name] = configProject{Instructions: p.Instructions}
+	}
+	return doc, nil
+}
+
+// configChanges is what an edited config file changes.
+type configChanges struct {
+	settings     map[string]string // key -> validated value
+	instructions map[string]string // project -> instructions
+}
+
+func (c *configChanges) empty() bool {
+	return len(c.settings) == 0 && len(c.instructions) == 0
+}
+
+// diffConfigDoc parses an edited config file and validates everything that
+// differs from current. It reports the first problem found, naming the
+// field.
+func diffConfigDoc(database *db.DB, current *configDoc, edited []byte) (*configChanges, error) {
+	var doc configDoc
+	if err := yaml.Unmarshal(edited, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	changes := &configChanges{settings: map[string]string{}, instructions: map[string]string{}}
+	keys := make([]string, 0, len(doc.Settings))
+	for key := range doc.Settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := strings.TrimSpace(doc.Settings[key])
+		old, known := current.Settings[key]
+		if !known {
+			return nil, fmt.Errorf("settings.%s: %v (available: %s)", key, errUnknownSetting, strings.Join(settingKeys, ", "))
+		}
+		if value == old {
+			continue
+		}
+		if value == "" {
+			// Blank secrets mean "keep"; other blanks fall back to the default.
+			if !db.IsSecretSetting(key) {
+				changes.settings[key] = ""
+			}
+			continue
+		}
+		normalized, err := validateSetting(key, value)
+		if err != nil {
+			return nil, fmt.Errorf("settings.%s: %w", key, err)
+		}
+		changes.settings[key] = normalized
+	}
+
+	for name, p := range doc.Projects {
+		old, known := current.Projects[name]
+		if !known {
+			return nil, fmt.Errorf("projects.%s: no such project (create it with 'ty projects create')", name)
+		}
+		if p.Instructions != old.Instructions {
+			changes.instructions[name] = p.Instructions
+		}
+	}
+	return changes, nil
+}
+
+// applyConfigChanges saves validated changes.
+func applyConfigChanges(database *db.DB, changes *configChanges) error {
+	keys := make([]string, 0, len(changes.settings))
+	for key := range changes.settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := saveSetting(database, key, changes.settings[key]); err != nil {
+			return fmt.Errorf("save %s: %w", key, err)
+		}
+	}
+
+	names := make([]string, 0, len(changes.instructions))
+	for name := range changes.instructions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := setProjectInstructions(database, name, changes.instructions[name]); err != nil {
+			return err
+		}
+		outln(successStyle.Render("Instructions saved: " + name))
+	}
+	return nil
+}
+
+// setProjectInstructions replaces a project's instructions.
+func setProjectInstructions(database *db.DB, name, instructions string) error {
+	project, err := database.GetProjectByName(name)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return fmt.Errorf("project '%s' not found", name)
+	}
+	project.Instructions = instructions
+	return database.UpdateProject(project)
+}
+
+func newProjectsEditCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "edit <name>",
+		Short:             "Edit a project's instructions in $EDITOR",
+		ValidArgsFunction: completeProjectNames,
+		Long: `Opens the project's instructions in $EDITOR and saves them when you close the
+editor.
+
+Examples:
+  ty projects edit myapp`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			project, err := database.GetProjectByName(args[0])
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if project == nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Project '%s' not found", args[0])))
+				os.Exit(1)
+			}
+
+			f, err := os.CreateTemp("", "ty-"+project.Name+"-instructions-*.md")
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			path := f.Name()
+			defer os.Remove(path)
+			f.WriteString(project.Instructions)
+			f.Close()
+
+			if err := runEditor(path); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Editor failed: "+err.Error()))
+				os.Exit(1)
+			}
+			edited, err := os.ReadFile(path)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if bytes.Equal(edited, []byte(project.Instructions)) {
+				fmt.Println(dimStyle.Render("No changes"))
+				return
+			}
+			if err := setProjectInstructions(database, project.Name, string(edited)); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			outln(successStyle.Render("Instructions saved: " + project.Name))
+		},
+	}
+}
+
+// runEditor opens path in $EDITOR (vim if unset) and waits for it to exit.
+func runEditor(path string) error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vim"
+	}
+	// $EDITOR may be a command with flags (e.g. "code --wait"), so run via shell.
+	editCmd := exec.Command("sh", "-c", editor+" "+shellQuote(path))
+	editCmd.Stdin = os.Stdin
+	editCmd.Stdout = os.Stdout
+	editCmd.Stderr = os.Stderr
+	return editCmd.Run()
+}
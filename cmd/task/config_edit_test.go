@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestValidateSetting(t *testing.T) {
+	valid := map[string]string{
+		"anthropic_api_key":     "sk-ant-abc",
+		"autocomplete_enabled":  "false",
+		"idle_suspend_timeout":  "1h30m",
+		"http_api_port":         "9090",
+		"max_concurrent_tasks":  "2",
+		"max_retries":           "0",
+		"pr_cache_ttl":          "0s",
+		"notifications_enabled": "true",
+		"default_model":         "custom:next-model",
+	}
+	for key, value := range valid {
+		if _, err := validateSetting(key, value); err != nil {
+			t.Errorf("validateSetting(%q, %q): %v", key, value, err)
+		}
+	}
+
+	invalid := map[string]string{
+		"anthropic_api_key":    "abc",
+		"autocomplete_enabled": "yes",
+		"idle_suspend_timeout": "soon",
+		"http_api_port":        "70000",
+		"max_concurrent_tasks": "0",
+		"max_attachment_size":  "-1",
+		"default_model":        "gpt-4o",
+	}
+	for key, value := range invalid {
+		if _, err := validateSetting(key, value); err == nil {
+			t.Errorf("validateSetting(%q, %q) accepted an invalid value", key, value)
+		}
+	}
+
+	if _, err := validateSetting("nope", "1"); !errors.Is(err, errUnknownSetting) {
+		t.Errorf("unknown key: got %v", err)
+	}
+	for _, key := range settingKeys {
+		if _, err := validateSetting(key, ""); errors.Is(err, errUnknownSetting) {
+			t.Errorf("%s is listed in settingKeys but not validated", key)
+		}
+	}
+}
+
+func TestConfigEditRoundTrip(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	if err := database.CreateProject(&db.Project{Name: "app", Path: t.TempDir(), Instructions: "Use Go."}); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.SetSetting("max_retries", "2"); err != nil {
+		t.Fatal(err)
+	}
+
+	current, err := loadConfigDoc(database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.Settings["max_retries"] != "2" || current.Projects["app"].Instructions != "Use Go." {
+		t.Fatalf("unexpected doc: %+v", current)
+	}
+
+	edited := `settings:
+  max_retries: "3"
+  pr_cache_ttl: 5m
+  anthropic_api_key: ""
+projects:
+  app:
+    instructions: Use Go 1.22.
+`
+	changes, err := diffConfigDoc(database, current, []byte(edited))
+	if err != nil {
+		t.Fatalf("diffConfigDoc: %v", err)
+	}
+	if len(changes.settings) != 2 || changes.settings["max_retries"] != "3" || changes.settings["pr_cache_ttl"] != "5m" {
+		t.Errorf("settings changes = %v", changes.settings)
+	}
+	if err := applyConfigChanges(database, changes); err != nil {
+		t.Fatalf("applyConfigChanges: %v", err)
+	}
+	if v, _ := database.GetSetting("max_retries"); v != "3" {
+		t.Errorf("max_retries = %q", v)
+	}
+	if p, _ := database.GetProjectByName("app"); p.Instructions != "Use Go 1.22." {
+		t.Errorf("instructions = %q", p.Instructions)
+	}
+
+	for doc, want := range map[string]string{
+		"settings:\n  idle_suspend_timeout: soon\n":   "settings.idle_suspend_timeout",
+		"settings:\n  colour: blue\n":                 "settings.colour",
+		"projects:\n  ghost:\n    instructions: hi\n": "projects.ghost",
+		"settings: [\n": "invalid YAML",
+		"settings:\n  max_retries: \"1\"\n  http_api_port: x\n": "settings.http_api_port",
+	} {
+		_, err := diffConfigDoc(database, current, []byte(doc))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("diffConfigDoc(%q) error = %v, want mention of %q", doc, err, want)
+		}
+	}
+	if v, _ := database.GetSetting("max_retries"); v != "3" {
+		t.Errorf("rejected edit changed max_retries to %q", v)
+	}
+}
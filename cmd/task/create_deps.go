@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+// checkCreateBlockers verifies every --depends-on task exists, before the new
+// task is created, and reports whether any of them is still open (not done or
+// archived) so the new task should start out blocked.
+func checkCreateBlockers(database *db.DB, blockerIDs []int64) (open bool, err error) {
+	seen := make(map[int64]bool, len(blockerIDs))
+	for _, id := range blockerIDs {
+		if seen[id] {
+			return false, fmt.Errorf("--depends-on #%d given twice", id)
+		}
+		seen[id] = true
+
+		blocker, err := database.GetTask(id)
+		if err != nil {
+			return false, err
+		}
+		if blocker == nil {
+			return false, fmt.Errorf("blocker task #%d not found", id)
+		}
+		if blocker.Status != db.StatusDone && blocker.Status != db.StatusArchived {
+			open = true
+		}
+	}
+	return open, nil
+}
+
+// addCreateDependencies records task as blocked by each of blockerIDs. If any
+// insert fails the task is deleted again (its dependency rows go with it), so
+// 'ty create --depends-on' either fully succeeds or leaves nothing behind.
+func addCreateDependencies(database *db.DB, task *db.Task, blockerIDs []int64, autoQueue bool) error {
+	for _, id := range blockerIDs {
+		if err := database.AddDependency(id, task.ID, autoQueue); err != nil {
+			if delErr := database.DeleteTask(task.ID); delErr != nil {
+				return fmt.Errorf("depend on #%d: %w (and removing task #%d failed: %v)", id, err, task.ID, delErr)
+			}
+			return fmt.Errorf("depend on #%d: %w", id, err)
+		}
+	}
+	return nil
+}
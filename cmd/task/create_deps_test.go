@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestCheckCreateBlockers(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	ids := createTestTasks(t, database, 2)
+	if err := database.UpdateTaskStatus(ids[1], db.StatusDone); err != nil {
+		t.Fatal(err)
+	}
+
+	if open, err := checkCreateBlockers(database, []int64{ids[1]}); err != nil || open {
+		t.Errorf("done blocker: open=%v err=%v", open, err)
+	}
+	if open, err := checkCreateBlockers(database, []int64{ids[0], ids[1]}); err != nil || !open {
+		t.Errorf("open blocker: open=%v err=%v", open, err)
+	}
+	if _, err := checkCreateBlockers(database, []int64{ids[0], 9999}); err == nil || !strings.Contains(err.Error(), "#9999 not found") {
+		t.Errorf("missing blocker: %v", err)
+	}
+	if _, err := checkCreateBlockers(database, []int64{ids[0], ids[0]}); err == nil {
+		t.Error("duplicate blocker accepted")
+	}
+}
+
+func TestAddCreateDependencies(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	ids := createTestTasks(t, database, 2)
+	task := &db.Task{Title: "deploy", Status: db.StatusBlocked}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	if err := addCreateDependencies(database, task, ids, true); err != nil {
+		t.Fatalf("addCreateDependencies: %v", err)
+	}
+	blockers, err := database.GetBlockers(task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blockers) != 2 {
+		t.Errorf("got %d blockers, want 2", len(blockers))
+	}
+
+	// A failing insert (here: a blocker that vanished after validation)
+	// removes the new task again.
+	rollback := &db.Task{Title: "orphan", Status: db.StatusBlocked}
+	if err := database.CreateTask(rollback); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.DeleteTask(ids[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := addCreateDependencies(database, rollback, []int64{ids[0], ids[1]}, false); err == nil {
+		t.Fatal("expected an error for a missing blocker")
+	}
+	if got, _ := database.GetTask(rollback.ID); got != nil {
+		t.Errorf("task #%d was not rolled back", rollback.ID)
+	}
+}
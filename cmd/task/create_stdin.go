@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+// stdinBodyMarker on a line of its own starts the body of the task above it.
+const stdinBodyMarker = "---"
+
+// maxStdinLine caps a single line of `ty create --stdin` input.
+const maxStdinLine = 1024 * 1024
+
+// stdinTask is one task parsed from `ty create --stdin` input.
+type stdinTask struct {
+	Title string
+	Body  string
+}
+
+// scanStdinTasks parses brain-dump input and calls fn for each task as soon as
+// it is complete, so arbitrarily large input is never held in memory:
+//
+//	Fix login bug              <- one task per non-empty line
+//	Add dark mode
+//	---                        <- starts a body for "Add dark mode"
+//	Follow the system setting.
+//	                           <- a blank line ends the body
+//	Write release notes
+//
+// A "---" with no title above it is an error.
+func scanStdinTasks(r io.Reader, fn func(stdinTask) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStdinLine)
+
+	var pending *stdinTask
+	var body []string
+	inBody := false
+	lineNo := 0
+
+	flush := func() error {
+		if pending == nil {
+			return nil
+		}
+		t := *pending
+		t.Body = strings.TrimSpace(strings.Join(body, "\n"))
+		pending, body, inBody = nil, nil, false
+		return fn(t)
+	}
+
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			if err := flush(); err != nil {
+				return err
+			}
+		case inBody:
+			body = append(body, line)
+		case trimmed == stdinBodyMarker:
+			if pending == nil {
+				return fmt.Errorf("line %d: %q must follow a title", lineNo, stdinBodyMarker)
+			}
+			inBody = true
+		default:
+			if err := flush(); err != nil {
+				return err
+			}
+			pending = &stdinTask{Title: trimmed}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read stdin: %w", err)
+	}
+	return flush()
+}
+
+// createTasksFromStdin creates one task per entry in r, each a copy of
+// template with the entry's title and body. created is called after each
+// insert so progress is reported while the input is still streaming.
+func createTasksFromStdin(database *db.DB, r io.Reader, template db.Task, created func(*db.Task)) (int, error) {
+	count := 0
+	err := scanStdinTasks(r, func(entry stdinTask) error {
+		task := template
+		task.Title = entry.Title
+		task.Body = entry.Body
+		if err := database.CreateTask(&task); err != nil {
+			return fmt.Errorf("create %q: %w", entry.Title, err)
+		}
+		count++
+		if created != nil {
+			created(&task)
+		}
+		return nil
+	})
+	return count, err
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestScanStdinTasks(t *testing.T) {
+	input := `Fix login bug
+  Add dark mode
+---
+Follow the system setting.
+  Keep the toggle.
+
+
+Write release notes
+---
+
+Trailing title`
+
+	var got []stdinTask
+	if err := scanStdinTasks(strings.NewReader(input), func(task stdinTask) error {
+		got = append(got, task)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []stdinTask{
+		{Title: "Fix login bug"},
+		{Title: "Add dark mode", Body: "Follow the system setting.\n  Keep the toggle."},
+		{Title: "Write release notes"},
+		{Title: "Trailing title"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d tasks %+v, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("task %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestScanStdinTasksBodyWithoutTitle(t *testing.T) {
+	err := scanStdinTasks(strings.NewReader("---\nbody\n"), func(stdinTask) error { return nil })
+	if err == nil {
+		t.Fatal("expected an error for a body with no title")
+	}
+}
+
+func TestCreateTasksFromStdin(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	template := db.Task{Status: db.StatusBacklog, Type: db.TypeCode, Project: "personal", Tags: "inbox"}
+	var ids []int64
+	count, err := createTasksFromStdin(database, strings.NewReader("one\ntwo\n---\nbody\n"), template, func(task *db.Task) {
+		ids = append(ids, task.ID)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 || len(ids) != 2 {
+		t.Fatalf("created %d tasks (%v), want 2", count, ids)
+	}
+
+	second, err := database.GetTask(ids[1])
+	if err != nil || second == nil {
+		t.Fatalf("get task: %v", err)
+	}
+	if second.Title != "two" || second.Body != "body" || second.Tags != "inbox" || second.Status != db.StatusBacklog {
+		t.Errorf("unexpected task: %+v", second)
+	}
+}
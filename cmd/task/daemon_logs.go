@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// daemonLogMaxSize is the size at which the daemon log is rotated. One
+// rotated file (daemon.log.1) is kept, so the logs never use more than twice
+// this on disk.
+const daemonLogMaxSize = 10 * 1024 * 1024
+
+// getDaemonLogPath returns the daemon's log file, next to its PID file
+// (~/.local/share/task/daemon.log for the live instance).
+func getDaemonLogPath() string {
+	return strings.TrimSuffix(getPidFilePath(), ".pid") + ".log"
+}
+
+// rotatingFile is an append-only log file that moves itself to path.1 once it
+// grows past maxSize.
+type rotatingFile struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	f       *os.File
+	size    int64
+}
+
+func openRotatingFile(path string, maxSize int64) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, maxSize: maxSize}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("stat log file: %w", err)
+	}
+	r.f, r.size = f, info.Size()
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		r.f.Close()
+		os.Rename(r.path, r.path+".1")
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Close()
+}
+
+// daemonLogWriter receives the daemon's JSON log lines. It stores them as-is
+// in the log file and, when console is set, also writes a readable rendering
+// there, so a foreground `ty daemon` still shows its output.
+type daemonLogWriter struct {
+	file    io.Writer
+	console io.Writer
+}
+
+func (w *daemonLogWriter) Write(p []byte) (int, error) {
+	n, err := w.file.Write(p)
+	if w.console != nil {
+		fmt.Fprintln(w.console, formatDaemonLogLine(bytes.TrimRight(p, "\n")))
+	}
+	return n, err
+}
+
+// formatDaemonLogLine renders one JSON log line as
+// "2006-01-02 15:04:05 INFO prefix: message key=value ...". Lines that aren't
+// JSON are returned unchanged.
+func formatDaemonLogLine(line []byte) string {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return string(line)
+	}
+
+	var ts, level, prefix, msg string
+	var fields []string
+	for dec.More() {
+		keyTok, err := dec.Token()
+		if err != nil {
+			return string(line)
+		}
+		key, _ := keyTok.(string)
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			return string(line)
+		}
+		str := fmt.Sprint(value)
+		switch key {
+		case "time":
+			ts = str
+			if t, err := time.Parse(time.RFC3339, str); err == nil {
+				ts = t.Local().Format("2006-01-02 15:04:05")
+			}
+		case "level":
+			level = strings.ToUpper(str)
+		case "prefix":
+			prefix = str
+		case "msg":
+			msg = str
+		default:
+			if strings.ContainsAny(str, " \t\"=") {
+				str = fmt.Sprintf("%q", str)
+			}
+			fields = append(fields, key+"="+str)
+		}
+	}
+
+	var b strings.Builder
+	for _, part := range []string{ts, level} {
+		if part != "" {
+			b.WriteString(part)
+			b.WriteByte(' ')
+		}
+	}
+	if prefix != "" {
+		b.WriteString(prefix + ": ")
+	}
+	b.WriteString(msg)
+	for _, f := range fields {
+		b.WriteByte(' ')
+		b.WriteString(f)
+	}
+	return b.String()
+}
+
+// lastDaemonLogLines returns up to n of the most recent lines across the
+// rotated and current log files, oldest first.
+func lastDaemonLogLines(path string, n int) ([]string, error) {
+	var lines []string
+	for _, p := range []string{path + ".1", path} {
+		f, err := os.Open(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+			if len(lines) > n {
+				lines = lines[1:]
+			}
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return lines, nil
+}
+
+// followDaemonLog prints lines appended to the log after offset until
+// interrupted, starting over when the file is rotated.
+func followDaemonLog(path string, offset int64, show func(string)) error {
+	var partial string
+	for {
+		info, err := os.Stat(path)
+		if err == nil {
+			if info.Size() < offset {
+				// Rotated (or truncated): read the new file from the start.
+				offset, partial = 0, ""
+			}
+			if info.Size() > offset {
+				f, err := os.Open(path)
+				if err != nil {
+					return err
+				}
+				f.Seek(offset, io.SeekStart)
+				data, err := io.ReadAll(f)
+				f.Close()
+				if err != nil {
+					return err
+				}
+				offset += int64(len(data))
+				chunk := partial + string(data)
+				lines := strings.Split(chunk, "\n")
+				partial = lines[len(lines)-1]
+				for _, line := range lines[:len(lines)-1] {
+					show(line)
+				}
+			}
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+func newDaemonLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Show recent daemon log output",
+		Long: `Show the daemon's log file (see 'ty daemon status' for its path).
+
+The daemon keeps its structured logs in JSON; they are shown as readable
+lines unless --json is given. The file is rotated at 10MB.
+
+Examples:
+  ty daemon logs               # Last 50 lines
+  ty daemon logs -n 200
+  ty daemon logs -f            # Follow new output
+  ty daemon logs --json | jq 'select(.level == "error")'`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			lines, _ := cmd.Flags().GetInt("lines")
+			follow, _ := cmd.Flags().GetBool("follow")
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			show := func(line string) {
+				if outputJSON {
+					fmt.Println(line)
+					return
+				}
+				fmt.Println(formatDaemonLogLine([]byte(line)))
+			}
+
+			path := getDaemonLogPath()
+			info, err := os.Stat(path)
+			if os.IsNotExist(err) && !follow {
+				infoln(dimStyle.Render("No daemon log yet at " + path))
+				return
+			}
+
+			recent, err := lastDaemonLogLines(path, lines)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			for _, line := range recent {
+				show(line)
+			}
+
+			if follow {
+				var offset int64
+				if info != nil {
+					offset = info.Size()
+				}
+				if err := followDaemonLog(path, offset, show); err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+			}
+		},
+	}
+	cmd.Flags().IntP("lines", "n", 50, "Number of recent lines to show")
+	cmd.Flags().BoolP("follow", "f", false, "Keep printing new log lines as they are written")
+	cmd.Flags().Bool("json", false, "Print the raw JSON log lines")
+	return cmd
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+func TestDaemonLogWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.log")
+	file, err := openRotatingFile(path, daemonLogMaxSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var console bytes.Buffer
+	logger := log.NewWithOptions(&daemonLogWriter{file: file, console: &console}, log.Options{
+		ReportTimestamp: true,
+		TimeFormat:      time.RFC3339,
+		Prefix:          "task-daemon",
+		Formatter:       log.JSONFormatter,
+	})
+	logger.Info("Task finished", "id", 42, "note", "two words")
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(raw), `{"time":`) || !strings.Contains(string(raw), `"msg":"Task finished"`) {
+		t.Errorf("log file should hold the JSON line, got %q", raw)
+	}
+	got := console.String()
+	for _, want := range []string{"INFO task-daemon: Task finished", "id=42", `note="two words"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("console output %q missing %q", got, want)
+		}
+	}
+}
+
+func TestFormatDaemonLogLineNonJSON(t *testing.T) {
+	if got := formatDaemonLogLine([]byte("plain text line")); got != "plain text line" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestRotatingFileAndLastLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.log")
+	file, err := openRotatingFile(path, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"line-1\n", "line-2\n", "line-3\n", "line-4\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	file.Close()
+
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Fatalf("expected a rotated file: %v", err)
+	}
+	current, _ := os.ReadFile(path)
+	if string(current) != "line-3\nline-4\n" {
+		t.Errorf("current file = %q, want only the lines written after rotation", current)
+	}
+
+	lines, err := lastDaemonLogLines(path, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(lines, ",") != "line-2,line-3,line-4" {
+		t.Errorf("last lines = %v, want line-2..line-4 across the rotation", lines)
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/bborn/workflow/internal/config"
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/executor"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+func newDaemonRunOnceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run-once",
+		Short: "Run the currently queued tasks, then exit",
+		Long: `Runs the tasks that are queued right now, without a long-running daemon,
+and exits once each has finished, blocked for input, or failed. Tasks queued
+after it starts (including dependents its tasks unblock) are left for the next
+run. Meant for CI and cron.
+
+It exits 1 if any task failed. A task that blocked asking for input is not a
+failure; its tmux window is left open, as the daemon would leave it. The
+daemon's periodic work (auto-retries, schedules, cleanup) doesn't run, and it
+refuses to start while a daemon is running.
+
+With --timeout, tasks still running when it expires are interrupted and the
+command exits 1.
+
+Examples:
+  ty daemon run-once
+  ty daemon run-once --timeout 30m --json`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			outputJSON, _ := cmd.Flags().GetBool("json")
+			if dangerous, _ := cmd.Flags().GetBool("dangerous"); dangerous {
+				os.Setenv("WORKTREE_DANGEROUS_MODE", "1")
+			}
+
+			results, err := runDaemonOnce(timeout)
+			if err != nil && results == nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			failed := 0
+			for _, r := range results {
+				if r.Failed {
+					failed++
+				}
+			}
+			if outputJSON {
+				items := make([]map[string]interface{}, 0, len(results))
+				for _, r := range results {
+					items = append(items, map[string]interface{}{
+						"id":     r.ID,
+						"title":  r.Title,
+						"status": r.Status,
+						"failed": r.Failed,
+					})
+				}
+				out := map[string]interface{}{"tasks": items, "failed": failed}
+				if err != nil {
+					out["error"] = err.Error()
+				}
+				jsonBytes, _ := json.MarshalIndent(out, "", "  ")
+				fmt.Println(string(jsonBytes))
+			} else {
+				printDrainedTasks(results)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				}
+			}
+			if err != nil || failed > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().Duration("timeout", 0, "Give up after this long, interrupting tasks still running (0 = no limit)")
+	cmd.Flags().Bool("json", false, "Output in JSON format")
+	return cmd
+}
+
+// runDaemonOnce drains the queue like the daemon would. It holds the daemon's
+// lock and pid file while it runs, so a daemon can't start alongside it and
+// other commands see a daemon running. If it is interrupted or times out, it
+// returns the error together with where the tasks stood.
+func runDaemonOnce(timeout time.Duration) ([]executor.DrainedTask, error) {
+	pidFile := getPidFilePath()
+	lockFile, err := os.OpenFile(pidFile+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open lock file: %w", err)
+	}
+	defer lockFile.Close()
+	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		return nil, fmt.Errorf("a daemon is already running; stop it first with 'ty daemon stop'")
+	}
+	defer syscall.Flock(int(lockFile.Fd()), syscall.LOCK_UN)
+	if err := writePidFile(pidFile, os.Getpid()); err != nil {
+		return nil, fmt.Errorf("write pid file: %w", err)
+	}
+	defer os.Remove(pidFile)
+
+	database, err := openTaskDB(db.DefaultPath())
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	defer database.Close()
+
+	logger := log.NewWithOptions(os.Stderr, log.Options{ReportTimestamp: true, Prefix: "run-once"})
+	exec := executor.NewWithLogger(database, config.New(database), logger)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	if timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+		defer cancelTimeout()
+	}
+
+	results, err := exec.Drain(ctx)
+	exec.Stop()
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s", timeout)
+	} else if errors.Is(err, context.Canceled) {
+		err = errors.New("interrupted")
+	}
+	return results, err
+}
+
+func printDrainedTasks(results []executor.DrainedTask) {
+	if len(results) == 0 {
+		fmt.Println(dimStyle.Render("No queued tasks"))
+		return
+	}
+	failed := 0
+	for _, r := range results {
+		var outcome string
+		switch {
+		case r.Failed:
+			failed++
+			outcome = errorStyle.Render("failed")
+		case r.Status == db.StatusBlocked:
+			outcome = warnStyle.Render("needs input")
+		case r.Status == db.StatusBacklog || r.Status == db.StatusDone:
+			outcome = successStyle.Render("finished")
+		default:
+			outcome = dimStyle.Render(r.Status)
+		}
+		fmt.Printf("%s %s %s\n", dimStyle.Render(fmt.Sprintf("#%-4d", r.ID)), outcome, truncate(r.Title, 60))
+	}
+	fmt.Println(dimStyle.Render(fmt.Sprintf("%d task(s) run, %d failed", len(results), failed)))
+}
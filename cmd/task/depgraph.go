@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+// depGraph is the blocker graph `ty deps --graph` renders: the tasks involved
+// and an edge from each blocker to the task it blocks.
+type depGraph struct {
+	Tasks []*db.Task // sorted by ID
+	Edges [][2]int64 // {blocker, blocked}
+	// Cycles lists each dependency cycle found, as the task IDs along it
+	// (the first ID is repeated at the end).
+	Cycles [][]int64
+}
+
+// depGraphColors fills graph nodes by task status.
+var depGraphColors = map[string]string{
+	db.StatusBacklog:    "#e5e7eb",
+	db.StatusQueued:     "#bfdbfe",
+	db.StatusProcessing: "#fde68a",
+	db.StatusBlocked:    "#fecaca",
+	db.StatusDone:       "#bbf7d0",
+	db.StatusArchived:   "#f3f4f6",
+}
+
+// buildDepGraph collects the dependency graph. With rootID 0 it covers every
+// task that has a dependency; otherwise it is rootID's transitive closure:
+// everything it waits on and everything waiting on it.
+func buildDepGraph(database *db.DB, rootID int64) (*depGraph, error) {
+	deps, err := database.ListDependencies()
+	if err != nil {
+		return nil, err
+	}
+
+	forward := map[int64][]int64{}
+	backward := map[int64][]int64{}
+	for _, d := range deps {
+		forward[d.BlockerID] = append(forward[d.BlockerID], d.BlockedID)
+		backward[d.BlockedID] = append(backward[d.BlockedID], d.BlockerID)
+	}
+
+	include := map[int64]bool{}
+	if rootID == 0 {
+		for _, d := range deps {
+			include[d.BlockerID] = true
+			include[d.BlockedID] = true
+		}
+	} else {
+		include[rootID] = true
+		for _, adj := range []map[int64][]int64{forward, backward} {
+			queue := []int64{rootID}
+			seen := map[int64]bool{rootID: true}
+			for len(queue) > 0 {
+				id := queue[0]
+				queue = queue[1:]
+				for _, next := range adj[id] {
+					if !seen[next] {
+						seen[next] = true
+						include[next] = true
+						queue = append(queue, next)
+					}
+				}
+			}
+		}
+	}
+
+	g := &depGraph{}
+	for _, d := range deps {
+		if include[d.BlockerID] && include[d.BlockedID] {
+			g.Edges = append(g.Edges, [2]int64{d.BlockerID, d.BlockedID})
+		}
+	}
+	ids := make([]int64, 0, len(include))
+	for id := range include {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		task, err := database.GetTask(id)
+		if err != nil {
+			return nil, err
+		}
+		if task != nil {
+			g.Tasks = append(g.Tasks, task)
+		}
+	}
+	g.Cycles = findDepCycles(ids, g.Edges)
+	return g, nil
+}
+
+// findDepCycles returns the cycles in the graph, one per back edge found by a
+// depth-first walk. AddDependency refuses to create cycles, but older rows or
+// imports can still contain them, and they deadlock the auto-queue.
+func findDepCycles(ids []int64, edges [][2]int64) [][]int64 {
+	adj := map[int64][]int64{}
+	for _, e := range edges {
+		adj[e[0]] = append(adj[e[0]], e[1])
+	}
+
+	const (
+		unvisited = iota
+		onStack
+		finished
+	)
+	state := map[int64]int{}
+	var stack []int64
+	var cycles [][]int64
+
+	var visit func(id int64)
+	visit = func(id int64) {
+		state[id] = onStack
+		stack = append(stack, id)
+		for _, next := range adj[id] {
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case onStack:
+				// Back edge: the cycle is the stack from next up to id.
+				for i, s := range stack {
+					if s == next {
+						cycle := append([]int64{}, stack[i:]...)
+						cycles = append(cycles, append(cycle, next))
+						break
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = finished
+	}
+	for _, id := range ids {
+		if state[id] == unvisited {
+			visit(id)
+		}
+	}
+	return cycles
+}
+
+// formatDepCycle renders a cycle as "#1 → #2 → #1".
+func formatDepCycle(cycle []int64) string {
+	parts := make([]string, len(cycle))
+	for i, id := range cycle {
+		parts[i] = fmt.Sprintf("#%d", id)
+	}
+	return strings.Join(parts, " → ")
+}
+
+// writeMermaidDepGraph writes g as a Mermaid flowchart, blockers above the
+// tasks they block.
+func writeMermaidDepGraph(w io.Writer, g *depGraph) {
+	fmt.Fprintln(w, "graph TD")
+	for _, t := range g.Tasks {
+		label := strings.ReplaceAll(fmt.Sprintf("#%d: %s [%s]", t.ID, t.Title, t.Status), `"`, "#quot;")
+		fmt.Fprintf(w, "    t%d[\"%s\"]\n", t.ID, label)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(w, "    t%d --> t%d\n", e[0], e[1])
+	}
+
+	byStatus := map[string][]string{}
+	for _, t := range g.Tasks {
+		byStatus[t.Status] = append(byStatus[t.Status], fmt.Sprintf("t%d", t.ID))
+	}
+	statuses := make([]string, 0, len(byStatus))
+	for status := range byStatus {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		if color, ok := depGraphColors[status]; ok {
+			fmt.Fprintf(w, "    classDef %s fill:%s,stroke:#6b7280\n", status, color)
+			fmt.Fprintf(w, "    class %s %s\n", strings.Join(byStatus[status], ","), status)
+		}
+	}
+}
+
+// writeDotDepGraph writes g in Graphviz DOT format.
+func writeDotDepGraph(w io.Writer, g *depGraph) {
+	quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	fmt.Fprintln(w, "digraph deps {")
+	fmt.Fprintln(w, "    rankdir=TB;")
+	fmt.Fprintln(w, `    node [shape=box, style="rounded,filled", fontname="Helvetica"];`)
+	for _, t := range g.Tasks {
+		color := depGraphColors[t.Status]
+		if color == "" {
+			color = "#ffffff"
+		}
+		label := quote.Replace(fmt.Sprintf("#%d: %s", t.ID, t.Title)) + `\n[` + t.Status + "]"
+		fmt.Fprintf(w, "    t%d [label=\"%s\", fillcolor=\"%s\"];\n", t.ID, label, color)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(w, "    t%d -> t%d;\n", e[0], e[1])
+	}
+	fmt.Fprintln(w, "}")
+}
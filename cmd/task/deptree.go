@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+// depNode is one task in a dependency tree rooted at the task being shown.
+// Children follow the same direction as the branch it hangs off: blockers of
+// a blocker under "blocked by", dependents of a dependent under "blocks".
+type depNode struct {
+	Task     *db.Task
+	Children []*depNode
+	// Seen marks a task already expanded elsewhere in the same branch (a
+	// diamond or a cycle); it is listed but not expanded again.
+	Seen bool
+}
+
+// depTree is the dependency context `ty show --tree` appends.
+type depTree struct {
+	BlockedBy []*depNode
+	Blocks    []*depNode
+}
+
+// buildDepTree collects taskID's blockers and dependents. With deep it follows
+// each direction transitively; otherwise it stops after one hop.
+func buildDepTree(database *db.DB, taskID int64, deep bool) (*depTree, error) {
+	blockedBy, err := expandDeps(database.GetBlockers, taskID, deep, map[int64]bool{taskID: true})
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := expandDeps(database.GetBlockedBy, taskID, deep, map[int64]bool{taskID: true})
+	if err != nil {
+		return nil, err
+	}
+	return &depTree{BlockedBy: blockedBy, Blocks: blocks}, nil
+}
+
+func expandDeps(next func(int64) ([]*db.Task, error), taskID int64, deep bool, seen map[int64]bool) ([]*depNode, error) {
+	tasks, err := next(taskID)
+	if err != nil {
+		return nil, err
+	}
+	var nodes []*depNode
+	for _, t := range tasks {
+		node := &depNode{Task: t}
+		if seen[t.ID] {
+			node.Seen = true
+		} else if deep {
+			seen[t.ID] = true
+			if node.Children, err = expandDeps(next, t.ID, deep, seen); err != nil {
+				return nil, err
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// depStatusLabel renders a task's status the way `ty deps` does.
+func depStatusLabel(t *db.Task) string {
+	if t.Status == db.StatusDone || t.Status == db.StatusArchived {
+		return successStyle.Render("[done]")
+	}
+	return dimStyle.Render(fmt.Sprintf("[%s]", t.Status))
+}
+
+// printDepTree writes the tree below a "Dependencies:" heading.
+func printDepTree(tree *depTree) {
+	fmt.Println()
+	fmt.Println(boldStyle.Render("Dependencies:"))
+	if len(tree.BlockedBy) == 0 && len(tree.Blocks) == 0 {
+		fmt.Println(dimStyle.Render("  No dependencies"))
+		return
+	}
+	if len(tree.BlockedBy) > 0 {
+		fmt.Println("  Blocked by:")
+		printDepNodes(tree.BlockedBy, "    ")
+	}
+	if len(tree.Blocks) > 0 {
+		fmt.Println("  Blocks:")
+		printDepNodes(tree.Blocks, "    ")
+	}
+}
+
+func printDepNodes(nodes []*depNode, indent string) {
+	for i, n := range nodes {
+		branch, childIndent := "├─ ", "│  "
+		if i == len(nodes)-1 {
+			branch, childIndent = "└─ ", "   "
+		}
+		line := fmt.Sprintf("%s%s#%d: %s %s", indent, dimStyle.Render(branch), n.Task.ID, n.Task.Title, depStatusLabel(n.Task))
+		if n.Seen {
+			line += dimStyle.Render(" (see above)")
+		}
+		fmt.Println(line)
+		printDepNodes(n.Children, indent+dimStyle.Render(childIndent))
+	}
+}
+
+// printDirectDeps writes the one-hop "Blocked by" / "Blocks" sections that
+// `ty show` prints without --tree. Finished blockers get a green checkmark so
+// it's clear at a glance whether the task is ready to run.
+func printDirectDeps(blockers, blocks []*db.Task) {
+	if len(blockers) == 0 && len(blocks) == 0 {
+		return
+	}
+	if len(blockers) > 0 {
+		fmt.Println()
+		fmt.Println(boldStyle.Render("Blocked by:"))
+		for _, b := range blockers {
+			mark := dimStyle.Render("·")
+			if b.Status == db.StatusDone || b.Status == db.StatusArchived {
+				mark = successStyle.Render("✓")
+			}
+			fmt.Printf("  %s #%d: %s %s\n", mark, b.ID, b.Title, depStatusLabel(b))
+		}
+	}
+	if len(blocks) > 0 {
+		fmt.Println()
+		fmt.Println(boldStyle.Render("Blocks:"))
+		for _, b := range blocks {
+			fmt.Printf("  #%d: %s %s\n", b.ID, b.Title, depStatusLabel(b))
+		}
+	}
+}
+
+// directDepsJSON is the "dependencies" object in `ty show --json`.
+func directDepsJSON(blockers, blocks []*db.Task) map[string]interface{} {
+	list := func(tasks []*db.Task) []map[string]interface{} {
+		out := []map[string]interface{}{}
+		for _, t := range tasks {
+			out = append(out, map[string]interface{}{
+				"id":     t.ID,
+				"title":  t.Title,
+				"status": t.Status,
+			})
+		}
+		return out
+	}
+	return map[string]interface{}{
+		"blocked_by": list(blockers),
+		"blocks":     list(blocks),
+	}
+}
+
+// depTreeJSON converts the tree for --json output. Each node carries its
+// children under the same key as its branch ("blocked_by" or "blocks").
+func depTreeJSON(tree *depTree) map[string]interface{} {
+	return map[string]interface{}{
+		"blocked_by": depNodesJSON(tree.BlockedBy, "blocked_by"),
+		"blocks":     depNodesJSON(tree.Blocks, "blocks"),
+	}
+}
+
+func depNodesJSON(nodes []*depNode, key string) []map[string]interface{} {
+	out := []map[string]interface{}{}
+	for _, n := range nodes {
+		item := map[string]interface{}{
+			"id":     n.Task.ID,
+			"title":  n.Task.Title,
+			"status": n.Task.Status,
+		}
+		if n.Seen {
+			item["repeated"] = true
+		}
+		if len(n.Children) > 0 {
+			item[key] = depNodesJSON(n.Children, key)
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+// printDependencyCycle explains why `ty block` refused to make blockerID
+// block blockedID: path is the cycle from db.DependencyCyclePath.
+func printDependencyCycle(database *db.DB, blockerID, blockedID int64, path []int64) {
+	if blockerID == blockedID {
+		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: a task cannot block itself"))
+		return
+	}
+	fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Error: blocking #%d by #%d would create a dependency cycle:", blockedID, blockerID)))
+	for i, id := range path {
+		title := ""
+		if t, err := database.GetTask(id); err == nil && t != nil {
+			title = t.Title
+		}
+		prefix := "  "
+		if i > 0 {
+			prefix = "  " + dimStyle.Render("└ blocks ")
+		}
+		fmt.Fprintf(os.Stderr, "%s#%d: %s\n", prefix, id, title)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestBuildDepTree(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	// a → b → c → d: a blocks b, b blocks c, c blocks d.
+	ids := createTestTasks(t, database, 4)
+	a, b, c, d := ids[0], ids[1], ids[2], ids[3]
+	for _, edge := range [][2]int64{{a, b}, {b, c}, {c, d}} {
+		if err := database.AddDependency(edge[0], edge[1], false); err != nil {
+			t.Fatalf("add dependency %v: %v", edge, err)
+		}
+	}
+
+	tree, err := buildDepTree(database, c, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree.BlockedBy) != 1 || tree.BlockedBy[0].Task.ID != b || len(tree.BlockedBy[0].Children) != 0 {
+		t.Errorf("one hop: expected only #%d blocking, got %+v", b, tree.BlockedBy)
+	}
+	if len(tree.Blocks) != 1 || tree.Blocks[0].Task.ID != d {
+		t.Errorf("one hop: expected #%d blocked, got %+v", d, tree.Blocks)
+	}
+
+	tree, err = buildDepTree(database, c, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree.BlockedBy) != 1 || len(tree.BlockedBy[0].Children) != 1 || tree.BlockedBy[0].Children[0].Task.ID != a {
+		t.Errorf("deep: expected #%d under #%d, got %+v", a, b, tree.BlockedBy)
+	}
+
+	out := depTreeJSON(tree)
+	blockedBy := out["blocked_by"].([]map[string]interface{})
+	nested, ok := blockedBy[0]["blocked_by"].([]map[string]interface{})
+	if !ok || len(nested) != 1 || nested[0]["id"] != a {
+		t.Errorf("expected nested blocked_by in JSON, got %+v", blockedBy)
+	}
+	if blocks := out["blocks"].([]map[string]interface{}); len(blocks) != 1 || blocks[0]["status"] != db.StatusBacklog {
+		t.Errorf("unexpected blocks JSON %+v", blocks)
+	}
+}
+
+func TestDirectDepsJSON(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	ids := createTestTasks(t, database, 3)
+	if err := database.AddDependency(ids[0], ids[1], false); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.AddDependency(ids[1], ids[2], false); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.UpdateTaskStatus(ids[0], db.StatusDone); err != nil {
+		t.Fatal(err)
+	}
+
+	blockers, blocks, err := database.GetAllDependencies(ids[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := directDepsJSON(blockers, blocks)
+	blockedBy := out["blocked_by"].([]map[string]interface{})
+	if len(blockedBy) != 1 || blockedBy[0]["id"] != ids[0] || blockedBy[0]["status"] != db.StatusDone {
+		t.Errorf("unexpected blocked_by %+v", blockedBy)
+	}
+	if b := out["blocks"].([]map[string]interface{}); len(b) != 1 || b[0]["id"] != ids[2] {
+		t.Errorf("unexpected blocks %+v", b)
+	}
+
+	// A task with no blockers gets an empty list rather than null.
+	blockers, blocks, _ = database.GetAllDependencies(ids[0])
+	if b := directDepsJSON(blockers, blocks)["blocked_by"].([]map[string]interface{}); b == nil || len(b) != 0 {
+		t.Errorf("expected an empty blocked_by list, got %#v", b)
+	}
+}
+
+func TestBuildDepGraph(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	// a → b → c, plus an unrelated x → y.
+	ids := createTestTasks(t, database, 5)
+	a, b, c, x, y := ids[0], ids[1], ids[2], ids[3], ids[4]
+	for _, edge := range [][2]int64{{a, b}, {b, c}, {x, y}} {
+		if err := database.AddDependency(edge[0], edge[1], false); err != nil {
+			t.Fatalf("add dependency %v: %v", edge, err)
+		}
+	}
+	if err := database.UpdateTaskStatus(a, db.StatusDone); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := buildDepGraph(database, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all.Tasks) != 5 || len(all.Edges) != 3 || len(all.Cycles) != 0 {
+		t.Errorf("whole graph: %d tasks, %d edges, %d cycles", len(all.Tasks), len(all.Edges), len(all.Cycles))
+	}
+
+	scoped, err := buildDepGraph(database, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scoped.Tasks) != 3 || len(scoped.Edges) != 2 {
+		t.Fatalf("closure of #%d: got %d tasks, %d edges", b, len(scoped.Tasks), len(scoped.Edges))
+	}
+
+	var mermaid bytes.Buffer
+	writeMermaidDepGraph(&mermaid, scoped)
+	for _, want := range []string{"graph TD", fmt.Sprintf("t%d --> t%d", a, b), fmt.Sprintf("class t%d done", a)} {
+		if !strings.Contains(mermaid.String(), want) {
+			t.Errorf("mermaid output missing %q:\n%s", want, mermaid.String())
+		}
+	}
+	if strings.Contains(mermaid.String(), fmt.Sprintf("t%d", x)) {
+		t.Errorf("scoped graph includes unrelated task #%d:\n%s", x, mermaid.String())
+	}
+
+	var dot bytes.Buffer
+	writeDotDepGraph(&dot, scoped)
+	for _, want := range []string{"digraph deps {", fmt.Sprintf("t%d -> t%d;", b, c), `fillcolor="#bbf7d0"`} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("dot output missing %q:\n%s", want, dot.String())
+		}
+	}
+}
+
+func TestBuildDepGraphReportsCycles(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	ids := createTestTasks(t, database, 3)
+	// AddDependency refuses cycles, so plant one directly as an old DB could have.
+	for _, edge := range [][2]int64{{ids[0], ids[1]}, {ids[1], ids[2]}, {ids[2], ids[0]}} {
+		if _, err := database.Exec(`INSERT INTO task_dependencies (blocker_id, blocked_id) VALUES (?, ?)`, edge[0], edge[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	g, err := buildDepGraph(database, ids[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Cycles) != 1 {
+		t.Fatalf("expected one cycle, got %v", g.Cycles)
+	}
+	want := fmt.Sprintf("#%d → #%d → #%d → #%d", ids[0], ids[1], ids[2], ids[0])
+	if got := formatDepCycle(g.Cycles[0]); got != want {
+		t.Errorf("cycle = %q, want %q", got, want)
+	}
+}
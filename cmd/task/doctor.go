@@ -0,0 +1,509 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bborn/workflow/internal/config"
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/executor"
+	"github.com/bborn/workflow/internal/github"
+	"github.com/spf13/cobra"
+)
+
+// Statuses a doctor check can report. Only doctorFail makes 'ty doctor' exit
+// non-zero (doctorWarn does too under --strict).
+const (
+	doctorPass = "pass"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+)
+
+// Free space thresholds for the filesystems worktrees are created on.
+const (
+	doctorDiskWarnBytes = 5 << 30
+	doctorDiskFailBytes = 1 << 30
+)
+
+// anthropicModelsURL is probed to validate the Anthropic API key (a var so
+// tests can point it at a local server).
+var anthropicModelsURL = "https://api.anthropic.com/v1/models"
+
+// doctorCheck is one line of the 'ty doctor' report.
+type doctorCheck struct {
+	Category string `json:"category"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Message  string `json:"message"`
+	Detail   string `json:"detail,omitempty"` // remediation hint
+}
+
+func newDoctorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose the local setup (tmux, daemon, database, executors, GitHub auth)",
+		Long: `Checks everything TaskYou needs to run tasks and reports each as pass, warn
+or fail:
+
+  - tmux is installed (and its version)
+  - the daemon is running
+  - the database opens and its schema is current
+  - the Anthropic API key (used for titles and ghost text) is accepted
+  - the default executor and every executor a project uses is on PATH
+  - every project's path exists and is writable
+  - the filesystems worktrees are created on have free space
+  - the GitHub CLI authentication used by agents
+
+For GitHub, it warns about conditions that cause shared GraphQL bucket
+exhaustion across agent servers: authentication as a PERSONAL account, whose
+5,000 pt/hr GraphQL limit is shared per-user across every server authed as
+that account, and low remaining GraphQL headroom. Each agent server should
+authenticate with its OWN GitHub App installation token (a bot identity),
+which gets an independent GraphQL bucket.
+
+Exits non-zero if any check fails (tmux or gh missing, database unreadable, a
+rejected API key, an executor not installed, ...). Pass --strict to also exit
+non-zero on warnings (e.g. personal-account auth), so a fleet sweep like
+'for s in ...; do ssh $s ty doctor --strict; done' can flag servers
+programmatically.
+
+Examples:
+  ty doctor
+  ty doctor --strict
+  ty doctor --json`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			strict, _ := cmd.Flags().GetBool("strict")
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			checks := runDoctorChecks(context.Background())
+			failed := doctorFailed(checks, strict)
+
+			if outputJSON {
+				out, _ := json.MarshalIndent(map[string]interface{}{
+					"ok":     !failed,
+					"checks": checks,
+				}, "", "  ")
+				fmt.Println(string(out))
+			} else {
+				printDoctorReport(checks)
+			}
+			if failed {
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().Bool("strict", false, "Exit non-zero on warnings too (e.g. personal-account auth), for fleet health sweeps")
+	cmd.Flags().Bool("json", false, "Output in JSON format")
+	return cmd
+}
+
+// runDoctorChecks runs every check in report order.
+func runDoctorChecks(ctx context.Context) []doctorCheck {
+	var checks []doctorCheck
+	checks = append(checks, checkTmux())
+	checks = append(checks, checkDaemon(getPidFilePath()))
+
+	dbCheck, database := checkDatabase(db.DefaultPath())
+	checks = append(checks, dbCheck)
+	if database != nil {
+		defer database.Close()
+		projects, err := database.ListProjects()
+		if err != nil {
+			checks = append(checks, doctorCheck{Category: "projects", Name: "projects", Status: doctorFail, Message: "could not list projects: " + err.Error()})
+		}
+		checks = append(checks, checkAnthropicKey(ctx, database))
+		checks = append(checks, checkExecutors(database, projects)...)
+		checks = append(checks, checkProjectPaths(projects)...)
+		checks = append(checks, checkDiskSpace(projects)...)
+	}
+
+	checks = append(checks, checkGitHubAuth(ctx)...)
+	return checks
+}
+
+// doctorFailed reports whether the checks should make 'ty doctor' exit
+// non-zero.
+func doctorFailed(checks []doctorCheck, strict bool) bool {
+	for _, c := range checks {
+		if c.Status == doctorFail || (strict && c.Status == doctorWarn) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkTmux() doctorCheck {
+	c := doctorCheck{Category: "tmux", Name: "tmux"}
+	path, err := exec.LookPath("tmux")
+	if err != nil {
+		c.Status = doctorFail
+		c.Message = "tmux not found on PATH"
+		c.Detail = "Tasks run in tmux windows; install it (e.g. 'brew install tmux' or 'apt install tmux')."
+		return c
+	}
+	out, err := exec.Command(path, "-V").Output()
+	if err != nil {
+		c.Status = doctorWarn
+		c.Message = "tmux found at " + path + " but 'tmux -V' failed: " + err.Error()
+		return c
+	}
+	c.Status = doctorPass
+	c.Message = "tmux " + parseTmuxVersion(string(out))
+	return c
+}
+
+// parseTmuxVersion extracts the version from `tmux -V` output ("tmux 3.4").
+func parseTmuxVersion(out string) string {
+	out = strings.TrimSpace(out)
+	if v, ok := strings.CutPrefix(out, "tmux "); ok {
+		return v
+	}
+	return out
+}
+
+func checkDaemon(pidFile string) doctorCheck {
+	c := doctorCheck{Category: "daemon", Name: "daemon"}
+	pid, err := readPidFile(pidFile)
+	switch {
+	case err != nil:
+		c.Status = doctorWarn
+		c.Message = "daemon not running"
+		c.Detail = "It starts automatically when a task is queued; run 'ty daemon' to start it now."
+	case !processExists(pid):
+		c.Status = doctorWarn
+		c.Message = fmt.Sprintf("daemon not running (stale pid file for pid %d)", pid)
+		c.Detail = "Run 'ty daemon' to start it."
+	default:
+		c.Status = doctorPass
+		c.Message = fmt.Sprintf("daemon running (pid %d)", pid)
+	}
+	return c
+}
+
+// checkDatabase opens the database at path and compares its schema with the
+// one this binary expects. The returned database, when non-nil, is open for
+// the remaining checks; it is nil when pending migrations leave the schema
+// too old for them.
+func checkDatabase(path string) (doctorCheck, *db.DB) {
+	c := doctorCheck{Category: "database", Name: "database"}
+	database, err := db.OpenWithPendingMigrations(path)
+	if err != nil {
+		c.Status = doctorFail
+		c.Message = "cannot open " + path + ": " + err.Error()
+		return c, nil
+	}
+	version, err := database.SchemaVersion()
+	if err != nil {
+		c.Status = doctorFail
+		c.Message = "cannot read schema version of " + path + ": " + err.Error()
+		return c, database
+	}
+	c.Status, c.Message, c.Detail = schemaStatus(version, db.LatestSchemaVersion())
+	c.Message += " (" + path + ")"
+	if version < db.LatestSchemaVersion() {
+		database.Close()
+		return c, nil
+	}
+	return c, database
+}
+
+// schemaStatus compares a database's schema version with the latest one this
+// binary knows.
+func schemaStatus(version, latest int) (status, message, detail string) {
+	switch {
+	case version == latest:
+		return doctorPass, fmt.Sprintf("schema v%d is current", version), ""
+	case version < latest:
+		return doctorFail, fmt.Sprintf("schema v%d is behind v%d", version, latest),
+			"ty won't open the database until the pending migrations are applied with 'ty migrate up'."
+	default:
+		return doctorFail, fmt.Sprintf("schema v%d is newer than this ty supports (v%d)", version, latest),
+			"Upgrade ty with 'ty upgrade'."
+	}
+}
+
+// checkAnthropicKey validates the key used for title generation and ghost
+// text. The key is optional, so a missing one only warns; one the API
+// rejects fails.
+func checkAnthropicKey(ctx context.Context, database *db.DB) doctorCheck {
+	c := doctorCheck{Category: "anthropic", Name: "anthropic_api_key"}
+	key, _ := database.GetSetting("anthropic_api_key")
+	source := "setting"
+	if key == "" {
+		if storage, _ := database.SecretSettingStorage("anthropic_api_key"); storage != "" {
+			c.Status = doctorWarn
+			c.Message = "anthropic_api_key is stored in " + storage + " but cannot be read"
+			return c
+		}
+		key = os.Getenv("ANTHROPIC_API_KEY")
+		source = "ANTHROPIC_API_KEY"
+	}
+	if key == "" {
+		c.Status = doctorWarn
+		c.Message = "no Anthropic API key configured"
+		c.Detail = "Optional: set one with 'ty settings set anthropic_api_key <key>' for generated titles and ghost text."
+		return c
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", anthropicModelsURL, nil)
+	if err != nil {
+		c.Status = doctorWarn
+		c.Message = "could not check Anthropic API key: " + err.Error()
+		return c
+	}
+	req.Header.Set("x-api-key", key)
+	req.Header.Set("anthropic-version", "2023-06-01")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.Status = doctorWarn
+		c.Message = "could not reach the Anthropic API to check the key: " + err.Error()
+		return c
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		c.Status = doctorPass
+		c.Message = "Anthropic API key accepted (" + source + ")"
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		c.Status = doctorFail
+		c.Message = fmt.Sprintf("Anthropic API key rejected (%s, HTTP %d)", source, resp.StatusCode)
+		c.Detail = "The key is invalid or revoked; replace it with 'ty settings set anthropic_api_key <key>'."
+	default:
+		c.Status = doctorWarn
+		c.Message = fmt.Sprintf("could not check Anthropic API key (HTTP %d)", resp.StatusCode)
+	}
+	return c
+}
+
+// checkExecutors checks that the default executor, and every executor a
+// project defaults to, is installed.
+func checkExecutors(database *db.DB, projects []*db.Project) []doctorCheck {
+	users := map[string][]string{db.DefaultExecutor(): nil}
+	for _, p := range projects {
+		if p.Executor != "" {
+			users[p.Executor] = append(users[p.Executor], p.Name)
+		}
+	}
+	names := make([]string, 0, len(users))
+	for name := range users {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ex := executor.New(database, config.New(database))
+	var checks []doctorCheck
+	for _, name := range names {
+		c := doctorCheck{Category: "executor", Name: name}
+		usedBy := "default executor"
+		if len(users[name]) > 0 {
+			usedBy = "used by " + strings.Join(users[name], ", ")
+			if name == db.DefaultExecutor() {
+				usedBy = "default executor, " + usedBy
+			}
+		}
+		switch te := ex.GetExecutor(name); {
+		case te == nil:
+			c.Status = doctorFail
+			c.Message = fmt.Sprintf("unknown executor %q (%s)", name, usedBy)
+			c.Detail = "Valid executors: " + strings.Join(executor.ExecutorNames(), ", ")
+		case !te.IsAvailable():
+			c.Status = doctorFail
+			c.Message = fmt.Sprintf("%s not installed (%s)", name, usedBy)
+			c.Detail = "Install the " + name + " CLI and make sure it is on PATH."
+		default:
+			c.Status = doctorPass
+			c.Message = fmt.Sprintf("%s installed (%s)", name, usedBy)
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// checkProjectPaths checks that each project's directory exists and is
+// writable, since worktrees are created inside it.
+func checkProjectPaths(projects []*db.Project) []doctorCheck {
+	var checks []doctorCheck
+	for _, p := range projects {
+		if p.Path == "" {
+			continue
+		}
+		c := doctorCheck{Category: "project", Name: p.Name}
+		info, err := os.Stat(p.Path)
+		switch {
+		case err != nil:
+			c.Status = doctorFail
+			c.Message = p.Name + ": path does not exist: " + p.Path
+			c.Detail = fmt.Sprintf("Fix it with 'ty projects update %s --path <dir>' or delete the project.", p.Name)
+		case !info.IsDir():
+			c.Status = doctorFail
+			c.Message = p.Name + ": path is not a directory: " + p.Path
+		case syscall.Access(p.Path, 2) != nil: // W_OK
+			c.Status = doctorFail
+			c.Message = p.Name + ": path is not writable: " + p.Path
+			c.Detail = "Worktrees are created under " + filepath.Join(p.Path, ".task-worktrees") + "."
+		default:
+			c.Status = doctorPass
+			c.Message = p.Name + ": " + p.Path
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// checkDiskSpace reports free space on each filesystem holding a project's
+// worktrees. Projects sharing a filesystem are reported once.
+func checkDiskSpace(projects []*db.Project) []doctorCheck {
+	var checks []doctorCheck
+	seen := map[uint64]bool{}
+	for _, p := range projects {
+		if p.Path == "" {
+			continue
+		}
+		var st syscall.Statfs_t
+		if err := syscall.Statfs(p.Path, &st); err != nil {
+			continue // a missing path is already reported by checkProjectPaths
+		}
+		var dev uint64
+		if info, err := os.Stat(p.Path); err == nil {
+			if sys, ok := info.Sys().(*syscall.Stat_t); ok {
+				dev = uint64(sys.Dev)
+			}
+		}
+		if seen[dev] {
+			continue
+		}
+		seen[dev] = true
+		checks = append(checks, diskSpaceCheck(p.Path, uint64(st.Bavail)*uint64(st.Bsize)))
+	}
+	return checks
+}
+
+// diskSpaceCheck grades free bytes on the filesystem holding path.
+func diskSpaceCheck(path string, free uint64) doctorCheck {
+	c := doctorCheck{Category: "disk", Name: path, Message: formatAttachmentSize(int64(free)) + " free on " + path}
+	switch {
+	case free < doctorDiskFailBytes:
+		c.Status = doctorFail
+		c.Detail = "Free up space; 'ty worktrees cleanup' removes worktrees of finished tasks."
+	case free < doctorDiskWarnBytes:
+		c.Status = doctorWarn
+		c.Detail = "Free up space; 'ty worktrees cleanup' removes worktrees of finished tasks."
+	default:
+		c.Status = doctorPass
+	}
+	return c
+}
+
+// checkGitHubAuth turns the gh authentication findings into doctor checks.
+func checkGitHubAuth(ctx context.Context) []doctorCheck {
+	status := github.CheckAuth(ctx)
+	var checks []doctorCheck
+	if status.Err != nil {
+		checks = append(checks, doctorCheck{Category: "github", Name: "gh", Status: doctorWarn, Message: "could not fully probe gh: " + status.Err.Error()})
+	}
+	for _, f := range status.Findings() {
+		c := doctorCheck{Category: "github", Name: "gh", Message: f.Message, Detail: f.Detail}
+		switch f.Severity {
+		case github.SeverityOK:
+			c.Status = doctorPass
+		case github.SeverityWarn:
+			c.Status = doctorWarn
+		case github.SeverityError:
+			c.Status = doctorFail
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+func printDoctorReport(checks []doctorCheck) {
+	fmt.Println(boldStyle.Render("TaskYou Doctor"))
+	category := ""
+	githubProblems := false
+	for _, c := range checks {
+		if c.Category != category {
+			category = c.Category
+			fmt.Println()
+			fmt.Println(dimStyle.Render(doctorCategoryTitle(category)))
+		}
+		var icon, msg string
+		switch c.Status {
+		case doctorPass:
+			icon = successStyle.Render("✓")
+			msg = c.Message
+		case doctorWarn:
+			icon = warnStyle.Render("⚠")
+			msg = warnStyle.Render(c.Message)
+		default:
+			icon = errorStyle.Render("✗")
+			msg = errorStyle.Render(c.Message)
+		}
+		fmt.Printf("%s %s\n", icon, msg)
+		if c.Detail != "" {
+			fmt.Println(dimStyle.Render("    " + c.Detail))
+		}
+		if c.Category == "github" && c.Status != doctorPass {
+			githubProblems = true
+		}
+	}
+
+	fmt.Println()
+	if githubProblems {
+		fmt.Println(dimStyle.Render("Tip: provision this server with its own GitHub App installation token,"))
+		fmt.Println(dimStyle.Render("mirroring the offerlab-devs[bot] pattern, for an independent rate-limit bucket."))
+	}
+	var warns, fails int
+	for _, c := range checks {
+		switch c.Status {
+		case doctorWarn:
+			warns++
+		case doctorFail:
+			fails++
+		}
+	}
+	switch {
+	case fails > 0:
+		fmt.Println(errorStyle.Render(fmt.Sprintf("%d check(s) failed, %d warning(s).", fails, warns)))
+	case warns > 0:
+		fmt.Println(warnStyle.Render(fmt.Sprintf("No failures, %d warning(s).", warns)))
+	default:
+		outln(successStyle.Render("All checks passed."))
+	}
+}
+
+func doctorCategoryTitle(category string) string {
+	switch category {
+	case "tmux":
+		return "tmux"
+	case "daemon":
+		return "Daemon"
+	case "database":
+		return "Database"
+	case "anthropic":
+		return "Anthropic API"
+	case "executor":
+		return "Executors"
+	case "project", "projects":
+		return "Projects"
+	case "disk":
+		return "Disk space"
+	case "github":
+		return "GitHub authentication"
+	}
+	return category
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestParseTmuxVersion(t *testing.T) {
+	for in, want := range map[string]string{
+		"tmux 3.4\n":      "3.4",
+		"tmux next-3.5\n": "next-3.5",
+		"3.3a":            "3.3a",
+	} {
+		if got := parseTmuxVersion(in); got != want {
+			t.Errorf("parseTmuxVersion(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSchemaStatus(t *testing.T) {
+	if status, _, _ := schemaStatus(4, 4); status != doctorPass {
+		t.Errorf("current schema: status = %s, want pass", status)
+	}
+	if status, _, detail := schemaStatus(3, 4); status != doctorFail || detail == "" {
+		t.Errorf("behind schema: status = %s detail = %q, want fail with a hint", status, detail)
+	}
+	if status, _, _ := schemaStatus(5, 4); status != doctorFail {
+		t.Errorf("newer schema: status = %s, want fail", status)
+	}
+}
+
+func TestDoctorFailed(t *testing.T) {
+	warnOnly := []doctorCheck{{Status: doctorPass}, {Status: doctorWarn}}
+	if doctorFailed(warnOnly, false) {
+		t.Error("warnings alone should not fail without --strict")
+	}
+	if !doctorFailed(warnOnly, true) {
+		t.Error("warnings should fail under --strict")
+	}
+	if !doctorFailed([]doctorCheck{{Status: doctorPass}, {Status: doctorFail}}, false) {
+		t.Error("a failed check should fail")
+	}
+}
+
+func TestDiskSpaceCheck(t *testing.T) {
+	if c := diskSpaceCheck("/p", 10<<30); c.Status != doctorPass {
+		t.Errorf("10 GiB free: status = %s, want pass", c.Status)
+	}
+	if c := diskSpaceCheck("/p", 2<<30); c.Status != doctorWarn {
+		t.Errorf("2 GiB free: status = %s, want warn", c.Status)
+	}
+	if c := diskSpaceCheck("/p", 100<<20); c.Status != doctorFail {
+		t.Errorf("100 MiB free: status = %s, want fail", c.Status)
+	}
+}
+
+func TestCheckDaemon(t *testing.T) {
+	dir := t.TempDir()
+
+	if c := checkDaemon(filepath.Join(dir, "missing.pid")); c.Status != doctorWarn {
+		t.Errorf("no pid file: status = %s, want warn", c.Status)
+	}
+
+	running := filepath.Join(dir, "running.pid")
+	if err := os.WriteFile(running, []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if c := checkDaemon(running); c.Status != doctorPass {
+		t.Errorf("live pid: status = %s (%s), want pass", c.Status, c.Message)
+	}
+}
+
+func TestCheckProjectPaths(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	checks := checkProjectPaths([]*db.Project{
+		{Name: "ok", Path: dir},
+		{Name: "gone", Path: filepath.Join(dir, "gone")},
+		{Name: "file", Path: file},
+		{Name: "nopath"},
+	})
+	want := map[string]string{"ok": doctorPass, "gone": doctorFail, "file": doctorFail}
+	if len(checks) != len(want) {
+		t.Fatalf("got %d checks, want %d: %+v", len(checks), len(want), checks)
+	}
+	for _, c := range checks {
+		if c.Status != want[c.Name] {
+			t.Errorf("project %s: status = %s, want %s", c.Name, c.Status, want[c.Name])
+		}
+	}
+}
+
+func TestCheckAnthropicKey(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+	t.Setenv("ANTHROPIC_API_KEY", "")
+
+	if c := checkAnthropicKey(context.Background(), database); c.Status != doctorWarn {
+		t.Errorf("no key: status = %s, want warn", c.Status)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-api-key") != "good" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+	old := anthropicModelsURL
+	anthropicModelsURL = server.URL
+	defer func() { anthropicModelsURL = old }()
+
+	t.Setenv("ANTHROPIC_API_KEY", "good")
+	if c := checkAnthropicKey(context.Background(), database); c.Status != doctorPass {
+		t.Errorf("accepted key: status = %s (%s), want pass", c.Status, c.Message)
+	}
+
+	if err := database.SetSetting("anthropic_api_key", "stale"); err != nil {
+		t.Fatal(err)
+	}
+	if c := checkAnthropicKey(context.Background(), database); c.Status != doctorFail {
+		t.Errorf("rejected key: status = %s (%s), want fail", c.Status, c.Message)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/spf13/cobra"
+)
+
+func newDuplicateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "duplicate <task-id>",
+		Aliases:           []string{"dup"},
+		Short:             "Create a fresh backlog copy of a task",
+		ValidArgsFunction: completeTaskIDs,
+		Long: `Creates a new backlog task with the same title, body, type, executor,
+project and tags as an existing one, so you can try a different approach
+without touching the original or its history.
+
+The copy starts clean: no worktree, branch, port, agent session or
+timestamps, and none of the original's logs. Its title gets a " (copy)"
+suffix unless --title is given.
+
+With --link, each task gets a note pointing at the other ("Duplicated from
+#42" is pinned on the copy), so 'ty show' correlates them.
+
+Examples:
+  ty duplicate 42
+  ty duplicate 42 --title "Try the streaming parser instead" --execute
+  ty duplicate 42 --link --json`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			title, _ := cmd.Flags().GetString("title")
+			execute, _ := cmd.Flags().GetBool("execute")
+			link, _ := cmd.Flags().GetBool("link")
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			taskID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid task ID: "+args[0]))
+				os.Exit(1)
+			}
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			source, err := database.GetTask(taskID)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if source == nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Task #%d not found", taskID)))
+				os.Exit(1)
+			}
+
+			clone, err := duplicateTask(database, source, title, link)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if execute {
+				if err := database.UpdateTaskStatus(clone.ID, db.StatusQueued); err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error queueing task: "+err.Error()))
+					os.Exit(1)
+				}
+				clone.Status = db.StatusQueued
+			}
+
+			if outputJSON {
+				jsonBytes, _ := json.Marshal(map[string]interface{}{
+					"id":        clone.ID,
+					"source_id": source.ID,
+					"title":     clone.Title,
+					"project":   clone.Project,
+					"status":    clone.Status,
+					"linked":    link,
+				})
+				fmt.Println(string(jsonBytes))
+				return
+			}
+			outln(successStyle.Render(fmt.Sprintf("Duplicated task #%d as #%d: %s", source.ID, clone.ID, clone.Title)))
+			if execute {
+				outln(successStyle.Render(fmt.Sprintf("Queued task #%d for execution", clone.ID)))
+			}
+		},
+	}
+	cmd.Flags().String("title", "", `Title for the copy (default: the original's title + " (copy)")`)
+	cmd.Flags().BoolP("execute", "x", false, "Queue the copy for execution immediately")
+	cmd.Flags().Bool("link", false, "Add notes linking the copy and the original")
+	cmd.Flags().Bool("json", false, "Output in JSON format")
+	return cmd
+}
+
+// duplicateTask creates a backlog copy of source carrying its content but none
+// of its execution state. An empty title means source's title plus " (copy)".
+func duplicateTask(database *db.DB, source *db.Task, title string, link bool) (*db.Task, error) {
+	if title == "" {
+		title = source.Title + " (copy)"
+	}
+	clone := &db.Task{
+		Title:        title,
+		Body:         source.Body,
+		Type:         source.Type,
+		Executor:     source.Executor,
+		ExecutorArgs: source.ExecutorArgs,
+		Project:      source.Project,
+		Tags:         source.Tags,
+		Status:       db.StatusBacklog,
+	}
+	if err := database.CreateTask(clone); err != nil {
+		return nil, fmt.Errorf("create copy: %w", err)
+	}
+
+	if link {
+		if err := database.AppendTaskLog(clone.ID, db.PinnedNoteLineType, fmt.Sprintf("Duplicated from #%d: %s", source.ID, source.Title)); err != nil {
+			return clone, fmt.Errorf("created #%d but could not link it: %w", clone.ID, err)
+		}
+		if err := database.AppendTaskLog(source.ID, db.NoteLineType, fmt.Sprintf("Duplicated as #%d: %s", clone.ID, clone.Title)); err != nil {
+			return clone, fmt.Errorf("created #%d but could not link it: %w", clone.ID, err)
+		}
+	}
+	return clone, nil
+}
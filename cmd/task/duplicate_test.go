@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestDuplicateTask(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	source := &db.Task{
+		Title: "Parse the feed", Body: "Use the SAX parser.", Type: "code", Executor: "codex",
+		Project: "personal", Tags: "parser", Status: db.StatusBlocked, Pinned: true,
+	}
+	if err := database.CreateTask(source); err != nil {
+		t.Fatal(err)
+	}
+	source.WorktreePath = "/tmp/wt/task-1"
+	source.BranchName = "task/1-parse-the-feed"
+	if err := database.UpdateTask(source); err != nil {
+		t.Fatal(err)
+	}
+
+	clone, err := duplicateTask(database, source, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := database.GetTask(clone.ID)
+	if got.Title != "Parse the feed (copy)" || got.Body != source.Body || got.Type != "code" ||
+		got.Executor != "codex" || got.Project != "personal" || got.Tags != "parser" {
+		t.Errorf("content not copied: %+v", got)
+	}
+	if got.Status != db.StatusBacklog || got.WorktreePath != "" || got.BranchName != "" || got.StartedAt != nil {
+		t.Errorf("execution state carried over: status=%s worktree=%q branch=%q", got.Status, got.WorktreePath, got.BranchName)
+	}
+	if logs, _ := database.GetTaskLogs(clone.ID, 10); len(logs) != 0 {
+		t.Errorf("unlinked copy has logs: %+v", logs)
+	}
+
+	linked, err := duplicateTask(database, source, "Try the DOM parser", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if linked.Title != "Try the DOM parser" {
+		t.Errorf("title override ignored: %q", linked.Title)
+	}
+	logs, _ := database.GetTaskLogs(linked.ID, 10)
+	if len(logs) != 1 || logs[0].LineType != db.PinnedNoteLineType || logs[0].Content != "Duplicated from #1: Parse the feed" {
+		t.Errorf("copy link note = %+v", logs)
+	}
+	logs, _ = database.GetTaskLogs(source.ID, 10)
+	if len(logs) != 1 || logs[0].Content != "Duplicated as #3: Try the DOM parser" {
+		t.Errorf("original link note = %+v", logs)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bborn/workflow/internal/events"
+	"github.com/spf13/cobra"
+)
+
+func newEventsTypesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "types",
+		Short: "List every event type with its description and metadata",
+		Long: `Lists the event types ty emits, what each one means, where it shows up and
+the metadata keys it carries. This is the contract for integrations: match
+on these strings, and check schema_version (currently ` + fmt.Sprint(events.SchemaVersion) + `) in the entries
+of 'ty events list --json', 'ty events watch' and the HTTP event stream.
+
+Sources:
+  log   recorded in the event log: ty events list/watch and /api/events/stream
+  hook  runs hook scripts, which get the metadata in their stdin payload
+
+Examples:
+  ty events types
+  ty events types --json | jq '.types[].type'`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			if outputJSON {
+				data, _ := json.MarshalIndent(map[string]interface{}{
+					"schema_version": events.SchemaVersion,
+					"types":          events.Types(),
+				}, "", "  ")
+				fmt.Println(string(data))
+				return
+			}
+			printEventTypes(events.Types())
+		},
+	}
+	cmd.Flags().Bool("json", false, "Output in JSON format")
+	return cmd
+}
+
+func printEventTypes(types []events.TypeInfo) {
+	fmt.Println(dimStyle.Render(fmt.Sprintf("%-22s %-10s %s", "TYPE", "SOURCES", "DESCRIPTION")))
+	for _, t := range types {
+		fmt.Printf("%s %-10s %s\n", boldStyle.Render(fmt.Sprintf("%-22s", t.Type)), strings.Join(t.Sources, ","), t.Description)
+		for _, m := range t.Metadata {
+			fmt.Println(dimStyle.Render(fmt.Sprintf("%-33s metadata.%s (%s): %s", "", m.Name, m.Type, m.Description)))
+		}
+	}
+	fmt.Println()
+	fmt.Println(dimStyle.Render(fmt.Sprintf("Event schema version %d", events.SchemaVersion)))
+}
+
+// completeEventTypes completes the --type flag of ty events list and watch.
+func completeEventTypes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var names []string
+	for _, t := range events.Types() {
+		names = append(names, t.Type)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/events"
+	"github.com/spf13/cobra"
+)
+
+// eventsWatchPollInterval is how often the database fallback checks the
+// event_log for new rows.
+const eventsWatchPollInterval = 1 * time.Second
+
+// eventsWatchReconnectInterval is how often the database fallback retries the
+// daemon's event stream.
+const eventsWatchReconnectInterval = 5 * time.Second
+
+func newEventsWatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Stream new events as JSON lines",
+		Long: `Print each new event as one JSON object per line, as it happens.
+
+Events come from the daemon's HTTP event stream. When the daemon isn't
+reachable, ty events watch polls the event log in the database instead and
+switches back to the stream once the daemon is up. Only events logged after
+the command starts are printed.
+
+Examples:
+  ty events watch                                 # Everything
+  ty events watch --type task.blocked             # Only blocked tasks
+  ty events watch --task 42                       # Only task #42
+  ty events watch --type task.completed | jq .message`,
+		Run: func(cmd *cobra.Command, args []string) {
+			types, _ := cmd.Flags().GetStringArray("type")
+			taskID, _ := cmd.Flags().GetInt64("task")
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			w := &eventsWatcher{
+				db:        database,
+				streamURL: fmt.Sprintf("http://127.0.0.1:%d/api/events/stream", httpAPIPort(database)),
+				filter:    eventFilter{types: types, taskID: taskID},
+				out:       os.Stdout,
+			}
+			if err := w.run(ctx); err != nil && ctx.Err() == nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().StringArray("type", nil, "Only show events of this type (repeatable)")
+	cmd.Flags().Int64("task", 0, "Only show events for this task ID")
+	cmd.RegisterFlagCompletionFunc("type", completeEventTypes)
+	cmd.RegisterFlagCompletionFunc("task", completeTaskIDs)
+	return cmd
+}
+
+// eventFilter is the client-side --type/--task filter for ty events watch.
+type eventFilter struct {
+	types  []string
+	taskID int64
+}
+
+func (f eventFilter) match(e db.LoggedEvent) bool {
+	if f.taskID != 0 && e.TaskID != f.taskID {
+		return false
+	}
+	if len(f.types) == 0 {
+		return true
+	}
+	for _, t := range f.types {
+		if t == e.EventType {
+			return true
+		}
+	}
+	return false
+}
+
+// eventsWatcher follows the event log, preferring the daemon's SSE stream and
+// falling back to polling the database.
+type eventsWatcher struct {
+	db        *db.DB
+	streamURL string
+	filter    eventFilter
+	out       io.Writer
+
+	// lastID is the newest event already seen, so switching between the
+	// stream and the database never repeats or drops events.
+	lastID  int64
+	polling bool
+}
+
+func (w *eventsWatcher) run(ctx context.Context) error {
+	lastID, err := w.db.LatestEventID()
+	if err != nil {
+		return fmt.Errorf("read event log: %w", err)
+	}
+	w.lastID = lastID
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{Timeout: 2 * time.Second}).DialContext,
+		},
+	}
+	for ctx.Err() == nil {
+		connected, err := w.stream(ctx, client)
+		if connected {
+			// The stream was up and ended (daemon restart, server write
+			// timeout): reconnect from lastID after a short pause.
+			select {
+			case <-ctx.Done():
+			case <-time.After(eventsWatchPollInterval):
+			}
+			continue
+		}
+		if err != nil && !w.polling {
+			infoln(dimStyle.Render("Daemon event stream not reachable; polling the database"))
+			w.polling = true
+		}
+		if err := w.poll(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stream reads events from the daemon until the stream ends. connected
+// reports whether the daemon answered at all.
+func (w *eventsWatcher) stream(ctx context.Context, client *http.Client) (connected bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s?after=%d", w.streamURL, w.lastID), nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	// An older daemon without the stream endpoint may answer with the web
+	// UI's HTML instead of a 404, so check the content type too.
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return false, fmt.Errorf("event stream: %s", resp.Status)
+	}
+	if w.polling {
+		infoln(dimStyle.Render("Connected to daemon event stream"))
+		w.polling = false
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	var eventName, data string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if eventName == "event" && data != "" {
+				var e db.LoggedEvent
+				if err := json.Unmarshal([]byte(data), &e); err == nil {
+					if err := w.emit(e); err != nil {
+						return true, err
+					}
+				}
+			}
+			eventName, data = "", ""
+		case strings.HasPrefix(line, "event:"):
+			eventName = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		}
+	}
+	return true, scanner.Err()
+}
+
+// poll reads new rows straight from the event_log until it is time to retry
+// the daemon's stream.
+func (w *eventsWatcher) poll(ctx context.Context) error {
+	deadline := time.Now().Add(eventsWatchReconnectInterval)
+	ticker := time.NewTicker(eventsWatchPollInterval)
+	defer ticker.Stop()
+	for {
+		events, err := w.db.ListEventsAfter(w.lastID)
+		if err != nil {
+			return fmt.Errorf("read event log: %w", err)
+		}
+		for _, e := range events {
+			if err := w.emit(e); err != nil {
+				return err
+			}
+		}
+		if time.Now().After(deadline) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// emit prints e as a JSON line if it passes the filter. out is unbuffered, so
+// each line reaches a downstream `| jq` as soon as it is written.
+func (w *eventsWatcher) emit(e db.LoggedEvent) error {
+	if e.ID <= w.lastID {
+		return nil
+	}
+	w.lastID = e.ID
+	if !w.filter.match(e) {
+		return nil
+	}
+	line, err := json.Marshal(events.Versioned(e))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w.out, "%s\n", line)
+	return err
+}
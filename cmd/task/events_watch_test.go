@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestEventFilterMatch(t *testing.T) {
+	e := db.LoggedEvent{EventType: "task.blocked", TaskID: 42}
+	tests := []struct {
+		name   string
+		filter eventFilter
+		want   bool
+	}{
+		{"no filter", eventFilter{}, true},
+		{"matching type", eventFilter{types: []string{"task.completed", "task.blocked"}}, true},
+		{"other type", eventFilter{types: []string{"task.completed"}}, false},
+		{"matching task", eventFilter{taskID: 42}, true},
+		{"other task", eventFilter{taskID: 7}, false},
+		{"type and other task", eventFilter{types: []string{"task.blocked"}, taskID: 7}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.match(e); got != tt.want {
+				t.Errorf("match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func decodeEventLines(t *testing.T, out string) []db.LoggedEvent {
+	t.Helper()
+	var events []db.LoggedEvent
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line == "" {
+			continue
+		}
+		var e db.LoggedEvent
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("output line is not JSON: %q: %v", line, err)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestEventsWatchPollFallback(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	before := &db.Task{Title: "Before watch", Status: db.StatusBacklog}
+	if err := database.CreateTask(before); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	lastID, err := database.LatestEventID()
+	if err != nil {
+		t.Fatalf("LatestEventID: %v", err)
+	}
+	watched := &db.Task{Title: "Watched", Status: db.StatusBacklog}
+	if err := database.CreateTask(watched); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	other := &db.Task{Title: "Other", Status: db.StatusBacklog}
+	if err := database.CreateTask(other); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+
+	var out bytes.Buffer
+	w := &eventsWatcher{db: database, filter: eventFilter{taskID: watched.ID}, out: &out, lastID: lastID}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := w.poll(ctx); err != nil {
+		t.Fatalf("poll() error = %v", err)
+	}
+
+	events := decodeEventLines(t, out.String())
+	if len(events) != 1 || events[0].TaskID != watched.ID || events[0].EventType != "task.created" {
+		t.Fatalf("expected only task #%d's creation, got %+v", watched.ID, events)
+	}
+	if latest, _ := database.LatestEventID(); w.lastID != latest {
+		t.Errorf("lastID = %d, want %d (filtered events still advance the cursor)", w.lastID, latest)
+	}
+}
+
+func TestEventsWatchStream(t *testing.T) {
+	var gotAfter string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAfter = r.URL.Query().Get("after")
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: heartbeat\ndata: {}\n\n")
+		fmt.Fprint(w, "id: 5\nevent: event\ndata: {\"id\":5,\"event_type\":\"task.created\",\"task_id\":1}\n\n")
+		fmt.Fprint(w, "id: 6\nevent: event\ndata: {\"id\":6,\"event_type\":\"task.blocked\",\"task_id\":1,\"metadata\":\"{\\\"question\\\":\\\"ok?\\\"}\"}\n\n")
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	w := &eventsWatcher{streamURL: srv.URL, filter: eventFilter{types: []string{"task.blocked"}}, out: &out, lastID: 4}
+	connected, err := w.stream(context.Background(), srv.Client())
+	if !connected || err != nil {
+		t.Fatalf("stream() = %v, %v; want connected", connected, err)
+	}
+	if gotAfter != "4" {
+		t.Errorf("stream requested after=%q, want 4", gotAfter)
+	}
+
+	events := decodeEventLines(t, out.String())
+	if len(events) != 1 || events[0].ID != 6 || events[0].Metadata != `{"question":"ok?"}` {
+		t.Fatalf("expected only the task.blocked event, got %+v", events)
+	}
+	if w.lastID != 6 {
+		t.Errorf("lastID = %d, want 6", w.lastID)
+	}
+}
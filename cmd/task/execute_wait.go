@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+// Exit codes for `ty execute --wait`.
+const (
+	waitExitDone    = 0
+	waitExitFailed  = 1 // blocked, archived without finishing, or deleted
+	waitExitTimeout = 2
+)
+
+// waitResult is the outcome of `ty execute --wait`, and its --json output.
+type waitResult struct {
+	ID       int64  `json:"id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Message  string `json:"message,omitempty"`
+	TimedOut bool   `json:"timed_out,omitempty"`
+	Elapsed  string `json:"elapsed"`
+}
+
+func (r waitResult) exitCode() int {
+	switch {
+	case r.TimedOut:
+		return waitExitTimeout
+	case r.Status == db.StatusDone:
+		return waitExitDone
+	}
+	return waitExitFailed
+}
+
+// waitTerminal reports whether --wait stops at status.
+func waitTerminal(status string) bool {
+	return status == db.StatusDone || status == db.StatusBlocked || status == db.StatusArchived
+}
+
+// waitForTask polls taskID until it reaches done, blocked, or archived, is
+// deleted, or ctx ends. progress, if set, is called on every poll.
+func waitForTask(ctx context.Context, database *db.DB, taskID int64, interval time.Duration, progress func(status string)) (waitResult, error) {
+	start := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	res := waitResult{ID: taskID}
+	for {
+		task, err := database.GetTask(taskID)
+		if err != nil {
+			return res, err
+		}
+		if task == nil {
+			res.Status = "deleted"
+			res.Elapsed = time.Since(start).Round(time.Second).String()
+			return res, nil
+		}
+		res.Title, res.Status = task.Title, task.Status
+		if waitTerminal(task.Status) {
+			res.Elapsed = time.Since(start).Round(time.Second).String()
+			if task.Status != db.StatusDone {
+				res.Message = waitFailureMessage(database, taskID)
+			}
+			return res, nil
+		}
+		if progress != nil {
+			progress(task.Status)
+		}
+
+		select {
+		case <-ctx.Done():
+			res.TimedOut = ctx.Err() == context.DeadlineExceeded
+			res.Elapsed = time.Since(start).Round(time.Second).String()
+			return res, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitFailureMessage explains why a task stopped without finishing: the
+// question it is waiting on, or else its most recent error line.
+func waitFailureMessage(database *db.DB, taskID int64) string {
+	if q, _ := database.GetLastQuestion(taskID); q != "" {
+		return q
+	}
+	logs, _ := database.GetTaskLogs(taskID, 50)
+	for _, l := range logs {
+		if l.LineType == "error" {
+			return l.Content
+		}
+	}
+	return ""
+}
+
+// waitSpinner draws a one-line spinner on w when it is a terminal, and
+// otherwise prints a line whenever the status changes.
+type waitSpinner struct {
+	w     io.Writer
+	tty   bool
+	frame int
+	last  string
+	start time.Time
+}
+
+var waitSpinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+func newWaitSpinner(f *os.File) *waitSpinner {
+	tty := false
+	if fi, err := f.Stat(); err == nil {
+		tty = fi.Mode()&os.ModeCharDevice != 0
+	}
+	return &waitSpinner{w: f, tty: tty, start: time.Now()}
+}
+
+func (s *waitSpinner) update(taskID int64, status string) {
+	if !s.tty {
+		if status != s.last {
+			fmt.Fprintf(s.w, "Task #%d: %s\n", taskID, status)
+		}
+		s.last = status
+		return
+	}
+	s.frame = (s.frame + 1) % len(waitSpinnerFrames)
+	elapsed := time.Since(s.start).Round(time.Second)
+	fmt.Fprintf(s.w, "\r\033[K%s Task #%d: %s %s", waitSpinnerFrames[s.frame], taskID, status, dimStyle.Render(elapsed.String()))
+	s.last = status
+}
+
+func (s *waitSpinner) clear() {
+	if s.tty {
+		fmt.Fprint(s.w, "\r\033[K")
+	}
+}
+
+// runExecuteWait blocks until taskID finishes and exits with a code that
+// reflects the outcome. timeout <= 0 waits indefinitely.
+func runExecuteWait(database *db.DB, taskID int64, timeout time.Duration, outputJSON bool) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var progress func(string)
+	var spinner *waitSpinner
+	if !outputJSON {
+		spinner = newWaitSpinner(os.Stderr)
+		progress = func(status string) { spinner.update(taskID, status) }
+	}
+
+	res, err := waitForTask(ctx, database, taskID, time.Second, progress)
+	if spinner != nil {
+		spinner.clear()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+		os.Exit(waitExitFailed)
+	}
+
+	if outputJSON {
+		data, _ := json.Marshal(res)
+		fmt.Println(string(data))
+		os.Exit(res.exitCode())
+	}
+
+	switch {
+	case res.TimedOut:
+		fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Timed out after %s; task #%d is still %s", res.Elapsed, taskID, res.Status)))
+	case ctx.Err() != nil:
+		infoln(dimStyle.Render(fmt.Sprintf("Stopped waiting; task #%d is still %s", taskID, res.Status)))
+	case res.Status == db.StatusDone:
+		outln(successStyle.Render(fmt.Sprintf("Task #%d is done (%s)", taskID, res.Elapsed)))
+	default:
+		fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Task #%d is %s", taskID, res.Status)))
+		if res.Message != "" {
+			fmt.Fprintln(os.Stderr, res.Message)
+		}
+	}
+	os.Exit(res.exitCode())
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestWaitForTask(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	ids := createTestTasks(t, database, 2)
+	if err := database.UpdateTaskStatus(ids[0], db.StatusQueued); err != nil {
+		t.Fatal(err)
+	}
+
+	// The task finishes after a few polls.
+	var polls int
+	res, err := waitForTask(context.Background(), database, ids[0], time.Millisecond, func(status string) {
+		if polls++; polls == 3 {
+			database.UpdateTaskStatus(ids[0], db.StatusDone)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != db.StatusDone || res.exitCode() != waitExitDone {
+		t.Errorf("expected done with exit 0, got %+v", res)
+	}
+
+	// A blocked task reports the question it is waiting on.
+	if err := database.AppendTaskLog(ids[1], "question", "Which database?"); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.UpdateTaskStatus(ids[1], db.StatusBlocked); err != nil {
+		t.Fatal(err)
+	}
+	res, err = waitForTask(context.Background(), database, ids[1], time.Millisecond, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != db.StatusBlocked || res.Message != "Which database?" || res.exitCode() != waitExitFailed {
+		t.Errorf("expected blocked with the question, got %+v", res)
+	}
+}
+
+func TestWaitForTaskTimeout(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	ids := createTestTasks(t, database, 1)
+	if err := database.UpdateTaskStatus(ids[0], db.StatusProcessing); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	res, err := waitForTask(ctx, database, ids[0], time.Millisecond, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.TimedOut || res.Status != db.StatusProcessing || res.exitCode() != waitExitTimeout {
+		t.Errorf("expected a timeout while processing, got %+v", res)
+	}
+}
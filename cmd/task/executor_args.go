@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/executor"
+	"github.com/spf13/cobra"
+)
+
+// parseExecutorArgs turns repeated --executor-arg key=value pairs into the
+// JSON stored on the task, after checking them against the allowlist of the
+// executor the task will run with: executorName, else project's default.
+func parseExecutorArgs(database *db.DB, pairs []string, executorName, project string) (string, error) {
+	if len(pairs) == 0 {
+		return "", nil
+	}
+	args := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return "", fmt.Errorf("invalid --executor-arg %q, expected key=value", pair)
+		}
+		args[key] = strings.TrimSpace(value)
+	}
+
+	if executorName == "" {
+		executorName = db.DefaultExecutor()
+		if project == "" {
+			project = "personal"
+		}
+		if p, err := database.GetProjectByName(project); err == nil && p != nil {
+			executorName = p.EffectiveExecutor()
+		}
+	}
+	if err := executor.ValidateExecutorArgs(executorName, args); err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// formatExecutorArgs renders executor args as "key=value" pairs in key order.
+func formatExecutorArgs(args map[string]string) string {
+	keys := make([]string, 0, len(args))
+	for k := range args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + args[k]
+	}
+	return strings.Join(parts, " ")
+}
+
+// completeExecutorArgKeys completes --executor-arg with the keys the --executor
+// given on the command line accepts (claude's when none is given).
+func completeExecutorArgKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	executorName, _ := cmd.Flags().GetString("executor")
+	if executorName == "" {
+		executorName = db.DefaultExecutor()
+	}
+	var keys []string
+	for _, k := range executor.ExecutorArgKeys(executorName) {
+		keys = append(keys, k+"=")
+	}
+	return keys, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
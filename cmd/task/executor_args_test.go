@@ -0,0 +1,39 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestParseExecutorArgs(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+	if err := database.CreateProject(&db.Project{Name: "codexy", Path: t.TempDir(), Executor: db.ExecutorCodex}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := parseExecutorArgs(database, []string{"model=o3", "reasoning-effort = high"}, "", "codexy")
+	if err != nil {
+		t.Fatalf("parseExecutorArgs: %v", err)
+	}
+	task := &db.Task{ExecutorArgs: got}
+	if args := task.ExecutorArgMap(); args["model"] != "o3" || args["reasoning-effort"] != "high" {
+		t.Errorf("parsed args = %v", args)
+	}
+	if s := formatExecutorArgs(task.ExecutorArgMap()); s != "model=o3 reasoning-effort=high" {
+		t.Errorf("formatExecutorArgs = %q", s)
+	}
+
+	// The project's executor is codex, but an explicit claude executor is checked instead.
+	if _, err := parseExecutorArgs(database, []string{"reasoning-effort=high"}, db.ExecutorClaude, "codexy"); err == nil || !strings.Contains(err.Error(), "for claude") {
+		t.Errorf("claude with codex key: err = %v", err)
+	}
+	if _, err := parseExecutorArgs(database, []string{"model"}, "", "codexy"); err == nil || !strings.Contains(err.Error(), "expected key=value") {
+		t.Errorf("missing value: err = %v", err)
+	}
+	if got, err := parseExecutorArgs(database, nil, "", ""); err != nil || got != "" {
+		t.Errorf("no args = %q, %v; want empty", got, err)
+	}
+}
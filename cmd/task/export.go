@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/spf13/cobra"
+)
+
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export tasks, projects, task types, and dependencies as JSON",
+		Long: `Writes the task database as a single versioned JSON document, for moving
+tasks to another machine with 'ty import'.
+
+Tasks keep their IDs. Machine-local state (worktrees, ports, Claude sessions,
+tmux windows) is not exported, and trashed tasks are left out. Task logs are
+only included with --include-logs.
+
+Examples:
+  ty export > tasks.json
+  ty export --file tasks.json --include-logs`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			file, _ := cmd.Flags().GetString("file")
+			includeLogs, _ := cmd.Flags().GetBool("include-logs")
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			exp, err := database.ExportData(includeLogs)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			data, err := json.MarshalIndent(exp, "", "  ")
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			data = append(data, '\n')
+
+			if file == "" {
+				os.Stdout.Write(data)
+				return
+			}
+			if err := os.WriteFile(file, data, 0600); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			outln(successStyle.Render(fmt.Sprintf("Exported %d tasks, %d projects to %s", len(exp.Tasks), len(exp.Projects), file)))
+		},
+	}
+	cmd.Flags().StringP("file", "f", "", "Write to this file instead of stdout")
+	cmd.Flags().Bool("include-logs", false, "Include task logs")
+	return cmd
+}
+
+func newImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import a 'ty export' document",
+		Long: `Recreates projects, task types, tasks, and dependencies from a 'ty export'
+document. Use - to read from stdin.
+
+Import is idempotent: existing projects and task types (by name) are kept, and
+tasks whose ID already exists are skipped unless --overwrite is given.
+
+Examples:
+  ty import tasks.json
+  ty import tasks.json --overwrite
+  ssh laptop ty export | ty import -`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			overwrite, _ := cmd.Flags().GetBool("overwrite")
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			exp, err := readExport(args[0])
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			res, err := database.ImportData(exp, overwrite)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			if outputJSON {
+				data, _ := json.Marshal(res)
+				fmt.Println(string(data))
+				return
+			}
+			outln(successStyle.Render(fmt.Sprintf("Imported %d tasks", res.TasksCreated+res.TasksUpdated)))
+			fmt.Println(dimStyle.Render(fmt.Sprintf("  tasks: %d created, %d updated, %d skipped (already exist)",
+				res.TasksCreated, res.TasksUpdated, res.TasksSkipped)))
+			fmt.Println(dimStyle.Render(fmt.Sprintf("  projects: %d created · task types: %d created · dependencies: %d created",
+				res.ProjectsCreated, res.TaskTypesCreated, res.DependenciesCreated)))
+			if res.TasksSkipped > 0 && !overwrite {
+				fmt.Println(dimStyle.Render("  Use --overwrite to replace existing tasks"))
+			}
+		},
+	}
+	cmd.Flags().Bool("overwrite", false, "Replace tasks whose ID already exists")
+	cmd.Flags().Bool("json", false, "Output the import summary as JSON")
+	return cmd
+}
+
+// readExport decodes an export document from path, or stdin for "-".
+func readExport(path string) (*db.Export, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	var exp db.Export
+	if err := json.NewDecoder(r).Decode(&exp); err != nil {
+		return nil, fmt.Errorf("parse export: %w", err)
+	}
+	if exp.SchemaVersion == 0 {
+		return nil, fmt.Errorf("not a ty export document (missing schema_version)")
+	}
+	return &exp, nil
+}
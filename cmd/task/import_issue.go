@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/events"
+	"github.com/bborn/workflow/internal/github"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+// issueTag is the tag every imported issue gets.
+const issueTag = "github"
+
+func newImportIssueCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-issue <issue-url|owner/repo#number>",
+		Short: "Create a task from a GitHub issue",
+		Long: `Fetches a GitHub issue with the gh CLI (so private repositories work with
+your gh login) and creates a task with the issue's title and body, tagged
+"github". The issue URL is linked to the task and shown to the agent.
+
+With --link-back, the daemon comments on the issue once the task is
+completed, pointing back at the task and its pull request.
+
+Examples:
+  ty import-issue https://github.com/bborn/taskyou/issues/42
+  ty import-issue bborn/taskyou#42 --project taskyou --execute
+  ty import-issue bborn/taskyou#42 --link-back`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			project, _ := cmd.Flags().GetString("project")
+			execute, _ := cmd.Flags().GetBool("execute")
+			linkBack, _ := cmd.Flags().GetBool("link-back")
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			if _, _, _, err := github.ParseIssueRef(args[0]); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			if project != "" {
+				if p, err := database.GetProjectByName(project); err != nil || p == nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: project not found: "+project))
+					os.Exit(1)
+				}
+			} else if cwd, err := os.Getwd(); err == nil {
+				if p, err := database.GetProjectByPath(cwd); err == nil && p != nil {
+					project = p.Name
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			issue, err := github.FetchIssue(ctx, args[0])
+			cancel()
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			if existing, _ := database.FindTaskByIssue(issue.URL); existing != 0 {
+				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Error: %s was already imported as task #%d", issue.URL, existing)))
+				os.Exit(1)
+			}
+
+			task, err := createTaskFromIssue(database, issue, project, execute, linkBack)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			if outputJSON {
+				jsonBytes, _ := json.Marshal(map[string]interface{}{
+					"id":        task.ID,
+					"title":     task.Title,
+					"status":    task.Status,
+					"project":   task.Project,
+					"issue_url": issue.URL,
+				})
+				fmt.Println(string(jsonBytes))
+				return
+			}
+			msg := fmt.Sprintf("Created task #%d from %s/%s#%d: %s", task.ID, issue.Owner, issue.Repo, issue.Number, task.Title)
+			if execute {
+				msg += " (queued for execution)"
+			}
+			outln(successStyle.Render(msg))
+			if strings.EqualFold(issue.State, "closed") {
+				fmt.Println(warnStyle.Render("Note: the issue is already closed"))
+			}
+			if linkBack {
+				fmt.Println(dimStyle.Render("The daemon will comment on the issue when the task is completed"))
+			}
+		},
+	}
+	cmd.Flags().StringP("project", "p", "", "Project for the task (default: detected from the current directory)")
+	cmd.Flags().BoolP("execute", "x", false, "Queue the task for immediate execution")
+	cmd.Flags().Bool("link-back", false, "Comment on the issue when the task is completed")
+	cmd.Flags().Bool("json", false, "Output in JSON format")
+	cmd.RegisterFlagCompletionFunc("project", completeFlagProjects)
+	return cmd
+}
+
+// createTaskFromIssue creates the task for an imported issue and links it.
+func createTaskFromIssue(database *db.DB, issue *github.IssueDetails, project string, execute, linkBack bool) (*db.Task, error) {
+	status := db.StatusBacklog
+	if execute {
+		status = db.StatusQueued
+	}
+	task := &db.Task{
+		Title:   issue.Title,
+		Body:    strings.TrimSpace(issue.Body),
+		Status:  status,
+		Type:    db.TypeCode,
+		Project: project,
+		Tags:    issueTag,
+	}
+	if err := database.CreateTask(task); err != nil {
+		return nil, err
+	}
+	if err := database.SetTaskIssue(task.ID, issue.URL, linkBack); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// issueLinkBackComment is the comment posted on an imported issue when its
+// task is completed.
+func issueLinkBackComment(task *db.Task) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Completed in TaskYou task #%d: %s", task.ID, task.Title)
+	if task.PRURL != "" {
+		fmt.Fprintf(&b, "\n\nPull request: %s", task.PRURL)
+	}
+	if task.Summary != "" {
+		fmt.Fprintf(&b, "\n\n%s", task.Summary)
+	}
+	return b.String()
+}
+
+// issueLinkBackWatcher follows the event log in the daemon and comments on
+// the linked issue of each completed task imported with --link-back.
+type issueLinkBackWatcher struct {
+	db     *db.DB
+	logger *log.Logger
+	lastID int64
+
+	// comment posts a comment on an issue. Overridable in tests; defaults to
+	// github.CommentOnIssue.
+	comment func(ctx context.Context, issueURL, body string) error
+}
+
+func newIssueLinkBackWatcher(database *db.DB, logger *log.Logger) *issueLinkBackWatcher {
+	lastID, err := database.LatestEventID()
+	if err != nil {
+		logger.Warn("issue link-back: could not read event log", "error", err)
+	}
+	return &issueLinkBackWatcher{db: database, logger: logger, lastID: lastID, comment: github.CommentOnIssue}
+}
+
+// Run polls until ctx is cancelled.
+func (w *issueLinkBackWatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.Poll(ctx); err != nil {
+				w.logger.Warn("issue link-back: poll failed", "error", err)
+			}
+		}
+	}
+}
+
+// Poll comments on the issues of tasks completed since the previous poll.
+func (w *issueLinkBackWatcher) Poll(ctx context.Context) error {
+	logged, err := w.db.ListEventsAfter(w.lastID)
+	if err != nil {
+		return err
+	}
+	for _, event := range logged {
+		w.lastID = event.ID
+		if event.EventType != events.TaskCompleted {
+			continue
+		}
+		issue, err := w.db.GetTaskIssue(event.TaskID)
+		if err != nil || issue == nil || !issue.LinkBack {
+			continue
+		}
+		task, err := w.db.GetTask(event.TaskID)
+		if err != nil || task == nil {
+			continue
+		}
+
+		commentCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		err = w.comment(commentCtx, issue.URL, issueLinkBackComment(task))
+		cancel()
+		if err != nil {
+			w.logger.Warn("issue link-back: comment failed", "task", task.ID, "issue", issue.URL, "error", err)
+			continue
+		}
+		w.db.ClearIssueLinkBack(task.ID)
+		w.db.AppendTaskLog(task.ID, "system", "Commented on "+issue.URL)
+		w.logger.Info("Commented on linked issue", "task", task.ID, "issue", issue.URL)
+	}
+	return nil
+}
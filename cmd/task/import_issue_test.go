@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/github"
+	"github.com/charmbracelet/log"
+)
+
+func TestCreateTaskFromIssue(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	issue := &github.IssueDetails{
+		Owner: "o", Repo: "r", Number: 7,
+		URL:   "https://github.com/o/r/issues/7",
+		Title: "Login redirect loops",
+		Body:  "Steps to reproduce...\n",
+	}
+	task, err := createTaskFromIssue(database, issue, "", true, true)
+	if err != nil {
+		t.Fatalf("createTaskFromIssue: %v", err)
+	}
+	got, _ := database.GetTask(task.ID)
+	if got.Title != issue.Title || got.Body != "Steps to reproduce..." || got.Tags != issueTag || got.Status != db.StatusQueued {
+		t.Errorf("unexpected task: %+v", got)
+	}
+	link, _ := database.GetTaskIssue(task.ID)
+	if link == nil || link.URL != issue.URL || !link.LinkBack {
+		t.Errorf("unexpected issue link: %+v", link)
+	}
+}
+
+func TestIssueLinkBackWatcher(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	ids := createTestTasks(t, database, 2)
+	database.SetTaskIssue(ids[0], "https://github.com/o/r/issues/1", true)
+	database.SetTaskIssue(ids[1], "https://github.com/o/r/issues/2", false)
+
+	type posted struct{ url, body string }
+	var comments []posted
+	w := newIssueLinkBackWatcher(database, log.New(io.Discard))
+	w.comment = func(ctx context.Context, url, body string) error {
+		comments = append(comments, posted{url, body})
+		return nil
+	}
+
+	for _, id := range ids {
+		if err := database.UpdateTaskStatus(id, db.StatusDone); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if len(comments) != 1 || comments[0].url != "https://github.com/o/r/issues/1" {
+		t.Fatalf("comments = %+v, want one on issue 1", comments)
+	}
+	if !strings.Contains(comments[0].body, "task #") {
+		t.Errorf("comment should point at the task: %q", comments[0].body)
+	}
+	if link, _ := database.GetTaskIssue(ids[0]); link.LinkBack {
+		t.Error("link-back should be cleared once the comment is posted")
+	}
+
+	// Completing the task again doesn't comment twice.
+	database.UpdateTaskStatus(ids[0], db.StatusQueued)
+	database.UpdateTaskStatus(ids[0], db.StatusDone)
+	w.Poll(context.Background())
+	if len(comments) != 1 {
+		t.Errorf("expected no second comment, got %+v", comments)
+	}
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	osexec "os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// interactiveCaptureLines is how much pane history each capture reads;
+	// enough to find where the previous capture ended.
+	interactiveCaptureLines = 500
+	// interactivePoll is how often the pane is captured while waiting for a
+	// reply, interactiveSettle how long it must stay unchanged to count as
+	// done, and interactiveMaxWait when to give up waiting and prompt again.
+	interactivePoll    = 300 * time.Millisecond
+	interactiveSettle  = 2 * time.Second
+	interactiveMaxWait = 60 * time.Second
+)
+
+// capturePane returns the last lines of paneID's content.
+func capturePane(paneID string, lines int) (string, error) {
+	out, err := osexec.Command("tmux", "capture-pane", "-t", paneID, "-p", "-S", fmt.Sprintf("-%d", lines)).Output()
+	return string(out), err
+}
+
+// sendPaneLine types message into paneID and, if submit is set, presses Enter.
+// The text is sent literally, so a message that looks like a tmux key name
+// such as "Enter" or "Up" isn't interpreted as a keypress. Enter is sent as a
+// SEPARATE keypress after the text: agentic TUIs (Claude Code, etc.) use
+// bracketed-paste / input debouncing, so an Enter bundled into the same
+// send-keys call as the text gets absorbed as a newline instead of
+// submitting. The brief pause lets the TUI register the text first.
+func sendPaneLine(paneID, message string, submit bool) error {
+	if message != "" {
+		if err := osexec.Command("tmux", "send-keys", "-t", paneID, "-l", message).Run(); err != nil {
+			return fmt.Errorf("sending input: %w", err)
+		}
+	}
+	if submit {
+		if message != "" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		if err := osexec.Command("tmux", "send-keys", "-t", paneID, "Enter").Run(); err != nil {
+			return fmt.Errorf("sending Enter: %w", err)
+		}
+	}
+	return nil
+}
+
+// paneSession is what an interactive input session needs from the pane;
+// tests substitute a fake for tmux.
+type paneSession struct {
+	send    func(line string) error
+	capture func() (string, error)
+	poll    time.Duration
+	settle  time.Duration
+	maxWait time.Duration
+}
+
+func newTmuxPaneSession(paneID string) paneSession {
+	return paneSession{
+		send:    func(line string) error { return sendPaneLine(paneID, line, true) },
+		capture: func() (string, error) { return capturePane(paneID, interactiveCaptureLines) },
+		poll:    interactivePoll,
+		settle:  interactiveSettle,
+		maxWait: interactiveMaxWait,
+	}
+}
+
+// runInteractiveInput reads lines from in until EOF or /quit, sends each to
+// the pane, and writes what the pane printed in response to out. It returns
+// when the pane disappears too, telling the user the task likely finished.
+func runInteractiveInput(taskID int64, pane paneSession, in io.Reader, out io.Writer) error {
+	last, err := pane.capture()
+	if err != nil {
+		fmt.Fprintln(out, paneGoneMessage(taskID))
+		return nil
+	}
+	fmt.Fprintln(out, dimStyle.Render(fmt.Sprintf("Talking to task #%d. Each line is sent and submitted; /quit or Ctrl-D to leave.", taskID)))
+
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, boldStyle.Render(fmt.Sprintf("#%d> ", taskID)))
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		line := scanner.Text()
+		switch strings.TrimSpace(line) {
+		case "":
+			continue
+		case "/quit", "/exit":
+			return nil
+		}
+
+		if err := pane.send(line); err != nil {
+			fmt.Fprintln(out, paneGoneMessage(taskID))
+			return nil
+		}
+		current, ok := waitForPane(pane, last)
+		if !ok {
+			fmt.Fprintln(out, paneGoneMessage(taskID))
+			return nil
+		}
+		if reply := newPaneLines(last, current); reply != "" {
+			fmt.Fprintln(out, reply)
+		}
+		last = current
+	}
+}
+
+// waitForPane captures the pane until its content has changed from before and
+// then stayed the same for the settle period, or maxWait passes. It reports
+// false if the pane went away.
+func waitForPane(pane paneSession, before string) (string, bool) {
+	current := before
+	stableSince := time.Now()
+	deadline := time.Now().Add(pane.maxWait)
+	for time.Now().Before(deadline) {
+		time.Sleep(pane.poll)
+		next, err := pane.capture()
+		if err != nil {
+			return current, false
+		}
+		if next != current {
+			current = next
+			stableSince = time.Now()
+			continue
+		}
+		if current != before && time.Since(stableSince) >= pane.settle {
+			break
+		}
+	}
+	return current, true
+}
+
+// newPaneLines returns the part of after that follows the content of before,
+// so each reply is printed once even though every capture includes history.
+// It anchors on before's last three non-blank lines, then fewer if the pane
+// scrolled them away; if the pane was redrawn and none can be found, the
+// whole of after is returned.
+func newPaneLines(before, after string) string {
+	prev := nonBlankLines(before)
+	next := strings.Split(strings.TrimRight(after, "\n "), "\n")
+	for k := min(3, len(prev)); k > 0; k-- {
+		anchor := prev[len(prev)-k:]
+		for i := len(next) - 1; i >= 0; i-- {
+			if matchesAnchor(next, i, anchor) {
+				end := nextNonBlank(next, i, len(anchor))
+				return strings.Trim(strings.Join(next[end:], "\n"), "\n")
+			}
+		}
+	}
+	return strings.TrimSpace(strings.Join(next, "\n"))
+}
+
+// matchesAnchor reports whether anchor's lines occur in lines starting at i,
+// ignoring blank lines in between.
+func matchesAnchor(lines []string, i int, anchor []string) bool {
+	for _, want := range anchor {
+		for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+			i++
+		}
+		if i >= len(lines) || strings.TrimRight(lines[i], " ") != want {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// nextNonBlank returns the index just past n non-blank lines starting at i.
+func nextNonBlank(lines []string, i, n int) int {
+	for n > 0 && i < len(lines) {
+		if strings.TrimSpace(lines[i]) != "" {
+			n--
+		}
+		i++
+	}
+	return i
+}
+
+func nonBlankLines(s string) []string {
+	var out []string
+	for _, l := range strings.Split(s, "\n") {
+		if strings.TrimSpace(l) != "" {
+			out = append(out, strings.TrimRight(l, " "))
+		}
+	}
+	return out
+}
+
+func paneGoneMessage(taskID int64) string {
+	return warnStyle.Render(fmt.Sprintf("Executor pane for task #%d is gone; the task likely finished.", taskID)) + "\n" +
+		dimStyle.Render(fmt.Sprintf("Tip: use 'ty show %d' to see what it accomplished", taskID))
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewPaneLines(t *testing.T) {
+	tests := []struct {
+		name, before, after, want string
+	}{
+		{"appended", "banner\n> hello\nHi!\n\n", "banner\n> hello\nHi!\n> next\nSure.\n\n\n", "> next\nSure."},
+		{"scrolled", "a\nb\nc\nd\n", "c\nd\ne\nf\n", "e\nf"},
+		{"unchanged", "a\nb\n", "a\nb\n\n", ""},
+		{"redrawn", "old screen\n", "entirely\nnew\n", "entirely\nnew"},
+		{"empty before", "", "first\n", "first"},
+	}
+	for _, tt := range tests {
+		if got := newPaneLines(tt.before, tt.after); got != tt.want {
+			t.Errorf("%s: newPaneLines() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// fakePane echoes each sent line back as a reply, like an agent would.
+type fakePane struct {
+	screen  string
+	sent    []string
+	goneAt  int // capture fails once this many lines were sent (0 = never)
+	capture int
+}
+
+func (f *fakePane) session() paneSession {
+	return paneSession{
+		send: func(line string) error {
+			f.sent = append(f.sent, line)
+			f.screen += "> " + line + "\nreply to " + line + "\n"
+			return nil
+		},
+		capture: func() (string, error) {
+			if f.goneAt > 0 && len(f.sent) >= f.goneAt {
+				return "", errors.New("can't find pane")
+			}
+			return f.screen, nil
+		},
+		poll:    time.Millisecond,
+		settle:  5 * time.Millisecond,
+		maxWait: time.Second,
+	}
+}
+
+func TestRunInteractiveInput(t *testing.T) {
+	pane := &fakePane{screen: "Claude ready\n"}
+	var out strings.Builder
+	if err := runInteractiveInput(42, pane.session(), strings.NewReader("first\n\nsecond\n/quit\nnever sent\n"), &out); err != nil {
+		t.Fatalf("runInteractiveInput: %v", err)
+	}
+	if strings.Join(pane.sent, "|") != "first|second" {
+		t.Errorf("sent = %q, want first and second only", pane.sent)
+	}
+	transcript := out.String()
+	if strings.Count(transcript, "reply to first") != 1 || !strings.Contains(transcript, "reply to second") {
+		t.Errorf("transcript should show each reply once:\n%s", transcript)
+	}
+	if strings.Contains(transcript, "Claude ready") {
+		t.Errorf("transcript should not repeat the screen from before the session:\n%s", transcript)
+	}
+}
+
+func TestRunInteractiveInputPaneGone(t *testing.T) {
+	pane := &fakePane{screen: "ready\n", goneAt: 1}
+	var out strings.Builder
+	if err := runInteractiveInput(7, pane.session(), strings.NewReader("wrap up\nmore\n"), &out); err != nil {
+		t.Fatalf("runInteractiveInput: %v", err)
+	}
+	if len(pane.sent) != 1 {
+		t.Errorf("sent %d lines after the pane went away, want 1", len(pane.sent))
+	}
+	if !strings.Contains(out.String(), "task likely finished") {
+		t.Errorf("expected a finished-task notice, got:\n%s", out.String())
+	}
+}
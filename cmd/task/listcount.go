@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+// taskCountKey mirrors db.CountTasksBy's grouping for lists filtered in Go
+// (the --workflows/--no-workflows split has no SQL equivalent).
+func taskCountKey(t *db.Task, field string) string {
+	switch field {
+	case "status":
+		return t.Status
+	case "project":
+		return t.Project
+	case "type":
+		return t.Type
+	case "executor":
+		if t.Executor == "" {
+			return db.DefaultExecutor()
+		}
+		return t.Executor
+	case "assignee":
+		return t.Assignee
+	}
+	return ""
+}
+
+// countTaskList groups tasks by field, largest group first.
+func countTaskList(tasks []*db.Task, field string) ([]db.TaskCount, error) {
+	if !slices.Contains(db.TaskCountFields(), field) {
+		return nil, fmt.Errorf("cannot count by %q (use one of: %s)", field, strings.Join(db.TaskCountFields(), ", "))
+	}
+	byKey := make(map[string]int)
+	for _, t := range tasks {
+		byKey[taskCountKey(t, field)]++
+	}
+	counts := make([]db.TaskCount, 0, len(byKey))
+	for k, n := range byKey {
+		counts = append(counts, db.TaskCount{Key: k, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Key < counts[j].Key
+	})
+	return counts, nil
+}
+
+// printTaskCount prints `ty list --count` output.
+func printTaskCount(count int, outputJSON bool) {
+	if outputJSON {
+		jsonBytes, _ := json.Marshal(map[string]interface{}{"count": count})
+		fmt.Println(string(jsonBytes))
+		return
+	}
+	fmt.Println(count)
+}
+
+// printTaskCounts prints `ty list --count-by <field>` output. Empty keys
+// (e.g. unassigned tasks) are shown as "(none)".
+func printTaskCounts(counts []db.TaskCount, field string, outputJSON bool) {
+	total := 0
+	for _, c := range counts {
+		total += c.Count
+	}
+
+	if outputJSON {
+		byKey := make(map[string]int, len(counts))
+		for _, c := range counts {
+			byKey[c.Key] = c.Count
+		}
+		jsonBytes, _ := json.Marshal(map[string]interface{}{
+			"by":     field,
+			"counts": byKey,
+			"total":  total,
+		})
+		fmt.Println(string(jsonBytes))
+		return
+	}
+
+	width := len("total")
+	for _, c := range counts {
+		if n := len(countLabel(c.Key)); n > width {
+			width = n
+		}
+	}
+	for _, c := range counts {
+		fmt.Printf("%-*s  %d\n", width, countLabel(c.Key), c.Count)
+	}
+	fmt.Println(dimStyle.Render(fmt.Sprintf("%-*s  %d", width, "total", total)))
+}
+
+func countLabel(key string) string {
+	if key == "" {
+		return "(none)"
+	}
+	return key
+}
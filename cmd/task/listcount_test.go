@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestCountTaskList(t *testing.T) {
+	tasks := []*db.Task{
+		{Project: "a", Assignee: "me"},
+		{Project: "b"},
+		{Project: "a"},
+	}
+
+	counts, err := countTaskList(tasks, "project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(counts) != 2 || counts[0] != (db.TaskCount{Key: "a", Count: 2}) || counts[1] != (db.TaskCount{Key: "b", Count: 1}) {
+		t.Errorf("counts by project = %+v", counts)
+	}
+
+	counts, err = countTaskList(tasks, "assignee")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(counts) != 2 || counts[0] != (db.TaskCount{Key: "", Count: 2}) {
+		t.Errorf("counts by assignee = %+v", counts)
+	}
+
+	if _, err := countTaskList(tasks, "title"); err == nil {
+		t.Error("expected an unsupported field to be rejected")
+	}
+}
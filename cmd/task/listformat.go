@@ -0,0 +1,221 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/github"
+)
+
+// Output kinds for `ty list`, chosen by resolveListFormat.
+const (
+	listFormatTable      = "table"       // the default, colored listing
+	listFormatCSV        = "csv"         // RFC 4180 CSV with a header row
+	listFormatTSV        = "tsv"         // the same, tab-separated
+	listFormatGoTemplate = "go-template" // --template executed once per task
+)
+
+// listFormatPresets are the named --format values accepted by `ty list`.
+// Anything else is parsed as a Go text/template.
+var listFormatPresets = map[string]string{
+	"oneline": `{{.id}} {{.status}} {{.title}}`,
+	"wide":    `{{printf "%-5d" .id}} {{printf "%-10s" .status}} {{printf "%-8s" .type}} {{printf "%-16s" .project}} {{date "2006-01-02 15:04" .created_at}}  {{.title}}`,
+}
+
+// listFormatFields documents the fields a --format template sees: the keys
+// of `ty list --json`, each present on every task, and the db.Task fields.
+const listFormatFields = `  .id .title .status .type .project .created_at (RFC3339) .assignee .priority
+  .scheduled .blocked_reason, and with --pr .pr.number .pr.url .pr.state
+  .pr.check_state .pr.description (guard with {{with .pr}} for tasks without one).
+  The task's own fields work too: .ID .Title .Status .Tags .Body and so on.`
+
+var listFormatFuncs = template.FuncMap{
+	// truncate shortens s to at most n runes, ending in "…" when cut.
+	"truncate": func(n int, s string) string {
+		r := []rune(s)
+		if n <= 0 || len(r) <= n {
+			return s
+		}
+		if n == 1 {
+			return "…"
+		}
+		return string(r[:n-1]) + "…"
+	},
+	// date reformats an RFC3339 timestamp (such as .created_at) with a Go
+	// time layout, leaving anything else unchanged.
+	"date": func(layout, s string) string {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return s
+		}
+		return t.Local().Format(layout)
+	},
+}
+
+// parseListFormat resolves a preset name or parses a custom template, then
+// renders it once on an empty task so a misspelled field is reported before
+// any output rather than on the first row. An empty format returns a nil
+// template (use the default output).
+func parseListFormat(format string) (*template.Template, error) {
+	if format == "" {
+		return nil, nil
+	}
+	text := format
+	if preset, ok := listFormatPresets[format]; ok {
+		text = preset
+	}
+	tmpl, err := template.New("list").Funcs(listFormatFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format template: %w", err)
+	}
+	// The sample has a PR so fields under .pr are checked as well.
+	if err := tmpl.Execute(io.Discard, listTemplateData(&db.Task{}, false, &github.PRInfo{})); err != nil {
+		return nil, fmt.Errorf("invalid --format template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// listTemplateData is what a --format template runs on for t: its --json
+// fields (see listTaskFields) plus every exported db.Task field under its Go
+// name, so both {{.id}} and {{.ID}} work.
+func listTemplateData(t *db.Task, scheduled bool, pr *github.PRInfo) map[string]interface{} {
+	data := listTaskFields(t, scheduled, pr, true)
+	v := reflect.ValueOf(t).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Type().Field(i); f.IsExported() {
+			data[f.Name] = v.Field(i).Interface()
+		}
+	}
+	return data
+}
+
+// listTaskFields returns the fields `ty list --json` prints for t. pr is nil
+// when --pr wasn't given or the task has no PR. With allKeys, the fields
+// --json leaves out when empty are included anyway, so a --format template
+// can use them on every task.
+func listTaskFields(t *db.Task, scheduled bool, pr *github.PRInfo, allKeys bool) map[string]interface{} {
+	item := map[string]interface{}{
+		"id":         t.ID,
+		"title":      t.Title,
+		"status":     t.Status,
+		"type":       t.Type,
+		"project":    t.Project,
+		"created_at": t.CreatedAt.Time.Format(time.RFC3339),
+	}
+	if t.BlockedReason != "" || allKeys {
+		item["blocked_reason"] = t.BlockedReason
+	}
+	if t.Assignee != "" || allKeys {
+		item["assignee"] = t.Assignee
+	}
+	if t.Priority != 0 || allKeys {
+		item["priority"] = t.Priority
+	}
+	if scheduled || allKeys {
+		item["scheduled"] = scheduled
+	}
+	if pr != nil {
+		item["pr"] = map[string]interface{}{
+			"number":      pr.Number,
+			"url":         pr.URL,
+			"state":       string(pr.State),
+			"check_state": string(pr.CheckState),
+			"description": pr.StatusDescription(),
+		}
+	} else if allKeys {
+		item["pr"] = nil
+	}
+	return item
+}
+
+// resolveListFormat works out what `ty list` prints from --format and
+// --template: one of the listFormat kinds, plus the parsed template for
+// listFormatGoTemplate. --template alone implies --format go-template, and
+// any --format that isn't a kind is taken as a preset or inline template, as
+// before --template existed.
+func resolveListFormat(format, templateText string) (string, *template.Template, error) {
+	if templateText != "" {
+		if format != "" && format != listFormatGoTemplate {
+			return "", nil, fmt.Errorf("--template needs --format %s (got --format %s)", listFormatGoTemplate, format)
+		}
+		tmpl, err := parseListFormat(templateText)
+		return listFormatGoTemplate, tmpl, err
+	}
+	switch format {
+	case "", listFormatTable:
+		return listFormatTable, nil, nil
+	case listFormatCSV, listFormatTSV:
+		return format, nil, nil
+	case listFormatGoTemplate:
+		return "", nil, fmt.Errorf("--format %s needs --template (e.g. --template '{{.id}} {{.title}}')", listFormatGoTemplate)
+	}
+	tmpl, err := parseListFormat(format)
+	return listFormatGoTemplate, tmpl, err
+}
+
+// listCSVHeader returns the --format csv/tsv columns: the fields of the
+// --json output, with the PR ones only when --pr fetched them.
+func listCSVHeader(withPR bool) []string {
+	header := []string{"id", "title", "status", "type", "project", "created_at", "assignee", "priority", "scheduled", "blocked_reason"}
+	if withPR {
+		header = append(header, "pr_number", "pr_url", "pr_state", "check_state")
+	}
+	return header
+}
+
+// renderListDelimited writes tasks as CSV (comma ',') or TSV (comma '\t').
+// Fields holding the separator, quotes or newlines are quoted, so titles
+// with commas or line breaks survive a round trip through a spreadsheet.
+func renderListDelimited(w io.Writer, comma rune, tasks []*db.Task, scheduled map[int64]bool, withPR bool, prs map[int64]*github.PRInfo) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	if err := cw.Write(listCSVHeader(withPR)); err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		row := []string{
+			strconv.FormatInt(t.ID, 10),
+			t.Title,
+			t.Status,
+			t.Type,
+			t.Project,
+			t.CreatedAt.Time.Format(time.RFC3339),
+			t.Assignee,
+			strconv.Itoa(t.Priority),
+			strconv.FormatBool(scheduled[t.ID]),
+			t.BlockedReason,
+		}
+		if withPR {
+			if pr := prs[t.ID]; pr != nil {
+				row = append(row, strconv.Itoa(pr.Number), pr.URL, string(pr.State), string(pr.CheckState))
+			} else {
+				row = append(row, "", "", "", "")
+			}
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// renderListFormat writes one template line per task, executing tmpl on the
+// task's fields (see listTemplateData).
+func renderListFormat(w io.Writer, tmpl *template.Template, tasks []*db.Task, scheduled map[int64]bool, prs map[int64]*github.PRInfo) error {
+	for _, t := range tasks {
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, listTemplateData(t, scheduled[t.ID], prs[t.ID])); err != nil {
+			return fmt.Errorf("render task #%d: %w", t.ID, err)
+		}
+		fmt.Fprintln(w, strings.TrimRight(sb.String(), "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/github"
+)
+
+func TestParseListFormat(t *testing.T) {
+	if tmpl, err := parseListFormat(""); err != nil || tmpl != nil {
+		t.Fatalf("empty format: expected nil template, got %v, %v", tmpl, err)
+	}
+	for name := range listFormatPresets {
+		if _, err := parseListFormat(name); err != nil {
+			t.Errorf("preset %q: %v", name, err)
+		}
+	}
+	if _, err := parseListFormat("{{.ID"); err == nil {
+		t.Error("expected parse error for unterminated action")
+	}
+	// Fields that are nil on an empty task must still parse.
+	if _, err := parseListFormat("{{with .pr}}{{.number}}{{end}} {{.pr.url}}"); err != nil {
+		t.Errorf("optional fields: %v", err)
+	}
+	// Unknown fields are caught before anything is rendered.
+	for _, text := range []string{"{{.NoSuchField}}", "{{.pr.nope}}"} {
+		if _, err := parseListFormat(text); err == nil {
+			t.Errorf("%s: expected an error for an unknown field", text)
+		}
+	}
+}
+
+func TestRenderListFormat(t *testing.T) {
+	tasks := []*db.Task{
+		{ID: 1, Title: "First task", Status: db.StatusQueued},
+		{ID: 2, Title: "A rather long second title", Status: db.StatusBacklog},
+	}
+
+	tmpl, err := parseListFormat("oneline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := renderListFormat(&sb, tmpl, tasks, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := "1 queued First task\n2 backlog A rather long second title\n"
+	if sb.String() != want {
+		t.Errorf("oneline: expected %q, got %q", want, sb.String())
+	}
+
+	tmpl, err = parseListFormat(`{{.id}}:{{truncate 8 .title}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb.Reset()
+	if err := renderListFormat(&sb, tmpl, tasks, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	want = "1:First t…\n2:A rathe…\n"
+	if sb.String() != want {
+		t.Errorf("custom: expected %q, got %q", want, sb.String())
+	}
+
+	// Optional fields are present on every task; --pr data only where fetched.
+	tmpl, err = parseListFormat(`{{.id}} {{.assignee}}|{{.scheduled}}|{{with .pr}}#{{.number}}{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb.Reset()
+	prs := map[int64]*github.PRInfo{2: {Number: 42}}
+	if err := renderListFormat(&sb, tmpl, tasks, map[int64]bool{1: true}, prs); err != nil {
+		t.Fatal(err)
+	}
+	want = "1 |true|\n2 |false|#42\n"
+	if sb.String() != want {
+		t.Errorf("optional fields: expected %q, got %q", want, sb.String())
+	}
+
+	// The task's Go field names work as well as the --json keys.
+	tmpl, err = parseListFormat(`{{.ID}} {{.Title}} {{.Status}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb.Reset()
+	if err := renderListFormat(&sb, tmpl, tasks, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	want = "1 First task queued\n2 A rather long second title backlog\n"
+	if sb.String() != want {
+		t.Errorf("struct fields: expected %q, got %q", want, sb.String())
+	}
+}
+
+func TestResolveListFormat(t *testing.T) {
+	for _, c := range []struct {
+		format, template string
+		want             string
+		wantTmpl         bool
+	}{
+		{"", "", listFormatTable, false},
+		{"table", "", listFormatTable, false},
+		{"csv", "", listFormatCSV, false},
+		{"tsv", "", listFormatTSV, false},
+		{"go-template", "{{.id}}", listFormatGoTemplate, true},
+		{"", "{{.id}}", listFormatGoTemplate, true},
+		{"oneline", "", listFormatGoTemplate, true},
+		{"{{.title}}", "", listFormatGoTemplate, true},
+	} {
+		kind, tmpl, err := resolveListFormat(c.format, c.template)
+		if err != nil || kind != c.want || (tmpl != nil) != c.wantTmpl {
+			t.Errorf("resolveListFormat(%q, %q) = %q, %v, %v", c.format, c.template, kind, tmpl != nil, err)
+		}
+	}
+	if _, _, err := resolveListFormat("go-template", ""); err == nil {
+		t.Error("go-template without --template should fail")
+	}
+	if _, _, err := resolveListFormat("csv", "{{.id}}"); err == nil {
+		t.Error("--template with --format csv should fail")
+	}
+}
+
+func TestRenderListDelimited(t *testing.T) {
+	tasks := []*db.Task{
+		{ID: 1, Title: "Fix login, then logout", Status: db.StatusQueued, Project: "app"},
+		{ID: 2, Title: "Line one\nline \"two\"", Status: db.StatusBacklog, Priority: 2},
+	}
+
+	var sb strings.Builder
+	if err := renderListDelimited(&sb, ',', tasks, map[int64]bool{2: true}, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	r := csv.NewReader(strings.NewReader(sb.String()))
+	rows, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("output isn't valid CSV: %v\n%s", err, sb.String())
+	}
+	if len(rows) != 3 || strings.Join(rows[0], ",") != strings.Join(listCSVHeader(false), ",") {
+		t.Fatalf("rows = %q", rows)
+	}
+	if rows[1][1] != tasks[0].Title || rows[2][1] != tasks[1].Title {
+		t.Errorf("titles didn't round-trip: %q, %q", rows[1][1], rows[2][1])
+	}
+	if rows[2][7] != "2" || rows[2][8] != "true" {
+		t.Errorf("priority/scheduled = %q, %q", rows[2][7], rows[2][8])
+	}
+
+	sb.Reset()
+	if err := renderListDelimited(&sb, '\t', tasks[:1], nil, true, nil); err != nil {
+		t.Fatal(err)
+	}
+	first := strings.SplitN(sb.String(), "\n", 2)[0]
+	if got := strings.Split(first, "\t"); len(got) != len(listCSVHeader(true)) || got[len(got)-1] != "check_state" {
+		t.Errorf("tsv header = %q", first)
+	}
+}
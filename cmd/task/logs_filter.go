@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/executor"
+)
+
+// claudeLogFilter narrows `ty logs` to some of the session directories under
+// the Claude projects dir. Claude names each directory after the escaped
+// working directory (see executor.ClaudeProjectDirName), so a project's logs
+// are the directory for its path plus those for its tasks' worktrees.
+type claudeLogFilter struct {
+	dirs        map[string]bool // exact session directory names
+	dirPrefixes []string        // directory name prefixes (e.g. a project's worktrees)
+	sessionFile string          // only this session file, if set
+}
+
+// matches reports whether the session file at path passes the filter. A nil
+// filter matches everything.
+func (f *claudeLogFilter) matches(path string) bool {
+	if f == nil {
+		return true
+	}
+	if f.sessionFile != "" && filepath.Base(path) != f.sessionFile {
+		return false
+	}
+	dir := filepath.Base(filepath.Dir(path))
+	if f.dirs[dir] {
+		return true
+	}
+	for _, prefix := range f.dirPrefixes {
+		if strings.HasPrefix(dir, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// projectLogFilter matches session logs for a project: Claude sessions run in
+// the project directory itself and in its tasks' worktrees.
+func projectLogFilter(database *db.DB, name string) (*claudeLogFilter, error) {
+	project, err := database.GetProjectByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("get project: %w", err)
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", name)
+	}
+	if project.Path == "" {
+		return nil, fmt.Errorf("project %s has no path", project.Name)
+	}
+
+	projectPath := filepath.Clean(project.Path)
+	filter := &claudeLogFilter{
+		dirs:        map[string]bool{executor.ClaudeProjectDirName(projectPath): true},
+		dirPrefixes: []string{executor.ClaudeProjectDirName(filepath.Join(projectPath, ".task-worktrees") + "/")},
+	}
+
+	// Worktrees can live outside the project (older layouts, custom paths).
+	tasks, err := database.ListTasks(db.ListTasksOptions{Project: project.Name, IncludeClosed: true, Limit: 100000})
+	if err != nil {
+		return nil, fmt.Errorf("list tasks: %w", err)
+	}
+	for _, task := range tasks {
+		if task.WorktreePath != "" {
+			filter.dirs[executor.ClaudeProjectDirName(filepath.Clean(task.WorktreePath))] = true
+		}
+	}
+	return filter, nil
+}
+
+// taskLogFilter matches one task's Claude session log and returns the projects
+// directory it lives in, which depends on the task's Claude config dir.
+func taskLogFilter(database *db.DB, taskID int64) (string, *claudeLogFilter, error) {
+	task, err := database.GetTask(taskID)
+	if err != nil {
+		return "", nil, fmt.Errorf("get task: %w", err)
+	}
+	if task == nil {
+		return "", nil, fmt.Errorf("task #%d not found", taskID)
+	}
+
+	project, err := database.GetProjectByName(task.Project)
+	if err != nil {
+		return "", nil, fmt.Errorf("get project: %w", err)
+	}
+
+	workDir := task.WorktreePath
+	if workDir == "" {
+		if project == nil || project.Path == "" {
+			return "", nil, fmt.Errorf("task #%d has no worktree yet", taskID)
+		}
+		workDir = project.Path
+	}
+
+	// The task's config dir overrides the project's, which overrides the default.
+	configDir := task.ClaudeConfigDir
+	if configDir == "" && project != nil {
+		configDir = project.ClaudeConfigDir
+	}
+
+	filter := &claudeLogFilter{
+		dirs: map[string]bool{executor.ClaudeProjectDirName(filepath.Clean(workDir)): true},
+	}
+	// Without a recorded session ID, follow whatever sessions run in the
+	// task's directory.
+	if task.ClaudeSessionID != "" {
+		filter.sessionFile = task.ClaudeSessionID + ".jsonl"
+	}
+	projectsDir := filepath.Join(executor.ResolveClaudeConfigDir(configDir), "projects")
+	return projectsDir, filter, nil
+}
+
+// logResolver returns the current filter and the task ID for each session
+// directory it knows about. `ty logs` calls it again on every re-glob, so a
+// task that starts a new Claude session is followed without a restart.
+type logResolver func() (*claudeLogFilter, map[string]int64, error)
+
+// sessionTaskIDs maps each task worktree's Claude session directory name to
+// the task's ID, so log lines can be labelled with the task they came from.
+func sessionTaskIDs(database *db.DB) (map[string]int64, error) {
+	tasks, err := database.ListTasks(db.ListTasksOptions{IncludeClosed: true, Limit: 100000})
+	if err != nil {
+		return nil, fmt.Errorf("list tasks: %w", err)
+	}
+	ids := make(map[string]int64)
+	for _, task := range tasks {
+		if task.WorktreePath != "" {
+			ids[executor.ClaudeProjectDirName(filepath.Clean(task.WorktreePath))] = task.ID
+		}
+	}
+	return ids, nil
+}
+
+// logLabel is the prefix shown before each `ty logs` line: the session
+// directory, plus the task ID when the directory belongs to a task.
+func logLabel(path string, taskIDs map[string]int64) string {
+	dir := filepath.Base(filepath.Dir(path))
+	if id, ok := taskIDs[dir]; ok {
+		return fmt.Sprintf("[#%d %s]", id, dir)
+	}
+	return "[" + dir + "]"
+}
+
+// ansiSGR matches the color and style escapes lipgloss adds to log lines.
+var ansiSGR = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// logGrep keeps the `ty logs` lines that match a pattern (--grep), or with
+// invert those that don't (--invert). A nil logGrep keeps every line.
+type logGrep struct {
+	re     *regexp.Regexp
+	invert bool
+}
+
+// newLogGrep compiles pattern once for the whole tail. It returns nil when no
+// pattern is given.
+func newLogGrep(pattern string, invert bool) (*logGrep, error) {
+	if pattern == "" {
+		if invert {
+			return nil, fmt.Errorf("--invert requires --grep")
+		}
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --grep pattern: %w", err)
+	}
+	return &logGrep{re: re, invert: invert}, nil
+}
+
+// keep reports whether line should be printed. It matches the line as shown,
+// label included, but without color codes.
+func (g *logGrep) keep(line string) bool {
+	if g == nil {
+		return true
+	}
+	return g.re.MatchString(ansiSGR.ReplaceAllString(line, "")) != g.invert
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestProjectLogFilter(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	if err := database.CreateProject(&db.Project{Name: "app", Path: "/work/app", Aliases: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	task := &db.Task{Title: "elsewhere", Status: db.StatusBacklog, Type: db.TypeCode, Project: "app"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	task.WorktreePath = "/tmp/wt/elsewhere"
+	if err := database.UpdateTask(task); err != nil {
+		t.Fatal(err)
+	}
+
+	filter, err := projectLogFilter(database, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := func(dir string) string { return filepath.Join("/home/u/.claude/projects", dir, "abc.jsonl") }
+	for dir, want := range map[string]bool{
+		"-work-app":                         true,
+		"-work-app--task-worktrees-12-fix":  true,
+		"-tmp-wt-elsewhere":                 true,
+		"-work-application":                 false,
+		"-work-app-other":                   false,
+		"-work-other--task-worktrees-3-foo": false,
+	} {
+		if got := filter.matches(session(dir)); got != want {
+			t.Errorf("matches(%s) = %v, want %v", dir, got, want)
+		}
+	}
+
+	if _, err := projectLogFilter(database, "missing"); err == nil {
+		t.Error("expected an error for an unknown project")
+	}
+}
+
+func TestTaskLogFilter(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	if err := database.CreateProject(&db.Project{Name: "app", Path: "/work/app", ClaudeConfigDir: "/cfg/alt"}); err != nil {
+		t.Fatal(err)
+	}
+	task := &db.Task{Title: "fix", Status: db.StatusBacklog, Type: db.TypeCode, Project: "app"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	task.WorktreePath = "/work/app/.task-worktrees/1-fix"
+	task.ClaudeSessionID = "sess-1"
+	if err := database.UpdateTask(task); err != nil {
+		t.Fatal(err)
+	}
+
+	projectsDir, filter, err := taskLogFilter(database, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if projectsDir != "/cfg/alt/projects" {
+		t.Errorf("projectsDir = %q, want the project's config dir", projectsDir)
+	}
+	dir := filepath.Join(projectsDir, "-work-app--task-worktrees-1-fix")
+	if !filter.matches(filepath.Join(dir, "sess-1.jsonl")) {
+		t.Error("expected the task's session file to match")
+	}
+	if filter.matches(filepath.Join(dir, "other.jsonl")) {
+		t.Error("expected other sessions in the worktree not to match")
+	}
+	if filter.matches(filepath.Join(projectsDir, "-work-app", "sess-1.jsonl")) {
+		t.Error("expected the project directory not to match")
+	}
+}
+
+func TestLogLabelIncludesTaskID(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	if err := database.CreateProject(&db.Project{Name: "app", Path: "/work/app"}); err != nil {
+		t.Fatal(err)
+	}
+	task := &db.Task{Title: "fix", Status: db.StatusBacklog, Type: db.TypeCode, Project: "app"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	task.WorktreePath = "/work/app/.task-worktrees/7-fix"
+	if err := database.UpdateTask(task); err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := sessionTaskIDs(database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	taskDir := "-work-app--task-worktrees-7-fix"
+	if got, want := logLabel(filepath.Join("/p", taskDir, "s.jsonl"), ids), fmt.Sprintf("[#%d %s]", task.ID, taskDir); got != want {
+		t.Errorf("logLabel() = %q, want %q", got, want)
+	}
+	if got := logLabel(filepath.Join("/p", "-work-app", "s.jsonl"), ids); got != "[-work-app]" {
+		t.Errorf("logLabel() for a non-task dir = %q", got)
+	}
+}
+
+func TestLogGrep(t *testing.T) {
+	if g, err := newLogGrep("", false); err != nil || g != nil {
+		t.Fatalf("empty pattern: got %v, %v; want nil, nil", g, err)
+	}
+	if _, err := newLogGrep("", true); err == nil {
+		t.Error("expected --invert without --grep to fail")
+	}
+	if _, err := newLogGrep("(unclosed", false); err == nil {
+		t.Error("expected an invalid pattern to fail")
+	}
+
+	// As printed to a terminal: label and prefix carry color codes.
+	line := "\x1b[38;5;245m[#42 -work-app]\x1b[0m \x1b[32mCLAUDE: \x1b[0mfixed auth.go"
+	g, err := newLogGrep(`^\[#42 .*CLAUDE: fixed`, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.keep(line) {
+		t.Error("expected the pattern to match the line without its color codes")
+	}
+	if g.keep("[#7 -work-app] USER: hi") {
+		t.Error("expected a non-matching line to be dropped")
+	}
+
+	inverted, err := newLogGrep("USER:", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inverted.keep("[-work-app] USER: hi") || !inverted.keep("[-work-app] CLAUDE: done") {
+		t.Error("expected --invert to drop matching lines and keep the rest")
+	}
+}
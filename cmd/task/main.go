@@ -5,11 +5,13 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	osexec "os/exec"
 	"os/signal"
+	"os/user"
 	"path/filepath"
 	"runtime"
 	"runtime/pprof"
@@ -32,14 +34,18 @@ import (
 	"github.com/bborn/workflow/internal/github"
 	"github.com/bborn/workflow/internal/hooks"
 	"github.com/bborn/workflow/internal/mcp"
+	"github.com/bborn/workflow/internal/notify"
 	"github.com/bborn/workflow/internal/pipeline"
+	"github.com/bborn/workflow/internal/profile"
 	"github.com/bborn/workflow/internal/routine"
 	"github.com/bborn/workflow/internal/ui"
+	"github.com/bborn/workflow/internal/usage"
 	"github.com/bborn/workflow/internal/web"
 )
 
 var (
 	version = "dev"
+	commit  = "" // git commit, set with -ldflags "-X main.commit=..."
 
 	// Styles for CLI output
 	successStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#10B981"))
@@ -62,12 +68,12 @@ func getSessionID() string {
 
 // getUISessionName returns the task-ui session name for this instance.
 func getUISessionName() string {
-	return fmt.Sprintf("task-ui-%s", getSessionID())
+	return profile.UISessionPrefix() + getSessionID()
 }
 
 // getDaemonSessionName returns the task-daemon session name for this instance.
 func getDaemonSessionName() string {
-	return fmt.Sprintf("task-daemon-%s", getSessionID())
+	return profile.DaemonSessionPrefix() + getSessionID()
 }
 
 // taskEmitter holds the process-wide events emitter so short-lived CLI
@@ -160,15 +166,36 @@ func main() {
 	rootCmd.PersistentFlags().String("debug-state-file", "", "Path to write debug state JSON on update")
 	rootCmd.PersistentFlags().String("cpuprofile", "", "Write a CPU profile here while the TUI runs (analyze with: go tool pprof)")
 	rootCmd.PersistentFlags().String("memprofile", "", "Write a heap profile here when the TUI exits")
+	rootCmd.PersistentFlags().String("profile", "", "Use a named profile's database (see 'ty profiles'); also read from $"+profile.Env)
+	rootCmd.PersistentFlags().String("db", "", "Use the database at this path; also read from $"+profile.DBEnv)
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only print errors and essential values, such as a new task's ID (distinct from --json)")
+	rootCmd.MarkFlagsMutuallyExclusive("profile", "db")
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		// Resolve the profile or --db before any command touches db.DefaultPath.
+		// Activation exports it, so the daemon, tmux windows and hooks we spawn
+		// inherit it. A flag beats the environment; see profile.ActivateFromEnv
+		// for how the variables rank.
+		name, _ := cmd.Flags().GetString("profile")
+		dbPath, _ := cmd.Flags().GetString("db")
+		switch {
+		case dbPath != "":
+			return profile.ActivateDB(dbPath)
+		case name != "":
+			return profile.Activate(name)
+		}
+		return profile.ActivateFromEnv()
+	}
 
 	// Version deprecation warning for CLI subcommands.
-	// Skip for root (TUI has its own check), upgrade, daemon, mcp-server, and claude-hook.
+	// Skip for root (TUI has its own check), upgrade, daemon, mcp-server, and the hook handlers.
 	skipVersionCheck := map[string]bool{
 		"ty":          true, // root command (TUI)
 		"upgrade":     true,
 		"daemon":      true,
 		"mcp-server":  true,
 		"claude-hook": true,
+		"agent-hook":  true,
+		"version":     true, // has its own --check
 	}
 	rootCmd.PersistentPostRun = func(cmd *cobra.Command, args []string) {
 		// Flush any pending event hook goroutines kicked off by the command.
@@ -276,7 +303,7 @@ Examples:
 				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
 				os.Exit(1)
 			}
-			fmt.Println(successStyle.Render("Daemon stopped"))
+			outln(successStyle.Render("Daemon stopped"))
 		},
 	}
 	daemonCmd.AddCommand(daemonStopCmd)
@@ -298,7 +325,7 @@ Examples:
 				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
 				os.Exit(1)
 			}
-			fmt.Println(successStyle.Render("Daemon restarted"))
+			outln(successStyle.Render("Daemon restarted"))
 		},
 	}
 	daemonCmd.AddCommand(daemonRestartCmd)
@@ -315,13 +342,16 @@ Examples:
 				if m, err := os.ReadFile(modeFile); err == nil {
 					mode = string(m)
 				}
-				fmt.Println(successStyle.Render(fmt.Sprintf("Daemon running (pid %d, %s mode)", pid, mode)))
+				outln(successStyle.Render(fmt.Sprintf("Daemon running (pid %d, %s mode)", pid, mode)))
 			} else {
 				fmt.Println(dimStyle.Render("Daemon not running"))
 			}
+			fmt.Println(dimStyle.Render("Log file: " + getDaemonLogPath()))
 		},
 	}
 	daemonCmd.AddCommand(daemonStatusCmd)
+	daemonCmd.AddCommand(newDaemonLogsCmd())
+	daemonCmd.AddCommand(newDaemonRunOnceCmd())
 
 	rootCmd.AddCommand(daemonCmd)
 
@@ -342,7 +372,7 @@ Use --hard to kill all tmux sessions for a complete reset.`,
 				out, _ := osexec.Command("tmux", "list-sessions", "-F", "#{session_name}").Output()
 				for _, session := range strings.Split(string(out), "\n") {
 					session = strings.TrimSpace(session)
-					if strings.HasPrefix(session, "task-daemon-") || strings.HasPrefix(session, "task-ui-") {
+					if strings.HasPrefix(session, profile.DaemonSessionPrefix()) || strings.HasPrefix(session, profile.UISessionPrefix()) {
 						osexec.Command("tmux", "kill-session", "-t", session).Run()
 					}
 				}
@@ -353,13 +383,13 @@ Use --hard to kill all tmux sessions for a complete reset.`,
 				for _, session := range strings.Split(string(out), "\n") {
 					session = strings.TrimSpace(session)
 					// Only kill task-ui sessions, keep task-daemon sessions with Claude windows
-					if strings.HasPrefix(session, "task-ui-") {
+					if strings.HasPrefix(session, profile.UISessionPrefix()) {
 						osexec.Command("tmux", "kill-session", "-t", session).Run()
 					}
 				}
 			}
 
-			fmt.Println(successStyle.Render("Restarting..."))
+			outln(successStyle.Render("Restarting..."))
 			time.Sleep(200 * time.Millisecond)
 
 			// Re-exec task command
@@ -395,15 +425,99 @@ Tasks will automatically reconnect to their agent sessions when viewed.`,
 	logsCmd := &cobra.Command{
 		Use:   "logs",
 		Short: "Tail claude session logs for debugging",
-		Long:  "Streams all claude session logs across all projects in real-time.",
-		Args:  cobra.NoArgs, // takes no positional args; reject them instead of silently ignoring (e.g. `ty logs 4013`)
+		Long: `Streams all claude session logs across all projects in real-time.
+
+Use --project to follow only one project's sessions (its directory and its
+task worktrees), or --task to follow a single task's Claude session. Lines
+from a task's worktree are prefixed with the task ID. Use --raw to print the
+unparsed JSONL lines instead.
+
+Use --grep to print only the lines matching a regular expression (matched
+against the line as shown, label included), and --invert to drop them instead.
+
+Examples:
+  ty logs
+  ty logs --project myapp
+  ty logs --task 42
+  ty logs --task 42 --raw | jq .
+  ty logs --grep '(?i)error|panic'
+  ty logs --project myapp --grep 'auth\.go'
+  ty logs --grep 'USER:' --invert    # Only Claude's replies`,
+		Args: cobra.NoArgs, // takes no positional args; reject them instead of silently ignoring (e.g. `ty logs 4013`)
 		Run: func(cmd *cobra.Command, args []string) {
-			if err := tailClaudeLogs(); err != nil {
+			projectName, _ := cmd.Flags().GetString("project")
+			taskID, _ := cmd.Flags().GetInt64("task")
+			raw, _ := cmd.Flags().GetBool("raw")
+			pattern, _ := cmd.Flags().GetString("grep")
+			invert, _ := cmd.Flags().GetBool("invert")
+
+			grep, err := newLogGrep(pattern, invert)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			home, err := os.UserHomeDir()
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: get home dir: "+err.Error()))
+				os.Exit(1)
+			}
+			projectsDir := filepath.Join(home, ".claude", "projects")
+
+			// Resolved on start and again on every re-glob, so a task's new
+			// sessions (e.g. after a retry) are picked up.
+			resolve := func() (*claudeLogFilter, map[string]int64, error) {
+				database, err := openTaskDB(db.DefaultPath())
+				if err != nil {
+					return nil, nil, err
+				}
+				defer database.Close()
+
+				var filter *claudeLogFilter
+				if projectName != "" {
+					filter, err = projectLogFilter(database, projectName)
+				} else if taskID != 0 {
+					_, filter, err = taskLogFilter(database, taskID)
+				}
+				if err != nil {
+					return nil, nil, err
+				}
+				taskIDs, err := sessionTaskIDs(database)
+				if err != nil {
+					return nil, nil, err
+				}
+				return filter, taskIDs, nil
+			}
+
+			// A task's sessions live under its own Claude config dir.
+			if taskID != 0 {
+				database, err := openTaskDB(db.DefaultPath())
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+				projectsDir, _, err = taskLogFilter(database, taskID)
+				database.Close()
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+			}
+
+			if err := tailClaudeLogs(projectsDir, resolve, raw, grep); err != nil {
 				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
 				os.Exit(1)
 			}
 		},
 	}
+	logsCmd.Flags().String("project", "", "Only tail sessions belonging to this project")
+	logsCmd.Flags().Int64("task", 0, "Only tail this task's Claude session")
+	logsCmd.Flags().Bool("raw", false, "Print unparsed JSONL lines")
+	logsCmd.Flags().String("grep", "", "Only print lines matching this regular expression")
+	logsCmd.Flags().Bool("invert", false, "With --grep, print only the lines that don't match")
+	logsCmd.MarkFlagsMutuallyExclusive("project", "task")
+	logsCmd.RegisterFlagCompletionFunc("project", completeFlagProjects)
+	logsCmd.RegisterFlagCompletionFunc("task", completeTaskIDs)
 	rootCmd.AddCommand(logsCmd)
 
 	// Claude hook subcommand - handles Claude Code hook callbacks (internal use)
@@ -421,6 +535,7 @@ Tasks will automatically reconnect to their agent sessions when viewed.`,
 	}
 	claudeHookCmd.Flags().String("event", "", "Hook event type (Notification, Stop, etc.)")
 	rootCmd.AddCommand(claudeHookCmd)
+	rootCmd.AddCommand(newAgentHookCmd())
 
 	// Worktree write-guard subcommand - the executor-agnostic transport for the
 	// worktree write-guard used by the codex/gemini/opencode pre-tool hooks. Reads a
@@ -472,17 +587,27 @@ Tasks will automatically reconnect to their agent sessions when viewed.`,
 		Use:   "sessions",
 		Short: "Manage running agent tmux sessions",
 		Run: func(cmd *cobra.Command, args []string) {
-			listSessions()
+			outputJSON, _ := cmd.Flags().GetBool("json")
+			listSessions(outputJSON)
 		},
 	}
+	sessionsCmd.Flags().Bool("json", false, "Output in JSON format")
 
 	sessionsListCmd := &cobra.Command{
 		Use:   "list",
 		Short: "List running agent sessions",
+		Long: `List the agent windows running in the daemon's tmux sessions, with each
+task's executor, memory use and last activity.
+
+Examples:
+  ty sessions list
+  ty sessions list --json | jq '.total_memory_mb'`,
 		Run: func(cmd *cobra.Command, args []string) {
-			listSessions()
+			outputJSON, _ := cmd.Flags().GetBool("json")
+			listSessions(outputJSON)
 		},
 	}
+	sessionsListCmd.Flags().Bool("json", false, "Output in JSON format")
 	sessionsCmd.AddCommand(sessionsListCmd)
 
 	sessionsCleanupCmd := &cobra.Command{
@@ -526,6 +651,9 @@ Examples:
 	}
 	sessionsSuspendCmd.Flags().Bool("all", false, "Suspend all tasks with running sessions, not just blocked ones")
 	sessionsCmd.AddCommand(sessionsSuspendCmd)
+	sessionsCmd.AddCommand(newSessionsTopCmd())
+	sessionsCmd.AddCommand(newSessionsAttachCmd())
+	sessionsCmd.AddCommand(newSessionsKillCmd())
 
 	rootCmd.AddCommand(sessionsCmd)
 
@@ -552,7 +680,7 @@ Examples:
 		Short:  "Alias for 'sessions' (deprecated, use 'sessions' instead)",
 		Hidden: true, // Hide from help but still works
 		Run: func(cmd *cobra.Command, args []string) {
-			listSessions()
+			listSessions(false)
 		},
 	}
 	rootCmd.AddCommand(claudesCmd)
@@ -610,7 +738,7 @@ Examples:
 					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
 					os.Exit(1)
 				}
-				fmt.Println(successStyle.Render(fmt.Sprintf("Permanently deleted task #%d", taskID)))
+				outln(successStyle.Render(fmt.Sprintf("Permanently deleted task #%d", taskID)))
 				return
 			}
 
@@ -618,7 +746,7 @@ Examples:
 				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
 				os.Exit(1)
 			}
-			fmt.Println(successStyle.Render(fmt.Sprintf("Trashed task #%d — restore with 'task restore %d'", taskID, taskID)))
+			outln(successStyle.Render(fmt.Sprintf("Trashed task #%d — restore with 'task restore %d'", taskID, taskID)))
 		},
 	}
 	deleteCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
@@ -647,7 +775,7 @@ Examples:
 				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
 				os.Exit(1)
 			}
-			fmt.Println(successStyle.Render(fmt.Sprintf("Restored task #%d", taskID)))
+			outln(successStyle.Render(fmt.Sprintf("Restored task #%d", taskID)))
 		},
 	}
 	rootCmd.AddCommand(restoreCmd)
@@ -695,9 +823,35 @@ Examples:
   task create "Add dark mode" --type code --project myapp
   task create "Write documentation" --body "Document the API endpoints" --execute
   task create "Refactor auth" --executor codex  # Use Codex instead of Claude
+  task create "Refactor auth" -e codex --executor-arg model=o3 --executor-arg reasoning-effort=high
   task create "Urgent bug" --tags "bug,urgent" --pinned  # Tagged and pinned task
+  task create "Hotfix" --priority 10 --execute  # Runs ahead of lower-priority queued tasks
   task create --body "The login button is broken on mobile devices" # AI generates title
-  task create "QA: PR #2526" --branch fix/ui-overflow --project myapp  # Checkout existing branch`,
+  task create "Plan the migration" --body-file notes.md  # Long body from a file
+  pbpaste | task create --body-file -  # Body from stdin (or a heredoc); AI generates title
+  task create "QA: PR #2526" --branch fix/ui-overflow --project myapp  # Checkout existing branch
+  task create --from-pr https://github.com/o/r/pull/42 --project myapp  # Review an existing PR
+  cat ideas.txt | task create --stdin --project inbox  # One task per line
+  task create --template qa-pr --arg pr=2526  # Pre-filled from a saved template (see: ty templates)
+  task create "Deploy" --depends-on 12 --depends-on 13 --auto-queue  # Runs once #12 and #13 are done
+
+With --stdin, every non-empty line becomes a task title. A line containing only
+"---" starts a body for the title above it; the body runs until the next blank
+line. The other flags (--project, --type, --tags, --execute, ...) apply to every
+task created.
+
+With --depends-on, the new task is blocked by the given tasks (like 'ty block')
+and starts out blocked while any of them is unfinished. When they are all done
+it moves to backlog, or to the queue with --auto-queue (or --execute).
+
+--executor-arg passes an extra flag to the executor's CLI. Only a few keys are
+accepted per executor, and values are quoted, never interpreted by the shell:
+  claude    model, effort (--model/--effort win when both are given)
+  codex     model, profile, reasoning-effort (minimal, low, medium, high)
+  gemini    model
+  pi        model, provider, thinking (off, minimal, low, medium, high, xhigh)
+  opencode  model
+  openclaw  thinking (off, minimal, low, medium, high)`,
 		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			var title string
@@ -706,29 +860,48 @@ Examples:
 			}
 			body, _ := cmd.Flags().GetString("body")
 			body = unescapeNewlines(body) // Convert literal \n to actual newlines
+			if bodyFile, _ := cmd.Flags().GetString("body-file"); bodyFile != "" {
+				var err error
+				if body, err = readBodyFile(bodyFile, os.Stdin); err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+			}
 			taskType, _ := cmd.Flags().GetString("type")
 			project, _ := cmd.Flags().GetString("project")
 			taskExecutor, _ := cmd.Flags().GetString("executor")
 			effortLevel, _ := cmd.Flags().GetString("effort")
 			modelOverride, _ := cmd.Flags().GetString("model")
+			executorArgPairs, _ := cmd.Flags().GetStringArray("executor-arg")
 			execute, _ := cmd.Flags().GetBool("execute")
 			createDangerous, _ := cmd.Flags().GetBool("dangerous")
 			permissionModeFlag, _ := cmd.Flags().GetString("permission-mode")
 			tags, _ := cmd.Flags().GetString("tags")
 			pinned, _ := cmd.Flags().GetBool("pinned")
+			noAutoRetry, _ := cmd.Flags().GetBool("no-auto-retry")
+			priority, _ := cmd.Flags().GetInt("priority")
 			remoteControl, _ := cmd.Flags().GetBool("remote-control")
 			branch, _ := cmd.Flags().GetString("branch")
+			fromPR, _ := cmd.Flags().GetString("from-pr")
 			outputJSON, _ := cmd.Flags().GetBool("json")
+			fromStdin, _ := cmd.Flags().GetBool("stdin")
 
-			// Validate that either title or body is provided
-			if strings.TrimSpace(title) == "" && strings.TrimSpace(body) == "" {
-				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: either title or --body must be provided"))
+			templateName, _ := cmd.Flags().GetString("template")
+			templateArgPairs, _ := cmd.Flags().GetStringArray("arg")
+			dependsOn, _ := cmd.Flags().GetInt64Slice("depends-on")
+			autoQueue, _ := cmd.Flags().GetBool("auto-queue")
+
+			if fromStdin && (title != "" || body != "" || fromPR != "" || branch != "" || templateName != "" || len(dependsOn) > 0) {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: --stdin cannot be combined with a title, --body, --body-file, --branch, --from-pr, --template or --depends-on"))
 				os.Exit(1)
 			}
-
-			// Set defaults
-			if taskType == "" {
-				taskType = db.TypeCode
+			if autoQueue && len(dependsOn) == 0 {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: --auto-queue requires --depends-on"))
+				os.Exit(1)
+			}
+			if len(templateArgPairs) > 0 && templateName == "" {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: --arg requires --template"))
+				os.Exit(1)
 			}
 
 			// Open database
@@ -740,6 +913,67 @@ Examples:
 			}
 			defer database.Close()
 
+			// --template pre-fills anything not given explicitly on the command line.
+			if templateName != "" {
+				templateArgs, err := parseTemplateArgs(templateArgPairs)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+				tmpl, err := instantiateTemplate(database, templateName, templateArgs)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+				if title == "" {
+					title = tmpl.Title
+				}
+				if body == "" {
+					body = tmpl.Body
+				}
+				if taskType == "" {
+					taskType = tmpl.Type
+				}
+				if taskExecutor == "" {
+					taskExecutor = tmpl.Executor
+				}
+				if tags == "" {
+					tags = tmpl.Tags
+				}
+				if project == "" {
+					project = tmpl.Project
+				}
+			}
+
+			// --from-pr seeds title, body and branch from an existing pull request.
+			// Explicit title/--body/--branch still win over the PR's values.
+			var prDetails *github.PRDetails
+			if fromPR != "" {
+				ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+				details, err := github.FetchPRDetails(ctx, fromPR)
+				cancel()
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+				prDetails = details
+				if strings.TrimSpace(title) == "" {
+					title = fmt.Sprintf("Review PR #%d: %s", prDetails.Number, prDetails.Title)
+				}
+				if strings.TrimSpace(body) == "" {
+					body = fmt.Sprintf("Review pull request #%d (%s).\n\n%s", prDetails.Number, prDetails.URL, strings.TrimSpace(prDetails.Body))
+				}
+				if branch == "" {
+					branch = prBranchName(prDetails)
+				}
+			}
+
+			// Validate that either title or body is provided
+			if !fromStdin && strings.TrimSpace(title) == "" && strings.TrimSpace(body) == "" {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: either title or --body must be provided"))
+				os.Exit(1)
+			}
+
 			// Validate task type against database types
 			if taskType == "" {
 				taskType = db.TypeCode // Default to code if not specified
@@ -763,19 +997,9 @@ Examples:
 			}
 
 			// Validate executor if provided
-			validExecutors := []string{db.ExecutorClaude, db.ExecutorCodex, db.ExecutorGemini, db.ExecutorPi, db.ExecutorOpenCode, db.ExecutorOpenClaw}
-			if taskExecutor != "" {
-				validExecutor := false
-				for _, e := range validExecutors {
-					if e == taskExecutor {
-						validExecutor = true
-						break
-					}
-				}
-				if !validExecutor {
-					fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid executor. Must be one of: "+strings.Join(validExecutors, ", ")))
-					os.Exit(1)
-				}
+			if !executor.IsValidExecutor(taskExecutor) {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid executor. Must be one of: "+strings.Join(executor.ExecutorNames(), ", ")))
+				os.Exit(1)
 			}
 
 			// Validate effort level if provided (empty = use Claude's global default)
@@ -785,9 +1009,13 @@ Examples:
 				os.Exit(1)
 			}
 
-			// Normalize model override (empty = use Claude's global default). Any
-			// non-empty value is accepted; the Claude CLI validates the model name.
+			// Validate model override (empty = the project's default model, then
+			// the default_model setting). custom:<id> skips the known-model check.
 			modelOverride = strings.TrimSpace(modelOverride)
+			if !db.IsValidModel(modelOverride) {
+				fmt.Fprintln(os.Stderr, errorStyle.Render(invalidModelMessage))
+				os.Exit(1)
+			}
 
 			// If project not specified, try to detect from cwd
 			if project == "" {
@@ -798,6 +1026,13 @@ Examples:
 				}
 			}
 
+			// Validate --executor-arg against the executor the task will run with
+			executorArgs, err := parseExecutorArgs(database, executorArgPairs, taskExecutor, project)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
 			// Generate title from body if title is empty
 			if strings.TrimSpace(title) == "" && strings.TrimSpace(body) != "" {
 				var apiKey string
@@ -808,7 +1043,7 @@ Examples:
 					if generatedTitle, genErr := svc.GenerateTitle(ctx, body, project); genErr == nil && generatedTitle != "" {
 						title = generatedTitle
 						if !outputJSON {
-							fmt.Println(dimStyle.Render("Generated title: " + title))
+							infoln(dimStyle.Render("Generated title: " + title))
 						}
 					}
 					cancel()
@@ -823,11 +1058,26 @@ Examples:
 				}
 			}
 
+			// Check blockers before creating anything, so a typo'd ID doesn't leave
+			// a half-set-up task behind.
+			blockersOpen, err := checkCreateBlockers(database, dependsOn)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
 			// Set initial status
 			status := db.StatusBacklog
+			blockedReason := ""
 			if execute {
 				status = db.StatusQueued
 			}
+			if blockersOpen {
+				// Wait in the DAG; --execute means "run it", so it implies auto-queue.
+				status = db.StatusBlocked
+				blockedReason = db.BlockedDependency
+				autoQueue = autoQueue || execute
+			}
 
 			// Resolve permission mode: an explicit --permission-mode wins, then the
 			// legacy --dangerous flag; empty inherits the project default in CreateTask.
@@ -836,18 +1086,68 @@ Examples:
 				permMode = db.PermissionModeDangerous
 			}
 
+			if fromStdin {
+				template := db.Task{
+					Status:         status,
+					BlockedReason:  blockedReason,
+					Type:           taskType,
+					Project:        project,
+					Executor:       taskExecutor,
+					EffortLevel:    effortLevel,
+					Model:          modelOverride,
+					ExecutorArgs:   executorArgs,
+					Tags:           tags,
+					Pinned:         pinned,
+					Priority:       priority,
+					PermissionMode: permMode,
+					RemoteControl:  remoteControl,
+				}
+				createdTasks := []map[string]interface{}{}
+				count, err := createTasksFromStdin(database, os.Stdin, template, func(t *db.Task) {
+					if noAutoRetry {
+						if err := database.SetNoAutoRetry(t.ID, true); err != nil {
+							fmt.Fprintln(os.Stderr, warnStyle.Render("Warning: "+err.Error()))
+						}
+					}
+					if outputJSON {
+						createdTasks = append(createdTasks, map[string]interface{}{"id": t.ID, "title": t.Title})
+						return
+					}
+					if quiet {
+						fmt.Println(t.ID)
+						return
+					}
+					outln(successStyle.Render(fmt.Sprintf("Created task #%d: %s", t.ID, t.Title)))
+				})
+				if outputJSON {
+					jsonBytes, _ := json.Marshal(createdTasks)
+					fmt.Println(string(jsonBytes))
+				}
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+				if !outputJSON {
+					outln(dimStyle.Render(fmt.Sprintf("%d tasks created", count)))
+				}
+				return
+			}
+
 			// Create the task
 			task := &db.Task{
 				Title:          title,
 				Body:           body,
 				Status:         status,
+				BlockedReason:  blockedReason,
 				Type:           taskType,
 				Project:        project,
 				Executor:       taskExecutor,
 				EffortLevel:    effortLevel,
 				Model:          modelOverride,
+				ExecutorArgs:   executorArgs,
 				Tags:           tags,
 				Pinned:         pinned,
+				Priority:       priority,
 				SourceBranch:   branch,
 				PermissionMode: permMode,
 				RemoteControl:  remoteControl,
@@ -857,6 +1157,34 @@ Examples:
 				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
 				os.Exit(1)
 			}
+			if err := addCreateDependencies(database, task, dependsOn, autoQueue); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if noAutoRetry {
+				if err := database.SetNoAutoRetry(task.ID, true); err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+			}
+
+			if prDetails != nil {
+				// Make the PR branch resolvable when the executor builds the worktree
+				// (skipped if --branch overrode it), then link the PR so its status
+				// shows on the task right away.
+				if task.SourceBranch == prBranchName(prDetails) {
+					projectDir := config.New(database).GetProjectDir(task.Project)
+					if err := fetchPRBranch(projectDir, prDetails); err != nil {
+						fmt.Fprintln(os.Stderr, warnStyle.Render("Warning: could not fetch PR branch: "+err.Error()))
+					}
+				}
+				if err := database.UpdateTaskPRInfo(task.ID, prDetails.URL, prDetails.Number, ""); err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+				task.PRURL = prDetails.URL
+				task.PRNumber = prDetails.Number
+			}
 
 			if outputJSON {
 				output := map[string]interface{}{
@@ -876,47 +1204,93 @@ Examples:
 				if task.Model != "" {
 					output["model"] = task.Model
 				}
+				if task.BlockedReason != "" {
+					output["blocked_reason"] = task.BlockedReason
+				}
+				if args := task.ExecutorArgMap(); len(args) > 0 {
+					output["executor_args"] = args
+				}
+				if task.Priority != 0 {
+					output["priority"] = task.Priority
+				}
+				if task.PRURL != "" {
+					output["pr_url"] = task.PRURL
+					output["pr_number"] = task.PRNumber
+				}
+				if len(dependsOn) > 0 {
+					output["blocked_by"] = dependsOn
+					output["auto_queue"] = autoQueue
+				}
 				jsonBytes, _ := json.Marshal(output)
 				fmt.Println(string(jsonBytes))
+			} else if quiet {
+				fmt.Println(task.ID)
 			} else {
 				msg := fmt.Sprintf("Created task #%d: %s", task.ID, task.Title)
 				if branch != "" {
 					msg += fmt.Sprintf(" (branch: %s)", branch)
 				}
-				if execute {
+				if execute && !blockersOpen {
 					if createDangerous {
 						msg += " (queued for execution in dangerous mode)"
 					} else {
 						msg += " (queued for execution)"
 					}
 				}
-				fmt.Println(successStyle.Render(msg))
+				outln(successStyle.Render(msg))
+				if len(dependsOn) > 0 {
+					refs := make([]string, len(dependsOn))
+					for i, id := range dependsOn {
+						refs[i] = fmt.Sprintf("#%d", id)
+					}
+					depMsg := "Blocked by " + strings.Join(refs, ", ")
+					if autoQueue {
+						depMsg += " (will auto-queue when unblocked)"
+					}
+					if !blockersOpen {
+						depMsg += "; already done"
+					}
+					fmt.Println(dimStyle.Render(depMsg))
+				}
 			}
 		},
 	}
 	createCmd.Flags().String("body", "", "Task body/description (if no title, AI generates from body)")
+	createCmd.Flags().String("body-file", "", "Read the task body from a file (- for stdin), verbatim")
+	createCmd.MarkFlagsMutuallyExclusive("body", "body-file")
 	createCmd.Flags().StringP("type", "t", "", "Task type: code, writing, thinking (default: code)")
 	createCmd.Flags().StringP("project", "p", "", "Project name (auto-detected from cwd if not specified)")
-	createCmd.Flags().StringP("executor", "e", "", "Task executor: claude, codex, gemini, pi, opencode, openclaw (default: claude)")
+	createCmd.Flags().StringP("executor", "e", "", "Task executor: claude, codex, gemini, pi, opencode, openclaw (default: the project's default executor, else claude)")
 	createCmd.Flags().String("effort", "", "Per-task Claude effort override: low, medium, high, xhigh, max (default: Claude's global default)")
-	createCmd.Flags().String("model", "", "Per-task Claude model override: opus, sonnet, haiku, or a full model name (default: Claude's global default)")
+	createCmd.Flags().String("model", "", "Per-task Claude model override: opus, sonnet, haiku, fable, a full claude-* model ID, or custom:<id> (default: the project's default model, else the default_model setting)")
+	createCmd.Flags().StringArray("executor-arg", nil, "Extra executor flag as key=value, repeatable (e.g. model=o3 for codex); keys are checked per executor")
 	createCmd.Flags().BoolP("execute", "x", false, "Queue task for immediate execution")
 	createCmd.Flags().Bool("dangerous", false, "Execute in dangerous mode (alias for --permission-mode dangerous)")
 	createCmd.Flags().String("permission-mode", "", "Permission mode: default (prompt), accept-edits (auto-accept file edits), auto (Claude Code auto mode: auto-approve safe actions, block risky ones), dangerous (skip all). Defaults to the project's setting")
 	createCmd.Flags().String("tags", "", "Task tags (comma-separated)")
 	createCmd.Flags().Bool("pinned", false, "Pin the task to the top of its column")
+	createCmd.Flags().Bool("no-auto-retry", false, "Never retry this task automatically when it fails (see the max_retries setting)")
+	createCmd.Flags().Int("priority", 0, "Queue priority: higher-priority queued tasks run first (default 0)")
 	createCmd.Flags().Bool("remote-control", false, "Launch Claude with --remote-control (interactive, remote-drivable session)")
 	createCmd.Flags().StringP("branch", "b", "", "Existing branch to checkout for worktree (e.g., fix/ui-overflow)")
+	createCmd.Flags().String("from-pr", "", "Seed title, body and branch from a GitHub pull request URL and link the PR (uses gh)")
 	createCmd.Flags().Bool("json", false, "Output in JSON format")
+	createCmd.Flags().Bool("stdin", false, "Create one task per line read from stdin (\"---\" starts a body for the line above)")
+	createCmd.Flags().String("template", "", "Pre-fill the task from a saved template (see: ty templates)")
+	createCmd.Flags().StringArray("arg", nil, "Template placeholder value as key=value (repeatable)")
+	createCmd.Flags().Int64Slice("depends-on", nil, "ID of a task that must finish first (repeatable or comma-separated)")
+	createCmd.Flags().Bool("auto-queue", false, "With --depends-on, queue the task automatically once its blockers are done")
+	createCmd.RegisterFlagCompletionFunc("template", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return templateNameCompletions(), cobra.ShellCompDirectiveNoFileComp
+	})
 	createCmd.RegisterFlagCompletionFunc("project", completeFlagProjects)
 	createCmd.RegisterFlagCompletionFunc("type", completeFlagTypes)
 	createCmd.RegisterFlagCompletionFunc("executor", completeFlagExecutors)
+	createCmd.RegisterFlagCompletionFunc("executor-arg", completeExecutorArgKeys)
 	createCmd.RegisterFlagCompletionFunc("effort", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return db.EffortLevels(), cobra.ShellCompDirectiveNoFileComp
 	})
-	createCmd.RegisterFlagCompletionFunc("model", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return db.ModelOptions(), cobra.ShellCompDirectiveNoFileComp
-	})
+	createCmd.RegisterFlagCompletionFunc("model", completeFlagModels)
 	rootCmd.AddCommand(createCmd)
 
 	// Pipeline subcommand - create a multi-phase pipeline task
@@ -1047,7 +1421,7 @@ Examples:
 					fmt.Println(string(jsonBytes))
 					return
 				}
-				fmt.Println(successStyle.Render(fmt.Sprintf("Created %s task #%d (%s)", result.Definition.Name, t.ID, t.Status)))
+				outln(successStyle.Render(fmt.Sprintf("Created %s task #%d (%s)", result.Definition.Name, t.ID, t.Status)))
 				if noExecute {
 					fmt.Println(dimStyle.Render("Staged but not started — queue it to run."))
 				}
@@ -1077,7 +1451,7 @@ Examples:
 				return
 			}
 
-			fmt.Println(successStyle.Render(fmt.Sprintf("Created %s workflow on branch %s", result.Definition.Name, result.Branch)))
+			outln(successStyle.Render(fmt.Sprintf("Created %s workflow on branch %s", result.Definition.Name, result.Branch)))
 			for i, t := range result.Tasks {
 				s := result.Definition.Steps[i]
 				model := s.Model
@@ -1150,7 +1524,7 @@ Examples:
 			defer database.Close()
 			apiKey, _ := database.GetSetting("anthropic_api_key")
 
-			fmt.Fprintln(os.Stderr, dimStyle.Render("Designing workflow…"))
+			infoln(dimStyle.Render("Designing workflow…"))
 			ctx, cancel := context.WithTimeout(context.Background(), 70*time.Second)
 			defer cancel()
 			def, yamlBytes, err := pipeline.GenerateDefinition(ctx, apiKey, desc)
@@ -1179,7 +1553,7 @@ Examples:
 				os.Exit(1)
 			}
 
-			fmt.Println(successStyle.Render("Created workflow '" + def.Name + "'"))
+			outln(successStyle.Render("Created workflow '" + def.Name + "'"))
 			for _, s := range def.Steps {
 				dep := ""
 				if len(s.Deps) > 0 {
@@ -1251,7 +1625,7 @@ Examples:
 				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
 				os.Exit(1)
 			}
-			fmt.Println(successStyle.Render("Wrote " + def.Name + " to " + path))
+			outln(successStyle.Render("Wrote " + def.Name + " to " + path))
 			fmt.Println(dimStyle.Render("Edit it, then run: task pipeline \"<goal>\" --definition " + def.Name))
 		},
 	}
@@ -1271,16 +1645,65 @@ Examples:
   task list --status queued
   task list --project myapp
   task list --pr           # Show PR/CI status
-  task list --all --json`,
+  task list --assignee me  # Only tasks assigned to you
+  task list --tag bug --tag ui  # Tasks tagged both bug and ui
+  task list --all --json
+  task list --format oneline
+  task list --format csv > tasks.csv
+  task list --format go-template --template '{{.id}}\t{{.status}}\t{{.title}}'
+  task list --count --status blocked
+  task list --blocked-reason error      # Blocked tasks that actually failed
+  task list --all --count-by project
+  task list --since 7d                  # Created (or finished) in the last week
+  task list --status done --since 2026-01-05 --until 2026-01-12
+
+--since/--until take a date, an RFC3339 timestamp, or a duration ago (24h,
+7d, 2w). Done tasks match on when they finished, others on when they were
+created; a window also includes done tasks without --all.
+
+--format is table (the default), csv or tsv (the --json fields, with a header
+row), go-template, a preset (oneline, wide), or an inline template. With
+go-template, --template is a Go text/template executed once per task; a
+misspelled field is reported before anything is printed. Fields:
+` + listFormatFields + `
+Template functions: truncate N STRING, date LAYOUT TIMESTAMP.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			status, _ := cmd.Flags().GetString("status")
 			project, _ := cmd.Flags().GetString("project")
 			taskType, _ := cmd.Flags().GetString("type")
-			tag, _ := cmd.Flags().GetString("tag")
+			tags, _ := cmd.Flags().GetStringArray("tag")
+			assignee, _ := cmd.Flags().GetString("assignee")
 			all, _ := cmd.Flags().GetBool("all")
 			limit, _ := cmd.Flags().GetInt("limit")
 			outputJSON, _ := cmd.Flags().GetBool("json")
 			showPR, _ := cmd.Flags().GetBool("pr")
+			refreshPR, _ := cmd.Flags().GetBool("refresh")
+			format, _ := cmd.Flags().GetString("format")
+			templateText, _ := cmd.Flags().GetString("template")
+			countOnly, _ := cmd.Flags().GetBool("count")
+			countBy, _ := cmd.Flags().GetString("count-by")
+			sinceStr, _ := cmd.Flags().GetString("since")
+			untilStr, _ := cmd.Flags().GetString("until")
+			blockedReason, _ := cmd.Flags().GetString("blocked-reason")
+
+			if blockedReason != "" && !db.IsValidBlockedReason(blockedReason) {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid blocked reason. Must be one of: "+strings.Join(db.BlockedReasons(), ", ")))
+				os.Exit(1)
+			}
+
+			since, until, err := parseTimeWindow(sinceStr, untilStr, time.Now())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			// Parse the template before touching the database so a syntax
+			// error fails fast.
+			format, formatTmpl, err := resolveListFormat(format, templateText)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
 
 			// Open database
 			dbPath := db.DefaultPath()
@@ -1296,12 +1719,59 @@ Examples:
 
 			opts := db.ListTasksOptions{
 				Status:        status,
+				BlockedReason: blockedReason,
 				Project:       project,
 				Type:          taskType,
-				Tag:           tag,
+				Tags:          tags,
 				Limit:         limit,
-				IncludeClosed: all,
+				IncludeClosed: all || !since.IsZero() || !until.IsZero(),
+				Since:         since,
+				Until:         until,
+			}
+			switch assignee {
+			case "":
+			case "none":
+				opts.Unassigned = true
+			default:
+				opts.Assignee = resolveAssignee(assignee)
+			}
+
+			// --count/--count-by answer with COUNT(*)/GROUP BY, so --limit doesn't
+			// apply. The workflow split has no SQL form; count those in Go.
+			if countOnly || countBy != "" {
+				var counts []db.TaskCount
+				total := 0
+				if onlyWorkflows || noWorkflows {
+					opts.Limit = 100000
+					var tasks []*db.Task
+					tasks, err = database.ListTasks(opts)
+					var filtered []*db.Task
+					for _, t := range tasks {
+						if pipeline.IsWorkflowTask(t) == onlyWorkflows {
+							filtered = append(filtered, t)
+						}
+					}
+					total = len(filtered)
+					if err == nil && countBy != "" {
+						counts, err = countTaskList(filtered, countBy)
+					}
+				} else if countBy != "" {
+					counts, err = database.CountTasksBy(opts, countBy)
+				} else {
+					total, err = database.CountTasks(opts)
+				}
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+				if countBy != "" {
+					printTaskCounts(counts, countBy, outputJSON)
+				} else {
+					printTaskCount(total, outputJSON)
+				}
+				return
 			}
+
 			// The workflow split is applied in Go, after the query. Keeping the SQL
 			// LIMIT here would cap the rows BEFORE filtering and silently return far
 			// fewer than asked for, so widen the fetch and re-apply the limit below.
@@ -1339,40 +1809,41 @@ Examples:
 			if showPR {
 				prCache = github.NewPRCache()
 				cfg = config.New(database)
+				ttl := prCacheTTL(database)
 				for _, t := range tasks {
 					if t.BranchName != "" {
-						repoDir := t.WorktreePath
-						if repoDir == "" {
-							repoDir = cfg.GetProjectDir(t.Project)
-						}
-						if prInfo := prCache.GetPRForBranch(repoDir, t.BranchName); prInfo != nil {
+						if prInfo := lookupTaskPR(database, cfg, prCache, t, ttl, refreshPR); prInfo != nil {
 							prInfoMap[t.ID] = prInfo
 						}
 					}
 				}
 			}
 
-			if outputJSON {
+			// Tasks created by a schedule ('ty schedule') get a clock icon.
+			taskIDs := make([]int64, len(tasks))
+			for i, t := range tasks {
+				taskIDs[i] = t.ID
+			}
+			scheduledIDs, _ := database.ScheduledTaskIDs(taskIDs)
+
+			if format == listFormatCSV || format == listFormatTSV {
+				comma := ','
+				if format == listFormatTSV {
+					comma = '\t'
+				}
+				if err := renderListDelimited(os.Stdout, comma, tasks, scheduledIDs, showPR, prInfoMap); err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+			} else if formatTmpl != nil {
+				if err := renderListFormat(os.Stdout, formatTmpl, tasks, scheduledIDs, prInfoMap); err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+			} else if outputJSON {
 				var output []map[string]interface{}
 				for _, t := range tasks {
-					item := map[string]interface{}{
-						"id":         t.ID,
-						"title":      t.Title,
-						"status":     t.Status,
-						"type":       t.Type,
-						"project":    t.Project,
-						"created_at": t.CreatedAt.Time.Format(time.RFC3339),
-					}
-					// Add PR info to JSON output if available
-					if prInfo, ok := prInfoMap[t.ID]; ok {
-						item["pr"] = map[string]interface{}{
-							"number":      prInfo.Number,
-							"url":         prInfo.URL,
-							"state":       string(prInfo.State),
-							"check_state": string(prInfo.CheckState),
-							"description": prInfo.StatusDescription(),
-						}
-					}
+					item := listTaskFields(t, scheduledIDs[t.ID], prInfoMap[t.ID], false)
 					output = append(output, item)
 				}
 				jsonBytes, _ := json.Marshal(output)
@@ -1443,31 +1914,60 @@ Examples:
 				for _, t := range tasks {
 					id := dimStyle.Render(fmt.Sprintf("#%-4d", t.ID))
 					status := statusStyle(t.Status).Render(fmt.Sprintf("%-10s", t.Status))
+					if reason := formatBlockedReason(t.BlockedReason); reason != "" {
+						status += " " + reason
+					}
 					project := ""
 					if t.Project != "" {
 						project = dimStyle.Render(fmt.Sprintf("[%s] ", t.Project))
 					}
+					priority := ""
+					if t.Priority != 0 {
+						priority = warnStyle.Render(fmt.Sprintf("P%d ", t.Priority))
+					}
 					// Schedule indicator
+					scheduled := ""
+					if scheduledIDs[t.ID] {
+						scheduled = "⏰ "
+					}
 					prStatus := ""
 					if showPR {
 						prStatus = prStatusStyle(prInfoMap[t.ID])
 					}
-					fmt.Printf("%s %s %s%s%s\n", id, status, project, t.Title, prStatus)
+					fmt.Printf("%s %s %s%s%s%s%s\n", id, status, project, priority, scheduled, t.Title, prStatus)
 				}
 			}
 		},
 	}
 	listCmd.Flags().StringP("status", "s", "", "Filter by status: backlog, queued, processing, blocked, done")
+	listCmd.Flags().String("blocked-reason", "", "Only blocked tasks with this reason: "+strings.Join(db.BlockedReasons(), ", "))
+	listCmd.RegisterFlagCompletionFunc("blocked-reason", completeFlagBlockedReasons)
 	listCmd.Flags().StringP("project", "p", "", "Filter by project")
 	listCmd.Flags().StringP("type", "t", "", "Filter by type: code, writing, thinking")
-	listCmd.Flags().String("tag", "", "Filter by tag (exact match, e.g. gm:cortex)")
+	listCmd.Flags().StringArray("tag", nil, "Filter by tag (whole tag, case-insensitive, e.g. gm:cortex); repeat to require several")
+	listCmd.Flags().String("assignee", "", "Filter by assignee (\"me\" for yourself, \"none\" for unassigned)")
 	listCmd.Flags().BoolP("all", "a", false, "Include completed tasks")
 	listCmd.Flags().IntP("limit", "n", 50, "Maximum number of tasks to return")
+	listCmd.Flags().String("since", "", "Only tasks created (done: completed) at or after this date/time or duration ago (e.g. 7d)")
+	listCmd.Flags().String("until", "", "Only tasks created (done: completed) before this date/time or duration ago")
 	listCmd.Flags().Bool("json", false, "Output in JSON format")
-	listCmd.Flags().Bool("pr", false, "Show PR/CI status (requires network)")
+	listCmd.Flags().Bool("pr", false, "Show PR/CI status (cached for pr_cache_ttl, default 60s)")
+	listCmd.Flags().Bool("refresh", false, "With --pr, fetch PR status live instead of using the cache")
 	listCmd.Flags().Bool("workflows", false, "Only workflow (pipeline) step tasks")
 	listCmd.Flags().Bool("no-workflows", false, "Exclude workflow step tasks (only standalone tasks)")
+	listCmd.Flags().String("format", "", "Output format: table, csv, tsv, go-template (with --template), oneline, wide, or an inline Go template")
+	listCmd.Flags().String("template", "", "Go template rendered per task with --format go-template (e.g. '{{.id}} {{.title}}')")
 	listCmd.MarkFlagsMutuallyExclusive("workflows", "no-workflows")
+	listCmd.Flags().Bool("count", false, "Print the number of matching tasks instead of listing them")
+	listCmd.Flags().String("count-by", "", "Print counts of matching tasks grouped by: "+strings.Join(db.TaskCountFields(), ", "))
+	listCmd.MarkFlagsMutuallyExclusive("format", "json")
+	listCmd.MarkFlagsMutuallyExclusive("count", "count-by")
+	listCmd.MarkFlagsMutuallyExclusive("count", "format")
+	listCmd.MarkFlagsMutuallyExclusive("count-by", "format")
+	listCmd.MarkFlagsMutuallyExclusive("template", "json")
+	listCmd.MarkFlagsMutuallyExclusive("count", "template")
+	listCmd.MarkFlagsMutuallyExclusive("count-by", "template")
+	listCmd.RegisterFlagCompletionFunc("format", completeListFormats)
 	listCmd.RegisterFlagCompletionFunc("status", completeFlagStatuses)
 	listCmd.RegisterFlagCompletionFunc("project", completeFlagProjects)
 	listCmd.RegisterFlagCompletionFunc("type", completeFlagTypes)
@@ -1477,10 +1977,44 @@ Examples:
 		Use:   "board",
 		Short: "Show the Kanban board in the CLI",
 		Long: `Print the same Backlog / Queued / In Progress / Blocked / Done view
-that the TUI shows, either as formatted text or JSON for automation.`,
+that the TUI shows, either as formatted text or JSON for automation.
+
+--since/--until limit the board to tasks created (done tasks: completed)
+inside the window. They take a date, an RFC3339 timestamp, or a duration ago
+(24h, 7d, 2w).
+
+Projects with a WIP limit ('ty projects update <name> --wip-limit N') show
+their in-progress count against it in the In Progress header.
+
+With --digest, print an activity digest instead: the tasks created, started,
+blocked and completed within the window (default the last 24h), read from the
+event log.
+
+Examples:
+  ty board
+  ty board --json
+  ty board --all           # Include archived tasks
+  ty board --since 7d
+  ty board --project myapp  # In Progress (2/3) with a WIP limit of 3
+  ty board --digest --since 24h
+  ty board --digest --since 7d --json`,
 		Run: func(cmd *cobra.Command, args []string) {
 			outputJSON, _ := cmd.Flags().GetBool("json")
 			limit, _ := cmd.Flags().GetInt("limit")
+			sinceStr, _ := cmd.Flags().GetString("since")
+			untilStr, _ := cmd.Flags().GetString("until")
+			digestMode, _ := cmd.Flags().GetBool("digest")
+			project, _ := cmd.Flags().GetString("project")
+
+			since, until, err := parseTimeWindow(sinceStr, untilStr, time.Now())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if digestMode && !until.IsZero() {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: --digest covers everything since --since; --until is not supported"))
+				os.Exit(1)
+			}
 
 			if limit <= 0 {
 				limit = 5
@@ -1494,13 +2028,65 @@ that the TUI shows, either as formatted text or JSON for automation.`,
 			}
 			defer database.Close()
 
-			tasks, err := database.ListTasks(db.ListTasksOptions{IncludeClosed: true, Limit: 500})
+			if digestMode {
+				if sinceStr == "" {
+					sinceStr = "24h"
+					since = time.Now().Add(-24 * time.Hour)
+				}
+				digest, err := buildBoardDigest(database, since)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+				if outputJSON {
+					data, _ := json.MarshalIndent(digest, "", "  ")
+					fmt.Println(string(data))
+					return
+				}
+				// The digest lists everything by default; --limit caps it only
+				// when given explicitly.
+				digestLimit := 0
+				if cmd.Flags().Changed("limit") {
+					digestLimit = limit
+				}
+				printBoardDigest(digest, sinceStr, digestLimit)
+				return
+			}
+
+			if project != "" {
+				p, err := database.GetProjectByName(project)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+				if p == nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Project '%s' not found", project)))
+					os.Exit(1)
+				}
+				project = p.Name
+			}
+
+			tasks, err := database.ListTasks(db.ListTasksOptions{IncludeClosed: true, Limit: 500, Since: since, Until: until, Project: project})
 			if err != nil {
 				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
 				os.Exit(1)
 			}
 
-			snapshot := web.BuildBoardSnapshot(tasks, limit)
+			buildSnapshot := web.BuildBoardSnapshot
+			if showArchived, _ := cmd.Flags().GetBool("all"); showArchived {
+				buildSnapshot = web.BuildBoardSnapshotWithArchived
+			}
+			snapshot := buildSnapshot(tasks, limit)
+			wip, err := boardWIP(database, project)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			for i := range snapshot.Columns {
+				if snapshot.Columns[i].Status == db.StatusProcessing {
+					snapshot.Columns[i].WIP = wip
+				}
+			}
 
 			if outputJSON {
 				data, _ := json.MarshalIndent(snapshot, "", "  ")
@@ -1511,7 +2097,7 @@ that the TUI shows, either as formatted text or JSON for automation.`,
 			fmt.Println(boldStyle.Render("Kanban Snapshot"))
 			fmt.Println(strings.Repeat("─", 50))
 			for _, column := range snapshot.Columns {
-				fmt.Printf("%s (%d)\n", column.Label, column.Count)
+				fmt.Println(boardColumnHeader(column, project))
 				if column.Count == 0 {
 					fmt.Println("  (empty)")
 					fmt.Println()
@@ -1528,6 +2114,12 @@ that the TUI shows, either as formatted text or JSON for automation.`,
 					if task.Pinned {
 						line += " 📌"
 					}
+					if task.Priority != 0 {
+						line += fmt.Sprintf(" P%d", task.Priority)
+					}
+					if reason := formatBlockedReason(task.BlockedReason); reason != "" {
+						line += " " + reason
+					}
 					if task.AgeHint != "" {
 						line += fmt.Sprintf(" • %s", task.AgeHint)
 					}
@@ -1541,7 +2133,13 @@ that the TUI shows, either as formatted text or JSON for automation.`,
 		},
 	}
 	boardCmd.Flags().Bool("json", false, "Output board snapshot as JSON")
+	boardCmd.Flags().Bool("all", false, "Include an Archived column")
 	boardCmd.Flags().Int("limit", 5, "Maximum entries to show per column")
+	boardCmd.Flags().String("since", "", "Only tasks created (done: completed) at or after this date/time or duration ago (e.g. 7d)")
+	boardCmd.Flags().String("until", "", "Only tasks created (done: completed) before this date/time or duration ago")
+	boardCmd.Flags().StringP("project", "p", "", "Only show tasks in this project")
+	boardCmd.RegisterFlagCompletionFunc("project", completeFlagProjects)
+	boardCmd.Flags().Bool("digest", false, "Show tasks created/started/blocked/completed since --since (default 24h) instead")
 	rootCmd.AddCommand(boardCmd)
 
 	// Tail subcommand - live updating task view grouped by project and status
@@ -1586,12 +2184,17 @@ Press Ctrl+C to stop.`,
 		Use:               "show <task-id>",
 		Short:             "Show task details",
 		ValidArgsFunction: completeTaskIDs,
-		Long: `Show detailed information about a task.
+		Long: `Show detailed information about a task, including the tasks it is
+blocked by and the tasks it blocks.
 
 Examples:
   task show 42
   task show 42 --json
-  task show 42 --logs`,
+  task show 42 --logs
+  task show 42 --tree         # Append blockers and dependents
+  task show 42 --tree --deep  # Follow dependencies transitively
+  task show 42 --diff         # Append the git diff of the task's worktree
+  task show 42 --stat-only    # Just the diffstat`,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			var taskID int64
@@ -1602,6 +2205,11 @@ Examples:
 
 			outputJSON, _ := cmd.Flags().GetBool("json")
 			showLogs, _ := cmd.Flags().GetBool("logs")
+			showTree, _ := cmd.Flags().GetBool("tree")
+			deep, _ := cmd.Flags().GetBool("deep")
+			refreshPR, _ := cmd.Flags().GetBool("refresh")
+			showDiff, _ := cmd.Flags().GetBool("diff")
+			statOnly, _ := cmd.Flags().GetBool("stat-only")
 
 			// Open database
 			dbPath := db.DefaultPath()
@@ -1625,13 +2233,41 @@ Examples:
 			// Fetch PR info if task has a branch
 			var prInfo *github.PRInfo
 			if task.BranchName != "" {
-				cfg := config.New(database)
-				repoDir := task.WorktreePath
-				if repoDir == "" {
-					repoDir = cfg.GetProjectDir(task.Project)
+				prInfo = lookupTaskPR(database, config.New(database), github.NewPRCache(), task, prCacheTTL(database), refreshPR)
+			}
+
+			var tree *depTree
+			if showTree || deep {
+				tree, err = buildDepTree(database, task.ID, deep)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+			}
+			blockers, blocks, err := database.GetAllDependencies(task.ID)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			pinnedNotes, _ := database.GetPinnedNotes(task.ID)
+
+			// Blocked tasks are suspended by the daemon after idle_suspend_timeout;
+			// surface when that will happen (or that it already has).
+			var suspend executor.SuspendStatus
+			hasSuspend := false
+			if task.Status == db.StatusBlocked {
+				latest, _ := database.GetLatestLogPerTask([]int64{task.ID})
+				suspend, hasSuspend = executor.IdleSuspendStatus(task, latest[task.ID], executor.SuspendIdleTimeout(database))
+			}
+
+			var diff *taskDiff
+			if showDiff || statOnly {
+				diff, err = computeTaskDiff(database, task, !statOnly, !outputJSON && stdoutIsTerminal())
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
 				}
-				prCache := github.NewPRCache()
-				prInfo = prCache.GetPRForBranch(repoDir, task.BranchName)
 			}
 
 			if outputJSON {
@@ -1651,12 +2287,48 @@ Examples:
 					"created_at":     task.CreatedAt.Time.Format(time.RFC3339),
 					"updated_at":     task.UpdatedAt.Time.Format(time.RFC3339),
 				}
+				if task.Assignee != "" {
+					output["assignee"] = task.Assignee
+				}
+				if task.Priority != 0 {
+					output["priority"] = task.Priority
+				}
+				if task.Model != "" {
+					output["model"] = task.Model
+				}
+				if task.BlockedReason != "" {
+					output["blocked_reason"] = task.BlockedReason
+				}
+				if args := task.ExecutorArgMap(); len(args) > 0 {
+					output["executor_args"] = args
+				}
+				if task.InputTokens != 0 || task.OutputTokens != 0 {
+					output["usage"] = map[string]interface{}{
+						"input_tokens":  task.InputTokens,
+						"output_tokens": task.OutputTokens,
+						"cost_usd":      task.CostUSD,
+					}
+				}
+				if tracked, err := database.TaskTrackedTime(task.ID); err == nil && (tracked.Total() > 0 || tracked.Open) {
+					output["tracked"] = trackedJSON(tracked)
+				}
 				if task.StartedAt != nil {
 					output["started_at"] = task.StartedAt.Time.Format(time.RFC3339)
 				}
 				if task.CompletedAt != nil {
 					output["completed_at"] = task.CompletedAt.Time.Format(time.RFC3339)
 				}
+				if hasSuspend {
+					idle := map[string]interface{}{
+						"suspended": suspend.Suspended,
+						"hint":      suspend.Hint(time.Now()),
+					}
+					if !suspend.Suspended {
+						idle["suspends_at"] = suspend.SuspendsAt.Format(time.RFC3339)
+						idle["remaining_seconds"] = int64(suspend.Remaining(time.Now()).Seconds())
+					}
+					output["idle_suspend"] = idle
+				}
 				// Add PR info to JSON output
 				if prInfo != nil {
 					output["pr"] = map[string]interface{}{
@@ -1668,6 +2340,20 @@ Examples:
 						"mergeable":   prInfo.Mergeable,
 					}
 				}
+				if len(pinnedNotes) > 0 {
+					notes := make([]map[string]interface{}, 0, len(pinnedNotes))
+					for _, n := range pinnedNotes {
+						notes = append(notes, map[string]interface{}{
+							"content":    n.Content,
+							"created_at": n.CreatedAt.Time.Format(time.RFC3339),
+						})
+					}
+					output["pinned_notes"] = notes
+				}
+				output["dependencies"] = directDepsJSON(blockers, blocks)
+				if tree != nil {
+					output["dependency_tree"] = depTreeJSON(tree)
+				}
 				if showLogs {
 					logs, _ := database.GetTaskLogs(taskID, 1000)
 					var logEntries []map[string]interface{}
@@ -1680,6 +2366,9 @@ Examples:
 					}
 					output["logs"] = logEntries
 				}
+				if diff != nil {
+					output["diff"] = diff.json()
+				}
 				jsonBytes, _ := json.MarshalIndent(output, "", "  ")
 				fmt.Println(string(jsonBytes))
 			} else {
@@ -1687,6 +2376,13 @@ Examples:
 				fmt.Printf("%s %s\n", boldStyle.Render(fmt.Sprintf("Task #%d:", task.ID)), task.Title)
 				fmt.Println(strings.Repeat("─", 50))
 
+				for _, n := range pinnedNotes {
+					fmt.Printf("%s %s\n", warnStyle.Render("📌"), n.Content)
+				}
+				if len(pinnedNotes) > 0 {
+					fmt.Println()
+				}
+
 				// Status line
 				statusColor := lipgloss.Color("#6B7280")
 				switch task.Status {
@@ -1699,11 +2395,36 @@ Examples:
 				case db.StatusDone:
 					statusColor = lipgloss.Color("#10B981")
 				}
-				fmt.Printf("Status:   %s\n", lipgloss.NewStyle().Foreground(statusColor).Render(task.Status))
+				statusLine := lipgloss.NewStyle().Foreground(statusColor).Render(task.Status)
+				if reason := formatBlockedReason(task.BlockedReason); reason != "" {
+					statusLine += " " + reason
+				}
+				if hasSuspend {
+					statusLine += " " + dimStyle.Render("("+suspend.Hint(time.Now())+")")
+				}
+				fmt.Printf("Status:   %s\n", statusLine)
 				fmt.Printf("Type:     %s\n", task.Type)
 				if task.Project != "" {
 					fmt.Printf("Project:  %s\n", task.Project)
 				}
+				if args := task.ExecutorArgMap(); len(args) > 0 {
+					fmt.Printf("Executor: %s %s\n", task.Executor, dimStyle.Render(formatExecutorArgs(args)))
+				}
+				if task.Model != "" {
+					fmt.Printf("Model:    %s\n", task.Model)
+				}
+				if task.Assignee != "" {
+					fmt.Printf("Assignee: %s\n", task.Assignee)
+				}
+				if task.Priority != 0 {
+					fmt.Printf("Priority: %d\n", task.Priority)
+				}
+				if task.InputTokens != 0 || task.OutputTokens != 0 {
+					fmt.Printf("Usage:    %s\n", formatUsage(task.InputTokens, task.OutputTokens, task.CostUSD))
+				}
+				if tracked, err := database.TaskTrackedTime(task.ID); err == nil && (tracked.Total() > 0 || tracked.Open) {
+					fmt.Printf("Tracked:  %s\n", formatTrackedTime(tracked))
+				}
 
 				// Timestamps
 				fmt.Printf("Created:  %s\n", task.CreatedAt.Time.Format("2006-01-02 15:04:05"))
@@ -1777,6 +2498,12 @@ Examples:
 					}
 				}
 
+				if tree != nil {
+					printDepTree(tree)
+				} else {
+					printDirectDeps(blockers, blocks)
+				}
+
 				// Logs
 				if showLogs {
 					logs, _ := database.GetTaskLogs(taskID, 100)
@@ -1784,31 +2511,24 @@ Examples:
 						fmt.Println()
 						fmt.Println(boldStyle.Render("Recent Logs:"))
 						for _, l := range logs {
-							ts := dimStyle.Render(l.CreatedAt.Time.Format("15:04:05"))
-							prefix := ""
-							switch l.LineType {
-							case "system":
-								prefix = dimStyle.Render("[system] ")
-							case "error":
-								prefix = errorStyle.Render("[error] ")
-							case "tool":
-								prefix = lipgloss.NewStyle().Foreground(lipgloss.Color("#8B5CF6")).Render("[tool] ")
-							case "question":
-								prefix = lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Render("[question] ")
-							case "output":
-								prefix = dimStyle.Render("[output] ")
-							case "text":
-								prefix = dimStyle.Render("[text] ")
-							}
-							fmt.Printf("%s %s%s\n", ts, prefix, truncate(l.Content, 200))
+							fmt.Println(formatTaskLogLine(l, 200))
 						}
 					}
 				}
+
+				if diff != nil {
+					printTaskDiff(diff)
+				}
 			}
 		},
 	}
 	showCmd.Flags().Bool("json", false, "Output in JSON format")
 	showCmd.Flags().Bool("logs", false, "Show task logs")
+	showCmd.Flags().Bool("tree", false, "Append the task's dependency tree (blockers and dependents)")
+	showCmd.Flags().Bool("deep", false, "With --tree, follow dependencies transitively instead of one hop")
+	showCmd.Flags().Bool("refresh", false, "Fetch PR status live instead of using the cache")
+	showCmd.Flags().Bool("diff", false, "Append the git diff of the task's worktree against its base branch")
+	showCmd.Flags().Bool("stat-only", false, "With --diff, show only the diffstat (implies --diff)")
 	rootCmd.AddCommand(showCmd)
 
 	// Update subcommand - update task fields
@@ -1822,8 +2542,12 @@ Examples:
   task update 42 --title "New title"
   task update 42 --body "Updated description"
   task update 42 --executor codex        # Switch to Codex executor
+  task update 42 --model opus            # Run the task on Opus
+  task update 42 --model ""              # Clear the model override
   task update 42 --tags "bug,urgent"     # Set tags
-  task update 42 --pinned                # Pin the task`,
+  task update 42 --pinned                # Pin the task
+  task update 42 --assignee me           # Assign the task to yourself
+  task update 42 --assignee none         # Unassign (same as --assignee "")`,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			var taskID int64
@@ -1835,11 +2559,27 @@ Examples:
 			title, _ := cmd.Flags().GetString("title")
 			body, _ := cmd.Flags().GetString("body")
 			body = unescapeNewlines(body) // Convert literal \n to actual newlines
+			bodyFile, _ := cmd.Flags().GetString("body-file")
+			if bodyFile != "" {
+				var err error
+				if body, err = readBodyFile(bodyFile, os.Stdin); err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+			}
 			taskType, _ := cmd.Flags().GetString("type")
 			project, _ := cmd.Flags().GetString("project")
 			taskExecutor, _ := cmd.Flags().GetString("executor")
 			tags, _ := cmd.Flags().GetString("tags")
 			pinned, _ := cmd.Flags().GetBool("pinned")
+			assignee, _ := cmd.Flags().GetString("assignee")
+			priority, _ := cmd.Flags().GetInt("priority")
+			model, _ := cmd.Flags().GetString("model")
+			model = strings.TrimSpace(model)
+			if !db.IsValidModel(model) {
+				fmt.Fprintln(os.Stderr, errorStyle.Render(invalidModelMessage))
+				os.Exit(1)
+			}
 
 			// Open database
 			dbPath := db.DefaultPath()
@@ -1872,19 +2612,9 @@ Examples:
 			}
 
 			// Validate executor if provided
-			if taskExecutor != "" {
-				validExecutors := []string{db.ExecutorClaude, db.ExecutorCodex, db.ExecutorGemini, db.ExecutorPi, db.ExecutorOpenCode, db.ExecutorOpenClaw}
-				validExecutor := false
-				for _, e := range validExecutors {
-					if e == taskExecutor {
-						validExecutor = true
-						break
-					}
-				}
-				if !validExecutor {
-					fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid executor. Must be one of: "+strings.Join(validExecutors, ", ")))
-					os.Exit(1)
-				}
+			if !executor.IsValidExecutor(taskExecutor) {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid executor. Must be one of: "+strings.Join(executor.ExecutorNames(), ", ")))
+				os.Exit(1)
 			}
 
 			task, err := database.GetTask(taskID)
@@ -1901,7 +2631,7 @@ Examples:
 			if title != "" {
 				task.Title = title
 			}
-			if cmd.Flags().Changed("body") {
+			if cmd.Flags().Changed("body") || bodyFile != "" {
 				task.Body = body
 			}
 			if taskType != "" {
@@ -1919,25 +2649,40 @@ Examples:
 			if cmd.Flags().Changed("pinned") {
 				task.Pinned = pinned
 			}
+			if cmd.Flags().Changed("assignee") {
+				task.Assignee = resolveAssignee(assignee)
+			}
+			if cmd.Flags().Changed("priority") {
+				task.Priority = priority
+			}
+			if cmd.Flags().Changed("model") {
+				task.Model = model
+			}
 
 			if err := database.UpdateTask(task); err != nil {
 				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
 				os.Exit(1)
 			}
 
-			fmt.Println(successStyle.Render(fmt.Sprintf("Updated task #%d", taskID)))
+			outln(successStyle.Render(fmt.Sprintf("Updated task #%d", taskID)))
 		},
 	}
 	updateCmd.Flags().String("title", "", "Update task title")
 	updateCmd.Flags().String("body", "", "Update task body/description")
+	updateCmd.Flags().String("body-file", "", "Replace the task body with the contents of a file (- for stdin), verbatim")
+	updateCmd.MarkFlagsMutuallyExclusive("body", "body-file")
 	updateCmd.Flags().StringP("type", "t", "", "Update task type: code, writing, thinking")
 	updateCmd.Flags().StringP("project", "p", "", "Update project name")
 	updateCmd.Flags().StringP("executor", "e", "", "Update task executor: claude, codex, gemini, pi, opencode, openclaw")
 	updateCmd.Flags().String("tags", "", "Update task tags (comma-separated)")
 	updateCmd.Flags().Bool("pinned", false, "Pin or unpin the task")
+	updateCmd.Flags().String("assignee", "", "Assign the task (\"me\" for yourself, \"none\" or empty to unassign)")
+	updateCmd.Flags().Int("priority", 0, "Set queue priority (higher runs first; 0 is the default)")
+	updateCmd.Flags().String("model", "", "Update the Claude model override: opus, sonnet, haiku, fable, a full claude-* model ID, or custom:<id> (empty to clear)")
 	updateCmd.RegisterFlagCompletionFunc("project", completeFlagProjects)
 	updateCmd.RegisterFlagCompletionFunc("type", completeFlagTypes)
 	updateCmd.RegisterFlagCompletionFunc("executor", completeFlagExecutors)
+	updateCmd.RegisterFlagCompletionFunc("model", completeFlagModels)
 	rootCmd.AddCommand(updateCmd)
 
 	// Move subcommand - move a task to a different project
@@ -1959,8 +2704,12 @@ The new task resets:
 - Agent session ID, daemon session
 - Started/completed timestamps
 
+Use --dry-run to print the worktree, sessions, and task that would be removed
+and the task that would be created, without changing anything.
+
 Examples:
   task move 42 myapp
+  task move 42 myapp --dry-run
   task move 42 myapp --execute`,
 		Args: cobra.ExactArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -2008,6 +2757,12 @@ Examples:
 				return
 			}
 
+			plan := planMove(database, task, proj.Name)
+			if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+				printMovePlan(os.Stdout, plan)
+				return
+			}
+
 			oldProject := task.Project
 			execute, _ := cmd.Flags().GetBool("execute")
 			moveDangerous, _ := cmd.Flags().GetBool("dangerous")
@@ -2026,13 +2781,13 @@ Examples:
 			}
 
 			// Perform the move
-			newTaskID, err := moveTask(database, task, targetProject)
+			newTaskID, err := executeMovePlan(database, plan)
 			if err != nil {
 				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
 				os.Exit(1)
 			}
 
-			fmt.Println(successStyle.Render(fmt.Sprintf("Moved task #%d to project '%s' (new task #%d)", taskID, targetProject, newTaskID)))
+			outln(successStyle.Render(fmt.Sprintf("Moved task #%d to project '%s' (new task #%d)", taskID, targetProject, newTaskID)))
 
 			// Queue for execution if requested
 			if execute {
@@ -2050,13 +2805,14 @@ Examples:
 				if moveDangerous {
 					msg += " (dangerous mode)"
 				}
-				fmt.Println(successStyle.Render(msg))
+				outln(successStyle.Render(msg))
 			}
 		},
 	}
 	moveCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
 	moveCmd.Flags().BoolP("execute", "e", false, "Queue the task for execution after moving")
 	moveCmd.Flags().Bool("dangerous", false, "Execute in dangerous mode (requires --execute)")
+	moveCmd.Flags().Bool("dry-run", false, "Show what the move would do without changing anything")
 	rootCmd.AddCommand(moveCmd)
 
 	// Execute subcommand - queue a task for execution
@@ -2071,7 +2827,12 @@ Examples:
   task execute 42
   task queue 42
   task run 42                   # "run" with a task ID also queues it
-  task execute 42 --dangerous   # Execute in dangerous mode`,
+  task execute 42 --dangerous   # Execute in dangerous mode
+  task execute 42 --wait --timeout 30m
+
+With --wait, the command blocks until the task is done, blocked, or archived
+and exits 0 when it is done, 1 when it stopped without finishing (printing the
+question it is waiting on or its last error), and 2 on --timeout.`,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			var taskID int64
@@ -2081,6 +2842,9 @@ Examples:
 			}
 
 			executeDangerous, _ := cmd.Flags().GetBool("dangerous")
+			wait, _ := cmd.Flags().GetBool("wait")
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			outputJSON, _ := cmd.Flags().GetBool("json")
 			executePermMode, _ := cmd.Flags().GetString("permission-mode")
 			executePermMode = db.NormalizePermissionMode(executePermMode)
 			if executePermMode == "" && executeDangerous {
@@ -2107,12 +2871,16 @@ Examples:
 			}
 
 			// Check if already queued/processing
-			if task.Status == db.StatusQueued {
-				fmt.Println(dimStyle.Render(fmt.Sprintf("Task #%d is already queued", taskID)))
-				return
-			}
-			if task.Status == db.StatusProcessing {
-				fmt.Println(dimStyle.Render(fmt.Sprintf("Task #%d is already processing", taskID)))
+			if task.Status == db.StatusQueued || task.Status == db.StatusProcessing {
+				if wait {
+					runExecuteWait(database, taskID, timeout, outputJSON)
+				}
+				if outputJSON {
+					data, _ := json.Marshal(map[string]interface{}{"id": taskID, "title": task.Title, "status": task.Status})
+					fmt.Println(string(data))
+					return
+				}
+				fmt.Println(dimStyle.Render(fmt.Sprintf("Task #%d is already %s", taskID, task.Status)))
 				return
 			}
 
@@ -2139,11 +2907,26 @@ Examples:
 			case db.PermissionModeAcceptEdits:
 				msg += " (accept-edits mode)"
 			}
-			fmt.Println(successStyle.Render(msg))
+			if outputJSON {
+				if !wait {
+					data, _ := json.Marshal(map[string]interface{}{"id": taskID, "title": task.Title, "status": db.StatusQueued})
+					fmt.Println(string(data))
+				}
+			} else if wait {
+				infoln(successStyle.Render(msg))
+			} else {
+				outln(successStyle.Render(msg))
+			}
 			ensureDaemonForQueuedWork()
+			if wait {
+				runExecuteWait(database, taskID, timeout, outputJSON)
+			}
 		},
 	}
 	executeCmd.Flags().Bool("dangerous", false, "Execute in dangerous mode (alias for --permission-mode dangerous)")
+	executeCmd.Flags().Bool("wait", false, "Block until the task is done, blocked, or archived; the exit code reflects the outcome")
+	executeCmd.Flags().Duration("timeout", 0, "With --wait, give up after this long (e.g. 30m)")
+	executeCmd.Flags().Bool("json", false, "Output the result as JSON (with --wait, only the final status)")
 	executeCmd.Flags().String("permission-mode", "", "Override permission mode: default (prompt), accept-edits (auto-accept file edits), auto (Claude Code auto mode), dangerous (skip all)")
 	rootCmd.AddCommand(executeCmd)
 
@@ -2197,7 +2980,7 @@ Valid statuses: backlog, queued, processing, blocked, done, archived.`,
 				os.Exit(1)
 			}
 
-			fmt.Println(successStyle.Render(fmt.Sprintf("Task #%d moved to %s", taskID, status)))
+			outln(successStyle.Render(fmt.Sprintf("Task #%d moved to %s", taskID, status)))
 		},
 	}
 	rootCmd.AddCommand(statusCmd)
@@ -2253,7 +3036,7 @@ Valid statuses: backlog, queued, processing, blocked, done, archived.`,
 			if !newValue {
 				state = "unpinned"
 			}
-			fmt.Println(successStyle.Render(fmt.Sprintf("Task #%d %s", taskID, state)))
+			outln(successStyle.Render(fmt.Sprintf("Task #%d %s", taskID, state)))
 		},
 	}
 	pinCmd.Flags().Bool("unpin", false, "Unpin the task")
@@ -2319,7 +3102,7 @@ Examples:
 				os.Exit(1)
 			}
 
-			fmt.Println(successStyle.Render(fmt.Sprintf("Closed task #%d: %s", taskID, task.Title)))
+			outln(successStyle.Render(fmt.Sprintf("Closed task #%d: %s", taskID, task.Title)))
 		},
 	}
 	rootCmd.AddCommand(closeCmd)
@@ -2331,12 +3114,40 @@ Examples:
 		ValidArgsFunction: completeTaskIDs,
 		Long: `Retry a task that is blocked or failed, optionally with feedback.
 
+Retrying also resets the task's automatic retry count (see the max_retries
+setting), so it gets the full number of automatic retries again.
+
+--all-blocked retries every blocked task instead, for example after fixing
+missing credentials; --project and --tag narrow the selection (and imply
+--all-blocked). Tasks blocked only on unfinished dependencies are skipped.
+Retrying more than ` + fmt.Sprint(retryConfirmThreshold) + ` tasks at once requires --yes.
+
 Examples:
   task retry 42
   task retry 42 --feedback "Try a different approach"
-  task retry 42 -m "Focus on the error handling"`,
-		Args: cobra.ExactArgs(1),
+  task retry 42 -m "Focus on the error handling"
+  task retry --all-blocked --project myapp -m "Credentials are fixed"
+  task retry --tag infra --yes`,
+		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
+			allBlocked, _ := cmd.Flags().GetBool("all-blocked")
+			project, _ := cmd.Flags().GetString("project")
+			tag, _ := cmd.Flags().GetString("tag")
+			if allBlocked || project != "" || tag != "" {
+				if len(args) > 0 {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: pass a task ID or --all-blocked/--project/--tag, not both"))
+					os.Exit(1)
+				}
+				feedback, _ := cmd.Flags().GetString("feedback")
+				yes, _ := cmd.Flags().GetBool("yes")
+				runRetryBatch(project, tag, feedback, yes)
+				return
+			}
+			if len(args) == 0 {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: pass a task ID, or --all-blocked to retry every blocked task"))
+				os.Exit(1)
+			}
+
 			var taskID int64
 			if _, err := fmt.Sscanf(args[0], "%d", &taskID); err != nil {
 				fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid task ID: "+args[0]))
@@ -2369,10 +3180,16 @@ Examples:
 				os.Exit(1)
 			}
 
-			fmt.Println(successStyle.Render(fmt.Sprintf("Retrying task #%d: %s", taskID, task.Title)))
+			outln(successStyle.Render(fmt.Sprintf("Retrying task #%d: %s", taskID, task.Title)))
 		},
 	}
 	retryCmd.Flags().StringP("feedback", "m", "", "Feedback for the retry")
+	retryCmd.Flags().Bool("all-blocked", false, "Retry every blocked task")
+	retryCmd.Flags().StringP("project", "p", "", "Retry the blocked tasks in this project")
+	retryCmd.Flags().String("tag", "", "Retry the blocked tasks with this tag")
+	retryCmd.Flags().BoolP("yes", "y", false, fmt.Sprintf("Confirm retrying more than %d tasks at once", retryConfirmThreshold))
+	retryCmd.RegisterFlagCompletionFunc("project", completeProjectNames)
+	retryCmd.RegisterFlagCompletionFunc("tag", completeTagNames)
 	rootCmd.AddCommand(retryCmd)
 
 	// Input subcommand - send input directly to a running task's executor
@@ -2392,6 +3209,9 @@ field without submitting it.
 If no message is provided, reads from stdin (useful for piping).
 Use --enter to just send Enter (for confirming TUI prompts).
 Use --key to send special keys like "Up", "Down", "Tab", "Escape".
+Use --interactive for a back-and-forth: each line you type is sent and
+submitted, and the executor's reply is printed once its pane settles. Leave
+with /quit or Ctrl-D.
 
 Examples:
   task input 42 "yes"                # Type "yes" and submit
@@ -2399,7 +3219,8 @@ Examples:
   task input 42 "draft text" --no-submit   # Fill the field, don't submit
   task input 42 --enter              # Just press Enter
   task input 42 --key Down --enter   # Press Down then Enter
-  echo "continue" | task input 42`,
+  echo "continue" | task input 42
+  task input 42 --interactive        # Converse with the agent from your terminal`,
 		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			var taskID int64
@@ -2411,11 +3232,17 @@ Examples:
 			justEnter, _ := cmd.Flags().GetBool("enter")
 			specialKey, _ := cmd.Flags().GetString("key")
 			noSubmit, _ := cmd.Flags().GetBool("no-submit")
+			interactive, _ := cmd.Flags().GetBool("interactive")
+
+			if interactive && (len(args) > 1 || justEnter || specialKey != "" || noSubmit) {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: --interactive cannot be combined with a message, --enter, --key or --no-submit"))
+				os.Exit(1)
+			}
 
 			var message string
 			if len(args) > 1 {
 				message = strings.Join(args[1:], " ")
-			} else if !justEnter && specialKey == "" {
+			} else if !justEnter && specialKey == "" && !interactive {
 				// Read from stdin only if not using --enter or --key
 				scanner := bufio.NewScanner(os.Stdin)
 				if scanner.Scan() {
@@ -2427,7 +3254,7 @@ Examples:
 				}
 			}
 
-			if message == "" && !justEnter && specialKey == "" {
+			if message == "" && !justEnter && specialKey == "" && !interactive {
 				fmt.Fprintln(os.Stderr, errorStyle.Render("No input provided (use --enter to send just Enter, or --key for special keys)"))
 				os.Exit(1)
 			}
@@ -2458,6 +3285,14 @@ Examples:
 				os.Exit(1)
 			}
 
+			if interactive {
+				if err := runInteractiveInput(taskID, newTmuxPaneSession(paneID), os.Stdin, os.Stdout); err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error reading stdin: "+err.Error()))
+					os.Exit(1)
+				}
+				return
+			}
+
 			// Build and send tmux send-keys commands
 			// If --key specified, send that first
 			if specialKey != "" {
@@ -2468,43 +3303,25 @@ Examples:
 				}
 			}
 
-			// Send the message text (literally, so a message that looks like a tmux
-			// key name such as "Enter" or "Up" isn't interpreted as a keypress).
-			if message != "" {
-				sendCmd := osexec.Command("tmux", "send-keys", "-t", paneID, "-l", message)
-				if err := sendCmd.Run(); err != nil {
-					fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Error sending input to pane %s (task may have finished): %v", paneID, err)))
-					os.Exit(1)
-				}
-			}
-
-			// Submit by pressing Enter unless --no-submit was passed. Enter is sent
-			// as a SEPARATE keypress after the text: agentic TUIs (Claude Code, etc.)
-			// use bracketed-paste / input debouncing, so an Enter bundled into the
-			// same send-keys call as the text gets absorbed as a newline instead of
-			// submitting. The brief pause lets the TUI register the text first.
+			// Send the message text, then submit by pressing Enter unless
+			// --no-submit was passed (see sendPaneLine).
 			submit := shouldSubmitInput(message, justEnter, noSubmit)
-			if submit {
-				if message != "" {
-					time.Sleep(100 * time.Millisecond)
-				}
-				sendCmd := osexec.Command("tmux", "send-keys", "-t", paneID, "Enter")
-				if err := sendCmd.Run(); err != nil {
-					fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Error sending Enter to pane %s (task may have finished): %v", paneID, err)))
-					os.Exit(1)
-				}
+			if err := sendPaneLine(paneID, message, submit); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Error on pane %s (task may have finished): %v", paneID, err)))
+				os.Exit(1)
 			}
 
 			if message != "" && !submit {
-				fmt.Println(successStyle.Render(fmt.Sprintf("Sent input to task #%d (not submitted)", taskID)))
+				outln(successStyle.Render(fmt.Sprintf("Sent input to task #%d (not submitted)", taskID)))
 			} else {
-				fmt.Println(successStyle.Render(fmt.Sprintf("Sent input to task #%d", taskID)))
+				outln(successStyle.Render(fmt.Sprintf("Sent input to task #%d", taskID)))
 			}
 		},
 	}
 	inputCmd.Flags().Bool("enter", false, "Just send Enter key (for confirming prompts)")
 	inputCmd.Flags().String("key", "", "Send a special key (e.g., Up, Down, Tab, Escape)")
 	inputCmd.Flags().Bool("no-submit", false, "Type the text but don't press Enter (leave it in the input field)")
+	inputCmd.Flags().BoolP("interactive", "i", false, "Read lines in a loop, sending each and printing the executor's reply")
 	rootCmd.AddCommand(inputCmd)
 
 	// Pi Wrapper subcommand - internal use for RPC mode
@@ -2558,20 +3375,19 @@ Examples:
 			paneID := task.ClaudePaneID
 			if paneID == "" {
 				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Task #%d has no executor pane (not running?)", taskID)))
-				fmt.Fprintln(os.Stderr, dimStyle.Render("Tip: use 'task show' to see what the task accomplished"))
+				infoln(dimStyle.Render("Tip: use 'task show' to see what the task accomplished"))
 				os.Exit(1)
 			}
 
 			// Capture pane content
-			captureCmd := osexec.Command("tmux", "capture-pane", "-t", paneID, "-p", "-S", fmt.Sprintf("-%d", lines))
-			output, err := captureCmd.Output()
+			output, err := capturePane(paneID, lines)
 			if err != nil {
 				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Executor pane no longer exists for task #%d", taskID)))
-				fmt.Fprintln(os.Stderr, dimStyle.Render("Tip: use 'task show' to see what the task accomplished, or 'task show --logs' for full activity"))
+				infoln(dimStyle.Render("Tip: use 'task show' to see what the task accomplished, or 'task show --logs' for full activity"))
 				os.Exit(1)
 			}
 
-			fmt.Print(string(output))
+			fmt.Print(output)
 		},
 	}
 	outputCmd.Flags().IntP("lines", "n", 50, "Number of lines to capture")
@@ -2622,7 +3438,7 @@ Examples:
 			if removed == 0 {
 				fmt.Println(dimStyle.Render("No stale entries found"))
 			} else {
-				fmt.Println(successStyle.Render(fmt.Sprintf("Removed %d stale entries from %s", removed, configPath)))
+				outln(successStyle.Render(fmt.Sprintf("Removed %d stale entries from %s", removed, configPath)))
 			}
 		},
 	}
@@ -2647,14 +3463,30 @@ then the directory is removed. Archived tasks can be restored with 'unarchive'.
 The default max age is 24 hours. Use --max-age to override.
 Use --dry-run to preview what would be cleaned up.
 
+Task branches are kept by default (--keep-branch). With --delete-branch, each
+task's local branch is deleted once its worktree is archived; unarchiving
+recreates it from the archive. Branches with an open pull request, and
+existing branches a task checked out with 'ty create --branch', are always
+kept. --force deletes branches with open pull requests too.
+
 Examples:
   task worktrees cleanup
   task worktrees cleanup --dry-run
   task worktrees cleanup --max-age 72h
-  task worktrees cleanup --max-age 0  # clean up ALL done/archived worktrees`,
+  task worktrees cleanup --max-age 0  # clean up ALL done/archived worktrees
+  task worktrees cleanup --delete-branch --dry-run`,
 		Run: func(cmd *cobra.Command, args []string) {
 			dryRun, _ := cmd.Flags().GetBool("dry-run")
 			maxAgeStr, _ := cmd.Flags().GetString("max-age")
+			deleteBranch, _ := cmd.Flags().GetBool("delete-branch")
+			if keepBranch, _ := cmd.Flags().GetBool("keep-branch"); !keepBranch {
+				deleteBranch = true // --keep-branch=false
+			}
+			force, _ := cmd.Flags().GetBool("force")
+			if force && !deleteBranch {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: --force only applies with --delete-branch"))
+				os.Exit(1)
+			}
 
 			maxAge := executor.DefaultWorktreeCleanupMaxAge
 			if maxAgeStr != "" {
@@ -2681,13 +3513,18 @@ Examples:
 			cfg := config.New(database)
 			exec := executor.New(database, cfg)
 
-			tasks, err := exec.CleanupStaleWorktreesManual(maxAge, dryRun)
+			cleaned, err := exec.CleanupStaleWorktreesManual(executor.WorktreeCleanupOptions{
+				MaxAge:       maxAge,
+				DryRun:       dryRun,
+				DeleteBranch: deleteBranch,
+				Force:        force,
+			})
 			if err != nil {
 				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
 				os.Exit(1)
 			}
 
-			if len(tasks) == 0 {
+			if len(cleaned) == 0 {
 				if maxAge == 0 {
 					fmt.Println(dimStyle.Render("No done/archived tasks with worktrees found"))
 				} else {
@@ -2697,23 +3534,44 @@ Examples:
 			}
 
 			if dryRun {
-				fmt.Printf("Would archive and remove %d worktree(s):\n", len(tasks))
-				for _, t := range tasks {
+				fmt.Printf("Would archive and remove %d worktree(s):\n", len(cleaned))
+				for _, c := range cleaned {
+					t := c.Task
 					age := time.Since(t.CompletedAt.Time).Round(time.Hour)
-					fmt.Printf("  #%-4d %-12s %-30s %s (age: %s)\n",
-						t.ID, t.Project, truncate(t.Title, 30), dimStyle.Render(t.WorktreePath), age)
+					fmt.Printf("  #%-4d %-12s %-30s %s (age: %s)%s\n",
+						t.ID, t.Project, truncate(t.Title, 30), dimStyle.Render(t.WorktreePath), age, cleanupBranchNote(c, deleteBranch, true))
 				}
 			} else {
-				fmt.Println(successStyle.Render(fmt.Sprintf("Archived and removed %d stale worktree(s)", len(tasks))))
-				for _, t := range tasks {
-					fmt.Printf("  #%-4d %s\n", t.ID, t.Title)
+				outln(successStyle.Render(fmt.Sprintf("Archived and removed %d stale worktree(s)", len(cleaned))))
+				for _, c := range cleaned {
+					fmt.Printf("  #%-4d %s%s\n", c.Task.ID, c.Task.Title, cleanupBranchNote(c, deleteBranch, false))
+				}
+			}
+			if deleteBranch {
+				var deleted, kept int
+				for _, c := range cleaned {
+					if c.BranchDeleted {
+						deleted++
+					} else if c.Branch != "" {
+						kept++
+					}
+				}
+				verb := "Deleted"
+				if dryRun {
+					verb = "Would delete"
 				}
+				outln(dimStyle.Render(fmt.Sprintf("%s %d branch(es), kept %d", verb, deleted, kept)))
 			}
 		},
 	}
 	worktreesCleanupCmd.Flags().Bool("dry-run", false, "Show what would be removed without making changes")
 	worktreesCleanupCmd.Flags().String("max-age", "", "Maximum age before cleanup (e.g., 24h, 72h, 0 for all). Default: 24h (1 day)")
+	worktreesCleanupCmd.Flags().Bool("delete-branch", false, "Also delete each task's local branch (unless it has an open PR)")
+	worktreesCleanupCmd.Flags().Bool("keep-branch", true, "Keep task branches (default)")
+	worktreesCleanupCmd.Flags().Bool("force", false, "With --delete-branch, delete branches even if they have an open PR")
+	worktreesCleanupCmd.MarkFlagsMutuallyExclusive("delete-branch", "keep-branch")
 	worktreesCmd.AddCommand(worktreesCleanupCmd)
+	worktreesCmd.AddCommand(newWorktreesListCmd())
 	rootCmd.AddCommand(worktreesCmd)
 
 	// Update command - self-update via install script
@@ -2738,75 +3596,7 @@ Examples:
 	}
 	rootCmd.AddCommand(upgradeCmd)
 
-	// Doctor command - diagnose the agent server's GitHub auth health.
-	doctorCmd := &cobra.Command{
-		Use:   "doctor",
-		Short: "Diagnose agent server health (GitHub auth & rate limits)",
-		Long: `Checks the local GitHub CLI authentication used by agents and warns about
-conditions that cause shared GraphQL bucket exhaustion across agent servers:
-
-  - gh not installed or not logged in (GitHub operations silently fail)
-  - an expired/revoked token (401 Bad credentials)
-  - authentication as a PERSONAL account, whose 5,000 pt/hr GraphQL limit is
-    shared per-user across every server authed as that account
-  - low remaining GraphQL headroom
-
-Each agent server should authenticate with its OWN GitHub App installation
-token (a bot identity), which gets an independent GraphQL bucket.
-
-Exits non-zero on hard errors (gh missing, logged out, expired token). Pass
---strict to also exit non-zero on warnings (e.g. personal-account auth), so a
-fleet sweep like 'for s in ...; do ssh $s ty doctor --strict; done' can flag
-servers programmatically.`,
-		Run: func(cmd *cobra.Command, args []string) {
-			strict, _ := cmd.Flags().GetBool("strict")
-			fmt.Println(boldStyle.Render("TaskYou Doctor"))
-			fmt.Println(dimStyle.Render("Checking GitHub authentication..."))
-			fmt.Println()
-
-			status := github.CheckAuth(context.Background())
-			if status.Err != nil {
-				fmt.Println(warnStyle.Render("⚠ Could not fully probe gh: " + status.Err.Error()))
-				fmt.Println()
-			}
-
-			findings := status.Findings()
-			hasError := false
-			for _, f := range findings {
-				var icon, msg string
-				switch f.Severity {
-				case github.SeverityOK:
-					icon = successStyle.Render("✓")
-					msg = f.Message
-				case github.SeverityWarn:
-					icon = warnStyle.Render("⚠")
-					msg = warnStyle.Render(f.Message)
-				case github.SeverityError:
-					icon = errorStyle.Render("✗")
-					msg = errorStyle.Render(f.Message)
-					hasError = true
-				}
-				fmt.Printf("%s %s\n", icon, msg)
-				if f.Detail != "" {
-					fmt.Println(dimStyle.Render("    " + f.Detail))
-				}
-			}
-
-			fmt.Println()
-			if hasError || status.HasProblems() {
-				fmt.Println(dimStyle.Render("Tip: provision this server with its own GitHub App installation token,"))
-				fmt.Println(dimStyle.Render("mirroring the offerlab-devs[bot] pattern, for an independent rate-limit bucket."))
-			} else {
-				fmt.Println(successStyle.Render("All checks passed."))
-			}
-
-			if hasError || (strict && status.HasProblems()) {
-				os.Exit(1)
-			}
-		},
-	}
-	doctorCmd.Flags().Bool("strict", false, "Exit non-zero on warnings too (e.g. personal-account auth), for fleet health sweeps")
-	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(newDoctorCmd())
 
 	// Settings command
 	settingsCmd := &cobra.Command{
@@ -2830,7 +3620,12 @@ servers programmatically.`,
 			if apiKey != "" {
 				// Mask the key for display
 				masked := apiKey[:7] + "..." + apiKey[len(apiKey)-4:]
-				fmt.Printf("anthropic_api_key: %s\n", masked)
+				storage, _ := database.SecretSettingStorage("anthropic_api_key")
+				fmt.Printf("anthropic_api_key: %s %s\n", masked, dimStyle.Render("("+storage+")"))
+			} else if storage, _ := database.SecretSettingStorage("anthropic_api_key"); storage != "" {
+				// Stored but unreadable: encrypted without the passphrase set, or
+				// the keychain entry is gone.
+				fmt.Printf("anthropic_api_key: %s\n", warnStyle.Render("(stored in "+storage+", cannot be read)"))
 			} else if os.Getenv("ANTHROPIC_API_KEY") != "" {
 				fmt.Printf("anthropic_api_key: %s\n", dimStyle.Render("(using ANTHROPIC_API_KEY env var)"))
 			} else {
@@ -2851,6 +3646,55 @@ servers programmatically.`,
 			}
 			fmt.Printf("idle_suspend_timeout: %s\n", idleTimeout)
 
+			// Daemon concurrency limit
+			maxConcurrent, _ := database.GetSetting(config.SettingMaxConcurrentTasks)
+			if maxConcurrent == "" {
+				maxConcurrent = strconv.Itoa(config.DefaultMaxConcurrentTasks) + " (default)"
+			}
+			fmt.Printf("max_concurrent_tasks: %s\n", maxConcurrent)
+
+			// Automatic retries of failed tasks
+			maxRetries, _ := database.GetSetting(config.SettingMaxRetries)
+			if maxRetries == "" {
+				maxRetries = "0 (default, disabled)"
+			}
+			fmt.Printf("max_retries: %s\n", maxRetries)
+
+			// ty attach size limit
+			maxAttachment, _ := database.GetSetting(config.SettingMaxAttachmentSize)
+			if maxAttachment == "" {
+				maxAttachment = strconv.Itoa(config.DefaultMaxAttachmentSize) + " (default)"
+			}
+			fmt.Printf("max_attachment_size: %s MB\n", maxAttachment)
+
+			// How long cached PR status is reused
+			prTTL, _ := database.GetSetting(config.SettingPRCacheTTL)
+			if prTTL == "" {
+				prTTL = github.DefaultPRCacheTTL.String() + " (default)"
+			}
+			fmt.Printf("pr_cache_ttl: %s\n", prTTL)
+
+			// Desktop notifications
+			notificationsEnabled, _ := database.GetSetting(config.SettingNotificationsEnabled)
+			if notificationsEnabled == "" {
+				notificationsEnabled = "false"
+			}
+			fmt.Printf("notifications_enabled: %s\n", notificationsEnabled)
+			notifyOn, _ := database.GetSetting(config.SettingNotifyOn)
+			if notifyOn == "" {
+				notifyOn = config.DefaultNotifyOn + " (default)"
+			}
+			fmt.Printf("notify_on: %s\n", notifyOn)
+
+			fmt.Printf("secret_storage: %s\n", database.SecretStorageMode())
+
+			// Claude model for tasks that don't pick one
+			defaultModel, _ := database.GetSetting(db.SettingDefaultModel)
+			if defaultModel == "" {
+				defaultModel = "(not set, Claude's default)"
+			}
+			fmt.Printf("default_model: %s\n", defaultModel)
+
 			fmt.Println()
 			fmt.Println(dimStyle.Render("Use 'task settings set <key> <value>' to change settings"))
 		},
@@ -2868,42 +3712,34 @@ Available settings:
   autocomplete_enabled  Enable/disable ghost text autocomplete (true/false)
   idle_suspend_timeout  How long blocked tasks wait before suspending (e.g. 6h, 30m, 24h)
   http_api_port         Port the daemon-hosted HTTP API listens on (default 8080)
-  http_api_disabled     Stop the daemon from hosting the HTTP API (true/false)`,
+  http_api_disabled     Stop the daemon from hosting the HTTP API (true/false)
+  max_concurrent_tasks  How many queued tasks the daemon runs at once (default 3)
+  max_retries           Times to automatically re-queue a failed task, with
+                        exponential backoff (default 0 = never)
+  max_attachment_size   Largest file 'ty attach' accepts, in MB (default 10)
+  pr_cache_ttl          How long 'ty list --pr' and 'ty show' reuse cached PR
+                        status before refetching (default 60s)
+  notifications_enabled Show desktop notifications from the daemon (true/false)
+  notify_on             Comma-separated events to notify on (default
+                        task.blocked,task.completed; also task.created, task.started)
+  secret_storage        Where API keys are kept: plaintext (default), keychain
+                        (macOS Keychain / libsecret), or passphrase (encrypted
+                        with the ` + db.SecretPassphraseEnv + ` env var). Existing keys
+                        are migrated when this changes.
+  default_model         Claude model for tasks that set none themselves and
+                        whose project has no default (opus, sonnet, haiku, fable,
+                        a full claude-* model ID, or custom:<id>)`,
 		Args: cobra.ExactArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			key := args[0]
-			value := args[1]
-
-			// Validate known settings
-			switch key {
-			case "anthropic_api_key":
-				if !strings.HasPrefix(value, "sk-ant-") {
-					fmt.Println(errorStyle.Render("Invalid API key format. Should start with 'sk-ant-'"))
-					return
-				}
-			case "autocomplete_enabled":
-				if value != "true" && value != "false" {
-					fmt.Println(errorStyle.Render("Value must be 'true' or 'false'"))
-					return
-				}
-			case "idle_suspend_timeout":
-				if _, err := time.ParseDuration(value); err != nil {
-					fmt.Println(errorStyle.Render("Invalid duration format. Examples: 6h, 30m, 24h, 1h30m"))
-					return
-				}
-			case config.SettingHTTPAPIPort:
-				if p, err := strconv.Atoi(value); err != nil || p < 1 || p > 65535 {
-					fmt.Println(errorStyle.Render("Value must be a port number between 1 and 65535"))
-					return
-				}
-			case config.SettingHTTPAPIDisabled:
-				if value != "true" && value != "false" {
-					fmt.Println(errorStyle.Render("Value must be 'true' or 'false'"))
-					return
-				}
-			default:
+			value, err := validateSetting(key, args[1])
+			if errors.Is(err, errUnknownSetting) {
 				fmt.Println(errorStyle.Render("Unknown setting: " + key))
-				fmt.Println(dimStyle.Render("Available: anthropic_api_key, autocomplete_enabled, idle_suspend_timeout, http_api_port, http_api_disabled"))
+				fmt.Println(dimStyle.Render("Available: " + strings.Join(settingKeys, ", ")))
+				return
+			}
+			if err != nil {
+				fmt.Println(errorStyle.Render(err.Error()))
 				return
 			}
 
@@ -2915,11 +3751,9 @@ Available settings:
 			}
 			defer database.Close()
 
-			if err := database.SetSetting(key, value); err != nil {
+			if err := saveSetting(database, key, value); err != nil {
 				fmt.Println(errorStyle.Render("Failed to save setting: " + err.Error()))
-				return
 			}
-			fmt.Println(successStyle.Render("Setting saved: " + key))
 		},
 	}
 
@@ -2936,7 +3770,9 @@ Events are emitted when tasks change state (created, started, completed, failed)
 Script hooks in ~/.config/task/hooks/ are executed automatically.
 
 Examples:
-  ty events list                      # Show recent events`,
+  ty events list                      # Show recent events
+  ty events watch | jq .              # Stream new events as JSON lines
+  ty events types                     # Every event type and its metadata`,
 	}
 
 	// events list - show recent events from event log
@@ -2981,40 +3817,41 @@ Examples:
 			defer rows.Close()
 
 			type EventRecord struct {
-				ID        int64     `json:"id"`
-				Type      string    `json:"event_type"`
-				TaskID    int64     `json:"task_id"`
-				Message   string    `json:"message"`
-				Metadata  string    `json:"metadata"`
-				CreatedAt time.Time `json:"created_at"`
+				SchemaVersion int       `json:"schema_version"`
+				ID            int64     `json:"id"`
+				Type          string    `json:"event_type"`
+				TaskID        int64     `json:"task_id"`
+				Message       string    `json:"message"`
+				Metadata      string    `json:"metadata"`
+				CreatedAt     time.Time `json:"created_at"`
 			}
 
-			var events []EventRecord
+			var records []EventRecord
 			for rows.Next() {
-				var e EventRecord
+				e := EventRecord{SchemaVersion: events.SchemaVersion}
 				var createdAt db.LocalTime
 				if err := rows.Scan(&e.ID, &e.Type, &e.TaskID, &e.Message, &e.Metadata, &createdAt); err != nil {
 					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
 					os.Exit(1)
 				}
 				e.CreatedAt = createdAt.Time
-				events = append(events, e)
+				records = append(records, e)
 			}
 
 			if outputJSON {
-				data, _ := json.MarshalIndent(events, "", "  ")
+				data, _ := json.MarshalIndent(records, "", "  ")
 				fmt.Println(string(data))
 				return
 			}
 
-			if len(events) == 0 {
+			if len(records) == 0 {
 				fmt.Println(dimStyle.Render("No events found"))
 				return
 			}
 
-			fmt.Println(boldStyle.Render(fmt.Sprintf("Recent Events (%d)", len(events))))
+			fmt.Println(boldStyle.Render(fmt.Sprintf("Recent Events (%d)", len(records))))
 			fmt.Println(strings.Repeat("─", 80))
-			for _, e := range events {
+			for _, e := range records {
 				timestamp := e.CreatedAt.Format("2006-01-02 15:04:05")
 				fmt.Printf("%s  %s  Task #%d  %s\n",
 					dimStyle.Render(timestamp),
@@ -3031,7 +3868,10 @@ Examples:
 	eventsListCmd.Flags().String("type", "", "Filter by event type (e.g., task.created)")
 	eventsListCmd.Flags().Int64("task", 0, "Filter by task ID")
 	eventsListCmd.Flags().Bool("json", false, "Output in JSON format")
+	eventsListCmd.RegisterFlagCompletionFunc("type", completeEventTypes)
 	eventsCmd.AddCommand(eventsListCmd)
+	eventsCmd.AddCommand(newEventsWatchCmd())
+	eventsCmd.AddCommand(newEventsTypesCmd())
 
 	rootCmd.AddCommand(eventsCmd)
 
@@ -3047,7 +3887,10 @@ Examples:
   ty projects show myapp         # Show project details
   ty projects create myapp       # Create new project
   ty projects update myapp       # Update project settings
-  ty projects delete myapp       # Delete a project`,
+  ty projects delete myapp       # Delete a project
+  ty projects validate myapp     # Check repo/worktree health
+  ty projects scan ~/Projects    # Register every git repo in a directory
+  ty projects action list myapp  # Show event-triggered actions`,
 		Run: func(cmd *cobra.Command, args []string) {
 			// Default to list when no subcommand provided
 			listProjectsCLI(cmd)
@@ -3094,7 +3937,11 @@ Examples:
 Examples:
   ty projects create myapp --path ~/Projects/myapp
   ty projects create myapp --path ~/Projects/myapp --instructions "Use TypeScript"
-  ty projects create myapp --path ~/Projects/myapp --color "#61AFEF"`,
+  ty projects create myapp --path ~/Projects/myapp --color "#61AFEF"
+  ty projects create infra --path ~/Projects/infra --executor codex
+  ty projects create docs --path ~/Projects/docs --model haiku
+  ty projects create legacy --path ~/Projects/legacy --base-branch develop
+  ty projects create myapp --path ~/Projects/myapp --wip-limit 2`,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			path, _ := cmd.Flags().GetString("path")
@@ -3103,10 +3950,14 @@ Examples:
 			aliases, _ := cmd.Flags().GetString("aliases")
 			claudeConfigDir, _ := cmd.Flags().GetString("claude-config-dir")
 			permissionMode, _ := cmd.Flags().GetString("permission-mode")
+			projectExecutor, _ := cmd.Flags().GetString("executor")
+			projectModel, _ := cmd.Flags().GetString("model")
+			baseBranch, _ := cmd.Flags().GetString("base-branch")
+			wipLimit, _ := cmd.Flags().GetInt("wip-limit")
 			noGit, _ := cmd.Flags().GetBool("no-git")
 			outputJSON, _ := cmd.Flags().GetBool("json")
 
-			createProjectCLI(args[0], path, instructions, color, aliases, claudeConfigDir, permissionMode, noGit, outputJSON)
+			createProjectCLI(args[0], path, instructions, color, aliases, claudeConfigDir, permissionMode, projectExecutor, projectModel, baseBranch, wipLimit, noGit, outputJSON)
 		},
 	}
 	projectsCreateCmd.Flags().StringP("path", "p", "", "Project directory path (required)")
@@ -3115,6 +3966,12 @@ Examples:
 	projectsCreateCmd.Flags().StringP("aliases", "a", "", "Comma-separated aliases for lookup")
 	projectsCreateCmd.Flags().String("claude-config-dir", "", "Override CLAUDE_CONFIG_DIR for this project")
 	projectsCreateCmd.Flags().String("permission-mode", "", "Default permission mode for tasks: default (prompt), accept-edits (auto-accept file edits), auto (Claude Code auto mode), dangerous (skip all)")
+	projectsCreateCmd.Flags().StringP("executor", "e", "", "Default executor for new tasks: claude, codex, gemini, pi, opencode, openclaw (default: claude)")
+	projectsCreateCmd.RegisterFlagCompletionFunc("executor", completeFlagExecutors)
+	projectsCreateCmd.Flags().String("model", "", "Default Claude model for tasks that set none: opus, sonnet, haiku, fable, a full claude-* model ID, or custom:<id> (default: the default_model setting)")
+	projectsCreateCmd.RegisterFlagCompletionFunc("model", completeFlagModels)
+	projectsCreateCmd.Flags().String("base-branch", "", "Branch new task worktrees are created from (default: the repo's default branch, detected now)")
+	projectsCreateCmd.Flags().Int("wip-limit", 0, "Most tasks the daemon runs at once for this project (0 = unlimited)")
 	projectsCreateCmd.Flags().Bool("no-git", false, "Disable git worktrees (for non-git projects)")
 	projectsCreateCmd.Flags().Bool("json", false, "Output in JSON format")
 	projectsCreateCmd.MarkFlagRequired("path")
@@ -3132,7 +3989,12 @@ Examples:
   ty projects update myapp --color "#10B981"
   ty projects update myapp --name newname
   ty projects update myapp --path ~/Projects/newpath
-  ty projects update myapp --context "Project context summary..."`,
+  ty projects update myapp --context "Project context summary..."
+  ty projects update infra --executor codex
+  ty projects update docs --model haiku    # Default Claude model for its tasks
+  ty projects update docs --model ""       # Fall back to the default_model setting
+  ty projects update legacy --base-branch develop  # Branch new worktrees off develop
+  ty projects update myapp --wip-limit 2   # At most 2 tasks in progress (0 = unlimited)`,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			name, _ := cmd.Flags().GetString("name")
@@ -3143,6 +4005,7 @@ Examples:
 			claudeConfigDir, _ := cmd.Flags().GetString("claude-config-dir")
 			projectContext, _ := cmd.Flags().GetString("context")
 			permissionMode, _ := cmd.Flags().GetString("permission-mode")
+			projectExecutor, _ := cmd.Flags().GetString("executor")
 			outputJSON, _ := cmd.Flags().GetBool("json")
 			noGit, _ := cmd.Flags().GetBool("no-git")
 			git, _ := cmd.Flags().GetBool("git")
@@ -3162,7 +4025,28 @@ Examples:
 				useWorktrees = &v
 			}
 
-			updateProjectCLI(args[0], name, path, instructions, color, aliases, claudeConfigDir, projectContext, permissionMode, useWorktrees, outputJSON)
+			// nil means not specified; "" clears the project's default model
+			var projectModel *string
+			if cmd.Flags().Changed("model") {
+				m, _ := cmd.Flags().GetString("model")
+				projectModel = &m
+			}
+
+			// nil means not specified; "" falls back to the repo's default branch
+			var baseBranch *string
+			if cmd.Flags().Changed("base-branch") {
+				b, _ := cmd.Flags().GetString("base-branch")
+				baseBranch = &b
+			}
+
+			// nil means not specified; 0 removes the limit
+			var wipLimit *int
+			if cmd.Flags().Changed("wip-limit") {
+				n, _ := cmd.Flags().GetInt("wip-limit")
+				wipLimit = &n
+			}
+
+			updateProjectCLI(args[0], name, path, instructions, color, aliases, claudeConfigDir, projectContext, permissionMode, projectExecutor, projectModel, baseBranch, useWorktrees, wipLimit, outputJSON)
 		},
 	}
 	projectsUpdateCmd.Flags().StringP("name", "n", "", "New project name")
@@ -3173,6 +4057,12 @@ Examples:
 	projectsUpdateCmd.Flags().String("claude-config-dir", "", "Override CLAUDE_CONFIG_DIR for this project")
 	projectsUpdateCmd.Flags().String("context", "", "Cached project context summary")
 	projectsUpdateCmd.Flags().String("permission-mode", "", "Default permission mode for tasks: default (prompt), accept-edits (auto-accept file edits), auto (Claude Code auto mode), dangerous (skip all)")
+	projectsUpdateCmd.Flags().StringP("executor", "e", "", "Default executor for new tasks: claude, codex, gemini, pi, opencode, openclaw")
+	projectsUpdateCmd.RegisterFlagCompletionFunc("executor", completeFlagExecutors)
+	projectsUpdateCmd.Flags().String("model", "", "Default Claude model for tasks that set none: opus, sonnet, haiku, fable, a full claude-* model ID, or custom:<id> (empty to clear)")
+	projectsUpdateCmd.RegisterFlagCompletionFunc("model", completeFlagModels)
+	projectsUpdateCmd.Flags().String("base-branch", "", "Branch new task worktrees are created from (empty to use the repo's default branch)")
+	projectsUpdateCmd.Flags().Int("wip-limit", 0, "Most tasks the daemon runs at once for this project (0 = unlimited)")
 	projectsUpdateCmd.Flags().Bool("no-git", false, "Disable git worktrees (for non-git projects)")
 	projectsUpdateCmd.Flags().Bool("git", false, "Enable git worktrees (default)")
 	projectsUpdateCmd.Flags().Bool("json", false, "Output in JSON format")
@@ -3199,6 +4089,10 @@ Examples:
 	}
 	projectsDeleteCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
 	projectsCmd.AddCommand(projectsDeleteCmd)
+	projectsCmd.AddCommand(newProjectsValidateCmd())
+	projectsCmd.AddCommand(newProjectsScanCmd())
+	projectsCmd.AddCommand(newProjectsEditCmd())
+	projectsCmd.AddCommand(newProjectsActionCmd())
 
 	rootCmd.AddCommand(projectsCmd)
 
@@ -3250,6 +4144,18 @@ Use --auto-queue to automatically move the blocked task to 'queued' when unblock
 				os.Exit(1)
 			}
 
+			// A cycle would leave every task in it waiting on another forever, so
+			// refuse it and show the loop.
+			path, err := database.DependencyCyclePath(blockerID, blockedID)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if path != nil {
+				printDependencyCycle(database, blockerID, blockedID, path)
+				os.Exit(1)
+			}
+
 			if err := database.AddDependency(blockerID, blockedID, autoQueue); err != nil {
 				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
 				os.Exit(1)
@@ -3259,7 +4165,7 @@ Use --auto-queue to automatically move the blocked task to 'queued' when unblock
 			if autoQueue {
 				autoQueueStr = " (will auto-queue when unblocked)"
 			}
-			fmt.Println(successStyle.Render(fmt.Sprintf("Task #%d is now blocked by #%d%s", blockedID, blockerID, autoQueueStr)))
+			outln(successStyle.Render(fmt.Sprintf("Task #%d is now blocked by #%d%s", blockedID, blockerID, autoQueueStr)))
 		},
 	}
 	blockCmd.Flags().Int64("by", 0, "ID of the blocker task (required)")
@@ -3303,7 +4209,7 @@ This removes the dependency where task #5 was blocked by task #3.`,
 				os.Exit(1)
 			}
 
-			fmt.Println(successStyle.Render(fmt.Sprintf("Task #%d is no longer blocked by #%d", blockedID, blockerID)))
+			outln(successStyle.Render(fmt.Sprintf("Task #%d is no longer blocked by #%d", blockedID, blockerID)))
 		},
 	}
 	unblockCmd.Flags().Int64("from", 0, "ID of the blocker task to remove (required)")
@@ -3312,20 +4218,43 @@ This removes the dependency where task #5 was blocked by task #3.`,
 
 	// Deps command - show dependencies for a task
 	depsCmd := &cobra.Command{
-		Use:               "deps <task-id>",
+		Use:               "deps [task-id]",
 		ValidArgsFunction: completeTaskIDs,
 		Short:             "Show dependencies for a task",
 		Long: `Display all dependencies for a task, showing:
 - Tasks that block this task (must complete before this task)
-- Tasks that this task blocks (waiting on this task)`,
-		Args: cobra.ExactArgs(1),
+- Tasks that this task blocks (waiting on this task)
+
+With --graph, print the blocker graph as a Mermaid flowchart instead (--dot
+for Graphviz), nodes colored by status. Given a task ID the graph covers
+everything it transitively waits on and everything waiting on it; without
+one it covers every task with a dependency. Dependency cycles are reported
+on stderr.
+
+Examples:
+  ty deps 42
+  ty deps --graph > deps.mmd
+  ty deps 42 --dot | dot -Tsvg > deps.svg`,
+		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			var taskID int64
-			if _, err := fmt.Sscanf(args[0], "%d", &taskID); err != nil {
-				fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid task ID: "+args[0]))
+			graph, _ := cmd.Flags().GetBool("graph")
+			dot, _ := cmd.Flags().GetBool("dot")
+			if dot {
+				graph = true
+			}
+			if len(args) == 0 && !graph {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: a task ID is required (or use --graph for every task)"))
 				os.Exit(1)
 			}
 
+			var taskID int64
+			if len(args) == 1 {
+				if _, err := fmt.Sscanf(args[0], "%d", &taskID); err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid task ID: "+args[0]))
+					os.Exit(1)
+				}
+			}
+
 			dbPath := db.DefaultPath()
 			database, err := openTaskDB(dbPath)
 			if err != nil {
@@ -3334,6 +4263,29 @@ This removes the dependency where task #5 was blocked by task #3.`,
 			}
 			defer database.Close()
 
+			if graph {
+				if taskID != 0 {
+					if task, err := database.GetTask(taskID); err != nil || task == nil {
+						fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Task #%d not found", taskID)))
+						os.Exit(1)
+					}
+				}
+				g, err := buildDepGraph(database, taskID)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+				for _, cycle := range g.Cycles {
+					fmt.Fprintln(os.Stderr, warnStyle.Render("Warning: dependency cycle: "+formatDepCycle(cycle)))
+				}
+				if dot {
+					writeDotDepGraph(os.Stdout, g)
+				} else {
+					writeMermaidDepGraph(os.Stdout, g)
+				}
+				return
+			}
+
 			task, err := database.GetTask(taskID)
 			if err != nil || task == nil {
 				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Task #%d not found", taskID)))
@@ -3375,6 +4327,8 @@ This removes the dependency where task #5 was blocked by task #3.`,
 			}
 		},
 	}
+	depsCmd.Flags().Bool("graph", false, "Print the dependency graph as a Mermaid flowchart")
+	depsCmd.Flags().Bool("dot", false, "Print the dependency graph in Graphviz DOT format (implies --graph)")
 	rootCmd.AddCommand(depsCmd)
 
 	// Types command - manage task types
@@ -3555,6 +4509,8 @@ The instructions field supports template variables:
   {{task_id}}              - Task ID
   {{task_metadata}}        - Full task metadata section
   {{project_instructions}} - Project-specific instructions
+  {{memories}}             - Project memories (patterns, decisions, gotchas, context)
+  {{memories:all}}         - Project memories in every category
   {{attachments}}          - File attachments content
   {{history}}              - Conversation history
 
@@ -3629,7 +4585,7 @@ Examples:
 				os.Exit(1)
 			}
 
-			fmt.Println(successStyle.Render("Created task type: " + name))
+			outln(successStyle.Render("Created task type: " + name))
 		},
 	}
 	typesCreateCmd.Flags().String("name", "", "Type name (lowercase, no spaces) - required")
@@ -3731,7 +4687,7 @@ Examples:
 				os.Exit(1)
 			}
 
-			fmt.Println(successStyle.Render("Updated task type: " + taskType.Name))
+			outln(successStyle.Render("Updated task type: " + taskType.Name))
 		},
 	}
 	typesEditCmd.Flags().String("name", "", "New type name (lowercase, no spaces)")
@@ -3796,7 +4752,7 @@ Examples:
 				os.Exit(1)
 			}
 
-			fmt.Println(successStyle.Render("Deleted task type: " + name))
+			outln(successStyle.Render("Deleted task type: " + name))
 		},
 	}
 	typesDeleteCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
@@ -3858,6 +4814,36 @@ The server shares the same SQLite database the daemon writes to (WAL mode).`,
 	// Bulk operations
 	rootCmd.AddCommand(newBulkCmd())
 
+	// Schema versioning
+	rootCmd.AddCommand(newMigrateCmd())
+	rootCmd.AddCommand(newVersionCmd())
+	rootCmd.AddCommand(newProfilesCmd())
+	rootCmd.AddCommand(newWatchCmd())
+	rootCmd.AddCommand(newExportCmd())
+	rootCmd.AddCommand(newImportCmd())
+	rootCmd.AddCommand(newTemplatesCmd())
+	rootCmd.AddCommand(newTagsCmd())
+	rootCmd.AddCommand(newSearchCmd())
+	rootCmd.AddCommand(newStatsCmd())
+	rootCmd.AddCommand(newTrackCmd())
+	rootCmd.AddCommand(newUndoCmd())
+	rootCmd.AddCommand(newNoteCmd())
+	rootCmd.AddCommand(newAttachCmd())
+	rootCmd.AddCommand(newAttachmentsCmd())
+	rootCmd.AddCommand(newSummaryCmd())
+	rootCmd.AddCommand(newImportIssueCmd())
+	rootCmd.AddCommand(newPRCmd())
+	rootCmd.AddCommand(newConfigCmd())
+	rootCmd.AddCommand(newReorderCmd())
+	rootCmd.AddCommand(newMemoriesCmd())
+	rootCmd.AddCommand(newDuplicateCmd())
+	rootCmd.AddCommand(newBackupCmd())
+	rootCmd.AddCommand(newRestoreCmd())
+	rootCmd.AddCommand(newScheduleCmd())
+	rootCmd.AddCommand(newArchiveCmd())
+	rootCmd.AddCommand(newUnarchiveCmd())
+	rootCmd.AddCommand(newOpenCmd())
+
 	// Completion command for shell tab completion
 	rootCmd.AddCommand(newCompletionCmd(rootCmd))
 
@@ -4162,7 +5148,7 @@ func setupProfiling(cpuPath, memPath string) func() {
 				_ = f.Close()
 			} else {
 				cpuFile = f
-				fmt.Fprintln(os.Stderr, dimStyle.Render("CPU profiling to: "+cpuPath))
+				infoln(dimStyle.Render("CPU profiling to: " + cpuPath))
 			}
 		}
 	}
@@ -4218,7 +5204,7 @@ func runLocal(dangerousMode bool, debugStatePath, cpuProfilePath, memProfilePath
 	}
 	defer database.Close()
 
-	fmt.Fprintln(os.Stderr, dimStyle.Render("Using local database: "+dbPath))
+	infoln(dimStyle.Render("Using local database: " + dbPath))
 
 	// Load config from database
 	cfg := config.New(database)
@@ -4276,7 +5262,7 @@ func ensureDaemonForQueuedWork() {
 	// --dangerous flag is scoped to the TUI/daemon commands).
 	if err := ensureDaemonRunning(os.Getenv("WORKTREE_DANGEROUS_MODE") == "1"); err != nil {
 		fmt.Fprintln(os.Stderr, errorStyle.Render("Warning: daemon is not running and could not be started: "+err.Error()))
-		fmt.Fprintln(os.Stderr, dimStyle.Render("Queued work will not execute until you run 'ty daemon' or 'ty restart'."))
+		infoln(dimStyle.Render("Queued work will not execute until you run 'ty daemon' or 'ty restart'."))
 	}
 }
 
@@ -4335,7 +5321,7 @@ func ensureDaemonRunning(dangerousMode bool) error {
 	}
 	os.WriteFile(modeFile, []byte(modeStr), 0644)
 
-	fmt.Fprintln(os.Stderr, dimStyle.Render(fmt.Sprintf("Started daemon (pid %d, %s mode)", cmd.Process.Pid, modeStr)))
+	infoln(dimStyle.Render(fmt.Sprintf("Started daemon (pid %d, %s mode)", cmd.Process.Pid, modeStr)))
 	return nil
 }
 
@@ -4344,6 +5330,14 @@ func getPidFilePath() string {
 	// (WORKTREE_DB_PATH set, e.g. the QA harness) gets its own daemon lock and can
 	// run a full daemon alongside the live one. For the live instance this resolves
 	// to the historical ~/.local/share/task/daemon.pid — no behavior change.
+	// A profile (or --db database) also gets its own file name, so two of them
+	// pointed at DBs in the same directory still don't share a lock.
+	if name := profile.Active(); name != "" {
+		return filepath.Join(filepath.Dir(db.DefaultPath()), "daemon-"+name+".pid")
+	}
+	if tag := profile.DBTag(); tag != "" {
+		return filepath.Join(filepath.Dir(db.DefaultPath()), "daemon_"+tag+".pid")
+	}
 	return filepath.Join(filepath.Dir(db.DefaultPath()), "daemon.pid")
 }
 
@@ -4447,6 +5441,56 @@ var allowedStatuses = []string{
 	db.StatusArchived,
 }
 
+// prBranchName is the local branch a --from-pr task checks out. Same-repo PRs
+// use the head branch directly; fork PRs have no origin branch, so they get a
+// local pr-<number> branch fetched from the PR's pull/<n>/head ref.
+func prBranchName(pr *github.PRDetails) string {
+	if pr.IsCrossRepository || pr.HeadRefName == "" {
+		return fmt.Sprintf("pr-%d", pr.Number)
+	}
+	return pr.HeadRefName
+}
+
+// fetchPRBranch fetches the PR head into projectDir so the executor's
+// source-branch worktree setup can find it. Same-repo branches only need a
+// regular fetch (the executor does one too); fork PRs are fetched from
+// pull/<n>/head into the local branch.
+func fetchPRBranch(projectDir string, pr *github.PRDetails) error {
+	branch := prBranchName(pr)
+	args := []string{"fetch", "origin", branch}
+	if branch != pr.HeadRefName {
+		args = []string{"fetch", "origin", fmt.Sprintf("pull/%d/head:%s", pr.Number, branch)}
+	}
+	cmd := osexec.Command("git", args...)
+	cmd.Dir = projectDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// resolveAssignee expands the "me" shorthand to the current user's identity:
+// TASKYOU_USER when set (multi-user hosts inject the user id there), otherwise
+// the OS login name. "none" means nobody and resolves to "", matching
+// `ty list --assignee none`. Any other value is returned trimmed but
+// otherwise as-is.
+func resolveAssignee(v string) string {
+	v = strings.TrimSpace(v)
+	if v == "none" {
+		return ""
+	}
+	if v != "me" {
+		return v
+	}
+	if u := strings.TrimSpace(os.Getenv("TASKYOU_USER")); u != "" {
+		return u
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
 func validStatuses() []string {
 	return allowedStatuses
 }
@@ -4529,10 +5573,19 @@ func runDaemon() error {
 	os.WriteFile(modeFile, []byte(modeStr), 0644)
 	defer os.Remove(modeFile)
 
-	// Setup logger
-	logger := log.NewWithOptions(os.Stderr, log.Options{
+	// Setup logger. Structured JSON lines go to the rotating daemon log (read
+	// back by 'ty daemon logs'); stderr gets a readable copy, which is simply
+	// discarded when the daemon runs detached.
+	logFile, err := openRotatingFile(getDaemonLogPath(), daemonLogMaxSize)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+	logger := log.NewWithOptions(&daemonLogWriter{file: logFile, console: os.Stderr}, log.Options{
 		ReportTimestamp: true,
+		TimeFormat:      time.RFC3339,
 		Prefix:          "task-daemon",
+		Formatter:       log.JSONFormatter,
 	})
 
 	// Open database
@@ -4549,7 +5602,7 @@ func runDaemon() error {
 	cfg := config.New(database)
 
 	// Create executor with logging
-	exec := executor.NewWithLogging(database, cfg, os.Stderr)
+	exec := executor.NewWithLogger(database, cfg, logger.WithPrefix("executor"))
 
 	// Start background executor
 	ctx, cancel := context.WithCancel(context.Background())
@@ -4564,6 +5617,14 @@ func runDaemon() error {
 	// down the executor; the daemon's job is running tasks first and foremost.
 	httpSrv := startDaemonHTTPAPI(database, exec, logger)
 
+	// Desktop notifications follow the event log, so they fire for changes made
+	// by any process. Off unless notifications_enabled is "true".
+	go notify.NewWatcher(database, logger).Run(ctx)
+
+	// Comment on GitHub issues imported with `ty import-issue --link-back`
+	// once their tasks are completed.
+	go newIssueLinkBackWatcher(database, logger).Run(ctx)
+
 	// Start any long-running services declared by installed plugins (a sidecar an
 	// extension used to run on its own). They live for the daemon's lifetime and are
 	// stopped on shutdown. Hand each service a stable way to find ty: TY_DB_PATH (the
@@ -4658,6 +5719,12 @@ type ClaudeHookInput struct {
 	NotificationType string `json:"notification_type,omitempty"` // For Notification hooks
 	Message          string `json:"message,omitempty"`           // General message field
 	StopReason       string `json:"stop_reason,omitempty"`       // For Stop hooks
+	Source           string `json:"source,omitempty"`            // For SessionStart hooks: startup, resume, clear, compact
+	Model            string `json:"model,omitempty"`             // For SessionStart hooks: the model the session runs on
+	Reason           string `json:"reason,omitempty"`            // For SessionEnd hooks: clear, logout, prompt_input_exit, other
+	// LastAssistantMessage is Claude's final message for the turn (Stop hooks);
+	// when Claude stops to ask something, this is the question.
+	LastAssistantMessage string `json:"last_assistant_message,omitempty"`
 	// Tool use fields (for PreToolUse and PostToolUse hooks)
 	ToolName     string          `json:"tool_name,omitempty"`     // Name of the tool being used
 	ToolInput    json.RawMessage `json:"tool_input,omitempty"`    // Tool-specific input parameters
@@ -4694,11 +5761,22 @@ func handleClaudeHook(hookEvent string) error {
 	}
 	defer database.Close()
 
+	// One invocation can write several log rows (a tool entry, a status note);
+	// buffer them and write them in one transaction before the database closes.
+	logs := database.BatchTaskLogs()
+	defer logs.Stop()
+
 	// Log session ID once (on first hook call for this task)
 	logSessionIDOnce(database, taskID, &input)
 
 	// Handle based on hook event type
 	switch hookEvent {
+	case "SessionStart":
+		handleSessionStartHook(database, taskID, &input)
+		return nil
+	case "SessionEnd":
+		handleSessionEndHook(database, taskID, &input)
+		return nil
 	case "PreToolUse":
 		return handlePreToolUseHook(database, taskID, &input)
 	case "PostToolUse":
@@ -4706,6 +5784,7 @@ func handleClaudeHook(hookEvent string) error {
 	case "Notification":
 		return handleNotificationHook(database, taskID, &input)
 	case "Stop":
+		recordTranscriptUsage(database, taskID, input.TranscriptPath)
 		return handleStopHook(database, taskID, &input)
 	default:
 		// Unknown hook type, ignore
@@ -4713,6 +5792,56 @@ func handleClaudeHook(hookEvent string) error {
 	}
 }
 
+// recordTranscriptUsage adds the token usage in a Claude transcript to the
+// task. Each turn re-reads the whole transcript; messages already recorded
+// are skipped, so nothing is counted twice. Failures are ignored - usage is
+// informational and must not break the hook.
+func recordTranscriptUsage(database *db.DB, taskID int64, transcriptPath string) {
+	if transcriptPath == "" {
+		return
+	}
+	entries, err := usage.ParseClaudeTranscriptFile(transcriptPath)
+	if err != nil {
+		return
+	}
+	database.RecordTaskUsage(taskID, entries)
+}
+
+// handleSessionStartHook logs a session boundary marker with the model the
+// session runs on, so `ty show --logs` shows where each Claude session begins.
+func handleSessionStartHook(database *db.DB, taskID int64, input *ClaudeHookInput) {
+	model := input.Model
+	if model == "" {
+		if task, err := database.GetTask(taskID); err == nil && task != nil {
+			model = task.Model
+		}
+	}
+	if model == "" {
+		model = "default"
+	}
+	msg := "── Session started (model: " + model
+	if input.Source != "" {
+		msg += ", " + input.Source
+	}
+	database.AppendTaskLog(taskID, "system", msg+") ──")
+}
+
+// handleSessionEndHook records the session's final usage from its transcript
+// and logs a closing marker with the task's running total. A missing or
+// unreadable transcript only skips the usage; the hook never fails.
+func handleSessionEndHook(database *db.DB, taskID int64, input *ClaudeHookInput) {
+	recordTranscriptUsage(database, taskID, input.TranscriptPath)
+
+	msg := "── Session ended"
+	if input.Reason != "" {
+		msg += " (" + input.Reason + ")"
+	}
+	if task, err := database.GetTask(taskID); err == nil && task != nil && (task.InputTokens != 0 || task.OutputTokens != 0) {
+		msg += " · " + formatUsage(task.InputTokens, task.OutputTokens, task.CostUSD)
+	}
+	database.AppendTaskLog(taskID, "system", msg+" ──")
+}
+
 // logSessionIDOnce logs the Claude session ID for a task, but only once.
 // It checks if a session ID log already exists to avoid duplicate entries.
 // Also persists the session ID to the task record for reliable resumption.
@@ -4755,9 +5884,10 @@ func handleNotificationHook(database *db.DB, taskID int64, input *ClaudeHookInpu
 		// 1. Task has actually started (StartedAt is set)
 		// 2. Currently processing (avoid overwriting other states)
 		if task != nil && task.StartedAt != nil && task.Status == db.StatusProcessing {
-			database.UpdateTaskStatus(taskID, db.StatusBlocked)
 			msg := "Waiting for user input"
+			blockedReason := db.BlockedNeedsInput
 			if input.NotificationType == "permission_prompt" {
+				blockedReason = db.BlockedNeedsPermission
 				msg = "Waiting for permission"
 				if input.Message != "" {
 					msg = "Waiting for permission: " + input.Message
@@ -4769,12 +5899,30 @@ func handleNotificationHook(database *db.DB, taskID int64, input *ClaudeHookInpu
 					msg += "\n" + detail
 				}
 			}
+			database.BlockTask(taskID, blockedReason, msg)
 			database.AppendTaskLog(taskID, "system", msg)
 		}
 	}
 	return nil
 }
 
+// maxBlockedQuestionLen caps the question text attached to task.blocked events.
+const maxBlockedQuestionLen = 2000
+
+// blockedQuestion returns the text to attach to a task.blocked event for a
+// Stop hook: Claude's last message, trimmed and capped, or the generic
+// waiting message when Claude didn't send one.
+func blockedQuestion(lastMessage string) string {
+	q := strings.TrimSpace(lastMessage)
+	if q == "" {
+		return "Waiting for user input"
+	}
+	if r := []rune(q); len(r) > maxBlockedQuestionLen {
+		q = string(r[:maxBlockedQuestionLen]) + "…"
+	}
+	return q
+}
+
 // runMCPServer runs the workflow MCP server for a specific task.
 // This is invoked by Claude Code via the .mcp.json configuration.
 func runMCPServer(taskID int64) error {
@@ -4827,12 +5975,12 @@ func handleStopHook(database *db.DB, taskID int64, input *ClaudeHookInput) error
 			if pipeline.IsWorkflowTask(task) {
 				if reason := workflowStepUnfinishedReason(database, task); reason == "" {
 					if pipeline.IsTerminalStep(database, task) {
-						database.UpdateTaskStatus(taskID, db.StatusBlocked)
+						database.BlockTask(taskID, db.BlockedNeedsInput, pipeline.TerminalStepParkedLog)
 						database.AppendTaskLog(taskID, "system", pipeline.TerminalStepParkedLog)
 					} else if pipeline.IsGateStep(task) {
 						// A gate step is a human-review boundary: park it 'blocked' rather
 						// than advancing, so the next phase waits until a human closes it.
-						database.UpdateTaskStatus(taskID, db.StatusBlocked)
+						database.BlockTask(taskID, db.BlockedNeedsInput, pipeline.GateStepParkedLog)
 						database.AppendTaskLog(taskID, "system", pipeline.GateStepParkedLog)
 					} else {
 						database.UpdateTaskStatus(taskID, db.StatusDone)
@@ -4843,11 +5991,14 @@ func handleStopHook(database *db.DB, taskID int64, input *ClaudeHookInput) error
 					// input" reads as a question the agent never asked and hides
 					// what actually blocked the handoff (e.g. leftover untracked
 					// files), leaving the DAG stalled with no clue on the board.
-					database.UpdateTaskStatus(taskID, db.StatusBlocked)
-					database.AppendTaskLog(taskID, "system", "Step ended its turn without completing the handoff — "+reason)
+					msg := "Step ended its turn without completing the handoff — " + reason
+					database.BlockTask(taskID, db.BlockedNeedsInput, msg)
+					database.AppendTaskLog(taskID, "system", msg)
 				}
 			} else {
-				database.UpdateTaskStatus(taskID, db.StatusBlocked)
+				// Carry what Claude last said (usually its question) on the
+				// task.blocked event so hooks can show it.
+				database.BlockTask(taskID, db.BlockedNeedsInput, blockedQuestion(input.LastAssistantMessage))
 				database.AppendTaskLog(taskID, "system", "Waiting for user input")
 			}
 		}
@@ -5252,18 +6403,31 @@ func latestPendingToolDetail(database *db.DB, taskID int64) string {
 	return ""
 }
 
-// tailClaudeLogs tails all claude session logs for debugging.
-func tailClaudeLogs() error {
-	home, err := os.UserHomeDir()
+// tailClaudeLogs tails claude session logs under projectsDir for debugging,
+// limited to those the resolved filter matches (all of them if it is nil).
+// With raw, lines are printed as-is instead of formatted.
+func tailClaudeLogs(projectsDir string, resolve logResolver, raw bool, grep *logGrep) error {
+	filter, taskIDs, err := resolve()
 	if err != nil {
-		return fmt.Errorf("get home dir: %w", err)
+		return err
 	}
 
-	projectsDir := filepath.Join(home, ".claude", "projects")
-
 	// Find all .jsonl files
 	pattern := filepath.Join(projectsDir, "*", "*.jsonl")
-	files, err := filepath.Glob(pattern)
+	glob := func() ([]string, error) {
+		matches, err := filepath.Glob(pattern)
+		if err != nil || filter == nil {
+			return matches, err
+		}
+		var files []string
+		for _, f := range matches {
+			if filter.matches(f) {
+				files = append(files, f)
+			}
+		}
+		return files, nil
+	}
+	files, err := glob()
 	if err != nil {
 		return fmt.Errorf("glob: %w", err)
 	}
@@ -5320,8 +6484,12 @@ func tailClaudeLogs() error {
 
 			tick++
 			if tick%reglobEvery == 0 {
-				// Re-glob to catch new files.
-				files, _ = filepath.Glob(pattern)
+				// Re-resolve and re-glob to catch new files. If the database
+				// is briefly unavailable, keep following what we have.
+				if f, ids, err := resolve(); err == nil {
+					filter, taskIDs = f, ids
+				}
+				files, _ = glob()
 			}
 
 			for _, f := range files {
@@ -5338,7 +6506,7 @@ func tailClaudeLogs() error {
 				if info, err := os.Stat(f); err == nil && seen && info.Size() == pos {
 					continue
 				}
-				newPos, err := tailFile(f, pos)
+				newPos, err := tailFile(f, pos, logLabel(f, taskIDs), raw, grep)
 				if err == nil {
 					positions[f] = newPos
 				}
@@ -5347,8 +6515,10 @@ func tailClaudeLogs() error {
 	}
 }
 
-// tailFile reads new content from a file starting at the given position.
-func tailFile(path string, pos int64) (int64, error) {
+// tailFile reads new content from a file starting at the given position,
+// printing each entry after label, or each line verbatim when raw. Lines grep
+// doesn't keep are skipped.
+func tailFile(path string, pos int64, label string, raw bool, grep *logGrep) (int64, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return pos, err
@@ -5375,16 +6545,23 @@ func tailFile(path string, pos int64) (int64, error) {
 		return pos, err
 	}
 
-	// Extract project name from path for display
-	project := filepath.Base(filepath.Dir(path))
-	projectStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
 
 	scanner := bufio.NewScanner(file)
+	// Session entries (tool results, file reads) easily exceed the default
+	// 64KB token limit.
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
 	for scanner.Scan() {
 		line := scanner.Text()
 		if line == "" {
 			continue
 		}
+		if raw {
+			if grep.keep(line) {
+				fmt.Println(line)
+			}
+			continue
+		}
 
 		// Parse JSON and extract useful content
 		var entry map[string]interface{}
@@ -5394,8 +6571,11 @@ func tailFile(path string, pos int64) (int64, error) {
 
 		// Format output based on entry type
 		output := formatLogEntry(entry)
-		if output != "" {
-			fmt.Printf("%s %s\n", projectStyle.Render("["+project+"]"), output)
+		if output == "" {
+			continue
+		}
+		if out := labelStyle.Render(label) + " " + output; grep.keep(out) {
+			fmt.Println(out)
 		}
 	}
 
@@ -5442,12 +6622,42 @@ func formatLogEntry(entry map[string]interface{}) string {
 	return ""
 }
 
+// warnSecretFallback warns when a secret was stored in plaintext even though
+// secret_storage asked for something stronger.
+func warnSecretFallback(database *db.DB, storage string) {
+	mode := database.SecretStorageMode()
+	if storage != db.SecretStoragePlaintext || mode == db.SecretStoragePlaintext {
+		return
+	}
+	reason := "no OS keychain is available"
+	if mode == db.SecretStoragePassphrase {
+		reason = db.SecretPassphraseEnv + " is not set"
+	}
+	fmt.Println(warnStyle.Render("Warning: secret stored in plaintext because " + reason))
+}
+
 // unescapeNewlines converts literal "\n" sequences to actual newline characters
 // in CLI input. This allows users to enter multi-line text from the command line.
 func unescapeNewlines(s string) string {
 	return strings.ReplaceAll(s, "\\n", "\n")
 }
 
+// readBodyFile reads a task body from path, or from stdin when path is "-".
+// The content is used verbatim (no unescapeNewlines), minus trailing newlines.
+func readBodyFile(path string, stdin io.Reader) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("read body file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
 // truncate shortens a string to maxLen, adding ellipsis if needed.
 func truncate(s string, maxLen int) string {
 	// Replace newlines with spaces for single-line display
@@ -5471,19 +6681,26 @@ func (r *execCommandRunner) Output(name string, args ...string) ([]byte, error)
 	return osexec.Command(name, args...).Output()
 }
 
-// listSessions lists all running agent task windows in task-daemon.
-func listSessions() {
+// listSessions lists all running agent task windows in task-daemon, as a
+// table or as JSON.
+func listSessions(outputJSON bool) {
 	sessions := getSessions()
+	if outputJSON {
+		jsonBytes, _ := json.MarshalIndent(sessionsReport(sessions), "", "  ")
+		fmt.Println(string(jsonBytes))
+		return
+	}
+	printSessions(sessions)
+}
+
+// printSessions renders sessions as the `ty sessions` table.
+func printSessions(sessions []agentSession) {
 	if len(sessions) == 0 {
 		fmt.Println(dimStyle.Render("No agent sessions running"))
 		return
 	}
 
-	// Calculate total memory
-	totalMemoryMB := 0
-	for _, s := range sessions {
-		totalMemoryMB += s.memoryMB
-	}
+	totalMemoryMB := sessionsMemoryMB(sessions)
 
 	fmt.Printf("%s\n\n", boldStyle.Render(fmt.Sprintf("Running Agent Sessions (%d total, %dMB memory):", len(sessions), totalMemoryMB)))
 	for _, s := range sessions {
@@ -5510,16 +6727,80 @@ func listSessions() {
 	}
 }
 
+// sessionsMemoryMB sums the memory used by sessions.
+func sessionsMemoryMB(sessions []agentSession) int {
+	total := 0
+	for _, s := range sessions {
+		total += s.memoryMB
+	}
+	return total
+}
+
+// sessionJSON is one agent session in `ty sessions list --json`.
+type sessionJSON struct {
+	TaskID        int    `json:"task_id"`
+	Title         string `json:"title"`
+	Executor      string `json:"executor"`
+	Model         string `json:"model,omitempty"`
+	Effort        string `json:"effort,omitempty"`
+	MemoryMB      int    `json:"memory_mb"`
+	LastActivity  string `json:"last_activity,omitempty"` // RFC 3339
+	DaemonSession string `json:"daemon_session"`
+}
+
+// sessionsJSON is the `ty sessions list --json` envelope.
+type sessionsJSON struct {
+	Sessions      []sessionJSON `json:"sessions"`
+	Count         int           `json:"count"`
+	TotalMemoryMB int           `json:"total_memory_mb"`
+}
+
+// sessionsReport converts sessions to their JSON form.
+func sessionsReport(sessions []agentSession) sessionsJSON {
+	report := sessionsJSON{
+		Sessions:      []sessionJSON{},
+		Count:         len(sessions),
+		TotalMemoryMB: sessionsMemoryMB(sessions),
+	}
+	for _, s := range sessions {
+		executor := s.executor
+		if executor == "" {
+			executor = "claude" // default
+		}
+		entry := sessionJSON{
+			TaskID:        s.taskID,
+			Title:         s.taskTitle,
+			Executor:      executor,
+			Model:         s.model,
+			Effort:        s.effort,
+			MemoryMB:      s.memoryMB,
+			DaemonSession: s.daemonSession,
+		}
+		if !s.lastActivity.IsZero() {
+			entry.LastActivity = s.lastActivity.Format(time.RFC3339)
+		}
+		report.Sessions = append(report.Sessions, entry)
+	}
+	return report
+}
+
 type agentSession struct {
-	taskID    int
-	taskTitle string
-	executor  string // Executor name (claude, codex, gemini, etc.)
-	model     string // Per-task model override ("" = executor default)
-	effort    string // Per-task reasoning effort override ("" = executor default)
-	memoryMB  int    // Memory usage in MB
-	info      string
+	taskID        int
+	taskTitle     string
+	executor      string    // Executor name (claude, codex, gemini, etc.)
+	model         string    // Per-task model override ("" = executor default)
+	effort        string    // Per-task reasoning effort override ("" = executor default)
+	memoryMB      int       // Memory usage in MB
+	daemonSession string    // tmux session the window runs in
+	lastActivity  time.Time // Window's last activity (zero if unknown)
+	info          string
 }
 
+// invalidModelMessage is the error shown when a --model value fails
+// db.IsValidModel.
+var invalidModelMessage = "Invalid model. Must be one of: " + strings.Join(db.ModelOptions(), ", ") +
+	", a full claude-* model ID, or custom:<id>"
+
 // executorLabel renders the executor plus any per-task model/effort overrides,
 // e.g. "claude opus/high", "claude sonnet", "claude ·/high", or plain "claude".
 func executorLabel(executor, model, effort string) string {
@@ -5546,6 +6827,12 @@ func executorLabel(executor, model, effort string) string {
 
 // getSessions returns all running task-* windows across all task-daemon-* sessions.
 func getSessions() []agentSession {
+	return listAgentSessions(getAgentMemoryByTaskID())
+}
+
+// listAgentSessions enumerates task windows, filling memoryMB from taskMemory
+// (which may be nil when the caller measures memory itself).
+func listAgentSessions(taskMemory map[int]int) []agentSession {
 	// First, get all task-daemon-* sessions
 	sessionsCmd := osexec.Command("tmux", "list-sessions", "-F", "#{session_name}")
 	sessionsOut, err := sessionsCmd.Output()
@@ -5555,7 +6842,7 @@ func getSessions() []agentSession {
 
 	var daemonSessions []string
 	for _, session := range strings.Split(strings.TrimSpace(string(sessionsOut)), "\n") {
-		if strings.HasPrefix(session, "task-daemon-") {
+		if strings.HasPrefix(session, profile.DaemonSessionPrefix()) {
 			daemonSessions = append(daemonSessions, session)
 		}
 	}
@@ -5571,9 +6858,6 @@ func getSessions() []agentSession {
 		defer database.Close()
 	}
 
-	// Build map of task ID -> memory usage (supports all executors)
-	taskMemory := getAgentMemoryByTaskID()
-
 	var sessions []agentSession
 	seen := make(map[int]bool) // Avoid duplicates if same task appears in multiple sessions
 
@@ -5617,13 +6901,14 @@ func getSessions() []agentSession {
 			seen[taskID] = true
 
 			info := daemonSession
+			var lastActivity time.Time
 			if len(parts) >= 2 {
 				// Parse activity timestamp
 				var activity int64
 				fmt.Sscanf(parts[1], "%d", &activity)
 				if activity > 0 {
-					t := time.Unix(activity, 0)
-					info = fmt.Sprintf("%s, last activity %s", daemonSession, t.Format("15:04:05"))
+					lastActivity = time.Unix(activity, 0)
+					info = fmt.Sprintf("%s, last activity %s", daemonSession, lastActivity.Format("15:04:05"))
 				}
 			}
 
@@ -5645,13 +6930,15 @@ func getSessions() []agentSession {
 			memoryMB := taskMemory[taskID]
 
 			sessions = append(sessions, agentSession{
-				taskID:    taskID,
-				taskTitle: taskTitle,
-				executor:  taskExecutor,
-				model:     taskModel,
-				effort:    taskEffort,
-				memoryMB:  memoryMB,
-				info:      info,
+				taskID:        taskID,
+				taskTitle:     taskTitle,
+				executor:      taskExecutor,
+				model:         taskModel,
+				effort:        taskEffort,
+				memoryMB:      memoryMB,
+				daemonSession: daemonSession,
+				lastActivity:  lastActivity,
+				info:          info,
 			})
 		}
 	}
@@ -5660,10 +6947,23 @@ func getSessions() []agentSession {
 }
 
 // getAgentMemoryByTaskID returns a map of task ID -> memory (MB) for all agent processes.
-// It identifies task IDs by examining each agent process's working directory.
-// Supports all executors: claude, codex, gemini, openclaw, opencode, pi.
 func getAgentMemoryByTaskID() map[int]int {
 	result := make(map[int]int)
+	for taskID, pids := range getAgentPIDsByTaskID() {
+		for _, pid := range pids {
+			// If there are multiple agent processes for the same task, sum them
+			result[taskID] += getProcessMemoryMB(pid)
+		}
+	}
+	return result
+}
+
+// getAgentPIDsByTaskID returns a map of task ID -> agent process IDs.
+// It identifies task IDs by examining each agent process's working directory.
+// Supports all executors: claude, codex, gemini, openclaw, opencode, pi.
+func getAgentPIDsByTaskID() map[int][]int {
+	result := make(map[int][]int)
+	seen := make(map[int]bool) // pgrep -f patterns overlap (e.g. "pi")
 
 	// Find processes for all supported executors
 	executorNames := []string{"claude", "codex", "gemini", "openclaw", "opencode", "pi"}
@@ -5680,9 +6980,10 @@ func getAgentMemoryByTaskID() map[int]int {
 				continue
 			}
 			pid, err := strconv.Atoi(pidStr)
-			if err != nil {
+			if err != nil || seen[pid] {
 				continue
 			}
+			seen[pid] = true
 
 			// Get the process's current working directory using lsof
 			lsofOut, err := osexec.Command("lsof", "-p", pidStr, "-Fn").Output()
@@ -5710,9 +7011,7 @@ func getAgentMemoryByTaskID() map[int]int {
 				// Parse the task ID from the beginning of the path
 				var taskID int
 				if _, err := fmt.Sscanf(pathPart, "%d-", &taskID); err == nil && taskID > 0 {
-					memMB := getProcessMemoryMB(pid)
-					// If there are multiple agent processes for the same task, sum them
-					result[taskID] += memMB
+					result[taskID] = append(result[taskID], pid)
 				}
 			}
 		}
@@ -5766,7 +7065,7 @@ func killSessionAcrossDaemons(taskID int) bool {
 	killed := false
 
 	for _, session := range strings.Split(strings.TrimSpace(string(sessionsOut)), "\n") {
-		if !strings.HasPrefix(session, "task-daemon-") {
+		if !strings.HasPrefix(session, profile.DaemonSessionPrefix()) {
 			continue
 		}
 		windowTarget := fmt.Sprintf("%s:%s", session, windowName)
@@ -5889,9 +7188,9 @@ func suspendSessions(taskIDs []int, all bool) {
 
 	fmt.Println()
 	if totalFreedMB > 0 {
-		fmt.Println(successStyle.Render(fmt.Sprintf("Suspended %d session(s), ~%dMB freed", suspended, totalFreedMB)))
+		outln(successStyle.Render(fmt.Sprintf("Suspended %d session(s), ~%dMB freed", suspended, totalFreedMB)))
 	} else {
-		fmt.Println(successStyle.Render(fmt.Sprintf("Suspended %d session(s)", suspended)))
+		outln(successStyle.Render(fmt.Sprintf("Suspended %d session(s)", suspended)))
 	}
 	fmt.Println(dimStyle.Render("Session IDs preserved — use 'ty retry <task-id>' to resume"))
 }
@@ -5913,7 +7212,7 @@ func recoverStaleTmuxRefs(dryRun bool) {
 	sessionsOut, err := osexec.Command("tmux", "list-sessions", "-F", "#{session_name}").Output()
 	if err == nil {
 		for _, session := range strings.Split(strings.TrimSpace(string(sessionsOut)), "\n") {
-			if strings.HasPrefix(session, "task-daemon-") {
+			if strings.HasPrefix(session, profile.DaemonSessionPrefix()) {
 				activeSessions[session] = true
 			}
 		}
@@ -5973,7 +7272,7 @@ func recoverStaleTmuxRefs(dryRun bool) {
 	}
 
 	if staleDaemonCount == 0 && staleWindowCount == 0 {
-		fmt.Println(successStyle.Render("No stale references found - database is clean"))
+		outln(successStyle.Render("No stale references found - database is clean"))
 		return
 	}
 
@@ -6001,7 +7300,7 @@ func recoverStaleTmuxRefs(dryRun bool) {
 		if err != nil {
 			fmt.Fprintln(os.Stderr, errorStyle.Render("Error clearing daemon sessions: "+err.Error()))
 		} else {
-			fmt.Println(successStyle.Render(fmt.Sprintf("Cleared %d stale daemon_session references", staleDaemonCount)))
+			outln(successStyle.Render(fmt.Sprintf("Cleared %d stale daemon_session references", staleDaemonCount)))
 		}
 	}
 
@@ -6014,7 +7313,7 @@ func recoverStaleTmuxRefs(dryRun bool) {
 		if err != nil {
 			fmt.Fprintln(os.Stderr, errorStyle.Render("Error clearing window IDs: "+err.Error()))
 		} else {
-			fmt.Println(successStyle.Render(fmt.Sprintf("Cleared %d stale tmux_window_id references", staleWindowCount)))
+			outln(successStyle.Render(fmt.Sprintf("Cleared %d stale tmux_window_id references", staleWindowCount)))
 		}
 	}
 
@@ -6073,7 +7372,7 @@ func cleanupOrphanedSessions(force bool) {
 	var allWindows []windowRef
 	for _, session := range strings.Split(string(sessionsOut), "\n") {
 		session = strings.TrimSpace(session)
-		if !strings.HasPrefix(session, "task-daemon-") {
+		if !strings.HasPrefix(session, profile.DaemonSessionPrefix()) {
 			continue
 		}
 		windowsOut, err := osexec.Command("tmux", "list-windows", "-t", session, "-F", "#{window_name}").Output()
@@ -6128,7 +7427,7 @@ func cleanupOrphanedSessions(force bool) {
 
 	totalToKill := len(deletedWindows) + len(oldDoneWindows)
 	if totalToKill == 0 {
-		fmt.Println(successStyle.Render("No orphaned agent windows found"))
+		outln(successStyle.Render("No orphaned agent windows found"))
 		return
 	}
 
@@ -6178,83 +7477,6 @@ func killWindow(target string, force bool) error {
 	return osexec.Command("tmux", "kill-window", "-t", target).Run()
 }
 
-// moveTask moves a task to a different project by cleaning up old resources,
-// deleting the old task, and creating a new task in the target project.
-// Returns the new task ID.
-func moveTask(database *db.DB, oldTask *db.Task, targetProject string) (int64, error) {
-	cfg := config.New(database)
-	exec := executor.New(database, cfg)
-
-	// Step 1: Clean up old task's resources
-
-	// Kill agent session if running. Use the across-daemons variant because the
-	// CLI invocation's session ID rarely matches the daemon that originally
-	// spawned the window — the scoped killSession would silently miss it and
-	// leak the agent process. See sessions_test.go for repro.
-	killSessionAcrossDaemons(int(oldTask.ID))
-
-	// Clean up worktree and agent sessions if they exist
-	if oldTask.WorktreePath != "" {
-		projectConfigDir := ""
-		if oldTask.Project != "" {
-			if project, err := database.GetProjectByName(oldTask.Project); err == nil && project != nil {
-				projectConfigDir = project.ClaudeConfigDir
-			}
-		}
-		// Clean up Claude session files first (before worktree is removed)
-		if err := executor.CleanupClaudeSessions(oldTask.WorktreePath, projectConfigDir); err != nil {
-			fmt.Fprintln(os.Stderr, dimStyle.Render(fmt.Sprintf("Warning: could not remove Claude sessions: %v", err)))
-		}
-
-		// Clean up worktree
-		if err := exec.CleanupWorktree(oldTask); err != nil {
-			fmt.Fprintln(os.Stderr, dimStyle.Render(fmt.Sprintf("Warning: could not remove worktree: %v", err)))
-		}
-	}
-
-	// Step 2: Delete the old task from database
-	if err := database.DeleteTask(oldTask.ID); err != nil {
-		return 0, fmt.Errorf("delete old task: %w", err)
-	}
-
-	// Step 3: Create new task in target project
-	// Reset execution-related fields but preserve content
-	newTask := &db.Task{
-		Title:    oldTask.Title,
-		Body:     oldTask.Body,
-		Type:     oldTask.Type,
-		Tags:     oldTask.Tags,
-		Project:  targetProject,
-		Executor: oldTask.Executor,
-		Pinned:   oldTask.Pinned,
-		// Reset execution state
-		WorktreePath:    "",
-		BranchName:      "",
-		Port:            0,
-		ClaudeSessionID: "",
-		DaemonSession:   "",
-		StartedAt:       nil,
-		CompletedAt:     nil,
-		// Keep status unless it was processing/blocked
-		Status: oldTask.Status,
-	}
-
-	// Reset status if task was in progress (work is lost)
-	if newTask.Status == db.StatusProcessing || newTask.Status == db.StatusBlocked {
-		newTask.Status = db.StatusBacklog
-	}
-
-	if err := database.CreateTask(newTask); err != nil {
-		return 0, fmt.Errorf("create new task: %w", err)
-	}
-
-	// Notify about the changes
-	exec.NotifyTaskChange("deleted", oldTask)
-	exec.NotifyTaskChange("created", newTask)
-
-	return newTask.ID, nil
-}
-
 // deleteTask deletes a task, its agent session, and its worktree.
 // softDeleteTask trashes a task: it stops any running agent so the task no longer
 // consumes a session, but deliberately LEAVES the worktree and Claude transcript on
@@ -6308,6 +7530,11 @@ func hardDeleteTask(taskID int64) error {
 		return fmt.Errorf("task #%d not found", taskID)
 	}
 
+	undoID, err := database.RecordUndo(db.UndoHardDelete, taskID)
+	if err != nil {
+		return err
+	}
+
 	// Kill agent session if running. Use the across-daemons variant: the CLI's
 	// session ID rarely matches the daemon that originally spawned the window
 	// (UI launches with WORKTREE_SESSION_ID set to its own PID; the daemon
@@ -6329,6 +7556,7 @@ func hardDeleteTask(taskID int64) error {
 
 	// Delete from database
 	if err := database.DeleteTask(taskID); err != nil {
+		database.DeleteUndo(undoID)
 		return fmt.Errorf("delete task: %w", err)
 	}
 
@@ -6490,6 +7718,10 @@ func showProjectCLI(name string, outputJSON bool) {
 			"aliases":                 project.Aliases,
 			"use_worktrees":           project.UseWorktrees,
 			"default_permission_mode": project.EffectiveDefaultPermissionMode(),
+			"default_executor":        project.EffectiveExecutor(),
+			"default_model":           project.DefaultModel,
+			"base_branch":             project.BaseBranch,
+			"wip_limit":               project.WIPLimit,
 			"task_count":              taskCount,
 			"created_at":              project.CreatedAt.Time.Format(time.RFC3339),
 		}
@@ -6536,6 +7768,20 @@ func showProjectCLI(name string, outputJSON bool) {
 		fmt.Printf("%s %s\n", dimStyle.Render("Git Worktrees:"), "disabled (non-git project)")
 	}
 	fmt.Printf("%s %s\n", dimStyle.Render("Permission Mode:"), project.EffectiveDefaultPermissionMode())
+	fmt.Printf("%s %s\n", dimStyle.Render("Default Executor:"), project.EffectiveExecutor())
+	if project.DefaultModel != "" {
+		fmt.Printf("%s %s\n", dimStyle.Render("Default Model:"), project.DefaultModel)
+	}
+	if project.UseWorktrees {
+		baseBranch := project.BaseBranch
+		if baseBranch == "" {
+			baseBranch = "repo default"
+		}
+		fmt.Printf("%s %s\n", dimStyle.Render("Base Branch:"), baseBranch)
+	}
+	if project.WIPLimit > 0 {
+		fmt.Printf("%s %d\n", dimStyle.Render("WIP Limit:"), project.WIPLimit)
+	}
 
 	if project.Instructions != "" {
 		fmt.Println()
@@ -6564,12 +7810,25 @@ func showProjectCLI(name string, outputJSON bool) {
 }
 
 // createProjectCLI creates a new project.
-func createProjectCLI(name, path, instructions, color, aliases, claudeConfigDir, permissionMode string, noGit bool, outputJSON bool) {
+func createProjectCLI(name, path, instructions, color, aliases, claudeConfigDir, permissionMode, projectExecutor, projectModel, baseBranch string, wipLimit int, noGit bool, outputJSON bool) {
 	// Validate name
 	if strings.TrimSpace(name) == "" {
 		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: project name cannot be empty"))
 		os.Exit(1)
 	}
+	if wipLimit < 0 {
+		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: --wip-limit must be 0 (unlimited) or more"))
+		os.Exit(1)
+	}
+	if err := executor.ValidateExecutor(projectExecutor); err != nil {
+		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+		os.Exit(1)
+	}
+	projectModel = strings.TrimSpace(projectModel)
+	if !db.IsValidModel(projectModel) {
+		fmt.Fprintln(os.Stderr, errorStyle.Render(invalidModelMessage))
+		os.Exit(1)
+	}
 
 	// Expand path
 	if path == "" {
@@ -6593,6 +7852,12 @@ func createProjectCLI(name, path, instructions, color, aliases, claudeConfigDir,
 		os.Exit(1)
 	}
 
+	// Start from the repo's current default branch unless one was given
+	baseBranch = strings.TrimSpace(baseBranch)
+	if baseBranch == "" && !noGit {
+		baseBranch = detectDefaultBranch(absPath)
+	}
+
 	dbPath := db.DefaultPath()
 	database, err := openTaskDB(dbPath)
 	if err != nil {
@@ -6617,6 +7882,10 @@ func createProjectCLI(name, path, instructions, color, aliases, claudeConfigDir,
 		ClaudeConfigDir:       claudeConfigDir,
 		UseWorktrees:          !noGit,
 		DefaultPermissionMode: db.NormalizePermissionMode(permissionMode),
+		Executor:              projectExecutor,
+		DefaultModel:          projectModel,
+		BaseBranch:            baseBranch,
+		WIPLimit:              wipLimit,
 	}
 
 	if err := database.CreateProject(project); err != nil {
@@ -6635,6 +7904,9 @@ func createProjectCLI(name, path, instructions, color, aliases, claudeConfigDir,
 		if project.Color != "" {
 			output["color"] = project.Color
 		}
+		if project.BaseBranch != "" {
+			output["base_branch"] = project.BaseBranch
+		}
 		if project.Instructions != "" {
 			output["instructions"] = project.Instructions
 		}
@@ -6645,12 +7917,12 @@ func createProjectCLI(name, path, instructions, color, aliases, claudeConfigDir,
 		if !project.UseWorktrees {
 			suffix = " (non-git, worktrees disabled)"
 		}
-		fmt.Println(successStyle.Render(fmt.Sprintf("Created project '%s' at %s%s", name, absPath, suffix)))
+		outln(successStyle.Render(fmt.Sprintf("Created project '%s' at %s%s", name, absPath, suffix)))
 	}
 }
 
 // updateProjectCLI updates an existing project.
-func updateProjectCLI(currentName, newName, path, instructions, color, aliases, claudeConfigDir, projectContext, permissionMode string, useWorktrees *bool, outputJSON bool) {
+func updateProjectCLI(currentName, newName, path, instructions, color, aliases, claudeConfigDir, projectContext, permissionMode, projectExecutor string, projectModel, baseBranch *string, useWorktrees *bool, wipLimit *int, outputJSON bool) {
 	dbPath := db.DefaultPath()
 	database, err := openTaskDB(dbPath)
 	if err != nil {
@@ -6733,6 +8005,30 @@ func updateProjectCLI(currentName, newName, path, instructions, color, aliases,
 		changes = append(changes, "default permission mode")
 	}
 
+	if projectExecutor != "" {
+		if err := executor.ValidateExecutor(projectExecutor); err != nil {
+			fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+			os.Exit(1)
+		}
+		project.Executor = projectExecutor
+		changes = append(changes, "default executor")
+	}
+
+	if projectModel != nil {
+		m := strings.TrimSpace(*projectModel)
+		if !db.IsValidModel(m) {
+			fmt.Fprintln(os.Stderr, errorStyle.Render(invalidModelMessage))
+			os.Exit(1)
+		}
+		project.DefaultModel = m
+		changes = append(changes, "default model")
+	}
+
+	if baseBranch != nil {
+		project.BaseBranch = strings.TrimSpace(*baseBranch)
+		changes = append(changes, "base branch")
+	}
+
 	if useWorktrees != nil {
 		project.UseWorktrees = *useWorktrees
 		if *useWorktrees {
@@ -6742,6 +8038,15 @@ func updateProjectCLI(currentName, newName, path, instructions, color, aliases,
 		}
 	}
 
+	if wipLimit != nil {
+		if *wipLimit < 0 {
+			fmt.Fprintln(os.Stderr, errorStyle.Render("Error: --wip-limit must be 0 (unlimited) or more"))
+			os.Exit(1)
+		}
+		project.WIPLimit = *wipLimit
+		changes = append(changes, "WIP limit")
+	}
+
 	if len(changes) == 0 && projectContext == "" {
 		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: no changes specified"))
 		os.Exit(1)
@@ -6771,7 +8076,7 @@ func updateProjectCLI(currentName, newName, path, instructions, color, aliases,
 		jsonBytes, _ := json.Marshal(output)
 		fmt.Println(string(jsonBytes))
 	} else {
-		fmt.Println(successStyle.Render(fmt.Sprintf("Updated project '%s': %s", project.Name, strings.Join(changes, ", "))))
+		outln(successStyle.Render(fmt.Sprintf("Updated project '%s': %s", project.Name, strings.Join(changes, ", "))))
 	}
 }
 
@@ -6823,7 +8128,7 @@ func deleteProjectCLI(name string, force bool) {
 		os.Exit(1)
 	}
 
-	fmt.Println(successStyle.Render(fmt.Sprintf("Deleted project '%s'", name)))
+	outln(successStyle.Render(fmt.Sprintf("Deleted project '%s'", name)))
 }
 
 // parseKeyEvents parses a comma-separated string of keys into bubbletea KeyMsgs.
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/spf13/cobra"
+)
+
+func newMemoriesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "memories",
+		Short: "Manage project memories",
+		Long: `Project memories are short notes about a project - conventions to follow,
+decisions and their reasons, known pitfalls - kept alongside its tasks.
+
+Categories: ` + strings.Join(db.MemoryCategories(), ", ") + `
+
+Examples:
+  ty memories list myapp
+  ty memories list myapp --category gotcha --json
+  ty memories add myapp --category pattern "Wrap errors with fmt.Errorf and %w"
+  ty memories delete 12`,
+	}
+
+	listCmd := &cobra.Command{
+		Use:               "list <project>",
+		Short:             "List a project's memories",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeProjectNames,
+		Run: func(cmd *cobra.Command, args []string) {
+			category, _ := cmd.Flags().GetString("category")
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			memories, err := listMemoriesCLI(database, args[0], category)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			if outputJSON {
+				if memories == nil {
+					memories = []*db.ProjectMemory{}
+				}
+				jsonBytes, _ := json.Marshal(memories)
+				fmt.Println(string(jsonBytes))
+				return
+			}
+			if len(memories) == 0 {
+				fmt.Println(dimStyle.Render("No memories found"))
+				return
+			}
+			for _, m := range memories {
+				fmt.Printf("%s %s %s\n",
+					dimStyle.Render(fmt.Sprintf("#%-4d", m.ID)),
+					boldStyle.Render(fmt.Sprintf("%-9s", m.Category)),
+					m.Content)
+			}
+		},
+	}
+	listCmd.Flags().StringP("category", "c", "", "Only show memories in this category")
+	listCmd.Flags().Bool("json", false, "Output in JSON format")
+	listCmd.RegisterFlagCompletionFunc("category", completeMemoryCategories)
+	cmd.AddCommand(listCmd)
+
+	addCmd := &cobra.Command{
+		Use:               "add <project> <content>",
+		Short:             "Add a memory to a project",
+		Args:              cobra.MinimumNArgs(2),
+		ValidArgsFunction: completeProjectNames,
+		Run: func(cmd *cobra.Command, args []string) {
+			category, _ := cmd.Flags().GetString("category")
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			m, err := addMemoryCLI(database, args[0], category, strings.Join(args[1:], " "))
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			if outputJSON {
+				jsonBytes, _ := json.Marshal(m)
+				fmt.Println(string(jsonBytes))
+				return
+			}
+			outln(successStyle.Render(fmt.Sprintf("Added %s memory #%d to %s", m.Category, m.ID, m.Project)))
+		},
+	}
+	addCmd.Flags().StringP("category", "c", db.MemoryCategoryGeneral, "Memory category")
+	addCmd.Flags().Bool("json", false, "Output in JSON format")
+	addCmd.RegisterFlagCompletionFunc("category", completeMemoryCategories)
+	cmd.AddCommand(addCmd)
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete <id>",
+		Short: "Delete a memory",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			id, err := strconv.ParseInt(strings.TrimPrefix(args[0], "#"), 10, 64)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid memory ID: "+args[0]))
+				os.Exit(1)
+			}
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			if err := database.DeleteProjectMemory(id); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			outln(successStyle.Render(fmt.Sprintf("Deleted memory #%d", id)))
+		},
+	}
+	cmd.AddCommand(deleteCmd)
+
+	return cmd
+}
+
+// memoryProject resolves a project name or alias to the project's name, so
+// memories are always stored under the canonical name.
+func memoryProject(database *db.DB, name string) (string, error) {
+	p, err := database.GetProjectByName(name)
+	if err != nil {
+		return "", err
+	}
+	if p == nil {
+		return "", fmt.Errorf("project %q not found", name)
+	}
+	return p.Name, nil
+}
+
+// listMemoriesCLI returns the memories of project, optionally limited to one
+// category.
+func listMemoriesCLI(database *db.DB, project, category string) ([]*db.ProjectMemory, error) {
+	if category != "" && !db.IsValidMemoryCategory(category) {
+		return nil, fmt.Errorf("invalid category %q (valid: %s)", category, strings.Join(db.MemoryCategories(), ", "))
+	}
+	name, err := memoryProject(database, project)
+	if err != nil {
+		return nil, err
+	}
+	return database.ListProjectMemories(name, category)
+}
+
+// addMemoryCLI stores a new memory for project.
+func addMemoryCLI(database *db.DB, project, category, content string) (*db.ProjectMemory, error) {
+	name, err := memoryProject(database, project)
+	if err != nil {
+		return nil, err
+	}
+	m := &db.ProjectMemory{Project: name, Category: category, Content: strings.TrimSpace(content)}
+	if err := database.CreateProjectMemory(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// completeMemoryCategories completes the --category flag of ty memories.
+func completeMemoryCategories(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return db.MemoryCategories(), cobra.ShellCompDirectiveNoFileComp
+}
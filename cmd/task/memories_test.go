@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestMemoriesCLI(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	if err := database.CreateProject(&db.Project{Name: "app", Path: t.TempDir(), Aliases: "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := addMemoryCLI(database, "a", db.MemoryCategoryGotcha, "  CI runs on Go 1.21  ")
+	if err != nil {
+		t.Fatalf("addMemoryCLI: %v", err)
+	}
+	if m.Project != "app" || m.Content != "CI runs on Go 1.21" {
+		t.Errorf("memory stored as %+v", m)
+	}
+	if _, err := addMemoryCLI(database, "app", "", "Prefer table-driven tests"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := addMemoryCLI(database, "ghost", "", "x"); err == nil {
+		t.Error("added a memory to a missing project")
+	}
+
+	all, err := listMemoriesCLI(database, "app", "")
+	if err != nil || len(all) != 2 {
+		t.Fatalf("list all = %+v, %v", all, err)
+	}
+	gotchas, err := listMemoriesCLI(database, "app", db.MemoryCategoryGotcha)
+	if err != nil || len(gotchas) != 1 || gotchas[0].ID != m.ID {
+		t.Errorf("list gotchas = %+v, %v", gotchas, err)
+	}
+	if _, err := listMemoriesCLI(database, "app", "trivia"); err == nil {
+		t.Error("accepted an unknown category")
+	}
+}
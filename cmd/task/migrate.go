@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+// newMigrateCmd exposes the versioned schema state. Open refuses a database
+// stamped at an older version (unless TASKYOU_AUTO_MIGRATIONS is set), so
+// after a `ty upgrade` that changes the schema, `ty migrate up` is how the
+// new migrations get applied, typically after a `ty backup`.
+func newMigrateCmd() *cobra.Command {
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Inspect and apply database schema migrations",
+		Long: `Inspect and apply versioned database schema migrations.
+
+A new database gets every migration when it is created, and so does one from
+before versioned migrations existed. When an upgraded ty brings new migrations
+for a database that already has a schema version, they are not applied on
+their own: ty refuses to open the database until 'ty migrate up' applies them,
+so there is a chance to 'ty backup' first. Set ` + db.AutoMigrationsEnv + `=1 to
+have them applied whenever the database is opened instead. Each migration runs
+in its own transaction; a failing migration is rolled back and the database
+stays at the prior version.
+
+Examples:
+  ty migrate status
+  ty migrate status --json
+  ty backup && ty migrate up`,
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the current schema version and pending migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			database, err := db.OpenWithPendingMigrations(db.DefaultPath())
+			if err != nil {
+				return err
+			}
+			defer database.Close()
+
+			version, err := database.SchemaVersion()
+			if err != nil {
+				return err
+			}
+			states, err := database.MigrationStates()
+			if err != nil {
+				return err
+			}
+
+			pending := 0
+			for _, st := range states {
+				if !st.Applied {
+					pending++
+				}
+			}
+
+			if outputJSON {
+				var migrations []map[string]interface{}
+				for _, st := range states {
+					item := map[string]interface{}{
+						"version": st.Version,
+						"name":    st.Name,
+						"applied": st.Applied,
+					}
+					if st.AppliedAt != nil {
+						item["applied_at"] = st.AppliedAt.Time.Format(time.RFC3339)
+					}
+					migrations = append(migrations, item)
+				}
+				out := map[string]interface{}{
+					"database":       database.Path(),
+					"version":        version,
+					"latest_version": db.LatestSchemaVersion(),
+					"pending":        pending,
+					"migrations":     migrations,
+				}
+				jsonBytes, _ := json.MarshalIndent(out, "", "  ")
+				fmt.Println(string(jsonBytes))
+				return nil
+			}
+
+			fmt.Printf("%s %s\n", boldStyle.Render("Database:"), database.Path())
+			fmt.Printf("%s %d (latest %d)\n", boldStyle.Render("Schema version:"), version, db.LatestSchemaVersion())
+			fmt.Println()
+			for _, st := range states {
+				if st.Applied {
+					applied := ""
+					if st.AppliedAt != nil {
+						applied = dimStyle.Render(" applied " + st.AppliedAt.Time.Format("2006-01-02 15:04"))
+					}
+					fmt.Printf("  %s %3d  %s%s\n", successStyle.Render("✓"), st.Version, st.Name, applied)
+				} else {
+					fmt.Printf("  %s %3d  %s %s\n", warnStyle.Render("○"), st.Version, st.Name, dimStyle.Render("pending"))
+				}
+			}
+			if pending > 0 {
+				fmt.Println()
+				fmt.Println(warnStyle.Render(fmt.Sprintf("%d pending migration(s). Run 'ty migrate up' to apply.", pending)))
+			}
+			return nil
+		},
+	}
+	statusCmd.Flags().Bool("json", false, "Output in JSON format")
+	migrateCmd.AddCommand(statusCmd)
+
+	upCmd := &cobra.Command{
+		Use:   "up",
+		Short: "Apply all pending migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			database, err := db.OpenWithPendingMigrations(db.DefaultPath())
+			if err != nil {
+				return err
+			}
+			defer database.Close()
+
+			applied, err := database.ApplyPendingMigrations()
+			for _, m := range applied {
+				outln(successStyle.Render(fmt.Sprintf("Applied migration %d (%s)", m.Version, m.Name)))
+			}
+			if err != nil {
+				return err
+			}
+			if len(applied) == 0 {
+				fmt.Println(dimStyle.Render("Database schema is up to date"))
+			}
+			return nil
+		},
+	}
+	migrateCmd.AddCommand(upCmd)
+
+	return migrateCmd
+}
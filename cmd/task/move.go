@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bborn/workflow/internal/config"
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/executor"
+)
+
+// Fields a moved task keeps and the execution state it loses. The dry-run
+// printer lists these so the output matches what moveTask actually copies.
+var (
+	moveKeptFields  = []string{"title", "body", "type", "tags", "executor", "pinned", "status (processing/blocked reset to backlog)"}
+	moveResetFields = []string{"worktree path", "branch name", "port", "agent session ID", "daemon session", "started/completed timestamps"}
+)
+
+// movePlan is everything moveTask will do to move a task to another project.
+// It is computed up front so `ty move --dry-run` prints exactly what the real
+// move then executes.
+type movePlan struct {
+	OldTask       *db.Task
+	TargetProject string
+	// WorktreePath is the worktree to remove; empty when the task has none.
+	WorktreePath string
+	// ClaudeConfigDir is the source project's CLAUDE_CONFIG_DIR override.
+	ClaudeConfigDir string
+	// SessionDir is the Claude session directory to remove; empty when the
+	// task has no worktree.
+	SessionDir string
+	// NewTask is the task that will be created in the target project.
+	NewTask *db.Task
+}
+
+// planMove computes the move of oldTask to targetProject without changing
+// anything.
+func planMove(database *db.DB, oldTask *db.Task, targetProject string) *movePlan {
+	plan := &movePlan{
+		OldTask:       oldTask,
+		TargetProject: targetProject,
+		WorktreePath:  oldTask.WorktreePath,
+	}
+	if oldTask.WorktreePath != "" {
+		if oldTask.Project != "" {
+			if project, err := database.GetProjectByName(oldTask.Project); err == nil && project != nil {
+				plan.ClaudeConfigDir = project.ClaudeConfigDir
+			}
+		}
+		plan.SessionDir = executor.ClaudeSessionDir(oldTask.WorktreePath, plan.ClaudeConfigDir)
+	}
+
+	// Reset execution-related fields but preserve content
+	plan.NewTask = &db.Task{
+		Title:    oldTask.Title,
+		Body:     oldTask.Body,
+		Type:     oldTask.Type,
+		Tags:     oldTask.Tags,
+		Project:  targetProject,
+		Executor: oldTask.Executor,
+		Pinned:   oldTask.Pinned,
+		// Keep status unless it was processing/blocked
+		Status: oldTask.Status,
+	}
+	// Reset status if task was in progress (work is lost)
+	if plan.NewTask.Status == db.StatusProcessing || plan.NewTask.Status == db.StatusBlocked {
+		plan.NewTask.Status = db.StatusBacklog
+	}
+	return plan
+}
+
+// printMovePlan writes a human-readable description of plan to w.
+func printMovePlan(w io.Writer, plan *movePlan) {
+	old := plan.OldTask
+	fmt.Fprintln(w, boldStyle.Render(fmt.Sprintf("Move task #%d from '%s' to '%s' (dry run)", old.ID, old.Project, plan.TargetProject)))
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, boldStyle.Render("Cleanup:"))
+	fmt.Fprintf(w, "  Stop agent session for task #%d (if running)\n", old.ID)
+	if plan.WorktreePath != "" {
+		fmt.Fprintf(w, "  Remove worktree: %s\n", plan.WorktreePath)
+		if old.BranchName != "" {
+			fmt.Fprintf(w, "  Branch: %s\n", old.BranchName)
+		}
+		fmt.Fprintf(w, "  Remove Claude sessions: %s\n", plan.SessionDir)
+	} else {
+		fmt.Fprintln(w, dimStyle.Render("  No worktree or Claude sessions to remove"))
+	}
+	fmt.Fprintf(w, "  Delete task #%d\n", old.ID)
+	fmt.Fprintln(w)
+
+	task := plan.NewTask
+	fmt.Fprintln(w, boldStyle.Render("New task:"))
+	fmt.Fprintf(w, "  Title:    %s\n", task.Title)
+	fmt.Fprintf(w, "  Project:  %s\n", task.Project)
+	status := task.Status
+	if status != old.Status {
+		status += dimStyle.Render(" (was " + old.Status + ")")
+	}
+	fmt.Fprintf(w, "  Status:   %s\n", status)
+	if task.Type != "" {
+		fmt.Fprintf(w, "  Type:     %s\n", task.Type)
+	}
+	if task.Tags != "" {
+		fmt.Fprintf(w, "  Tags:     %s\n", task.Tags)
+	}
+	if task.Executor != "" {
+		fmt.Fprintf(w, "  Executor: %s\n", task.Executor)
+	}
+	if task.Pinned {
+		fmt.Fprintln(w, "  Pinned:   yes")
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "%s %s\n", dimStyle.Render("Preserved:"), strings.Join(moveKeptFields, ", "))
+	fmt.Fprintf(w, "%s %s\n", dimStyle.Render("Reset:"), strings.Join(moveResetFields, ", "))
+}
+
+// moveTask moves a task to a different project by cleaning up old resources,
+// deleting the old task, and creating a new task in the target project.
+// Returns the new task ID.
+func moveTask(database *db.DB, oldTask *db.Task, targetProject string) (int64, error) {
+	return executeMovePlan(database, planMove(database, oldTask, targetProject))
+}
+
+// executeMovePlan carries out a plan computed by planMove.
+func executeMovePlan(database *db.DB, plan *movePlan) (int64, error) {
+	cfg := config.New(database)
+	exec := executor.New(database, cfg)
+	oldTask := plan.OldTask
+
+	undoID, err := database.RecordUndo(db.UndoMove, oldTask.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Step 1: Clean up old task's resources
+
+	// Kill agent session if running. Use the across-daemons variant because the
+	// CLI invocation's session ID rarely matches the daemon that originally
+	// spawned the window — the scoped killSession would silently miss it and
+	// leak the agent process. See sessions_test.go for repro.
+	killSessionAcrossDaemons(int(oldTask.ID))
+
+	// Clean up worktree and agent sessions if they exist
+	if plan.WorktreePath != "" {
+		// Clean up Claude session files first (before worktree is removed)
+		if err := executor.CleanupClaudeSessions(plan.WorktreePath, plan.ClaudeConfigDir); err != nil {
+			fmt.Fprintln(os.Stderr, dimStyle.Render(fmt.Sprintf("Warning: could not remove Claude sessions: %v", err)))
+		}
+
+		// Clean up worktree
+		if err := exec.CleanupWorktree(oldTask); err != nil {
+			fmt.Fprintln(os.Stderr, dimStyle.Render(fmt.Sprintf("Warning: could not remove worktree: %v", err)))
+		}
+	}
+
+	// Step 2: Delete the old task from database
+	if err := database.DeleteTask(oldTask.ID); err != nil {
+		database.DeleteUndo(undoID)
+		return 0, fmt.Errorf("delete old task: %w", err)
+	}
+
+	// Step 3: Create new task in target project
+	newTask := plan.NewTask
+	if err := database.CreateTask(newTask); err != nil {
+		// Put the old task back rather than leave the move half done.
+		if rerr := database.RevertUndo(undoID); rerr != nil {
+			return 0, fmt.Errorf("create new task: %w (restoring #%d also failed: %v)", err, oldTask.ID, rerr)
+		}
+		return 0, fmt.Errorf("create new task: %w (task #%d was restored)", err, oldTask.ID)
+	}
+	database.RecordTaskMoved(newTask.ID, oldTask.ID, oldTask.Project)
+	if err := database.SetUndoNewTaskID(undoID, newTask.ID); err != nil {
+		fmt.Fprintln(os.Stderr, dimStyle.Render(fmt.Sprintf("Warning: this move can't be undone: %v", err)))
+	}
+
+	// Notify about the changes
+	exec.NotifyTaskChange("deleted", oldTask)
+	exec.NotifyTaskChange("created", newTask)
+
+	return newTask.ID, nil
+}
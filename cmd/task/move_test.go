@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestMoveDryRunDoesNotMutate(t *testing.T) {
+	tmpDir := t.TempDir()
+	database, err := db.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	for _, name := range []string{"src-project", "tgt-project"} {
+		dir := filepath.Join(tmpDir, name)
+		os.MkdirAll(dir, 0755)
+		if err := database.CreateProject(&db.Project{Name: name, Path: dir}); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+	}
+
+	worktree := filepath.Join(tmpDir, "src-project", ".task-worktrees", "7-move-me")
+	task := &db.Task{
+		Title:   "Move me",
+		Status:  db.StatusBlocked,
+		Type:    db.TypeCode,
+		Tags:    "infra",
+		Project: "src-project",
+	}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+	task.WorktreePath = worktree
+	task.BranchName = "task/7-move-me"
+	if err := database.UpdateTask(task); err != nil {
+		t.Fatalf("failed to update task: %v", err)
+	}
+
+	plan := planMove(database, task, "tgt-project")
+	if plan.WorktreePath != worktree {
+		t.Errorf("WorktreePath = %q, want %q", plan.WorktreePath, worktree)
+	}
+	if plan.SessionDir == "" {
+		t.Error("expected a Claude session dir to clean up")
+	}
+	if plan.NewTask.Status != db.StatusBacklog {
+		t.Errorf("new status = %q, want backlog for a blocked task", plan.NewTask.Status)
+	}
+	if plan.NewTask.Project != "tgt-project" || plan.NewTask.Title != "Move me" || plan.NewTask.Tags != "infra" {
+		t.Errorf("unexpected new task: %+v", plan.NewTask)
+	}
+
+	var buf bytes.Buffer
+	printMovePlan(&buf, plan)
+	out := buf.String()
+	for _, want := range []string{worktree, plan.SessionDir, "Delete task #", "tgt-project", "Preserved:", "Reset:", "worktree path"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dry-run output missing %q:\n%s", want, out)
+		}
+	}
+
+	// Nothing changed: the task is still there, untouched, and no task was created.
+	got, err := database.GetTask(task.ID)
+	if err != nil || got == nil {
+		t.Fatalf("task should still exist after planning: %v", err)
+	}
+	if got.Project != "src-project" || got.Status != db.StatusBlocked {
+		t.Errorf("task changed by dry run: project=%q status=%q", got.Project, got.Status)
+	}
+	tasks, err := database.ListTasks(db.ListTasksOptions{Project: "tgt-project", IncludeClosed: true})
+	if err != nil {
+		t.Fatalf("ListTasks() error = %v", err)
+	}
+	if len(tasks) != 0 {
+		t.Errorf("dry run created %d task(s) in target project", len(tasks))
+	}
+}
+
+func TestMoveExecutesPlan(t *testing.T) {
+	tmpDir := t.TempDir()
+	database, err := db.Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	for _, name := range []string{"src-project", "tgt-project"} {
+		dir := filepath.Join(tmpDir, name)
+		os.MkdirAll(dir, 0755)
+		if err := database.CreateProject(&db.Project{Name: name, Path: dir}); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+	}
+	task := &db.Task{Title: "Move me", Status: db.StatusProcessing, Project: "src-project", Pinned: true}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+
+	plan := planMove(database, task, "tgt-project")
+	newID, err := executeMovePlan(database, plan)
+	if err != nil {
+		t.Fatalf("executeMovePlan() error = %v", err)
+	}
+	moved, err := database.GetTask(newID)
+	if err != nil || moved == nil {
+		t.Fatalf("moved task missing: %v", err)
+	}
+	if moved.Status != plan.NewTask.Status || moved.Project != plan.NewTask.Project || moved.Pinned != plan.NewTask.Pinned {
+		t.Errorf("moved task %+v does not match plan %+v", moved, plan.NewTask)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/spf13/cobra"
+)
+
+func newNoteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "note <task-id> [text]",
+		Short:             "Leave a note on a task without sending it to the agent",
+		ValidArgsFunction: completeTaskIDs,
+		Long: `Appends a note to the task's log. Notes are for you: they are never sent to
+the running agent and never change the task's status. They show in
+'ty show --logs' with a [note] prefix; pinned notes also show at the top of
+the task's details.
+
+With no text argument, the note is read from stdin.
+
+Examples:
+  ty note 42 "Check the staging deploy before merging"
+  ty note 42 --pin "Customer wants this by Friday"
+  git log -1 --format=%B | ty note 42`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid task ID: "+args[0]))
+				os.Exit(1)
+			}
+			pin, _ := cmd.Flags().GetBool("pin")
+
+			var text string
+			if len(args) == 2 {
+				text = args[1]
+			} else {
+				data, err := io.ReadAll(os.Stdin)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+				text = string(data)
+			}
+			text = strings.TrimSpace(text)
+			if text == "" {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: note text is empty"))
+				os.Exit(1)
+			}
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			if err := addTaskNote(database, taskID, text, pin); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			msg := fmt.Sprintf("Added note to task #%d", taskID)
+			if pin {
+				msg = fmt.Sprintf("Pinned note to task #%d", taskID)
+			}
+			outln(successStyle.Render(msg))
+		},
+	}
+	cmd.Flags().Bool("pin", false, "Also show the note at the top of the task's details")
+	return cmd
+}
+
+// addTaskNote appends a user note to a task's log. It only writes the log
+// line, so the task's status is never touched.
+func addTaskNote(database *db.DB, taskID int64, text string, pin bool) error {
+	task, err := database.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return fmt.Errorf("task #%d not found", taskID)
+	}
+	lineType := db.NoteLineType
+	if pin {
+		lineType = db.PinnedNoteLineType
+	}
+	return database.AppendTaskLog(taskID, lineType, text)
+}
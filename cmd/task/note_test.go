@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestAddTaskNote(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	ids := createTestTasks(t, database, 1)
+	if err := database.UpdateTaskStatus(ids[0], db.StatusBlocked); err != nil {
+		t.Fatalf("UpdateTaskStatus: %v", err)
+	}
+
+	if err := addTaskNote(database, ids[0], "remember the flag", false); err != nil {
+		t.Fatalf("addTaskNote: %v", err)
+	}
+	if err := addTaskNote(database, ids[0], "ship by friday", true); err != nil {
+		t.Fatalf("addTaskNote pinned: %v", err)
+	}
+
+	task, err := database.GetTask(ids[0])
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	if task.Status != db.StatusBlocked {
+		t.Errorf("notes must not change status, got %q", task.Status)
+	}
+
+	logs, err := database.GetTaskLogs(ids[0], 10)
+	if err != nil {
+		t.Fatalf("GetTaskLogs: %v", err)
+	}
+	var notes int
+	for _, l := range logs {
+		if l.LineType == db.NoteLineType || l.LineType == db.PinnedNoteLineType {
+			notes++
+		}
+	}
+	if notes != 2 {
+		t.Errorf("expected 2 note lines, got %d", notes)
+	}
+
+	pinned, err := database.GetPinnedNotes(ids[0])
+	if err != nil {
+		t.Fatalf("GetPinnedNotes: %v", err)
+	}
+	if len(pinned) != 1 || pinned[0].Content != "ship by friday" {
+		t.Errorf("unexpected pinned notes: %+v", pinned)
+	}
+
+	if err := addTaskNote(database, 99999, "nope", false); err == nil {
+		t.Errorf("expected an error for a missing task")
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/executor"
+	"github.com/spf13/cobra"
+)
+
+func newOpenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "open <task-id>",
+		Short:             "Open a task's worktree in your editor, or print its path",
+		ValidArgsFunction: completeTaskIDs,
+		Long: `Opens a task's worktree in an editor: the project's editor from .taskyou.yml
+(editor: code), else $VISUAL, else $EDITOR, else the system's default opener.
+
+Examples:
+  ty open 42
+  cd "$(ty open 42 --path)"
+  ty open 42 --tmux           # Switch this tmux client to the task's agent window`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid task ID: "+args[0]))
+				os.Exit(1)
+			}
+			pathOnly, _ := cmd.Flags().GetBool("path")
+			toTmux, _ := cmd.Flags().GetBool("tmux")
+			if pathOnly && toTmux {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: --path and --tmux cannot be combined"))
+				os.Exit(1)
+			}
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			task, err := database.GetTask(taskID)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if task == nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Task #%d not found", taskID)))
+				os.Exit(1)
+			}
+
+			if toTmux {
+				if err := switchToTaskPane(task); err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+				return
+			}
+
+			if err := checkOpenableWorktree(task); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if pathOnly {
+				fmt.Println(task.WorktreePath)
+				return
+			}
+
+			projectDir := ""
+			if project, err := database.GetProjectByName(task.Project); err == nil && project != nil {
+				projectDir = project.Path
+			}
+			if err := openInEditor(resolveOpenEditor(projectDir), task.WorktreePath); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().Bool("path", false, "Print the worktree path instead of opening it")
+	cmd.Flags().Bool("tmux", false, "Switch the current tmux client to the task's agent window")
+	return cmd
+}
+
+// checkOpenableWorktree reports why a task's worktree can't be opened, with
+// the command that would bring it back.
+func checkOpenableWorktree(task *db.Task) error {
+	missing := task.WorktreePath == ""
+	if !missing {
+		_, err := os.Stat(task.WorktreePath)
+		missing = os.IsNotExist(err)
+	}
+	switch {
+	case !missing:
+		return nil
+	case task.HasArchiveState():
+		return fmt.Errorf("task #%d is archived; restore its worktree with 'ty unarchive %d'", task.ID, task.ID)
+	case task.WorktreePath == "":
+		return fmt.Errorf("task #%d has no worktree yet", task.ID)
+	}
+	return fmt.Errorf("worktree %s no longer exists; after a crash, run 'ty recover' and reopen the task to recreate it", task.WorktreePath)
+}
+
+// resolveOpenEditor picks the editor command for ty open: the project's
+// configured editor, then VISUAL, then EDITOR. "" means the system opener.
+func resolveOpenEditor(projectDir string) string {
+	if projectDir != "" {
+		if editor := executor.ProjectEditor(projectDir); editor != "" {
+			return editor
+		}
+	}
+	if editor := os.Getenv("VISUAL"); editor != "" {
+		return editor
+	}
+	return os.Getenv("EDITOR")
+}
+
+// openInEditor runs editor (which may include arguments, e.g. "code -n") on
+// path in the foreground, so terminal editors take over the terminal. With no
+// editor, it hands path to the system opener.
+func openInEditor(editor, path string) error {
+	var c *osexec.Cmd
+	if fields := strings.Fields(editor); len(fields) > 0 {
+		c = osexec.Command(fields[0], append(fields[1:], path)...)
+	} else {
+		opener := "xdg-open"
+		if runtime.GOOS == "darwin" {
+			opener = "open"
+		}
+		c = osexec.Command(opener, path)
+	}
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	return nil
+}
+
+// switchToTaskPane points the current tmux client at the task's agent pane.
+func switchToTaskPane(task *db.Task) error {
+	if task.ClaudePaneID == "" {
+		return fmt.Errorf("task #%d has no agent window; start it with 'ty execute %d'", task.ID, task.ID)
+	}
+	if os.Getenv("TMUX") == "" {
+		return fmt.Errorf("--tmux only works inside tmux")
+	}
+	out, err := osexec.Command("tmux", "switch-client", "-t", task.ClaudePaneID).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("switch to task #%d's window: %s", task.ID, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
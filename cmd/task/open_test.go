@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestResolveOpenEditor(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "vim")
+
+	projectDir := t.TempDir()
+	if got := resolveOpenEditor(projectDir); got != "vim" {
+		t.Errorf("without project config got %q, want vim", got)
+	}
+
+	t.Setenv("VISUAL", "code -w")
+	if got := resolveOpenEditor(projectDir); got != "code -w" {
+		t.Errorf("VISUAL should win over EDITOR, got %q", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(projectDir, ".taskyou.yml"), []byte("editor: zed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := resolveOpenEditor(projectDir); got != "zed" {
+		t.Errorf("project editor should win, got %q", got)
+	}
+}
+
+func TestCheckOpenableWorktree(t *testing.T) {
+	dir := t.TempDir()
+	if err := checkOpenableWorktree(&db.Task{ID: 1, WorktreePath: dir}); err != nil {
+		t.Errorf("existing worktree: %v", err)
+	}
+
+	err := checkOpenableWorktree(&db.Task{ID: 2, WorktreePath: filepath.Join(dir, "gone")})
+	if err == nil || !strings.Contains(err.Error(), "ty recover") {
+		t.Errorf("missing worktree should suggest ty recover, got %v", err)
+	}
+
+	err = checkOpenableWorktree(&db.Task{ID: 3, ArchiveRef: "refs/task-archive/3", ArchiveCommit: "abc"})
+	if err == nil || !strings.Contains(err.Error(), "ty unarchive 3") {
+		t.Errorf("archived task should suggest ty unarchive, got %v", err)
+	}
+}
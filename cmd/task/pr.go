@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bborn/workflow/internal/config"
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/github"
+	"github.com/spf13/cobra"
+)
+
+func newPRCmd() *cobra.Command {
+	prCmd := &cobra.Command{
+		Use:   "pr",
+		Short: "Work with a task's pull request",
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create <task-id>",
+		Short: "Push a task's branch and open a pull request",
+		Long: `Pushes the task's branch to origin and opens a pull request with the gh CLI,
+using the task's title and body as the PR title and description. The PR is
+then linked to the task, so its status shows in 'ty show' and 'ty list --pr'.
+
+The task needs a worktree with at least one commit on its branch that isn't
+on the base branch.
+
+Examples:
+  ty pr create 42
+  ty pr create 42 --draft
+  ty pr create 42 --base release-2.0`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTaskIDs,
+		Run: func(cmd *cobra.Command, args []string) {
+			draft, _ := cmd.Flags().GetBool("draft")
+			base, _ := cmd.Flags().GetString("base")
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			taskID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid task ID: "+args[0]))
+				os.Exit(1)
+			}
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			task, err := database.GetTask(taskID)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if task == nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Task #%d not found", taskID)))
+				os.Exit(1)
+			}
+			if task.PRURL != "" {
+				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Error: task #%d already has a pull request: %s", taskID, task.PRURL)))
+				os.Exit(1)
+			}
+
+			if base == "" {
+				if project, _ := database.GetProjectByName(task.Project); project != nil {
+					base = project.BaseBranch
+				}
+			}
+			if err := checkPRBranch(task, base); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			if !outputJSON {
+				fmt.Println(dimStyle.Render(fmt.Sprintf("Pushing %s to origin...", task.BranchName)))
+			}
+			push := osexec.Command("git", "push", "-u", "origin", task.BranchName)
+			push.Dir = task.WorktreePath
+			if out, err := push.CombinedOutput(); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Error: git push: %s", strings.TrimSpace(string(out)))))
+				os.Exit(1)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+			defer cancel()
+			number, url, err := github.CreatePR(ctx, task.WorktreePath, github.CreatePROptions{
+				Head:  task.BranchName,
+				Base:  base,
+				Title: task.Title,
+				Body:  task.Body,
+				Draft: draft,
+			})
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if err := database.UpdateTaskPRInfo(taskID, url, number, ""); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			if outputJSON {
+				jsonBytes, _ := json.Marshal(map[string]interface{}{
+					"id":        taskID,
+					"pr_number": number,
+					"pr_url":    url,
+					"draft":     draft,
+				})
+				fmt.Println(string(jsonBytes))
+				return
+			}
+			msg := fmt.Sprintf("Opened PR #%d for task #%d: %s", number, taskID, url)
+			if draft {
+				msg = fmt.Sprintf("Opened draft PR #%d for task #%d: %s", number, taskID, url)
+			}
+			outln(successStyle.Render(msg))
+		},
+	}
+	createCmd.Flags().Bool("draft", false, "Open the pull request as a draft")
+	createCmd.Flags().String("base", "", "Branch to merge into (default: the project's base branch, else the repository's default branch)")
+	createCmd.Flags().Bool("json", false, "Output in JSON format")
+	prCmd.AddCommand(createCmd)
+
+	return prCmd
+}
+
+// checkPRBranch verifies a task's worktree is ready for a pull request: it
+// has a branch, an origin remote, and commits that aren't on base ("" = the
+// default branch).
+func checkPRBranch(task *db.Task, base string) error {
+	if task.WorktreePath == "" || task.BranchName == "" {
+		return fmt.Errorf("task #%d has no worktree branch (run it first)", task.ID)
+	}
+	if _, err := os.Stat(task.WorktreePath); err != nil {
+		return fmt.Errorf("task #%d's worktree is gone: %s", task.ID, task.WorktreePath)
+	}
+	if _, err := gitOutput(task.WorktreePath, "remote", "get-url", "origin"); err != nil {
+		return fmt.Errorf("no 'origin' remote is configured in %s", task.WorktreePath)
+	}
+
+	baseRef := ""
+	if base != "" {
+		baseRef = base
+		if _, err := gitOutput(task.WorktreePath, "rev-parse", "--verify", "origin/"+base); err == nil {
+			baseRef = "origin/" + base
+		} else if _, err := gitOutput(task.WorktreePath, "rev-parse", "--verify", base); err != nil {
+			return fmt.Errorf("base branch %q not found", base)
+		}
+	} else {
+		baseRef = worktreeDefaultBase(task.WorktreePath)
+	}
+
+	if baseRef != "" {
+		count, err := gitOutput(task.WorktreePath, "rev-list", "--count", baseRef+".."+task.BranchName)
+		if err != nil {
+			return fmt.Errorf("count commits on %s: %w", task.BranchName, err)
+		}
+		if count == "0" {
+			return fmt.Errorf("branch %s has no commits that aren't on %s", task.BranchName, baseRef)
+		}
+	}
+	return nil
+}
+
+// worktreeDefaultBase returns the ref of the repository's default branch as
+// seen from dir: "origin/main" when origin's HEAD is known, otherwise a local
+// main or master, or "" if neither exists.
+func worktreeDefaultBase(dir string) string {
+	if head, err := gitOutput(dir, "symbolic-ref", "refs/remotes/origin/HEAD"); err == nil && head != "" {
+		return strings.TrimPrefix(head, "refs/remotes/")
+	}
+	for _, b := range []string{"main", "master"} {
+		if _, err := gitOutput(dir, "rev-parse", "--verify", b); err == nil {
+			return b
+		}
+	}
+	return ""
+}
+
+// gitOutput runs git in dir and returns its trimmed stdout.
+func gitOutput(dir string, args ...string) (string, error) {
+	c := osexec.Command("git", args...)
+	c.Dir = dir
+	out, err := c.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// prCacheTTL returns the pr_cache_ttl setting.
+func prCacheTTL(database *db.DB) time.Duration {
+	if val, err := database.GetSetting(config.SettingPRCacheTTL); err == nil && val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d >= 0 {
+			return d
+		}
+	}
+	return github.DefaultPRCacheTTL
+}
+
+// lookupTaskPR returns the PR for a task's branch through the database cache.
+// Entries are keyed by the project directory, the same key the daemon uses
+// when it refreshes active tasks, while the live fetch runs in the task's
+// worktree when it has one.
+func lookupTaskPR(database *db.DB, cfg *config.Config, cache *github.PRCache, task *db.Task, ttl time.Duration, refresh bool) *github.PRInfo {
+	projectDir := cfg.GetProjectDir(task.Project)
+	repoDir := task.WorktreePath
+	if repoDir == "" {
+		repoDir = projectDir
+	}
+	return cache.LookupPR(database, projectDir, repoDir, task.BranchName, ttl, refresh)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestCheckPRBranch(t *testing.T) {
+	if err := checkPRBranch(&db.Task{ID: 1}, ""); err == nil || !strings.Contains(err.Error(), "no worktree") {
+		t.Errorf("task without a worktree: %v", err)
+	}
+
+	remote := t.TempDir()
+	git(t, remote, "init", "-q", "--bare", "-b", "main")
+
+	dir := t.TempDir()
+	git(t, dir, "init", "-q", "-b", "main")
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("hi\n"), 0644)
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-q", "-m", "init")
+	git(t, dir, "checkout", "-q", "-b", "task/1-fix")
+	task := &db.Task{ID: 1, WorktreePath: dir, BranchName: "task/1-fix"}
+
+	if err := checkPRBranch(task, ""); err == nil || !strings.Contains(err.Error(), "origin") {
+		t.Errorf("no remote: %v", err)
+	}
+
+	git(t, dir, "remote", "add", "origin", remote)
+	if err := checkPRBranch(task, ""); err == nil || !strings.Contains(err.Error(), "no commits") {
+		t.Errorf("branch without commits: %v", err)
+	}
+	if err := checkPRBranch(task, "nope"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("unknown base: %v", err)
+	}
+
+	os.WriteFile(filepath.Join(dir, "fix.go"), []byte("package fix\n"), 0644)
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-q", "-m", "fix")
+	if err := checkPRBranch(task, ""); err != nil {
+		t.Errorf("ready branch: %v", err)
+	}
+	if err := checkPRBranch(task, "main"); err != nil {
+		t.Errorf("ready branch with --base main: %v", err)
+	}
+}
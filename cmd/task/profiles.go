@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/profile"
+)
+
+// newProfilesCmd manages named profiles: separate task databases selected with
+// the global --profile flag (or TY_PROFILE). Each profile's daemon, pid file
+// and tmux sessions are namespaced, so profiles can run side by side.
+func newProfilesCmd() *cobra.Command {
+	profilesCmd := &cobra.Command{
+		Use:   "profiles",
+		Short: "Manage profiles (separate task databases)",
+		Long: `Manage profiles. A profile is a named task database, selected for any
+command with the global --profile flag or the ` + profile.Env + ` environment variable.
+A name that hasn't been added uses ` + profile.DefaultDBPath("<name>") + `.
+Each profile runs its own daemon with its own tmux sessions, so two profiles
+can run at the same time without seeing each other's tasks.
+
+For a one-off database that isn't worth a profile, use the global --db flag
+(or ` + profile.DBEnv + `) with its path instead; it is isolated the same way.
+
+Profiles are stored in ` + profile.ConfigPath() + `.
+
+Examples:
+  ty profiles list
+  ty profiles add work
+  ty profiles add personal --path ~/Dropbox/tasks.db
+  ty --profile work list
+  ty profiles remove work`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listProfilesCLI(cmd)
+		},
+	}
+	profilesCmd.Flags().Bool("json", false, "Output in JSON format")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List profiles",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listProfilesCLI(cmd)
+		},
+	}
+	listCmd.Flags().Bool("json", false, "Output in JSON format")
+	profilesCmd.AddCommand(listCmd)
+
+	addCmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Add a profile",
+		Long: `Add a profile. Without --path the database is
+` + profile.DefaultDBPath("<name>") + `, and is created on first use.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
+			p, err := profile.Add(args[0], path)
+			if err != nil {
+				return err
+			}
+			outln(successStyle.Render(fmt.Sprintf("Added profile %s", p.Name)) + dimStyle.Render(" → "+p.Path))
+			return nil
+		},
+	}
+	addCmd.Flags().String("path", "", "Database file for the profile")
+	profilesCmd.AddCommand(addCmd)
+
+	removeCmd := &cobra.Command{
+		Use:     "remove <name>",
+		Aliases: []string{"rm"},
+		Short:   "Remove a profile (the database file is kept)",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := profile.Remove(args[0]); err != nil {
+				return err
+			}
+			outln(successStyle.Render(fmt.Sprintf("Removed profile %s", args[0])))
+			return nil
+		},
+	}
+	profilesCmd.AddCommand(removeCmd)
+
+	return profilesCmd
+}
+
+func listProfilesCLI(cmd *cobra.Command) error {
+	outputJSON, _ := cmd.Flags().GetBool("json")
+
+	profiles, err := profile.Load()
+	if err != nil {
+		return err
+	}
+	active := profile.Active()
+
+	if outputJSON {
+		var output []map[string]interface{}
+		for _, p := range profiles {
+			output = append(output, map[string]interface{}{
+				"name":   p.Name,
+				"path":   p.Path,
+				"active": p.Name == active,
+			})
+		}
+		jsonBytes, _ := json.MarshalIndent(output, "", "  ")
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	marker := func(isActive bool) string {
+		if isActive {
+			return successStyle.Render("*")
+		}
+		return " "
+	}
+	// db.DefaultPath reports the active profile's DB, so only show the default
+	// profile's path when it is the one in use.
+	defaultPath := ""
+	if active == "" {
+		defaultPath = db.DefaultPath()
+	}
+	fmt.Printf("%s %-16s %s\n", marker(active == ""), "default", dimStyle.Render(defaultPath))
+	for _, p := range profiles {
+		fmt.Printf("%s %-16s %s\n", marker(p.Name == active), p.Name, dimStyle.Render(p.Path))
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/spf13/cobra"
+)
+
+func newProjectsActionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "action",
+		Aliases: []string{"actions"},
+		Short:   "Manage a project's event-triggered actions",
+		Long: `Project actions run instructions when one of the project's tasks reaches a
+lifecycle event. A project has at most one action per trigger:
+
+  task.created    prepended to the task's prompt the first time it runs
+  task.started    appended to the task's log when it starts processing
+  task.blocked    appended to the task's log when it becomes blocked
+  task.completed  creates a backlog follow-up task (tagged "` + db.FollowUpTag + `") with the
+                  instructions as its body; completing a follow-up doesn't
+                  create another
+
+Actions fire on the status change itself, whether the daemon, the CLI, the
+TUI or a hook makes it.
+
+Examples:
+  ty projects action add myapp --trigger task.completed --instructions "Update the CHANGELOG"
+  ty projects action list myapp
+  ty projects action remove myapp task.completed`,
+	}
+
+	addCmd := &cobra.Command{
+		Use:               "add <project>",
+		Short:             "Add or replace a project action",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeProjectNames,
+		Run: func(cmd *cobra.Command, args []string) {
+			trigger, _ := cmd.Flags().GetString("trigger")
+			instructions, _ := cmd.Flags().GetString("instructions")
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			project, trigger, replaced, err := setProjectAction(database, args[0], trigger, instructions)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if replaced {
+				outln(successStyle.Render(fmt.Sprintf("Replaced %s action for %s", trigger, project)))
+			} else {
+				outln(successStyle.Render(fmt.Sprintf("Added %s action to %s", trigger, project)))
+			}
+		},
+	}
+	addCmd.Flags().StringP("trigger", "t", "", "Event that fires the action: "+strings.Join(db.ProjectActionTriggers(), ", "))
+	addCmd.Flags().StringP("instructions", "i", "", "Instructions the action runs with")
+	addCmd.MarkFlagRequired("trigger")
+	addCmd.MarkFlagRequired("instructions")
+	addCmd.RegisterFlagCompletionFunc("trigger", completeActionTriggers)
+	cmd.AddCommand(addCmd)
+
+	listCmd := &cobra.Command{
+		Use:               "list <project>",
+		Short:             "List a project's actions",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeProjectNames,
+		Run: func(cmd *cobra.Command, args []string) {
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			project, err := actionProject(database, args[0])
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			if outputJSON {
+				actions := project.Actions
+				if actions == nil {
+					actions = []db.ProjectAction{}
+				}
+				jsonBytes, _ := json.Marshal(actions)
+				fmt.Println(string(jsonBytes))
+				return
+			}
+			if len(project.Actions) == 0 {
+				fmt.Println(dimStyle.Render("No actions for " + project.Name))
+				return
+			}
+			for _, a := range project.Actions {
+				fmt.Printf("%s %s\n", boldStyle.Render(fmt.Sprintf("%-15s", a.Trigger)), a.Instructions)
+			}
+		},
+	}
+	listCmd.Flags().Bool("json", false, "Output in JSON format")
+	cmd.AddCommand(listCmd)
+
+	removeCmd := &cobra.Command{
+		Use:               "remove <project> <trigger>",
+		Aliases:           []string{"rm"},
+		Short:             "Remove a project action",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeProjectNames,
+		Run: func(cmd *cobra.Command, args []string) {
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			project, trigger, err := removeProjectAction(database, args[0], args[1])
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			outln(successStyle.Render(fmt.Sprintf("Removed %s action from %s", trigger, project)))
+		},
+	}
+	cmd.AddCommand(removeCmd)
+
+	return cmd
+}
+
+// actionProject resolves a project name or alias.
+func actionProject(database *db.DB, name string) (*db.Project, error) {
+	p, err := database.GetProjectByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, fmt.Errorf("project %q not found", name)
+	}
+	return p, nil
+}
+
+// setProjectAction validates trigger and stores the action on the project,
+// returning the project's name, the canonical trigger and whether an existing
+// action was replaced.
+func setProjectAction(database *db.DB, name, trigger, instructions string) (string, string, bool, error) {
+	trigger, err := db.NormalizeActionTrigger(trigger)
+	if err != nil {
+		return "", "", false, err
+	}
+	instructions = strings.TrimSpace(instructions)
+	if instructions == "" {
+		return "", "", false, fmt.Errorf("instructions are empty")
+	}
+	p, err := actionProject(database, name)
+	if err != nil {
+		return "", "", false, err
+	}
+	// A legacy on_create action is superseded by the task.created one.
+	legacy := trigger == db.ActionTaskCreated && p.RemoveAction(db.ActionOnCreate)
+	replaced := p.SetAction(trigger, instructions) || legacy
+	if err := database.UpdateProject(p); err != nil {
+		return "", "", false, err
+	}
+	return p.Name, trigger, replaced, nil
+}
+
+// removeProjectAction deletes the project's action for trigger.
+func removeProjectAction(database *db.DB, name, trigger string) (string, string, error) {
+	trigger, err := db.NormalizeActionTrigger(trigger)
+	if err != nil {
+		return "", "", err
+	}
+	p, err := actionProject(database, name)
+	if err != nil {
+		return "", "", err
+	}
+	removed := p.RemoveAction(trigger)
+	if trigger == db.ActionTaskCreated && p.RemoveAction(db.ActionOnCreate) {
+		removed = true
+	}
+	if !removed {
+		return "", "", fmt.Errorf("project %s has no %s action", p.Name, trigger)
+	}
+	if err := database.UpdateProject(p); err != nil {
+		return "", "", err
+	}
+	return p.Name, trigger, nil
+}
+
+// completeActionTriggers completes the --trigger flag of ty projects action add.
+func completeActionTriggers(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return db.ProjectActionTriggers(), cobra.ShellCompDirectiveNoFileComp
+}
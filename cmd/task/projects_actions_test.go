@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestProjectActionsCLI(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	if err := database.CreateProject(&db.Project{Name: "app", Path: t.TempDir(), Aliases: "a",
+		Actions: []db.ProjectAction{{Trigger: db.ActionOnCreate, Instructions: "Triage first"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, _, err := setProjectAction(database, "app", "on_status:queued", "x"); err == nil {
+		t.Error("accepted an unknown trigger")
+	}
+	if _, _, _, err := setProjectAction(database, "app", db.ActionTaskCompleted, "  "); err == nil {
+		t.Error("accepted empty instructions")
+	}
+
+	name, trigger, replaced, err := setProjectAction(database, "a", db.ActionTaskCompleted, "Update the CHANGELOG")
+	if err != nil || name != "app" || trigger != db.ActionTaskCompleted || replaced {
+		t.Fatalf("add = %q %q %v %v", name, trigger, replaced, err)
+	}
+	// Setting task.created supersedes the legacy on_create action.
+	if _, trigger, replaced, err = setProjectAction(database, "app", db.ActionOnCreate, "Ask questions"); err != nil || trigger != db.ActionTaskCreated || !replaced {
+		t.Fatalf("replace legacy = %q %v %v", trigger, replaced, err)
+	}
+
+	p, _ := database.GetProjectByName("app")
+	if len(p.Actions) != 2 || p.GetAction(db.ActionOnCreate) != nil || p.GetAction(db.ActionTaskCreated).Instructions != "Ask questions" {
+		t.Errorf("actions = %+v", p.Actions)
+	}
+
+	if _, _, err := removeProjectAction(database, "app", db.ActionTaskCompleted); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := removeProjectAction(database, "app", db.ActionTaskCompleted); err == nil {
+		t.Error("removed a missing action")
+	}
+	p, _ = database.GetProjectByName("app")
+	if len(p.Actions) != 1 {
+		t.Errorf("actions after remove = %+v", p.Actions)
+	}
+}
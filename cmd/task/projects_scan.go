@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/ui"
+)
+
+// scannedRepo is one git repository found by `ty projects scan`, and what
+// the scan did (or would do) with it.
+type scannedRepo struct {
+	Name          string `json:"name"`
+	Path          string `json:"path"`
+	DefaultBranch string `json:"default_branch,omitempty"`
+	Color         string `json:"color,omitempty"`
+	Created       bool   `json:"created"`
+	SkipReason    string `json:"skip_reason,omitempty"`
+}
+
+func newProjectsScanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scan <dir>",
+		Short: "Register the git repos in a directory as projects",
+		Long: `Find git repositories under a directory and register each one as a
+project, named after its directory and given a color from the project palette.
+The repo's default branch becomes the project's base branch. Repos that
+already have a project for their path are skipped, as are repos whose
+directory name is already taken by another project.
+
+Hidden directories are never scanned. Repos are not searched for nested repos.
+
+Examples:
+  ty projects scan ~/Projects
+  ty projects scan ~/Projects --dry-run
+  ty projects scan ~/code --depth 2 --exclude 'archive-*'`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			depth, _ := cmd.Flags().GetInt("depth")
+			excludes, _ := cmd.Flags().GetStringArray("exclude")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			if depth < 1 {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: --depth must be 1 or more"))
+				os.Exit(1)
+			}
+			for _, pattern := range excludes {
+				if _, err := filepath.Match(pattern, ""); err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Error: invalid --exclude pattern %q: %v", pattern, err)))
+					os.Exit(1)
+				}
+			}
+
+			root := args[0]
+			if strings.HasPrefix(root, "~") {
+				home, _ := os.UserHomeDir()
+				root = filepath.Join(home, root[1:])
+			}
+			root, err := filepath.Abs(root)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: invalid path: "+err.Error()))
+				os.Exit(1)
+			}
+
+			paths, err := findGitRepos(root, depth, excludes)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			existing, err := database.ListProjects()
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			repos := planProjectScan(paths, existing)
+			for _, r := range repos {
+				if r.SkipReason != "" {
+					continue
+				}
+				r.DefaultBranch = detectDefaultBranch(r.Path)
+				if dryRun {
+					continue
+				}
+				project := &db.Project{
+					Name:         r.Name,
+					Path:         r.Path,
+					Color:        r.Color,
+					BaseBranch:   r.DefaultBranch,
+					UseWorktrees: true,
+				}
+				if err := database.CreateProject(project); err != nil {
+					r.SkipReason = err.Error()
+					continue
+				}
+				r.Created = true
+			}
+
+			if outputJSON {
+				if repos == nil {
+					repos = []*scannedRepo{}
+				}
+				jsonBytes, _ := json.MarshalIndent(repos, "", "  ")
+				fmt.Println(string(jsonBytes))
+				return
+			}
+			printProjectScan(repos, dryRun)
+		},
+	}
+	cmd.Flags().Int("depth", 1, "How many directory levels below <dir> to search for repos")
+	cmd.Flags().StringArray("exclude", nil, "Skip repos whose directory name matches this glob (repeatable)")
+	cmd.Flags().Bool("dry-run", false, "Show what would be registered without creating projects")
+	cmd.Flags().Bool("json", false, "Output in JSON format")
+	return cmd
+}
+
+// findGitRepos returns the git repositories under root, searching at most
+// depth levels down. It doesn't descend into hidden directories, into repos
+// it has found, or into directories whose name matches an exclude glob.
+func findGitRepos(root string, depth int, excludes []string) ([]string, error) {
+	var repos []string
+	var walk func(dir string, level int) error
+	walk = func(dir string, level int) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if !e.IsDir() || strings.HasPrefix(e.Name(), ".") || matchesAnyGlob(e.Name(), excludes) {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+				repos = append(repos, path)
+				continue
+			}
+			if level < depth {
+				// Unreadable subdirectories are skipped rather than failing
+				// the whole scan.
+				_ = walk(path, level+1)
+			}
+		}
+		return nil
+	}
+	if err := walk(root, 1); err != nil {
+		return nil, fmt.Errorf("scan %s: %w", root, err)
+	}
+	sort.Strings(repos)
+	return repos, nil
+}
+
+func matchesAnyGlob(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// planProjectScan decides which repos become projects. A repo is skipped when
+// a project already has its path or its directory name; the rest are given
+// the least-used color from the project palette.
+func planProjectScan(paths []string, existing []*db.Project) []*scannedRepo {
+	byPath := make(map[string]string)
+	names := make(map[string]bool)
+	colorUse := make(map[string]int)
+	for _, p := range existing {
+		byPath[filepath.Clean(p.Path)] = p.Name
+		names[strings.ToLower(p.Name)] = true
+		colorUse[strings.ToUpper(p.Color)]++
+	}
+
+	var repos []*scannedRepo
+	for _, path := range paths {
+		r := &scannedRepo{Name: filepath.Base(path), Path: path}
+		repos = append(repos, r)
+		if name, ok := byPath[filepath.Clean(path)]; ok {
+			r.SkipReason = fmt.Sprintf("already registered as %s", name)
+			continue
+		}
+		if names[strings.ToLower(r.Name)] {
+			r.SkipReason = fmt.Sprintf("a project named %s already exists", r.Name)
+			continue
+		}
+		names[strings.ToLower(r.Name)] = true
+
+		r.Color = ui.DefaultProjectColors[0]
+		for _, c := range ui.DefaultProjectColors {
+			if colorUse[c] < colorUse[r.Color] {
+				r.Color = c
+			}
+		}
+		colorUse[r.Color]++
+	}
+	return repos
+}
+
+// detectDefaultBranch returns the branch origin/HEAD points at, falling back
+// to the repo's current branch. It returns "" when neither is known.
+func detectDefaultBranch(repo string) string {
+	cmd := osexec.Command("git", "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+	cmd.Dir = repo
+	if out, err := cmd.Output(); err == nil {
+		return strings.TrimPrefix(strings.TrimSpace(string(out)), "origin/")
+	}
+	cmd = osexec.Command("git", "symbolic-ref", "--short", "HEAD")
+	cmd.Dir = repo
+	if out, err := cmd.Output(); err == nil {
+		return strings.TrimSpace(string(out))
+	}
+	return ""
+}
+
+func printProjectScan(repos []*scannedRepo, dryRun bool) {
+	if len(repos) == 0 {
+		fmt.Println(dimStyle.Render("No git repositories found"))
+		return
+	}
+	var created, skipped int
+	for _, r := range repos {
+		if r.SkipReason != "" {
+			skipped++
+			fmt.Printf("  %s %s %s\n", dimStyle.Render("-"), r.Name, dimStyle.Render(r.SkipReason))
+			continue
+		}
+		created++
+		line := fmt.Sprintf("  %s %s %s", successStyle.Render("+"), r.Name, dimStyle.Render(r.Path))
+		if r.DefaultBranch != "" {
+			line += dimStyle.Render(" (" + r.DefaultBranch + ")")
+		}
+		fmt.Println(line)
+	}
+	fmt.Println()
+	verb := "Created"
+	if dryRun {
+		verb = "Would create"
+	}
+	noun := "projects"
+	if created == 1 {
+		noun = "project"
+	}
+	fmt.Printf("%s %d %s, skipped %d\n", verb, created, noun, skipped)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/ui"
+)
+
+func TestFindGitRepos(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{
+		"api/.git",
+		"web/.git",
+		"web/vendor/lib/.git", // nested in a repo: not scanned
+		"archive-old/.git",
+		".hidden/.git",
+		"group/tool/.git", // two levels down
+		"notes",
+	} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := findGitRepos(root, 1, []string{"archive-*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(root, "api"), filepath.Join(root, "web")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("depth 1: got %v, want %v", got, want)
+	}
+
+	got, err = findGitRepos(root, 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{
+		filepath.Join(root, "api"),
+		filepath.Join(root, "archive-old"),
+		filepath.Join(root, "group", "tool"),
+		filepath.Join(root, "web"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("depth 2: got %v, want %v", got, want)
+	}
+}
+
+func TestPlanProjectScan(t *testing.T) {
+	existing := []*db.Project{
+		{Name: "api", Path: "/src/api", Color: ui.DefaultProjectColors[0]},
+		{Name: "docs", Path: "/elsewhere/docs"},
+	}
+	repos := planProjectScan([]string{"/src/api", "/src/docs", "/src/web"}, existing)
+	if len(repos) != 3 {
+		t.Fatalf("expected 3 repos, got %d", len(repos))
+	}
+	if repos[0].SkipReason == "" {
+		t.Error("expected /src/api to be skipped as already registered")
+	}
+	if repos[1].SkipReason == "" {
+		t.Error("expected /src/docs to be skipped as its name is taken")
+	}
+	web := repos[2]
+	if web.SkipReason != "" || web.Name != "web" {
+		t.Errorf("expected web to be registered, got %+v", web)
+	}
+	if web.Color != ui.DefaultProjectColors[1] {
+		t.Errorf("expected the least-used palette color %s, got %s", ui.DefaultProjectColors[1], web.Color)
+	}
+}
+
+func TestDetectDefaultBranch(t *testing.T) {
+	repo := t.TempDir()
+	git(t, repo, "init", "-q", "-b", "trunk")
+	if got := detectDefaultBranch(repo); got != "trunk" {
+		t.Errorf("detectDefaultBranch() = %q, want trunk", got)
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+// Result levels for a project validation check.
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "fail"
+	checkSkip = "skip"
+)
+
+// projectCheck is the outcome of one `ty projects validate` check.
+type projectCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// projectValidation is the full report for one project.
+type projectValidation struct {
+	Project           string         `json:"project"`
+	Path              string         `json:"path"`
+	Checks            []projectCheck `json:"checks"`
+	OrphanedWorktrees []string       `json:"orphaned_worktrees"`
+}
+
+// OK reports whether no check failed. Warnings (e.g. orphaned worktrees) don't
+// stop the executor, so they don't fail validation.
+func (v *projectValidation) OK() bool {
+	for _, c := range v.Checks {
+		if c.Status == checkFail {
+			return false
+		}
+	}
+	return true
+}
+
+func newProjectsValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "validate <name>",
+		Short:             "Check a project's repo and worktree health",
+		ValidArgsFunction: completeProjectNames,
+		Long: `Check that a project is in a state the executor can work with before
+queueing tasks: the path exists, it is a git repository, HEAD is on a branch
+(not detached), and the .task-worktrees directory is writable. Also lists
+worktree directories under .task-worktrees that no task references.
+
+Exits non-zero if any check fails.
+
+Examples:
+  ty projects validate myapp
+  ty projects validate myapp --json`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			project, err := database.GetProjectByName(args[0])
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if project == nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Project '%s' not found", args[0])))
+				os.Exit(1)
+			}
+
+			// Every task that could own a worktree, including closed and trashed
+			// ones (a trashed task's worktree is kept until the retention sweep).
+			tasks, err := database.ListTasks(db.ListTasksOptions{
+				Project:        project.Name,
+				IncludeClosed:  true,
+				IncludeTrashed: true,
+				Limit:          100000,
+			})
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			report := validateProject(project, tasks)
+
+			if outputJSON {
+				jsonBytes, _ := json.MarshalIndent(report, "", "  ")
+				fmt.Println(string(jsonBytes))
+			} else {
+				printProjectValidation(report)
+			}
+			if !report.OK() {
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().Bool("json", false, "Output in JSON format")
+	return cmd
+}
+
+// validateProject runs every check against project. tasks are the project's
+// tasks, used to tell live worktrees from orphaned ones.
+func validateProject(project *db.Project, tasks []*db.Task) *projectValidation {
+	report := &projectValidation{
+		Project:           project.Name,
+		Path:              project.Path,
+		OrphanedWorktrees: []string{},
+	}
+	add := func(name, status, detail string) {
+		report.Checks = append(report.Checks, projectCheck{Name: name, Status: status, Detail: detail})
+	}
+
+	path := project.Path
+	if fi, err := os.Stat(path); err != nil {
+		add("path exists", checkFail, err.Error())
+		return report
+	} else if !fi.IsDir() {
+		add("path exists", checkFail, path+" is not a directory")
+		return report
+	}
+	add("path exists", checkOK, path)
+
+	if !project.UsesWorktrees() {
+		add("git repository", checkSkip, "project has git worktrees disabled")
+		return report
+	}
+
+	if out, err := runGit(path, "rev-parse", "--show-toplevel"); err != nil {
+		add("git repository", checkFail, strings.TrimSpace(string(out)))
+		return report
+	}
+	add("git repository", checkOK, "")
+
+	if out, err := runGit(path, "symbolic-ref", "--quiet", "--short", "HEAD"); err != nil {
+		add("HEAD on a branch", checkFail, "HEAD is detached; check out a branch before queueing work")
+	} else {
+		add("HEAD on a branch", checkOK, strings.TrimSpace(string(out)))
+	}
+
+	worktreesDir := filepath.Join(path, ".task-worktrees")
+	if err := checkDirWritable(worktreesDir); err != nil {
+		add(".task-worktrees writable", checkFail, err.Error())
+	} else {
+		add(".task-worktrees writable", checkOK, worktreesDir)
+	}
+
+	report.OrphanedWorktrees = findOrphanedWorktrees(worktreesDir, tasks)
+	if n := len(report.OrphanedWorktrees); n > 0 {
+		add("orphaned worktrees", checkWarn, fmt.Sprintf("%d worktree(s) not tied to any task", n))
+	} else {
+		add("orphaned worktrees", checkOK, "")
+	}
+
+	return report
+}
+
+// checkDirWritable verifies dir (or, if it doesn't exist yet, its parent,
+// where the executor will create it) accepts new files.
+func checkDirWritable(dir string) error {
+	target := dir
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		target = filepath.Dir(dir)
+	}
+	f, err := os.CreateTemp(target, ".ty-validate-*")
+	if err != nil {
+		return fmt.Errorf("not writable: %w", err)
+	}
+	name := f.Name()
+	f.Close()
+	os.Remove(name)
+	return nil
+}
+
+// findOrphanedWorktrees returns directories under worktreesDir that no task
+// references as its current or archived worktree.
+func findOrphanedWorktrees(worktreesDir string, tasks []*db.Task) []string {
+	entries, err := os.ReadDir(worktreesDir)
+	if err != nil {
+		return []string{}
+	}
+
+	owned := make(map[string]bool)
+	for _, t := range tasks {
+		for _, p := range []string{t.WorktreePath, t.ArchiveWorktreePath} {
+			if p != "" {
+				owned[filepath.Clean(p)] = true
+			}
+		}
+	}
+
+	orphans := []string{}
+	for _, e := range entries {
+		// "sessions" holds executor session files, not a worktree.
+		if !e.IsDir() || e.Name() == "sessions" {
+			continue
+		}
+		p := filepath.Join(worktreesDir, e.Name())
+		if !owned[filepath.Clean(p)] {
+			orphans = append(orphans, p)
+		}
+	}
+	sort.Strings(orphans)
+	return orphans
+}
+
+func printProjectValidation(report *projectValidation) {
+	fmt.Println(boldStyle.Render("Project: " + report.Project))
+	for _, c := range report.Checks {
+		var icon string
+		switch c.Status {
+		case checkOK:
+			icon = successStyle.Render("✓")
+		case checkWarn:
+			icon = warnStyle.Render("!")
+		case checkSkip:
+			icon = dimStyle.Render("-")
+		default:
+			icon = errorStyle.Render("✗")
+		}
+		line := fmt.Sprintf("  %s %s", icon, c.Name)
+		if c.Detail != "" {
+			line += dimStyle.Render("  " + c.Detail)
+		}
+		fmt.Println(line)
+	}
+	for _, p := range report.OrphanedWorktrees {
+		fmt.Println(dimStyle.Render("      " + p))
+	}
+	if !report.OK() {
+		fmt.Println()
+		fmt.Println(errorStyle.Render("Project validation failed"))
+	}
+}
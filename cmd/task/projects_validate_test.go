@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func checkStatus(report *projectValidation, name string) string {
+	for _, c := range report.Checks {
+		if c.Name == name {
+			return c.Status
+		}
+	}
+	return ""
+}
+
+func TestValidateProject(t *testing.T) {
+	repo := t.TempDir()
+	git(t, repo, "init", "-q", "-b", "main")
+	git(t, repo, "commit", "-q", "--allow-empty", "-m", "init")
+
+	live := filepath.Join(repo, ".task-worktrees", "1-live")
+	orphan := filepath.Join(repo, ".task-worktrees", "2-orphan")
+	for _, dir := range []string{live, orphan, filepath.Join(repo, ".task-worktrees", "sessions")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	project := &db.Project{Name: "app", Path: repo, UseWorktrees: true}
+	tasks := []*db.Task{{ID: 1, WorktreePath: live}}
+
+	report := validateProject(project, tasks)
+	if !report.OK() {
+		t.Fatalf("expected healthy project, got %+v", report.Checks)
+	}
+	for _, name := range []string{"path exists", "git repository", "HEAD on a branch", ".task-worktrees writable"} {
+		if got := checkStatus(report, name); got != checkOK {
+			t.Errorf("%s: expected ok, got %q", name, got)
+		}
+	}
+	if len(report.OrphanedWorktrees) != 1 || report.OrphanedWorktrees[0] != orphan {
+		t.Errorf("expected only %s orphaned, got %v", orphan, report.OrphanedWorktrees)
+	}
+	if got := checkStatus(report, "orphaned worktrees"); got != checkWarn {
+		t.Errorf("orphaned worktrees: expected warn, got %q", got)
+	}
+
+	git(t, repo, "checkout", "-q", "--detach", "HEAD")
+	report = validateProject(project, tasks)
+	if report.OK() || checkStatus(report, "HEAD on a branch") != checkFail {
+		t.Errorf("expected detached HEAD to fail, got %+v", report.Checks)
+	}
+}
+
+func TestValidateProjectNotARepo(t *testing.T) {
+	dir := t.TempDir()
+
+	report := validateProject(&db.Project{Name: "plain", Path: dir, UseWorktrees: true}, nil)
+	if report.OK() || checkStatus(report, "git repository") != checkFail {
+		t.Errorf("expected non-repo to fail, got %+v", report.Checks)
+	}
+
+	report = validateProject(&db.Project{Name: "plain", Path: dir, UseWorktrees: false}, nil)
+	if !report.OK() || checkStatus(report, "git repository") != checkSkip {
+		t.Errorf("expected git checks skipped for no-git project, got %+v", report.Checks)
+	}
+
+	report = validateProject(&db.Project{Name: "gone", Path: filepath.Join(dir, "missing"), UseWorktrees: true}, nil)
+	if report.OK() || checkStatus(report, "path exists") != checkFail {
+		t.Errorf("expected missing path to fail, got %+v", report.Checks)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// quiet is set by the global --quiet/-q flag. It silences success and
+// informational lines so scripts only see errors and the values they ask for
+// (such as a new task's ID).
+var quiet bool
+
+// outln prints a success or informational line to stdout unless --quiet is set.
+func outln(a ...any) {
+	if !quiet {
+		fmt.Println(a...)
+	}
+}
+
+// infoln prints an informational notice to stderr unless --quiet is set.
+func infoln(a ...any) {
+	if !quiet {
+		fmt.Fprintln(os.Stderr, a...)
+	}
+}
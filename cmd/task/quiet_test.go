@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestOutlnRespectsQuiet(t *testing.T) {
+	defer func() { quiet = false }()
+
+	if got := captureStdout(t, func() { outln("Created task #1") }); got != "Created task #1\n" {
+		t.Errorf("outln() printed %q, want the line", got)
+	}
+
+	quiet = true
+	if got := captureStdout(t, func() { outln("Created task #1") }); got != "" {
+		t.Errorf("outln() with --quiet printed %q, want nothing", got)
+	}
+}
+
+// Notices such as ty create's "Generated title" line must stay off stdout,
+// so `id=$(ty create -q ...)` captures only the ID.
+func TestInfolnNeverWritesStdout(t *testing.T) {
+	defer func() { quiet = false }()
+
+	for _, q := range []bool{false, true} {
+		quiet = q
+		if got := captureStdout(t, func() { infoln("Generated title: Fix login") }); got != "" {
+			t.Errorf("infoln() with quiet=%v printed %q to stdout, want nothing", q, got)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/spf13/cobra"
+)
+
+func newReorderCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "reorder <task-id> (--before <id> | --after <id>)",
+		Short:             "Move a task before or after another in its column",
+		ValidArgsFunction: completeTaskIDs,
+		Long: `Places a task directly before or after another task in the same column,
+overriding the usual newest-first order. The order is saved, so the board,
+'ty list' and the TUI show it from then on. Only the moved task is updated.
+
+Both tasks need the same status and must both be pinned or both unpinned.
+Done and archived tasks stay in completion order. Moving a task to another
+status drops its manual position.
+
+In the TUI, K and J move the selected task up and down.
+
+Examples:
+  ty reorder 42 --before 17
+  ty reorder 42 --after 17`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			before, _ := cmd.Flags().GetInt64("before")
+			after, _ := cmd.Flags().GetInt64("after")
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			taskID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid task ID: "+args[0]))
+				os.Exit(1)
+			}
+			if (before == 0) == (after == 0) {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: give exactly one of --before or --after"))
+				os.Exit(1)
+			}
+			anchorID, placeAfter := before, false
+			if after != 0 {
+				anchorID, placeAfter = after, true
+			}
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			if err := database.ReorderTask(taskID, anchorID, placeAfter); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			where := "before"
+			if placeAfter {
+				where = "after"
+			}
+			if outputJSON {
+				pos, _, _ := database.GetTaskPosition(taskID)
+				jsonBytes, _ := json.Marshal(map[string]interface{}{
+					"id":       taskID,
+					where:      anchorID,
+					"position": pos,
+				})
+				fmt.Println(string(jsonBytes))
+				return
+			}
+			outln(successStyle.Render(fmt.Sprintf("Moved task #%d %s #%d", taskID, where, anchorID)))
+		},
+	}
+	cmd.Flags().Int64("before", 0, "Place the task directly before this task")
+	cmd.Flags().Int64("after", 0, "Place the task directly after this task")
+	cmd.Flags().Bool("json", false, "Output in JSON format")
+	return cmd
+}
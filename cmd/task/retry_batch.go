@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+// retryConfirmThreshold is how many tasks 'ty retry --all-blocked' retries
+// without --yes; each retry starts an agent.
+const retryConfirmThreshold = 5
+
+// selectRetryTasks returns the blocked tasks 'ty retry --all-blocked' would
+// retry, narrowed to project and tag when set. Tasks blocked only because
+// their dependencies are unfinished are left out: retrying them would just
+// bounce them back to blocked.
+func selectRetryTasks(database *db.DB, project, tag string) ([]*db.Task, error) {
+	tasks, err := database.ListTasks(db.ListTasksOptions{Status: db.StatusBlocked, Project: project, Tag: tag, Limit: 100000})
+	if err != nil {
+		return nil, err
+	}
+	var selected []*db.Task
+	for _, t := range tasks {
+		open, err := database.GetOpenBlockerCount(t.ID)
+		if err != nil {
+			return nil, err
+		}
+		if open == 0 {
+			selected = append(selected, t)
+		}
+	}
+	return selected, nil
+}
+
+// runRetryBatch retries every blocked task matching project and tag with
+// the same feedback, printing a line per task and a summary.
+func runRetryBatch(project, tag, feedback string, yes bool) {
+	database, err := openTaskDB(db.DefaultPath())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+		os.Exit(1)
+	}
+	defer database.Close()
+
+	if project != "" {
+		p, err := database.GetProjectByName(project)
+		if err != nil || p == nil {
+			fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Error: project %q not found", project)))
+			os.Exit(1)
+		}
+		project = p.Name
+	}
+
+	tasks, err := selectRetryTasks(database, project, tag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+		os.Exit(1)
+	}
+	if len(tasks) == 0 {
+		fmt.Println(dimStyle.Render("No blocked tasks to retry"))
+		return
+	}
+	if len(tasks) > retryConfirmThreshold && !yes {
+		for _, t := range tasks {
+			fmt.Printf("  #%d [%s] %s\n", t.ID, t.Project, t.Title)
+		}
+		fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("\nRefusing to retry %d tasks at once without --yes (each starts an agent)", len(tasks))))
+		os.Exit(1)
+	}
+
+	var succeeded, failed int
+	for _, t := range tasks {
+		if err := database.RetryTask(t.ID, feedback); err != nil {
+			fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Error retrying task #%d: %v", t.ID, err)))
+			failed++
+			continue
+		}
+		outln(successStyle.Render(fmt.Sprintf("Retrying task #%d: %s", t.ID, t.Title)))
+		succeeded++
+	}
+	printBulkSummary("retry", succeeded, failed)
+	if succeeded > 0 {
+		ensureDaemonForQueuedWork()
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestSelectRetryTasks(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	for _, p := range []string{"app", "web"} {
+		if err := database.CreateProject(&db.Project{Name: p, Path: t.TempDir()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	create := func(title, status, project, tags string) *db.Task {
+		task := &db.Task{Title: title, Status: status, Project: project, Tags: tags}
+		if err := database.CreateTask(task); err != nil {
+			t.Fatal(err)
+		}
+		return task
+	}
+	failed := create("failed", db.StatusBlocked, "app", "infra")
+	other := create("other project", db.StatusBlocked, "web", "")
+	create("not blocked", db.StatusBacklog, "app", "infra")
+	blocker := create("blocker", db.StatusBacklog, "app", "")
+	waiting := create("waiting on blocker", db.StatusBlocked, "app", "infra")
+	if err := database.AddDependency(blocker.ID, waiting.ID, false); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := func(tasks []*db.Task) map[int64]bool {
+		m := make(map[int64]bool)
+		for _, task := range tasks {
+			m[task.ID] = true
+		}
+		return m
+	}
+
+	all, err := selectRetryTasks(database, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(all); len(got) != 2 || !got[failed.ID] || !got[other.ID] {
+		t.Errorf("all blocked: got %v, want #%d and #%d", got, failed.ID, other.ID)
+	}
+
+	inApp, _ := selectRetryTasks(database, "app", "")
+	if got := ids(inApp); len(got) != 1 || !got[failed.ID] {
+		t.Errorf("project app: got %v, want #%d", got, failed.ID)
+	}
+
+	tagged, _ := selectRetryTasks(database, "", "infra")
+	if got := ids(tagged); len(got) != 1 || !got[failed.ID] {
+		t.Errorf("tag infra: got %v, want #%d", got, failed.ID)
+	}
+}
+
+func TestSelectRetryTasksPastDefaultLimit(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	for i := 0; i < 120; i++ {
+		if err := database.CreateTask(&db.Task{Title: "failed", Status: db.StatusBlocked}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tasks, err := selectRetryTasks(database, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 120 {
+		t.Errorf("got %d tasks, want all 120", len(tasks))
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -92,7 +91,7 @@ func runRoutine(name string) {
 		fmt.Fprintln(os.Stderr, dimStyle.Render("Log: "+result.LogPath))
 		os.Exit(1)
 	}
-	fmt.Println(successStyle.Render(fmt.Sprintf("Run #%d ok (%s)", result.RunID, result.Duration.Round(time.Second))))
+	outln(successStyle.Render(fmt.Sprintf("Run #%d ok (%s)", result.RunID, result.Duration.Round(time.Second))))
 }
 
 func newRoutinesCmd() *cobra.Command {
@@ -138,7 +137,7 @@ logs, run history, and failure alerting (a pinned task + routine.failed hook).`,
 				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
 				os.Exit(1)
 			}
-			fmt.Println(successStyle.Render("Created routine " + rt.Name))
+			outln(successStyle.Render("Created routine " + rt.Name))
 			fmt.Printf("  %s %s\n", dimStyle.Render("Edit the prompt:"), rt.Dir+"/prompt.md")
 			fmt.Printf("  %s %s\n", dimStyle.Render("Optional secrets/checks:"), rt.Dir+"/env.sh (sourced before each run)")
 			fmt.Printf("  %s ty run %s\n", dimStyle.Render("Run it:"), rt.Name)
@@ -213,7 +212,7 @@ Examples:
 				os.Exit(1)
 			}
 			if removed {
-				fmt.Println(successStyle.Render(fmt.Sprintf("Unscheduled %q — the routine still exists; run it manually with: ty run %s", args[0], args[0])))
+				outln(successStyle.Render(fmt.Sprintf("Unscheduled %q — the routine still exists; run it manually with: ty run %s", args[0], args[0])))
 			} else {
 				fmt.Println(dimStyle.Render(fmt.Sprintf("No ty-managed schedule found for %q", args[0])))
 			}
@@ -364,9 +363,9 @@ func setRoutineDisabled(name string, disabled bool) {
 		os.Exit(1)
 	}
 	if disabled {
-		fmt.Println(successStyle.Render(fmt.Sprintf("Disabled %q — ty run %s is now a no-op", name, name)))
+		outln(successStyle.Render(fmt.Sprintf("Disabled %q — ty run %s is now a no-op", name, name)))
 	} else {
-		fmt.Println(successStyle.Render(fmt.Sprintf("Enabled %q", name)))
+		outln(successStyle.Render(fmt.Sprintf("Enabled %q", name)))
 	}
 }
 
@@ -376,17 +375,7 @@ func editRoutine(name string) {
 		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
 		os.Exit(1)
 	}
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = "vim"
-	}
-	// $EDITOR may be a command with flags (e.g. "code --wait"), so run via shell.
-	promptPath := filepath.Join(rt.Dir, "prompt.md")
-	editCmd := exec.Command("sh", "-c", editor+" "+shellQuote(promptPath))
-	editCmd.Stdin = os.Stdin
-	editCmd.Stdout = os.Stdout
-	editCmd.Stderr = os.Stderr
-	if err := editCmd.Run(); err != nil {
+	if err := runEditor(filepath.Join(rt.Dir, "prompt.md")); err != nil {
 		fmt.Fprintln(os.Stderr, errorStyle.Render("Editor failed: "+err.Error()))
 		os.Exit(1)
 	}
@@ -395,7 +384,7 @@ func editRoutine(name string) {
 		fmt.Fprintln(os.Stderr, warnStyle.Render("Warning: routine no longer loads: "+err.Error()))
 		os.Exit(1)
 	}
-	fmt.Println(successStyle.Render("Saved — routine loads cleanly"))
+	outln(successStyle.Render("Saved — routine loads cleanly"))
 }
 
 func shellQuote(s string) string {
@@ -424,7 +413,7 @@ func scheduleRoutine(name string, opts routine.ScheduleOptions, printOnly bool)
 		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
 		os.Exit(1)
 	}
-	fmt.Println(successStyle.Render(fmt.Sprintf("Scheduled %q — %s via %s", name, sched.Detail, sched.Backend)))
+	outln(successStyle.Render(fmt.Sprintf("Scheduled %q — %s via %s", name, sched.Detail, sched.Backend)))
 	fmt.Println(dimStyle.Render("  " + sched.Path))
 	fmt.Println(dimStyle.Render("  The OS owns the clock from here; ty records each run. Pause with: ty routines disable " + name))
 }
@@ -471,7 +460,7 @@ func deleteRoutine(name string, force bool) {
 		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
 		os.Exit(1)
 	}
-	fmt.Println(successStyle.Render(fmt.Sprintf("Deleted routine %q", rt.Name)))
+	outln(successStyle.Render(fmt.Sprintf("Deleted routine %q", rt.Name)))
 }
 
 func printRoutineLog(name string, runID int64) {
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bborn/workflow/internal/cron"
+	"github.com/bborn/workflow/internal/db"
+	"github.com/spf13/cobra"
+)
+
+func newScheduleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "schedule",
+		Aliases: []string{"schedules"},
+		Short:   "Manage recurring tasks",
+		Long: `Schedules create a task on a recurring cron spec. The daemon checks them
+every few seconds and creates (and by default queues) the task when one comes
+due. Each run is recorded, so restarting the daemon never fires the same slot
+twice; if the daemon was down through several slots, it fires once.
+
+Specs are five cron fields (minute hour day-of-month month day-of-week), or
+@hourly, @daily, @weekly, @monthly. Prefix CRON_TZ=<zone> to use a time zone
+other than the local one. Tasks created by a schedule show a ⏰ in 'ty list'.
+
+Routines ('ty routines') are different: they run outside the task board and
+are triggered by the OS scheduler.
+
+Examples:
+  ty schedule create deps --cron "0 9 * * mon" --title "Review dependabot PRs" -p myapp
+  ty schedule create standup --cron "CRON_TZ=Europe/Berlin 30 8 * * 1-5" --title "Draft standup notes" --backlog
+  ty schedule list
+  ty schedule delete deps`,
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a schedule",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			spec, _ := cmd.Flags().GetString("cron")
+			title, _ := cmd.Flags().GetString("title")
+			body, _ := cmd.Flags().GetString("body")
+			project, _ := cmd.Flags().GetString("project")
+			taskType, _ := cmd.Flags().GetString("type")
+			executorName, _ := cmd.Flags().GetString("executor")
+			tags, _ := cmd.Flags().GetString("tags")
+			backlog, _ := cmd.Flags().GetBool("backlog")
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			s := &db.Schedule{
+				Name: args[0], Spec: spec, Title: title, Body: body, Project: project,
+				Type: taskType, Executor: executorName, Tags: tags, Queue: !backlog,
+			}
+			next, err := createSchedule(database, s, time.Now())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			if outputJSON {
+				jsonBytes, _ := json.Marshal(scheduleJSON(s, next))
+				fmt.Println(string(jsonBytes))
+				return
+			}
+			outln(successStyle.Render(fmt.Sprintf("Created schedule %q", s.Name)))
+			fmt.Println(dimStyle.Render("Next run: " + formatScheduleTime(next)))
+		},
+	}
+	createCmd.Flags().String("cron", "", "Cron spec, e.g. \"0 9 * * mon\" or \"CRON_TZ=UTC @daily\" (required)")
+	createCmd.Flags().String("title", "", "Title of the created tasks (required)")
+	createCmd.Flags().String("body", "", "Body of the created tasks")
+	createCmd.Flags().StringP("project", "p", "", "Project of the created tasks")
+	createCmd.Flags().StringP("type", "t", "", "Task type: code, writing, thinking")
+	createCmd.Flags().StringP("executor", "e", "", "Task executor (default: the project's default executor)")
+	createCmd.Flags().String("tags", "", "Task tags (comma-separated)")
+	createCmd.Flags().Bool("backlog", false, "Leave created tasks in the backlog instead of queueing them")
+	createCmd.Flags().Bool("json", false, "Output in JSON format")
+	createCmd.MarkFlagRequired("cron")
+	createCmd.MarkFlagRequired("title")
+	createCmd.RegisterFlagCompletionFunc("project", completeProjectNames)
+	createCmd.RegisterFlagCompletionFunc("executor", completeFlagExecutors)
+	cmd.AddCommand(createCmd)
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List schedules with their last and next run",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			schedules, err := database.ListSchedules()
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			now := time.Now()
+			if outputJSON {
+				output := make([]map[string]interface{}, 0, len(schedules))
+				for _, s := range schedules {
+					output = append(output, scheduleJSON(s, nextScheduleRun(s, now)))
+				}
+				jsonBytes, _ := json.Marshal(output)
+				fmt.Println(string(jsonBytes))
+				return
+			}
+			if len(schedules) == 0 {
+				fmt.Println(dimStyle.Render("No schedules"))
+				return
+			}
+			for _, s := range schedules {
+				project := ""
+				if s.Project != "" {
+					project = dimStyle.Render(fmt.Sprintf("[%s] ", s.Project))
+				}
+				fmt.Printf("%s %s %s%s\n", boldStyle.Render(s.Name), dimStyle.Render(s.Spec), project, s.Title)
+				last := "never"
+				if s.LastRunAt != nil {
+					last = formatScheduleTime(s.LastRunAt.Time)
+				}
+				mode := "queued"
+				if !s.Queue {
+					mode = "backlog"
+				}
+				fmt.Println(dimStyle.Render(fmt.Sprintf("  next %s · last %s · %s", formatScheduleTime(nextScheduleRun(s, now)), last, mode)))
+			}
+		},
+	}
+	listCmd.Flags().Bool("json", false, "Output in JSON format")
+	cmd.AddCommand(listCmd)
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete <name|id>",
+		Short: "Delete a schedule (tasks it created are kept)",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			s, err := database.GetSchedule(args[0])
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if s == nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Schedule %q not found", args[0])))
+				os.Exit(1)
+			}
+			if err := database.DeleteSchedule(s.ID); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			outln(successStyle.Render(fmt.Sprintf("Deleted schedule %q", s.Name)))
+		},
+	}
+	cmd.AddCommand(deleteCmd)
+
+	return cmd
+}
+
+// createSchedule validates s and stores it, returning when it will first run.
+func createSchedule(database *db.DB, s *db.Schedule, now time.Time) (time.Time, error) {
+	spec, err := cron.Parse(s.Spec)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --cron: %w", err)
+	}
+	next := spec.Next(now)
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("--cron %q never fires", s.Spec)
+	}
+	if s.Project != "" {
+		p, err := database.GetProjectByName(s.Project)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if p == nil {
+			return time.Time{}, fmt.Errorf("project %q not found", s.Project)
+		}
+		s.Project = p.Name
+	}
+	if err := database.CreateSchedule(s); err != nil {
+		return time.Time{}, err
+	}
+	return next, nil
+}
+
+// nextScheduleRun returns when s will next fire after now, or the zero time
+// if its spec is invalid or never fires.
+func nextScheduleRun(s *db.Schedule, now time.Time) time.Time {
+	spec, err := cron.Parse(s.Spec)
+	if err != nil {
+		return time.Time{}
+	}
+	return spec.Next(now)
+}
+
+func formatScheduleTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Local().Format("Mon Jan 2 15:04")
+}
+
+func scheduleJSON(s *db.Schedule, next time.Time) map[string]interface{} {
+	item := map[string]interface{}{
+		"id":      s.ID,
+		"name":    s.Name,
+		"spec":    s.Spec,
+		"title":   s.Title,
+		"project": s.Project,
+		"queue":   s.Queue,
+	}
+	if s.Body != "" {
+		item["body"] = s.Body
+	}
+	if s.Type != "" {
+		item["type"] = s.Type
+	}
+	if s.Executor != "" {
+		item["executor"] = s.Executor
+	}
+	if s.Tags != "" {
+		item["tags"] = s.Tags
+	}
+	if s.LastRunAt != nil {
+		item["last_run_at"] = s.LastRunAt.Time.Format(time.RFC3339)
+	}
+	if !next.IsZero() {
+		item["next_run_at"] = next.Format(time.RFC3339)
+	}
+	return item
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestCreateSchedule(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	if err := database.CreateProject(&db.Project{Name: "app", Path: t.TempDir(), Aliases: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC) // a Friday
+
+	s := &db.Schedule{Name: "deps", Spec: "CRON_TZ=UTC 0 9 * * mon", Title: "Review dependabot PRs", Project: "a", Queue: true}
+	next, err := createSchedule(database, s, now)
+	if err != nil {
+		t.Fatalf("createSchedule: %v", err)
+	}
+	if want := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("next = %v, want %v", next, want)
+	}
+	if s.Project != "app" {
+		t.Errorf("alias not resolved: project = %q", s.Project)
+	}
+	if got := nextScheduleRun(s, now); !got.Equal(next) {
+		t.Errorf("nextScheduleRun = %v, want %v", got, next)
+	}
+
+	for _, bad := range []*db.Schedule{
+		{Name: "bad-spec", Spec: "every monday", Title: "x"},
+		{Name: "never", Spec: "0 0 30 2 *", Title: "x"},
+		{Name: "ghost", Spec: "@daily", Title: "x", Project: "ghost"},
+	} {
+		if _, err := createSchedule(database, bad, now); err == nil {
+			t.Errorf("createSchedule(%s) succeeded", bad.Name)
+		}
+	}
+	if list, _ := database.ListSchedules(); len(list) != 1 {
+		t.Errorf("invalid schedules were stored: %+v", list)
+	}
+}
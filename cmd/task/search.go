@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var searchMatchStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#F59E0B"))
+
+// searchResultJSON is one `ty search --json` result.
+type searchResultJSON struct {
+	ID      int64  `json:"id"`
+	Title   string `json:"title"`
+	Status  string `json:"status"`
+	Project string `json:"project"`
+	Snippet string `json:"snippet"`
+}
+
+func newSearchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Full-text search task titles, bodies, and summaries",
+		Long: `Searches the title, body, and summary of every task (done and archived
+included) for all the words in <query>, best matches first. Words match as
+prefixes, so "auth" also finds "authentication".
+
+Examples:
+  ty search oauth callback
+  ty search flaky test --project myapp --status done
+  ty search migration --limit 5 --json`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			project, _ := cmd.Flags().GetString("project")
+			status, _ := cmd.Flags().GetString("status")
+			limit, _ := cmd.Flags().GetInt("limit")
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			results, err := database.SearchTaskText(strings.Join(args, " "), db.TaskSearchOptions{
+				Project: project,
+				Status:  status,
+				Limit:   limit,
+			})
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			if outputJSON {
+				out := make([]searchResultJSON, 0, len(results))
+				for _, r := range results {
+					out = append(out, searchResultJSON{
+						ID:      r.ID,
+						Title:   r.Title,
+						Status:  r.Status,
+						Project: r.Project,
+						Snippet: stripSearchMarkers(r.Snippet),
+					})
+				}
+				data, _ := json.MarshalIndent(out, "", "  ")
+				fmt.Println(string(data))
+				return
+			}
+
+			if len(results) == 0 {
+				fmt.Println(dimStyle.Render("No matching tasks"))
+				return
+			}
+			for _, r := range results {
+				line := fmt.Sprintf("%s %s %s", dimStyle.Render(fmt.Sprintf("#%d", r.ID)), r.Title, dimStyle.Render("["+r.Status+"]"))
+				if r.Project != "" {
+					line += " " + dimStyle.Render(r.Project)
+				}
+				fmt.Println(line)
+				if r.Snippet != "" {
+					fmt.Println("    " + highlightSearchSnippet(r.Snippet))
+				}
+			}
+		},
+	}
+	cmd.Flags().StringP("project", "p", "", "Only search tasks in this project")
+	cmd.Flags().StringP("status", "s", "", "Only search tasks with this status")
+	cmd.Flags().IntP("limit", "n", 20, "Maximum number of results")
+	cmd.Flags().Bool("json", false, "Output in JSON format")
+	cmd.RegisterFlagCompletionFunc("project", completeFlagProjects)
+	cmd.RegisterFlagCompletionFunc("status", completeFlagStatuses)
+	return cmd
+}
+
+// highlightSearchSnippet renders a search snippet on one line with its matches
+// styled.
+func highlightSearchSnippet(snippet string) string {
+	snippet = strings.Join(strings.Fields(snippet), " ")
+	var b strings.Builder
+	for {
+		start := strings.Index(snippet, db.SearchMatchStart)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(snippet[start:], db.SearchMatchEnd)
+		if end < 0 {
+			break
+		}
+		end += start
+		b.WriteString(snippet[:start])
+		b.WriteString(searchMatchStyle.Render(snippet[start+len(db.SearchMatchStart) : end]))
+		snippet = snippet[end+len(db.SearchMatchEnd):]
+	}
+	b.WriteString(snippet)
+	return stripSearchMarkers(b.String())
+}
+
+// stripSearchMarkers removes snippet match markers and collapses whitespace.
+func stripSearchMarkers(snippet string) string {
+	snippet = strings.NewReplacer(db.SearchMatchStart, "", db.SearchMatchEnd, "").Replace(snippet)
+	return strings.Join(strings.Fields(snippet), " ")
+}
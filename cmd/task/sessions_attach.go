@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"strings"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/profile"
+	"github.com/spf13/cobra"
+)
+
+func newSessionsAttachCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "attach <task-id>",
+		Short:             "Jump into a task's agent window",
+		ValidArgsFunction: completeTaskIDs,
+		Long: `Switches to the tmux window running a task's agent. Inside tmux the
+current client switches to it; outside tmux this attaches to the daemon
+session with that window selected (detach with the tmux prefix then d).
+
+Examples:
+  ty sessions attach 42`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			task := sessionTaskArg(args[0])
+			target, pane := taskWindowTarget(task)
+			if target == "" {
+				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Task #%d has no running session", task.ID)))
+				fmt.Fprintln(os.Stderr, dimStyle.Render(noSessionHint(task)))
+				os.Exit(1)
+			}
+			if err := attachTaskWindow(target, pane); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+func newSessionsKillCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "kill <task-id>",
+		Short:             "Kill a task's agent window",
+		ValidArgsFunction: completeTaskIDs,
+		Long: `Kills the tmux window running a task's agent, ending the agent process,
+and clears the task's stale window references. The task's status is left to
+the daemon, which marks it blocked once it notices the window is gone; the
+agent's session ID is kept, so 'ty retry' resumes it.
+
+Examples:
+  ty sessions kill 42`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			task := sessionTaskArg(args[0])
+			id := int(task.ID)
+			killed := killSession(id) == nil || killSessionAcrossDaemons(id)
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+			database.ClearTaskTmuxIDs(task.ID)
+			database.Exec(`UPDATE tasks SET daemon_session = '' WHERE id = ?`, task.ID)
+
+			if !killed {
+				fmt.Println(dimStyle.Render(fmt.Sprintf("Task #%d has no running session", task.ID)))
+				return
+			}
+			outln(successStyle.Render(fmt.Sprintf("Killed session for task #%d: %s", task.ID, task.Title)))
+			fmt.Println(dimStyle.Render(fmt.Sprintf("Use 'ty retry %d' to resume it", task.ID)))
+		},
+	}
+}
+
+// sessionTaskArg parses a task ID argument and loads the task, exiting with
+// an error if either fails.
+func sessionTaskArg(arg string) *db.Task {
+	var taskID int64
+	if _, err := fmt.Sscanf(arg, "%d", &taskID); err != nil {
+		fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid task ID: "+arg))
+		os.Exit(1)
+	}
+	database, err := openTaskDB(db.DefaultPath())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+		os.Exit(1)
+	}
+	defer database.Close()
+	task, err := database.GetTask(taskID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+		os.Exit(1)
+	}
+	if task == nil {
+		fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Task #%d not found", taskID)))
+		os.Exit(1)
+	}
+	return task
+}
+
+// taskWindowTarget returns the tmux window ("session:@id") running task's
+// agent, and its pane if known. The recorded executor pane is tried first,
+// since the TUI may have moved it out of the daemon session; then the task's
+// recorded daemon session and every other daemon session of this profile
+// are searched for its task-<id> window. It returns "" if none is live.
+func taskWindowTarget(task *db.Task) (target, pane string) {
+	if task.ClaudePaneID != "" {
+		if w := tmuxWindowOf(task.ClaudePaneID); w != "" {
+			return w, task.ClaudePaneID
+		}
+	}
+
+	windowName := fmt.Sprintf("task-%d", task.ID)
+	var sessions []string
+	if task.DaemonSession != "" {
+		sessions = append(sessions, task.DaemonSession)
+	}
+	if out, err := osexec.Command("tmux", "list-sessions", "-F", "#{session_name}").Output(); err == nil {
+		for _, s := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			if strings.HasPrefix(s, profile.DaemonSessionPrefix()) && s != task.DaemonSession {
+				sessions = append(sessions, s)
+			}
+		}
+	}
+	for _, s := range sessions {
+		if w := tmuxWindowOf(s + ":" + windowName); w != "" {
+			return w, ""
+		}
+	}
+	return "", ""
+}
+
+// tmuxWindowOf returns the "session:@id" window holding tmux target, or "" if
+// the target doesn't exist. display-message alone would fall back to the
+// current pane for a missing target, so existence is checked first.
+func tmuxWindowOf(target string) string {
+	if osexec.Command("tmux", "list-panes", "-t", target).Run() != nil {
+		return ""
+	}
+	out, err := osexec.Command("tmux", "display-message", "-p", "-t", target, "#{session_name}:#{window_id}").Output()
+	if err != nil {
+		return ""
+	}
+	w := strings.TrimSpace(string(out))
+	if strings.HasPrefix(w, ":") || strings.HasSuffix(w, ":") {
+		return ""
+	}
+	return w
+}
+
+// noSessionHint explains why a task has no live window and what to do.
+func noSessionHint(task *db.Task) string {
+	if task.DaemonSession != "" || task.ClaudePaneID != "" || task.TmuxWindowID != "" {
+		return "Its recorded tmux window is gone; run 'ty recover' to clear the stale references."
+	}
+	switch task.Status {
+	case db.StatusQueued, db.StatusProcessing:
+		return "It may still be starting; check 'ty sessions' in a moment."
+	case db.StatusBlocked:
+		return fmt.Sprintf("Use 'ty retry %d' to resume it.", task.ID)
+	}
+	return fmt.Sprintf("Use 'ty execute %d' to start it.", task.ID)
+}
+
+// attachTaskWindow switches the current tmux client to target, or attaches
+// the terminal to it when not inside tmux. The window and pane are selected
+// first, so the session opens on them.
+func attachTaskWindow(target, pane string) error {
+	session := target
+	if i := strings.LastIndex(target, ":"); i > 0 {
+		session = target[:i]
+	}
+	if err := osexec.Command("tmux", "select-window", "-t", target).Run(); err != nil {
+		return fmt.Errorf("select %s: %w", target, err)
+	}
+	if pane != "" {
+		osexec.Command("tmux", "select-pane", "-t", pane).Run()
+	}
+	if os.Getenv("TMUX") != "" {
+		if err := osexec.Command("tmux", "switch-client", "-t", session).Run(); err != nil {
+			return fmt.Errorf("switch to %s: %w", session, err)
+		}
+		return nil
+	}
+	cmd := osexec.Command("tmux", "attach-session", "-t", session)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
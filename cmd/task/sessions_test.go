@@ -5,6 +5,7 @@ import (
 	"os"
 	osexec "os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -331,3 +332,59 @@ func TestCleanupOrphanedSessions_KeepsWindowForRecentDoneTask(t *testing.T) {
 		t.Error("recently-done task window was killed; should stay for review window")
 	}
 }
+
+func TestTaskWindowTarget(t *testing.T) {
+	requireTmux(t)
+
+	const taskID = 991010
+	cleanup := makeDaemonSession(t, "test-attach-991010", taskID)
+	defer cleanup()
+
+	// No recorded session or pane: found by searching the daemon sessions.
+	target, pane := taskWindowTarget(&db.Task{ID: taskID})
+	if !strings.HasPrefix(target, "task-daemon-test-attach-991010:@") || pane != "" {
+		t.Errorf("got target %q pane %q", target, pane)
+	}
+
+	// A recorded pane that is still alive wins.
+	out, err := osexec.Command("tmux", "display-message", "-p", "-t", "task-daemon-test-attach-991010:task-991010", "#{pane_id}").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	livePane := strings.TrimSpace(string(out))
+	if got, p := taskWindowTarget(&db.Task{ID: taskID, ClaudePaneID: livePane}); got != target || p != livePane {
+		t.Errorf("with pane: got %q %q, want %q %q", got, p, target, livePane)
+	}
+
+	// Nothing live: stale references get the 'ty recover' hint.
+	gone := &db.Task{ID: 991011, DaemonSession: "task-daemon-gone", ClaudePaneID: "%999999"}
+	if got, _ := taskWindowTarget(gone); got != "" {
+		t.Errorf("expected no window, got %q", got)
+	}
+	if hint := noSessionHint(gone); !strings.Contains(hint, "ty recover") {
+		t.Errorf("hint = %q", hint)
+	}
+}
+
+func TestSessionsReport(t *testing.T) {
+	active := time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC)
+	report := sessionsReport([]agentSession{
+		{taskID: 7, taskTitle: "Fix login", memoryMB: 300, daemonSession: "task-daemon-1", lastActivity: active},
+		{taskID: 9, taskTitle: "Docs", executor: "codex", model: "gpt-5", memoryMB: 120, daemonSession: "task-daemon-1"},
+	})
+
+	if report.Count != 2 || report.TotalMemoryMB != 420 {
+		t.Errorf("count/total = %d/%d, want 2/420", report.Count, report.TotalMemoryMB)
+	}
+	first := report.Sessions[0]
+	if first.Executor != "claude" || first.LastActivity != active.Format(time.RFC3339) || first.DaemonSession != "task-daemon-1" {
+		t.Errorf("unexpected first session: %+v", first)
+	}
+	if second := report.Sessions[1]; second.Executor != "codex" || second.Model != "gpt-5" || second.LastActivity != "" {
+		t.Errorf("unexpected second session: %+v", second)
+	}
+
+	if empty := sessionsReport(nil); empty.Sessions == nil || empty.Count != 0 {
+		t.Errorf("expected an empty, non-nil session list, got %+v", empty)
+	}
+}
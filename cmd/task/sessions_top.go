@@ -0,0 +1,294 @@
+package main
+
+import (
+	"fmt"
+	osexec "os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+func newSessionsTopCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "top",
+		Short: "Live view of agent memory, CPU, and runtime",
+		Long: `Shows running agent sessions in a live table that refreshes like top, so a
+runaway agent stands out. CPU is sampled between refreshes; runtime is how long
+the task's oldest agent process has been running.
+
+Press q or Ctrl+C to exit.
+
+Examples:
+  ty sessions top
+  ty sessions top --interval 5s`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			interval, _ := cmd.Flags().GetDuration("interval")
+			if interval < 100*time.Millisecond {
+				return fmt.Errorf("--interval must be at least 100ms")
+			}
+			p := tea.NewProgram(sessionsTopModel{interval: interval, prevCPU: map[int]time.Duration{}}, tea.WithAltScreen())
+			_, err := p.Run()
+			return err
+		},
+	}
+	cmd.Flags().Duration("interval", time.Second, "Refresh interval")
+	return cmd
+}
+
+// processSample is one ps reading for an agent process.
+type processSample struct {
+	rssKB   int
+	cpuTime time.Duration // cumulative CPU time
+	elapsed time.Duration // time since the process started
+}
+
+// sampleProcesses reads memory, CPU time, and elapsed time for pids in one ps
+// call. Processes that exited in the meantime are simply missing.
+func sampleProcesses(pids []int) map[int]processSample {
+	if len(pids) == 0 {
+		return nil
+	}
+	ids := make([]string, len(pids))
+	for i, pid := range pids {
+		ids[i] = strconv.Itoa(pid)
+	}
+	// ps exits non-zero if any pid is gone but still prints the others.
+	out, _ := osexec.Command("ps", "-o", "pid=,rss=,time=,etime=", "-p", strings.Join(ids, ",")).Output()
+	return parseProcessSamples(string(out))
+}
+
+func parseProcessSamples(out string) map[int]processSample {
+	samples := make(map[int]processSample)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		rss, _ := strconv.Atoi(fields[1])
+		cpuTime, _ := parsePSDuration(fields[2])
+		elapsed, _ := parsePSDuration(fields[3])
+		samples[pid] = processSample{rssKB: rss, cpuTime: cpuTime, elapsed: elapsed}
+	}
+	return samples
+}
+
+// parsePSDuration parses ps time/etime values: [[dd-]hh:]mm:ss[.cc]. macOS
+// prints fractional seconds for time, Linux doesn't.
+func parsePSDuration(s string) (time.Duration, error) {
+	var days int
+	if d, rest, ok := strings.Cut(s, "-"); ok {
+		n, err := strconv.Atoi(d)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		days, s = n, rest
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	secs, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	total := time.Duration(secs * float64(time.Second))
+	unit := time.Minute
+	for i := len(parts) - 2; i >= 0; i-- {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		total += time.Duration(n) * unit
+		unit *= 60
+	}
+	return total + time.Duration(days)*24*time.Hour, nil
+}
+
+// topRow is one task's line in the top table.
+type topRow struct {
+	session  agentSession
+	procs    int
+	memoryMB int
+	cpu      float64 // percent of one core since the previous refresh
+	runtime  time.Duration
+}
+
+type sessionsTopSnapshot struct {
+	rows    []topRow
+	cpuTime map[int]time.Duration // per-pid CPU time, for the next delta
+	at      time.Time
+}
+
+type sessionsTopTickMsg time.Time
+
+// sessionsTopModel is a Bubble Tea model for `ty sessions top`.
+type sessionsTopModel struct {
+	interval time.Duration
+	rows     []topRow
+	prevCPU  map[int]time.Duration
+	prevAt   time.Time
+	loaded   bool
+	width    int
+}
+
+func (m sessionsTopModel) Init() tea.Cmd {
+	return m.refresh()
+}
+
+// refresh collects a snapshot off the UI goroutine; tmux, pgrep, and lsof
+// calls can take a moment with many agents.
+func (m sessionsTopModel) refresh() tea.Cmd {
+	prevCPU, prevAt := m.prevCPU, m.prevAt
+	return func() tea.Msg {
+		return collectSessionsTop(prevCPU, prevAt)
+	}
+}
+
+func collectSessionsTop(prevCPU map[int]time.Duration, prevAt time.Time) sessionsTopSnapshot {
+	now := time.Now()
+	pidsByTask := getAgentPIDsByTaskID()
+	var allPIDs []int
+	for _, pids := range pidsByTask {
+		allPIDs = append(allPIDs, pids...)
+	}
+	samples := sampleProcesses(allPIDs)
+
+	snap := sessionsTopSnapshot{cpuTime: make(map[int]time.Duration), at: now}
+	wall := now.Sub(prevAt)
+	for _, s := range listAgentSessions(nil) {
+		row := topRow{session: s}
+		for _, pid := range pidsByTask[s.taskID] {
+			sample, ok := samples[pid]
+			if !ok {
+				continue
+			}
+			row.procs++
+			row.memoryMB += sample.rssKB / 1024
+			row.runtime = max(row.runtime, sample.elapsed)
+			snap.cpuTime[pid] = sample.cpuTime
+			// A pid seen for the first time has no previous sample to diff.
+			if prev, ok := prevCPU[pid]; ok && !prevAt.IsZero() && wall > 0 && sample.cpuTime >= prev {
+				row.cpu += float64(sample.cpuTime-prev) / float64(wall) * 100
+			}
+		}
+		snap.rows = append(snap.rows, row)
+	}
+	sortTopRows(snap.rows)
+	return snap
+}
+
+// sortTopRows puts the busiest agents first: by CPU, then memory, then task ID.
+func sortTopRows(rows []topRow) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].cpu != rows[j].cpu {
+			return rows[i].cpu > rows[j].cpu
+		}
+		if rows[i].memoryMB != rows[j].memoryMB {
+			return rows[i].memoryMB > rows[j].memoryMB
+		}
+		return rows[i].session.taskID < rows[j].session.taskID
+	})
+}
+
+func (m sessionsTopModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return m, tea.Quit
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+	case sessionsTopSnapshot:
+		m.rows, m.prevCPU, m.prevAt, m.loaded = msg.rows, msg.cpuTime, msg.at, true
+		return m, tea.Tick(m.interval, func(t time.Time) tea.Msg {
+			return sessionsTopTickMsg(t)
+		})
+	case sessionsTopTickMsg:
+		return m, m.refresh()
+	}
+	return m, nil
+}
+
+func (m sessionsTopModel) View() string {
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#E5E7EB"))
+	hotStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#EF4444"))
+
+	var totalMem int
+	var totalCPU float64
+	for _, r := range m.rows {
+		totalMem += r.memoryMB
+		totalCPU += r.cpu
+	}
+	b.WriteString(boldStyle.Render("Agent sessions"))
+	b.WriteString(dimStyle.Render(fmt.Sprintf("  %d running · %s · %.0f%% CPU · refresh %s · q to quit",
+		len(m.rows), formatMemoryMB(totalMem), totalCPU, m.interval)))
+	b.WriteString("\n\n")
+
+	if !m.loaded {
+		b.WriteString(dimStyle.Render("Sampling..."))
+		return b.String()
+	}
+	if len(m.rows) == 0 {
+		b.WriteString(dimStyle.Render("No agent sessions running"))
+		return b.String()
+	}
+
+	b.WriteString(headerStyle.Render(fmt.Sprintf("%-7s %7s %9s %10s  %-18s %s", "TASK", "CPU%", "MEM", "RUNTIME", "EXECUTOR", "TITLE")))
+	b.WriteString("\n")
+
+	titleWidth := 40
+	if m.width > 0 {
+		titleWidth = max(m.width-58, 10)
+	}
+	for _, r := range m.rows {
+		runtime := "-"
+		if r.procs > 0 {
+			runtime = formatRuntime(r.runtime)
+		}
+		cpu := fmt.Sprintf("%7.1f", r.cpu)
+		if r.cpu >= 80 {
+			cpu = hotStyle.Render(cpu)
+		}
+		title := r.session.taskTitle
+		if len(title) > titleWidth {
+			title = title[:titleWidth-3] + "..."
+		}
+		fmt.Fprintf(&b, "%-7s %s %9s %10s  %-18s %s\n",
+			fmt.Sprintf("#%d", r.session.taskID), cpu, formatMemoryMB(r.memoryMB), runtime,
+			executorLabel(r.session.executor, r.session.model, r.session.effort), title)
+	}
+	return b.String()
+}
+
+func formatMemoryMB(mb int) string {
+	if mb >= 1024 {
+		return fmt.Sprintf("%.1fG", float64(mb)/1024)
+	}
+	return fmt.Sprintf("%dM", mb)
+}
+
+// formatRuntime renders a duration as 45s, 12m03s, 3h04m, or 2d05h.
+func formatRuntime(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dd%02dh", int(d.Hours())/24, int(d.Hours())%24)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePSDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"00:05", 5 * time.Second},
+		{"12:03", 12*time.Minute + 3*time.Second},
+		{"0:01.50", 1500 * time.Millisecond}, // macOS time
+		{"01:02:03", time.Hour + 2*time.Minute + 3*time.Second},
+		{"2-03:00:00", 51 * time.Hour},
+	}
+	for _, tt := range tests {
+		got, err := parsePSDuration(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("parsePSDuration(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "5", "a:b", "x-01:00"} {
+		if _, err := parsePSDuration(bad); err == nil {
+			t.Errorf("parsePSDuration(%q) expected an error", bad)
+		}
+	}
+}
+
+func TestParseProcessSamples(t *testing.T) {
+	out := "  101  204800 00:01:30 1-02:00:00\n  202   10240    00:00  00:42\ngarbage\n"
+	samples := parseProcessSamples(out)
+	if len(samples) != 2 {
+		t.Fatalf("got %d samples, want 2: %v", len(samples), samples)
+	}
+	want := processSample{rssKB: 204800, cpuTime: 90 * time.Second, elapsed: 26 * time.Hour}
+	if samples[101] != want {
+		t.Errorf("samples[101] = %+v, want %+v", samples[101], want)
+	}
+	if samples[202].elapsed != 42*time.Second {
+		t.Errorf("samples[202].elapsed = %v", samples[202].elapsed)
+	}
+}
+
+func TestSortTopRows(t *testing.T) {
+	rows := []topRow{
+		{session: agentSession{taskID: 3}, cpu: 0, memoryMB: 100},
+		{session: agentSession{taskID: 1}, cpu: 95},
+		{session: agentSession{taskID: 2}, cpu: 0, memoryMB: 100},
+		{session: agentSession{taskID: 4}, cpu: 0, memoryMB: 900},
+	}
+	sortTopRows(rows)
+	var got []int
+	for _, r := range rows {
+		got = append(got, r.session.taskID)
+	}
+	want := []int{1, 4, 2, 3}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("order = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestFormatRuntime(t *testing.T) {
+	tests := map[time.Duration]string{
+		45 * time.Second:               "45s",
+		12*time.Minute + 3*time.Second: "12m03s",
+		3*time.Hour + 4*time.Minute:    "3h04m",
+		53*time.Hour + 30*time.Minute:  "2d05h",
+	}
+	for d, want := range tests {
+		if got := formatRuntime(d); got != want {
+			t.Errorf("formatRuntime(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+// taskDiff is what 'ty show --diff' reports about a task's changes.
+type taskDiff struct {
+	Branch       string // the task's branch, for diffing by hand when the worktree is gone
+	DefaultBase  string // the project's default branch, for the same
+	Available    bool   // the worktree exists and a base was found
+	Base         string // commit the diff is taken against
+	BaseLabel    string // what Base is, for display
+	Stat         string // git diff --stat
+	Patch        string // git diff
+	FilesChanged int
+	Insertions   int
+	Deletions    int
+}
+
+// taskDiffBase returns the commit a task's changes are measured from, and a
+// label for it. A task that checked out an existing branch (SourceBranch)
+// works on that branch directly, so its changes are the ones since the commit
+// its worktree was created at; diffing against the default branch would also
+// show the branch's earlier work. Other tasks diff against the merge base with
+// the default branch, like 'ty summary'.
+func taskDiffBase(task *db.Task, baseCommit string) (string, string) {
+	dir := task.WorktreePath
+	if task.SourceBranch != "" && baseCommit != "" {
+		return baseCommit, fmt.Sprintf("%s at task start (%s)", task.SourceBranch, shortSHA(baseCommit))
+	}
+	if ref := worktreeDefaultBase(dir); ref != "" {
+		if mergeBase, err := gitOutput(dir, "merge-base", ref, "HEAD"); err == nil && mergeBase != "" {
+			return mergeBase, ref
+		}
+	}
+	if baseCommit != "" {
+		return baseCommit, fmt.Sprintf("task start (%s)", shortSHA(baseCommit))
+	}
+	return "", ""
+}
+
+// computeTaskDiff diffs the task's worktree, uncommitted changes included,
+// against its base. withPatch adds the full diff; color renders it with git's
+// colors.
+func computeTaskDiff(database *db.DB, task *db.Task, withPatch, color bool) (*taskDiff, error) {
+	d := &taskDiff{Branch: task.BranchName}
+	if info, err := os.Stat(task.WorktreePath); task.WorktreePath == "" || err != nil || !info.IsDir() {
+		d.DefaultBase = "main"
+		if p, err := database.GetProjectByName(task.Project); err == nil && p != nil && p.Path != "" {
+			if ref := worktreeDefaultBase(p.Path); ref != "" {
+				d.DefaultBase = ref
+			}
+		}
+		return d, nil
+	}
+	baseCommit, err := database.GetTaskBaseCommit(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	d.Base, d.BaseLabel = taskDiffBase(task, baseCommit)
+	if d.Base == "" {
+		return d, nil
+	}
+
+	colorFlag := "--color=never"
+	if color {
+		colorFlag = "--color=always"
+	}
+	if d.Stat, err = gitOutput(task.WorktreePath, "diff", colorFlag, "--stat", d.Base); err != nil {
+		return nil, fmt.Errorf("git diff --stat: %w", err)
+	}
+	numstat, err := gitOutput(task.WorktreePath, "diff", "--numstat", d.Base)
+	if err != nil {
+		return nil, fmt.Errorf("git diff --numstat: %w", err)
+	}
+	d.FilesChanged, d.Insertions, d.Deletions = parseNumstat(numstat)
+	if withPatch {
+		if d.Patch, err = gitOutput(task.WorktreePath, "diff", colorFlag, d.Base); err != nil {
+			return nil, fmt.Errorf("git diff: %w", err)
+		}
+	}
+	d.Available = true
+	return d, nil
+}
+
+// parseNumstat totals `git diff --numstat` output. Binary files count as
+// changed files with no line counts.
+func parseNumstat(out string) (files, insertions, deletions int) {
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		files++
+		if n, err := strconv.Atoi(fields[0]); err == nil {
+			insertions += n
+		}
+		if n, err := strconv.Atoi(fields[1]); err == nil {
+			deletions += n
+		}
+	}
+	return files, insertions, deletions
+}
+
+func (d *taskDiff) json() map[string]interface{} {
+	out := map[string]interface{}{"available": d.Available}
+	if d.Branch != "" {
+		out["branch"] = d.Branch
+	}
+	if !d.Available {
+		if d.Branch != "" {
+			out["manual_command"] = d.manualCommand()
+		}
+		return out
+	}
+	out["base"] = d.Base
+	out["base_label"] = d.BaseLabel
+	out["files_changed"] = d.FilesChanged
+	out["insertions"] = d.Insertions
+	out["deletions"] = d.Deletions
+	out["stat"] = d.Stat
+	if d.Patch != "" {
+		out["patch"] = d.Patch
+	}
+	return out
+}
+
+func printTaskDiff(d *taskDiff) {
+	fmt.Println()
+	if !d.Available {
+		fmt.Println(boldStyle.Render("Changes:"))
+		if d.Branch != "" {
+			fmt.Println(dimStyle.Render("Worktree not available. Diff the branch by hand from the project: " + d.manualCommand()))
+		} else {
+			fmt.Println(dimStyle.Render("No worktree or branch to diff."))
+		}
+		return
+	}
+	fmt.Println(boldStyle.Render("Changes:") + " " + dimStyle.Render("vs "+d.BaseLabel))
+	if d.FilesChanged == 0 {
+		fmt.Println(dimStyle.Render("No changes."))
+		return
+	}
+	fmt.Println(d.Stat)
+	if d.Patch != "" {
+		fmt.Println()
+		fmt.Println(d.Patch)
+	}
+}
+
+// manualCommand is the git command to diff the task's branch from the
+// project's repository once its worktree is gone.
+func (d *taskDiff) manualCommand() string {
+	base := d.DefaultBase
+	if base == "" {
+		base = "main"
+	}
+	return fmt.Sprintf("git diff %s...%s", base, d.Branch)
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// stdoutIsTerminal reports whether stdout is a terminal (so color is wanted).
+func stdoutIsTerminal() bool {
+	fi, err := os.Stdout.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
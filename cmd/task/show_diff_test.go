@@ -0,0 +1,129 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestComputeTaskDiff(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	git(t, dir, "init", "-q", "-b", "main")
+	os.WriteFile(filepath.Join(dir, "app.go"), []byte("package app\n"), 0644)
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-q", "-m", "init")
+	git(t, dir, "checkout", "-q", "-b", "task/1")
+	os.WriteFile(filepath.Join(dir, "app.go"), []byte("package app\n\nfunc Login() {}\n"), 0644)
+	git(t, dir, "commit", "-q", "-am", "add login")
+	os.WriteFile(filepath.Join(dir, "wip.go"), []byte("package app\n"), 0644)
+	git(t, dir, "add", "wip.go")
+
+	task := &db.Task{Title: "Login", Status: db.StatusBacklog, Project: "personal", WorktreePath: dir, BranchName: "task/1"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	d, err := computeTaskDiff(database, task, true, false)
+	if err != nil {
+		t.Fatalf("computeTaskDiff: %v", err)
+	}
+	if !d.Available || d.BaseLabel != "main" {
+		t.Fatalf("expected a diff against main, got %+v", d)
+	}
+	if d.FilesChanged != 2 || d.Insertions != 3 || d.Deletions != 0 {
+		t.Errorf("totals = %d files +%d -%d, want 2 files +3 -0", d.FilesChanged, d.Insertions, d.Deletions)
+	}
+	for _, want := range []string{"+func Login() {}", "wip.go"} {
+		if !strings.Contains(d.Patch, want) {
+			t.Errorf("patch missing %q:\n%s", want, d.Patch)
+		}
+	}
+	if !strings.Contains(d.Stat, "app.go") {
+		t.Errorf("stat missing app.go:\n%s", d.Stat)
+	}
+
+	statOnly, err := computeTaskDiff(database, task, false, false)
+	if err != nil {
+		t.Fatalf("computeTaskDiff: %v", err)
+	}
+	if statOnly.Patch != "" || statOnly.Stat == "" {
+		t.Errorf("stat only should have a stat and no patch: %+v", statOnly)
+	}
+}
+
+func TestComputeTaskDiffSourceBranch(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	// The task checked out feature, which already had work on it; only what
+	// the task added since should show.
+	dir := t.TempDir()
+	git(t, dir, "init", "-q", "-b", "main")
+	os.WriteFile(filepath.Join(dir, "app.go"), []byte("package app\n"), 0644)
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-q", "-m", "init")
+	git(t, dir, "checkout", "-q", "-b", "feature")
+	os.WriteFile(filepath.Join(dir, "earlier.go"), []byte("package app\n"), 0644)
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-q", "-m", "earlier work")
+	start := headSHA(t, dir)
+	os.WriteFile(filepath.Join(dir, "task.go"), []byte("package app\n"), 0644)
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-q", "-m", "task work")
+
+	task := &db.Task{Title: "Feature", Status: db.StatusBacklog, Project: "personal", WorktreePath: dir, BranchName: "feature", SourceBranch: "feature"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if err := database.SetTaskBaseCommit(task.ID, start); err != nil {
+		t.Fatalf("SetTaskBaseCommit: %v", err)
+	}
+
+	d, err := computeTaskDiff(database, task, true, false)
+	if err != nil {
+		t.Fatalf("computeTaskDiff: %v", err)
+	}
+	if d.Base != start || !strings.HasPrefix(d.BaseLabel, "feature at task start") {
+		t.Errorf("base = %q (%s), want %q", d.Base, d.BaseLabel, start)
+	}
+	if !strings.Contains(d.Stat, "task.go") || strings.Contains(d.Stat, "earlier.go") {
+		t.Errorf("expected only the task's changes:\n%s", d.Stat)
+	}
+}
+
+func TestComputeTaskDiffWorktreeGone(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	task := &db.Task{Title: "Gone", Status: db.StatusDone, Project: "personal", WorktreePath: filepath.Join(t.TempDir(), "gone"), BranchName: "task/9-gone"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	d, err := computeTaskDiff(database, task, true, false)
+	if err != nil {
+		t.Fatalf("computeTaskDiff: %v", err)
+	}
+	if d.Available {
+		t.Fatalf("expected no diff for a missing worktree: %+v", d)
+	}
+	out := d.json()
+	if out["available"] != false || out["branch"] != "task/9-gone" {
+		t.Errorf("json = %v", out)
+	}
+	if cmd, _ := out["manual_command"].(string); !strings.HasSuffix(cmd, "...task/9-gone") {
+		t.Errorf("manual command = %q", cmd)
+	}
+}
+
+func TestParseNumstat(t *testing.T) {
+	files, ins, del := parseNumstat("3\t1\ta.go\n-\t-\tlogo.png\n10\t0\tb.go\n")
+	if files != 3 || ins != 13 || del != 1 {
+		t.Errorf("got %d files +%d -%d", files, ins, del)
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/spf13/cobra"
+)
+
+func newStatsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show throughput, cycle time, and Claude usage",
+		Long: `Reports how tasks flow through the board: tasks per status, average cycle
+time (created to completed), average time spent blocked, and completions per
+day. Tasks moved between projects are re-created by the move, so they are left
+out of the cycle time and counted separately.
+
+Then sums the token usage recorded from tasks' Claude sessions, with an
+estimated cost at list prices, by project and by day. Usage is recorded at the
+end of each Claude turn. Time tracked with 'ty track' (and the time tasks
+spent processing) is summed the same way.
+
+--since limits completions, blocked spells, usage, and tracked time to the
+window; status counts are always current.
+
+Examples:
+  ty stats
+  ty stats --since 7d         # Last 7 days
+  ty stats --project myapp --json
+  ty stats --by day`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			by, _ := cmd.Flags().GetString("by")
+			sinceStr, _ := cmd.Flags().GetString("since")
+			project, _ := cmd.Flags().GetString("project")
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			if by != "" && by != "project" && by != "day" {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: --by must be project or day"))
+				os.Exit(1)
+			}
+			var since time.Time
+			if sinceStr != "" {
+				window, err := parseRelativeDuration(sinceStr)
+				if err != nil || window <= 0 {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid duration: "+sinceStr))
+					os.Exit(1)
+				}
+				since = time.Now().Add(-window)
+			}
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			throughput, err := database.TaskThroughput(project, since)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			usage := map[string][]db.UsageTotal{}
+			if by == "" || by == "project" {
+				totals, err := database.UsageByProject(project, since)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+				usage["by_project"] = totals
+			}
+			if by == "" || by == "day" {
+				totals, err := database.UsageByDay(project, since)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+				usage["by_day"] = totals
+			}
+			trackedByProject, trackedByDay, err := database.TrackedTimeReport(project, since)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			tracked := map[string][]db.TrackedTotal{}
+			if by == "" || by == "project" {
+				tracked["by_project"] = trackedByProject
+			}
+			if by == "" || by == "day" {
+				tracked["by_day"] = trackedByDay
+			}
+
+			if outputJSON {
+				output := map[string]interface{}{"throughput": throughputJSON(throughput)}
+				for k, v := range usage {
+					output[k] = v
+				}
+				output["tracked"] = tracked
+				if !since.IsZero() {
+					output["since"] = since.Format(time.RFC3339)
+				}
+				data, _ := json.MarshalIndent(output, "", "  ")
+				fmt.Println(string(data))
+				return
+			}
+
+			printThroughput(throughput)
+			for _, section := range []struct{ key, title, column string }{
+				{"by_project", "Usage by project", "PROJECT"},
+				{"by_day", "Usage by day", "DAY"},
+			} {
+				totals, ok := usage[section.key]
+				if !ok {
+					continue
+				}
+				fmt.Println()
+				fmt.Println(boldStyle.Render(section.title))
+				if len(totals) == 0 {
+					fmt.Println(dimStyle.Render("No usage recorded"))
+					continue
+				}
+				printUsageTotals(section.column, totals)
+			}
+			for _, section := range []struct{ key, title, column string }{
+				{"by_project", "Tracked time by project", "PROJECT"},
+				{"by_day", "Tracked time by day", "DAY"},
+			} {
+				totals, ok := tracked[section.key]
+				if !ok {
+					continue
+				}
+				fmt.Println()
+				fmt.Println(boldStyle.Render(section.title))
+				if len(totals) == 0 {
+					fmt.Println(dimStyle.Render("No time tracked"))
+					continue
+				}
+				printTrackedTotals(section.column, totals)
+			}
+		},
+	}
+	cmd.Flags().String("by", "", "Only show one usage and tracked time breakdown: project or day")
+	cmd.Flags().String("since", "", "Only count completions, blocked time, usage, and tracked time in this window (e.g. 7d, 24h)")
+	cmd.Flags().StringP("project", "p", "", "Only count tasks in this project")
+	cmd.Flags().Bool("json", false, "Output in JSON format")
+	cmd.RegisterFlagCompletionFunc("project", completeFlagProjects)
+	return cmd
+}
+
+// throughputJSON is the --json form of ThroughputStats, with durations in
+// seconds.
+func throughputJSON(t *db.ThroughputStats) map[string]interface{} {
+	perDay := t.CompletedPerDay
+	if perDay == nil {
+		perDay = []db.DayCount{}
+	}
+	return map[string]interface{}{
+		"status_counts":        t.StatusCounts,
+		"completed":            t.Completed,
+		"cycle_time_seconds":   int64(t.CycleTime.Seconds()),
+		"cycle_time_samples":   t.CycleTimeSamples,
+		"moved_excluded":       t.MovedExcluded,
+		"blocked_time_seconds": int64(t.BlockedTime.Seconds()),
+		"blocked_samples":      t.BlockedSamples,
+		"completed_per_day":    perDay,
+	}
+}
+
+func printThroughput(t *db.ThroughputStats) {
+	fmt.Println(boldStyle.Render("Tasks by status"))
+	if len(t.StatusCounts) == 0 {
+		fmt.Println(dimStyle.Render("No tasks"))
+	}
+	for _, status := range t.Statuses() {
+		fmt.Printf("  %-12s %d\n", status, t.StatusCounts[status])
+	}
+
+	fmt.Println()
+	fmt.Println(boldStyle.Render("Throughput"))
+	fmt.Printf("  Completed:      %d\n", t.Completed)
+	cycle := dimStyle.Render("n/a")
+	if t.CycleTimeSamples > 0 {
+		cycle = fmt.Sprintf("%s (%d tasks)", formatStatsDuration(t.CycleTime), t.CycleTimeSamples)
+	}
+	if t.MovedExcluded > 0 {
+		cycle += dimStyle.Render(fmt.Sprintf(", %d moved task(s) excluded", t.MovedExcluded))
+	}
+	fmt.Printf("  Cycle time:     %s\n", cycle)
+	blocked := dimStyle.Render("n/a")
+	if t.BlockedSamples > 0 {
+		blocked = fmt.Sprintf("%s (%d spells)", formatStatsDuration(t.BlockedTime), t.BlockedSamples)
+	}
+	fmt.Printf("  Time blocked:   %s\n", blocked)
+
+	if len(t.CompletedPerDay) > 0 {
+		fmt.Println()
+		fmt.Println(boldStyle.Render("Completed per day"))
+		for _, d := range t.CompletedPerDay {
+			fmt.Printf("  %s %3d %s\n", d.Day, d.Count, dimStyle.Render(strings.Repeat("▇", min(d.Count, 40))))
+		}
+	}
+}
+
+// formatStatsDuration renders an average duration coarsely: 45m, 3.2h, 2.5d.
+func formatStatsDuration(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("%.1fd", d.Hours()/24)
+	case d >= time.Hour:
+		return fmt.Sprintf("%.1fh", d.Hours())
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%ds", int(d.Seconds()))
+}
+
+func printUsageTotals(column string, totals []db.UsageTotal) {
+	fmt.Println(dimStyle.Render(fmt.Sprintf("%-24s %6s %10s %10s %10s", column, "TASKS", "INPUT", "OUTPUT", "COST")))
+	var sum db.UsageTotal
+	for _, u := range totals {
+		key := u.Key
+		if key == "" {
+			key = "(none)"
+		}
+		fmt.Printf("%-24s %6d %10s %10s %10s\n", truncate(key, 24), u.Tasks, formatTokenCount(u.InputTokens), formatTokenCount(u.OutputTokens), formatCost(u.CostUSD))
+		sum.InputTokens += u.InputTokens
+		sum.OutputTokens += u.OutputTokens
+		sum.CostUSD += u.CostUSD
+	}
+	fmt.Println(dimStyle.Render(fmt.Sprintf("%-24s %6s %10s %10s %10s", "Total", "", formatTokenCount(sum.InputTokens), formatTokenCount(sum.OutputTokens), formatCost(sum.CostUSD))))
+}
+
+// formatUsage renders a task's token totals for ty show.
+func formatUsage(input, output int64, cost float64) string {
+	return fmt.Sprintf("%s in / %s out (%s)", formatTokenCount(input), formatTokenCount(output), formatCost(cost))
+}
+
+// formatTokenCount abbreviates a token count: 950, 12.3k, 4.5M.
+func formatTokenCount(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1e3)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+func formatCost(usd float64) string {
+	return fmt.Sprintf("$%.2f", usd)
+}
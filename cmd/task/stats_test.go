@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatTokenCount(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0"},
+		{950, "950"},
+		{12_345, "12.3k"},
+		{4_500_000, "4.5M"},
+	}
+	for _, tt := range tests {
+		if got := formatTokenCount(tt.n); got != tt.want {
+			t.Errorf("formatTokenCount(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+	if got := formatUsage(12_345, 950, 1.234); got != "12.3k in / 950 out ($1.23)" {
+		t.Errorf("formatUsage = %q", got)
+	}
+}
+
+func TestFormatStatsDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{45 * time.Second, "45s"},
+		{45 * time.Minute, "45m"},
+		{3*time.Hour + 12*time.Minute, "3.2h"},
+		{60 * time.Hour, "2.5d"},
+	}
+	for _, tt := range tests {
+		if got := formatStatsDuration(tt.d); got != tt.want {
+			t.Errorf("formatStatsDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
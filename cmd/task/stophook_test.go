@@ -250,3 +250,17 @@ func TestWorkflowStepUnfinishedReason(t *testing.T) {
 		t.Error("WorkflowStepFinished should agree with an empty reason")
 	}
 }
+
+func TestBlockedQuestion(t *testing.T) {
+	if got := blockedQuestion("  "); got != "Waiting for user input" {
+		t.Errorf("empty message: got %q", got)
+	}
+	if got := blockedQuestion("  Which database should I use?\n"); got != "Which database should I use?" {
+		t.Errorf("got %q", got)
+	}
+	long := strings.Repeat("é", maxBlockedQuestionLen+10)
+	got := []rune(blockedQuestion(long))
+	if len(got) != maxBlockedQuestionLen+1 || got[len(got)-1] != '…' {
+		t.Errorf("long message not capped: %d runes", len(got))
+	}
+}
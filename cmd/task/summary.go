@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bborn/workflow/internal/autocomplete"
+	"github.com/bborn/workflow/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// summaryLogLines is how many of the task's most recent log lines are sent to
+// the summarizer.
+const summaryLogLines = 300
+
+func newSummaryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "summary <task-id>",
+		Short:             "Generate a summary of what a task accomplished",
+		ValidArgsFunction: completeTaskIDs,
+		Long: `Reads the task's logs and the diff in its worktree, asks Claude (Haiku, via
+the anthropic_api_key setting or ANTHROPIC_API_KEY) for a short summary of what
+was done, and saves it as the task's summary shown by 'ty show'.
+
+A task that already has a summary is left alone unless --force is given.
+--json prints the generated summary without saving it.
+
+Examples:
+  ty summary 42
+  ty summary 42 --force
+  ty summary 42 --json | jq -r .summary`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			force, _ := cmd.Flags().GetBool("force")
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			taskID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid task ID: "+args[0]))
+				os.Exit(1)
+			}
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			task, err := database.GetTask(taskID)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if task == nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Task #%d not found", taskID)))
+				os.Exit(1)
+			}
+			if task.Summary != "" && !force && !outputJSON {
+				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Task #%d already has a summary (use --force to regenerate it)", taskID)))
+				os.Exit(1)
+			}
+
+			apiKey, _ := database.GetSetting("anthropic_api_key")
+			svc := autocomplete.NewService(apiKey)
+			if !svc.IsAvailable() {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: no Anthropic API key (set ANTHROPIC_API_KEY or the anthropic_api_key setting)"))
+				os.Exit(1)
+			}
+
+			logs, err := summaryLogText(database, taskID)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			diff := taskWorktreeDiff(task.WorktreePath)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+			defer cancel()
+			summary, err := svc.GenerateSummary(ctx, task.Title, task.Body, logs, diff)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			if outputJSON {
+				data, _ := json.MarshalIndent(map[string]interface{}{
+					"id":      taskID,
+					"summary": summary,
+				}, "", "  ")
+				fmt.Println(string(data))
+				return
+			}
+
+			if err := database.UpdateTaskSummary(taskID, summary); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			fmt.Println(summary)
+			fmt.Println()
+			outln(successStyle.Render(fmt.Sprintf("Saved summary for task #%d", taskID)))
+		},
+	}
+	cmd.Flags().Bool("force", false, "Replace an existing summary")
+	cmd.Flags().Bool("json", false, "Print the summary as JSON without saving it")
+	return cmd
+}
+
+// summaryLogText returns the task's recent log lines, oldest first, as the
+// summarizer sees them. Notes left with `ty note` are for the user and are
+// left out.
+func summaryLogText(database *db.DB, taskID int64) (string, error) {
+	logs, err := database.GetTaskLogs(taskID, summaryLogLines)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for i := len(logs) - 1; i >= 0; i-- {
+		l := logs[i]
+		if l.LineType == db.NoteLineType || l.LineType == db.PinnedNoteLineType {
+			continue
+		}
+		fmt.Fprintf(&b, "[%s] %s\n", l.LineType, l.Content)
+	}
+	return b.String(), nil
+}
+
+// taskWorktreeDiff returns the changes in a task's worktree since it branched
+// from the default branch, including uncommitted work. It returns "" when the
+// worktree is gone or isn't a git checkout.
+func taskWorktreeDiff(worktreePath string) string {
+	if worktreePath == "" {
+		return ""
+	}
+	if _, err := os.Stat(worktreePath); err != nil {
+		return ""
+	}
+
+	baseRef := worktreeDefaultBase(worktreePath)
+	if baseRef == "" {
+		return ""
+	}
+	mergeBase, err := gitOutput(worktreePath, "merge-base", baseRef, "HEAD")
+	if err != nil || mergeBase == "" {
+		return ""
+	}
+	diff, err := gitOutput(worktreePath, "diff", mergeBase)
+	if err != nil {
+		return ""
+	}
+	return diff
+}
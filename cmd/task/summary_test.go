@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestSummaryLogText(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	ids := createTestTasks(t, database, 1)
+	database.AppendTaskLog(ids[0], "system", "Starting task")
+	database.AppendTaskLog(ids[0], db.NoteLineType, "private note")
+	database.AppendTaskLog(ids[0], "output", "Fixed the login redirect")
+
+	text, err := summaryLogText(database, ids[0])
+	if err != nil {
+		t.Fatalf("summaryLogText: %v", err)
+	}
+	if strings.Contains(text, "private note") {
+		t.Errorf("notes should be left out:\n%s", text)
+	}
+	start := strings.Index(text, "[system] Starting task")
+	end := strings.Index(text, "[output] Fixed the login redirect")
+	if start < 0 || end < 0 || start > end {
+		t.Errorf("expected logs oldest first:\n%s", text)
+	}
+}
+
+func TestTaskWorktreeDiff(t *testing.T) {
+	if got := taskWorktreeDiff(""); got != "" {
+		t.Errorf("no worktree: got %q", got)
+	}
+	if got := taskWorktreeDiff(filepath.Join(t.TempDir(), "gone")); got != "" {
+		t.Errorf("missing worktree: got %q", got)
+	}
+
+	dir := t.TempDir()
+	git(t, dir, "init", "-q", "-b", "main")
+	os.WriteFile(filepath.Join(dir, "app.go"), []byte("package app\n"), 0644)
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-q", "-m", "init")
+	git(t, dir, "checkout", "-q", "-b", "task/1")
+	os.WriteFile(filepath.Join(dir, "app.go"), []byte("package app\n\nfunc Login() {}\n"), 0644)
+	git(t, dir, "commit", "-q", "-am", "add login")
+	os.WriteFile(filepath.Join(dir, "wip.go"), []byte("package app\n"), 0644)
+	git(t, dir, "add", "wip.go")
+
+	diff := taskWorktreeDiff(dir)
+	for _, want := range []string{"+func Login() {}", "wip.go"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("diff missing %q:\n%s", want, diff)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/spf13/cobra"
+)
+
+func newTagsCmd() *cobra.Command {
+	tagsCmd := &cobra.Command{
+		Use:   "tags",
+		Short: "List, rename, and delete tags across all tasks",
+		Long: `Tags are free-form, so they drift ("bug" vs "Bug" vs "bugs"). List every tag
+in use with its task count, then fold variants together with rename.
+
+Examples:
+  ty tags                       # Same as: ty tags list
+  ty tags list --json
+  ty tags rename Bug bug
+  ty tags delete wontfix`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			listTags(cmd)
+		},
+	}
+	tagsCmd.Flags().Bool("json", false, "Output in JSON format")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List tags with their task counts",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			listTags(cmd)
+		},
+	}
+	listCmd.Flags().Bool("json", false, "Output in JSON format")
+	tagsCmd.AddCommand(listCmd)
+
+	renameCmd := &cobra.Command{
+		Use:               "rename <old> <new>",
+		Short:             "Rename a tag on every task",
+		Long:              `Replaces the tag <old> (exact match) with <new> on every task. Tasks that already carry <new> end up with a single copy.`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeTagNames,
+		Run: func(cmd *cobra.Command, args []string) {
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			n, err := database.RenameTag(args[0], args[1])
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if n == 0 {
+				fmt.Println(dimStyle.Render(fmt.Sprintf("No tasks are tagged %q", args[0])))
+				return
+			}
+			outln(successStyle.Render(fmt.Sprintf("Renamed %q to %q on %d task(s)", args[0], args[1], n)))
+		},
+	}
+	tagsCmd.AddCommand(renameCmd)
+
+	deleteCmd := &cobra.Command{
+		Use:               "delete <tag>",
+		Aliases:           []string{"rm"},
+		Short:             "Remove a tag from every task",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTagNames,
+		Run: func(cmd *cobra.Command, args []string) {
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			n, err := database.DeleteTag(args[0])
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if n == 0 {
+				fmt.Println(dimStyle.Render(fmt.Sprintf("No tasks are tagged %q", args[0])))
+				return
+			}
+			outln(successStyle.Render(fmt.Sprintf("Removed %q from %d task(s)", args[0], n)))
+		},
+	}
+	tagsCmd.AddCommand(deleteCmd)
+
+	return tagsCmd
+}
+
+func listTags(cmd *cobra.Command) {
+	outputJSON, _ := cmd.Flags().GetBool("json")
+
+	database, err := openTaskDB(db.DefaultPath())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+		os.Exit(1)
+	}
+	defer database.Close()
+
+	counts, err := database.TagCounts()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+		os.Exit(1)
+	}
+
+	if outputJSON {
+		data, _ := json.MarshalIndent(counts, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+	if len(counts) == 0 {
+		fmt.Println(dimStyle.Render("No tags in use"))
+		return
+	}
+	for _, c := range counts {
+		fmt.Printf("%5d  %s\n", c.Count, c.Tag)
+	}
+}
+
+// completeTagNames completes tags in use for the first argument.
+func completeTagNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	database, err := db.Open(db.DefaultPath())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer database.Close()
+	counts, err := database.TagCounts()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	tags := make([]string, 0, len(counts))
+	for _, c := range counts {
+		tags = append(tags, fmt.Sprintf("%s\t%d task(s)", c.Tag, c.Count))
+	}
+	return tags, cobra.ShellCompDirectiveNoFileComp
+}
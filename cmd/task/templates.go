@@ -0,0 +1,411 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/executor"
+	"github.com/spf13/cobra"
+)
+
+// templatePlaceholder matches {{name}} in a template's title and body.
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_-]+)\s*\}\}`)
+
+// parseTemplateArgs turns repeated --arg key=value flags into a map.
+func parseTemplateArgs(pairs []string) (map[string]string, error) {
+	args := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --arg %q, expected key=value", pair)
+		}
+		args[key] = value
+	}
+	return args, nil
+}
+
+// templateArgNames returns the distinct placeholder names in s, in order of
+// first appearance.
+func templateArgNames(s string) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, m := range templatePlaceholder.FindAllStringSubmatch(s, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// expandTemplateArgs fills the {{name}} placeholders in s. Every placeholder
+// must have a value.
+func expandTemplateArgs(s string, args map[string]string) (string, error) {
+	var missing []string
+	for _, name := range templateArgNames(s) {
+		if _, ok := args[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing --arg for %s", strings.Join(missing, ", "))
+	}
+	return templatePlaceholder.ReplaceAllStringFunc(s, func(m string) string {
+		return args[templatePlaceholder.FindStringSubmatch(m)[1]]
+	}), nil
+}
+
+// validateTemplateRefs checks that the type and executor a template names
+// still exist; either may have been removed since the template was saved.
+func validateTemplateRefs(database *db.DB, tmpl *db.TaskTemplate) error {
+	if tmpl.Type != "" {
+		t, err := database.GetTaskTypeByName(tmpl.Type)
+		if err != nil {
+			return err
+		}
+		if t == nil {
+			return fmt.Errorf("template %q uses task type %q, which no longer exists", tmpl.Name, tmpl.Type)
+		}
+	}
+	if !executor.IsValidExecutor(tmpl.Executor) {
+		return fmt.Errorf("template %q uses executor %q, which is not available (valid: %s)",
+			tmpl.Name, tmpl.Executor, strings.Join(executor.ExecutorNames(), ", "))
+	}
+	return nil
+}
+
+// instantiateTemplate loads the named template, validates it, and returns it
+// with its title and body placeholders filled from args.
+func instantiateTemplate(database *db.DB, name string, args map[string]string) (*db.TaskTemplate, error) {
+	tmpl, err := database.GetTaskTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+	if tmpl == nil {
+		return nil, fmt.Errorf("template %q not found (see: ty templates list)", name)
+	}
+	if err := validateTemplateRefs(database, tmpl); err != nil {
+		return nil, err
+	}
+	if tmpl.Title, err = expandTemplateArgs(tmpl.Title, args); err != nil {
+		return nil, fmt.Errorf("template %q: %w", name, err)
+	}
+	if tmpl.Body, err = expandTemplateArgs(tmpl.Body, args); err != nil {
+		return nil, fmt.Errorf("template %q: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// completeTemplateNames completes saved template names.
+func completeTemplateNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return templateNameCompletions(), cobra.ShellCompDirectiveNoFileComp
+}
+
+func templateNameCompletions() []string {
+	database, err := db.Open(db.DefaultPath())
+	if err != nil {
+		return nil
+	}
+	defer database.Close()
+	templates, err := database.ListTaskTemplates()
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(templates))
+	for _, t := range templates {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+func newTemplatesCmd() *cobra.Command {
+	templatesCmd := &cobra.Command{
+		Use:     "templates",
+		Aliases: []string{"template"},
+		Short:   "Manage reusable task templates",
+		Long: `Templates are saved starting points for tasks you create over and over.
+A template's title and body may contain {{name}} placeholders, filled in with
+--arg name=value when a task is created from it:
+
+  ty templates create qa-pr --title "QA: PR #{{pr}}" --body-file qa.md --tags qa
+  ty create --template qa-pr --arg pr=2526
+
+Flags given to 'ty create' override the template's values.
+
+Examples:
+  ty templates                  # List templates
+  ty templates show qa-pr
+  ty templates create qa-pr --from-task 42
+  ty templates delete qa-pr`,
+		Run: func(cmd *cobra.Command, args []string) {
+			listTemplates(cmd)
+		},
+	}
+	templatesCmd.Flags().Bool("json", false, "Output in JSON format")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List task templates",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			listTemplates(cmd)
+		},
+	}
+	listCmd.Flags().Bool("json", false, "Output in JSON format")
+	templatesCmd.AddCommand(listCmd)
+
+	showCmd := &cobra.Command{
+		Use:               "show <name>",
+		Short:             "Show a task template",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTemplateNames,
+		Run: func(cmd *cobra.Command, args []string) {
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			tmpl, err := database.GetTaskTemplate(args[0])
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if tmpl == nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Template %q not found", args[0])))
+				os.Exit(1)
+			}
+
+			if outputJSON {
+				out := map[string]interface{}{
+					"template": tmpl,
+					"args":     templateArgNames(tmpl.Title + "\n" + tmpl.Body),
+				}
+				data, _ := json.MarshalIndent(out, "", "  ")
+				fmt.Println(string(data))
+				return
+			}
+
+			fmt.Printf("%s %s\n", boldStyle.Render("Template:"), tmpl.Name)
+			fmt.Println(strings.Repeat("─", 50))
+			fmt.Printf("Title:    %s\n", tmpl.Title)
+			if tmpl.Type != "" {
+				fmt.Printf("Type:     %s\n", tmpl.Type)
+			}
+			if tmpl.Executor != "" {
+				fmt.Printf("Executor: %s\n", tmpl.Executor)
+			}
+			if tmpl.Project != "" {
+				fmt.Printf("Project:  %s\n", tmpl.Project)
+			}
+			if tmpl.Tags != "" {
+				fmt.Printf("Tags:     %s\n", tmpl.Tags)
+			}
+			if names := templateArgNames(tmpl.Title + "\n" + tmpl.Body); len(names) > 0 {
+				fmt.Printf("Args:     %s\n", strings.Join(names, ", "))
+			}
+			if tmpl.Body != "" {
+				fmt.Println()
+				fmt.Println(boldStyle.Render("Body:"))
+				fmt.Println(tmpl.Body)
+			}
+		},
+	}
+	showCmd.Flags().Bool("json", false, "Output in JSON format")
+	templatesCmd.AddCommand(showCmd)
+
+	createCmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Save a task template",
+		Long: `Save a task template, either from flags or copied from an existing task
+with --from-task. Flags override the copied task's values. Use --force to
+replace an existing template with the same name.
+
+Examples:
+  ty templates create qa-pr --title "QA: PR #{{pr}}" --body "Check PR #{{pr}} on staging" --tags qa
+  ty templates create weekly-report --from-task 42
+  ty templates create release --title "Release {{version}}" --body-file release.md --type writing`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := strings.TrimSpace(args[0])
+			if name == "" {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: template name is required"))
+				os.Exit(1)
+			}
+			fromTask, _ := cmd.Flags().GetInt64("from-task")
+			force, _ := cmd.Flags().GetBool("force")
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			tmpl := &db.TaskTemplate{Name: name}
+			if fromTask != 0 {
+				task, err := database.GetTask(fromTask)
+				if err != nil || task == nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Task #%d not found", fromTask)))
+					os.Exit(1)
+				}
+				tmpl.Title, tmpl.Body, tmpl.Type = task.Title, task.Body, task.Type
+				tmpl.Executor, tmpl.Tags, tmpl.Project = task.Executor, task.Tags, task.Project
+			}
+			if cmd.Flags().Changed("title") {
+				tmpl.Title, _ = cmd.Flags().GetString("title")
+			}
+			if cmd.Flags().Changed("body") {
+				body, _ := cmd.Flags().GetString("body")
+				tmpl.Body = unescapeNewlines(body)
+			}
+			if bodyFile, _ := cmd.Flags().GetString("body-file"); bodyFile != "" {
+				if tmpl.Body, err = readBodyFile(bodyFile, os.Stdin); err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+			}
+			for flag, field := range map[string]*string{
+				"type":     &tmpl.Type,
+				"executor": &tmpl.Executor,
+				"tags":     &tmpl.Tags,
+				"project":  &tmpl.Project,
+			} {
+				if cmd.Flags().Changed(flag) {
+					*field, _ = cmd.Flags().GetString(flag)
+				}
+			}
+			if strings.TrimSpace(tmpl.Title) == "" && strings.TrimSpace(tmpl.Body) == "" {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: a template needs a --title or --body (or --from-task)"))
+				os.Exit(1)
+			}
+			if err := validateTemplateRefs(database, tmpl); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			existing, err := database.GetTaskTemplate(name)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if existing != nil {
+				if !force {
+					fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Template %q already exists (use --force to replace it)", name)))
+					os.Exit(1)
+				}
+				err = database.UpdateTaskTemplate(tmpl)
+			} else {
+				err = database.CreateTaskTemplate(tmpl)
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			msg := fmt.Sprintf("Saved template %q", name)
+			if names := templateArgNames(tmpl.Title + "\n" + tmpl.Body); len(names) > 0 {
+				msg += " (args: " + strings.Join(names, ", ") + ")"
+			}
+			outln(successStyle.Render(msg))
+		},
+	}
+	createCmd.Flags().String("title", "", "Title pattern, e.g. \"QA: PR #{{pr}}\"")
+	createCmd.Flags().String("body", "", "Body pattern")
+	createCmd.Flags().String("body-file", "", "Read the body pattern from a file (- for stdin), verbatim")
+	createCmd.MarkFlagsMutuallyExclusive("body", "body-file")
+	createCmd.Flags().StringP("type", "t", "", "Task type")
+	createCmd.Flags().StringP("executor", "e", "", "Task executor")
+	createCmd.Flags().String("tags", "", "Task tags (comma-separated)")
+	createCmd.Flags().StringP("project", "p", "", "Project name")
+	createCmd.Flags().Int64("from-task", 0, "Copy title, body, type, executor, tags, and project from this task")
+	createCmd.Flags().BoolP("force", "f", false, "Replace an existing template with the same name")
+	createCmd.RegisterFlagCompletionFunc("project", completeFlagProjects)
+	createCmd.RegisterFlagCompletionFunc("type", completeFlagTypes)
+	templatesCmd.AddCommand(createCmd)
+
+	deleteCmd := &cobra.Command{
+		Use:               "delete <name>",
+		Aliases:           []string{"rm"},
+		Short:             "Delete a task template",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTemplateNames,
+		Run: func(cmd *cobra.Command, args []string) {
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			if err := database.DeleteTaskTemplate(args[0]); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			outln(successStyle.Render(fmt.Sprintf("Deleted template %q", args[0])))
+		},
+	}
+	templatesCmd.AddCommand(deleteCmd)
+
+	return templatesCmd
+}
+
+func listTemplates(cmd *cobra.Command) {
+	outputJSON, _ := cmd.Flags().GetBool("json")
+
+	database, err := openTaskDB(db.DefaultPath())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+		os.Exit(1)
+	}
+	defer database.Close()
+
+	templates, err := database.ListTaskTemplates()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+		os.Exit(1)
+	}
+
+	if outputJSON {
+		if templates == nil {
+			templates = []*db.TaskTemplate{}
+		}
+		data, _ := json.MarshalIndent(templates, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	if len(templates) == 0 {
+		fmt.Println(dimStyle.Render("No templates. Create one with: ty templates create <name> --title ..."))
+		return
+	}
+	for _, t := range templates {
+		line := fmt.Sprintf("%-20s %s", boldStyle.Render(t.Name), t.Title)
+		var extras []string
+		if t.Type != "" {
+			extras = append(extras, t.Type)
+		}
+		if t.Project != "" {
+			extras = append(extras, t.Project)
+		}
+		if names := templateArgNames(t.Title + "\n" + t.Body); len(names) > 0 {
+			extras = append(extras, "args: "+strings.Join(names, ", "))
+		}
+		if len(extras) > 0 {
+			line += " " + dimStyle.Render("("+strings.Join(extras, " · ")+")")
+		}
+		fmt.Println(line)
+	}
+	fmt.Println(dimStyle.Render(fmt.Sprintf("%d template(s)", len(templates))))
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestExpandTemplateArgs(t *testing.T) {
+	got, err := expandTemplateArgs("QA: PR #{{pr}} on {{ env }} ({{pr}})", map[string]string{"pr": "2526", "env": "staging"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "QA: PR #2526 on staging (2526)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := expandTemplateArgs("{{a}} {{b}}", map[string]string{"a": "1"}); err == nil || !strings.Contains(err.Error(), "b") {
+		t.Errorf("expected an error naming the missing arg, got %v", err)
+	}
+
+	if _, err := parseTemplateArgs([]string{"novalue"}); err == nil {
+		t.Error("expected an error for an --arg without =")
+	}
+	args, err := parseTemplateArgs([]string{"q=a=b"})
+	if err != nil || args["q"] != "a=b" {
+		t.Errorf("expected values to keep later '=', got %v, %v", args, err)
+	}
+}
+
+func TestInstantiateTemplateValidatesRefs(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	taskType := &db.TaskType{Name: "research", Label: "Research"}
+	if err := database.CreateTaskType(taskType); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.CreateTaskTemplate(&db.TaskTemplate{Name: "dig", Title: "Research {{topic}}", Type: "research"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err := instantiateTemplate(database, "dig", map[string]string{"topic": "caching"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.Title != "Research caching" {
+		t.Errorf("title = %q", tmpl.Title)
+	}
+
+	if err := database.DeleteTaskType(taskType.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := instantiateTemplate(database, "dig", map[string]string{"topic": "caching"}); err == nil {
+		t.Error("expected an error once the template's type is gone")
+	}
+	if _, err := instantiateTemplate(database, "missing", nil); err == nil {
+		t.Error("expected an error for an unknown template")
+	}
+}
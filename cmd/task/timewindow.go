@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dayWeekUnit matches the d (day) and w (week) units parseRelativeDuration
+// adds on top of Go's duration syntax.
+var dayWeekUnit = regexp.MustCompile(`(\d+(?:\.\d+)?)([dw])`)
+
+// parseRelativeDuration parses a duration like time.ParseDuration does, also
+// accepting d (24h) and w (7d) units: "7d", "2w", "1d12h", "90m". Commands
+// taking a look-back window (--since) share it so they all read "7d" the
+// same way.
+func parseRelativeDuration(s string) (time.Duration, error) {
+	expanded := dayWeekUnit.ReplaceAllStringFunc(s, func(m string) string {
+		parts := dayWeekUnit.FindStringSubmatch(m)
+		n, _ := strconv.ParseFloat(parts[1], 64)
+		hours := n * 24
+		if parts[2] == "w" {
+			hours *= 7
+		}
+		return strconv.FormatFloat(hours, 'f', -1, 64) + "h"
+	})
+	d, err := time.ParseDuration(expanded)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q (examples: 24h, 7d, 2w, 1d12h)", s)
+	}
+	return d, nil
+}
+
+// parseTimeBound parses a --since/--until value: an RFC3339 timestamp, a
+// date (midnight local time), or a duration counted back from now.
+func parseTimeBound(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	d, err := parseRelativeDuration(value)
+	if err != nil || d <= 0 {
+		return time.Time{}, fmt.Errorf("invalid time %q: use a date (2026-01-31), an RFC3339 timestamp, or a duration ago (24h, 7d)", value)
+	}
+	return now.Add(-d), nil
+}
+
+// parseTimeWindow parses the --since and --until flag values (either may be
+// empty) and checks that they form a non-empty window.
+func parseTimeWindow(since, until string, now time.Time) (from, to time.Time, err error) {
+	if since != "" {
+		if from, err = parseTimeBound(since, now); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if until != "" {
+		if to, err = parseTimeBound(until, now); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--since must be before --until")
+	}
+	return from, to, nil
+}
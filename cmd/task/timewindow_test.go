@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRelativeDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"24h", 24 * time.Hour},
+		{"7d", 7 * 24 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+		{"1d12h", 36 * time.Hour},
+		{"1.5d", 36 * time.Hour},
+		{"90m", 90 * time.Minute},
+	}
+	for _, tt := range tests {
+		got, err := parseRelativeDuration(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("parseRelativeDuration(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := parseRelativeDuration("soon"); err == nil {
+		t.Error("expected an error for a non-duration")
+	}
+}
+
+func TestParseTimeWindow(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	since, until, err := parseTimeWindow("7d", "2026-03-09T00:00:00Z", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !since.Equal(now.Add(-7 * 24 * time.Hour)) {
+		t.Errorf("since = %v", since)
+	}
+	if !until.Equal(time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("until = %v", until)
+	}
+
+	since, _, err = parseTimeWindow("2026-03-01", "", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local); !since.Equal(want) {
+		t.Errorf("date since = %v, want %v", since, want)
+	}
+
+	if _, _, err := parseTimeWindow("1d", "7d", now); err == nil {
+		t.Error("expected an error when --since is after --until")
+	}
+	if _, _, err := parseTimeWindow("yesterday", "", now); err == nil {
+		t.Error("expected an error for an unparseable bound")
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/spf13/cobra"
+)
+
+func newTrackCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "track",
+		Short: "Track time spent on tasks",
+		Long: `Tracks how long you actively work on a task, next to its wall-clock age.
+
+'ty track start' opens a time entry for a task and 'ty track stop' closes it.
+A task has at most one open entry: starting one stops the previous.
+
+Time a task spends processing is tracked automatically, as "auto" time, so
+'ty show' and 'ty stats' can compare your time with the agent's. A manual
+entry that is already open when the task starts processing keeps running
+instead.
+
+Examples:
+  ty track start 42
+  ty track stop 42
+  ty track report --since 7d
+  ty track report --project myapp --json`,
+	}
+
+	startCmd := &cobra.Command{
+		Use:               "start <task-id>",
+		Short:             "Start tracking time on a task",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTaskIDs,
+		Run: func(cmd *cobra.Command, args []string) {
+			database, taskID := openTrackTask(args[0])
+			defer database.Close()
+
+			_, stopped, err := database.StartTimeEntry(taskID, db.TimeSourceManual)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if stopped != nil {
+				fmt.Println(dimStyle.Render(fmt.Sprintf("Stopped the open %s entry after %s", stopped.Source, formatShortDuration(stopped.Duration(time.Now())))))
+			}
+			outln(successStyle.Render(fmt.Sprintf("Tracking time on task #%d", taskID)))
+		},
+	}
+	cmd.AddCommand(startCmd)
+
+	stopCmd := &cobra.Command{
+		Use:               "stop <task-id>",
+		Short:             "Stop tracking time on a task",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTaskIDs,
+		Run: func(cmd *cobra.Command, args []string) {
+			database, taskID := openTrackTask(args[0])
+			defer database.Close()
+
+			stopped, err := database.StopTimeEntry(taskID)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if stopped == nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Error: no time is being tracked on task #%d", taskID)))
+				os.Exit(1)
+			}
+			tracked, _ := database.TaskTrackedTime(taskID)
+			outln(successStyle.Render(fmt.Sprintf("Stopped tracking task #%d after %s", taskID, formatShortDuration(stopped.Duration(time.Now())))) +
+				dimStyle.Render(" (total "+formatTrackedTime(tracked)+")"))
+		},
+	}
+	cmd.AddCommand(stopCmd)
+
+	reportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Sum tracked time by project and day",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			sinceStr, _ := cmd.Flags().GetString("since")
+			project, _ := cmd.Flags().GetString("project")
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			var since time.Time
+			if sinceStr != "" {
+				window, err := parseRelativeDuration(sinceStr)
+				if err != nil || window <= 0 {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid duration: "+sinceStr))
+					os.Exit(1)
+				}
+				since = time.Now().Add(-window)
+			}
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			byProject, byDay, err := database.TrackedTimeReport(project, since)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			if outputJSON {
+				output := map[string]interface{}{
+					"by_project": byProject,
+					"by_day":     byDay,
+				}
+				if !since.IsZero() {
+					output["since"] = since.Format(time.RFC3339)
+				}
+				data, _ := json.MarshalIndent(output, "", "  ")
+				fmt.Println(string(data))
+				return
+			}
+			printTrackedReport(byProject, byDay)
+		},
+	}
+	reportCmd.Flags().String("since", "", "Only count time in this window (e.g. 7d, 24h)")
+	reportCmd.Flags().StringP("project", "p", "", "Only count tasks in this project")
+	reportCmd.Flags().Bool("json", false, "Output in JSON format")
+	reportCmd.RegisterFlagCompletionFunc("project", completeFlagProjects)
+	cmd.AddCommand(reportCmd)
+
+	return cmd
+}
+
+// openTrackTask parses a task ID and opens the database, exiting when either
+// fails or the task doesn't exist.
+func openTrackTask(arg string) (*db.DB, int64) {
+	taskID, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid task ID: "+arg))
+		os.Exit(1)
+	}
+	database, err := openTaskDB(db.DefaultPath())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+		os.Exit(1)
+	}
+	task, err := database.GetTask(taskID)
+	if err != nil || task == nil {
+		database.Close()
+		fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Error: task #%d not found", taskID)))
+		os.Exit(1)
+	}
+	return database, taskID
+}
+
+// formatTrackedTime renders a task's tracked time for ty show: the total,
+// then the manual and processing parts when both are present.
+func formatTrackedTime(t db.TrackedTime) string {
+	s := formatShortDuration(t.Total())
+	if t.Manual > 0 && t.Auto > 0 {
+		s += fmt.Sprintf(" (you %s, processing %s)", formatShortDuration(t.Manual), formatShortDuration(t.Auto))
+	} else if t.Auto > 0 {
+		s += " (processing)"
+	}
+	if t.Open {
+		s += ", running"
+	}
+	return s
+}
+
+// trackedJSON is the --json form of a task's tracked time, in seconds.
+func trackedJSON(t db.TrackedTime) map[string]interface{} {
+	return map[string]interface{}{
+		"manual_seconds": int64(t.Manual.Seconds()),
+		"auto_seconds":   int64(t.Auto.Seconds()),
+		"total_seconds":  int64(t.Total().Seconds()),
+		"running":        t.Open,
+	}
+}
+
+func printTrackedReport(byProject, byDay []db.TrackedTotal) {
+	for i, section := range []struct {
+		title, column string
+		totals        []db.TrackedTotal
+	}{
+		{"Tracked time by project", "PROJECT", byProject},
+		{"Tracked time by day", "DAY", byDay},
+	} {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(boldStyle.Render(section.title))
+		if len(section.totals) == 0 {
+			fmt.Println(dimStyle.Render("No time tracked"))
+			continue
+		}
+		printTrackedTotals(section.column, section.totals)
+	}
+}
+
+func printTrackedTotals(column string, totals []db.TrackedTotal) {
+	fmt.Println(dimStyle.Render(fmt.Sprintf("%-24s %6s %10s %10s %10s", column, "TASKS", "YOU", "PROCESSING", "TOTAL")))
+	var manual, auto int64
+	for _, t := range totals {
+		key := t.Key
+		if key == "" {
+			key = "(none)"
+		}
+		fmt.Printf("%-24s %6d %10s %10s %10s\n", truncate(key, 24), t.Tasks,
+			formatTrackedSeconds(t.ManualSeconds), formatTrackedSeconds(t.AutoSeconds), formatTrackedSeconds(t.ManualSeconds+t.AutoSeconds))
+		manual += t.ManualSeconds
+		auto += t.AutoSeconds
+	}
+	fmt.Println(dimStyle.Render(fmt.Sprintf("%-24s %6s %10s %10s %10s", "Total", "",
+		formatTrackedSeconds(manual), formatTrackedSeconds(auto), formatTrackedSeconds(manual+auto))))
+}
+
+func formatTrackedSeconds(s int64) string {
+	if s == 0 {
+		return "-"
+	}
+	return formatShortDuration(time.Duration(s) * time.Second)
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestFormatTrackedTime(t *testing.T) {
+	for _, c := range []struct {
+		tracked db.TrackedTime
+		want    string
+	}{
+		{db.TrackedTime{Manual: 90 * time.Minute}, "1h 30m"},
+		{db.TrackedTime{Auto: 20 * time.Minute}, "20m (processing)"},
+		{db.TrackedTime{Manual: time.Hour, Auto: 30 * time.Minute, Open: true}, "1h 30m (you 1h, processing 30m), running"},
+	} {
+		if got := formatTrackedTime(c.tracked); got != c.want {
+			t.Errorf("formatTrackedTime(%+v) = %q, want %q", c.tracked, got, c.want)
+		}
+	}
+}
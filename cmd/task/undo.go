@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/bborn/workflow/internal/config"
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/executor"
+	"github.com/spf13/cobra"
+)
+
+func newUndoCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "undo",
+		Short: "Revert the last delete, move or archive",
+		Long: `Reverts the most recent destructive operation: a delete (soft or --hard), a
+move to another project, or an archive. Each snapshots the task and its logs
+first, and 'ty undo' brings the task back from the latest snapshot, under its
+original ID where possible. A bulk archive is undone as a whole. Run it again
+to undo the operation before that.
+
+  delete          the trashed task is restored as it was
+  delete --hard   the task and its logs are recreated; the worktree is gone
+  move            the moved copy is deleted and the original recreated; the
+                  worktree is gone
+  archive         the task and its saved worktree are restored
+
+The undo log keeps the last ` + fmt.Sprint(db.DefaultUndoRetention) + ` operations; change that with the
+` + db.SettingUndoRetention + ` setting ("0" turns the log off).
+
+Examples:
+  ty undo
+  ty undo list
+  ty undo list --json`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			entries, err := database.LatestUndoBatch()
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			if len(entries) == 0 {
+				fmt.Println(dimStyle.Render("Nothing to undo"))
+				return
+			}
+
+			for _, entry := range entries {
+				res, err := runUndo(database, entry)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+					os.Exit(1)
+				}
+				outln(successStyle.Render(fmt.Sprintf("Undid %s: restored task #%d: %s", describeUndo(entry), res.TaskID, entry.Title)))
+				if res.TaskID != entry.TaskID {
+					fmt.Println(dimStyle.Render(fmt.Sprintf("Task #%d was taken, so it came back as #%d", entry.TaskID, res.TaskID)))
+				}
+				if res.MetadataOnly {
+					fmt.Println(warnStyle.Render("Only the task's metadata and logs were restored: its worktree was removed and can't be recovered."))
+				}
+			}
+		},
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the operations ty undo can revert",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			entries, err := database.ListUndo(0)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			if outputJSON {
+				output := make([]map[string]interface{}, 0, len(entries))
+				for _, e := range entries {
+					output = append(output, map[string]interface{}{
+						"id":            e.ID,
+						"batch_id":      e.BatchID,
+						"operation":     e.Operation,
+						"task_id":       e.TaskID,
+						"new_task_id":   e.NewTaskID,
+						"title":         e.Title,
+						"project":       e.Project,
+						"metadata_only": e.MetadataOnly(),
+						"created_at":    e.CreatedAt,
+					})
+				}
+				data, _ := json.MarshalIndent(output, "", "  ")
+				fmt.Println(string(data))
+				return
+			}
+
+			if len(entries) == 0 {
+				fmt.Println(dimStyle.Render("Nothing to undo"))
+				return
+			}
+			fmt.Println(dimStyle.Render(fmt.Sprintf("%-12s %-22s %-40s %s", "WHEN", "OPERATION", "TASK", "RECOVERS")))
+			for _, e := range entries {
+				recovers := "everything"
+				if e.MetadataOnly() {
+					recovers = "metadata only"
+				}
+				fmt.Printf("%-12s %-22s %-40s %s\n", timeAgo(e.CreatedAt.Time), describeUndo(e),
+					truncate(fmt.Sprintf("#%d %s", e.TaskID, e.Title), 40), recovers)
+			}
+			fmt.Println(dimStyle.Render("'ty undo' reverts the top entry, with the rest of its bulk operation"))
+		},
+	}
+	listCmd.Flags().Bool("json", false, "Output in JSON format")
+	cmd.AddCommand(listCmd)
+
+	return cmd
+}
+
+// runUndo reverts an undo entry. The database does the bookkeeping; this
+// handles the parts that need the executor: a move's copy loses its session
+// and worktree, and an archived task gets its worktree back from the archive
+// ref before it is unarchived.
+func runUndo(database *db.DB, entry *db.UndoEntry) (*db.UndoResult, error) {
+	exec := executor.New(database, config.New(database))
+
+	switch entry.Operation {
+	case db.UndoMove:
+		if moved, _ := database.GetTask(entry.NewTaskID); entry.NewTaskID != 0 && moved != nil {
+			killSessionAcrossDaemons(int(moved.ID))
+			if moved.WorktreePath != "" {
+				if err := exec.CleanupWorktree(moved); err != nil {
+					fmt.Fprintln(os.Stderr, dimStyle.Render(fmt.Sprintf("Warning: could not remove worktree of #%d: %v", moved.ID, err)))
+				}
+			}
+			exec.NotifyTaskChange("deleted", moved)
+		}
+	case db.UndoArchive:
+		if task, _ := database.GetTask(entry.TaskID); task != nil && task.Status == db.StatusArchived && task.HasArchiveState() {
+			if err := exec.UnarchiveWorktree(task); err != nil {
+				return nil, fmt.Errorf("unarchive worktree: %w", err)
+			}
+		}
+	}
+
+	res, err := database.Undo(entry)
+	if err != nil {
+		return nil, err
+	}
+	if task, _ := database.GetTask(res.TaskID); task != nil {
+		exec.NotifyTaskChange("status_changed", task)
+	}
+	return res, nil
+}
+
+// describeUndo names an undo entry's operation, e.g. "move to #57".
+func describeUndo(e *db.UndoEntry) string {
+	switch e.Operation {
+	case db.UndoDelete:
+		return "delete"
+	case db.UndoHardDelete:
+		return "delete --hard"
+	case db.UndoMove:
+		if e.NewTaskID != 0 {
+			return fmt.Sprintf("move to #%d", e.NewTaskID)
+		}
+		return "move"
+	case db.UndoArchive:
+		return "archive"
+	}
+	return e.Operation
+}
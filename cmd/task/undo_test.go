@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestDescribeUndo(t *testing.T) {
+	for _, tc := range []struct {
+		entry db.UndoEntry
+		want  string
+	}{
+		{db.UndoEntry{Operation: db.UndoDelete}, "delete"},
+		{db.UndoEntry{Operation: db.UndoHardDelete}, "delete --hard"},
+		{db.UndoEntry{Operation: db.UndoMove, NewTaskID: 57}, "move to #57"},
+		{db.UndoEntry{Operation: db.UndoMove}, "move"},
+		{db.UndoEntry{Operation: db.UndoArchive}, "archive"},
+	} {
+		if got := describeUndo(&tc.entry); got != tc.want {
+			t.Errorf("describeUndo(%+v) = %q, want %q", tc.entry, got, tc.want)
+		}
+	}
+}
+
+func TestRunUndoRestoresSoftDeletedTask(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	task := &db.Task{Title: "oops", Status: db.StatusBacklog, Project: "personal"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.SoftDeleteTask(task.ID); err != nil {
+		t.Fatal(err)
+	}
+	entry, err := database.LatestUndo()
+	if err != nil || entry == nil {
+		t.Fatalf("LatestUndo = %v, %v", entry, err)
+	}
+	res, err := runUndo(database, entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TaskID != task.ID || res.MetadataOnly {
+		t.Errorf("result = %+v", res)
+	}
+	trashed, _ := database.ListTrashedTasks()
+	if len(trashed) != 0 {
+		t.Errorf("task still trashed: %+v", trashed)
+	}
+}
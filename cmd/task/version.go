@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/github"
+	"github.com/spf13/cobra"
+)
+
+// versionInfo is what 'ty version --json' reports.
+type versionInfo struct {
+	Version         string `json:"version"`
+	Commit          string `json:"commit,omitempty"`
+	GoVersion       string `json:"go_version"`
+	OS              string `json:"os"`
+	Arch            string `json:"arch"`
+	Database        string `json:"database"`
+	SchemaVersion   *int   `json:"schema_version,omitempty"` // nil if the database doesn't exist yet
+	LatestSchema    int    `json:"latest_schema_version"`
+	LatestVersion   string `json:"latest_version,omitempty"` // --check only
+	UpdateAvailable *bool  `json:"update_available,omitempty"`
+	ReleaseURL      string `json:"release_url,omitempty"`
+}
+
+func newVersionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Show version and build information",
+		Long: `Shows the ty version, the commit it was built from, the Go version and
+platform, and the database in use with its schema version. The database is
+read, not migrated.
+
+--check asks GitHub whether a newer release exists; it never upgrades (see
+'ty upgrade').
+
+Examples:
+  ty version
+  ty version --json
+  ty version --check`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			outputJSON, _ := cmd.Flags().GetBool("json")
+			check, _ := cmd.Flags().GetBool("check")
+
+			info := buildVersionInfo(db.DefaultPath())
+			checkFailed := false
+			if check {
+				if release := github.FetchLatestRelease(); release != nil {
+					newer := github.IsNewerVersion(version, release.Version)
+					info.LatestVersion = release.Version
+					info.UpdateAvailable = &newer
+					info.ReleaseURL = release.URL
+				} else {
+					checkFailed = true
+				}
+			}
+
+			if outputJSON {
+				jsonBytes, _ := json.MarshalIndent(info, "", "  ")
+				fmt.Println(string(jsonBytes))
+				return
+			}
+			printVersionInfo(info)
+			if checkFailed {
+				fmt.Fprintln(os.Stderr, warnStyle.Render("Could not check GitHub for the latest release"))
+			}
+		},
+	}
+	cmd.Flags().Bool("json", false, "Output in JSON format")
+	cmd.Flags().Bool("check", false, "Check GitHub for a newer release (does not upgrade)")
+	return cmd
+}
+
+// buildVersionInfo collects build metadata and the schema version of the
+// database at dbPath.
+func buildVersionInfo(dbPath string) versionInfo {
+	info := versionInfo{
+		Version:      version,
+		Commit:       buildCommit(),
+		GoVersion:    runtime.Version(),
+		OS:           runtime.GOOS,
+		Arch:         runtime.GOARCH,
+		Database:     dbPath,
+		LatestSchema: db.LatestSchemaVersion(),
+	}
+	if v, err := db.ReadSchemaVersion(dbPath); err == nil {
+		info.SchemaVersion = &v
+	}
+	return info
+}
+
+// buildCommit returns the commit injected with -X main.commit, else the one
+// the Go toolchain stamped from the checkout (plain 'go build'), marked
+// "-dirty" when built with uncommitted changes.
+func buildCommit() string {
+	if commit != "" {
+		return commit
+	}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	var revision string
+	dirty := false
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			dirty = s.Value == "true"
+		}
+	}
+	if revision != "" && dirty {
+		revision += "-dirty"
+	}
+	return revision
+}
+
+func printVersionInfo(info versionInfo) {
+	fmt.Printf("%s %s\n", boldStyle.Render("ty"), info.Version)
+	if info.Commit != "" {
+		fmt.Printf("%s %s\n", dimStyle.Render("Commit:  "), info.Commit)
+	}
+	fmt.Printf("%s %s %s/%s\n", dimStyle.Render("Go:      "), info.GoVersion, info.OS, info.Arch)
+	fmt.Printf("%s %s\n", dimStyle.Render("Database:"), info.Database)
+	if info.SchemaVersion != nil {
+		fmt.Printf("%s %d (latest %d)\n", dimStyle.Render("Schema:  "), *info.SchemaVersion, info.LatestSchema)
+	} else {
+		fmt.Printf("%s %s\n", dimStyle.Render("Schema:  "), "no database yet")
+	}
+	if info.UpdateAvailable == nil {
+		return
+	}
+	fmt.Println()
+	if *info.UpdateAvailable {
+		fmt.Println(warnStyle.Render(fmt.Sprintf("Update available: %s → %s  (run: ty upgrade)", info.Version, info.LatestVersion)))
+		if info.ReleaseURL != "" {
+			fmt.Println(dimStyle.Render(info.ReleaseURL))
+		}
+	} else if info.Version == "dev" {
+		fmt.Println(dimStyle.Render(fmt.Sprintf("Development build; latest release is %s", info.LatestVersion)))
+	} else {
+		outln(successStyle.Render(fmt.Sprintf("Up to date (latest release %s)", info.LatestVersion)))
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestBuildVersionInfo(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "tasks.db")
+
+	info := buildVersionInfo(dbPath)
+	if info.Version != version || info.GoVersion != runtime.Version() || info.OS != runtime.GOOS || info.Arch != runtime.GOARCH {
+		t.Errorf("unexpected build info: %+v", info)
+	}
+	if info.SchemaVersion != nil {
+		t.Errorf("no database yet, got schema version %d", *info.SchemaVersion)
+	}
+	data, _ := json.Marshal(info)
+	var out map[string]interface{}
+	json.Unmarshal(data, &out)
+	if _, ok := out["schema_version"]; ok {
+		t.Errorf("schema_version should be omitted without a database: %s", data)
+	}
+	if _, ok := out["update_available"]; ok {
+		t.Errorf("update_available should be omitted without --check: %s", data)
+	}
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	database.Close()
+	info = buildVersionInfo(dbPath)
+	if info.SchemaVersion == nil || *info.SchemaVersion != db.LatestSchemaVersion() {
+		t.Errorf("schema version = %v, want %d", info.SchemaVersion, db.LatestSchemaVersion())
+	}
+	if info.Database != dbPath {
+		t.Errorf("database = %q, want %q", info.Database, dbPath)
+	}
+}
+
+func TestBuildCommitPrefersLdflags(t *testing.T) {
+	old := commit
+	defer func() { commit = old }()
+	commit = "abc1234"
+	if got := buildCommit(); got != "abc1234" {
+		t.Errorf("buildCommit = %q, want the injected commit", got)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// formatTaskLogLine renders a task log line with its timestamp and a colored
+// line-type prefix. Content longer than maxLen is truncated onto one line;
+// maxLen <= 0 prints it as-is.
+func formatTaskLogLine(l *db.TaskLog, maxLen int) string {
+	ts := dimStyle.Render(l.CreatedAt.Time.Format("15:04:05"))
+	prefix := ""
+	switch l.LineType {
+	case "system":
+		prefix = dimStyle.Render("[system] ")
+	case "error":
+		prefix = errorStyle.Render("[error] ")
+	case "tool":
+		prefix = lipgloss.NewStyle().Foreground(lipgloss.Color("#8B5CF6")).Render("[tool] ")
+	case "question":
+		prefix = lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Render("[question] ")
+	case "output":
+		prefix = dimStyle.Render("[output] ")
+	case "text":
+		prefix = dimStyle.Render("[text] ")
+	case db.NoteLineType, db.PinnedNoteLineType:
+		prefix = dimStyle.Render("[note] ")
+	}
+	content := l.Content
+	if maxLen > 0 {
+		content = truncate(content, maxLen)
+	}
+	return fmt.Sprintf("%s %s%s", ts, prefix, content)
+}
+
+func newWatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "watch <task-id>",
+		Short:             "Stream a task's logs live",
+		ValidArgsFunction: completeTaskIDs,
+		Long: `Prints a task's recent logs, then follows new lines as they are written,
+without attaching to its tmux window. Exits when the task is done or archived,
+or on Ctrl+C.
+
+Examples:
+  ty watch 42
+  ty watch 42 --no-follow      # Print the current logs and exit
+  ty watch 42 -n 0             # Start from the very first log line`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			taskID, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Invalid task ID: "+args[0]))
+				os.Exit(1)
+			}
+			noFollow, _ := cmd.Flags().GetBool("no-follow")
+			lines, _ := cmd.Flags().GetInt("lines")
+			interval, _ := cmd.Flags().GetDuration("interval")
+			if interval <= 0 {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: --interval must be positive"))
+				os.Exit(1)
+			}
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+
+			opts := watchOptions{follow: !noFollow, lines: lines, interval: interval}
+			if err := watchTaskLogs(ctx, database, taskID, os.Stdout, opts); err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().Bool("no-follow", false, "Print the current logs and exit")
+	cmd.Flags().IntP("lines", "n", 50, "Number of existing log lines to print first (0 for all)")
+	cmd.Flags().Duration("interval", 500*time.Millisecond, "How often to check for new lines")
+	return cmd
+}
+
+type watchOptions struct {
+	follow   bool
+	lines    int // existing lines to print first; <= 0 for all
+	interval time.Duration
+}
+
+// watchTaskLogs prints a task's logs to w and, when following, polls for new
+// lines by log ID until the task is done or archived or ctx is cancelled.
+// Polling the database (rather than the executor's in-process log channel)
+// works no matter which process is running the task.
+func watchTaskLogs(ctx context.Context, database *db.DB, taskID int64, w io.Writer, opts watchOptions) error {
+	task, err := database.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return fmt.Errorf("task #%d not found", taskID)
+	}
+
+	var logs []*db.TaskLog
+	if opts.lines > 0 {
+		// GetTaskLogs returns the newest first.
+		if logs, err = database.GetTaskLogs(taskID, opts.lines); err != nil {
+			return err
+		}
+		slices.Reverse(logs)
+	} else if logs, err = database.GetTaskLogsSince(taskID, 0); err != nil {
+		return err
+	}
+
+	var cursor int64
+	emit := func(logs []*db.TaskLog) {
+		for _, l := range logs {
+			fmt.Fprintln(w, formatTaskLogLine(l, 0))
+			cursor = l.ID
+		}
+	}
+	emit(logs)
+
+	if !opts.follow {
+		return nil
+	}
+	if taskFinished(task) {
+		fmt.Fprintln(w, dimStyle.Render(fmt.Sprintf("Task #%d is %s", taskID, task.Status)))
+		return nil
+	}
+
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		// Check the status before reading so lines written just before the
+		// task finished are still printed.
+		task, err := database.GetTask(taskID)
+		if err != nil {
+			return err
+		}
+		logs, err := database.GetTaskLogsSince(taskID, cursor)
+		if err != nil {
+			return err
+		}
+		emit(logs)
+
+		if task == nil {
+			fmt.Fprintln(w, dimStyle.Render(fmt.Sprintf("Task #%d was deleted", taskID)))
+			return nil
+		}
+		if taskFinished(task) {
+			fmt.Fprintln(w, dimStyle.Render(fmt.Sprintf("Task #%d is %s", taskID, task.Status)))
+			return nil
+		}
+	}
+}
+
+func taskFinished(task *db.Task) bool {
+	return task.Status == db.StatusDone || task.Status == db.StatusArchived
+}
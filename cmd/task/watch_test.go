@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestWatchTaskLogsNoFollow(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	ids := createTestTasks(t, database, 1)
+	for _, line := range []string{"first", "second", "third"} {
+		if err := database.AppendTaskLog(ids[0], "output", line); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := watchTaskLogs(context.Background(), database, ids[0], &out, watchOptions{lines: 2}); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	if strings.Contains(got, "first") || !strings.Contains(got, "second") || !strings.Contains(got, "third") {
+		t.Errorf("expected the last two lines, got:\n%s", got)
+	}
+	if strings.Index(got, "second") > strings.Index(got, "third") {
+		t.Errorf("expected oldest-first order, got:\n%s", got)
+	}
+}
+
+func TestWatchTaskLogsFollowsUntilDone(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	ids := createTestTasks(t, database, 1)
+	if err := database.AppendTaskLog(ids[0], "output", "before"); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		database.AppendTaskLog(ids[0], "tool", "while running")
+		database.AppendTaskLog(ids[0], "output", "last words")
+		database.UpdateTaskStatus(ids[0], db.StatusDone)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var out bytes.Buffer
+	opts := watchOptions{follow: true, interval: 10 * time.Millisecond}
+	if err := watchTaskLogs(ctx, database, ids[0], &out, opts); err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("watch did not exit when the task finished")
+	}
+
+	got := out.String()
+	for _, want := range []string{"before", "while running", "last words", "is done"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Count(got, "before") != 1 {
+		t.Errorf("expected each line once, got:\n%s", got)
+	}
+}
+
+func TestWatchTaskLogsMissingTask(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	if err := watchTaskLogs(context.Background(), database, 999, &bytes.Buffer{}, watchOptions{}); err == nil {
+		t.Fatal("expected an error for a missing task")
+	}
+}
@@ -133,7 +133,7 @@ func renderWorkflow(database *db.DB, g *pipeline.Group) {
 
 		switch m.Status {
 		case db.StatusDone:
-			fmt.Println(successStyle.Render(line))
+			outln(successStyle.Render(line))
 		case db.StatusProcessing:
 			fmt.Println(boldStyle.Render(line))
 		case db.StatusBlocked:
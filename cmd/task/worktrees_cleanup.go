@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/bborn/workflow/internal/executor"
+)
+
+// cleanupBranchNote describes what `ty worktrees cleanup` did (or, in a dry
+// run, would do) with a task's branch. It's empty unless --delete-branch was
+// given, since keeping the branch is the default.
+func cleanupBranchNote(c *executor.CleanedWorktree, deleteBranch, dryRun bool) string {
+	if !deleteBranch || c.Branch == "" {
+		return ""
+	}
+	if c.BranchDeleted {
+		if dryRun {
+			return dimStyle.Render(" · would delete branch " + c.Branch)
+		}
+		return dimStyle.Render(" · deleted branch " + c.Branch)
+	}
+	return warnStyle.Render(fmt.Sprintf(" · kept branch %s (%s)", c.Branch, c.BranchKept))
+}
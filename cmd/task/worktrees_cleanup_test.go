@@ -0,0 +1,29 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bborn/workflow/internal/executor"
+)
+
+func TestCleanupBranchNote(t *testing.T) {
+	deleted := &executor.CleanedWorktree{Branch: "task/1-fix", BranchDeleted: true}
+	kept := &executor.CleanedWorktree{Branch: "task/2-docs", BranchKept: "open PR #12"}
+
+	if got := cleanupBranchNote(deleted, false, false); got != "" {
+		t.Errorf("without --delete-branch expected no note, got %q", got)
+	}
+	if got := cleanupBranchNote(deleted, true, false); !strings.Contains(got, "deleted branch task/1-fix") {
+		t.Errorf("expected a deleted-branch note, got %q", got)
+	}
+	if got := cleanupBranchNote(deleted, true, true); !strings.Contains(got, "would delete branch task/1-fix") {
+		t.Errorf("expected a dry-run note, got %q", got)
+	}
+	if got := cleanupBranchNote(kept, true, false); !strings.Contains(got, "kept branch task/2-docs (open PR #12)") {
+		t.Errorf("expected a kept-branch note with its reason, got %q", got)
+	}
+	if got := cleanupBranchNote(&executor.CleanedWorktree{}, true, false); got != "" {
+		t.Errorf("expected no note for a task without a branch, got %q", got)
+	}
+}
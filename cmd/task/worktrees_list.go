@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// worktreeEntry is one row of 'ty worktrees list': a task's worktree, or an
+// orphaned directory no task points at.
+type worktreeEntry struct {
+	TaskID      int64      `json:"task_id,omitempty"`
+	Title       string     `json:"title,omitempty"`
+	Status      string     `json:"status,omitempty"`
+	Project     string     `json:"project"`
+	Path        string     `json:"path"`
+	Exists      bool       `json:"exists"`
+	SizeBytes   int64      `json:"size_bytes"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Trashed     bool       `json:"trashed,omitempty"`
+	Orphaned    bool       `json:"orphaned,omitempty"`
+}
+
+func newWorktreesListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List task worktrees with their disk usage",
+		Long: `Lists every task that has a worktree: its status, whether the directory
+still exists, its size on disk, and how long ago the task was completed.
+Use it to pick a --max-age for 'ty worktrees cleanup'.
+
+--orphaned instead lists directories under each project's .task-worktrees/
+that no task points at (for example because the task was deleted), which
+cleanup never touches.
+
+Examples:
+  ty worktrees list
+  ty worktrees list --project myapp --json
+  ty worktrees list --orphaned`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			project, _ := cmd.Flags().GetString("project")
+			orphaned, _ := cmd.Flags().GetBool("orphaned")
+			outputJSON, _ := cmd.Flags().GetBool("json")
+
+			database, err := openTaskDB(db.DefaultPath())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+			defer database.Close()
+
+			if project != "" {
+				p, err := database.GetProjectByName(project)
+				if err != nil || p == nil {
+					fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Error: project %q not found", project)))
+					os.Exit(1)
+				}
+				project = p.Name
+			}
+
+			var entries []worktreeEntry
+			if orphaned {
+				entries, err = orphanedWorktrees(database, project)
+			} else {
+				entries, err = taskWorktrees(database, project)
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
+				os.Exit(1)
+			}
+
+			if outputJSON {
+				if entries == nil {
+					entries = []worktreeEntry{}
+				}
+				jsonBytes, _ := json.Marshal(entries)
+				fmt.Println(string(jsonBytes))
+				return
+			}
+			printWorktreeEntries(entries, orphaned)
+		},
+	}
+	cmd.Flags().StringP("project", "p", "", "Only list worktrees of this project")
+	cmd.Flags().Bool("orphaned", false, "List .task-worktrees directories that no task points at")
+	cmd.Flags().Bool("json", false, "Output in JSON format")
+	cmd.RegisterFlagCompletionFunc("project", completeProjectNames)
+	return cmd
+}
+
+// taskWorktrees returns the worktree of every task (trashed ones included,
+// since their directories stay until the trash is swept), largest first.
+func taskWorktrees(database *db.DB, project string) ([]worktreeEntry, error) {
+	all, err := database.ListTasks(db.ListTasksOptions{Project: project, IncludeClosed: true, IncludeTrashed: true, Limit: 100000})
+	if err != nil {
+		return nil, err
+	}
+	visible, err := database.ListTasks(db.ListTasksOptions{Project: project, IncludeClosed: true, Limit: 100000})
+	if err != nil {
+		return nil, err
+	}
+	notTrashed := make(map[int64]bool, len(visible))
+	for _, t := range visible {
+		notTrashed[t.ID] = true
+	}
+
+	var entries []worktreeEntry
+	for _, t := range all {
+		if t.WorktreePath == "" {
+			continue
+		}
+		e := worktreeEntry{
+			TaskID:  t.ID,
+			Title:   t.Title,
+			Status:  t.Status,
+			Project: t.Project,
+			Path:    t.WorktreePath,
+			Trashed: !notTrashed[t.ID],
+		}
+		if t.CompletedAt != nil {
+			completed := t.CompletedAt.Time
+			e.CompletedAt = &completed
+		}
+		e.Exists, e.SizeBytes = dirSize(t.WorktreePath)
+		entries = append(entries, e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].SizeBytes > entries[j].SizeBytes })
+	return entries, nil
+}
+
+// orphanedWorktrees returns directories in the projects' .task-worktrees/
+// that no task, trashed or not, has as its current or archived worktree.
+func orphanedWorktrees(database *db.DB, project string) ([]worktreeEntry, error) {
+	// Every project's tasks count, since two projects can share a path.
+	tasks, err := database.ListTasks(db.ListTasksOptions{IncludeClosed: true, IncludeTrashed: true, Limit: 100000})
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := database.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	var entries []worktreeEntry
+	seenDirs := make(map[string]bool)
+	for _, p := range projects {
+		if (project != "" && p.Name != project) || p.Path == "" {
+			continue
+		}
+		dir := filepath.Join(p.Path, ".task-worktrees")
+		if seenDirs[dir] { // two projects can share a path
+			continue
+		}
+		seenDirs[dir] = true
+
+		for _, path := range findOrphanedWorktrees(dir, tasks) {
+			e := worktreeEntry{Project: p.Name, Path: path, Orphaned: true}
+			e.Exists, e.SizeBytes = dirSize(path)
+			entries = append(entries, e)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].SizeBytes > entries[j].SizeBytes })
+	return entries, nil
+}
+
+// dirSize reports whether path is an existing directory and the total size
+// of the regular files under it. Symlinks are not followed.
+func dirSize(path string) (exists bool, size int64) {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return false, 0
+	}
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // unreadable entries are skipped, not fatal
+		}
+		if d.Type().IsRegular() {
+			if fi, err := d.Info(); err == nil {
+				size += fi.Size()
+			}
+		}
+		return nil
+	})
+	return true, size
+}
+
+func printWorktreeEntries(entries []worktreeEntry, orphaned bool) {
+	if len(entries) == 0 {
+		if orphaned {
+			fmt.Println(dimStyle.Render("No orphaned worktrees found"))
+		} else {
+			fmt.Println(dimStyle.Render("No task worktrees found"))
+		}
+		return
+	}
+
+	var total int64
+	for _, e := range entries {
+		total += e.SizeBytes
+		size := fmt.Sprintf("%9s", formatAttachmentSize(e.SizeBytes))
+		if !e.Exists {
+			size = fmt.Sprintf("%9s", "missing")
+		}
+		if e.Orphaned {
+			fmt.Printf("%s %s %s\n", size, dimStyle.Render(fmt.Sprintf("[%s]", e.Project)), e.Path)
+			continue
+		}
+		age := "active"
+		if e.CompletedAt != nil {
+			age = timeAgo(*e.CompletedAt) + " ago"
+		}
+		status := e.Status
+		if e.Trashed {
+			status = "trashed"
+		}
+		fmt.Printf("%s %s %-10s %-12s %s\n", size, dimStyle.Render(fmt.Sprintf("#%-4d", e.TaskID)), status, age, truncate(e.Title, 50))
+		fmt.Printf("%s %s\n", dimStyle.Render("          "), dimStyle.Render(e.Path))
+	}
+	fmt.Println(dimStyle.Render(fmt.Sprintf("%d worktree(s), %s total", len(entries), formatAttachmentSize(total))))
+}
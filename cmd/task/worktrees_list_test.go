@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestWorktreesListAndOrphans(t *testing.T) {
+	database, cleanup := setupBulkTestDB(t)
+	defer cleanup()
+
+	projectDir := t.TempDir()
+	if err := database.CreateProject(&db.Project{Name: "app", Path: projectDir}); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	wtDir := filepath.Join(projectDir, ".task-worktrees")
+
+	mkWorktree := func(name string, size int) string {
+		dir := filepath.Join(wtDir, name)
+		if err := os.MkdirAll(filepath.Join(dir, "sub"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "sub", "file"), make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	withWorktree := func(title, path string) *db.Task {
+		task := &db.Task{Title: title, Project: "app", Status: db.StatusDone}
+		if err := database.CreateTask(task); err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+		task.WorktreePath = path
+		if err := database.UpdateTask(task); err != nil {
+			t.Fatalf("update task: %v", err)
+		}
+		return task
+	}
+
+	small := withWorktree("small", mkWorktree("1-small", 10))
+	big := withWorktree("big", mkWorktree("2-big", 1000))
+	gone := withWorktree("gone", filepath.Join(wtDir, "3-gone"))
+	trashed := withWorktree("trashed", mkWorktree("4-trashed", 5))
+	if err := database.SoftDeleteTask(trashed.ID); err != nil {
+		t.Fatal(err)
+	}
+	orphan := mkWorktree("9-orphan", 42)
+	archived := &db.Task{Title: "archived", Project: "app", Status: db.StatusArchived}
+	if err := database.CreateTask(archived); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	if err := database.SaveArchiveState(archived.ID, "refs/archive/5", "abc123", mkWorktree("5-archived", 7), "task/5"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(wtDir, "sessions"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := taskWorktrees(database, "app")
+	if err != nil {
+		t.Fatalf("taskWorktrees: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("got %d entries, want 4: %+v", len(entries), entries)
+	}
+	if entries[0].TaskID != big.ID || entries[0].SizeBytes != 1000 || !entries[0].Exists {
+		t.Errorf("largest entry = %+v, want task %d with 1000 bytes", entries[0], big.ID)
+	}
+	byID := make(map[int64]worktreeEntry)
+	for _, e := range entries {
+		byID[e.TaskID] = e
+	}
+	if e := byID[small.ID]; e.SizeBytes != 10 || e.Trashed {
+		t.Errorf("small = %+v", e)
+	}
+	if e := byID[gone.ID]; e.Exists || e.SizeBytes != 0 {
+		t.Errorf("gone = %+v, want missing", e)
+	}
+	if e := byID[trashed.ID]; !e.Trashed {
+		t.Errorf("trashed = %+v, want Trashed", e)
+	}
+
+	if entries, _ := taskWorktrees(database, "other"); len(entries) != 0 {
+		t.Errorf("project filter: got %d entries, want 0", len(entries))
+	}
+
+	orphans, err := orphanedWorktrees(database, "")
+	if err != nil {
+		t.Fatalf("orphanedWorktrees: %v", err)
+	}
+	if len(orphans) != 1 || orphans[0].Path != orphan || orphans[0].SizeBytes != 42 || orphans[0].Project != "app" {
+		t.Errorf("orphans = %+v, want only %s", orphans, orphan)
+	}
+}
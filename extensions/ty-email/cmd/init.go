@@ -94,8 +94,8 @@ func runInit(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// === Claude API ===
-	fmt.Println(titleStyle.Render("\n🤖 Claude API"))
+	// === LLM Classifier ===
+	fmt.Println(titleStyle.Render("\n🤖 LLM Classifier"))
 
 	if err := configureLLM(cfg); err != nil {
 		return err
@@ -410,11 +410,46 @@ func configureSMTP(cfg *Config) error {
 }
 
 func configureLLM(cfg *Config) error {
-	cfg.Classifier.Provider = "claude"
+	provider := cfg.Classifier.Provider
+	if provider == "" {
+		provider = "claude"
+	}
+
+	err := huh.NewSelect[string]().
+		Title("Which LLM should classify emails?").
+		Options(
+			huh.NewOption("Claude (Anthropic)", "claude"),
+			huh.NewOption("OpenAI (or OpenAI-compatible endpoint)", "openai"),
+			huh.NewOption("Ollama (local)", "ollama"),
+		).
+		Value(&provider).
+		Run()
+	if err != nil {
+		return err
+	}
 
+	// Don't carry another provider's model over as the default.
+	if provider != cfg.Classifier.Provider {
+		cfg.Classifier.Model = ""
+		cfg.Classifier.BaseURL = ""
+	}
+	cfg.Classifier.Provider = provider
+
+	switch provider {
+	case "openai":
+		return configureAPIKeyLLM(cfg, "gpt-4o-mini", "OpenAI", "OPENAI_API_KEY", "op read 'op://Private/OpenAI/api_key'")
+	case "ollama":
+		return configureOllama(cfg)
+	default:
+		return configureAPIKeyLLM(cfg, "claude-sonnet-4-20250514", "Anthropic", "ANTHROPIC_API_KEY", "op read 'op://Private/Anthropic/api_key'")
+	}
+}
+
+// configureAPIKeyLLM prompts for the model and API key of a hosted provider.
+func configureAPIKeyLLM(cfg *Config, defaultModel, vendor, envVar, placeholder string) error {
 	model := cfg.Classifier.Model
 	if model == "" {
-		model = "claude-sonnet-4-20250514"
+		model = defaultModel
 	}
 
 	var apiKeyMethod string
@@ -422,7 +457,7 @@ func configureLLM(cfg *Config) error {
 		Title("API Key Retrieval").
 		Options(
 			huh.NewOption("Command (e.g., op read, pass, etc.)", "command"),
-			huh.NewOption("Environment variable (ANTHROPIC_API_KEY)", "env"),
+			huh.NewOption(fmt.Sprintf("Environment variable (%s)", envVar), "env"),
 		).
 		Value(&apiKeyMethod).
 		Run()
@@ -442,8 +477,8 @@ func configureLLM(cfg *Config) error {
 
 				huh.NewInput().
 					Title("API Key Command").
-					Description("Command that outputs your Anthropic API key").
-					Placeholder("op read 'op://Private/Anthropic/api_key'").
+					Description(fmt.Sprintf("Command that outputs your %s API key", vendor)).
+					Placeholder(placeholder).
 					Value(&apiKeyCmd).
 					Validate(func(s string) error {
 						if s == "" {
@@ -472,13 +507,13 @@ func configureLLM(cfg *Config) error {
 
 	} else {
 		// Environment variable
-		if os.Getenv("ANTHROPIC_API_KEY") == "" {
-			fmt.Println(errorStyle.Render("✗ ANTHROPIC_API_KEY is not set"))
-			fmt.Println(infoStyle.Render("  Set it and re-run init: export ANTHROPIC_API_KEY=sk-ant-..."))
-			return fmt.Errorf("ANTHROPIC_API_KEY environment variable is not set")
+		if os.Getenv(envVar) == "" {
+			fmt.Println(errorStyle.Render(fmt.Sprintf("✗ %s is not set", envVar)))
+			fmt.Println(infoStyle.Render(fmt.Sprintf("  Set it and re-run init: export %s=...", envVar)))
+			return fmt.Errorf("%s environment variable is not set", envVar)
 		}
-		fmt.Println(successStyle.Render("✓ ANTHROPIC_API_KEY is set"))
-		apiKeyCmd = "echo $ANTHROPIC_API_KEY" //nolint:gosec // shell command to read the key, not a credential
+		fmt.Println(successStyle.Render(fmt.Sprintf("✓ %s is set", envVar)))
+		apiKeyCmd = "echo $" + envVar //nolint:gosec // shell command to read the key, not a credential
 
 		form := huh.NewForm(
 			huh.NewGroup(
@@ -499,6 +534,42 @@ func configureLLM(cfg *Config) error {
 	return nil
 }
 
+// configureOllama prompts for a local Ollama server and model. No API key is needed.
+func configureOllama(cfg *Config) error {
+	model := cfg.Classifier.Model
+	if model == "" {
+		model = "llama3.2"
+	}
+	baseURL := cfg.Classifier.BaseURL
+	if baseURL == "" {
+		baseURL = "http://localhost:11434"
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Ollama URL").
+				Value(&baseURL),
+
+			huh.NewInput().
+				Title("Model").
+				Description("Must already be pulled (ollama pull <model>)").
+				Value(&model),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		return err
+	}
+
+	cfg.Classifier.Model = model
+	cfg.Classifier.BaseURL = baseURL
+	cfg.Classifier.APIKeyCmd = ""
+	cfg.Classifier.APIKey = ""
+
+	return nil
+}
+
 func configureTaskYou(cfg *Config) error {
 	tyPath := cfg.TaskYou.CLI
 	if tyPath == "" {
@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
@@ -36,7 +37,9 @@ type Config struct {
 		AutoExecute    bool   `yaml:"auto_execute"` // Queue email-created tasks for execution immediately
 	} `yaml:"routing"`
 	Security struct {
-		AllowedSenders  []string `yaml:"allowed_senders"`    // Only process emails from these addresses (exact match)
+		AllowedSenders  []string `yaml:"allowed_senders"`    // Only process emails from these addresses (exact match, or wildcards like *@example.com)
+		AllowedDomains  []string `yaml:"allowed_domains"`    // Also process emails from any address at these domains
+		BounceRejected  bool     `yaml:"bounce_rejected"`    // Reply to rejected senders instead of ignoring them
 		Notify          string   `yaml:"notify"`             // Address for blocked-task notifications (default: first allowed sender)
 		MaxTasksPerHour int      `yaml:"max_tasks_per_hour"` // Rate limit (default 20, -1 to disable)
 	} `yaml:"security"`
@@ -57,6 +60,8 @@ func processorConfig(cfg *Config) *processor.Config {
 	}
 	return &processor.Config{
 		AllowedSenders:  cfg.Security.AllowedSenders,
+		AllowedDomains:  cfg.Security.AllowedDomains,
+		BounceRejected:  cfg.Security.BounceRejected,
 		Dangerous:       cfg.TaskYou.Dangerous,
 		DefaultProject:  cfg.Routing.DefaultProject,
 		AutoExecute:     cfg.Routing.AutoExecute,
@@ -69,8 +74,10 @@ func notifyAddress(cfg *Config) string {
 	if cfg.Security.Notify != "" {
 		return cfg.Security.Notify
 	}
-	if len(cfg.Security.AllowedSenders) > 0 {
-		return cfg.Security.AllowedSenders[0]
+	for _, sender := range cfg.Security.AllowedSenders {
+		if !strings.ContainsAny(sender, "*?[") {
+			return sender
+		}
 	}
 	return ""
 }
@@ -532,7 +539,10 @@ func setupClassifier(cfg *Config) (classifier.Classifier, error) {
 	switch cfg.Classifier.Provider {
 	case "claude", "":
 		return classifier.NewClaudeClassifier(&cfg.Classifier)
-	// TODO: Add openai, ollama classifiers
+	case "openai":
+		return classifier.NewOpenAIClassifier(&cfg.Classifier)
+	case "ollama":
+		return classifier.NewOllamaClassifier(&cfg.Classifier)
 	default:
 		return nil, fmt.Errorf("unsupported classifier: %s", cfg.Classifier.Provider)
 	}
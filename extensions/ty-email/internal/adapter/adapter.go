@@ -3,6 +3,8 @@ package adapter
 
 import (
 	"context"
+	"fmt"
+	"mime"
 	"strings"
 	"time"
 )
@@ -31,6 +33,48 @@ type Attachment struct {
 	Data        []byte
 }
 
+// Attachment limits applied by the adapters when parsing inbound mail.
+const (
+	// MaxAttachmentSize caps each attachment kept from an inbound email.
+	// It matches ty's default max_attachment_size.
+	MaxAttachmentSize = 10 << 20
+
+	// MinInlineImageSize is the smallest inline image kept as an attachment.
+	// Smaller inline images are usually signature logos or tracking pixels.
+	MinInlineImageSize = 20 << 10
+)
+
+// keepAttachment reports whether a MIME part should become an attachment.
+// inline is true for parts not explicitly marked as attachments.
+func keepAttachment(contentType string, size int, inline bool) bool {
+	if size <= 0 || size > MaxAttachmentSize {
+		return false
+	}
+	if inline && strings.HasPrefix(strings.ToLower(contentType), "image/") && size < MinInlineImageSize {
+		return false
+	}
+	return true
+}
+
+// isInlineDisposition reports whether a Content-Disposition header value
+// marks a part as inline (anything other than an explicit attachment).
+func isInlineDisposition(disposition string) bool {
+	return !strings.HasPrefix(strings.ToLower(strings.TrimSpace(disposition)), "attachment")
+}
+
+// attachmentFilename returns filename, or a generated name for unnamed parts
+// (typically inline images) based on the content type.
+func attachmentFilename(filename, contentType string, n int) string {
+	if filename != "" {
+		return filename
+	}
+	ext := ".bin"
+	if exts, _ := mime.ExtensionsByType(contentType); len(exts) > 0 {
+		ext = exts[0]
+	}
+	return fmt.Sprintf("inline-%d%s", n, ext)
+}
+
 // OutboundEmail represents an email to send.
 type OutboundEmail struct {
 	To        []string
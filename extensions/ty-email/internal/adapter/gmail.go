@@ -313,7 +313,7 @@ func (a *GmailAdapter) fetchMessage(ctx context.Context, id string) (*Email, err
 	}
 
 	// Parse attachments
-	email.Attachments = a.extractAttachments(msg.Payload)
+	email.Attachments = a.extractAttachments(ctx, id, msg.Payload, nil)
 
 	return email, nil
 }
@@ -335,24 +335,64 @@ func (a *GmailAdapter) extractBody(payload *gmail.MessagePart, mimeType string)
 	return ""
 }
 
-func (a *GmailAdapter) extractAttachments(payload *gmail.MessagePart) []Attachment {
-	var attachments []Attachment
-
-	if payload.Filename != "" && payload.Body != nil && payload.Body.AttachmentId != "" {
-		// Would need to fetch attachment data separately
-		attachments = append(attachments, Attachment{
-			Filename:    payload.Filename,
-			ContentType: payload.MimeType,
-		})
+// extractAttachments walks the MIME tree and downloads attachment data.
+// Parts over the size cap and small inline images are skipped before fetching.
+func (a *GmailAdapter) extractAttachments(ctx context.Context, msgID string, payload *gmail.MessagePart, attachments []Attachment) []Attachment {
+	if att, ok := a.fetchAttachment(ctx, msgID, payload, len(attachments)+1); ok {
+		attachments = append(attachments, att)
 	}
 
 	for _, part := range payload.Parts {
-		attachments = append(attachments, a.extractAttachments(part)...)
+		attachments = a.extractAttachments(ctx, msgID, part, attachments)
 	}
 
 	return attachments
 }
 
+func (a *GmailAdapter) fetchAttachment(ctx context.Context, msgID string, part *gmail.MessagePart, n int) (Attachment, bool) {
+	if part.Body == nil || (part.Body.AttachmentId == "" && part.Body.Data == "") {
+		return Attachment{}, false
+	}
+	// Unnamed parts are body text unless they're inline images.
+	if part.Filename == "" && !strings.HasPrefix(part.MimeType, "image/") {
+		return Attachment{}, false
+	}
+
+	disposition := ""
+	for _, header := range part.Headers {
+		if strings.EqualFold(header.Name, "Content-Disposition") {
+			disposition = header.Value
+			break
+		}
+	}
+	if !keepAttachment(part.MimeType, int(part.Body.Size), isInlineDisposition(disposition)) {
+		a.logger.Debug("skipping attachment", "filename", part.Filename, "content_type", part.MimeType, "size", part.Body.Size)
+		return Attachment{}, false
+	}
+
+	encoded := part.Body.Data
+	if part.Body.AttachmentId != "" {
+		body, err := a.service.Users.Messages.Attachments.Get("me", msgID, part.Body.AttachmentId).Context(ctx).Do()
+		if err != nil {
+			a.logger.Warn("failed to fetch attachment", "filename", part.Filename, "error", err)
+			return Attachment{}, false
+		}
+		encoded = body.Data
+	}
+
+	data, err := base64.URLEncoding.DecodeString(encoded)
+	if err != nil {
+		a.logger.Warn("failed to decode attachment", "filename", part.Filename, "error", err)
+		return Attachment{}, false
+	}
+
+	return Attachment{
+		Filename:    attachmentFilename(part.Filename, part.MimeType, n),
+		ContentType: part.MimeType,
+		Data:        data,
+	}, true
+}
+
 func (a *GmailAdapter) Stop() error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
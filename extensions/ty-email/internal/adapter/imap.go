@@ -17,6 +17,16 @@ import (
 	"github.com/emersion/go-message/mail"
 )
 
+const (
+	// idleRestartInterval re-issues IDLE before servers drop it. RFC 2177
+	// lets servers end IDLE after 29 minutes of inactivity.
+	idleRestartInterval = 25 * time.Minute
+
+	// Reconnect backoff bounds after a connection or IDLE failure.
+	minReconnectBackoff = 5 * time.Second
+	maxReconnectBackoff = 5 * time.Minute
+)
+
 // IMAPAdapter connects to an IMAP server to receive emails.
 type IMAPAdapter struct {
 	config   *IMAPConfig
@@ -28,6 +38,14 @@ type IMAPAdapter struct {
 	client  *imapclient.Client
 	stopCh  chan struct{}
 	stopped bool
+
+	// IDLE state. newMailCh is signaled when the server pushes EXISTS;
+	// idleHolds counts commands waiting for IDLE to get out of the way.
+	newMailCh chan struct{}
+	resumeCh  chan struct{}
+	idleCmd   *imapclient.IdleCommand
+	idleDone  chan struct{}
+	idleHolds int
 }
 
 // SMTPConfig holds SMTP configuration for sending.
@@ -44,11 +62,13 @@ func NewIMAPAdapter(cfg *IMAPConfig, smtp *SMTPConfig, logger *slog.Logger) *IMA
 		logger = slog.Default()
 	}
 	return &IMAPAdapter{
-		config:   cfg,
-		smtp:     smtp,
-		logger:   logger,
-		emailsCh: make(chan *Email, 100),
-		stopCh:   make(chan struct{}),
+		config:    cfg,
+		smtp:      smtp,
+		logger:    logger,
+		emailsCh:  make(chan *Email, 100),
+		stopCh:    make(chan struct{}),
+		newMailCh: make(chan struct{}, 1),
+		resumeCh:  make(chan struct{}, 1),
 	}
 }
 
@@ -74,8 +94,18 @@ func (a *IMAPAdapter) connect() error {
 		password = strings.TrimSpace(string(out))
 	}
 
-	// Connect
-	client, err := imapclient.DialTLS(a.config.Server, nil)
+	// Connect. The unilateral handler runs on the client's reader goroutine,
+	// so it only signals the watch loop and never issues commands itself.
+	options := &imapclient.Options{
+		UnilateralDataHandler: &imapclient.UnilateralDataHandler{
+			Mailbox: func(data *imapclient.UnilateralDataMailbox) {
+				if data.NumMessages != nil {
+					signal(a.newMailCh)
+				}
+			},
+		},
+	}
+	client, err := imapclient.DialTLS(a.config.Server, options)
 	if err != nil {
 		return fmt.Errorf("failed to connect to IMAP server: %w", err)
 	}
@@ -96,7 +126,7 @@ func (a *IMAPAdapter) Start(ctx context.Context) error {
 		return err
 	}
 
-	// Parse poll interval
+	// Parse poll interval. With IDLE it is only the fallback cadence.
 	pollInterval := 30 * time.Second
 	if a.config.PollInterval != "" {
 		d, err := time.ParseDuration(a.config.PollInterval)
@@ -105,28 +135,165 @@ func (a *IMAPAdapter) Start(ctx context.Context) error {
 		}
 	}
 
-	// Start poll loop
-	go a.pollLoop(ctx, pollInterval)
+	go a.watchLoop(ctx, pollInterval)
 
 	return nil
 }
 
-func (a *IMAPAdapter) pollLoop(ctx context.Context, interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
-
-	// Initial poll
-	a.poll(ctx)
+// watchLoop fetches unseen mail, then waits for more. Servers that support
+// IDLE push new mail immediately; otherwise it falls back to polling every
+// interval. Connection failures are retried with exponential backoff.
+func (a *IMAPAdapter) watchLoop(ctx context.Context, interval time.Duration) {
+	backoff := minReconnectBackoff
 
 	for {
+		a.poll(ctx)
+
+		var wait time.Duration
+		client := a.currentClient()
+		switch {
+		case client == nil:
+			// Reconnect failed; back off before the next attempt.
+			wait = backoff
+			backoff = min(backoff*2, maxReconnectBackoff)
+		case client.Caps().Has(imap.CapIdle):
+			if err := a.idle(ctx, client, interval); err != nil {
+				a.logger.Warn("IMAP IDLE failed, reconnecting", "error", err, "backoff", backoff)
+				a.resetConnection()
+				wait = backoff
+				backoff = min(backoff*2, maxReconnectBackoff)
+			} else {
+				backoff = minReconnectBackoff
+			}
+		default:
+			wait = interval
+			backoff = minReconnectBackoff
+		}
+
+		if !a.sleep(ctx, wait) {
+			return
+		}
+	}
+}
+
+// idle runs a single IDLE command until new mail arrives, the fallback poll
+// interval elapses, IDLE needs re-issuing, or another command needs the
+// connection. A nil error means the caller should poll and idle again.
+func (a *IMAPAdapter) idle(ctx context.Context, client *imapclient.Client, interval time.Duration) error {
+	a.mu.Lock()
+	if a.idleHolds > 0 {
+		a.mu.Unlock()
+		// Another command is using the connection; wait for it to finish.
 		select {
 		case <-ctx.Done():
-			return
 		case <-a.stopCh:
-			return
-		case <-ticker.C:
-			a.poll(ctx)
+		case <-a.resumeCh:
 		}
+		return nil
+	}
+	idleCmd, err := client.Idle()
+	if err != nil {
+		a.mu.Unlock()
+		return fmt.Errorf("failed to start IDLE: %w", err)
+	}
+	var idleErr error
+	done := make(chan struct{})
+	go func() {
+		idleErr = idleCmd.Wait()
+		close(done)
+	}()
+	a.idleCmd = idleCmd
+	a.idleDone = done
+	a.mu.Unlock()
+
+	timeout := min(interval, idleRestartInterval)
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-a.stopCh:
+	case <-a.newMailCh:
+	case <-timer.C:
+	case <-done:
+	}
+
+	a.stopIdle()
+	<-done
+	return idleErr
+}
+
+// stopIdle ends the running IDLE command, if any, and waits for the server
+// to acknowledge it.
+func (a *IMAPAdapter) stopIdle() {
+	a.mu.Lock()
+	idleCmd, done := a.idleCmd, a.idleDone
+	a.idleCmd, a.idleDone = nil, nil
+	a.mu.Unlock()
+
+	if idleCmd == nil {
+		return
+	}
+	if err := idleCmd.Close(); err != nil {
+		a.logger.Debug("failed to stop IDLE", "error", err)
+	}
+	<-done
+}
+
+// pauseIdle interrupts IDLE so another command can use the connection.
+// The returned function lets the watch loop resume idling.
+func (a *IMAPAdapter) pauseIdle() func() {
+	a.mu.Lock()
+	a.idleHolds++
+	a.mu.Unlock()
+
+	a.stopIdle()
+
+	return func() {
+		a.mu.Lock()
+		a.idleHolds--
+		a.mu.Unlock()
+		signal(a.resumeCh)
+	}
+}
+
+// sleep waits for d, returning false if the adapter is stopping.
+func (a *IMAPAdapter) sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-a.stopCh:
+			return false
+		default:
+			return true
+		}
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-a.stopCh:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (a *IMAPAdapter) currentClient() *imapclient.Client {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.client
+}
+
+// signal does a non-blocking send on a buffered wake-up channel.
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
 	}
 }
 
@@ -262,25 +429,25 @@ func (a *IMAPAdapter) parseMessage(msg *imapclient.FetchMessageBuffer) (*Email,
 
 			switch h := part.Header.(type) {
 			case *mail.InlineHeader:
-				ct, _, _ := h.ContentType()
-				body, _ := io.ReadAll(part.Body)
+				ct, params, _ := h.ContentType()
+				body, _ := io.ReadAll(io.LimitReader(part.Body, MaxAttachmentSize+1))
 
 				if strings.HasPrefix(ct, "text/plain") {
 					email.Body = string(body)
 				} else if strings.HasPrefix(ct, "text/html") {
 					email.HTML = string(body)
+				} else if strings.HasPrefix(ct, "image/") {
+					// Screenshots pasted into the message body arrive as
+					// inline images rather than attachments.
+					a.addAttachment(email, params["name"], ct, body, true)
 				}
 
 			case *mail.AttachmentHeader:
 				filename, _ := h.Filename()
 				ct, _, _ := h.ContentType()
-				data, _ := io.ReadAll(part.Body)
+				data, _ := io.ReadAll(io.LimitReader(part.Body, MaxAttachmentSize+1))
 
-				email.Attachments = append(email.Attachments, Attachment{
-					Filename:    filename,
-					ContentType: ct,
-					Data:        data,
-				})
+				a.addAttachment(email, filename, ct, data, isInlineDisposition(h.Get("Content-Disposition")))
 			}
 		}
 	}
@@ -294,6 +461,20 @@ func (a *IMAPAdapter) parseMessage(msg *imapclient.FetchMessageBuffer) (*Email,
 	return email, nil
 }
 
+// addAttachment appends a parsed MIME part to email.Attachments unless it is
+// over the size cap or a small inline image.
+func (a *IMAPAdapter) addAttachment(email *Email, filename, contentType string, data []byte, inline bool) {
+	if !keepAttachment(contentType, len(data), inline) {
+		a.logger.Debug("skipping attachment", "filename", filename, "content_type", contentType, "size", len(data))
+		return
+	}
+	email.Attachments = append(email.Attachments, Attachment{
+		Filename:    attachmentFilename(filename, contentType, len(email.Attachments)+1),
+		ContentType: contentType,
+		Data:        data,
+	})
+}
+
 // resetConnection drops the current IMAP connection so the next poll
 // reconnects. Called when a command fails (dead/stale connection).
 func (a *IMAPAdapter) resetConnection() {
@@ -390,14 +571,15 @@ func (a *IMAPAdapter) Send(ctx context.Context, email *OutboundEmail) error {
 }
 
 func (a *IMAPAdapter) MarkProcessed(ctx context.Context, emailID string) error {
-	a.mu.Lock()
-	client := a.client
-	a.mu.Unlock()
-
+	client := a.currentClient()
 	if client == nil {
 		return fmt.Errorf("not connected")
 	}
 
+	// Commands can't be sent while IDLE is running.
+	resume := a.pauseIdle()
+	defer resume()
+
 	folder := a.config.Folder
 	if folder == "" {
 		folder = "INBOX"
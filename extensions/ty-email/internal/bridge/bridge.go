@@ -4,10 +4,13 @@ package bridge
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 
+	"github.com/bborn/workflow/extensions/ty-email/internal/adapter"
 	"github.com/bborn/workflow/extensions/ty-email/internal/classifier"
 )
 
@@ -128,6 +131,43 @@ func (b *Bridge) ExecuteTask(taskID int64) error {
 	return err
 }
 
+// AttachFiles attaches email attachments to a task via `ty attach`.
+// Files are staged in a temporary directory that is removed afterwards.
+func (b *Bridge) AttachFiles(taskID int64, attachments []adapter.Attachment) error {
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	dir, err := os.MkdirTemp("", "ty-email-attachments-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	args := []string{"attach", strconv.FormatInt(taskID, 10)}
+	seen := make(map[string]bool)
+	for i, att := range attachments {
+		// Sender-controlled names: never let them escape the temp dir.
+		name := filepath.Base(att.Filename)
+		if name == "." || name == "/" || name == "" {
+			name = fmt.Sprintf("attachment-%d", i+1)
+		}
+		if seen[name] {
+			name = fmt.Sprintf("%d-%s", i+1, name)
+		}
+		seen[name] = true
+
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, att.Data, 0600); err != nil {
+			return fmt.Errorf("failed to stage attachment %s: %w", name, err)
+		}
+		args = append(args, path)
+	}
+
+	_, err = b.run(args...)
+	return err
+}
+
 // GetBlockedTasks returns tasks that are waiting for input.
 func (b *Bridge) GetBlockedTasks() ([]Task, error) {
 	return b.ListTasks("blocked")
@@ -3,6 +3,10 @@ package classifier
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
 
 	"github.com/bborn/workflow/extensions/ty-email/internal/adapter"
 )
@@ -78,3 +82,145 @@ type Config struct {
 	APIKey    string `yaml:"api_key"`     // Direct API key (less secure)
 	BaseURL   string `yaml:"base_url"`    // For Ollama or custom endpoints
 }
+
+// maxTaskContext caps how many tasks are included in the prompt to avoid
+// excessive input tokens.
+const maxTaskContext = 10
+
+func limitTasks(tasks []Task) []Task {
+	if len(tasks) > maxTaskContext {
+		return tasks[:maxTaskContext]
+	}
+	return tasks
+}
+
+// resolveAPIKey returns the configured API key, running APIKeyCmd if no
+// key is set directly.
+func resolveAPIKey(cfg *Config) (string, error) {
+	apiKey := cfg.APIKey
+	if apiKey == "" && cfg.APIKeyCmd != "" {
+		out, err := exec.Command("sh", "-c", cfg.APIKeyCmd).Output()
+		if err != nil {
+			return "", fmt.Errorf("failed to get API key: %w", err)
+		}
+		apiKey = strings.TrimSpace(string(out))
+	}
+
+	if apiKey == "" {
+		return "", fmt.Errorf("no API key configured")
+	}
+	return apiKey, nil
+}
+
+// buildPrompt renders the classification prompt shared by all providers.
+func buildPrompt(email *adapter.Email, tasks []Task, threadTaskID *int64) string {
+	var sb strings.Builder
+
+	sb.WriteString(`You are an email classifier for TaskYou, a task management system.
+
+Your job is to understand the intent of incoming emails and translate them to TaskYou actions.
+
+Available actions:
+- "create": Create a new task
+- "input": Provide input to a task that's waiting (status=blocked)
+- "execute": Queue a task for execution
+- "query": User is asking about task status
+- "ignore": Email is spam, irrelevant, or doesn't need action
+
+`)
+
+	// Add current tasks context (only title/status/project to minimize tokens)
+	if len(tasks) > 0 {
+		sb.WriteString("Current tasks:\n")
+		for _, t := range tasks {
+			line := fmt.Sprintf("- #%d: %s (status: %s", t.ID, t.Title, t.Status)
+			if t.Project != "" {
+				line += ", project: " + t.Project
+			}
+			line += ")\n"
+			sb.WriteString(line)
+		}
+		sb.WriteString("\n")
+	} else {
+		sb.WriteString("No current tasks.\n\n")
+	}
+
+	// Add thread context if this is a reply
+	if threadTaskID != nil {
+		sb.WriteString(fmt.Sprintf("This email is part of a thread related to task #%d.\n\n", *threadTaskID))
+	}
+
+	// Add the email (truncate body to limit token usage)
+	sb.WriteString("Incoming email:\n")
+	sb.WriteString(fmt.Sprintf("From: %s\n", email.From))
+	sb.WriteString(fmt.Sprintf("Subject: %s\n", email.Subject))
+	body := email.Body
+	const maxBodyLen = 2000
+	if len(body) > maxBodyLen {
+		body = body[:maxBodyLen] + "\n[truncated]"
+	}
+	sb.WriteString(fmt.Sprintf("Body:\n%s\n\n", body))
+
+	// Instructions
+	sb.WriteString(`Analyze this email and respond with a JSON object:
+
+{
+  "type": "create|input|execute|query|ignore",
+  "title": "task title",           // for create
+  "body": "task description",      // for create
+  "project": "project name",       // for create (optional)
+  "task_type": "code|writing|thinking", // for create (optional, default: code)
+  "execute": false,                // for create: queue immediately?
+  "task_id": 123,                  // for input/execute
+  "input_text": "the input",       // for input
+  "query": "what they're asking",  // for query
+  "reply": "what to reply",        // always include a friendly reply
+  "reasoning": "why this action",  // brief explanation
+  "confidence": 0.95               // 0-1 confidence score
+}
+
+Guidelines:
+- If the email is clearly about a specific existing task (by ID or context), use "input" or relate to that task
+- If it's a new request/bug report/feature ask, use "create"
+- Extract a clear, actionable title for new tasks
+- Include relevant details in the body
+- If the email is a reply in a thread about a blocked task, it's likely providing "input"
+- Set "execute": true if user wants immediate execution (phrases like "and run it", "execute now", "do it", "start this", "asap", etc.)
+- Be friendly in replies, confirm what action you took
+- If unsure, ask for clarification in the reply and use lower confidence
+
+Respond with only the JSON object, no other text.`)
+
+	return sb.String()
+}
+
+// parseResponse decodes the JSON action from an LLM response, tolerating
+// markdown code fences around it.
+func parseResponse(text string) (*Action, error) {
+	// Try to extract JSON from the response
+	text = strings.TrimSpace(text)
+
+	// Handle markdown code blocks
+	if strings.HasPrefix(text, "```") {
+		lines := strings.Split(text, "\n")
+		var jsonLines []string
+		inBlock := false
+		for _, line := range lines {
+			if strings.HasPrefix(line, "```") {
+				inBlock = !inBlock
+				continue
+			}
+			if inBlock {
+				jsonLines = append(jsonLines, line)
+			}
+		}
+		text = strings.Join(jsonLines, "\n")
+	}
+
+	var action Action
+	if err := json.Unmarshal([]byte(text), &action); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w\nresponse: %s", err, text)
+	}
+
+	return &action, nil
+}
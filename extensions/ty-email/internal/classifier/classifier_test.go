@@ -0,0 +1,124 @@
+package classifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bborn/workflow/extensions/ty-email/internal/adapter"
+)
+
+const testActionJSON = `{"type":"create","title":"Fix login","body":"Login is broken","reply":"On it","confidence":0.9}`
+
+func TestParseResponseStripsCodeFence(t *testing.T) {
+	action, err := parseResponse("```json\n" + testActionJSON + "\n```")
+	if err != nil {
+		t.Fatalf("parseResponse: %v", err)
+	}
+	if action.Type != ActionCreate || action.Title != "Fix login" {
+		t.Errorf("unexpected action: %+v", action)
+	}
+}
+
+func TestOpenAIClassifier(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			t.Errorf("path = %q, want /chat/completions", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer sk-test" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var req openAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+		if req.Model != "gpt-test" {
+			t.Errorf("model = %q, want gpt-test", req.Model)
+		}
+		if len(req.Messages) != 1 || !strings.Contains(req.Messages[0].Content, "Fix the bug") {
+			t.Errorf("prompt missing email subject: %+v", req.Messages)
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{
+				{"message": map[string]string{"role": "assistant", "content": testActionJSON}},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	cls, err := NewOpenAIClassifier(&Config{Provider: "openai", Model: "gpt-test", APIKey: "sk-test", BaseURL: srv.URL + "/"})
+	if err != nil {
+		t.Fatalf("NewOpenAIClassifier: %v", err)
+	}
+
+	action, err := cls.Classify(context.Background(), &adapter.Email{Subject: "Fix the bug"}, nil, nil)
+	if err != nil {
+		t.Fatalf("Classify: %v", err)
+	}
+	if action.Type != ActionCreate || action.Reply != "On it" {
+		t.Errorf("unexpected action: %+v", action)
+	}
+}
+
+func TestOpenAIClassifierRequiresAPIKey(t *testing.T) {
+	if _, err := NewOpenAIClassifier(&Config{Provider: "openai"}); err == nil {
+		t.Fatal("expected error without API key")
+	}
+}
+
+func TestOpenAIClassifierAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"message":"invalid api key"}}`))
+	}))
+	defer srv.Close()
+
+	cls, err := NewOpenAIClassifier(&Config{APIKey: "bad", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewOpenAIClassifier: %v", err)
+	}
+	_, err = cls.Classify(context.Background(), &adapter.Email{}, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid api key") {
+		t.Fatalf("err = %v, want invalid api key", err)
+	}
+}
+
+func TestOllamaClassifier(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("path = %q, want /api/chat", r.URL.Path)
+		}
+		var req ollamaRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+		if req.Stream || req.Format != "json" {
+			t.Errorf("stream = %v, format = %q; want non-streaming json", req.Stream, req.Format)
+		}
+		if req.Model != "llama3.2" {
+			t.Errorf("model = %q, want default llama3.2", req.Model)
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"message": map[string]string{"role": "assistant", "content": testActionJSON},
+		})
+	}))
+	defer srv.Close()
+
+	cls, err := NewOllamaClassifier(&Config{Provider: "ollama", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewOllamaClassifier: %v", err)
+	}
+
+	action, err := cls.Classify(context.Background(), &adapter.Email{Subject: "Fix the bug"}, nil, nil)
+	if err != nil {
+		t.Fatalf("Classify: %v", err)
+	}
+	if action.Type != ActionCreate || action.Confidence != 0.9 {
+		t.Errorf("unexpected action: %+v", action)
+	}
+}
@@ -2,11 +2,8 @@ package classifier
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log/slog"
-	"os/exec"
-	"strings"
 
 	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/anthropics/anthropic-sdk-go/option"
@@ -22,17 +19,9 @@ type ClaudeClassifier struct {
 
 // NewClaudeClassifier creates a new Claude classifier.
 func NewClaudeClassifier(cfg *Config) (*ClaudeClassifier, error) {
-	apiKey := cfg.APIKey
-	if apiKey == "" && cfg.APIKeyCmd != "" {
-		out, err := exec.Command("sh", "-c", cfg.APIKeyCmd).Output()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get API key: %w", err)
-		}
-		apiKey = strings.TrimSpace(string(out))
-	}
-
-	if apiKey == "" {
-		return nil, fmt.Errorf("no API key configured")
+	apiKey, err := resolveAPIKey(cfg)
+	if err != nil {
+		return nil, err
 	}
 
 	client := anthropic.NewClient(
@@ -59,13 +48,7 @@ func (c *ClaudeClassifier) IsAvailable() bool {
 }
 
 func (c *ClaudeClassifier) Classify(ctx context.Context, email *adapter.Email, tasks []Task, threadTaskID *int64) (*Action, error) {
-	// Limit task context to avoid excessive input tokens
-	limitedTasks := tasks
-	if len(limitedTasks) > 10 {
-		limitedTasks = limitedTasks[:10]
-	}
-
-	prompt := c.buildPrompt(email, limitedTasks, threadTaskID)
+	prompt := buildPrompt(email, limitTasks(tasks), threadTaskID)
 
 	resp, err := c.client.Messages.New(ctx, anthropic.MessageNewParams{
 		Model:     anthropic.F(c.model),
@@ -97,120 +80,10 @@ func (c *ClaudeClassifier) Classify(ctx context.Context, email *adapter.Email, t
 	}
 
 	// Parse JSON response
-	action, err := c.parseResponse(responseText)
+	action, err := parseResponse(responseText)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	return action, nil
 }
-
-func (c *ClaudeClassifier) buildPrompt(email *adapter.Email, tasks []Task, threadTaskID *int64) string {
-	var sb strings.Builder
-
-	sb.WriteString(`You are an email classifier for TaskYou, a task management system.
-
-Your job is to understand the intent of incoming emails and translate them to TaskYou actions.
-
-Available actions:
-- "create": Create a new task
-- "input": Provide input to a task that's waiting (status=blocked)
-- "execute": Queue a task for execution
-- "query": User is asking about task status
-- "ignore": Email is spam, irrelevant, or doesn't need action
-
-`)
-
-	// Add current tasks context (only title/status/project to minimize tokens)
-	if len(tasks) > 0 {
-		sb.WriteString("Current tasks:\n")
-		for _, t := range tasks {
-			line := fmt.Sprintf("- #%d: %s (status: %s", t.ID, t.Title, t.Status)
-			if t.Project != "" {
-				line += ", project: " + t.Project
-			}
-			line += ")\n"
-			sb.WriteString(line)
-		}
-		sb.WriteString("\n")
-	} else {
-		sb.WriteString("No current tasks.\n\n")
-	}
-
-	// Add thread context if this is a reply
-	if threadTaskID != nil {
-		sb.WriteString(fmt.Sprintf("This email is part of a thread related to task #%d.\n\n", *threadTaskID))
-	}
-
-	// Add the email (truncate body to limit token usage)
-	sb.WriteString("Incoming email:\n")
-	sb.WriteString(fmt.Sprintf("From: %s\n", email.From))
-	sb.WriteString(fmt.Sprintf("Subject: %s\n", email.Subject))
-	body := email.Body
-	const maxBodyLen = 2000
-	if len(body) > maxBodyLen {
-		body = body[:maxBodyLen] + "\n[truncated]"
-	}
-	sb.WriteString(fmt.Sprintf("Body:\n%s\n\n", body))
-
-	// Instructions
-	sb.WriteString(`Analyze this email and respond with a JSON object:
-
-{
-  "type": "create|input|execute|query|ignore",
-  "title": "task title",           // for create
-  "body": "task description",      // for create
-  "project": "project name",       // for create (optional)
-  "task_type": "code|writing|thinking", // for create (optional, default: code)
-  "execute": false,                // for create: queue immediately?
-  "task_id": 123,                  // for input/execute
-  "input_text": "the input",       // for input
-  "query": "what they're asking",  // for query
-  "reply": "what to reply",        // always include a friendly reply
-  "reasoning": "why this action",  // brief explanation
-  "confidence": 0.95               // 0-1 confidence score
-}
-
-Guidelines:
-- If the email is clearly about a specific existing task (by ID or context), use "input" or relate to that task
-- If it's a new request/bug report/feature ask, use "create"
-- Extract a clear, actionable title for new tasks
-- Include relevant details in the body
-- If the email is a reply in a thread about a blocked task, it's likely providing "input"
-- Set "execute": true if user wants immediate execution (phrases like "and run it", "execute now", "do it", "start this", "asap", etc.)
-- Be friendly in replies, confirm what action you took
-- If unsure, ask for clarification in the reply and use lower confidence
-
-Respond with only the JSON object, no other text.`)
-
-	return sb.String()
-}
-
-func (c *ClaudeClassifier) parseResponse(text string) (*Action, error) {
-	// Try to extract JSON from the response
-	text = strings.TrimSpace(text)
-
-	// Handle markdown code blocks
-	if strings.HasPrefix(text, "```") {
-		lines := strings.Split(text, "\n")
-		var jsonLines []string
-		inBlock := false
-		for _, line := range lines {
-			if strings.HasPrefix(line, "```") {
-				inBlock = !inBlock
-				continue
-			}
-			if inBlock {
-				jsonLines = append(jsonLines, line)
-			}
-		}
-		text = strings.Join(jsonLines, "\n")
-	}
-
-	var action Action
-	if err := json.Unmarshal([]byte(text), &action); err != nil {
-		return nil, fmt.Errorf("invalid JSON: %w\nresponse: %s", err, text)
-	}
-
-	return &action, nil
-}
@@ -0,0 +1,129 @@
+package classifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bborn/workflow/extensions/ty-email/internal/adapter"
+)
+
+const defaultOllamaBaseURL = "http://localhost:11434"
+
+// OllamaClassifier uses a local Ollama server for email classification.
+type OllamaClassifier struct {
+	httpClient *http.Client
+	baseURL    string
+	model      string
+}
+
+// NewOllamaClassifier creates a new Ollama classifier. No API key is needed.
+func NewOllamaClassifier(cfg *Config) (*OllamaClassifier, error) {
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultOllamaBaseURL
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = "llama3.2"
+	}
+
+	return &OllamaClassifier{
+		// Local models can be slow to load on first use.
+		httpClient: &http.Client{Timeout: 5 * time.Minute},
+		baseURL:    baseURL,
+		model:      model,
+	}, nil
+}
+
+func (c *OllamaClassifier) Name() string {
+	return "ollama"
+}
+
+func (c *OllamaClassifier) IsAvailable() bool {
+	return c.baseURL != ""
+}
+
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Format   string          `json:"format"`
+}
+
+type ollamaResponse struct {
+	Message         ollamaMessage `json:"message"`
+	PromptEvalCount int           `json:"prompt_eval_count"`
+	EvalCount       int           `json:"eval_count"`
+	Error           string        `json:"error"`
+}
+
+func (c *OllamaClassifier) Classify(ctx context.Context, email *adapter.Email, tasks []Task, threadTaskID *int64) (*Action, error) {
+	prompt := buildPrompt(email, limitTasks(tasks), threadTaskID)
+
+	reqBody, err := json.Marshal(ollamaRequest{
+		Model:    c.model,
+		Messages: []ollamaMessage{{Role: "user", Content: prompt}},
+		Stream:   false,
+		Format:   "json",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/chat", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ollama API error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var result ollamaResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("ollama API error: status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("ollama API error: %s", result.Error)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ollama API error: status %d", resp.StatusCode)
+	}
+
+	// Log token usage
+	if result.PromptEvalCount > 0 || result.EvalCount > 0 {
+		slog.Info("classifier token usage",
+			"model", c.model,
+			"input_tokens", result.PromptEvalCount,
+			"output_tokens", result.EvalCount,
+		)
+	}
+
+	action, err := parseResponse(result.Message.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return action, nil
+}
@@ -0,0 +1,146 @@
+package classifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bborn/workflow/extensions/ty-email/internal/adapter"
+)
+
+const defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// OpenAIClassifier uses the OpenAI chat completions API for email classification.
+// BaseURL can point at any OpenAI-compatible endpoint.
+type OpenAIClassifier struct {
+	httpClient *http.Client
+	apiKey     string
+	baseURL    string
+	model      string
+}
+
+// NewOpenAIClassifier creates a new OpenAI classifier.
+func NewOpenAIClassifier(cfg *Config) (*OpenAIClassifier, error) {
+	apiKey, err := resolveAPIKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+
+	return &OpenAIClassifier{
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		apiKey:     apiKey,
+		baseURL:    baseURL,
+		model:      model,
+	}, nil
+}
+
+func (c *OpenAIClassifier) Name() string {
+	return "openai"
+}
+
+func (c *OpenAIClassifier) IsAvailable() bool {
+	return c.apiKey != ""
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIRequest struct {
+	Model          string            `json:"model"`
+	Messages       []openAIMessage   `json:"messages"`
+	MaxTokens      int               `json:"max_tokens"`
+	ResponseFormat map[string]string `json:"response_format"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (c *OpenAIClassifier) Classify(ctx context.Context, email *adapter.Email, tasks []Task, threadTaskID *int64) (*Action, error) {
+	prompt := buildPrompt(email, limitTasks(tasks), threadTaskID)
+
+	reqBody, err := json.Marshal(openAIRequest{
+		Model:          c.model,
+		Messages:       []openAIMessage{{Role: "user", Content: prompt}},
+		MaxTokens:      256,
+		ResponseFormat: map[string]string{"type": "json_object"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openai API error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var result openAIResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("openai API error: status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("openai API error: %s", result.Error.Message)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openai API error: status %d", resp.StatusCode)
+	}
+	if len(result.Choices) == 0 {
+		return nil, fmt.Errorf("openai API error: no choices in response")
+	}
+
+	// Log token usage
+	if result.Usage.PromptTokens > 0 || result.Usage.CompletionTokens > 0 {
+		slog.Info("classifier token usage",
+			"model", c.model,
+			"input_tokens", result.Usage.PromptTokens,
+			"output_tokens", result.Usage.CompletionTokens,
+		)
+	}
+
+	action, err := parseResponse(result.Choices[0].Message.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return action, nil
+}
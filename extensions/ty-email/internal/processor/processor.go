@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/mail"
+	"path"
 	"strings"
 	"time"
 
@@ -26,6 +27,7 @@ const maxClassifyAttempts = 3
 type TaskBridge interface {
 	ListTasks(status string) ([]bridge.Task, error)
 	CreateTask(action *classifier.Action) (*bridge.CreateResult, error)
+	AttachFiles(taskID int64, attachments []adapter.Attachment) error
 	SendInput(taskID int64, input string) error
 	ExecuteTask(taskID int64) error
 	GetBlockedTasks() ([]bridge.Task, error)
@@ -40,6 +42,8 @@ type Processor struct {
 	state          *state.DB
 	logger         *slog.Logger
 	allowedSenders []string
+	allowedDomains []string
+	bounceRejected bool
 	dangerous      bool
 	defaultProject string
 	autoExecute    bool
@@ -50,7 +54,9 @@ type Processor struct {
 type Config struct {
 	DefaultProject  string
 	FromAddress     string   // Reply-from address
-	AllowedSenders  []string // Only process emails from these addresses
+	AllowedSenders  []string // Only process emails from these addresses (supports wildcards like *@example.com)
+	AllowedDomains  []string // Also process emails from any address at these domains
+	BounceRejected  bool     // Reply to rejected senders instead of silently ignoring them
 	Dangerous       bool     // Enable dangerous mode for created tasks
 	AutoExecute     bool     // Queue email-created tasks for execution immediately
 	MaxTasksPerHour int      // Max emails processed per hour (0 = unlimited)
@@ -77,10 +83,15 @@ func New(
 	}
 	if cfg != nil {
 		p.allowedSenders = cfg.AllowedSenders
+		p.allowedDomains = cfg.AllowedDomains
+		p.bounceRejected = cfg.BounceRejected
 		p.dangerous = cfg.Dangerous
 		p.defaultProject = cfg.DefaultProject
 		p.autoExecute = cfg.AutoExecute
 		p.maxPerHour = cfg.MaxTasksPerHour
+		if len(p.allowedSenders) == 0 && len(p.allowedDomains) == 0 {
+			logger.Warn("no allowed_senders or allowed_domains configured: anyone who knows the address can create tasks")
+		}
 	}
 	return p
 }
@@ -88,24 +99,97 @@ func New(
 // senderAllowed checks the From address against the allowlist using exact
 // (case-insensitive) address comparison. The previous substring match could
 // be bypassed by display-name spoofing ("you@gmail.com" <evil@attacker.com>)
-// or domain suffixing (you@gmail.com.attacker.com).
+// or domain suffixing (you@gmail.com.attacker.com). Entries may contain
+// wildcards (*@example.com), which are matched against the whole address.
+// A From header that isn't exactly one parseable address is rejected, so a
+// list like "a@attacker.com, b@mycompany.com" can't satisfy a wildcard.
 func (p *Processor) senderAllowed(from string) bool {
-	if len(p.allowedSenders) == 0 {
+	if len(p.allowedSenders) == 0 && len(p.allowedDomains) == 0 {
 		return true
 	}
-	addr := from
-	if parsed, err := mail.ParseAddress(from); err == nil {
-		addr = parsed.Address
+	addr, ok := senderAddress(from)
+	if !ok {
+		return false
 	}
-	addr = strings.ToLower(strings.TrimSpace(addr))
 	for _, a := range p.allowedSenders {
-		if strings.ToLower(strings.TrimSpace(a)) == addr {
+		pattern := strings.ToLower(strings.TrimSpace(a))
+		if pattern == addr {
 			return true
 		}
+		if strings.ContainsAny(pattern, "*?[") {
+			if ok, err := path.Match(pattern, addr); err == nil && ok {
+				return true
+			}
+		}
+	}
+	if i := strings.LastIndexByte(addr, '@'); i >= 0 {
+		domain := addr[i+1:]
+		for _, d := range p.allowedDomains {
+			if strings.ToLower(strings.TrimPrefix(strings.TrimSpace(d), "@")) == domain {
+				return true
+			}
+		}
 	}
 	return false
 }
 
+// senderAddress extracts the lowercased bare address from a From header.
+// It reports false unless the header parses as a single address.
+func senderAddress(from string) (string, bool) {
+	parsed, err := mail.ParseAddress(from)
+	if err != nil {
+		return "", false
+	}
+	return strings.ToLower(strings.TrimSpace(parsed.Address)), true
+}
+
+// rejectSender handles an email from a sender not on the allowlist. By
+// default it is ignored; with BounceRejected a one-time reply is queued.
+func (p *Processor) rejectSender(ctx context.Context, email *adapter.Email) error {
+	p.logger.Debug("ignoring email from unauthorized sender", "from", email.From)
+	if !p.bounceRejected {
+		return nil
+	}
+
+	// Never bounce auto-generated mail (that's how loops start), and only
+	// bounce once per message.
+	if email.AutoReply {
+		return nil
+	}
+	processed, err := p.state.IsProcessed(email.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check processed status: %w", err)
+	}
+	if processed {
+		return nil
+	}
+
+	subject := email.Subject
+	if !strings.HasPrefix(strings.ToLower(subject), "re:") {
+		subject = "Re: " + subject
+	}
+	replyFrom := ""
+	if len(email.To) > 0 {
+		replyFrom = email.To[0]
+	}
+	if _, err := p.state.QueueOutbound(
+		email.From,
+		replyFrom,
+		subject,
+		"Your message was not processed: this address only accepts email from approved senders.",
+		nil,
+		email.ID,
+	); err != nil {
+		p.logger.Error("failed to queue bounce", "error", err)
+	}
+
+	p.state.MarkProcessed(email.ID, nil, "ignore:sender")
+	if err := p.adapter.MarkProcessed(ctx, adapterID(email)); err != nil {
+		p.logger.Warn("failed to mark rejected email as processed in adapter", "error", err)
+	}
+	return nil
+}
+
 // adapterID returns the ID to use for adapter operations (mark seen, label).
 func adapterID(email *adapter.Email) string {
 	if email.ProviderID != "" {
@@ -124,8 +208,7 @@ func (p *Processor) ProcessEmail(ctx context.Context, email *adapter.Email) erro
 
 	// Check if sender is allowed
 	if !p.senderAllowed(email.From) {
-		p.logger.Warn("ignoring email from unauthorized sender", "from", email.From)
-		return nil
+		return p.rejectSender(ctx, email)
 	}
 
 	// Check if already processed
@@ -254,7 +337,7 @@ func (p *Processor) ProcessEmail(ctx context.Context, email *adapter.Email) erro
 
 	switch action.Type {
 	case classifier.ActionCreate:
-		taskID, reply, err = p.handleCreate(ctx, action)
+		taskID, reply, err = p.handleCreate(ctx, action, email.Attachments)
 	case classifier.ActionInput:
 		taskID, reply, err = p.handleInput(ctx, action, threadTaskID)
 	case classifier.ActionExecute:
@@ -324,7 +407,7 @@ func (p *Processor) ProcessEmail(ctx context.Context, email *adapter.Email) erro
 	return nil
 }
 
-func (p *Processor) handleCreate(ctx context.Context, action *classifier.Action) (*int64, string, error) {
+func (p *Processor) handleCreate(ctx context.Context, action *classifier.Action, attachments []adapter.Attachment) (*int64, string, error) {
 	// Apply dangerous mode from config
 	if p.dangerous {
 		action.Dangerous = true
@@ -332,6 +415,14 @@ func (p *Processor) handleCreate(ctx context.Context, action *classifier.Action)
 	if action.Project == "" {
 		action.Project = p.defaultProject
 	}
+
+	// Attachments are copied into the worktree when the task starts, so hold
+	// off on queueing until they're attached.
+	execute := action.Execute
+	if len(attachments) > 0 {
+		action.Execute = false
+	}
+
 	result, err := p.bridge.CreateTask(action)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to create task: %w", err)
@@ -341,11 +432,32 @@ func (p *Processor) handleCreate(ctx context.Context, action *classifier.Action)
 	reply := fmt.Sprintf("Created task #%d: %s\nStatus: %s\nProject: %s",
 		result.ID, result.Title, result.Status, result.Project)
 
+	if len(attachments) > 0 {
+		names := make([]string, len(attachments))
+		for i, att := range attachments {
+			names[i] = att.Filename
+		}
+		if err := p.bridge.AttachFiles(taskID, attachments); err != nil {
+			p.logger.Warn("failed to attach email attachments", "task", taskID, "error", err)
+			reply += "\nCould not attach: " + strings.Join(names, ", ")
+		} else {
+			reply += "\nAttachments: " + strings.Join(names, ", ")
+		}
+
+		if execute {
+			if err := p.bridge.ExecuteTask(taskID); err != nil {
+				p.logger.Warn("failed to queue task", "task", taskID, "error", err)
+			} else {
+				reply += "\nQueued for execution."
+			}
+		}
+	}
+
 	if action.Reply != "" {
 		reply = action.Reply + "\n\n---\n" + reply
 	}
 
-	p.logger.Info("created task", "id", taskID, "title", result.Title)
+	p.logger.Info("created task", "id", taskID, "title", result.Title, "attachments", len(attachments))
 	return &taskID, reply, nil
 }
 
@@ -51,10 +51,12 @@ func (m *mockClassifier) IsAvailable() bool { return true }
 
 // mockBridge implements TaskBridge for testing.
 type mockBridge struct {
-	tasks   []bridge.Task
-	blocked []bridge.Task
-	created []*classifier.Action
-	inputs  map[int64]string
+	tasks    []bridge.Task
+	blocked  []bridge.Task
+	created  []*classifier.Action
+	inputs   map[int64]string
+	attached map[int64][]adapter.Attachment
+	executed []int64
 }
 
 func (m *mockBridge) ListTasks(status string) ([]bridge.Task, error) { return m.tasks, nil }
@@ -69,7 +71,17 @@ func (m *mockBridge) SendInput(taskID int64, input string) error {
 	m.inputs[taskID] = input
 	return nil
 }
-func (m *mockBridge) ExecuteTask(taskID int64) error                { return nil }
+func (m *mockBridge) AttachFiles(taskID int64, attachments []adapter.Attachment) error {
+	if m.attached == nil {
+		m.attached = map[int64][]adapter.Attachment{}
+	}
+	m.attached[taskID] = append(m.attached[taskID], attachments...)
+	return nil
+}
+func (m *mockBridge) ExecuteTask(taskID int64) error {
+	m.executed = append(m.executed, taskID)
+	return nil
+}
 func (m *mockBridge) GetBlockedTasks() ([]bridge.Task, error)       { return m.blocked, nil }
 func (m *mockBridge) GetTaskOutput(id int64, n int) (string, error) { return "output", nil }
 
@@ -264,6 +276,67 @@ func TestSenderAllowlistExactMatch(t *testing.T) {
 	}
 }
 
+func TestSenderAllowlistWildcardsAndDomains(t *testing.T) {
+	p := &Processor{
+		allowedSenders: []string{"*@mycompany.com"},
+		allowedDomains: []string{"partner.org"},
+	}
+
+	tests := []struct {
+		from    string
+		allowed bool
+	}{
+		{"alice@mycompany.com", true},
+		{"Bob <BOB@MyCompany.com>", true},
+		{"carol@partner.org", true},
+		{"alice@mycompany.com.attacker.com", false},
+		{"eve@sub.partner.org", false},
+		{`"alice@mycompany.com" <evil@attacker.com>`, false},
+		// Address lists and unparseable headers never match, even when the
+		// raw text would satisfy a wildcard or domain.
+		{"a@attacker.com, b@mycompany.com", false},
+		{"a@attacker.com, carol@partner.org", false},
+		{"not an address @mycompany.com", false},
+	}
+
+	for _, tt := range tests {
+		if got := p.senderAllowed(tt.from); got != tt.allowed {
+			t.Errorf("senderAllowed(%q) = %v, want %v", tt.from, got, tt.allowed)
+		}
+	}
+}
+
+func TestRejectedSenderBounce(t *testing.T) {
+	st, err := state.Open(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	br := &mockBridge{}
+	cfg := &Config{AllowedSenders: []string{"me@gmail.com"}, BounceRejected: true}
+	proc := New(&mockAdapter{}, &mockClassifier{}, br, st, cfg, nil)
+
+	email := &adapter.Email{ID: "<spam@example.com>", From: "stranger@example.com", Subject: "Hi"}
+	// Processing twice must bounce only once.
+	for i := 0; i < 2; i++ {
+		if err := proc.ProcessEmail(context.Background(), email); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(br.created) != 0 {
+		t.Errorf("expected no task for rejected sender, got %d", len(br.created))
+	}
+	pending, err := st.GetPendingOutbound(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].To != "stranger@example.com" {
+		t.Fatalf("expected one bounce to stranger@example.com, got %+v", pending)
+	}
+}
+
 func TestAutoReplySkipped(t *testing.T) {
 	st, err := state.Open(t.TempDir() + "/test.db")
 	if err != nil {
@@ -439,6 +512,45 @@ func TestDirectCreateTitleFallback(t *testing.T) {
 	}
 }
 
+func TestCreateAttachesFilesBeforeExecuting(t *testing.T) {
+	st, err := state.Open(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	br := &mockBridge{}
+	proc := New(&mockAdapter{}, &mockClassifier{}, br, st, &Config{AutoExecute: true}, nil)
+
+	email := &adapter.Email{
+		ID:      "<att@example.com>",
+		From:    "me@gmail.com",
+		Subject: "Login broken",
+		Body:    "See screenshot",
+		Attachments: []adapter.Attachment{
+			{Filename: "screenshot.png", ContentType: "image/png", Data: []byte("png")},
+		},
+	}
+	if err := proc.ProcessEmail(context.Background(), email); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(br.created) != 1 {
+		t.Fatalf("expected 1 task, got %d", len(br.created))
+	}
+	// The task must not be queued by create, or it could start before the
+	// attachment lands.
+	if br.created[0].Execute {
+		t.Error("expected create without --execute when attachments are present")
+	}
+	if got := br.attached[1]; len(got) != 1 || got[0].Filename != "screenshot.png" {
+		t.Errorf("expected screenshot.png attached to task 1, got %+v", got)
+	}
+	if len(br.executed) != 1 || br.executed[0] != 1 {
+		t.Errorf("expected task 1 queued after attaching, got %v", br.executed)
+	}
+}
+
 func TestBlockedNotificationDedup(t *testing.T) {
 	st, err := state.Open(t.TempDir() + "/test.db")
 	if err != nil {
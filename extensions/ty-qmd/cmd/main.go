@@ -15,7 +15,9 @@ import (
 
 	"github.com/bborn/workflow/extensions/ty-qmd/internal/exporter"
 	"github.com/bborn/workflow/extensions/ty-qmd/internal/qmd"
+	"github.com/bborn/workflow/extensions/ty-qmd/internal/state"
 	"github.com/bborn/workflow/extensions/ty-qmd/internal/tasks"
+	"github.com/bborn/workflow/extensions/ty-qmd/internal/watcher"
 	"github.com/bborn/workflow/internal/db"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -63,6 +65,7 @@ func main() {
 	rootCmd.AddCommand(searchCmd())
 	rootCmd.AddCommand(serveCmd())
 	rootCmd.AddCommand(indexProjectCmd())
+	rootCmd.AddCommand(watchCmd())
 	rootCmd.AddCommand(statusCmd())
 
 	if err := rootCmd.Execute(); err != nil {
@@ -72,12 +75,21 @@ func main() {
 
 func syncCmd() *cobra.Command {
 	var all bool
+	var staleOnly bool
 	var project string
 
 	cmd := &cobra.Command{
 		Use:   "sync",
 		Short: "Sync completed tasks to QMD index",
+		Long: `Export completed tasks to the QMD index.
+
+By default only tasks that were never synced, or that changed (updated_at
+or new logs) since they were last synced, are exported.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if all && staleOnly {
+				return fmt.Errorf("--all and --stale-only are mutually exclusive")
+			}
+
 			cfg, err := loadConfig()
 			if err != nil {
 				return err
@@ -91,86 +103,135 @@ func syncCmd() *cobra.Command {
 				return fmt.Errorf("qmd not found at: %s\nInstall with: bun install -g github:tobi/qmd", cfg.QMD.Binary)
 			}
 
-			// Open tasks database
-			taskDB, err := tasks.Open(cfg.TaskYou.DB)
-			if err != nil {
-				return fmt.Errorf("failed to open tasks db: %w", err)
+			mode := state.ModeIncremental
+			switch {
+			case all:
+				mode = state.ModeAll
+			case staleOnly:
+				mode = state.ModeStaleOnly
 			}
-			defer taskDB.Close()
 
-			// Get tasks to sync
-			opts := tasks.ListOptions{
-				Statuses: cfg.Sync.Statuses,
-			}
-			if project != "" {
-				opts.Project = project
-			}
-			taskList, err := taskDB.ListTasks(opts)
-			if err != nil {
-				return fmt.Errorf("failed to list tasks: %w", err)
-			}
+			_, err = runSync(cfg, q, logger, project, mode)
+			return err
+		},
+	}
 
-			if len(taskList) == 0 {
-				logger.Info("no tasks to sync")
-				return nil
-			}
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "re-sync all tasks, not just new or changed ones")
+	cmd.Flags().BoolVar(&staleOnly, "stale-only", false, "only re-sync tasks changed since their last sync")
+	cmd.Flags().StringVarP(&project, "project", "p", "", "sync only tasks from specific project")
 
-			logger.Info("syncing tasks", "count", len(taskList))
+	return cmd
+}
 
-			collection := cfg.Collections.Tasks
+// runSync exports the tasks selected by mode to the QMD tasks collection.
+func runSync(cfg *Config, q *qmd.QMD, logger *slog.Logger, project string, mode state.Mode) (state.SyncStats, error) {
+	var stats state.SyncStats
 
-			// Export and index each task
-			exp := exporter.New(cfg.Sync.IncludeLogs, cfg.Sync.MaxLogLines)
-			synced := 0
+	// Open tasks database
+	taskDB, err := tasks.Open(cfg.TaskYou.DB)
+	if err != nil {
+		return stats, fmt.Errorf("failed to open tasks db: %w", err)
+	}
+	defer taskDB.Close()
 
-			for _, t := range taskList {
-				// Fetch logs if enabled
-				var taskLogs []*db.TaskLog
-				if cfg.Sync.IncludeLogs {
-					taskLogs, _ = taskDB.GetTaskLogs(t.ID, cfg.Sync.MaxLogLines)
-				}
+	st, err := state.Open("")
+	if err != nil {
+		return stats, fmt.Errorf("failed to open state: %w", err)
+	}
+	defer st.Close()
 
-				// Export task to markdown
-				md := exp.Export(t, taskLogs)
+	// Record the time before listing, so edits made during the sync
+	// are picked up as stale next time.
+	syncTime := time.Now()
 
-				// Write to temp file
-				tmpDir := filepath.Join(os.TempDir(), "ty-qmd-export")
-				os.MkdirAll(tmpDir, 0755)
+	// Get tasks to sync
+	opts := tasks.ListOptions{
+		Statuses: cfg.Sync.Statuses,
+	}
+	if project != "" {
+		opts.Project = project
+	}
+	taskList, err := taskDB.ListTasks(opts)
+	if err != nil {
+		return stats, fmt.Errorf("failed to list tasks: %w", err)
+	}
 
-				filename := fmt.Sprintf("task-%d.md", t.ID)
-				tmpPath := filepath.Join(tmpDir, filename)
+	syncedAt, err := st.SyncedAt()
+	if err != nil {
+		return stats, err
+	}
+	taskList, stats = state.Plan(taskList, syncedAt, mode)
 
-				if err := os.WriteFile(tmpPath, []byte(md), 0644); err != nil {
-					logger.Error("failed to write temp file", "task", t.ID, "error", err)
-					continue
-				}
+	if len(taskList) == 0 {
+		logger.Info("no tasks to sync", "total", stats.Total, "skipped", stats.Skipped)
+		return stats, nil
+	}
 
-				// Index with qmd
-				if err := q.IndexFile(tmpPath, collection); err != nil {
-					logger.Error("failed to index task", "task", t.ID, "error", err)
-					continue
-				}
+	logger.Info("syncing tasks", "count", len(taskList), "new", stats.New, "stale", stats.Stale)
 
-				synced++
-				logger.Debug("synced task", "id", t.ID, "title", t.Title)
-			}
+	collection := cfg.Collections.Tasks
 
-			// Update index once after all files are added
-			if synced > 0 {
-				if err := q.Update(); err != nil {
-					return fmt.Errorf("failed to update index: %w", err)
-				}
-			}
+	// Export and index each task
+	exp := exporter.New(cfg.Sync.IncludeLogs, cfg.Sync.MaxLogLines)
+	var indexed []int64
 
-			logger.Info("sync complete", "synced", synced, "total", len(taskList))
-			return nil
-		},
-	}
+	for _, t := range taskList {
+		// Fetch logs if enabled
+		var taskLogs []*db.TaskLog
+		if cfg.Sync.IncludeLogs {
+			taskLogs, _ = taskDB.GetTaskLogs(t.ID, cfg.Sync.MaxLogLines)
+		}
 
-	cmd.Flags().BoolVarP(&all, "all", "a", false, "re-sync all tasks, not just new ones")
-	cmd.Flags().StringVarP(&project, "project", "p", "", "sync only tasks from specific project")
+		// Export task to markdown
+		md := exp.Export(t, taskLogs)
 
-	return cmd
+		// Write to temp file
+		tmpDir := filepath.Join(os.TempDir(), "ty-qmd-export")
+		os.MkdirAll(tmpDir, 0755)
+
+		filename := fmt.Sprintf("task-%d.md", t.ID)
+		tmpPath := filepath.Join(tmpDir, filename)
+
+		if err := os.WriteFile(tmpPath, []byte(md), 0644); err != nil {
+			logger.Error("failed to write temp file", "task", t.ID, "error", err)
+			stats.Failed++
+			continue
+		}
+
+		// Index with qmd
+		if err := q.IndexFile(tmpPath, collection); err != nil {
+			logger.Error("failed to index task", "task", t.ID, "error", err)
+			stats.Failed++
+			continue
+		}
+
+		indexed = append(indexed, t.ID)
+		logger.Debug("synced task", "id", t.ID, "title", t.Title)
+	}
+
+	// Update index once after all files are added, and only then
+	// record the tasks as synced.
+	if len(indexed) > 0 {
+		if err := q.Update(); err != nil {
+			return stats, fmt.Errorf("failed to update index: %w", err)
+		}
+		for _, id := range indexed {
+			if err := st.MarkSynced(id, syncTime); err != nil {
+				logger.Warn("failed to record sync", "task", id, "error", err)
+			}
+		}
+	}
+	stats.Synced = len(indexed)
+
+	logger.Info("sync complete",
+		"synced", stats.Synced,
+		"new", stats.New,
+		"stale", stats.Stale,
+		"skipped", stats.Skipped,
+		"failed", stats.Failed,
+		"total", stats.Total,
+	)
+	return stats, nil
 }
 
 func searchCmd() *cobra.Command {
@@ -336,6 +397,20 @@ func indexProjectCmd() *cobra.Command {
 				return fmt.Errorf("failed to embed: %w", err)
 			}
 
+			// Remember the project so `ty-qmd watch` keeps it fresh
+			absPath, err := filepath.Abs(path)
+			if err != nil {
+				return fmt.Errorf("failed to resolve path: %w", err)
+			}
+			st, err := state.Open("")
+			if err != nil {
+				return fmt.Errorf("failed to open state: %w", err)
+			}
+			defer st.Close()
+			if err := st.SaveProject(state.Project{Name: name, Path: absPath, Mask: mask}); err != nil {
+				return err
+			}
+
 			logger.Info("project indexed successfully", "collection", name)
 			return nil
 		},
@@ -347,6 +422,112 @@ func indexProjectCmd() *cobra.Command {
 	return cmd
 }
 
+func watchCmd() *cobra.Command {
+	var debounce time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Re-index project docs on change and sync tasks periodically",
+		Long: `Watch every directory added with index-project and re-index changed
+files as they are saved. Rapid edits are debounced into a single update,
+and deleted files are dropped from their collection on the next update.
+
+When sync.auto is true, tasks are also synced every sync.interval, so one
+long-running process keeps both tasks and docs fresh.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+
+			logger := setupLogger()
+			q := qmd.New(cfg.QMD.Binary, logger)
+
+			if !q.IsAvailable() {
+				return fmt.Errorf("qmd not found at: %s\nInstall with: bun install -g github:tobi/qmd", cfg.QMD.Binary)
+			}
+
+			st, err := state.Open("")
+			if err != nil {
+				return fmt.Errorf("failed to open state: %w", err)
+			}
+			projects, err := st.Projects()
+			st.Close()
+			if err != nil {
+				return err
+			}
+			if len(projects) == 0 && !cfg.Sync.Auto {
+				return fmt.Errorf("nothing to watch: index a project with 'ty-qmd index-project <path>' or enable sync.auto")
+			}
+
+			ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			defer cancel()
+
+			if cfg.Sync.Auto {
+				go syncLoop(ctx, cfg, q, logger)
+			}
+
+			if len(projects) == 0 {
+				<-ctx.Done()
+				return nil
+			}
+
+			w, err := watcher.New(projects, debounce, logger)
+			if err != nil {
+				return err
+			}
+			defer w.Close()
+
+			for _, p := range projects {
+				logger.Info("watching project", "collection", p.Name, "path", p.Path, "mask", p.Mask)
+			}
+
+			return w.Run(ctx, func(changes []watcher.Change) {
+				for _, c := range changes {
+					logger.Debug("re-indexing", "collection", c.Project, "path", c.Path, "removed", c.Removed)
+				}
+				// qmd update rescans collections (picking up edits and
+				// dropping deleted files); embed then covers new content.
+				if err := q.Update(); err != nil {
+					logger.Error("failed to update index", "error", err)
+					return
+				}
+				if err := q.Embed(); err != nil {
+					logger.Error("failed to embed", "error", err)
+					return
+				}
+				logger.Info("re-indexed docs", "files", len(changes))
+			})
+		},
+	}
+
+	cmd.Flags().DurationVar(&debounce, "debounce", watcher.DefaultDebounce, "wait this long after the last change before re-indexing")
+
+	return cmd
+}
+
+// syncLoop runs an incremental task sync every cfg.Sync.Interval until ctx ends.
+func syncLoop(ctx context.Context, cfg *Config, q *qmd.QMD, logger *slog.Logger) {
+	interval := cfg.Sync.Interval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := runSync(cfg, q, logger, "", state.ModeIncremental); err != nil {
+			logger.Error("task sync failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func statusCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "status",
@@ -377,6 +558,35 @@ func statusCmd() *cobra.Command {
 				fmt.Printf("  Embedded: %d\n", status.Embedded)
 			}
 
+			// Report how far the task index lags behind the TaskYou database
+			taskDB, err := tasks.Open(cfg.TaskYou.DB)
+			if err != nil {
+				fmt.Printf("Tasks: %v\n", err)
+				return nil
+			}
+			defer taskDB.Close()
+
+			st, err := state.Open("")
+			if err != nil {
+				return fmt.Errorf("failed to open state: %w", err)
+			}
+			defer st.Close()
+
+			taskList, err := taskDB.ListTasks(tasks.ListOptions{Statuses: cfg.Sync.Statuses})
+			if err != nil {
+				return fmt.Errorf("failed to list tasks: %w", err)
+			}
+			syncedAt, err := st.SyncedAt()
+			if err != nil {
+				return err
+			}
+			_, stats := state.Plan(taskList, syncedAt, state.ModeIncremental)
+			fmt.Println("Task Sync:")
+			fmt.Printf("  Tasks: %d\n", stats.Total)
+			fmt.Printf("  Up to date: %d\n", stats.Skipped)
+			fmt.Printf("  Never synced: %d\n", stats.New)
+			fmt.Printf("  Stale: %d\n", stats.Stale)
+
 			return nil
 		},
 	}
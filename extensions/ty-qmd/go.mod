@@ -6,6 +6,7 @@ replace github.com/bborn/workflow => ../../
 
 require (
 	github.com/bborn/workflow v0.0.0
+	github.com/fsnotify/fsnotify v1.10.1
 	github.com/spf13/cobra v1.10.2
 	gopkg.in/yaml.v3 v3.0.1
 	modernc.org/sqlite v1.46.1
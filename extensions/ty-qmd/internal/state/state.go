@@ -0,0 +1,206 @@
+// Package state tracks which tasks ty-qmd has synced and when.
+package state
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+	_ "modernc.org/sqlite"
+)
+
+// DB manages ty-qmd sync state. The TaskYou database is opened read-only,
+// so sync bookkeeping lives here instead.
+type DB struct {
+	db *sql.DB
+}
+
+// DefaultPath returns the default state database path.
+func DefaultPath() string {
+	dataDir := os.Getenv("XDG_DATA_HOME")
+	if dataDir == "" {
+		home, _ := os.UserHomeDir()
+		dataDir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataDir, "ty-qmd", "state.db")
+}
+
+// Open opens or creates the state database.
+func Open(path string) (*DB, error) {
+	if path == "" {
+		path = DefaultPath()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	conn, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state database: %w", err)
+	}
+	conn.SetMaxOpenConns(1)
+
+	schema := []string{
+		`CREATE TABLE IF NOT EXISTS synced_tasks (
+			task_id INTEGER PRIMARY KEY,
+			synced_at TEXT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS indexed_projects (
+			name TEXT PRIMARY KEY,
+			path TEXT NOT NULL,
+			mask TEXT NOT NULL DEFAULT ''
+		)`,
+	}
+	for _, stmt := range schema {
+		if _, err := conn.Exec(stmt); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to create schema: %w", err)
+		}
+	}
+
+	return &DB{db: conn}, nil
+}
+
+// Close closes the database.
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// SyncedAt returns when each synced task was last synced, keyed by task ID.
+func (d *DB) SyncedAt() (map[int64]time.Time, error) {
+	rows, err := d.db.Query(`SELECT task_id, synced_at FROM synced_tasks`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query synced tasks: %w", err)
+	}
+	defer rows.Close()
+
+	synced := make(map[int64]time.Time)
+	for rows.Next() {
+		var id int64
+		var at string
+		if err := rows.Scan(&id, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan synced task: %w", err)
+		}
+		t, err := time.Parse(time.RFC3339Nano, at)
+		if err != nil {
+			// Unparseable timestamp: treat as never synced.
+			continue
+		}
+		synced[id] = t
+	}
+	return synced, rows.Err()
+}
+
+// MarkSynced records that a task was synced at the given time.
+func (d *DB) MarkSynced(taskID int64, at time.Time) error {
+	_, err := d.db.Exec(`
+		INSERT INTO synced_tasks (task_id, synced_at) VALUES (?, ?)
+		ON CONFLICT(task_id) DO UPDATE SET synced_at = excluded.synced_at
+	`, taskID, at.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("failed to mark task synced: %w", err)
+	}
+	return nil
+}
+
+// Project is a directory indexed with `ty-qmd index-project`.
+type Project struct {
+	Name string // QMD collection name
+	Path string // Absolute directory path
+	Mask string // File glob, e.g. "**/*.md"
+}
+
+// SaveProject records an indexed project so `ty-qmd watch` can follow it.
+func (d *DB) SaveProject(p Project) error {
+	_, err := d.db.Exec(`
+		INSERT INTO indexed_projects (name, path, mask) VALUES (?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET path = excluded.path, mask = excluded.mask
+	`, p.Name, p.Path, p.Mask)
+	if err != nil {
+		return fmt.Errorf("failed to save project: %w", err)
+	}
+	return nil
+}
+
+// Projects returns all indexed projects ordered by name.
+func (d *DB) Projects() ([]Project, error) {
+	rows, err := d.db.Query(`SELECT name, path, mask FROM indexed_projects ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query projects: %w", err)
+	}
+	defer rows.Close()
+
+	var projects []Project
+	for rows.Next() {
+		var p Project
+		if err := rows.Scan(&p.Name, &p.Path, &p.Mask); err != nil {
+			return nil, fmt.Errorf("failed to scan project: %w", err)
+		}
+		projects = append(projects, p)
+	}
+	return projects, rows.Err()
+}
+
+// Mode selects which tasks a sync run exports.
+type Mode int
+
+const (
+	// ModeIncremental syncs never-synced tasks and tasks updated since their last sync.
+	ModeIncremental Mode = iota
+	// ModeStaleOnly syncs only tasks updated since their last sync.
+	ModeStaleOnly
+	// ModeAll re-syncs every task.
+	ModeAll
+)
+
+// SyncStats summarizes a sync run.
+type SyncStats struct {
+	Total   int `json:"total"`   // Tasks matching the sync filters
+	New     int `json:"new"`     // Never synced before
+	Stale   int `json:"stale"`   // Updated since their last sync
+	Skipped int `json:"skipped"` // Not selected for this run (up to date, or new with --stale-only)
+	Synced  int `json:"synced"`  // Successfully indexed
+	Failed  int `json:"failed"`  // Export or indexing failed
+}
+
+// Plan picks the tasks to sync. A task is stale when its UpdatedAt is
+// newer than the time it was last synced.
+func Plan(tasks []*db.Task, syncedAt map[int64]time.Time, mode Mode) ([]*db.Task, SyncStats) {
+	stats := SyncStats{Total: len(tasks)}
+	var selected []*db.Task
+
+	for _, t := range tasks {
+		at, synced := syncedAt[t.ID]
+		isNew := !synced
+		isStale := synced && t.UpdatedAt.After(at)
+
+		if isNew {
+			stats.New++
+		}
+		if isStale {
+			stats.Stale++
+		}
+
+		var pick bool
+		switch mode {
+		case ModeAll:
+			pick = true
+		case ModeStaleOnly:
+			pick = isStale
+		default:
+			pick = isNew || isStale
+		}
+
+		if pick {
+			selected = append(selected, t)
+		} else {
+			stats.Skipped++
+		}
+	}
+
+	return selected, stats
+}
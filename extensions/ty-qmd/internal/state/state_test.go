@@ -0,0 +1,71 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestPlan(t *testing.T) {
+	syncTime := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	tasks := []*db.Task{
+		{ID: 1, UpdatedAt: db.LocalTime{Time: syncTime.Add(-time.Hour)}}, // up to date
+		{ID: 2, UpdatedAt: db.LocalTime{Time: syncTime.Add(time.Hour)}},  // stale
+		{ID: 3, UpdatedAt: db.LocalTime{Time: syncTime}},                 // never synced
+	}
+	syncedAt := map[int64]time.Time{1: syncTime, 2: syncTime}
+
+	tests := []struct {
+		mode    Mode
+		wantIDs []int64
+	}{
+		{ModeIncremental, []int64{2, 3}},
+		{ModeStaleOnly, []int64{2}},
+		{ModeAll, []int64{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		selected, stats := Plan(tasks, syncedAt, tt.mode)
+		if len(selected) != len(tt.wantIDs) {
+			t.Fatalf("mode %d: selected %d tasks, want %d", tt.mode, len(selected), len(tt.wantIDs))
+		}
+		for i, id := range tt.wantIDs {
+			if selected[i].ID != id {
+				t.Errorf("mode %d: selected[%d] = #%d, want #%d", tt.mode, i, selected[i].ID, id)
+			}
+		}
+		if stats.Total != 3 || stats.New != 1 || stats.Stale != 1 {
+			t.Errorf("mode %d: stats = %+v, want total 3, new 1, stale 1", tt.mode, stats)
+		}
+		if stats.Skipped != 3-len(tt.wantIDs) {
+			t.Errorf("mode %d: skipped = %d, want %d", tt.mode, stats.Skipped, 3-len(tt.wantIDs))
+		}
+	}
+}
+
+func TestMarkSynced(t *testing.T) {
+	st, err := Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+
+	first := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	if err := st.MarkSynced(7, first); err != nil {
+		t.Fatal(err)
+	}
+	second := first.Add(time.Hour)
+	if err := st.MarkSynced(7, second); err != nil {
+		t.Fatal(err)
+	}
+
+	synced, err := st.SyncedAt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := synced[7]; !got.Equal(second) {
+		t.Errorf("synced_at = %v, want %v", got, second)
+	}
+}
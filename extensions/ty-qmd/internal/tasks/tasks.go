@@ -52,10 +52,13 @@ func (d *DB) Close() error {
 	return d.conn.Close()
 }
 
-// ListTasks returns tasks matching the options.
+// ListTasks returns tasks matching the options. UpdatedAt is the later of
+// the task's updated_at and its most recent log entry, so appended logs
+// count as a change for incremental sync.
 func (d *DB) ListTasks(opts ListOptions) ([]*db.Task, error) {
 	query := `
-		SELECT id, title, body, status, project, type, tags, summary, created_at, completed_at
+		SELECT id, title, body, status, project, type, tags, summary, created_at, updated_at, completed_at,
+			(SELECT MAX(created_at) FROM task_logs WHERE task_logs.task_id = tasks.id)
 		FROM tasks
 		WHERE 1=1
 	`
@@ -90,11 +93,19 @@ func (d *DB) ListTasks(opts ListOptions) ([]*db.Task, error) {
 	for rows.Next() {
 		var t db.Task
 		var completedAt sql.NullTime
+		var lastLog sql.NullString
 
-		if err := rows.Scan(&t.ID, &t.Title, &t.Body, &t.Status, &t.Project, &t.Type, &t.Tags, &t.Summary, &t.CreatedAt, &completedAt); err != nil {
+		if err := rows.Scan(&t.ID, &t.Title, &t.Body, &t.Status, &t.Project, &t.Type, &t.Tags, &t.Summary, &t.CreatedAt, &t.UpdatedAt, &completedAt, &lastLog); err != nil {
 			return nil, fmt.Errorf("failed to scan task: %w", err)
 		}
 
+		// MAX() loses the column type, so parse leniently; an unparseable
+		// value just means logs don't count towards staleness.
+		var lastLogAt db.LocalTime
+		if lastLog.Valid && lastLogAt.Scan(lastLog.String) == nil && lastLogAt.After(t.UpdatedAt.Time) {
+			t.UpdatedAt = lastLogAt
+		}
+
 		if completedAt.Valid {
 			lt := db.LocalTime{Time: completedAt.Time}
 			t.CompletedAt = &lt
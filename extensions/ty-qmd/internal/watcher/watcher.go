@@ -0,0 +1,187 @@
+// Package watcher watches indexed project directories for document changes.
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/bborn/workflow/extensions/ty-qmd/internal/state"
+)
+
+// DefaultDebounce is how long the watcher waits after the last change before
+// reporting a batch, so an editor's save burst triggers a single re-index.
+const DefaultDebounce = 2 * time.Second
+
+// skipDirs are never watched (VCS metadata and dependency trees).
+var skipDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// Change is a created, modified, or removed file that matches a project's mask.
+type Change struct {
+	Project string
+	Path    string
+	Removed bool
+}
+
+// Watcher reports debounced document changes in indexed projects.
+type Watcher struct {
+	fsw      *fsnotify.Watcher
+	projects []state.Project
+	debounce time.Duration
+	logger   *slog.Logger
+}
+
+// New creates a watcher over every directory under each project's path.
+func New(projects []state.Project, debounce time.Duration, logger *slog.Logger) (*Watcher, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create watcher: %w", err)
+	}
+
+	w := &Watcher{fsw: fsw, projects: projects, debounce: debounce, logger: logger}
+	for _, p := range projects {
+		if err := w.addTree(p.Path); err != nil {
+			fsw.Close()
+			return nil, fmt.Errorf("failed to watch %s: %w", p.Path, err)
+		}
+	}
+	return w, nil
+}
+
+// Close stops watching.
+func (w *Watcher) Close() error {
+	return w.fsw.Close()
+}
+
+// addTree watches dir and all its subdirectories.
+func (w *Watcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != dir && (skipDirs[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+			return filepath.SkipDir
+		}
+		return w.fsw.Add(path)
+	})
+}
+
+// Run delivers batches of changes to onChange until ctx is cancelled.
+// onChange runs on the watcher goroutine; events arriving meanwhile are
+// batched for the next call.
+func (w *Watcher) Run(ctx context.Context, onChange func([]Change)) error {
+	pending := make(map[string]Change)
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return nil
+			}
+			w.logger.Warn("watch error", "error", err)
+
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return nil
+			}
+			if c, ok := w.classify(ev); ok {
+				pending[c.Path] = c
+				timer.Reset(w.debounce)
+			}
+
+		case <-timer.C:
+			if len(pending) == 0 {
+				continue
+			}
+			changes := make([]Change, 0, len(pending))
+			for _, c := range pending {
+				changes = append(changes, c)
+			}
+			sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+			pending = make(map[string]Change)
+			onChange(changes)
+		}
+	}
+}
+
+// classify maps a filesystem event to a document change, watching newly
+// created directories along the way.
+func (w *Watcher) classify(ev fsnotify.Event) (Change, bool) {
+	if ev.Op&fsnotify.Chmod == ev.Op {
+		return Change{}, false
+	}
+
+	removed := ev.Op&(fsnotify.Remove|fsnotify.Rename) != 0
+	if !removed {
+		if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+			if ev.Op&fsnotify.Create != 0 {
+				if err := w.addTree(ev.Name); err != nil {
+					w.logger.Warn("failed to watch new directory", "path", ev.Name, "error", err)
+				}
+			}
+			return Change{}, false
+		}
+	}
+
+	for _, p := range w.projects {
+		rel, err := filepath.Rel(p.Path, ev.Name)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if MatchMask(p.Mask, rel) {
+			return Change{Project: p.Name, Path: ev.Name, Removed: removed}, true
+		}
+	}
+	return Change{}, false
+}
+
+// MatchMask reports whether a slash- or OS-separated relative path matches a
+// collection mask such as "**/*.md" or "docs/*.md". An empty mask matches
+// everything. "**/" matches any number of leading directories.
+func MatchMask(mask, rel string) bool {
+	if mask == "" {
+		return true
+	}
+	rel = filepath.ToSlash(rel)
+	if rest, ok := strings.CutPrefix(mask, "**/"); ok {
+		if ok, _ := filepath.Match(rest, rel); ok {
+			return true
+		}
+		parts := strings.Split(rel, "/")
+		for i := 1; i < len(parts); i++ {
+			if ok, _ := filepath.Match(rest, strings.Join(parts[i:], "/")); ok {
+				return true
+			}
+		}
+		return false
+	}
+	ok, _ := filepath.Match(mask, rel)
+	return ok
+}
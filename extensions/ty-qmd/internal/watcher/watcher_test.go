@@ -0,0 +1,84 @@
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bborn/workflow/extensions/ty-qmd/internal/state"
+)
+
+func TestMatchMask(t *testing.T) {
+	tests := []struct {
+		mask string
+		rel  string
+		want bool
+	}{
+		{"**/*.md", "README.md", true},
+		{"**/*.md", "docs/guide/setup.md", true},
+		{"**/*.md", "main.go", false},
+		{"docs/*.md", "docs/a.md", true},
+		{"docs/*.md", "docs/sub/a.md", false},
+		{"", "anything.txt", true},
+	}
+
+	for _, tt := range tests {
+		if got := MatchMask(tt.mask, tt.rel); got != tt.want {
+			t.Errorf("MatchMask(%q, %q) = %v, want %v", tt.mask, tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestRunDebouncesChanges(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	doomed := filepath.Join(dir, "docs", "old.md")
+	if err := os.WriteFile(doomed, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := New([]state.Project{{Name: "notes", Path: dir, Mask: "**/*.md"}}, 100*time.Millisecond, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	batches := make(chan []Change, 4)
+	go w.Run(ctx, func(c []Change) { batches <- c })
+
+	// Several rapid edits plus a non-matching file and a deletion.
+	page := filepath.Join(dir, "docs", "page.md")
+	for i := 0; i < 3; i++ {
+		if err := os.WriteFile(page, []byte{byte('a' + i)}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(doomed); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case changes := <-batches:
+		if len(changes) != 2 {
+			t.Fatalf("expected 2 changes in one batch, got %+v", changes)
+		}
+		if changes[0].Path != doomed || !changes[0].Removed {
+			t.Errorf("expected removal of %s, got %+v", doomed, changes[0])
+		}
+		if changes[1].Path != page || changes[1].Removed || changes[1].Project != "notes" {
+			t.Errorf("expected update of %s, got %+v", page, changes[1])
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for changes")
+	}
+}
@@ -404,12 +404,76 @@ func buildTitleGenerationPrompt(body, project string) string {
 	return sb.String()
 }
 
+// summaryMaxTokens bounds GenerateSummary's response: a short paragraph or a
+// handful of bullets.
+const summaryMaxTokens = 400
+
+// summaryInputLimit caps how much of the logs and of the diff goes into the
+// summary prompt, keeping large tasks within a cheap request.
+const summaryInputLimit = 12000
+
+// GenerateSummary writes a short summary of what a finished task
+// accomplished, from its description, execution logs and final diff.
+func (s *Service) GenerateSummary(ctx context.Context, title, body, logs, diff string) (string, error) {
+	if s.apiKey == "" {
+		return "", fmt.Errorf("no API key available")
+	}
+	if strings.TrimSpace(logs) == "" && strings.TrimSpace(diff) == "" {
+		return "", fmt.Errorf("task has no logs or diff to summarize")
+	}
+
+	summary, err := s.callAPIWithLimit(ctx, buildSummaryPrompt(title, body, logs, diff), summaryMaxTokens)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(summary), nil
+}
+
+func buildSummaryPrompt(title, body, logs, diff string) string {
+	var sb strings.Builder
+	sb.WriteString("Summarize what was accomplished in this completed task in 2-5 sentences or short bullets. ")
+	sb.WriteString("Focus on the outcome: what changed, where, and anything left unfinished. ")
+	sb.WriteString("Output ONLY the summary, no preamble.\n\n")
+	sb.WriteString(fmt.Sprintf("Task: %s\n", title))
+	if strings.TrimSpace(body) != "" {
+		sb.WriteString(fmt.Sprintf("Description:\n%s\n", truncateTail(body, summaryInputLimit/4)))
+	}
+	if strings.TrimSpace(logs) != "" {
+		sb.WriteString(fmt.Sprintf("\nExecution log (most recent):\n%s\n", truncateTail(logs, summaryInputLimit)))
+	}
+	if strings.TrimSpace(diff) != "" {
+		sb.WriteString(fmt.Sprintf("\nFinal diff:\n%s\n", truncateHead(diff, summaryInputLimit)))
+	}
+	sb.WriteString("\nSummary:")
+	return sb.String()
+}
+
+// truncateHead keeps the first n bytes of s.
+func truncateHead(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "\n[... truncated]"
+}
+
+// truncateTail keeps the last n bytes of s.
+func truncateTail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return "[... truncated]\n" + s[len(s)-n:]
+}
+
 func (s *Service) callAPI(ctx context.Context, prompt string) (string, error) {
+	return s.callAPIWithLimit(ctx, prompt, 50)
+}
+
+func (s *Service) callAPIWithLimit(ctx context.Context, prompt string, maxTokens int) (string, error) {
 	s.log("REQUEST: %s", prompt[:min(80, len(prompt))])
 
 	reqBody := anthropicRequest{
 		Model:     "claude-haiku-4-5-20251001",
-		MaxTokens: 50,
+		MaxTokens: maxTokens,
 		Messages: []message{
 			{Role: "user", Content: prompt},
 		},
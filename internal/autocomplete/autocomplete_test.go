@@ -466,3 +466,31 @@ func TestGenerateTitle_EmptyBody(t *testing.T) {
 		t.Error("GenerateTitle() should return error when body is whitespace only")
 	}
 }
+
+func TestGenerateSummary_NoAPIKey(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	svc := NewService("")
+	if _, err := svc.GenerateSummary(context.TODO(), "Fix login", "", "did things", ""); err == nil {
+		t.Error("GenerateSummary() should return error when no API key")
+	}
+}
+
+func TestGenerateSummary_NothingToSummarize(t *testing.T) {
+	svc := NewService("test-api-key")
+	if _, err := svc.GenerateSummary(context.TODO(), "Fix login", "body", " ", ""); err == nil {
+		t.Error("GenerateSummary() should return error without logs or diff")
+	}
+}
+
+func TestBuildSummaryPrompt(t *testing.T) {
+	longDiff := strings.Repeat("+line\n", summaryInputLimit)
+	prompt := buildSummaryPrompt("Fix login", "Users can't log in", "ran tests\nall green", longDiff)
+	for _, want := range []string{"Task: Fix login", "Users can't log in", "all green", "Final diff:", "[... truncated]"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+	if len(prompt) > 3*summaryInputLimit {
+		t.Errorf("prompt not truncated: %d bytes", len(prompt))
+	}
+}
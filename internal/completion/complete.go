@@ -134,7 +134,7 @@ func Complete(database *db.DB, taskID int64, summary string, opts Options) (*Out
 	// 2. Human-review gate: park rather than advance. Leaving it 'blocked' (not
 	// 'done') keeps its dependents held until a human releases the chain.
 	if nonTerminalStep && pipeline.IsGateStep(task) {
-		if err := database.UpdateTaskStatus(taskID, db.StatusBlocked); err != nil {
+		if err := database.BlockTask(taskID, db.BlockedNeedsInput, ""); err != nil {
 			return nil, fmt.Errorf("failed to park gate step for review: %w", err)
 		}
 		// Logged as a "question" so it lands in the blocked/needs-input lane and the
@@ -150,7 +150,7 @@ func Complete(database *db.DB, taskID int64, summary string, opts Options) (*Out
 		prNumber, prURL = LookupPR(database, task)
 	}
 	if prNumber > 0 {
-		if err := database.UpdateTaskStatus(taskID, db.StatusBlocked); err != nil {
+		if err := database.BlockTask(taskID, db.BlockedNeedsInput, ""); err != nil {
 			return nil, fmt.Errorf("failed to move task to review: %w", err)
 		}
 		reviewMsg := fmt.Sprintf("✅ PR #%d ready for review — merge or close it to complete this task.", prNumber)
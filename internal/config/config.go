@@ -34,8 +34,41 @@ const (
 	// SettingHTTPAPIDisabled, when "true", stops the daemon from hosting the
 	// HTTP API (for headless/security-sensitive boxes). The API is on by default.
 	SettingHTTPAPIDisabled = "http_api_disabled"
+	// SettingMaxConcurrentTasks caps how many queued tasks the daemon runs at
+	// once. Extra tasks stay queued until a running one finishes. See
+	// DefaultMaxConcurrentTasks.
+	SettingMaxConcurrentTasks = "max_concurrent_tasks"
+	// SettingMaxRetries is how many times the daemon automatically re-queues a
+	// failed task, with exponential backoff, before leaving it blocked. "0"
+	// (the default) turns automatic retries off.
+	SettingMaxRetries = "max_retries"
+	// SettingMaxAttachmentSize is the largest file, in megabytes, that
+	// `ty attach` accepts. See DefaultMaxAttachmentSize.
+	SettingMaxAttachmentSize = "max_attachment_size"
+	// SettingPRCacheTTL is how long `ty list --pr` and `ty show` reuse a PR
+	// lookup stored in the database before fetching it again (a duration such
+	// as "60s" or "5m"). See github.DefaultPRCacheTTL.
+	SettingPRCacheTTL = "pr_cache_ttl"
+	// SettingNotificationsEnabled, when "true", makes the daemon show a native
+	// desktop notification for the events listed in SettingNotifyOn.
+	SettingNotificationsEnabled = "notifications_enabled"
+	// SettingNotifyOn is a comma-separated list of event types to notify on.
+	// See DefaultNotifyOn.
+	SettingNotifyOn = "notify_on"
 )
 
+// DefaultNotifyOn is the events desktop notifications fire for when notify_on
+// is unset: a task needing input and a task finishing.
+const DefaultNotifyOn = "task.blocked,task.completed"
+
+// DefaultMaxConcurrentTasks is how many tasks the daemon runs at once when
+// max_concurrent_tasks is unset.
+const DefaultMaxConcurrentTasks = 3
+
+// DefaultMaxAttachmentSize is the `ty attach` size limit, in megabytes, when
+// max_attachment_size is unset.
+const DefaultMaxAttachmentSize = 10
+
 // DefaultHTTPAPIPort is the port the daemon-hosted HTTP API binds by default.
 // Matches the standalone `ty serve` default so existing clients (ty-web, the
 // ty-chrome extension) keep working without reconfiguration.
@@ -53,6 +53,8 @@ type KeybindingsConfig struct {
 	CollapseDone       *KeybindingConfig `yaml:"collapse_done,omitempty"`
 	OpenBrowser        *KeybindingConfig `yaml:"open_browser,omitempty"`
 	OpenPR             *KeybindingConfig `yaml:"open_pr,omitempty"`
+	MoveTaskUp         *KeybindingConfig `yaml:"move_task_up,omitempty"`
+	MoveTaskDown       *KeybindingConfig `yaml:"move_task_down,omitempty"`
 }
 
 // DefaultKeybindingsConfigPath returns the default path for the keybindings config file.
@@ -258,5 +260,14 @@ open_browser:
 open_pr:
   keys: ["G"]
   help: "open PR"
+
+# Reorder the selected task within its column
+move_task_up:
+  keys: ["K"]
+  help: "move task up"
+
+move_task_down:
+  keys: ["J"]
+  help: "move task down"
 `
 }
@@ -0,0 +1,212 @@
+// Package cron parses cron schedule specs and computes when they next fire.
+//
+// A spec is the classic five fields — minute, hour, day of month, month, day
+// of week — each a "*", a number, a range ("1-5"), a list ("1,15"), or a step
+// ("*/15", "8-18/2"). Months and weekdays also accept three-letter names
+// ("jan", "mon"); Sunday is 0 or 7. The descriptors @hourly, @daily
+// (@midnight), @weekly, @monthly and @yearly (@annually) are shorthands.
+//
+// A leading "CRON_TZ=<zone>" or "TZ=<zone>" evaluates the spec in that IANA
+// time zone instead of the local one:
+//
+//	CRON_TZ=America/New_York 0 9 * * mon-fri
+//
+// As in cron, when both day of month and day of week are restricted a day
+// matching either one fires.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron spec.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64 // bit n set = value n matches
+	domStar, dowStar              bool   // field started with "*" (unrestricted)
+	loc                           *time.Location
+	spec                          string
+}
+
+// String returns the spec the schedule was parsed from.
+func (s *Schedule) String() string { return s.spec }
+
+// Location returns the time zone the schedule is evaluated in.
+func (s *Schedule) Location() *time.Location { return s.loc }
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dowNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// Parse parses a cron spec. Schedules without a time zone prefix use
+// time.Local.
+func Parse(spec string) (*Schedule, error) {
+	s := &Schedule{loc: time.Local, spec: strings.TrimSpace(spec)}
+	fields := strings.Fields(spec)
+	if len(fields) > 0 && (strings.HasPrefix(fields[0], "CRON_TZ=") || strings.HasPrefix(fields[0], "TZ=")) {
+		zone := fields[0][strings.Index(fields[0], "=")+1:]
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			return nil, fmt.Errorf("unknown time zone %q", zone)
+		}
+		s.loc = loc
+		fields = fields[1:]
+	}
+	if len(fields) == 1 && strings.HasPrefix(fields[0], "@") {
+		expanded, ok := descriptors[strings.ToLower(fields[0])]
+		if !ok {
+			return nil, fmt.Errorf("unknown descriptor %q", fields[0])
+		}
+		fields = strings.Fields(expanded)
+	}
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected five fields (minute hour day-of-month month day-of-week), got %d", len(fields))
+	}
+
+	var err error
+	if s.minute, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("minute: %w", err)
+	}
+	if s.hour, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("hour: %w", err)
+	}
+	if s.dom, err = parseField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("day of month: %w", err)
+	}
+	if s.month, err = parseField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+	if s.dow, err = parseField(fields[4], 0, 7, dowNames); err != nil {
+		return nil, fmt.Errorf("day of week: %w", err)
+	}
+	if s.dow&(1<<7) != 0 { // 7 is Sunday too
+		s.dow |= 1
+	}
+	// Like cron, a field starting with "*" (including "*/2") counts as
+	// unrestricted when combining the two day fields.
+	s.domStar = strings.HasPrefix(fields[2], "*")
+	s.dowStar = strings.HasPrefix(fields[4], "*")
+	return s, nil
+}
+
+// parseField parses one comma-separated field into a bit set.
+func parseField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rangePart, step = part[:i], n
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			i := strings.Index(rangePart, "-")
+			var err error
+			if lo, err = parseValue(rangePart[:i], names); err != nil {
+				return 0, err
+			}
+			if hi, err = parseValue(rangePart[i+1:], names); err != nil {
+				return 0, err
+			}
+		default:
+			v, err := parseValue(rangePart, names)
+			if err != nil {
+				return 0, err
+			}
+			lo, hi = v, v
+			if step > 1 { // "5/10" means from 5 to the end, every 10
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(s string, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return v, nil
+}
+
+// Next returns the first time after t that the schedule fires, in t's
+// location. It returns the zero time if there is none within five years
+// (e.g. "0 0 30 2 *").
+func (s *Schedule) Next(t time.Time) time.Time {
+	orig := t.Location()
+	t = t.In(s.loc).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t.In(orig)
+	}
+	return time.Time{}
+}
+
+// Last returns the most recent time at or before now that the schedule fired
+// after since, or the zero time if it did not fire in (since, now].
+func (s *Schedule) Last(since, now time.Time) time.Time {
+	var last time.Time
+	for t := s.Next(since); !t.IsZero() && !t.After(now); t = s.Next(t) {
+		last = t
+	}
+	return last
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
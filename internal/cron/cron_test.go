@@ -0,0 +1,95 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRejectsBadSpecs(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"@fortnightly",
+		"CRON_TZ=Mars/Olympus 0 9 * * *",
+		"x * * * *",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	utc := time.UTC
+	from := time.Date(2026, 10, 16, 10, 30, 0, 0, utc) // a Friday
+
+	cases := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 16, 10, 31, 0, 0, utc)},
+		{"*/15 * * * *", time.Date(2026, 10, 16, 10, 45, 0, 0, utc)},
+		{"0 9 * * mon", time.Date(2026, 10, 19, 9, 0, 0, 0, utc)},
+		{"0 9 * * 1-5", time.Date(2026, 10, 19, 9, 0, 0, 0, utc)},
+		{"30 10 * * *", time.Date(2026, 10, 17, 10, 30, 0, 0, utc)},
+		{"0 0 1 jan *", time.Date(2027, 1, 1, 0, 0, 0, 0, utc)},
+		{"0 0 * * 7", time.Date(2026, 10, 18, 0, 0, 0, 0, utc)},
+		{"@daily", time.Date(2026, 10, 17, 0, 0, 0, 0, utc)},
+		{"@monthly", time.Date(2026, 11, 1, 0, 0, 0, 0, utc)},
+		// Both day fields restricted: the 20th OR any Monday, whichever is first.
+		{"0 0 20 * mon", time.Date(2026, 10, 19, 0, 0, 0, 0, utc)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, utc)},
+	}
+	for _, c := range cases {
+		s, err := Parse("CRON_TZ=UTC " + c.spec)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", c.spec, err)
+		}
+		if got := s.Next(from); !got.Equal(c.want) {
+			t.Errorf("%q: Next = %v, want %v", c.spec, got, c.want)
+		}
+	}
+
+	never, _ := Parse("0 0 30 2 *")
+	if got := never.Next(from); !got.IsZero() {
+		t.Errorf("Feb 30 fired at %v", got)
+	}
+}
+
+func TestNextInTimeZone(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	s, err := Parse("TZ=America/New_York 0 9 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) // 08:00 in New York
+	got := s.Next(from)
+	if want := time.Date(2026, 10, 16, 9, 0, 0, 0, ny); !got.Equal(want) {
+		t.Errorf("Next = %v, want %v", got, want)
+	}
+	if got.Location() != time.UTC {
+		t.Errorf("Next returned location %v, want the argument's", got.Location())
+	}
+}
+
+func TestLast(t *testing.T) {
+	s, _ := Parse("CRON_TZ=UTC 0 * * * *")
+	since := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 10, 16, 11, 20, 0, 0, time.UTC)
+	if got, want := s.Last(since, now), time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Last = %v, want %v", got, want)
+	}
+	if got := s.Last(now, now.Add(10*time.Minute)); !got.IsZero() {
+		t.Errorf("Last with no run in window = %v", got)
+	}
+}
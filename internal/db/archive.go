@@ -0,0 +1,78 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// ArchiveTask moves a task to archived. Like any move to archived (see
+// statusUpdateQuery and statusUndo), it remembers the current status
+// so UnarchiveTask can restore it, snapshots the task into the undo log, and
+// records a task.archived event naming the old status before it returns, so
+// callers can tear down the task's session and worktree afterwards.
+// Archiving an archived task is a no-op.
+func (db *DB) ArchiveTask(id int64) error {
+	task, err := db.GetTask(id)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return fmt.Errorf("task #%d not found", id)
+	}
+	if task.Status == StatusArchived {
+		return nil
+	}
+	return db.UpdateTaskStatus(id, StatusArchived)
+}
+
+// PreArchiveStatus returns the status a task had when it was archived, or ""
+// if it was archived before that was recorded.
+func (db *DB) PreArchiveStatus(id int64) (string, error) {
+	var status string
+	err := db.QueryRow(`SELECT COALESCE(pre_archive_status, '') FROM tasks WHERE id = ?`, id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("task #%d not found", id)
+	}
+	if err != nil {
+		return "", fmt.Errorf("query pre-archive status: %w", err)
+	}
+	return status, nil
+}
+
+// unarchiveStatus is the status an archived task returns to. Archiving stops
+// the agent, so a task that was processing comes back blocked (ready to be
+// resumed or retried) rather than claiming to still be running.
+func unarchiveStatus(pre string) string {
+	switch pre {
+	case "", StatusArchived:
+		return StatusBacklog
+	case StatusProcessing:
+		return StatusBlocked
+	}
+	return pre
+}
+
+// UnarchiveTask restores an archived task to the status it had before it was
+// archived (backlog if unknown) and returns that status.
+func (db *DB) UnarchiveTask(id int64) (string, error) {
+	task, err := db.GetTask(id)
+	if err != nil {
+		return "", err
+	}
+	if task == nil {
+		return "", fmt.Errorf("task #%d not found", id)
+	}
+	if task.Status != StatusArchived {
+		return "", fmt.Errorf("task #%d is not archived (status: %s)", id, task.Status)
+	}
+	pre, err := db.PreArchiveStatus(id)
+	if err != nil {
+		return "", err
+	}
+	status := unarchiveStatus(pre)
+	if err := db.UpdateTaskStatus(id, status); err != nil {
+		return "", err
+	}
+	db.recordEvent(EventTaskUnarchived, id, "to "+status)
+	return status, nil
+}
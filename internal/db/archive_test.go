@@ -0,0 +1,191 @@
+package db
+
+import "testing"
+
+func TestArchiveAndUnarchiveRestoresStatus(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{StatusBacklog, StatusBacklog},
+		{StatusBlocked, StatusBlocked},
+		{StatusDone, StatusDone},
+		// The agent is stopped on archive, so processing comes back blocked.
+		{StatusProcessing, StatusBlocked},
+	}
+	for _, tt := range tests {
+		task := &Task{Title: "archive " + tt.status, Status: StatusBacklog, Project: "personal"}
+		if err := database.CreateTask(task); err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+		if err := database.UpdateTaskStatus(task.ID, tt.status); err != nil {
+			t.Fatalf("UpdateTaskStatus: %v", err)
+		}
+
+		if err := database.ArchiveTask(task.ID); err != nil {
+			t.Fatalf("ArchiveTask: %v", err)
+		}
+		got, _ := database.GetTask(task.ID)
+		if got.Status != StatusArchived {
+			t.Fatalf("status after archive = %q", got.Status)
+		}
+		if pre, _ := database.PreArchiveStatus(task.ID); pre != tt.status {
+			t.Errorf("pre-archive status = %q, want %q", pre, tt.status)
+		}
+		// Archiving again must not overwrite the saved status.
+		if err := database.ArchiveTask(task.ID); err != nil {
+			t.Fatalf("ArchiveTask again: %v", err)
+		}
+
+		restored, err := database.UnarchiveTask(task.ID)
+		if err != nil {
+			t.Fatalf("UnarchiveTask: %v", err)
+		}
+		got, _ = database.GetTask(task.ID)
+		if restored != tt.want || got.Status != tt.want {
+			t.Errorf("from %s: unarchived to %q (task %q), want %q", tt.status, restored, got.Status, tt.want)
+		}
+	}
+}
+
+func TestArchiveTaskRecordsTransition(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	task := &Task{Title: "running", Status: StatusBacklog, Project: "personal"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	if err := database.UpdateTaskStatus(task.ID, StatusProcessing); err != nil {
+		t.Fatalf("UpdateTaskStatus: %v", err)
+	}
+	if err := database.ArchiveTask(task.ID); err != nil {
+		t.Fatalf("ArchiveTask: %v", err)
+	}
+
+	var n int
+	if err := database.QueryRow(`SELECT COUNT(*) FROM event_log WHERE task_id = ? AND event_type = 'task.archived' AND message = 'from processing'`, task.ID).Scan(&n); err != nil {
+		t.Fatalf("query event_log: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected one task.archived event from processing, got %d", n)
+	}
+
+	if _, err := database.UnarchiveTask(task.ID); err != nil {
+		t.Fatalf("UnarchiveTask: %v", err)
+	}
+	if _, err := database.UnarchiveTask(task.ID); err == nil {
+		t.Errorf("expected an error unarchiving a task that is not archived")
+	}
+}
+
+// TestStatusUpdateToArchivedIsUndoable verifies archiving through a plain
+// status change (ty status, the web UI, bulk edits) saves the pre-archive
+// status and an undo snapshot just like ArchiveTask.
+func TestStatusUpdateToArchivedIsUndoable(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	single := &Task{Title: "single", Status: StatusBlocked, Project: "personal"}
+	bulk := &Task{Title: "bulk", Status: StatusDone, Project: "personal"}
+	for _, task := range []*Task{single, bulk} {
+		if err := database.CreateTask(task); err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+	}
+
+	if err := database.UpdateTaskStatus(single.ID, StatusArchived); err != nil {
+		t.Fatalf("UpdateTaskStatus: %v", err)
+	}
+	if pre, _ := database.PreArchiveStatus(single.ID); pre != StatusBlocked {
+		t.Errorf("pre-archive status = %q, want %q", pre, StatusBlocked)
+	}
+	entry, _ := database.LatestUndo()
+	if entry == nil || entry.Operation != UndoArchive || entry.TaskID != single.ID {
+		t.Fatalf("undo entry = %+v, want archive of #%d", entry, single.ID)
+	}
+	if _, err := database.Undo(entry); err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+	if got, _ := database.GetTask(single.ID); got.Status != StatusBlocked {
+		t.Errorf("status after undo = %q, want %q", got.Status, StatusBlocked)
+	}
+	if pre, _ := database.PreArchiveStatus(single.ID); pre != "" {
+		t.Errorf("pre-archive status after undo = %q, want it cleared", pre)
+	}
+
+	if err := database.UpdateTasksStatus([]int64{bulk.ID}, StatusArchived); err != nil {
+		t.Fatalf("UpdateTasksStatus: %v", err)
+	}
+	if pre, _ := database.PreArchiveStatus(bulk.ID); pre != StatusDone {
+		t.Errorf("bulk pre-archive status = %q, want %q", pre, StatusDone)
+	}
+	if entry, _ := database.LatestUndo(); entry == nil || entry.Operation != UndoArchive || entry.TaskID != bulk.ID {
+		t.Errorf("bulk undo entry = %+v, want archive of #%d", entry, bulk.ID)
+	}
+}
+
+func TestBulkArchiveIsOneUndoBatch(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	var ids []int64
+	for _, title := range []string{"a", "b", "c"} {
+		task := &Task{Title: title, Status: StatusDone, Project: "personal"}
+		if err := database.CreateTask(task); err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+		ids = append(ids, task.ID)
+	}
+	if err := database.UpdateTasksStatus(ids, StatusArchived); err != nil {
+		t.Fatalf("UpdateTasksStatus: %v", err)
+	}
+
+	batch, err := database.LatestUndoBatch()
+	if err != nil {
+		t.Fatalf("LatestUndoBatch: %v", err)
+	}
+	if len(batch) != len(ids) {
+		t.Fatalf("undo batch has %d entries, want %d", len(batch), len(ids))
+	}
+	for _, e := range batch {
+		if _, err := database.Undo(e); err != nil {
+			t.Fatalf("Undo: %v", err)
+		}
+	}
+	for _, id := range ids {
+		if got, _ := database.GetTask(id); got.Status != StatusDone {
+			t.Errorf("#%d status after undo = %q, want %q", id, got.Status, StatusDone)
+		}
+	}
+	if entry, _ := database.LatestUndo(); entry != nil {
+		t.Errorf("undo log should be empty, got %+v", entry)
+	}
+}
+
+func TestFailedArchiveRecordsNoUndo(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	task := &Task{Title: "stuck", Status: StatusDone, Project: "personal"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	if _, err := database.Exec(`CREATE TRIGGER refuse_archive BEFORE UPDATE OF status ON tasks
+		BEGIN SELECT RAISE(ABORT, 'refused'); END`); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+
+	if err := database.UpdateTaskStatus(task.ID, StatusArchived); err == nil {
+		t.Fatal("expected the status update to fail")
+	}
+	if err := database.UpdateTasksStatus([]int64{task.ID}, StatusArchived); err == nil {
+		t.Fatal("expected the bulk status update to fail")
+	}
+	if entry, _ := database.LatestUndo(); entry != nil {
+		t.Errorf("a failed archive left an undo entry: %+v", entry)
+	}
+}
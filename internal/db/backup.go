@@ -0,0 +1,177 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Backups are single self-contained SQLite files written with VACUUM INTO,
+// which reads the database in one transaction: the copy is consistent even
+// while the daemon keeps writing, and it includes whatever is still in the
+// WAL (see Files), so no sidecar files need copying.
+
+// backupPrefix and backupExt name timestamped backups: tasks-20060102-150405.db.
+const (
+	backupPrefix     = "tasks-"
+	backupExt        = ".db"
+	backupTimeLayout = "20060102-150405"
+)
+
+// DefaultBackupDir returns the directory ty backup writes to by default: a
+// backups directory next to the database.
+func DefaultBackupDir() string {
+	return filepath.Join(filepath.Dir(DefaultPath()), "backups")
+}
+
+// BackupFileName returns the timestamped file name for a backup taken at t.
+func BackupFileName(t time.Time) string {
+	return backupPrefix + t.Format(backupTimeLayout) + backupExt
+}
+
+// Backup writes a consistent snapshot of the database to dest, which must not
+// exist yet.
+func (db *DB) Backup(dest string) error {
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("%s already exists", dest)
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("create backup directory: %w", err)
+	}
+	if _, err := db.Exec(`VACUUM INTO ?`, dest); err != nil {
+		os.Remove(dest)
+		return fmt.Errorf("backup database: %w", err)
+	}
+	return nil
+}
+
+// VerifyBackup checks that path is a readable ty database without changing
+// it: it must pass SQLite's quick_check, have a tasks table, and not come
+// from a newer ty (a schema version above LatestSchemaVersion). Older
+// versions are fine; Open migrates them. It returns the schema version.
+func VerifyBackup(path string) (int, error) {
+	if _, err := os.Stat(path); err != nil {
+		return 0, err
+	}
+	conn, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return 0, fmt.Errorf("open backup: %w", err)
+	}
+	defer conn.Close()
+
+	var check string
+	if err := conn.QueryRow(`PRAGMA quick_check`).Scan(&check); err != nil {
+		return 0, fmt.Errorf("%s is not a readable SQLite database: %w", path, err)
+	}
+	if check != "ok" {
+		return 0, fmt.Errorf("%s failed the integrity check: %s", path, check)
+	}
+
+	var tables int
+	if err := conn.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'tasks'`).Scan(&tables); err != nil {
+		return 0, fmt.Errorf("read backup schema: %w", err)
+	}
+	if tables == 0 {
+		return 0, fmt.Errorf("%s is not a ty database (no tasks table)", path)
+	}
+
+	version, err := schemaVersionOf(conn)
+	if err != nil {
+		return 0, fmt.Errorf("read backup schema version: %w", err)
+	}
+	if version > LatestSchemaVersion() {
+		return version, fmt.Errorf("%s has schema version %d but this ty only knows up to %d; upgrade ty first", path, version, LatestSchemaVersion())
+	}
+	return version, nil
+}
+
+// RestoreBackup replaces the database at dest with a copy of src. src is
+// verified first. The copy is written next to dest and renamed over it, and
+// dest's WAL sidecars are removed so SQLite can't replay the old database's
+// log onto the restored file. Nothing may have dest open while this runs.
+func RestoreBackup(src, dest string) error {
+	if _, err := VerifyBackup(src); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("open backup: %w", err)
+	}
+	defer in.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), filepath.Base(dest)+".restore-*")
+	if err != nil {
+		return fmt.Errorf("create restore file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op once renamed
+
+	if _, err := io.Copy(tmp, in); err != nil {
+		tmp.Close()
+		return fmt.Errorf("copy backup: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync restore file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close restore file: %w", err)
+	}
+
+	for _, sidecar := range Files(dest)[1:] {
+		if err := os.Remove(sidecar); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove %s: %w", sidecar, err)
+		}
+	}
+	if err := os.Rename(tmpPath, dest); err != nil {
+		return fmt.Errorf("replace database: %w", err)
+	}
+	return nil
+}
+
+// PruneBackups deletes all but the newest keep timestamped backups in dir
+// (files named like BackupFileName; anything else is left alone) and returns
+// the paths it removed.
+func PruneBackups(dir string, keep int) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read backup directory: %w", err)
+	}
+
+	var backups []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, backupPrefix) || !strings.HasSuffix(name, backupExt) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, backupPrefix), backupExt)
+		if _, err := time.Parse(backupTimeLayout, stamp); err != nil {
+			continue
+		}
+		backups = append(backups, name)
+	}
+	if len(backups) <= keep {
+		return nil, nil
+	}
+
+	// The timestamp layout sorts chronologically as a string.
+	sort.Strings(backups)
+	var removed []string
+	for _, name := range backups[:len(backups)-keep] {
+		path := filepath.Join(dir, name)
+		if err := os.Remove(path); err != nil {
+			return removed, fmt.Errorf("remove old backup: %w", err)
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
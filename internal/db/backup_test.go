@@ -0,0 +1,125 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "tasks.db")
+	database, err := Open(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := database.CreateTask(&Task{Title: "keep me", Status: StatusBacklog}); err != nil {
+		t.Fatal(err)
+	}
+
+	backup := filepath.Join(dir, "backups", BackupFileName(time.Now()))
+	if err := database.Backup(backup); err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+	if err := database.Backup(backup); err == nil {
+		t.Error("Backup overwrote an existing file")
+	}
+	version, err := VerifyBackup(backup)
+	if err != nil {
+		t.Fatalf("VerifyBackup: %v", err)
+	}
+	if version != LatestSchemaVersion() {
+		t.Errorf("backup schema version = %d, want %d", version, LatestSchemaVersion())
+	}
+
+	// Changes made after the backup are undone by restoring it.
+	if err := database.CreateTask(&Task{Title: "lose me", Status: StatusBacklog}); err != nil {
+		t.Fatal(err)
+	}
+	database.Close()
+	if err := RestoreBackup(backup, dbPath); err != nil {
+		t.Fatalf("RestoreBackup: %v", err)
+	}
+
+	restored, err := Open(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restored.Close()
+	tasks, err := restored.ListTasks(ListTasksOptions{IncludeClosed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0].Title != "keep me" {
+		t.Errorf("restored tasks = %+v", tasks)
+	}
+}
+
+func TestVerifyBackupRejectsBadFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	junk := filepath.Join(dir, "junk.db")
+	if err := os.WriteFile(junk, []byte("not a database"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyBackup(junk); err == nil {
+		t.Error("accepted a non-SQLite file")
+	}
+	if _, err := VerifyBackup(filepath.Join(dir, "missing.db")); err == nil {
+		t.Error("accepted a missing file")
+	}
+
+	newer := filepath.Join(dir, "newer.db")
+	database, err := Open(newer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.Exec(`INSERT INTO schema_version (version, name) VALUES (?, 'from the future')`, LatestSchemaVersion()+1); err != nil {
+		t.Fatal(err)
+	}
+	database.Close()
+	if _, err := VerifyBackup(newer); err == nil {
+		t.Error("accepted a backup from a newer schema")
+	}
+	if err := RestoreBackup(newer, filepath.Join(dir, "tasks.db")); err == nil {
+		t.Error("restored a backup that failed verification")
+	}
+}
+
+func TestPruneBackups(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.Local)
+	for i := 0; i < 5; i++ {
+		name := BackupFileName(start.Add(time.Duration(i) * time.Hour))
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tasks-manual.db"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := PruneBackups(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 3 || filepath.Base(removed[0]) != BackupFileName(start) {
+		t.Errorf("removed = %v", removed)
+	}
+	entries, _ := os.ReadDir(dir)
+	var left []string
+	for _, e := range entries {
+		left = append(left, e.Name())
+	}
+	want := []string{BackupFileName(start.Add(3 * time.Hour)), BackupFileName(start.Add(4 * time.Hour)), "tasks-manual.db"}
+	if len(left) != len(want) {
+		t.Fatalf("left = %v, want %v", left, want)
+	}
+	for i := range want {
+		if left[i] != want[i] {
+			t.Errorf("left = %v, want %v", left, want)
+			break
+		}
+	}
+}
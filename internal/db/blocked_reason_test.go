@@ -0,0 +1,64 @@
+package db
+
+import "testing"
+
+func TestBlockedReasonLifecycle(t *testing.T) {
+	database := newPermTestDB(t)
+
+	if err := database.CreateProject(&Project{Name: "app", Path: t.TempDir()}); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	failed := &Task{Title: "failed", Project: "app", Status: StatusProcessing}
+	waiting := &Task{Title: "waiting", Project: "app", Status: StatusProcessing}
+	for _, task := range []*Task{failed, waiting} {
+		if err := database.CreateTask(task); err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+	}
+
+	if err := database.BlockTask(failed.ID, BlockedError, ""); err != nil {
+		t.Fatalf("block task: %v", err)
+	}
+	if err := database.BlockTask(waiting.ID, BlockedNeedsInput, ""); err != nil {
+		t.Fatalf("block task: %v", err)
+	}
+
+	got, err := database.GetTask(failed.ID)
+	if err != nil {
+		t.Fatalf("get task: %v", err)
+	}
+	if got.Status != StatusBlocked || got.BlockedReason != BlockedError {
+		t.Errorf("status/reason = %q/%q, want %q/%q", got.Status, got.BlockedReason, StatusBlocked, BlockedError)
+	}
+
+	tasks, err := database.ListTasks(ListTasksOptions{BlockedReason: BlockedError})
+	if err != nil {
+		t.Fatalf("list tasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != failed.ID {
+		t.Errorf("ListTasks(BlockedReason=error) returned %d tasks, want only #%d", len(tasks), failed.ID)
+	}
+
+	// Leaving blocked clears the reason.
+	if err := database.UpdateTaskStatus(failed.ID, StatusQueued); err != nil {
+		t.Fatalf("update status: %v", err)
+	}
+	got, err = database.GetTask(failed.ID)
+	if err != nil {
+		t.Fatalf("get task: %v", err)
+	}
+	if got.BlockedReason != "" {
+		t.Errorf("BlockedReason after requeue = %q, want empty", got.BlockedReason)
+	}
+}
+
+func TestIsValidBlockedReason(t *testing.T) {
+	for _, r := range BlockedReasons() {
+		if !IsValidBlockedReason(r) {
+			t.Errorf("IsValidBlockedReason(%q) = false, want true", r)
+		}
+	}
+	if IsValidBlockedReason("bogus") {
+		t.Error("IsValidBlockedReason(bogus) = true, want false")
+	}
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -16,16 +17,34 @@ type Dependency struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// DependencyCycleError is returned by AddDependency when the new dependency
+// would close a cycle. Path is the cycle, starting and ending at the proposed
+// blocker: blocker → blocked → … → blocker.
+type DependencyCycleError struct {
+	Path []int64
+}
+
+func (e *DependencyCycleError) Error() string {
+	if len(e.Path) == 2 && e.Path[0] == e.Path[1] {
+		return "a task cannot block itself"
+	}
+	parts := make([]string, len(e.Path))
+	for i, id := range e.Path {
+		parts[i] = fmt.Sprintf("#%d", id)
+	}
+	return "adding this dependency would create a cycle: " + strings.Join(parts, " → ")
+}
+
 // AddDependency creates a dependency where blockerID blocks blockedID.
-// Returns an error if the dependency already exists or would create a cycle.
+// Returns an error if the dependency already exists, or a
+// *DependencyCycleError if it would create a cycle.
 func (db *DB) AddDependency(blockerID, blockedID int64, autoQueue bool) error {
-	if blockerID == blockedID {
-		return fmt.Errorf("a task cannot block itself")
+	path, err := db.DependencyCyclePath(blockerID, blockedID)
+	if err != nil {
+		return err
 	}
-
-	// Check for cycles - blockedID should not be able to reach blockerID
-	if db.wouldCreateCycle(blockerID, blockedID) {
-		return fmt.Errorf("adding this dependency would create a cycle")
+	if path != nil {
+		return &DependencyCycleError{Path: path}
 	}
 
 	autoQueueInt := 0
@@ -33,7 +52,7 @@ func (db *DB) AddDependency(blockerID, blockedID int64, autoQueue bool) error {
 		autoQueueInt = 1
 	}
 
-	_, err := db.Exec(`
+	_, err = db.Exec(`
 		INSERT INTO task_dependencies (blocker_id, blocked_id, auto_queue)
 		VALUES (?, ?, ?)
 	`, blockerID, blockedID, autoQueueInt)
@@ -44,44 +63,68 @@ func (db *DB) AddDependency(blockerID, blockedID int64, autoQueue bool) error {
 	return nil
 }
 
-// wouldCreateCycle checks if adding blockerID -> blockedID would create a cycle.
-// A cycle exists if blockedID can reach blockerID through existing dependencies.
-func (db *DB) wouldCreateCycle(blockerID, blockedID int64) bool {
-	// BFS to check if blockedID can reach blockerID
-	visited := make(map[int64]bool)
-	queue := []int64{blockedID}
+// DependencyCyclePath reports whether making blockerID block blockedID would
+// create a cycle, i.e. whether blockedID already (transitively) blocks
+// blockerID. It returns the cycle the new dependency would close, as in
+// DependencyCycleError.Path, or nil when the dependency is safe.
+func (db *DB) DependencyCyclePath(blockerID, blockedID int64) ([]int64, error) {
+	if blockerID == blockedID {
+		return []int64{blockerID, blockerID}, nil
+	}
 
+	// BFS from blockedID along "blocks" edges, remembering how each task was
+	// reached so the path back to blockedID can be rebuilt.
+	parent := map[int64]int64{blockedID: 0}
+	queue := []int64{blockedID}
 	for len(queue) > 0 {
 		current := queue[0]
 		queue = queue[1:]
 
 		if current == blockerID {
-			return true
-		}
-
-		if visited[current] {
-			continue
+			var reversed []int64
+			for id := current; id != 0; id = parent[id] {
+				reversed = append(reversed, id)
+			}
+			// reversed is blockerID ← … ← blockedID; the cycle starts with the
+			// proposed edge blockerID → blockedID and follows it back around.
+			path := []int64{blockerID}
+			for i := len(reversed) - 1; i >= 0; i-- {
+				path = append(path, reversed[i])
+			}
+			return path, nil
 		}
-		visited[current] = true
 
-		// Get all tasks that current blocks
-		rows, err := db.Query(`
-			SELECT blocked_id FROM task_dependencies WHERE blocker_id = ?
-		`, current)
+		next, err := db.blockedIDs(current)
 		if err != nil {
-			continue
+			return nil, err
 		}
-
-		for rows.Next() {
-			var nextID int64
-			if err := rows.Scan(&nextID); err == nil {
-				queue = append(queue, nextID)
+		for _, id := range next {
+			if _, seen := parent[id]; !seen {
+				parent[id] = current
+				queue = append(queue, id)
 			}
 		}
-		rows.Close()
 	}
+	return nil, nil
+}
 
-	return false
+// blockedIDs returns the IDs of the tasks taskID directly blocks.
+func (db *DB) blockedIDs(taskID int64) ([]int64, error) {
+	rows, err := db.Query(`SELECT blocked_id FROM task_dependencies WHERE blocker_id = ?`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("query dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan dependency: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
 }
 
 // RemoveDependency removes a dependency between two tasks.
@@ -328,6 +371,32 @@ func (db *DB) hasAutoQueueBlocker(taskID int64) (bool, error) {
 	return n > 0, nil
 }
 
+// ListDependencies returns every dependency between live (not trashed) tasks,
+// oldest first.
+func (db *DB) ListDependencies() ([]*Dependency, error) {
+	rows, err := db.Query(`
+		SELECT d.id, d.blocker_id, d.blocked_id, COALESCE(d.auto_queue, 0), d.created_at
+		FROM task_dependencies d
+		JOIN tasks blocker ON blocker.id = d.blocker_id AND blocker.deleted_at IS NULL
+		JOIN tasks blocked ON blocked.id = d.blocked_id AND blocked.deleted_at IS NULL
+		ORDER BY d.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	var deps []*Dependency
+	for rows.Next() {
+		dep := &Dependency{}
+		if err := rows.Scan(&dep.ID, &dep.BlockerID, &dep.BlockedID, &dep.AutoQueue, &dep.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan dependency: %w", err)
+		}
+		deps = append(deps, dep)
+	}
+	return deps, rows.Err()
+}
+
 // GetAllDependencies returns all dependencies for a given task (both blockers and blocked).
 func (db *DB) GetAllDependencies(taskID int64) (blockers []*Task, blockedBy []*Task, err error) {
 	blockers, err = db.GetBlockers(taskID)
@@ -1,7 +1,10 @@
 package db
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -73,6 +76,10 @@ func TestAddDependencySelfBlocking(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error when adding self-blocking dependency")
 	}
+	var cycleErr *DependencyCycleError
+	if !errors.As(err, &cycleErr) || len(cycleErr.Path) != 2 {
+		t.Errorf("expected a two-step self cycle, got %v", err)
+	}
 }
 
 func TestAddDependencyCycleDetection(t *testing.T) {
@@ -106,6 +113,28 @@ func TestAddDependencyCycleDetection(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error when creating a cycle")
 	}
+
+	// The error carries the loop the new dependency would close.
+	var cycleErr *DependencyCycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("expected *DependencyCycleError, got %T: %v", err, err)
+	}
+	want := []int64{task3.ID, task1.ID, task2.ID, task3.ID}
+	if fmt.Sprint(cycleErr.Path) != fmt.Sprint(want) {
+		t.Errorf("cycle path = %v, want %v", cycleErr.Path, want)
+	}
+	wantMsg := fmt.Sprintf("#%d → #%d → #%d → #%d", task3.ID, task1.ID, task2.ID, task3.ID)
+	if !strings.Contains(err.Error(), wantMsg) {
+		t.Errorf("error %q should list the cycle %q", err, wantMsg)
+	}
+	if dep, _ := db.GetDependency(task3.ID, task1.ID); dep != nil {
+		t.Error("cyclic dependency was inserted")
+	}
+
+	// An unrelated dependency into the chain is still fine.
+	if path, err := db.DependencyCyclePath(task1.ID, task3.ID); err != nil || path != nil {
+		t.Errorf("DependencyCyclePath(1, 3) = %v, %v; want no cycle", path, err)
+	}
 }
 
 func TestRemoveDependency(t *testing.T) {
@@ -1,5 +1,31 @@
 package db
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Event types written to the event log. The events package re-exports them as
+// its canonical list, with a description of each in events.Types.
+const (
+	EventTaskCreated       = "task.created"
+	EventTaskUpdated       = "task.updated"
+	EventTaskDeleted       = "task.deleted"
+	EventTaskStarted       = "task.started"
+	EventTaskWorktreeReady = "task.worktree_ready"
+	EventTaskBlocked       = "task.blocked"
+	EventTaskAuthRequired  = "task.auth_required"
+	EventTaskCompleted     = "task.completed"
+	EventTaskFailed        = "task.failed"
+	EventTaskMoved         = "task.moved"
+	EventTaskArchived      = "task.archived"
+	EventTaskUnarchived    = "task.unarchived"
+	EventTaskRestored      = "task.restored"
+	EventTaskDone          = "task.done" // legacy hook name for task.completed
+	EventRoutineFailed     = "routine.failed"
+)
+
 // EventEmitter is an interface for emitting task events.
 // This allows the DB to emit events without depending on the events package.
 type EventEmitter interface {
@@ -24,6 +50,97 @@ func (db *DB) recordEvent(eventType string, taskID int64, message string) {
 	`, eventType, taskID, message)
 }
 
+// TaskTransition is one lifecycle event read back from the event_log.
+type TaskTransition struct {
+	EventType string
+	TaskID    int64
+	Message   string
+	CreatedAt LocalTime
+}
+
+// ListTaskTransitions returns event_log entries of the given types recorded at
+// or after since, oldest first. With no types, every event type is returned.
+func (db *DB) ListTaskTransitions(since time.Time, eventTypes ...string) ([]TaskTransition, error) {
+	query := `SELECT event_type, COALESCE(task_id, 0), COALESCE(message, ''), created_at
+		FROM event_log WHERE datetime(created_at) >= datetime(?)`
+	args := []interface{}{since.UTC().Format("2006-01-02 15:04:05")}
+	if len(eventTypes) > 0 {
+		query += " AND event_type IN (?" + strings.Repeat(", ?", len(eventTypes)-1) + ")"
+		for _, t := range eventTypes {
+			args = append(args, t)
+		}
+	}
+	query += " ORDER BY id ASC"
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list transitions: %w", err)
+	}
+	defer rows.Close()
+
+	var transitions []TaskTransition
+	for rows.Next() {
+		var t TaskTransition
+		if err := rows.Scan(&t.EventType, &t.TaskID, &t.Message, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan transition: %w", err)
+		}
+		transitions = append(transitions, t)
+	}
+	return transitions, rows.Err()
+}
+
+// LoggedEvent is one row of the event_log, as read by pollers that follow the
+// log by id.
+type LoggedEvent struct {
+	ID        int64     `json:"id"`
+	EventType string    `json:"event_type"`
+	TaskID    int64     `json:"task_id"`
+	Message   string    `json:"message"`
+	Metadata  string    `json:"metadata"`
+	CreatedAt LocalTime `json:"created_at"`
+}
+
+// LatestEventID returns the id of the newest event_log row, or 0 when the log
+// is empty. Pollers start from here so they only see new events.
+func (db *DB) LatestEventID() (int64, error) {
+	var id int64
+	if err := db.QueryRow(`SELECT COALESCE(MAX(id), 0) FROM event_log`).Scan(&id); err != nil {
+		return 0, fmt.Errorf("latest event id: %w", err)
+	}
+	return id, nil
+}
+
+// ListEventsAfter returns event_log rows with an id greater than afterID,
+// oldest first. With no types, every event type is returned.
+func (db *DB) ListEventsAfter(afterID int64, eventTypes ...string) ([]LoggedEvent, error) {
+	query := `SELECT id, event_type, COALESCE(task_id, 0), COALESCE(message, ''), COALESCE(metadata, ''), created_at
+		FROM event_log WHERE id > ?`
+	args := []interface{}{afterID}
+	if len(eventTypes) > 0 {
+		query += " AND event_type IN (?" + strings.Repeat(", ?", len(eventTypes)-1) + ")"
+		for _, t := range eventTypes {
+			args = append(args, t)
+		}
+	}
+	query += " ORDER BY id ASC"
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []LoggedEvent
+	for rows.Next() {
+		var e LoggedEvent
+		if err := rows.Scan(&e.ID, &e.EventType, &e.TaskID, &e.Message, &e.Metadata, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan event: %w", err)
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
 // SetEventEmitter sets the event emitter for this database.
 // This is called by the executor to enable event emission.
 func (db *DB) SetEventEmitter(emitter EventEmitter) {
@@ -32,7 +149,7 @@ func (db *DB) SetEventEmitter(emitter EventEmitter) {
 
 // emitTaskCreated emits a task created event if an emitter is configured.
 func (db *DB) emitTaskCreated(task *Task) {
-	db.recordEvent("task.created", task.ID, task.Title)
+	db.recordEvent(EventTaskCreated, task.ID, task.Title)
 	if db.eventEmitter != nil {
 		db.eventEmitter.EmitTaskCreated(task)
 	}
@@ -40,7 +157,7 @@ func (db *DB) emitTaskCreated(task *Task) {
 
 // emitTaskUpdated emits a task updated event if an emitter is configured.
 func (db *DB) emitTaskUpdated(task *Task, changes map[string]interface{}) {
-	db.recordEvent("task.updated", task.ID, task.Title)
+	db.recordEvent(EventTaskUpdated, task.ID, task.Title)
 	if db.eventEmitter != nil {
 		db.eventEmitter.EmitTaskUpdated(task, changes)
 	}
@@ -48,7 +165,7 @@ func (db *DB) emitTaskUpdated(task *Task, changes map[string]interface{}) {
 
 // emitTaskDeleted emits a task deleted event if an emitter is configured.
 func (db *DB) emitTaskDeleted(taskID int64, title string) {
-	db.recordEvent("task.deleted", taskID, title)
+	db.recordEvent(EventTaskDeleted, taskID, title)
 	if db.eventEmitter != nil {
 		db.eventEmitter.EmitTaskDeleted(taskID, title)
 	}
@@ -56,7 +173,7 @@ func (db *DB) emitTaskDeleted(taskID int64, title string) {
 
 // emitTaskPinned emits a task pinned event if an emitter is configured.
 func (db *DB) emitTaskPinned(task *Task) {
-	db.recordEvent("task.updated", task.ID, "pinned")
+	db.recordEvent(EventTaskUpdated, task.ID, "pinned")
 	if db.eventEmitter != nil {
 		db.eventEmitter.EmitTaskPinned(task)
 	}
@@ -64,15 +181,20 @@ func (db *DB) emitTaskPinned(task *Task) {
 
 // emitTaskUnpinned emits a task unpinned event if an emitter is configured.
 func (db *DB) emitTaskUnpinned(task *Task) {
-	db.recordEvent("task.updated", task.ID, "unpinned")
+	db.recordEvent(EventTaskUpdated, task.ID, "unpinned")
 	if db.eventEmitter != nil {
 		db.eventEmitter.EmitTaskUnpinned(task)
 	}
 }
 
 // emitTaskBlocked emits a task blocked event if an emitter is configured.
+// reason is empty for plain status changes.
 func (db *DB) emitTaskBlocked(task *Task, reason string) {
-	db.recordEvent("task.blocked", task.ID, reason)
+	message := reason
+	if message == "" {
+		message = "status change"
+	}
+	db.recordEvent(EventTaskBlocked, task.ID, message)
 	if db.eventEmitter != nil {
 		db.eventEmitter.EmitTaskBlocked(task, reason)
 	}
@@ -80,7 +202,7 @@ func (db *DB) emitTaskBlocked(task *Task, reason string) {
 
 // emitTaskCompleted emits a task completed event if an emitter is configured.
 func (db *DB) emitTaskCompleted(task *Task) {
-	db.recordEvent("task.completed", task.ID, task.Title)
+	db.recordEvent(EventTaskCompleted, task.ID, task.Title)
 	if db.eventEmitter != nil {
 		db.eventEmitter.EmitTaskCompleted(task)
 	}
@@ -0,0 +1,400 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ExportSchemaVersion is the format version of Export documents. Bump it when
+// a field changes meaning so ImportData can refuse documents it can't read.
+const ExportSchemaVersion = 1
+
+// Export is a portable snapshot of the task database: everything needed to
+// move tasks between machines. Machine-local runtime state (worktree paths,
+// ports, Claude sessions, tmux windows) is deliberately left out.
+type Export struct {
+	SchemaVersion int                `json:"schema_version"`
+	ExportedAt    time.Time          `json:"exported_at"`
+	Projects      []ExportProject    `json:"projects"`
+	TaskTypes     []ExportTaskType   `json:"task_types"`
+	Tasks         []ExportTask       `json:"tasks"`
+	Dependencies  []ExportDependency `json:"dependencies"`
+}
+
+// ExportProject is a project in an Export.
+type ExportProject struct {
+	Name                  string          `json:"name"`
+	Path                  string          `json:"path"`
+	Aliases               string          `json:"aliases,omitempty"`
+	Instructions          string          `json:"instructions,omitempty"`
+	Actions               []ProjectAction `json:"actions,omitempty"`
+	Color                 string          `json:"color,omitempty"`
+	ClaudeConfigDir       string          `json:"claude_config_dir,omitempty"`
+	UseWorktrees          bool            `json:"use_worktrees"`
+	DefaultPermissionMode string          `json:"default_permission_mode,omitempty"`
+	Executor              string          `json:"executor,omitempty"`
+	DefaultModel          string          `json:"default_model,omitempty"`
+	BaseBranch            string          `json:"base_branch,omitempty"`
+	WIPLimit              int             `json:"wip_limit,omitempty"`
+	CreatedAt             LocalTime       `json:"created_at"`
+}
+
+// ExportTaskType is a task type in an Export.
+type ExportTaskType struct {
+	Name         string    `json:"name"`
+	Label        string    `json:"label"`
+	Instructions string    `json:"instructions,omitempty"`
+	SortOrder    int       `json:"sort_order"`
+	IsBuiltin    bool      `json:"is_builtin"`
+	CreatedAt    LocalTime `json:"created_at"`
+}
+
+// ExportTask is a task in an Export. IDs are preserved so dependencies and
+// references in task bodies ("see #42") still line up after import.
+type ExportTask struct {
+	ID             int64           `json:"id"`
+	Title          string          `json:"title"`
+	Body           string          `json:"body,omitempty"`
+	Status         string          `json:"status"`
+	Type           string          `json:"type,omitempty"`
+	Project        string          `json:"project"`
+	Executor       string          `json:"executor,omitempty"`
+	EffortLevel    string          `json:"effort_level,omitempty"`
+	Model          string          `json:"model,omitempty"`
+	EnvJSON        string          `json:"env,omitempty"`
+	ExecutorArgs   string          `json:"executor_args,omitempty"`
+	BranchName     string          `json:"branch_name,omitempty"`
+	SourceBranch   string          `json:"source_branch,omitempty"`
+	PRURL          string          `json:"pr_url,omitempty"`
+	PRNumber       int             `json:"pr_number,omitempty"`
+	PermissionMode string          `json:"permission_mode,omitempty"`
+	RemoteControl  bool            `json:"remote_control,omitempty"`
+	Pinned         bool            `json:"pinned,omitempty"`
+	Priority       int             `json:"priority,omitempty"`
+	Tags           string          `json:"tags,omitempty"`
+	Summary        string          `json:"summary,omitempty"`
+	Assignee       string          `json:"assignee,omitempty"`
+	CreatedAt      LocalTime       `json:"created_at"`
+	UpdatedAt      LocalTime       `json:"updated_at"`
+	StartedAt      *LocalTime      `json:"started_at,omitempty"`
+	CompletedAt    *LocalTime      `json:"completed_at,omitempty"`
+	Logs           []ExportTaskLog `json:"logs,omitempty"`
+}
+
+// ExportTaskLog is a task log line in an Export.
+type ExportTaskLog struct {
+	LineType  string    `json:"line_type"`
+	Content   string    `json:"content"`
+	CreatedAt LocalTime `json:"created_at"`
+}
+
+// ExportDependency is a blocker -> blocked relationship in an Export.
+type ExportDependency struct {
+	BlockerID int64     `json:"blocker_id"`
+	BlockedID int64     `json:"blocked_id"`
+	AutoQueue bool      `json:"auto_queue,omitempty"`
+	CreatedAt LocalTime `json:"created_at"`
+}
+
+// ImportResult counts what ImportData did.
+type ImportResult struct {
+	ProjectsCreated     int `json:"projects_created"`
+	TaskTypesCreated    int `json:"task_types_created"`
+	TasksCreated        int `json:"tasks_created"`
+	TasksUpdated        int `json:"tasks_updated"`
+	TasksSkipped        int `json:"tasks_skipped"`
+	DependenciesCreated int `json:"dependencies_created"`
+}
+
+// ExportData builds an Export of all projects, task types, tasks (open and
+// closed; trashed tasks are left out), and dependencies. Task logs are only
+// included when includeLogs is set since they dwarf everything else.
+func (db *DB) ExportData(includeLogs bool) (*Export, error) {
+	exp := &Export{SchemaVersion: ExportSchemaVersion, ExportedAt: time.Now()}
+
+	projects, err := db.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		exp.Projects = append(exp.Projects, ExportProject{
+			Name: p.Name, Path: p.Path, Aliases: p.Aliases, Instructions: p.Instructions,
+			Actions: p.Actions, Color: p.Color, ClaudeConfigDir: p.ClaudeConfigDir,
+			UseWorktrees: p.UseWorktrees, DefaultPermissionMode: p.DefaultPermissionMode,
+			Executor: p.Executor, DefaultModel: p.DefaultModel, BaseBranch: p.BaseBranch, WIPLimit: p.WIPLimit, CreatedAt: p.CreatedAt,
+		})
+	}
+
+	types, err := db.ListTaskTypes()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range types {
+		exp.TaskTypes = append(exp.TaskTypes, ExportTaskType{
+			Name: t.Name, Label: t.Label, Instructions: t.Instructions,
+			SortOrder: t.SortOrder, IsBuiltin: t.IsBuiltin, CreatedAt: t.CreatedAt,
+		})
+	}
+
+	tasks, err := db.ListTasks(ListTasksOptions{IncludeClosed: true, Limit: 100000})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	exported := make(map[int64]bool, len(tasks))
+	for _, t := range tasks {
+		exported[t.ID] = true
+		et := exportTask(t)
+		if includeLogs {
+			if et.Logs, err = db.exportTaskLogs(t.ID); err != nil {
+				return nil, err
+			}
+		}
+		exp.Tasks = append(exp.Tasks, et)
+	}
+
+	deps, err := db.exportDependencies()
+	if err != nil {
+		return nil, err
+	}
+	// Only keep edges whose ends are both in the export, so an import never
+	// references a task it doesn't have.
+	for _, d := range deps {
+		if exported[d.BlockerID] && exported[d.BlockedID] {
+			exp.Dependencies = append(exp.Dependencies, d)
+		}
+	}
+	return exp, nil
+}
+
+// exportTask converts a task to its export form, without logs.
+func exportTask(t *Task) ExportTask {
+	return ExportTask{
+		ID: t.ID, Title: t.Title, Body: t.Body, Status: t.Status, Type: t.Type,
+		Project: t.Project, Executor: t.Executor, EffortLevel: t.EffortLevel,
+		Model: t.Model, EnvJSON: t.EnvJSON, ExecutorArgs: t.ExecutorArgs, BranchName: t.BranchName,
+		SourceBranch: t.SourceBranch, PRURL: t.PRURL, PRNumber: t.PRNumber,
+		PermissionMode: t.PermissionMode, RemoteControl: t.RemoteControl,
+		Pinned: t.Pinned, Priority: t.Priority, Tags: t.Tags, Summary: t.Summary, Assignee: t.Assignee,
+		CreatedAt: t.CreatedAt, UpdatedAt: t.UpdatedAt,
+		StartedAt: t.StartedAt, CompletedAt: t.CompletedAt,
+	}
+}
+
+func (db *DB) exportTaskLogs(taskID int64) ([]ExportTaskLog, error) {
+	rows, err := db.Query(`
+		SELECT line_type, content, created_at FROM task_logs
+		WHERE task_id = ? ORDER BY id
+	`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("query task logs: %w", err)
+	}
+	defer rows.Close()
+
+	var logs []ExportTaskLog
+	for rows.Next() {
+		var l ExportTaskLog
+		if err := rows.Scan(&l.LineType, &l.Content, &l.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan task log: %w", err)
+		}
+		logs = append(logs, l)
+	}
+	return logs, rows.Err()
+}
+
+func (db *DB) exportDependencies() ([]ExportDependency, error) {
+	// Dependencies on trashed tasks stay behind with the tasks themselves.
+	rows, err := db.Query(`
+		SELECT d.blocker_id, d.blocked_id, COALESCE(d.auto_queue, 0), d.created_at
+		FROM task_dependencies d
+		JOIN tasks blocker ON blocker.id = d.blocker_id AND blocker.deleted_at IS NULL
+		JOIN tasks blocked ON blocked.id = d.blocked_id AND blocked.deleted_at IS NULL
+		ORDER BY d.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	var deps []ExportDependency
+	for rows.Next() {
+		var d ExportDependency
+		if err := rows.Scan(&d.BlockerID, &d.BlockedID, &d.AutoQueue, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan dependency: %w", err)
+		}
+		deps = append(deps, d)
+	}
+	return deps, rows.Err()
+}
+
+// ImportData loads an Export, keeping task IDs. It is idempotent: projects
+// and task types that already exist (by name) are left alone, and tasks whose
+// ID already exists are skipped unless overwrite is set, in which case they
+// are updated in place (and their logs replaced, if the export has logs).
+// Everything happens in one transaction, and no task events or hooks fire.
+func (db *DB) ImportData(exp *Export, overwrite bool) (*ImportResult, error) {
+	if exp.SchemaVersion < 1 || exp.SchemaVersion > ExportSchemaVersion {
+		return nil, fmt.Errorf("unsupported export schema_version %d (this version of ty reads up to %d)", exp.SchemaVersion, ExportSchemaVersion)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin import: %w", err)
+	}
+	defer tx.Rollback()
+
+	res := &ImportResult{}
+	if err := importProjects(tx, exp.Projects, res); err != nil {
+		return nil, err
+	}
+	if err := importTaskTypes(tx, exp.TaskTypes, res); err != nil {
+		return nil, err
+	}
+	if err := importTasks(tx, exp.Tasks, overwrite, res); err != nil {
+		return nil, err
+	}
+	if err := importDependencies(tx, exp.Dependencies, res); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit import: %w", err)
+	}
+	return res, nil
+}
+
+func importProjects(tx *sql.Tx, projects []ExportProject, res *ImportResult) error {
+	for _, p := range projects {
+		var exists int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM projects WHERE name = ?`, p.Name).Scan(&exists); err != nil {
+			return fmt.Errorf("check project %s: %w", p.Name, err)
+		}
+		if exists > 0 {
+			continue
+		}
+		actionsJSON, _ := json.Marshal(p.Actions)
+		if _, err := tx.Exec(`
+			INSERT INTO projects (name, path, aliases, instructions, actions, color, claude_config_dir, use_worktrees, default_permission_mode, default_executor, default_model, base_branch, wip_limit, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, COALESCE(?, CURRENT_TIMESTAMP))
+		`, p.Name, p.Path, p.Aliases, p.Instructions, string(actionsJSON), p.Color, p.ClaudeConfigDir,
+			boolToInt(p.UseWorktrees), NormalizePermissionMode(p.DefaultPermissionMode), p.Executor, p.DefaultModel, p.BaseBranch, p.WIPLimit, sqlTime(&p.CreatedAt)); err != nil {
+			return fmt.Errorf("insert project %s: %w", p.Name, err)
+		}
+		res.ProjectsCreated++
+	}
+	return nil
+}
+
+func importTaskTypes(tx *sql.Tx, types []ExportTaskType, res *ImportResult) error {
+	for _, t := range types {
+		result, err := tx.Exec(`
+			INSERT OR IGNORE INTO task_types (name, label, instructions, sort_order, is_builtin, created_at)
+			VALUES (?, ?, ?, ?, ?, COALESCE(?, CURRENT_TIMESTAMP))
+		`, t.Name, t.Label, t.Instructions, t.SortOrder, t.IsBuiltin, sqlTime(&t.CreatedAt))
+		if err != nil {
+			return fmt.Errorf("insert task type %s: %w", t.Name, err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			res.TaskTypesCreated++
+		}
+	}
+	return nil
+}
+
+func importTasks(tx *sql.Tx, tasks []ExportTask, overwrite bool, res *ImportResult) error {
+	for _, t := range tasks {
+		var exists int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM tasks WHERE id = ?`, t.ID).Scan(&exists); err != nil {
+			return fmt.Errorf("check task #%d: %w", t.ID, err)
+		}
+		if exists > 0 && !overwrite {
+			res.TasksSkipped++
+			continue
+		}
+
+		mode := NormalizePermissionMode(t.PermissionMode)
+		args := []interface{}{
+			t.Title, t.Body, t.Status, t.Type, t.Project, t.Executor, t.EffortLevel, t.Model, t.EnvJSON, t.ExecutorArgs,
+			t.BranchName, t.SourceBranch, t.PRURL, t.PRNumber, mode, mode == PermissionModeDangerous, t.RemoteControl,
+			t.Pinned, t.Priority, t.Tags, t.Summary, t.Assignee,
+			sqlTime(&t.CreatedAt), sqlTime(&t.UpdatedAt), sqlTime(t.StartedAt), sqlTime(t.CompletedAt),
+			t.ID,
+		}
+		if exists > 0 {
+			// An overwritten task is "restored" too if it had been trashed here.
+			if _, err := tx.Exec(`
+				UPDATE tasks SET
+					title = ?, body = ?, status = ?, type = ?, project = ?, executor = ?, effort_level = ?, model = ?, env = ?, executor_args = ?,
+					branch_name = ?, source_branch = ?, pr_url = ?, pr_number = ?, permission_mode = ?, dangerous_mode = ?, remote_control = ?,
+					pinned = ?, priority = ?, tags = ?, summary = ?, assignee = ?,
+					created_at = COALESCE(?, created_at), updated_at = COALESCE(?, updated_at), started_at = ?, completed_at = ?,
+					deleted_at = NULL
+				WHERE id = ?
+			`, args...); err != nil {
+				return fmt.Errorf("update task #%d: %w", t.ID, err)
+			}
+			res.TasksUpdated++
+		} else {
+			if _, err := tx.Exec(`
+				INSERT INTO tasks (
+					title, body, status, type, project, executor, effort_level, model, env, executor_args,
+					branch_name, source_branch, pr_url, pr_number, permission_mode, dangerous_mode, remote_control,
+					pinned, priority, tags, summary, assignee,
+					created_at, updated_at, started_at, completed_at, id
+				) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?,
+					COALESCE(?, CURRENT_TIMESTAMP), COALESCE(?, CURRENT_TIMESTAMP), ?, ?, ?)
+			`, args...); err != nil {
+				return fmt.Errorf("insert task #%d: %w", t.ID, err)
+			}
+			res.TasksCreated++
+		}
+
+		if len(t.Logs) == 0 {
+			continue
+		}
+		if _, err := tx.Exec(`DELETE FROM task_logs WHERE task_id = ?`, t.ID); err != nil {
+			return fmt.Errorf("clear logs for task #%d: %w", t.ID, err)
+		}
+		for _, l := range t.Logs {
+			if _, err := tx.Exec(`
+				INSERT INTO task_logs (task_id, line_type, content, created_at)
+				VALUES (?, ?, ?, COALESCE(?, CURRENT_TIMESTAMP))
+			`, t.ID, l.LineType, l.Content, sqlTime(&l.CreatedAt)); err != nil {
+				return fmt.Errorf("insert log for task #%d: %w", t.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func importDependencies(tx *sql.Tx, deps []ExportDependency, res *ImportResult) error {
+	for _, d := range deps {
+		// Only link tasks that exist here; a partial import shouldn't fail on
+		// the foreign keys of tasks it didn't bring over.
+		result, err := tx.Exec(`
+			INSERT OR IGNORE INTO task_dependencies (blocker_id, blocked_id, auto_queue, created_at)
+			SELECT ?, ?, ?, COALESCE(?, CURRENT_TIMESTAMP)
+			WHERE EXISTS (SELECT 1 FROM tasks WHERE id = ?) AND EXISTS (SELECT 1 FROM tasks WHERE id = ?)
+		`, d.BlockerID, d.BlockedID, d.AutoQueue, sqlTime(&d.CreatedAt), d.BlockerID, d.BlockedID)
+		if err != nil {
+			return fmt.Errorf("insert dependency %d -> %d: %w", d.BlockerID, d.BlockedID, err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			res.DependenciesCreated++
+		}
+	}
+	return nil
+}
+
+// sqlTime formats a timestamp the way SQLite's CURRENT_TIMESTAMP stores it
+// (UTC, second precision) so imported rows compare and scan like native ones.
+// A nil or zero time is NULL.
+func sqlTime(t *LocalTime) interface{} {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,176 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	src := setupTestDB(t)
+	defer src.Close()
+
+	if err := src.CreateProject(&Project{Name: "app", Path: "/work/app", Aliases: "a", UseWorktrees: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.CreateTaskType(&TaskType{Name: "research", Label: "Research", Instructions: "Dig in", SortOrder: 9}); err != nil {
+		t.Fatal(err)
+	}
+	blocker := &Task{Title: "blocker", Body: "first", Status: StatusBacklog, Type: "research", Project: "app", Tags: "x,y"}
+	blocked := &Task{Title: "blocked", Status: StatusBacklog, Type: TypeCode, Project: "app"}
+	trashed := &Task{Title: "trashed", Status: StatusBacklog, Project: "app"}
+	for _, task := range []*Task{blocker, blocked, trashed} {
+		if err := src.CreateTask(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := src.UpdateTaskStatus(blocker.ID, StatusDone); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.AddDependency(blocker.ID, blocked.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.AppendTaskLog(blocker.ID, "output", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.SoftDeleteTask(trashed.ID); err != nil {
+		t.Fatal(err)
+	}
+	original, _ := src.GetTask(blocker.ID)
+
+	exp, err := src.ExportData(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp.SchemaVersion != ExportSchemaVersion || len(exp.Tasks) != 2 || len(exp.Dependencies) != 1 {
+		t.Fatalf("unexpected export: version %d, %d tasks, %d deps", exp.SchemaVersion, len(exp.Tasks), len(exp.Dependencies))
+	}
+
+	// Go through JSON, as the CLI does.
+	data, err := json.Marshal(exp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Export
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := setupTestDB(t)
+	defer dst.Close()
+	res, err := dst.ImportData(&decoded, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TasksCreated != 2 || res.ProjectsCreated != 1 || res.TaskTypesCreated != 1 || res.DependenciesCreated != 1 {
+		t.Errorf("unexpected import result: %+v", res)
+	}
+
+	got, err := dst.GetTask(blocker.ID)
+	if err != nil || got == nil {
+		t.Fatalf("imported task missing: %v", err)
+	}
+	if got.Title != "blocker" || got.Body != "first" || got.Status != StatusDone || got.Tags != "x,y" || got.Project != "app" {
+		t.Errorf("unexpected imported task: %+v", got)
+	}
+	if !got.CreatedAt.Equal(original.CreatedAt.Time) {
+		t.Errorf("created_at = %v, want %v", got.CreatedAt, original.CreatedAt)
+	}
+	if got.CompletedAt == nil || original.CompletedAt == nil || !got.CompletedAt.Equal(original.CompletedAt.Time) {
+		t.Errorf("completed_at = %v, want %v", got.CompletedAt, original.CompletedAt)
+	}
+	if blockers, _ := dst.GetBlockers(blocked.ID); len(blockers) != 1 || blockers[0].ID != blocker.ID {
+		t.Errorf("expected dependency to be imported, got %v", blockers)
+	}
+	if logs, _ := dst.GetTaskLogs(blocker.ID, 0); len(logs) != 1 || logs[0].Content != "hello" {
+		t.Errorf("expected the log to be imported, got %v", logs)
+	}
+	if p, _ := dst.GetProjectByName("a"); p == nil || p.Path != "/work/app" {
+		t.Errorf("expected project alias to resolve, got %+v", p)
+	}
+
+	// A new task created after import must not collide with imported IDs.
+	fresh := &Task{Title: "fresh", Status: StatusBacklog, Project: "app"}
+	if err := dst.CreateTask(fresh); err != nil {
+		t.Fatal(err)
+	}
+	if fresh.ID <= blocked.ID {
+		t.Errorf("new task ID %d reuses an imported ID", fresh.ID)
+	}
+}
+
+func TestImportIsIdempotent(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	task := &Task{Title: "keep me", Status: StatusBacklog, Project: "personal"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	exp, err := database.ExportData(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp.Tasks[0].Title = "changed"
+	res, err := database.ImportData(exp, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TasksSkipped != 1 || res.TasksCreated != 0 {
+		t.Errorf("expected the existing task to be skipped, got %+v", res)
+	}
+	if got, _ := database.GetTask(task.ID); got.Title != "keep me" {
+		t.Errorf("title = %q, want it untouched", got.Title)
+	}
+
+	res, err = database.ImportData(exp, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TasksUpdated != 1 {
+		t.Errorf("expected the task to be overwritten, got %+v", res)
+	}
+	if got, _ := database.GetTask(task.ID); got.Title != "changed" {
+		t.Errorf("title = %q, want it overwritten", got.Title)
+	}
+}
+
+func TestImportRejectsNewerSchema(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	_, err := database.ImportData(&Export{SchemaVersion: ExportSchemaVersion + 1, ExportedAt: time.Now()}, false)
+	if err == nil {
+		t.Fatal("expected an error for a newer schema_version")
+	}
+}
+
+func TestExportIncludesEveryTask(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	var ids []int64
+	for i := 0; i < 150; i++ {
+		task := &Task{Title: fmt.Sprintf("task %d", i), Status: StatusBacklog}
+		if err := database.CreateTask(task); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, task.ID)
+	}
+	if err := database.AddDependency(ids[0], ids[149], false); err != nil {
+		t.Fatal(err)
+	}
+
+	exp, err := database.ExportData(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exp.Tasks) != 150 {
+		t.Errorf("exported %d tasks, want 150", len(exp.Tasks))
+	}
+	if len(exp.Dependencies) != 1 {
+		t.Errorf("exported %d dependencies, want 1", len(exp.Dependencies))
+	}
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"fmt"
+)
+
+// TaskIssue links a task to the GitHub issue it was imported from
+// (`ty import-issue`).
+type TaskIssue struct {
+	URL string
+	// LinkBack asks the daemon to comment on the issue when the task is
+	// completed. It is cleared once the comment is posted.
+	LinkBack bool
+}
+
+// SetTaskIssue records the GitHub issue a task was imported from.
+func (db *DB) SetTaskIssue(taskID int64, url string, linkBack bool) error {
+	if _, err := db.Exec(`UPDATE tasks SET issue_url = ?, issue_link_back = ? WHERE id = ?`, url, linkBack, taskID); err != nil {
+		return fmt.Errorf("set task issue: %w", err)
+	}
+	return nil
+}
+
+// GetTaskIssue returns the GitHub issue linked to a task, or nil if it has
+// none.
+func (db *DB) GetTaskIssue(taskID int64) (*TaskIssue, error) {
+	issue := &TaskIssue{}
+	err := db.QueryRow(`
+		SELECT COALESCE(issue_url, ''), COALESCE(issue_link_back, 0)
+		FROM tasks WHERE id = ?
+	`, taskID).Scan(&issue.URL, &issue.LinkBack)
+	if err != nil {
+		return nil, fmt.Errorf("get task issue: %w", err)
+	}
+	if issue.URL == "" {
+		return nil, nil
+	}
+	return issue, nil
+}
+
+// FindTaskByIssue returns the ID of the live task imported from url, or 0.
+func (db *DB) FindTaskByIssue(url string) (int64, error) {
+	var id int64
+	err := db.QueryRow(`
+		SELECT COALESCE(MAX(id), 0) FROM tasks
+		WHERE issue_url = ? AND deleted_at IS NULL
+	`, url).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("find task by issue: %w", err)
+	}
+	return id, nil
+}
+
+// ClearIssueLinkBack marks a task's issue comment as posted.
+func (db *DB) ClearIssueLinkBack(taskID int64) error {
+	if _, err := db.Exec(`UPDATE tasks SET issue_link_back = 0 WHERE id = ?`, taskID); err != nil {
+		return fmt.Errorf("clear issue link back: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package db
+
+import "testing"
+
+func TestTaskIssueLink(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	task := &Task{Title: "Fix login", Status: StatusBacklog}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	if issue, err := database.GetTaskIssue(task.ID); err != nil || issue != nil {
+		t.Fatalf("new task: got %+v, %v; want no issue", issue, err)
+	}
+
+	url := "https://github.com/o/r/issues/7"
+	if err := database.SetTaskIssue(task.ID, url, true); err != nil {
+		t.Fatal(err)
+	}
+	issue, err := database.GetTaskIssue(task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue == nil || issue.URL != url || !issue.LinkBack {
+		t.Fatalf("GetTaskIssue = %+v", issue)
+	}
+	if id, _ := database.FindTaskByIssue(url); id != task.ID {
+		t.Errorf("FindTaskByIssue = %d, want %d", id, task.ID)
+	}
+	if id, _ := database.FindTaskByIssue("https://github.com/o/r/issues/8"); id != 0 {
+		t.Errorf("FindTaskByIssue for another issue = %d, want 0", id)
+	}
+
+	if err := database.ClearIssueLinkBack(task.ID); err != nil {
+		t.Fatal(err)
+	}
+	if issue, _ := database.GetTaskIssue(task.ID); issue == nil || issue.LinkBack {
+		t.Errorf("after ClearIssueLinkBack: %+v", issue)
+	}
+}
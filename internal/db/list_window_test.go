@@ -0,0 +1,87 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestListTasksTimeWindow(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	now := time.Now().UTC()
+	stamp := func(d time.Duration) string { return now.Add(-d).Format("2006-01-02 15:04:05") }
+
+	old := &Task{Title: "old backlog", Status: StatusBacklog}
+	recent := &Task{Title: "recent backlog", Status: StatusBacklog}
+	oldDone := &Task{Title: "created long ago, finished recently", Status: StatusBacklog}
+	for _, task := range []*Task{old, recent, oldDone} {
+		if err := database.CreateTask(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	database.Exec(`UPDATE tasks SET created_at = ? WHERE id = ?`, stamp(30*24*time.Hour), old.ID)
+	database.Exec(`UPDATE tasks SET created_at = ? WHERE id = ?`, stamp(2*time.Hour), recent.ID)
+	database.Exec(`UPDATE tasks SET status = 'done', created_at = ?, completed_at = ? WHERE id = ?`,
+		stamp(30*24*time.Hour), stamp(time.Hour), oldDone.ID)
+
+	ids := func(opts ListTasksOptions) map[int64]bool {
+		t.Helper()
+		tasks, err := database.ListTasks(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := map[int64]bool{}
+		for _, task := range tasks {
+			got[task.ID] = true
+		}
+		return got
+	}
+
+	got := ids(ListTasksOptions{IncludeClosed: true, Since: now.Add(-7 * 24 * time.Hour)})
+	if !got[recent.ID] || !got[oldDone.ID] || got[old.ID] {
+		t.Errorf("since 7d: got %v, want recent #%d and finished #%d only", got, recent.ID, oldDone.ID)
+	}
+
+	got = ids(ListTasksOptions{Status: StatusDone, Since: now.Add(-3 * time.Hour)})
+	if len(got) != 1 || !got[oldDone.ID] {
+		t.Errorf("done since 3h should match on completed_at: got %v", got)
+	}
+
+	got = ids(ListTasksOptions{IncludeClosed: true, Until: now.Add(-24 * time.Hour)})
+	if len(got) != 1 || !got[old.ID] {
+		t.Errorf("until 24h ago: got %v, want only #%d", got, old.ID)
+	}
+}
+
+func TestListTasksUpdatedBeforeBoundary(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	task := &Task{Title: "stale", Status: StatusBacklog}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	updated := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	database.Exec(`UPDATE tasks SET updated_at = ? WHERE id = ?`, updated.Format("2006-01-02 15:04:05"), task.ID)
+
+	count := func(before time.Time) int {
+		t.Helper()
+		tasks, err := database.ListTasks(ListTasksOptions{UpdatedBefore: before})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(tasks)
+	}
+
+	// The bound is exclusive, and is compared in UTC whatever zone it comes in.
+	if n := count(updated); n != 0 {
+		t.Errorf("updated before its own timestamp: got %d tasks, want 0", n)
+	}
+	if n := count(updated.Add(time.Second)); n != 1 {
+		t.Errorf("updated before a second later: got %d tasks, want 1", n)
+	}
+	if n := count(updated.Add(time.Second).In(time.FixedZone("UTC-5", -5*3600))); n != 1 {
+		t.Errorf("bound in another zone: got %d tasks, want 1", n)
+	}
+}
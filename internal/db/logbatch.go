@@ -0,0 +1,142 @@
+package db
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultLogBatchWindow is how long a LogBatcher waits for more appends before
+// writing what it has. Short enough that the TUI log view never looks stale.
+const DefaultLogBatchWindow = 100 * time.Millisecond
+
+// DefaultLogBatchSize is how many pending rows trigger an immediate flush.
+const DefaultLogBatchSize = 64
+
+// LogBatcher coalesces task log appends into a single transaction per window.
+// Under heavy tool use the executor emits bursts of log lines; writing each in
+// its own implicit transaction contends with status updates for the SQLite
+// write lock. Rows are written in the order Append was called. Call Stop on
+// shutdown so nothing buffered is lost.
+//
+// `ty claude-hook` runs as a fresh process per hook event; it uses
+// BatchTaskLogs so the rows one invocation writes (the tool or pending_tool
+// entry plus any status notes) go in as a single transaction on exit.
+type LogBatcher struct {
+	db       *DB
+	window   time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	pending []TaskLog
+	timer   *time.Timer
+	stopped bool
+
+	// flushMu serializes flushes so two concurrent batches can't interleave
+	// and reorder rows.
+	flushMu sync.Mutex
+}
+
+// NewLogBatcher creates a batcher writing to db. Non-positive window or
+// maxBatch fall back to DefaultLogBatchWindow and DefaultLogBatchSize.
+func NewLogBatcher(db *DB, window time.Duration, maxBatch int) *LogBatcher {
+	if window <= 0 {
+		window = DefaultLogBatchWindow
+	}
+	if maxBatch <= 0 {
+		maxBatch = DefaultLogBatchSize
+	}
+	return &LogBatcher{db: db, window: window, maxBatch: maxBatch}
+}
+
+// Append queues a log row. It flushes synchronously once the batch is full and
+// otherwise schedules a flush at the end of the window. After Stop, rows are
+// written directly.
+func (b *LogBatcher) Append(taskID int64, lineType, content string) error {
+	b.mu.Lock()
+	if b.stopped {
+		b.mu.Unlock()
+		return b.db.appendTaskLog(taskID, lineType, content)
+	}
+	b.pending = append(b.pending, TaskLog{TaskID: taskID, LineType: lineType, Content: content})
+	full := len(b.pending) >= b.maxBatch
+	if !full && b.timer == nil {
+		b.timer = time.AfterFunc(b.window, func() { b.Flush() })
+	}
+	b.mu.Unlock()
+
+	if full {
+		return b.Flush()
+	}
+	return nil
+}
+
+// Flush writes all pending rows in one transaction. On failure the rows are
+// put back at the front of the queue so a later flush retries them in order.
+func (b *LogBatcher) Flush() error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	batch := b.pending
+	b.pending = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+	if err := b.db.insertTaskLogs(batch); err != nil {
+		b.mu.Lock()
+		b.pending = append(batch, b.pending...)
+		if !b.stopped && b.timer == nil {
+			b.timer = time.AfterFunc(b.window, func() { b.Flush() })
+		}
+		b.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Stop flushes anything buffered and switches the batcher to direct writes.
+func (b *LogBatcher) Stop() error {
+	b.mu.Lock()
+	b.stopped = true
+	b.mu.Unlock()
+	return b.Flush()
+}
+
+// BatchTaskLogs makes AppendTaskLog queue rows on a LogBatcher with the
+// default window and size instead of inserting each one, and returns it. Call
+// its Stop before closing db; after Stop, AppendTaskLog writes directly again.
+func (db *DB) BatchTaskLogs() *LogBatcher {
+	db.logBatcher = NewLogBatcher(db, DefaultLogBatchWindow, DefaultLogBatchSize)
+	return db.logBatcher
+}
+
+// insertTaskLogs writes logs in a single transaction, preserving slice order.
+func (db *DB) insertTaskLogs(logs []TaskLog) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin task log batch: %w", err)
+	}
+	stmt, err := tx.Prepare(`INSERT INTO task_logs (task_id, line_type, content) VALUES (?, ?, ?)`)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("prepare task log batch: %w", err)
+	}
+	defer stmt.Close()
+	for _, l := range logs {
+		if _, err := stmt.Exec(l.TaskID, l.LineType, l.Content); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("insert task log: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit task log batch: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package db
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newLogBatchTestDB(t *testing.T) (*DB, *Task) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	database, err := Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	task := &Task{Title: "Batch", Status: StatusProcessing, Type: TypeCode, Project: "personal"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+	return database, task
+}
+
+// logContents returns the task's log contents oldest first.
+func logContents(t *testing.T, database *DB, taskID int64) []string {
+	t.Helper()
+	logs, err := database.GetTaskLogsSince(taskID, 0)
+	if err != nil {
+		t.Fatalf("failed to get logs: %v", err)
+	}
+	var out []string
+	for _, l := range logs {
+		out = append(out, l.Content)
+	}
+	return out
+}
+
+func TestLogBatcherPreservesOrderAcrossBatches(t *testing.T) {
+	database, task := newLogBatchTestDB(t)
+
+	// A long window means only size-triggered flushes and Stop write rows.
+	b := NewLogBatcher(database, time.Hour, 4)
+	for i := 0; i < 10; i++ {
+		if err := b.Append(task.ID, "tool", fmt.Sprintf("line %d", i)); err != nil {
+			t.Fatalf("append %d: %v", i, err)
+		}
+	}
+
+	// Two full batches of 4 are written; the last 2 are still buffered.
+	if got := logContents(t, database, task.ID); len(got) != 8 {
+		t.Fatalf("expected 8 rows after size flushes, got %d", len(got))
+	}
+
+	if err := b.Stop(); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	got := logContents(t, database, task.ID)
+	if len(got) != 10 {
+		t.Fatalf("expected 10 rows after stop, got %d", len(got))
+	}
+	for i, c := range got {
+		if want := fmt.Sprintf("line %d", i); c != want {
+			t.Errorf("row %d: expected %q, got %q", i, want, c)
+		}
+	}
+}
+
+func TestLogBatcherStopFlushesPending(t *testing.T) {
+	database, task := newLogBatchTestDB(t)
+
+	b := NewLogBatcher(database, time.Hour, 100)
+	for _, c := range []string{"a", "b", "c"} {
+		b.Append(task.ID, "tool", c)
+	}
+	if got := logContents(t, database, task.ID); len(got) != 0 {
+		t.Fatalf("expected nothing written before Stop, got %v", got)
+	}
+
+	if err := b.Stop(); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if got := logContents(t, database, task.ID); len(got) != 3 {
+		t.Fatalf("expected 3 rows after Stop, got %v", got)
+	}
+
+	// After Stop, appends are written straight away.
+	b.Append(task.ID, "tool", "d")
+	if got := logContents(t, database, task.ID); len(got) != 4 {
+		t.Errorf("expected a direct write after Stop, got %v", got)
+	}
+}
+
+func TestBatchTaskLogsBuffersAppendTaskLog(t *testing.T) {
+	database, task := newLogBatchTestDB(t)
+
+	b := database.BatchTaskLogs()
+	database.AppendTaskLog(task.ID, "pending_tool", "Bash: ls")
+	database.AppendTaskLog(task.ID, "system", "Agent resumed working")
+	b.mu.Lock()
+	queued := len(b.pending)
+	b.mu.Unlock()
+	if queued == 0 && len(logContents(t, database, task.ID)) != 2 {
+		t.Fatalf("expected AppendTaskLog to go through the batcher")
+	}
+
+	if err := b.Stop(); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	got := logContents(t, database, task.ID)
+	if len(got) != 2 || got[0] != "Bash: ls" || got[1] != "Agent resumed working" {
+		t.Errorf("expected both rows in order after Stop, got %v", got)
+	}
+}
+
+func TestLogBatcherFlushesOnTimer(t *testing.T) {
+	database, task := newLogBatchTestDB(t)
+
+	b := NewLogBatcher(database, 10*time.Millisecond, 100)
+	defer b.Stop()
+	b.Append(task.ID, "output", "hello")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if len(logContents(t, database, task.ID)) == 1 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("expected timer to flush the pending row")
+}
+
+func TestLogBatcherAppendAfterStopWritesDirectly(t *testing.T) {
+	database, task := newLogBatchTestDB(t)
+
+	b := NewLogBatcher(database, time.Hour, 100)
+	b.Append(task.ID, "output", "before")
+	if err := b.Stop(); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if err := b.Append(task.ID, "output", "after"); err != nil {
+		t.Fatalf("append after stop: %v", err)
+	}
+
+	got := logContents(t, database, task.ID)
+	if len(got) != 2 || got[0] != "before" || got[1] != "after" {
+		t.Errorf("expected [before after], got %v", got)
+	}
+}
+
+func TestLogBatcherConcurrentAppends(t *testing.T) {
+	database, task := newLogBatchTestDB(t)
+
+	b := NewLogBatcher(database, 5*time.Millisecond, 16)
+	const writers, perWriter = 8, 50
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				if err := b.Append(task.ID, "tool", fmt.Sprintf("w%d-%d", w, i)); err != nil {
+					t.Errorf("append: %v", err)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	if err := b.Stop(); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+
+	got := logContents(t, database, task.ID)
+	if len(got) != writers*perWriter {
+		t.Fatalf("expected %d rows, got %d", writers*perWriter, len(got))
+	}
+
+	// Each writer's own rows must land in the order it appended them.
+	next := make(map[int]int)
+	for _, c := range got {
+		var w, i int
+		fmt.Sscanf(c, "w%d-%d", &w, &i)
+		if i != next[w] {
+			t.Fatalf("writer %d: expected row %d, got %d", w, next[w], i)
+		}
+		next[w]++
+	}
+}
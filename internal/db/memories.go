@@ -0,0 +1,134 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Memory categories. Anything else is rejected so the prompt section and
+// 'ty memories list --category' stay predictable.
+const (
+	MemoryCategoryPattern  = "pattern"  // coding conventions and idioms to follow
+	MemoryCategoryContext  = "context"  // background on the project or domain
+	MemoryCategoryDecision = "decision" // architectural choices and their reasons
+	MemoryCategoryGotcha   = "gotcha"   // pitfalls, quirks, things that broke before
+	MemoryCategoryGeneral  = "general"  // everything else
+)
+
+// MemoryCategories returns the valid memory categories in display order.
+func MemoryCategories() []string {
+	return []string{
+		MemoryCategoryPattern,
+		MemoryCategoryContext,
+		MemoryCategoryDecision,
+		MemoryCategoryGotcha,
+		MemoryCategoryGeneral,
+	}
+}
+
+// IsValidMemoryCategory reports whether category is one of MemoryCategories.
+func IsValidMemoryCategory(category string) bool {
+	for _, c := range MemoryCategories() {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// ProjectMemory is a short note about a project that is shared with every
+// task run in it.
+type ProjectMemory struct {
+	ID        int64     `json:"id"`
+	Project   string    `json:"project"`
+	Category  string    `json:"category"`
+	Content   string    `json:"content"`
+	CreatedAt LocalTime `json:"created_at"`
+	UpdatedAt LocalTime `json:"updated_at"`
+}
+
+// CreateProjectMemory stores a new memory. An empty category is stored as
+// general.
+func (db *DB) CreateProjectMemory(m *ProjectMemory) error {
+	if m.Category == "" {
+		m.Category = MemoryCategoryGeneral
+	}
+	if !IsValidMemoryCategory(m.Category) {
+		return fmt.Errorf("invalid memory category %q (valid: %s)", m.Category, strings.Join(MemoryCategories(), ", "))
+	}
+	if strings.TrimSpace(m.Content) == "" {
+		return fmt.Errorf("memory content is empty")
+	}
+	result, err := db.Exec(`
+		INSERT INTO project_memories (project, category, content)
+		VALUES (?, ?, ?)
+	`, m.Project, m.Category, m.Content)
+	if err != nil {
+		return fmt.Errorf("insert project memory: %w", err)
+	}
+	id, _ := result.LastInsertId()
+	m.ID = id
+	now := LocalTime{Time: time.Now()}
+	m.CreatedAt, m.UpdatedAt = now, now
+	return nil
+}
+
+// ListProjectMemories returns a project's memories, oldest first. A non-empty
+// category limits the result to that category.
+func (db *DB) ListProjectMemories(project, category string) ([]*ProjectMemory, error) {
+	query := `
+		SELECT id, project, category, content, created_at, updated_at
+		FROM project_memories WHERE project = ?`
+	args := []interface{}{project}
+	if category != "" {
+		query += ` AND category = ?`
+		args = append(args, category)
+	}
+	query += ` ORDER BY created_at, id`
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query project memories: %w", err)
+	}
+	defer rows.Close()
+
+	var memories []*ProjectMemory
+	for rows.Next() {
+		m := &ProjectMemory{}
+		if err := rows.Scan(&m.ID, &m.Project, &m.Category, &m.Content, &m.CreatedAt, &m.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan project memory: %w", err)
+		}
+		memories = append(memories, m)
+	}
+	return memories, rows.Err()
+}
+
+// GetProjectMemory returns the memory with id, or nil if there is none.
+func (db *DB) GetProjectMemory(id int64) (*ProjectMemory, error) {
+	m := &ProjectMemory{}
+	err := db.QueryRow(`
+		SELECT id, project, category, content, created_at, updated_at
+		FROM project_memories WHERE id = ?
+	`, id).Scan(&m.ID, &m.Project, &m.Category, &m.Content, &m.CreatedAt, &m.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get project memory: %w", err)
+	}
+	return m, nil
+}
+
+// DeleteProjectMemory removes a memory.
+func (db *DB) DeleteProjectMemory(id int64) error {
+	result, err := db.Exec(`DELETE FROM project_memories WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete project memory: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("memory #%d not found", id)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package db
+
+import "testing"
+
+func TestProjectMemories(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	pattern := &ProjectMemory{Project: "app", Category: MemoryCategoryPattern, Content: "Wrap errors with %w."}
+	if err := database.CreateProjectMemory(pattern); err != nil {
+		t.Fatal(err)
+	}
+	general := &ProjectMemory{Project: "app", Content: "Staging is at staging.example.com."}
+	if err := database.CreateProjectMemory(general); err != nil {
+		t.Fatal(err)
+	}
+	if general.Category != MemoryCategoryGeneral {
+		t.Errorf("default category = %q", general.Category)
+	}
+	if err := database.CreateProjectMemory(&ProjectMemory{Project: "other", Content: "elsewhere"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := database.CreateProjectMemory(&ProjectMemory{Project: "app", Category: "trivia", Content: "x"}); err == nil {
+		t.Error("accepted an unknown category")
+	}
+	if err := database.CreateProjectMemory(&ProjectMemory{Project: "app", Content: "  "}); err == nil {
+		t.Error("accepted empty content")
+	}
+
+	all, err := database.ListProjectMemories("app", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].ID != pattern.ID || all[1].ID != general.ID {
+		t.Fatalf("ListProjectMemories = %+v", all)
+	}
+	patterns, _ := database.ListProjectMemories("app", MemoryCategoryPattern)
+	if len(patterns) != 1 || patterns[0].Content != "Wrap errors with %w." {
+		t.Errorf("pattern filter = %+v", patterns)
+	}
+
+	if err := database.DeleteProjectMemory(pattern.ID); err != nil {
+		t.Fatal(err)
+	}
+	if m, err := database.GetProjectMemory(pattern.ID); err != nil || m != nil {
+		t.Errorf("deleted memory still there: %+v, %v", m, err)
+	}
+	if err := database.DeleteProjectMemory(pattern.ID); err == nil {
+		t.Error("deleting a missing memory succeeded")
+	}
+}
@@ -0,0 +1,493 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// SchemaMigration is one versioned schema change. Unlike the idempotent
+// statements in migrate() (which tolerate re-running and swallow errors), a
+// versioned migration runs exactly once inside a transaction and is recorded in
+// schema_version only if every statement succeeds — a failure rolls the whole
+// step back and leaves the DB at the previous version.
+type SchemaMigration struct {
+	Version int
+	Name    string
+	Up      func(tx *sql.Tx) error
+}
+
+// schemaMigrations is the ordered list of versioned migrations. Version 1 is
+// the baseline: everything migrate() establishes (tables, the ALTER TABLE column
+// additions, one-time data fixes) as of the introduction of schema versioning.
+// New schema changes are appended here with the next version number.
+var schemaMigrations = []SchemaMigration{
+	{Version: 1, Name: "baseline", Up: func(tx *sql.Tx) error { return nil }},
+	{Version: 2, Name: "task_templates", Up: func(tx *sql.Tx) error {
+		_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS task_templates (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			title TEXT DEFAULT '',
+			body TEXT DEFAULT '',
+			type TEXT DEFAULT '',
+			executor TEXT DEFAULT '',
+			tags TEXT DEFAULT '',
+			project TEXT DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`)
+		return err
+	}},
+	{Version: 3, Name: "tasks_fts", Up: migrateTasksFTS},
+	{Version: 4, Name: "task_usage", Up: func(tx *sql.Tx) error {
+		// One row per Claude API message, so re-reading a transcript (or a
+		// resumed session that replays its history) never counts a message twice.
+		for _, stmt := range []string{
+			`CREATE TABLE IF NOT EXISTS task_usage (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				task_id INTEGER NOT NULL,
+				message_id TEXT NOT NULL,
+				model TEXT DEFAULT '',
+				input_tokens INTEGER DEFAULT 0,
+				output_tokens INTEGER DEFAULT 0,
+				cost_usd REAL DEFAULT 0,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE(task_id, message_id)
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_task_usage_created_at ON task_usage(created_at)`,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+	{Version: 5, Name: "time_entries", Up: func(tx *sql.Tx) error {
+		// Tracked spans of work on a task (ty track start/stop, and the time a
+		// task spends processing). ended_at is NULL while an entry is open.
+		for _, stmt := range []string{
+			`CREATE TABLE IF NOT EXISTS time_entries (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				task_id INTEGER NOT NULL,
+				source TEXT NOT NULL DEFAULT 'manual',
+				started_at DATETIME NOT NULL,
+				ended_at DATETIME
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_time_entries_task_id ON time_entries(task_id)`,
+			`CREATE INDEX IF NOT EXISTS idx_time_entries_started_at ON time_entries(started_at)`,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+	{Version: 6, Name: "undo_log", Up: func(tx *sql.Tx) error {
+		// Snapshots of tasks taken before a delete, move or archive, so
+		// 'ty undo' can bring them back. snapshot is the task (with its logs)
+		// as JSON, in the export format.
+		_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS undo_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			operation TEXT NOT NULL,
+			task_id INTEGER NOT NULL,
+			new_task_id INTEGER NOT NULL DEFAULT 0,
+			title TEXT NOT NULL DEFAULT '',
+			project TEXT NOT NULL DEFAULT '',
+			had_worktree INTEGER NOT NULL DEFAULT 0,
+			snapshot TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`)
+		return err
+	}},
+	{Version: 7, Name: "task_priority", Up: func(tx *sql.Tx) error {
+		// Queue priority: the executor picks queued tasks by priority (higher
+		// first), then FIFO by creation time.
+		return addColumn(tx, "tasks", "priority", "INTEGER DEFAULT 0")
+	}},
+	{Version: 8, Name: "task_usage_totals", Up: func(tx *sql.Tx) error {
+		// Claude token usage and estimated cost, totalled from task_usage.
+		for _, col := range [][2]string{
+			{"input_tokens", "INTEGER DEFAULT 0"},
+			{"output_tokens", "INTEGER DEFAULT 0"},
+			{"cost_usd", "REAL DEFAULT 0"},
+		} {
+			if err := addColumn(tx, "tasks", col[0], col[1]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+	{Version: 9, Name: "pre_archive_status", Up: func(tx *sql.Tx) error {
+		// Status a task had when it was archived, restored by unarchive.
+		return addColumn(tx, "tasks", "pre_archive_status", "TEXT DEFAULT ''")
+	}},
+	{Version: 10, Name: "project_default_executor", Up: func(tx *sql.Tx) error {
+		// Per-project default executor inherited by new tasks (empty = global
+		// default).
+		return addColumn(tx, "projects", "default_executor", "TEXT DEFAULT ''")
+	}},
+	{Version: 11, Name: "task_retries", Up: func(tx *sql.Tx) error {
+		// Automatic retry of failed tasks (max_retries setting): how many
+		// retries have been scheduled since the last manual retry, whether the
+		// task opted out, and when the pending retry is due (NULL = none pending).
+		for _, col := range [][2]string{
+			{"retry_count", "INTEGER DEFAULT 0"},
+			{"no_auto_retry", "INTEGER DEFAULT 0"},
+			{"retry_at", "DATETIME"},
+		} {
+			if err := addColumn(tx, "tasks", col[0], col[1]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+	{Version: 12, Name: "attachment_content_hash", Up: func(tx *sql.Tx) error {
+		// Hex SHA-256 of an attachment's data, so attaching the same file to
+		// a task twice keeps one copy.
+		return addColumn(tx, "task_attachments", "content_hash", "TEXT DEFAULT ''")
+	}},
+	{Version: 13, Name: "task_issue_link", Up: func(tx *sql.Tx) error {
+		// The GitHub issue a task was imported from, and whether the daemon
+		// still has to comment on it when the task is completed.
+		if err := addColumn(tx, "tasks", "issue_url", "TEXT DEFAULT ''"); err != nil {
+			return err
+		}
+		return addColumn(tx, "tasks", "issue_link_back", "INTEGER DEFAULT 0")
+	}},
+	{Version: 14, Name: "pr_cache", Up: func(tx *sql.Tx) error {
+		// Last known pull request per repo+branch, shared by every ty process
+		// so `ty list --pr` and `ty show` don't hit GitHub on each run. The
+		// daemon keeps it warm for active tasks. number = 0 records "no PR".
+		_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS pr_cache (
+			repo TEXT NOT NULL,
+			branch TEXT NOT NULL,
+			number INTEGER NOT NULL DEFAULT 0,
+			url TEXT NOT NULL DEFAULT '',
+			state TEXT NOT NULL DEFAULT '',
+			check_state TEXT NOT NULL DEFAULT '',
+			mergeable TEXT NOT NULL DEFAULT '',
+			info_json TEXT NOT NULL DEFAULT '',
+			fetched_at DATETIME NOT NULL,
+			PRIMARY KEY (repo, branch)
+		)`)
+		return err
+	}},
+	{Version: 15, Name: "project_wip_limit", Up: func(tx *sql.Tx) error {
+		// Most tasks of a project the daemon runs at once (0 = no limit).
+		return addColumn(tx, "projects", "wip_limit", "INTEGER DEFAULT 0")
+	}},
+	{Version: 16, Name: "task_position", Up: func(tx *sql.Tx) error {
+		// Manual order within a board column (NULL = not reordered yet).
+		return addColumn(tx, "tasks", "position", "REAL")
+	}},
+	{Version: 17, Name: "project_memories", Up: func(tx *sql.Tx) error {
+		// Short notes about a project (patterns, decisions, gotchas) that are
+		// fed to the executor through {{memories}}.
+		for _, stmt := range []string{
+			`CREATE TABLE IF NOT EXISTS project_memories (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				project TEXT NOT NULL,
+				category TEXT NOT NULL DEFAULT 'general',
+				content TEXT NOT NULL,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_project_memories_project ON project_memories(project, category)`,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+	{Version: 18, Name: "schedules", Up: func(tx *sql.Tx) error {
+		// Recurring tasks: the daemon creates a task from each schedule
+		// whenever its cron spec comes due. last_run_at is the slot last
+		// fired, so a restart never fires the same slot twice.
+		for _, stmt := range []string{
+			`CREATE TABLE IF NOT EXISTS schedules (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name TEXT NOT NULL UNIQUE,
+				spec TEXT NOT NULL,
+				title TEXT NOT NULL,
+				body TEXT NOT NULL DEFAULT '',
+				project TEXT NOT NULL DEFAULT '',
+				type TEXT NOT NULL DEFAULT '',
+				executor TEXT NOT NULL DEFAULT '',
+				tags TEXT NOT NULL DEFAULT '',
+				queue INTEGER NOT NULL DEFAULT 1,
+				last_run_at DATETIME,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`,
+			`CREATE TABLE IF NOT EXISTS schedule_runs (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				schedule_id INTEGER NOT NULL,
+				task_id INTEGER NOT NULL,
+				scheduled_for DATETIME NOT NULL,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_schedule_runs_task ON schedule_runs(task_id)`,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+	{Version: 19, Name: "task_executor_args", Up: func(tx *sql.Tx) error {
+		// Extra flags for the task's executor CLI, stored as a JSON object of
+		// allowlisted keys (see Task.ExecutorArgs / ExecutorArgMap).
+		return addColumn(tx, "tasks", "executor_args", "TEXT DEFAULT ''")
+	}},
+	{Version: 20, Name: "project_default_model", Up: func(tx *sql.Tx) error {
+		// Per-project default Claude model (empty = default_model setting).
+		return addColumn(tx, "projects", "default_model", "TEXT DEFAULT ''")
+	}},
+	{Version: 21, Name: "task_blocked_reason", Up: func(tx *sql.Tx) error {
+		// Why a blocked task is blocked (needs_input, needs_permission, error,
+		// dependency; "" = unknown).
+		return addColumn(tx, "tasks", "blocked_reason", "TEXT DEFAULT ''")
+	}},
+	{Version: 22, Name: "project_base_branch", Up: func(tx *sql.Tx) error {
+		// Branch new task worktrees are created from (empty = the repo's
+		// default branch).
+		return addColumn(tx, "projects", "base_branch", "TEXT DEFAULT ''")
+	}},
+	{Version: 23, Name: "undo_batches", Up: func(tx *sql.Tx) error {
+		// Groups the undo entries of one bulk operation (the ID of its first
+		// entry) so 'ty undo' reverts them together. 0 = a single operation.
+		return addColumn(tx, "undo_log", "batch_id", "INTEGER NOT NULL DEFAULT 0")
+	}},
+}
+
+// addColumn adds a column to table unless it is already there. Databases
+// from development builds got some of these columns from migrate() before
+// they were versioned, so the migrations that add them must not fail on them.
+func addColumn(tx *sql.Tx, table, column, decl string) error {
+	rows, err := tx.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return fmt.Errorf("read columns of %s: %w", table, err)
+	}
+	exists := false
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return fmt.Errorf("read columns of %s: %w", table, err)
+		}
+		if name == column {
+			exists = true
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("read columns of %s: %w", table, err)
+	}
+	if exists {
+		return nil
+	}
+	_, err = tx.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, decl))
+	return err
+}
+
+// AutoMigrationsEnv, when set to a non-empty value, lets Open apply pending
+// versioned migrations to a database stamped at an older version instead of
+// refusing to open it. Without it they are applied explicitly with `ty migrate
+// up`, so there is a chance to back the database up first after a `ty
+// upgrade`.
+const AutoMigrationsEnv = "TASKYOU_AUTO_MIGRATIONS"
+
+// ErrPendingMigrations is returned by Open for a database stamped at an older
+// schema version than this binary's.
+var ErrPendingMigrations = errors.New("database has pending schema migrations")
+
+// MigrationState reports whether a versioned migration has been applied.
+type MigrationState struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *LocalTime
+}
+
+// LatestSchemaVersion returns the highest version this binary knows about.
+func LatestSchemaVersion() int {
+	return schemaMigrations[len(schemaMigrations)-1].Version
+}
+
+// ensureSchemaVersionTable creates the table that records applied versions.
+func (db *DB) ensureSchemaVersionTable() error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_version (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create schema_version table: %w", err)
+	}
+	return nil
+}
+
+// SchemaVersion returns the highest applied migration version (0 if none).
+func (db *DB) SchemaVersion() (int, error) {
+	var version int
+	err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("query schema version: %w", err)
+	}
+	return version, nil
+}
+
+// ReadSchemaVersion returns the schema version of the database at path
+// without migrating it: the file is opened read-only. A database that
+// predates versioned migrations reports 0.
+func ReadSchemaVersion(path string) (int, error) {
+	if _, err := os.Stat(path); err != nil {
+		return 0, err
+	}
+	conn, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	return schemaVersionOf(conn)
+}
+
+// schemaVersionOf returns the highest version recorded in conn's
+// schema_version table, or 0 if it has none.
+func schemaVersionOf(conn *sql.DB) (int, error) {
+	var tables int
+	if err := conn.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_version'`).Scan(&tables); err != nil {
+		return 0, err
+	}
+	if tables == 0 {
+		return 0, nil
+	}
+	var version int
+	if err := conn.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&version); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// MigrationStates returns every known migration with its applied state, in
+// version order.
+func (db *DB) MigrationStates() ([]MigrationState, error) {
+	rows, err := db.Query(`SELECT version, applied_at FROM schema_version`)
+	if err != nil {
+		return nil, fmt.Errorf("query schema_version: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]LocalTime)
+	for rows.Next() {
+		var version int
+		var at LocalTime
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("scan schema_version: %w", err)
+		}
+		applied[version] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate schema_version: %w", err)
+	}
+
+	states := make([]MigrationState, 0, len(schemaMigrations))
+	for _, m := range schemaMigrations {
+		st := MigrationState{Version: m.Version, Name: m.Name}
+		if at, ok := applied[m.Version]; ok {
+			at := at
+			st.Applied = true
+			st.AppliedAt = &at
+		}
+		states = append(states, st)
+	}
+	return states, nil
+}
+
+// PendingMigrations returns the migrations that have not been applied yet.
+func (db *DB) PendingMigrations() ([]SchemaMigration, error) {
+	states, err := db.MigrationStates()
+	if err != nil {
+		return nil, err
+	}
+	var pending []SchemaMigration
+	for i, st := range states {
+		if !st.Applied {
+			pending = append(pending, schemaMigrations[i])
+		}
+	}
+	return pending, nil
+}
+
+// ApplyPendingMigrations applies every pending migration in version order and
+// returns the ones it applied. It stops at the first failure; that migration is
+// rolled back and earlier ones stay applied.
+func (db *DB) ApplyPendingMigrations() ([]SchemaMigration, error) {
+	pending, err := db.PendingMigrations()
+	if err != nil {
+		return nil, err
+	}
+	var applied []SchemaMigration
+	for _, m := range pending {
+		if err := db.applyMigration(m); err != nil {
+			return applied, err
+		}
+		applied = append(applied, m)
+	}
+	return applied, nil
+}
+
+func (db *DB) applyMigration(m SchemaMigration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("migration %d (%s): begin: %w", m.Version, m.Name, err)
+	}
+	if err := m.Up(tx); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration %d (%s): %w", m.Version, m.Name, err)
+	}
+	if _, err := tx.Exec(`INSERT INTO schema_version (version, name) VALUES (?, ?)`, m.Version, m.Name); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration %d (%s): record version: %w", m.Version, m.Name, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("migration %d (%s): commit: %w", m.Version, m.Name, err)
+	}
+	return nil
+}
+
+// migrateVersioned brings the versioned schema up to date on open. A database
+// with no schema version yet, because open just created it or it predates
+// versioned migrations, gets every migration straight away: the baseline
+// describes its schema and the later ones tolerate what migrate() already
+// added. For a database stamped at an older version, pending migrations are
+// left to the caller with allowPending (ty migrate, ty doctor, ty backup),
+// applied if AutoMigrationsEnv is set, and otherwise refused with
+// ErrPendingMigrations.
+func (db *DB) migrateVersioned(allowPending bool) error {
+	if err := db.ensureSchemaVersionTable(); err != nil {
+		return err
+	}
+	version, err := db.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	if version > 0 {
+		if allowPending {
+			return nil
+		}
+		if os.Getenv(AutoMigrationsEnv) == "" {
+			pending, err := db.PendingMigrations()
+			if err != nil || len(pending) == 0 {
+				return err
+			}
+			return fmt.Errorf("%w: the database is at schema v%d and this ty needs v%d; run 'ty migrate up' to apply them (after 'ty backup', to keep a copy)",
+				ErrPendingMigrations, version, LatestSchemaVersion())
+		}
+	}
+	_, err = db.ApplyPendingMigrations()
+	return err
+}
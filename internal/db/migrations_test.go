@@ -0,0 +1,200 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpenAppliesBaselineMigration(t *testing.T) {
+	database, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer database.Close()
+
+	version, err := database.SchemaVersion()
+	if err != nil {
+		t.Fatalf("SchemaVersion: %v", err)
+	}
+	if version != LatestSchemaVersion() {
+		t.Errorf("expected schema version %d, got %d", LatestSchemaVersion(), version)
+	}
+	pending, err := database.PendingMigrations()
+	if err != nil {
+		t.Fatalf("PendingMigrations: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected no pending migrations, got %d", len(pending))
+	}
+}
+
+// openOutdated creates a database and then forgets every migration after
+// version 6, as if it was last opened by a ty from before them. The columns
+// and tables those migrations add are still there, so applying them again
+// also checks they tolerate a schema that already has them.
+func openOutdated(t *testing.T) string {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	database, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if _, err := database.Exec(`DELETE FROM schema_version WHERE version > 6`); err != nil {
+		t.Fatalf("forget migrations: %v", err)
+	}
+	database.Close()
+	return dbPath
+}
+
+func TestOpenRefusesPendingMigrations(t *testing.T) {
+	dbPath := openOutdated(t)
+
+	_, err := Open(dbPath)
+	if !errors.Is(err, ErrPendingMigrations) {
+		t.Fatalf("expected ErrPendingMigrations, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "ty migrate up") {
+		t.Errorf("error %q should say to run 'ty migrate up'", err)
+	}
+
+	database, err := OpenWithPendingMigrations(dbPath)
+	if err != nil {
+		t.Fatalf("OpenWithPendingMigrations: %v", err)
+	}
+	pending, err := database.PendingMigrations()
+	if err != nil {
+		t.Fatalf("PendingMigrations: %v", err)
+	}
+	if want := LatestSchemaVersion() - 6; len(pending) != want {
+		t.Fatalf("expected %d migrations pending, got %d", want, len(pending))
+	}
+
+	applied, err := database.ApplyPendingMigrations()
+	if err != nil {
+		t.Fatalf("ApplyPendingMigrations: %v", err)
+	}
+	if len(applied) != len(pending) {
+		t.Errorf("expected %d applied, got %d", len(pending), len(applied))
+	}
+	database.Close()
+
+	database, err = Open(dbPath)
+	if err != nil {
+		t.Fatalf("open after up: %v", err)
+	}
+	defer database.Close()
+	if v, _ := database.SchemaVersion(); v != LatestSchemaVersion() {
+		t.Errorf("expected version %d after up, got %d", LatestSchemaVersion(), v)
+	}
+}
+
+func TestOpenMigratesUnversionedDatabase(t *testing.T) {
+	// A database from before versioned migrations has no schema_version
+	// table: it is stamped and brought up to date rather than refused.
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	database, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if _, err := database.Exec(`DROP TABLE schema_version`); err != nil {
+		t.Fatalf("drop schema_version: %v", err)
+	}
+	database.Close()
+
+	database, err = Open(dbPath)
+	if err != nil {
+		t.Fatalf("open unversioned database: %v", err)
+	}
+	defer database.Close()
+	if v, _ := database.SchemaVersion(); v != LatestSchemaVersion() {
+		t.Errorf("expected version %d, got %d", LatestSchemaVersion(), v)
+	}
+}
+
+func TestAutoMigrationsAppliesOnOpen(t *testing.T) {
+	dbPath := openOutdated(t)
+	t.Setenv(AutoMigrationsEnv, "1")
+
+	database, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer database.Close()
+	if v, _ := database.SchemaVersion(); v != LatestSchemaVersion() {
+		t.Errorf("expected version %d, got %d", LatestSchemaVersion(), v)
+	}
+}
+
+func TestFailingMigrationRollsBack(t *testing.T) {
+	database, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer database.Close()
+
+	orig := schemaMigrations
+	t.Cleanup(func() { schemaMigrations = orig })
+
+	next := LatestSchemaVersion() + 1
+	schemaMigrations = append(append([]SchemaMigration{}, orig...), SchemaMigration{
+		Version: next,
+		Name:    "broken",
+		Up: func(tx *sql.Tx) error {
+			if _, err := tx.Exec(`CREATE TABLE migration_probe (id INTEGER)`); err != nil {
+				return err
+			}
+			return errors.New("boom")
+		},
+	})
+
+	if _, err := database.ApplyPendingMigrations(); err == nil {
+		t.Fatal("expected the broken migration to fail")
+	}
+
+	if v, _ := database.SchemaVersion(); v != next-1 {
+		t.Errorf("expected version to stay at %d, got %d", next-1, v)
+	}
+	var count int
+	database.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'migration_probe'`).Scan(&count)
+	if count != 0 {
+		t.Error("expected the failed migration's table creation to be rolled back")
+	}
+}
+
+func TestReadSchemaVersion(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := ReadSchemaVersion(filepath.Join(dir, "missing.db")); err == nil {
+		t.Error("expected an error for a missing database")
+	}
+
+	dbPath := filepath.Join(dir, "test.db")
+	database, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	database.Close()
+	version, err := ReadSchemaVersion(dbPath)
+	if err != nil {
+		t.Fatalf("ReadSchemaVersion: %v", err)
+	}
+	if version != LatestSchemaVersion() {
+		t.Errorf("expected schema version %d, got %d", LatestSchemaVersion(), version)
+	}
+
+	// A database from before versioned migrations has no schema_version table.
+	oldPath := filepath.Join(dir, "old.db")
+	conn, err := sql.Open("sqlite", oldPath)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if _, err := conn.Exec(`CREATE TABLE tasks (id INTEGER PRIMARY KEY)`); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	conn.Close()
+	if version, err := ReadSchemaVersion(oldPath); err != nil || version != 0 {
+		t.Errorf("old database: got %d, %v; want 0", version, err)
+	}
+}
@@ -0,0 +1,183 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Manual ordering within a board column. tasks.position is a REAL sort key:
+// within a status (and pinned state), tasks with a position come first in
+// ascending order, followed by the unpositioned ones in the usual recency
+// order. Moving a task only rewrites that task's position, picking a value
+// halfway between its new neighbours (fractional indexing); the rest of the
+// column is left alone. A status change clears the position, so a task lands
+// in its new column by time like any other.
+
+// positionedTask is a task id and its position, if it has one.
+type positionedTask struct {
+	id  int64
+	pos sql.NullFloat64
+}
+
+// GetTaskPosition returns a task's manual position; ok is false when it has
+// none and sorts by time.
+func (db *DB) GetTaskPosition(taskID int64) (pos float64, ok bool, err error) {
+	var p sql.NullFloat64
+	if err := db.QueryRow(`SELECT position FROM tasks WHERE id = ?`, taskID).Scan(&p); err != nil {
+		return 0, false, fmt.Errorf("get task position: %w", err)
+	}
+	return p.Float64, p.Valid, nil
+}
+
+// ReorderTask moves task id directly before (or, with after, directly after)
+// anchorID in their column. Both tasks must share a status and pinned state;
+// done and archived tasks keep their completion order and can't be moved.
+func (db *DB) ReorderTask(id, anchorID int64, after bool) error {
+	if id == anchorID {
+		return fmt.Errorf("cannot move task #%d relative to itself", id)
+	}
+	task, err := db.GetTask(id)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return fmt.Errorf("task #%d not found", id)
+	}
+	anchor, err := db.GetTask(anchorID)
+	if err != nil {
+		return err
+	}
+	if anchor == nil {
+		return fmt.Errorf("task #%d not found", anchorID)
+	}
+	if task.Status == StatusDone || task.Status == StatusArchived {
+		return fmt.Errorf("task #%d is %s; closed tasks are ordered by completion time", id, task.Status)
+	}
+	if task.Status != anchor.Status {
+		return fmt.Errorf("task #%d is %s but #%d is %s; tasks can only be ordered within a column", id, task.Status, anchorID, anchor.Status)
+	}
+	if task.Pinned != anchor.Pinned {
+		return fmt.Errorf("pinned tasks always sort above unpinned ones; pin or unpin #%d first", id)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin reorder: %w", err)
+	}
+	defer tx.Rollback()
+
+	column, err := columnOrder(tx, task.Status, task.Pinned, id)
+	if err != nil {
+		return err
+	}
+	at := -1
+	for i, t := range column {
+		if t.id == anchorID {
+			at = i
+			break
+		}
+	}
+	if at < 0 {
+		return fmt.Errorf("task #%d not found in its column", anchorID)
+	}
+	if after {
+		at++
+	}
+
+	pos, ok, err := slotPosition(tx, column, at)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// The neighbours' positions are too close to split further: space the
+		// whole column out again and retry. This is the only path that writes
+		// more than the moved task and the ones above it that had no position.
+		if err := renumberColumn(tx, column); err != nil {
+			return err
+		}
+		if pos, _, err = slotPosition(tx, column, at); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE tasks SET position = ? WHERE id = ?`, pos, id); err != nil {
+		return fmt.Errorf("set task position: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit reorder: %w", err)
+	}
+	return nil
+}
+
+// columnOrder returns the tasks with status and pinned state in display order,
+// leaving out exclude (the task being moved).
+func columnOrder(tx *sql.Tx, status string, pinned bool, exclude int64) ([]positionedTask, error) {
+	rows, err := tx.Query(`
+		SELECT id, position FROM tasks
+		WHERE status = ? AND COALESCE(pinned, 0) = ? AND id != ? AND deleted_at IS NULL
+		ORDER BY position IS NULL, position,
+		         CASE WHEN status IN ('done', 'blocked') THEN completed_at ELSE created_at END DESC, id DESC
+	`, status, boolToInt(pinned), exclude)
+	if err != nil {
+		return nil, fmt.Errorf("query column order: %w", err)
+	}
+	defer rows.Close()
+
+	var column []positionedTask
+	for rows.Next() {
+		var t positionedTask
+		if err := rows.Scan(&t.id, &t.pos); err != nil {
+			return nil, fmt.Errorf("scan column order: %w", err)
+		}
+		column = append(column, t)
+	}
+	return column, rows.Err()
+}
+
+// slotPosition returns a position that sorts between column[at-1] and
+// column[at]. Unpositioned tasks above the slot are given positions first so
+// the slot's upper neighbour has one; column is updated in place. ok is false
+// when the neighbours are too close together to split.
+func slotPosition(tx *sql.Tx, column []positionedTask, at int) (pos float64, ok bool, err error) {
+	// Positioned tasks form a prefix of the column, so anything unpositioned
+	// above the slot follows the last positioned one.
+	next := 0.0
+	for i := 0; i < at; i++ {
+		if column[i].pos.Valid {
+			next = column[i].pos.Float64 + 1
+			continue
+		}
+		if _, err := tx.Exec(`UPDATE tasks SET position = ? WHERE id = ?`, next, column[i].id); err != nil {
+			return 0, false, fmt.Errorf("set task position: %w", err)
+		}
+		column[i].pos = sql.NullFloat64{Float64: next, Valid: true}
+		next++
+	}
+
+	var below *positionedTask
+	if at < len(column) && column[at].pos.Valid {
+		below = &column[at]
+	}
+	switch {
+	case at == 0 && below == nil:
+		return 0, true, nil
+	case at == 0:
+		return below.pos.Float64 - 1, true, nil
+	case below == nil:
+		return column[at-1].pos.Float64 + 1, true, nil
+	}
+	lo, hi := column[at-1].pos.Float64, below.pos.Float64
+	mid := lo + (hi-lo)/2
+	return mid, mid > lo && mid < hi, nil
+}
+
+// renumberColumn gives every task in column the position of its index.
+func renumberColumn(tx *sql.Tx, column []positionedTask) error {
+	for i := range column {
+		if _, err := tx.Exec(`UPDATE tasks SET position = ? WHERE id = ?`, float64(i), column[i].id); err != nil {
+			return fmt.Errorf("renumber column: %w", err)
+		}
+		column[i].pos = sql.NullFloat64{Float64: float64(i), Valid: true}
+	}
+	return nil
+}
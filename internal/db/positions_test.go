@@ -0,0 +1,104 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+// backlogOrder returns the ids of the backlog tasks in ListTasks order.
+func backlogOrder(t *testing.T, db *DB) []int64 {
+	t.Helper()
+	tasks, err := db.ListTasks(ListTasksOptions{Status: StatusBacklog})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]int64, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+func sameOrder(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestReorderTask(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ids := make([]int64, 4)
+	for i := range ids {
+		task := &Task{Title: "task", Status: StatusBacklog}
+		if err := db.CreateTask(task); err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = task.ID
+	}
+	// Newest first until something is placed by hand.
+	a, b, c, d := ids[0], ids[1], ids[2], ids[3]
+	if got := backlogOrder(t, db); !sameOrder(got, []int64{d, c, b, a}) {
+		t.Fatalf("initial order = %v", got)
+	}
+
+	steps := []struct {
+		id, anchor int64
+		after      bool
+		want       []int64
+	}{
+		{a, c, false, []int64{d, a, c, b}},
+		{d, b, true, []int64{a, c, b, d}},
+		{b, a, false, []int64{b, a, c, d}},
+		{c, b, true, []int64{b, c, a, d}},
+	}
+	for _, s := range steps {
+		if err := db.ReorderTask(s.id, s.anchor, s.after); err != nil {
+			t.Fatalf("ReorderTask(%d, %d, %v): %v", s.id, s.anchor, s.after, err)
+		}
+		if got := backlogOrder(t, db); !sameOrder(got, s.want) {
+			t.Fatalf("after moving #%d: order = %v, want %v", s.id, got, s.want)
+		}
+	}
+
+	// Repeatedly splitting the same gap eventually runs out of float precision;
+	// the column is renumbered and the order still holds.
+	for i := 0; i < 80; i++ {
+		mover, anchor := a, c
+		if i%2 == 1 {
+			mover, anchor = c, a
+		}
+		if err := db.ReorderTask(mover, anchor, true); err != nil {
+			t.Fatalf("split %d: %v", i, err)
+		}
+	}
+	if got := backlogOrder(t, db); !sameOrder(got, []int64{b, a, c, d}) {
+		t.Fatalf("after repeated splits: order = %v", got)
+	}
+
+	// Leaving the column drops the manual position.
+	if err := db.UpdateTaskStatus(d, StatusQueued); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := db.GetTaskPosition(d); ok {
+		t.Error("position kept after a status change")
+	}
+
+	if err := db.ReorderTask(a, d, false); err == nil || !strings.Contains(err.Error(), "within a column") {
+		t.Errorf("cross-column reorder: %v", err)
+	}
+	db.UpdateTaskPinned(c, true)
+	if err := db.ReorderTask(a, c, false); err == nil || !strings.Contains(err.Error(), "pinned") {
+		t.Errorf("pinned/unpinned reorder: %v", err)
+	}
+	if err := db.ReorderTask(a, a, false); err == nil {
+		t.Error("reorder relative to itself accepted")
+	}
+}
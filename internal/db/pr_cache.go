@@ -0,0 +1,58 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// PRCacheEntry is a cached pull request lookup for a branch of a repo.
+type PRCacheEntry struct {
+	Repo       string
+	Branch     string
+	Number     int // 0 = the branch had no PR when fetched
+	URL        string
+	State      string
+	CheckState string
+	Mergeable  string
+	InfoJSON   string // the full github.PRInfo as JSON
+	FetchedAt  time.Time
+}
+
+// GetPRCache returns the cached PR lookup for repo+branch, or nil if there is
+// none. Callers decide whether it is fresh enough from FetchedAt.
+func (db *DB) GetPRCache(repo, branch string) (*PRCacheEntry, error) {
+	e := &PRCacheEntry{}
+	var fetchedAt sql.NullTime
+	err := db.QueryRow(`
+		SELECT repo, branch, number, url, state, check_state, mergeable, info_json, fetched_at
+		FROM pr_cache WHERE repo = ? AND branch = ?
+	`, repo, branch).Scan(&e.Repo, &e.Branch, &e.Number, &e.URL, &e.State, &e.CheckState, &e.Mergeable, &e.InfoJSON, &fetchedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get pr cache: %w", err)
+	}
+	if fetchedAt.Valid {
+		e.FetchedAt = fetchedAt.Time
+	}
+	return e, nil
+}
+
+// PutPRCache stores (or replaces) a PR lookup.
+func (db *DB) PutPRCache(e *PRCacheEntry) error {
+	_, err := db.Exec(`
+		INSERT INTO pr_cache (repo, branch, number, url, state, check_state, mergeable, info_json, fetched_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(repo, branch) DO UPDATE SET
+			number = excluded.number, url = excluded.url, state = excluded.state,
+			check_state = excluded.check_state, mergeable = excluded.mergeable,
+			info_json = excluded.info_json, fetched_at = excluded.fetched_at
+	`, e.Repo, e.Branch, e.Number, e.URL, e.State, e.CheckState, e.Mergeable, e.InfoJSON,
+		e.FetchedAt.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return fmt.Errorf("put pr cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPRCache(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	if e, err := database.GetPRCache("/repo", "feature"); err != nil || e != nil {
+		t.Fatalf("empty cache: got %+v, %v", e, err)
+	}
+
+	fetched := time.Now().Add(-30 * time.Second).Truncate(time.Second)
+	entry := &PRCacheEntry{
+		Repo: "/repo", Branch: "feature", Number: 12, URL: "https://github.com/o/r/pull/12",
+		State: "OPEN", CheckState: "SUCCESS", Mergeable: "MERGEABLE", InfoJSON: `{"number":12}`,
+		FetchedAt: fetched,
+	}
+	if err := database.PutPRCache(entry); err != nil {
+		t.Fatal(err)
+	}
+	got, err := database.GetPRCache("/repo", "feature")
+	if err != nil || got == nil {
+		t.Fatalf("GetPRCache: %+v, %v", got, err)
+	}
+	if got.Number != 12 || got.State != "OPEN" || got.CheckState != "SUCCESS" || got.InfoJSON != `{"number":12}` {
+		t.Errorf("unexpected entry: %+v", got)
+	}
+	if !got.FetchedAt.Equal(fetched) {
+		t.Errorf("FetchedAt = %v, want %v", got.FetchedAt, fetched)
+	}
+
+	// Replacing keeps one row per repo+branch.
+	entry.State, entry.FetchedAt = "MERGED", time.Now()
+	if err := database.PutPRCache(entry); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = database.GetPRCache("/repo", "feature")
+	if got.State != "MERGED" {
+		t.Errorf("state after replace = %q", got.State)
+	}
+	var n int
+	database.QueryRow(`SELECT COUNT(*) FROM pr_cache`).Scan(&n)
+	if n != 1 {
+		t.Errorf("rows = %d, want 1", n)
+	}
+}
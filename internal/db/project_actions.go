@@ -0,0 +1,122 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Project action triggers, named after the task events they fire on.
+//
+//   - task.created: the instructions are prepended to the task's first prompt
+//     (see the executor's getOnCreateInstructions).
+//   - task.started, task.blocked: the instructions are appended to the task's
+//     log when it enters processing or blocked.
+//   - task.completed: a backlog follow-up task with the instructions as its
+//     body is created in the project when a task is done.
+//
+// The last three fire from UpdateTaskStatus, so they run whichever process
+// (daemon, CLI, TUI, hooks) makes the transition.
+const (
+	ActionTaskCreated   = EventTaskCreated
+	ActionTaskStarted   = EventTaskStarted
+	ActionTaskBlocked   = EventTaskBlocked
+	ActionTaskCompleted = EventTaskCompleted
+
+	// ActionOnCreate is the original name of ActionTaskCreated, still honored
+	// for projects configured before triggers were named after events.
+	ActionOnCreate = "on_create"
+)
+
+// FollowUpTag marks the tasks created by task.completed actions. Completing
+// one doesn't fire task.completed again, so actions can't chain forever.
+const FollowUpTag = "follow-up"
+
+// ProjectActionTriggers returns the valid action triggers in lifecycle order.
+func ProjectActionTriggers() []string {
+	return []string{ActionTaskCreated, ActionTaskStarted, ActionTaskBlocked, ActionTaskCompleted}
+}
+
+// NormalizeActionTrigger maps a trigger to its canonical name, or returns an
+// error listing the valid triggers.
+func NormalizeActionTrigger(trigger string) (string, error) {
+	trigger = strings.TrimSpace(trigger)
+	if trigger == ActionOnCreate {
+		return ActionTaskCreated, nil
+	}
+	for _, t := range ProjectActionTriggers() {
+		if t == trigger {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("invalid trigger %q (valid: %s)", trigger, strings.Join(ProjectActionTriggers(), ", "))
+}
+
+// SetAction sets the instructions for trigger, replacing any existing action
+// for it. It reports whether an action was replaced.
+func (p *Project) SetAction(trigger, instructions string) bool {
+	if a := p.GetAction(trigger); a != nil {
+		a.Instructions = instructions
+		return true
+	}
+	p.Actions = append(p.Actions, ProjectAction{Trigger: trigger, Instructions: instructions})
+	return false
+}
+
+// RemoveAction removes the action for trigger, reporting whether there was one.
+func (p *Project) RemoveAction(trigger string) bool {
+	for i := range p.Actions {
+		if p.Actions[i].Trigger == trigger {
+			p.Actions = append(p.Actions[:i], p.Actions[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// runProjectActions runs the task's project action for trigger, if any.
+// Best-effort: a failing action never fails the status change behind it.
+func (db *DB) runProjectActions(trigger string, task *Task) {
+	if task.Project == "" {
+		return
+	}
+	project, err := db.GetProjectByName(task.Project)
+	if err != nil || project == nil {
+		return
+	}
+	action := project.GetAction(trigger)
+	if action == nil || strings.TrimSpace(action.Instructions) == "" {
+		return
+	}
+
+	switch trigger {
+	case ActionTaskStarted, ActionTaskBlocked:
+		db.AppendTaskLog(task.ID, "system", fmt.Sprintf("Project action (%s): %s", trigger, action.Instructions))
+	case ActionTaskCompleted:
+		if hasTag(task.Tags, FollowUpTag) {
+			return
+		}
+		followUp := &Task{
+			Title:   "Follow-up: " + task.Title,
+			Body:    fmt.Sprintf("%s\n\nFollow-up to task #%d (%s).", action.Instructions, task.ID, task.Title),
+			Status:  StatusBacklog,
+			Type:    task.Type,
+			Project: task.Project,
+			Tags:    FollowUpTag,
+		}
+		if err := db.CreateTask(followUp); err != nil {
+			db.AppendTaskLog(task.ID, "error", "Project action (task.completed) could not create a follow-up task: "+err.Error())
+			return
+		}
+		db.AppendTaskLog(task.ID, "system", fmt.Sprintf("Project action (task.completed): created follow-up task #%d", followUp.ID))
+	}
+}
+
+// hasTag reports whether the comma-separated tags include tag.
+func hasTag(tags, tag string) bool {
+	for _, t := range strings.Split(tags, ",") {
+		if strings.EqualFold(strings.TrimSpace(t), tag) {
+			return true
+		}
+	}
+	return false
+}
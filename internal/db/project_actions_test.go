@@ -0,0 +1,105 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeActionTrigger(t *testing.T) {
+	for in, want := range map[string]string{
+		"task.completed":  ActionTaskCompleted,
+		" task.blocked ":  ActionTaskBlocked,
+		ActionOnCreate:    ActionTaskCreated,
+		ActionTaskCreated: ActionTaskCreated,
+	} {
+		got, err := NormalizeActionTrigger(in)
+		if err != nil || got != want {
+			t.Errorf("NormalizeActionTrigger(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := NormalizeActionTrigger("on_status:queued"); err == nil {
+		t.Error("accepted an unknown trigger")
+	}
+}
+
+func TestProjectSetRemoveAction(t *testing.T) {
+	p := &Project{}
+	if p.SetAction(ActionTaskCompleted, "one") {
+		t.Error("first SetAction reported a replacement")
+	}
+	if !p.SetAction(ActionTaskCompleted, "two") {
+		t.Error("second SetAction didn't report a replacement")
+	}
+	if len(p.Actions) != 1 || p.GetAction(ActionTaskCompleted).Instructions != "two" {
+		t.Errorf("actions = %+v", p.Actions)
+	}
+	if !p.RemoveAction(ActionTaskCompleted) || p.RemoveAction(ActionTaskCompleted) {
+		t.Error("RemoveAction should remove the action exactly once")
+	}
+}
+
+func TestProjectActionsFireOnStatusChange(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	project := &Project{Name: "app", Path: t.TempDir()}
+	project.SetAction(ActionTaskBlocked, "Ping the on-call channel")
+	project.SetAction(ActionTaskCompleted, "Update the CHANGELOG")
+	if err := database.CreateProject(project); err != nil {
+		t.Fatal(err)
+	}
+
+	task := &Task{Title: "Ship it", Status: StatusQueued, Type: TypeCode, Project: "app"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := database.UpdateTaskStatus(task.ID, StatusBlocked); err != nil {
+		t.Fatal(err)
+	}
+	logs, err := database.GetTaskLogs(task.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, l := range logs {
+		if strings.Contains(l.Content, "Ping the on-call channel") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("task.blocked action not logged: %+v", logs)
+	}
+
+	if err := database.UpdateTaskStatus(task.ID, StatusDone); err != nil {
+		t.Fatal(err)
+	}
+	followUps, err := database.ListTasks(ListTasksOptions{Project: "app", IncludeClosed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var followUp *Task
+	for _, ft := range followUps {
+		if ft.ID != task.ID {
+			followUp = ft
+		}
+	}
+	if followUp == nil {
+		t.Fatal("task.completed action created no follow-up task")
+	}
+	if followUp.Status != StatusBacklog || followUp.Tags != FollowUpTag || !strings.Contains(followUp.Body, "Update the CHANGELOG") {
+		t.Errorf("follow-up = %+v", followUp)
+	}
+
+	// Completing the follow-up doesn't chain another one.
+	if err := database.UpdateTaskStatus(followUp.ID, StatusDone); err != nil {
+		t.Fatal(err)
+	}
+	all, err := database.ListTasks(ListTasksOptions{Project: "app", IncludeClosed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Errorf("got %d tasks after completing the follow-up, want 2", len(all))
+	}
+}
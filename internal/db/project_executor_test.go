@@ -0,0 +1,70 @@
+package db
+
+import "testing"
+
+func TestProjectEffectiveExecutor(t *testing.T) {
+	if got := (&Project{}).EffectiveExecutor(); got != ExecutorClaude {
+		t.Errorf("unset project executor = %q, want %q", got, ExecutorClaude)
+	}
+	if got := (&Project{Executor: ExecutorCodex}).EffectiveExecutor(); got != ExecutorCodex {
+		t.Errorf("project executor = %q, want %q", got, ExecutorCodex)
+	}
+}
+
+func TestCreateTaskExecutorResolution(t *testing.T) {
+	database := newPermTestDB(t)
+
+	if err := database.CreateProject(&Project{Name: "infra", Path: t.TempDir(), Executor: ExecutorCodex}); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	if err := database.CreateProject(&Project{Name: "plain", Path: t.TempDir()}); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+
+	cases := []struct {
+		name     string
+		project  string
+		executor string
+		want     string
+	}{
+		{"explicit executor wins", "infra", ExecutorGemini, ExecutorGemini},
+		{"falls back to project default", "infra", "", ExecutorCodex},
+		{"falls back to global default", "plain", "", ExecutorClaude},
+	}
+	for _, c := range cases {
+		task := &Task{Title: c.name, Status: StatusBacklog, Type: TypeCode, Project: c.project, Executor: c.executor}
+		if err := database.CreateTask(task); err != nil {
+			t.Fatalf("%s: create task: %v", c.name, err)
+		}
+		got, err := database.GetTask(task.ID)
+		if err != nil {
+			t.Fatalf("%s: get task: %v", c.name, err)
+		}
+		if got.Executor != c.want {
+			t.Errorf("%s: executor = %q, want %q", c.name, got.Executor, c.want)
+		}
+	}
+}
+
+func TestProjectExecutorPersists(t *testing.T) {
+	database := newPermTestDB(t)
+	if err := database.CreateProject(&Project{Name: "p", Path: t.TempDir(), Executor: ExecutorCodex}); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	got, err := database.GetProjectByName("p")
+	if err != nil {
+		t.Fatalf("get project: %v", err)
+	}
+	if got.Executor != ExecutorCodex {
+		t.Errorf("project executor not persisted, got %q", got.Executor)
+	}
+
+	got.Executor = ExecutorClaude
+	if err := database.UpdateProject(got); err != nil {
+		t.Fatalf("update project: %v", err)
+	}
+	again, _ := database.GetProjectByName("p")
+	if again.Executor != ExecutorClaude {
+		t.Errorf("updated project executor not persisted, got %q", again.Executor)
+	}
+}
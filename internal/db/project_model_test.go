@@ -0,0 +1,89 @@
+package db
+
+import "testing"
+
+func TestIsValidModel(t *testing.T) {
+	valid := []string{"", ModelOpus, ModelSonnet, ModelHaiku, ModelFable, "claude-opus-4-8", "custom:gpt-next"}
+	for _, m := range valid {
+		if !IsValidModel(m) {
+			t.Errorf("IsValidModel(%q) = false, want true", m)
+		}
+	}
+	invalid := []string{"opsu", "gpt-4o", "claude-", "custom:", "custom:  ", "claude-opus 4"}
+	for _, m := range invalid {
+		if IsValidModel(m) {
+			t.Errorf("IsValidModel(%q) = true, want false", m)
+		}
+	}
+	if got := ModelID("custom:gpt-next"); got != "gpt-next" {
+		t.Errorf("ModelID(custom:gpt-next) = %q, want gpt-next", got)
+	}
+}
+
+func TestDefaultModelResolution(t *testing.T) {
+	database := newPermTestDB(t)
+
+	if err := database.CreateProject(&Project{Name: "infra", Path: t.TempDir(), DefaultModel: ModelHaiku}); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	if err := database.CreateProject(&Project{Name: "plain", Path: t.TempDir()}); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		project string
+		global  string
+		want    string
+	}{
+		{"project default wins", "infra", ModelSonnet, ModelHaiku},
+		{"falls back to global default", "plain", ModelSonnet, ModelSonnet},
+		{"no default anywhere", "plain", "", ""},
+	}
+	for _, c := range cases {
+		if err := database.SetSetting(SettingDefaultModel, c.global); err != nil {
+			t.Fatalf("%s: set default_model: %v", c.name, err)
+		}
+		if got := database.DefaultModel(c.project); got != c.want {
+			t.Errorf("%s: DefaultModel(%q) = %q, want %q", c.name, c.project, got, c.want)
+		}
+	}
+}
+
+// TestCreateTaskLeavesModelUnset verifies defaults aren't copied onto new
+// tasks, so later default changes still reach them.
+func TestCreateTaskLeavesModelUnset(t *testing.T) {
+	database := newPermTestDB(t)
+	if err := database.CreateProject(&Project{Name: "infra", Path: t.TempDir(), DefaultModel: ModelHaiku}); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	if err := database.SetSetting(SettingDefaultModel, ModelSonnet); err != nil {
+		t.Fatalf("set default_model: %v", err)
+	}
+
+	task := &Task{Title: "t", Status: StatusBacklog, Type: TypeCode, Project: "infra"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	got, err := database.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("get task: %v", err)
+	}
+	if got.Model != "" {
+		t.Errorf("model = %q, want it left empty", got.Model)
+	}
+}
+
+func TestProjectDefaultModelPersists(t *testing.T) {
+	database := newPermTestDB(t)
+	if err := database.CreateProject(&Project{Name: "p", Path: t.TempDir(), DefaultModel: ModelOpus}); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	got, err := database.GetProjectByName("p")
+	if err != nil {
+		t.Fatalf("get project: %v", err)
+	}
+	if got.DefaultModel != ModelOpus {
+		t.Errorf("project default model not persisted, got %q", got.DefaultModel)
+	}
+}
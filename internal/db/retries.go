@@ -0,0 +1,91 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// AutoRetryState is a task's automatic retry bookkeeping. The executor
+// re-queues a failed task up to the max_retries setting, waiting longer
+// before each attempt; these columns persist that across daemon restarts.
+type AutoRetryState struct {
+	Count    int        // Automatic retries scheduled since the last manual retry
+	Disabled bool       // Task opted out (ty create --no-auto-retry)
+	RetryAt  *LocalTime // When the pending retry is due (nil = none pending)
+}
+
+// GetAutoRetryState returns the automatic retry state of a task.
+func (db *DB) GetAutoRetryState(taskID int64) (*AutoRetryState, error) {
+	state := &AutoRetryState{}
+	var retryAt sql.NullTime
+	err := db.QueryRow(`
+		SELECT COALESCE(retry_count, 0), COALESCE(no_auto_retry, 0), retry_at
+		FROM tasks WHERE id = ?
+	`, taskID).Scan(&state.Count, &state.Disabled, &retryAt)
+	if err != nil {
+		return nil, fmt.Errorf("get auto retry state: %w", err)
+	}
+	if retryAt.Valid {
+		state.RetryAt = &LocalTime{Time: retryAt.Time}
+	}
+	return state, nil
+}
+
+// SetNoAutoRetry opts a task out of (or back into) automatic retries.
+func (db *DB) SetNoAutoRetry(taskID int64, disabled bool) error {
+	if _, err := db.Exec(`UPDATE tasks SET no_auto_retry = ? WHERE id = ?`, disabled, taskID); err != nil {
+		return fmt.Errorf("set no_auto_retry: %w", err)
+	}
+	return nil
+}
+
+// ScheduleAutoRetry records the count-th automatic retry of a task, due at at.
+// The task stays where it is (blocked) until ListDueAutoRetries returns it.
+func (db *DB) ScheduleAutoRetry(taskID int64, count int, at time.Time) error {
+	_, err := db.Exec(`UPDATE tasks SET retry_count = ?, retry_at = ? WHERE id = ?`,
+		count, at.UTC().Format("2006-01-02 15:04:05"), taskID)
+	if err != nil {
+		return fmt.Errorf("schedule auto retry: %w", err)
+	}
+	return nil
+}
+
+// ResetRetryCount clears a task's automatic retry count and any pending retry.
+func (db *DB) ResetRetryCount(taskID int64) error {
+	if _, err := db.Exec(`UPDATE tasks SET retry_count = 0, retry_at = NULL WHERE id = ?`, taskID); err != nil {
+		return fmt.Errorf("reset retry count: %w", err)
+	}
+	return nil
+}
+
+// ListDueAutoRetries returns the IDs of blocked tasks whose pending automatic
+// retry is due at now, oldest due first.
+func (db *DB) ListDueAutoRetries(now time.Time) ([]int64, error) {
+	rows, err := db.Query(`
+		SELECT id FROM tasks
+		WHERE status = ? AND retry_at IS NOT NULL AND deleted_at IS NULL
+		  AND datetime(retry_at) <= datetime(?)
+		ORDER BY retry_at, id
+	`, StatusBlocked, now.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, fmt.Errorf("list due auto retries: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan due auto retry: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// AutoRetryTask re-queues a task for a due automatic retry. Unlike RetryTask
+// it keeps the retry count, so the max_retries budget keeps shrinking.
+func (db *DB) AutoRetryTask(taskID int64, feedback string) error {
+	return db.requeueWithFeedback(taskID, feedback)
+}
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAutoRetryScheduling(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	task := &Task{Title: "flaky", Status: StatusProcessing}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.UpdateTaskStatus(task.ID, StatusBlocked); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	if err := database.ScheduleAutoRetry(task.ID, 1, now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	state, err := database.GetAutoRetryState(task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Count != 1 || state.RetryAt == nil || state.Disabled {
+		t.Fatalf("unexpected state after scheduling: %+v", state)
+	}
+
+	if ids, _ := database.ListDueAutoRetries(now); len(ids) != 0 {
+		t.Errorf("retry due before its time: %v", ids)
+	}
+	ids, err := database.ListDueAutoRetries(now.Add(2 * time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != task.ID {
+		t.Fatalf("ListDueAutoRetries = %v, want [%d]", ids, task.ID)
+	}
+
+	// The automatic retry re-queues the task, clears the pending retry and
+	// keeps the count.
+	if err := database.AutoRetryTask(task.ID, "try again"); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := database.GetTask(task.ID)
+	if got.Status != StatusQueued {
+		t.Errorf("status = %q, want queued", got.Status)
+	}
+	state, _ = database.GetAutoRetryState(task.ID)
+	if state.Count != 1 || state.RetryAt != nil {
+		t.Errorf("after auto retry: %+v, want count 1 and no pending retry", state)
+	}
+	if feedback, _ := database.GetRetryFeedback(task.ID); feedback != "try again" {
+		t.Errorf("retry feedback = %q", feedback)
+	}
+}
+
+func TestStatusChangeCancelsPendingAutoRetry(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	task := &Task{Title: "flaky", Status: StatusBlocked}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.ScheduleAutoRetry(task.ID, 2, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	// The user moves the task by hand; the stale retry must not fire later.
+	if err := database.UpdateTaskStatus(task.ID, StatusBacklog); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.UpdateTaskStatus(task.ID, StatusBlocked); err != nil {
+		t.Fatal(err)
+	}
+	if ids, _ := database.ListDueAutoRetries(time.Now()); len(ids) != 0 {
+		t.Errorf("status change should cancel the pending retry, got due %v", ids)
+	}
+}
+
+func TestRetryTaskResetsRetryCount(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	task := &Task{Title: "flaky", Status: StatusBlocked}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.SetNoAutoRetry(task.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.ScheduleAutoRetry(task.ID, 3, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.RetryTask(task.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	state, err := database.GetAutoRetryState(task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Count != 0 || state.RetryAt != nil {
+		t.Errorf("manual retry should reset the count: %+v", state)
+	}
+	if !state.Disabled {
+		t.Error("manual retry should keep the --no-auto-retry opt-out")
+	}
+}
@@ -0,0 +1,171 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Schedule is a recurring task: when Spec (a cron spec, see internal/cron)
+// comes due, the daemon creates a task from the template fields and, if
+// Queue is set, queues it.
+type Schedule struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Spec      string     `json:"spec"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body,omitempty"`
+	Project   string     `json:"project,omitempty"`
+	Type      string     `json:"type,omitempty"`
+	Executor  string     `json:"executor,omitempty"`
+	Tags      string     `json:"tags,omitempty"`
+	Queue     bool       `json:"queue"`
+	LastRunAt *LocalTime `json:"last_run_at,omitempty"` // the slot last fired
+	CreatedAt LocalTime  `json:"created_at"`
+}
+
+const scheduleColumns = `id, name, spec, title, body, project, type, executor, tags, queue, last_run_at, created_at`
+
+func scanSchedule(scan func(...interface{}) error) (*Schedule, error) {
+	s := &Schedule{}
+	var lastRun sql.NullTime
+	if err := scan(&s.ID, &s.Name, &s.Spec, &s.Title, &s.Body, &s.Project, &s.Type, &s.Executor, &s.Tags, &s.Queue, &lastRun, &s.CreatedAt); err != nil {
+		return nil, err
+	}
+	if lastRun.Valid {
+		s.LastRunAt = &LocalTime{Time: lastRun.Time.Local()}
+	}
+	return s, nil
+}
+
+// CreateSchedule stores a new schedule. Names are unique.
+func (db *DB) CreateSchedule(s *Schedule) error {
+	result, err := db.Exec(`
+		INSERT INTO schedules (name, spec, title, body, project, type, executor, tags, queue)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, s.Name, s.Spec, s.Title, s.Body, s.Project, s.Type, s.Executor, s.Tags, boolToInt(s.Queue))
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			return fmt.Errorf("a schedule named %q already exists", s.Name)
+		}
+		return fmt.Errorf("insert schedule: %w", err)
+	}
+	s.ID, _ = result.LastInsertId()
+	s.CreatedAt = LocalTime{Time: time.Now()}
+	return nil
+}
+
+// ListSchedules returns all schedules by name.
+func (db *DB) ListSchedules() ([]*Schedule, error) {
+	rows, err := db.Query(`SELECT ` + scheduleColumns + ` FROM schedules ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("query schedules: %w", err)
+	}
+	defer rows.Close()
+
+	var schedules []*Schedule
+	for rows.Next() {
+		s, err := scanSchedule(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("scan schedule: %w", err)
+		}
+		schedules = append(schedules, s)
+	}
+	return schedules, rows.Err()
+}
+
+// GetSchedule returns the schedule with the given name, or with the given ID
+// when ref is a number. It returns nil if there is none.
+func (db *DB) GetSchedule(ref string) (*Schedule, error) {
+	s, err := scanSchedule(db.QueryRow(`SELECT `+scheduleColumns+` FROM schedules WHERE name = ? OR CAST(id AS TEXT) = ? ORDER BY name = ? DESC LIMIT 1`, ref, ref, ref).Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get schedule: %w", err)
+	}
+	return s, nil
+}
+
+// DeleteSchedule removes a schedule. Tasks it already created are kept.
+func (db *DB) DeleteSchedule(id int64) error {
+	if _, err := db.Exec(`DELETE FROM schedules WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("delete schedule: %w", err)
+	}
+	return nil
+}
+
+// ClaimScheduleRun marks slot as the schedule's latest run. It reports false
+// when the schedule has already fired at or after slot (or no longer exists),
+// so a slot fires at most once even across daemon restarts.
+func (db *DB) ClaimScheduleRun(scheduleID int64, slot time.Time) (bool, error) {
+	ts := slot.UTC().Format("2006-01-02 15:04:05")
+	result, err := db.Exec(`
+		UPDATE schedules SET last_run_at = ?
+		WHERE id = ? AND (last_run_at IS NULL OR last_run_at < ?)
+	`, ts, scheduleID, ts)
+	if err != nil {
+		return false, fmt.Errorf("claim schedule run: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// ReleaseScheduleRun undoes ClaimScheduleRun for slot after its task couldn't
+// be created, putting last_run_at back to prev (nil if the schedule had never
+// fired) so the next tick retries the slot. It does nothing if another claim
+// has moved last_run_at on since.
+func (db *DB) ReleaseScheduleRun(scheduleID int64, slot time.Time, prev *LocalTime) error {
+	var prevTS interface{}
+	if prev != nil {
+		prevTS = prev.UTC().Format("2006-01-02 15:04:05")
+	}
+	_, err := db.Exec(`
+		UPDATE schedules SET last_run_at = ?
+		WHERE id = ? AND last_run_at = ?
+	`, prevTS, scheduleID, slot.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return fmt.Errorf("release schedule run: %w", err)
+	}
+	return nil
+}
+
+// RecordScheduleRun links a task to the schedule slot that created it.
+func (db *DB) RecordScheduleRun(scheduleID, taskID int64, slot time.Time) error {
+	_, err := db.Exec(`
+		INSERT INTO schedule_runs (schedule_id, task_id, scheduled_for)
+		VALUES (?, ?, ?)
+	`, scheduleID, taskID, slot.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return fmt.Errorf("record schedule run: %w", err)
+	}
+	return nil
+}
+
+// ScheduledTaskIDs reports which of taskIDs were created by a schedule.
+func (db *DB) ScheduledTaskIDs(taskIDs []int64) (map[int64]bool, error) {
+	scheduled := make(map[int64]bool)
+	if len(taskIDs) == 0 {
+		return scheduled, nil
+	}
+	placeholders := make([]string, len(taskIDs))
+	args := make([]interface{}, len(taskIDs))
+	for i, id := range taskIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	rows, err := db.Query(`SELECT DISTINCT task_id FROM schedule_runs WHERE task_id IN (`+strings.Join(placeholders, ",")+`)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query scheduled tasks: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan scheduled task: %w", err)
+		}
+		scheduled[id] = true
+	}
+	return scheduled, rows.Err()
+}
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedules(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	s := &Schedule{Name: "weekly-deps", Spec: "0 9 * * mon", Title: "Review dependabot PRs", Queue: true}
+	if err := database.CreateSchedule(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.CreateSchedule(&Schedule{Name: "weekly-deps", Spec: "@daily", Title: "dup"}); err == nil {
+		t.Error("created two schedules with the same name")
+	}
+
+	byName, err := database.GetSchedule("weekly-deps")
+	if err != nil || byName == nil || byName.ID != s.ID || !byName.Queue || byName.LastRunAt != nil {
+		t.Fatalf("GetSchedule by name = %+v, %v", byName, err)
+	}
+	if byID, _ := database.GetSchedule("1"); byID == nil || byID.Name != "weekly-deps" {
+		t.Errorf("GetSchedule by id = %+v", byID)
+	}
+	if missing, err := database.GetSchedule("nope"); missing != nil || err != nil {
+		t.Errorf("missing schedule = %+v, %v", missing, err)
+	}
+
+	slot := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	if ok, err := database.ClaimScheduleRun(s.ID, slot); !ok || err != nil {
+		t.Fatalf("first claim = %v, %v", ok, err)
+	}
+	if ok, _ := database.ClaimScheduleRun(s.ID, slot); ok {
+		t.Error("claimed the same slot twice")
+	}
+	if ok, _ := database.ClaimScheduleRun(s.ID, slot.Add(-time.Hour)); ok {
+		t.Error("claimed an earlier slot")
+	}
+	got, _ := database.GetSchedule("weekly-deps")
+	if got.LastRunAt == nil || !got.LastRunAt.Time.Equal(slot) {
+		t.Errorf("LastRunAt = %v, want %v", got.LastRunAt, slot)
+	}
+
+	if err := database.DeleteSchedule(s.ID); err != nil {
+		t.Fatal(err)
+	}
+	if list, _ := database.ListSchedules(); len(list) != 0 {
+		t.Errorf("schedules after delete = %+v", list)
+	}
+}
@@ -0,0 +1,238 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Snippet highlight markers. Search snippets wrap each matched term in these
+// so callers can style the match however suits their output.
+const (
+	SearchMatchStart = "\x02"
+	SearchMatchEnd   = "\x03"
+)
+
+// migrateTasksFTS creates tasks_fts, an FTS5 index over task titles, bodies,
+// and summaries, plus the triggers that keep it in sync with tasks. SQLite
+// builds without FTS5 skip the index; SearchTaskText then falls back to LIKE.
+func migrateTasksFTS(tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS tasks_fts USING fts5(
+		title, body, summary,
+		content='tasks', content_rowid='id'
+	)`)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such module") {
+			return nil
+		}
+		return err
+	}
+	for _, stmt := range []string{
+		`CREATE TRIGGER IF NOT EXISTS tasks_fts_insert AFTER INSERT ON tasks BEGIN
+			INSERT INTO tasks_fts(rowid, title, body, summary) VALUES (new.id, new.title, new.body, new.summary);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS tasks_fts_delete AFTER DELETE ON tasks BEGIN
+			INSERT INTO tasks_fts(tasks_fts, rowid, title, body, summary) VALUES ('delete', old.id, old.title, old.body, old.summary);
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS tasks_fts_update AFTER UPDATE OF title, body, summary ON tasks BEGIN
+			INSERT INTO tasks_fts(tasks_fts, rowid, title, body, summary) VALUES ('delete', old.id, old.title, old.body, old.summary);
+			INSERT INTO tasks_fts(rowid, title, body, summary) VALUES (new.id, new.title, new.body, new.summary);
+		END`,
+		// Index the tasks that existed before the table did.
+		`INSERT INTO tasks_fts(tasks_fts) VALUES ('rebuild')`,
+	} {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TaskSearchOptions filters SearchTaskText.
+type TaskSearchOptions struct {
+	Project string
+	Status  string // Empty searches every status, including done and archived
+	Limit   int
+}
+
+// TaskSearchResult is one full-text match. Snippet is an excerpt of the best
+// matching field with matches wrapped in SearchMatchStart/SearchMatchEnd.
+type TaskSearchResult struct {
+	ID      int64
+	Title   string
+	Status  string
+	Project string
+	Snippet string
+}
+
+// SearchTaskText searches task titles, bodies, and summaries for every term in
+// query, best matches first. It uses the tasks_fts index when SQLite has FTS5
+// and plain LIKE matching (ordered by recency) otherwise.
+func (db *DB) SearchTaskText(query string, opts TaskSearchOptions) ([]*TaskSearchResult, error) {
+	terms := strings.Fields(query)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("search query is empty")
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 20
+	}
+	where, args := db.listTasksFilter(ListTasksOptions{
+		Project:       opts.Project,
+		Status:        opts.Status,
+		IncludeClosed: true,
+	})
+
+	var hasFTS bool
+	if err := db.QueryRow(`SELECT COUNT(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = 'tasks_fts'`).Scan(&hasFTS); err != nil {
+		return nil, fmt.Errorf("check search index: %w", err)
+	}
+	if hasFTS {
+		return db.searchTasksFTS(terms, where, args, opts.Limit)
+	}
+	return db.searchTasksLike(terms, where, args, opts.Limit)
+}
+
+func (db *DB) searchTasksFTS(terms []string, where string, args []interface{}, limit int) ([]*TaskSearchResult, error) {
+	// Quote each term so punctuation in the query can't be read as FTS5
+	// syntax, and prefix-match it so "auth" finds "authentication".
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"*`
+	}
+
+	query := `
+		WITH m AS (
+			SELECT rowid, bm25(tasks_fts) AS score,
+			       snippet(tasks_fts, -1, char(2), char(3), '…', 12) AS snip
+			FROM tasks_fts WHERE tasks_fts MATCH ?
+		)
+		SELECT id, title, status, project, COALESCE(m.snip, '')
+		FROM tasks JOIN m ON m.rowid = tasks.id
+		WHERE 1=1` + where + `
+		ORDER BY m.score, id DESC
+		LIMIT ?`
+	queryArgs := append([]interface{}{strings.Join(quoted, " ")}, args...)
+	queryArgs = append(queryArgs, limit)
+
+	rows, err := db.Query(query, queryArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("search tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*TaskSearchResult
+	for rows.Next() {
+		r := &TaskSearchResult{}
+		if err := rows.Scan(&r.ID, &r.Title, &r.Status, &r.Project, &r.Snippet); err != nil {
+			return nil, fmt.Errorf("scan search result: %w", err)
+		}
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+func (db *DB) searchTasksLike(terms []string, where string, args []interface{}, limit int) ([]*TaskSearchResult, error) {
+	var match string
+	var matchArgs []interface{}
+	for _, term := range terms {
+		pattern := "%" + escapeLike(term) + "%"
+		match += ` AND (title LIKE ? ESCAPE '\' OR COALESCE(body, '') LIKE ? ESCAPE '\' OR COALESCE(summary, '') LIKE ? ESCAPE '\')`
+		matchArgs = append(matchArgs, pattern, pattern, pattern)
+	}
+
+	query := `
+		SELECT id, title, status, project, COALESCE(body, ''), COALESCE(summary, '')
+		FROM tasks
+		WHERE 1=1` + match + where + `
+		ORDER BY updated_at DESC, id DESC
+		LIMIT ?`
+	queryArgs := append(matchArgs, args...)
+	queryArgs = append(queryArgs, limit)
+
+	rows, err := db.Query(query, queryArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("search tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*TaskSearchResult
+	for rows.Next() {
+		r := &TaskSearchResult{}
+		var body, summary string
+		if err := rows.Scan(&r.ID, &r.Title, &r.Status, &r.Project, &body, &summary); err != nil {
+			return nil, fmt.Errorf("scan search result: %w", err)
+		}
+		r.Snippet = likeSnippet(terms, r.Title, body, summary)
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+// likeSnippet approximates FTS5's snippet() for the LIKE fallback: a window
+// around the first match in the first field containing any term, with every
+// term occurrence in the window highlighted.
+func likeSnippet(terms []string, fields ...string) string {
+	const radius = 40
+	for _, field := range fields {
+		text := strings.Join(strings.Fields(field), " ")
+		lower := strings.ToLower(text)
+		if len(lower) != len(text) {
+			// Case folding changed byte offsets; don't index text with them.
+			lower = text
+		}
+		at := -1
+		for _, term := range terms {
+			if i := strings.Index(lower, strings.ToLower(term)); i >= 0 && (at < 0 || i < at) {
+				at = i
+			}
+		}
+		if at < 0 {
+			continue
+		}
+
+		start, end := max(at-radius, 0), min(at+radius*2, len(text))
+		for start > 0 && !utf8.RuneStart(text[start]) {
+			start--
+		}
+		for end < len(text) && !utf8.RuneStart(text[end]) {
+			end++
+		}
+		window := highlightTerms(text[start:end], terms)
+		if start > 0 {
+			window = "…" + window
+		}
+		if end < len(text) {
+			window += "…"
+		}
+		return window
+	}
+	return ""
+}
+
+// highlightTerms wraps each case-insensitive occurrence of any term in s with
+// the search match markers.
+func highlightTerms(s string, terms []string) string {
+	lower := strings.ToLower(s)
+	if len(lower) != len(s) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		matched := 0
+		for _, term := range terms {
+			t := strings.ToLower(term)
+			if len(t) > matched && strings.HasPrefix(lower[i:], t) {
+				matched = len(t)
+			}
+		}
+		if matched == 0 {
+			b.WriteByte(s[i])
+			i++
+			continue
+		}
+		b.WriteString(SearchMatchStart + s[i:i+matched] + SearchMatchEnd)
+		i += matched
+	}
+	return b.String()
+}
@@ -0,0 +1,118 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSearchTaskTextFTS(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	auth := &Task{Title: "Fix auth callback", Body: "The redirect drops the state param", Status: StatusBacklog, Project: "personal"}
+	docs := &Task{Title: "Write docs", Body: "Explain the authentication flow", Status: StatusDone, Project: "personal"}
+	other := &Task{Title: "Bump deps", Body: "Nothing to see", Status: StatusBacklog, Project: "personal"}
+	for _, tk := range []*Task{auth, docs, other} {
+		if err := database.CreateTask(tk); err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+	}
+
+	results, err := database.SearchTaskText("auth", TaskSearchOptions{})
+	if err != nil {
+		t.Fatalf("SearchTaskText: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 prefix matches for auth (done tasks included), got %d", len(results))
+	}
+	for _, r := range results {
+		if r.ID == other.ID {
+			t.Errorf("unrelated task matched")
+		}
+		if !strings.Contains(r.Snippet, SearchMatchStart) {
+			t.Errorf("snippet %q has no highlighted match", r.Snippet)
+		}
+	}
+
+	results, err = database.SearchTaskText("auth", TaskSearchOptions{Status: StatusDone})
+	if err != nil {
+		t.Fatalf("SearchTaskText with status: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != docs.ID {
+		t.Errorf("expected only the done task, got %+v", results)
+	}
+
+	// Updates and summaries are indexed by the triggers.
+	if err := database.UpdateTaskSummary(other.ID, "Upgraded the websocket library"); err != nil {
+		t.Fatalf("UpdateTaskSummary: %v", err)
+	}
+	results, err = database.SearchTaskText("websocket", TaskSearchOptions{})
+	if err != nil {
+		t.Fatalf("SearchTaskText after update: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != other.ID {
+		t.Errorf("expected the updated summary to match, got %+v", results)
+	}
+
+	// Deleted tasks drop out of the index; query punctuation is not FTS syntax.
+	if err := database.DeleteTask(auth.ID); err != nil {
+		t.Fatalf("DeleteTask: %v", err)
+	}
+	results, err = database.SearchTaskText(`"auth" callback:`, TaskSearchOptions{})
+	if err != nil {
+		t.Fatalf("SearchTaskText with punctuation: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected deleted task to be gone from the index, got %d results", len(results))
+	}
+}
+
+func TestSearchTaskTextLikeFallback(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	// Simulate a SQLite build without FTS5.
+	for _, stmt := range []string{
+		`DROP TRIGGER tasks_fts_insert`,
+		`DROP TRIGGER tasks_fts_delete`,
+		`DROP TRIGGER tasks_fts_update`,
+		`DROP TABLE tasks_fts`,
+	} {
+		if _, err := database.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	match := &Task{Title: "Flaky test", Body: "The retry test in ci is flaky under load", Status: StatusBacklog, Project: "personal"}
+	miss := &Task{Title: "Flaky network", Body: "Unrelated", Status: StatusBacklog, Project: "personal"}
+	for _, tk := range []*Task{match, miss} {
+		if err := database.CreateTask(tk); err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+	}
+
+	results, err := database.SearchTaskText("flaky retry", TaskSearchOptions{})
+	if err != nil {
+		t.Fatalf("SearchTaskText: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != match.ID {
+		t.Fatalf("expected only the task containing every term, got %+v", results)
+	}
+	if !strings.Contains(results[0].Snippet, SearchMatchStart+"Flaky"+SearchMatchEnd) {
+		t.Errorf("snippet %q does not highlight the match", results[0].Snippet)
+	}
+}
+
+func TestLikeSnippet(t *testing.T) {
+	body := strings.Repeat("lorem ", 20) + "the Needle is here " + strings.Repeat("ipsum ", 20)
+	got := likeSnippet([]string{"needle"}, "title", body)
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("expected a trimmed window, got %q", got)
+	}
+	if !strings.Contains(got, SearchMatchStart+"Needle"+SearchMatchEnd) {
+		t.Errorf("expected highlighted Needle, got %q", got)
+	}
+	if got := likeSnippet([]string{"absent"}, "title", "body"); got != "" {
+		t.Errorf("expected empty snippet without a match, got %q", got)
+	}
+}
@@ -0,0 +1,263 @@
+package db
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Secret settings (API keys) can be kept out of the settings table in
+// plaintext. The secret_storage setting picks where new values go:
+//
+//   - plaintext (default): stored as-is, as before.
+//   - keychain: stored in the OS keychain (macOS Keychain via `security`,
+//     libsecret via `secret-tool`); the settings row only holds a reference.
+//   - passphrase: AES-256-GCM encrypted with a key derived (PBKDF2-SHA256)
+//     from the TY_SECRET_PASSPHRASE environment variable.
+//
+// GetSetting decrypts transparently whatever form a value is in, so changing
+// secret_storage never strands an existing key; MigrateSecretSettings rewrites
+// existing values into the current form. When the chosen backend isn't usable
+// (no keychain tool, passphrase unset) values fall back to plaintext and
+// SetSecretSetting reports it so callers can warn.
+const (
+	SettingSecretStorage = "secret_storage"
+
+	SecretStoragePlaintext  = "plaintext"
+	SecretStorageKeychain   = "keychain"
+	SecretStoragePassphrase = "passphrase"
+
+	// SecretPassphraseEnv holds the passphrase for passphrase storage. It is
+	// read on every access and never written anywhere.
+	SecretPassphraseEnv = "TY_SECRET_PASSPHRASE"
+)
+
+// secretSettings are the settings treated as secrets.
+var secretSettings = []string{"anthropic_api_key"}
+
+const (
+	encryptedSecretPrefix = "enc:v1:"
+	keychainSecretPrefix  = "keychain:v1:"
+
+	secretSaltSize       = 16
+	secretKDFIterations  = 600000
+	keychainService      = "taskyou"
+	secretStorageDefault = SecretStoragePlaintext
+)
+
+// SecretStorageModes lists the valid secret_storage values.
+func SecretStorageModes() []string {
+	return []string{SecretStoragePlaintext, SecretStorageKeychain, SecretStoragePassphrase}
+}
+
+// IsSecretSetting reports whether key holds a secret.
+func IsSecretSetting(key string) bool {
+	return slices.Contains(secretSettings, key)
+}
+
+// SecretStorageMode returns the configured secret_storage mode.
+func (db *DB) SecretStorageMode() string {
+	mode, _ := db.GetSetting(SettingSecretStorage)
+	if !slices.Contains(SecretStorageModes(), mode) {
+		return secretStorageDefault
+	}
+	return mode
+}
+
+// SecretSettingStorage reports how key's current value is stored, or "" if it
+// isn't set.
+func (db *DB) SecretSettingStorage(key string) (string, error) {
+	raw, err := db.getRawSetting(key)
+	if err != nil || raw == "" {
+		return "", err
+	}
+	return secretStorageOf(raw), nil
+}
+
+func secretStorageOf(raw string) string {
+	switch {
+	case strings.HasPrefix(raw, encryptedSecretPrefix):
+		return SecretStoragePassphrase
+	case strings.HasPrefix(raw, keychainSecretPrefix):
+		return SecretStorageKeychain
+	}
+	return SecretStoragePlaintext
+}
+
+// SetSecretSetting stores a secret setting using the configured
+// secret_storage mode and returns the storage actually used, which is
+// plaintext when the configured backend is unavailable.
+func (db *DB) SetSecretSetting(key, value string) (string, error) {
+	old, err := db.getRawSetting(key)
+	if err != nil {
+		return "", err
+	}
+
+	stored, storage := value, SecretStoragePlaintext
+	if value != "" {
+		stored, storage = db.sealSecret(key, value)
+	}
+	if err := db.setRawSetting(key, stored); err != nil {
+		return "", err
+	}
+
+	// Drop a keychain entry the new value no longer points at.
+	if strings.HasPrefix(old, keychainSecretPrefix) && old != stored {
+		_ = secretKeychain.Delete(strings.TrimPrefix(old, keychainSecretPrefix))
+	}
+	return storage, nil
+}
+
+// MigrateSecretSettings rewrites every stored secret into the current
+// secret_storage mode (e.g. after switching from plaintext to keychain). It
+// returns how many values changed form and the storage last used, which is
+// plaintext if the mode's backend turned out to be unavailable.
+func (db *DB) MigrateSecretSettings() (int, string, error) {
+	migrated := 0
+	mode := db.SecretStorageMode()
+	storage := mode
+	for _, key := range secretSettings {
+		raw, err := db.getRawSetting(key)
+		if err != nil {
+			return migrated, storage, err
+		}
+		if raw == "" {
+			continue
+		}
+		value, err := db.revealSecret(raw)
+		if err != nil {
+			return migrated, storage, fmt.Errorf("%s: %w", key, err)
+		}
+		if secretStorageOf(raw) == mode {
+			continue
+		}
+		used, err := db.SetSecretSetting(key, value)
+		if err != nil {
+			return migrated, storage, err
+		}
+		storage = used
+		if used != secretStorageOf(raw) {
+			migrated++
+		}
+	}
+	return migrated, storage, nil
+}
+
+// sealSecret converts a secret into its stored form for the configured mode,
+// falling back to plaintext when that mode can't be used.
+func (db *DB) sealSecret(key, value string) (string, string) {
+	switch db.SecretStorageMode() {
+	case SecretStorageKeychain:
+		account := db.keychainAccount(key)
+		if err := secretKeychain.Set(account, value); err == nil {
+			return keychainSecretPrefix + account, SecretStorageKeychain
+		}
+	case SecretStoragePassphrase:
+		if passphrase := os.Getenv(SecretPassphraseEnv); passphrase != "" {
+			if sealed, err := encryptSecret(passphrase, value); err == nil {
+				return encryptedSecretPrefix + sealed, SecretStoragePassphrase
+			}
+		}
+	}
+	return value, SecretStoragePlaintext
+}
+
+// revealSecret returns the plaintext of a stored secret.
+func (db *DB) revealSecret(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, encryptedSecretPrefix):
+		passphrase := os.Getenv(SecretPassphraseEnv)
+		if passphrase == "" {
+			return "", fmt.Errorf("secret is encrypted; set %s to read it", SecretPassphraseEnv)
+		}
+		return decryptSecret(passphrase, strings.TrimPrefix(raw, encryptedSecretPrefix))
+	case strings.HasPrefix(raw, keychainSecretPrefix):
+		return secretKeychain.Get(strings.TrimPrefix(raw, keychainSecretPrefix))
+	}
+	return raw, nil
+}
+
+// keychainAccount names the keychain entry for key. The database path is part
+// of it so profiles (separate databases) don't overwrite each other's keys.
+func (db *DB) keychainAccount(key string) string {
+	return key + "@" + db.path
+}
+
+// derivedKeys caches PBKDF2 output: derivation is deliberately slow and
+// GetSetting runs on hot paths like the TUI's autocomplete.
+var derivedKeys sync.Map
+
+func deriveSecretKey(passphrase string, salt []byte) ([]byte, error) {
+	sum := sha256.Sum256(append([]byte(passphrase+"\x00"), salt...))
+	if key, ok := derivedKeys.Load(sum); ok {
+		return key.([]byte), nil
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, secretKDFIterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	derivedKeys.Store(sum, key)
+	return key, nil
+}
+
+// encryptSecret returns base64(salt | nonce | ciphertext).
+func encryptSecret(passphrase, plaintext string) (string, error) {
+	salt := make([]byte, secretSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	gcm, err := secretCipher(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	out := append(append(salt, nonce...), gcm.Seal(nil, nonce, []byte(plaintext), nil)...)
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+func decryptSecret(passphrase, sealed string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", fmt.Errorf("decode secret: %w", err)
+	}
+	if len(data) < secretSaltSize {
+		return "", errors.New("secret is truncated")
+	}
+	gcm, err := secretCipher(passphrase, data[:secretSaltSize])
+	if err != nil {
+		return "", err
+	}
+	data = data[secretSaltSize:]
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("secret is truncated")
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt secret (wrong %s?)", SecretPassphraseEnv)
+	}
+	return string(plaintext), nil
+}
+
+func secretCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := deriveSecretKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
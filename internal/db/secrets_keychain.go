@@ -0,0 +1,95 @@
+package db
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// keychain stores secrets outside the database.
+type keychain interface {
+	Get(account string) (string, error)
+	Set(account, value string) error
+	Delete(account string) error
+}
+
+// secretKeychain is the OS keychain; tests swap in a fake.
+var secretKeychain keychain = systemKeychain{}
+
+// errKeychainUnavailable means no supported keychain tool is installed.
+var errKeychainUnavailable = errors.New("no OS keychain available (needs macOS `security` or libsecret `secret-tool`)")
+
+// systemKeychain shells out to the platform's keychain CLI rather than linking
+// a keychain library, so the binary stays cgo-free.
+type systemKeychain struct{}
+
+func (systemKeychain) Get(account string) (string, error) {
+	var cmd *exec.Cmd
+	switch {
+	case runtime.GOOS == "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", keychainService, "-a", account, "-w")
+	case hasSecretTool():
+		cmd = exec.Command("secret-tool", "lookup", "service", keychainService, "account", account)
+	default:
+		return "", errKeychainUnavailable
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("read %s from keychain: %w", account, err)
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+func (systemKeychain) Set(account, value string) error {
+	var cmd *exec.Cmd
+	switch {
+	case runtime.GOOS == "darwin":
+		// `security -i` reads the command from stdin, keeping the secret out
+		// of argv where ps could see it.
+		cmd = exec.Command("security", "-i")
+		cmd.Stdin = strings.NewReader(securityAddCommand(account, value))
+	case hasSecretTool():
+		// secret-tool reads the secret from stdin, keeping it off the command line.
+		cmd = exec.Command("secret-tool", "store", "--label", "TaskYou "+account, "service", keychainService, "account", account)
+		cmd.Stdin = strings.NewReader(value)
+	default:
+		return errKeychainUnavailable
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("write %s to keychain: %w: %s", account, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (systemKeychain) Delete(account string) error {
+	var cmd *exec.Cmd
+	switch {
+	case runtime.GOOS == "darwin":
+		cmd = exec.Command("security", "delete-generic-password", "-s", keychainService, "-a", account)
+	case hasSecretTool():
+		cmd = exec.Command("secret-tool", "clear", "service", keychainService, "account", account)
+	default:
+		return errKeychainUnavailable
+	}
+	return cmd.Run()
+}
+
+// securityAddCommand is the `security -i` input that stores value for account.
+// The value goes in hex (-X) so it needs no quoting.
+func securityAddCommand(account, value string) string {
+	return fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n",
+		securityQuote(keychainService), securityQuote(account), hex.EncodeToString([]byte(value)))
+}
+
+// securityQuote quotes s for the `security -i` command parser.
+func securityQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func hasSecretTool() bool {
+	_, err := exec.LookPath("secret-tool")
+	return err == nil
+}
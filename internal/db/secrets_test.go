@@ -0,0 +1,167 @@
+package db
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type fakeKeychain struct {
+	entries     map[string]string
+	unavailable bool
+}
+
+func (k *fakeKeychain) Get(account string) (string, error) {
+	v, ok := k.entries[account]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return v, nil
+}
+
+func (k *fakeKeychain) Set(account, value string) error {
+	if k.unavailable {
+		return errKeychainUnavailable
+	}
+	k.entries[account] = value
+	return nil
+}
+
+func (k *fakeKeychain) Delete(account string) error {
+	delete(k.entries, account)
+	return nil
+}
+
+func useFakeKeychain(t *testing.T) *fakeKeychain {
+	t.Helper()
+	fake := &fakeKeychain{entries: map[string]string{}}
+	orig := secretKeychain
+	secretKeychain = fake
+	t.Cleanup(func() { secretKeychain = orig })
+	return fake
+}
+
+const testAPIKey = "sk-ant-test-1234567890"
+
+func TestSecretSettingPlaintextByDefault(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	storage, err := database.SetSecretSetting("anthropic_api_key", testAPIKey)
+	if err != nil || storage != SecretStoragePlaintext {
+		t.Fatalf("SetSecretSetting = %q, %v", storage, err)
+	}
+	if raw, _ := database.getRawSetting("anthropic_api_key"); raw != testAPIKey {
+		t.Errorf("expected plaintext row, got %q", raw)
+	}
+}
+
+func TestSecretSettingPassphrase(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	t.Setenv(SecretPassphraseEnv, "correct horse")
+	if err := database.SetSetting(SettingSecretStorage, SecretStoragePassphrase); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.SetSetting("anthropic_api_key", testAPIKey); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, _ := database.getRawSetting("anthropic_api_key")
+	if !strings.HasPrefix(raw, encryptedSecretPrefix) || strings.Contains(raw, testAPIKey) {
+		t.Fatalf("expected an encrypted row, got %q", raw)
+	}
+	if got, err := database.GetSetting("anthropic_api_key"); err != nil || got != testAPIKey {
+		t.Errorf("GetSetting = %q, %v; want the decrypted key", got, err)
+	}
+
+	t.Setenv(SecretPassphraseEnv, "wrong")
+	if _, err := database.GetSetting("anthropic_api_key"); err == nil {
+		t.Error("expected the wrong passphrase to fail")
+	}
+	t.Setenv(SecretPassphraseEnv, "")
+	if _, err := database.GetSetting("anthropic_api_key"); err == nil {
+		t.Error("expected a missing passphrase to fail")
+	}
+
+	// Without a passphrase new values fall back to plaintext, and say so.
+	storage, err := database.SetSecretSetting("anthropic_api_key", testAPIKey)
+	if err != nil || storage != SecretStoragePlaintext {
+		t.Errorf("expected plaintext fallback, got %q, %v", storage, err)
+	}
+}
+
+func TestSecretSettingKeychainMigration(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+	fake := useFakeKeychain(t)
+
+	// An existing plaintext key is moved into the keychain when the mode changes.
+	if err := database.SetSetting("anthropic_api_key", testAPIKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.SetSetting(SettingSecretStorage, SecretStorageKeychain); err != nil {
+		t.Fatal(err)
+	}
+	migrated, storage, err := database.MigrateSecretSettings()
+	if err != nil || migrated != 1 || storage != SecretStorageKeychain {
+		t.Fatalf("MigrateSecretSettings = %d, %q, %v", migrated, storage, err)
+	}
+
+	raw, _ := database.getRawSetting("anthropic_api_key")
+	if !strings.HasPrefix(raw, keychainSecretPrefix) || strings.Contains(raw, testAPIKey) {
+		t.Fatalf("expected a keychain reference, got %q", raw)
+	}
+	if got, err := database.GetSetting("anthropic_api_key"); err != nil || got != testAPIKey {
+		t.Errorf("GetSetting = %q, %v", got, err)
+	}
+	if s, _ := database.SecretSettingStorage("anthropic_api_key"); s != SecretStorageKeychain {
+		t.Errorf("SecretSettingStorage = %q", s)
+	}
+
+	// Moving back to plaintext clears the keychain entry.
+	if err := database.SetSetting(SettingSecretStorage, SecretStoragePlaintext); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := database.MigrateSecretSettings(); err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := database.getRawSetting("anthropic_api_key"); raw != testAPIKey {
+		t.Errorf("expected plaintext after migrating back, got %q", raw)
+	}
+	if len(fake.entries) != 0 {
+		t.Errorf("expected keychain entry to be removed, got %v", fake.entries)
+	}
+}
+
+func TestSecretSettingKeychainUnavailable(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+	fake := useFakeKeychain(t)
+	fake.unavailable = true
+
+	if err := database.SetSetting(SettingSecretStorage, SecretStorageKeychain); err != nil {
+		t.Fatal(err)
+	}
+	storage, err := database.SetSecretSetting("anthropic_api_key", testAPIKey)
+	if err != nil || storage != SecretStoragePlaintext {
+		t.Errorf("expected plaintext fallback, got %q, %v", storage, err)
+	}
+	if got, _ := database.GetSetting("anthropic_api_key"); got != testAPIKey {
+		t.Errorf("GetSetting = %q", got)
+	}
+}
+
+func TestSecurityAddCommandHidesSecret(t *testing.T) {
+	cmd := securityAddCommand(`anthropic "key"`, "sk-secret value")
+	if strings.Contains(cmd, "sk-secret") {
+		t.Errorf("command %q carries the secret in plain text", cmd)
+	}
+	if !strings.Contains(cmd, `-a "anthropic \"key\""`) {
+		t.Errorf("command %q doesn't quote the account", cmd)
+	}
+	if !strings.HasSuffix(cmd, "-X 736b2d7365637265742076616c7565\n") {
+		t.Errorf("command %q doesn't pass the value in hex", cmd)
+	}
+}
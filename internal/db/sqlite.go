@@ -62,6 +62,7 @@ type DB struct {
 	*sql.DB
 	path         string
 	eventEmitter EventEmitter
+	logBatcher   *LogBatcher // set by BatchTaskLogs
 }
 
 // Path returns the path to the database file.
@@ -69,8 +70,32 @@ func (db *DB) Path() string {
 	return db.path
 }
 
-// Open opens or creates a SQLite database at the given path.
+// Files returns the files that make up the database at path: the main file
+// plus the WAL-mode sidecars, path-wal (committed transactions not yet
+// checkpointed into the main file) and path-shm (the WAL index). The sidecars
+// exist while any connection is open. Code that copies, moves or deletes the
+// database must handle all three together; copying only the main file while
+// the daemon runs can lose recent writes.
+func Files(path string) []string {
+	return []string{path, path + "-wal", path + "-shm"}
+}
+
+// Open opens or creates a SQLite database at the given path. A new database,
+// or one from before versioned migrations, gets the whole schema. One stamped
+// at an older schema version is refused with ErrPendingMigrations unless
+// AutoMigrationsEnv is set.
 func Open(path string) (*DB, error) {
+	return open(path, false)
+}
+
+// OpenWithPendingMigrations opens the database at path like Open, but leaves
+// pending versioned migrations for the caller instead of refusing. It is for
+// the commands that inspect, back up or migrate an out-of-date database.
+func OpenWithPendingMigrations(path string) (*DB, error) {
+	return open(path, true)
+}
+
+func open(path string, allowPending bool) (*DB, error) {
 	// Ensure directory exists
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -84,7 +109,11 @@ func Open(path string) (*DB, error) {
 	// and fail immediately with SQLITE_BUSY under contention.
 	// Note: the bare _busy_timeout DSN param does NOT work with
 	// modernc.org/sqlite, but _pragma=busy_timeout(N) does.
-	dsn := path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)"
+	// synchronous=NORMAL is the recommended setting under WAL: commits skip
+	// the fsync (the WAL is synced at checkpoints), which shortens how long
+	// each writer holds the lock; a power loss can only drop the last
+	// transactions, never corrupt the file.
+	dsn := path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_pragma=foreign_keys(1)"
 
 	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
@@ -109,6 +138,11 @@ func Open(path string) (*DB, error) {
 
 	// Run migrations
 	if err := wrapped.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+	if err := wrapped.migrateVersioned(allowPending); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("migrate: %w", err)
 	}
 
@@ -129,6 +163,10 @@ const permModeAutoMigrationKey = "migration:auto_means_accept_edits_v1"
 // rejects. Rewrite those rows to "" (no override / Claude's global default).
 const modelClaudeSlugMigrationKey = "migration:clear_model_claude_slug_v1"
 
+// legacyPriorityDropMigrationKey marks that the old priority column has been
+// dropped, so the integer priority column added later is never dropped again.
+const legacyPriorityDropMigrationKey = "migration:drop_legacy_priority_v1"
+
 // migrate runs database migrations.
 func (db *DB) migrate() error {
 	migrations := []string{
@@ -367,6 +405,19 @@ func (db *DB) migrate() error {
 		// no longer destroys its worktree or Claude transcript up front — that only
 		// happens when the sweep fires, and even then the transcript is preserved.
 		`ALTER TABLE tasks ADD COLUMN deleted_at DATETIME`,
+		// Owner of the task in multi-user deployments (taskweb/sprite host DBs).
+		// Free-form: a name or the host's user id. '' = unassigned, which is all
+		// single-user installs ever see.
+		`ALTER TABLE tasks ADD COLUMN assignee TEXT DEFAULT ''`,
+	}
+
+	// One-time: drop the long-removed legacy priority column (SQLite 3.35.0+
+	// supports DROP COLUMN) before the task_priority migration adds the current
+	// integer priority column in its place. Guarded so it never drops the new
+	// column.
+	if done, _ := db.GetSetting(legacyPriorityDropMigrationKey); done == "" {
+		db.Exec(`ALTER TABLE tasks DROP COLUMN priority`)
+		db.SetSetting(legacyPriorityDropMigrationKey, "done")
 	}
 
 	for _, m := range alterMigrations {
@@ -417,9 +468,6 @@ func (db *DB) migrate() error {
 	// Migrate tasks with empty project to 'personal'
 	db.Exec(`UPDATE tasks SET project = 'personal' WHERE project = ''`)
 
-	// Drop priority column if it exists (SQLite 3.35.0+ supports DROP COLUMN)
-	db.Exec(`ALTER TABLE tasks DROP COLUMN priority`)
-
 	// Ensure default task types exist
 	if err := db.ensureDefaultTaskTypes(); err != nil {
 		return fmt.Errorf("ensure default task types: %w", err)
@@ -593,10 +641,25 @@ func (db *DB) ensureProjectColors() error {
 // migrateProjectAliases finds tasks whose project field contains an alias
 // instead of the canonical project name, and updates them to use the canonical name.
 func (db *DB) migrateProjectAliases() error {
-	projects, err := db.ListProjects()
+	// Read the columns directly rather than through ListProjects: this runs
+	// before the versioned migrations, so later project columns may not exist.
+	rows, err := db.Query(`SELECT name, COALESCE(aliases, '') FROM projects`)
 	if err != nil {
 		return fmt.Errorf("list projects: %w", err)
 	}
+	var projects []*Project
+	for rows.Next() {
+		p := &Project{}
+		if err := rows.Scan(&p.Name, &p.Aliases); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan project: %w", err)
+		}
+		projects = append(projects, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("list projects: %w", err)
+	}
 
 	// Build a map of alias -> canonical name
 	for _, p := range projects {
@@ -623,6 +686,8 @@ const defaultCodeTaskTypeInstructions = `You are working on: {{project}}
 
 {{project_instructions}}
 
+{{memories}}
+
 Task: {{title}}
 
 {{body}}
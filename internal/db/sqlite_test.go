@@ -1,9 +1,11 @@
 package db
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -74,6 +76,85 @@ func TestBusyTimeoutIsSet(t *testing.T) {
 	if timeout != 5000 {
 		t.Errorf("expected busy_timeout=5000, got %d", timeout)
 	}
+
+	var synchronous int
+	if err := database.QueryRow("PRAGMA synchronous").Scan(&synchronous); err != nil {
+		t.Fatalf("failed to query synchronous: %v", err)
+	}
+	if synchronous != 1 {
+		t.Errorf("expected synchronous=NORMAL (1), got %d", synchronous)
+	}
+}
+
+// Several processes (daemon, CLI, TUI, MCP server) open the same file, each
+// with its own connection pool. Simulate that with separate handles writing
+// concurrently: busy_timeout must make them wait their turn rather than fail
+// with "database is locked".
+func TestConcurrentWritersDoNotLock(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	const writers, tasksEach = 4, 25
+	handles := make([]*DB, writers)
+	for i := range handles {
+		database, err := Open(dbPath)
+		if err != nil {
+			t.Fatalf("open handle %d: %v", i, err)
+		}
+		defer database.Close()
+		handles[i] = database
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*tasksEach)
+	for i, database := range handles {
+		wg.Add(1)
+		go func(i int, database *DB) {
+			defer wg.Done()
+			for j := 0; j < tasksEach; j++ {
+				task := &Task{Title: fmt.Sprintf("writer %d task %d", i, j), Status: StatusBacklog}
+				if err := database.CreateTask(task); err != nil {
+					errs <- err
+					continue
+				}
+				if err := database.UpdateTaskStatus(task.ID, StatusQueued); err != nil {
+					errs <- err
+				}
+			}
+		}(i, database)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("concurrent write failed: %v", err)
+	}
+	var count int
+	if err := handles[0].QueryRow(`SELECT COUNT(*) FROM tasks WHERE status = ?`, StatusQueued).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != writers*tasksEach {
+		t.Errorf("got %d queued tasks, want %d", count, writers*tasksEach)
+	}
+}
+
+func TestFilesIncludesWALSidecars(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	database, err := Open(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+	if err := database.CreateTask(&Task{Title: "t", Status: StatusBacklog}); err != nil {
+		t.Fatal(err)
+	}
+
+	files := Files(dbPath)
+	if len(files) != 3 || files[1] != dbPath+"-wal" || files[2] != dbPath+"-shm" {
+		t.Fatalf("Files = %v", files)
+	}
+	if _, err := os.Stat(files[1]); err != nil {
+		t.Errorf("expected a WAL file while the database is open: %v", err)
+	}
 }
 
 func TestBusyTimeoutSurvivesConnectionRecycling(t *testing.T) {
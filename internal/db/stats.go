@@ -0,0 +1,205 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// RecordTaskMoved notes in the event log that task newID replaced oldID when
+// it moved to another project. A moved task is re-created, so its created_at
+// is the move time; stats use this event to keep it out of cycle times.
+func (db *DB) RecordTaskMoved(newID, oldID int64, fromProject string) {
+	db.recordEvent(EventTaskMoved, newID, fmt.Sprintf("moved from #%d (%s)", oldID, fromProject))
+}
+
+// DayCount is a count for one local calendar day (YYYY-MM-DD).
+type DayCount struct {
+	Day   string `json:"day"`
+	Count int    `json:"count"`
+}
+
+// ThroughputStats summarizes how tasks flow through the board.
+type ThroughputStats struct {
+	// StatusCounts is the current number of tasks in each status.
+	StatusCounts map[string]int
+	// Completed is the number of tasks finished in the window.
+	Completed int
+	// CycleTime is the mean time from creation to completion over the tasks
+	// finished in the window, excluding moved tasks (see MovedExcluded).
+	CycleTime        time.Duration
+	CycleTimeSamples int
+	// MovedExcluded counts finished tasks left out of CycleTime because a move
+	// between projects reset their created_at.
+	MovedExcluded int
+	// BlockedTime is the mean length of the blocked spells that ended in the
+	// window: from a task.blocked event to the task's next start, completion,
+	// or deletion.
+	BlockedTime    time.Duration
+	BlockedSamples int
+	// CompletedPerDay lists days in the window with at least one completion,
+	// oldest first.
+	CompletedPerDay []DayCount
+}
+
+// TaskThroughput computes ThroughputStats for one project (all projects if
+// empty) over tasks completed at or after since. A zero since covers all time.
+func (db *DB) TaskThroughput(project string, since time.Time) (*ThroughputStats, error) {
+	stats := &ThroughputStats{StatusCounts: map[string]int{}}
+
+	projectFilter := ""
+	var projectArgs []interface{}
+	if project != "" {
+		if p, err := db.GetProjectByName(project); err == nil && p != nil {
+			project = p.Name
+		}
+		projectFilter = " AND project = ?"
+		projectArgs = append(projectArgs, project)
+	}
+
+	rows, err := db.Query(`SELECT status, COUNT(*) FROM tasks WHERE deleted_at IS NULL`+projectFilter+` GROUP BY status`, projectArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("count tasks by status: %w", err)
+	}
+	for rows.Next() {
+		var status string
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan status count: %w", err)
+		}
+		stats.StatusCounts[status] = n
+	}
+	rows.Close()
+
+	query := `
+		SELECT created_at, completed_at,
+		       EXISTS (SELECT 1 FROM event_log e WHERE e.task_id = tasks.id AND e.event_type = 'task.moved')
+		FROM tasks
+		WHERE completed_at IS NOT NULL AND status IN ('done', 'archived') AND deleted_at IS NULL` + projectFilter
+	args := append([]interface{}{}, projectArgs...)
+	if !since.IsZero() {
+		query += ` AND datetime(completed_at) >= datetime(?)`
+		args = append(args, since.UTC().Format("2006-01-02 15:04:05"))
+	}
+	rows, err = db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query completed tasks: %w", err)
+	}
+	perDay := map[string]int{}
+	var cycleTotal time.Duration
+	for rows.Next() {
+		var created, completed LocalTime
+		var moved bool
+		if err := rows.Scan(&created, &completed, &moved); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan completed task: %w", err)
+		}
+		stats.Completed++
+		perDay[completed.Time.Format("2006-01-02")]++
+		if moved {
+			stats.MovedExcluded++
+			continue
+		}
+		if d := completed.Time.Sub(created.Time); d >= 0 {
+			cycleTotal += d
+			stats.CycleTimeSamples++
+		}
+	}
+	rows.Close()
+	if stats.CycleTimeSamples > 0 {
+		stats.CycleTime = cycleTotal / time.Duration(stats.CycleTimeSamples)
+	}
+	for day, n := range perDay {
+		stats.CompletedPerDay = append(stats.CompletedPerDay, DayCount{Day: day, Count: n})
+	}
+	sort.Slice(stats.CompletedPerDay, func(i, j int) bool {
+		return stats.CompletedPerDay[i].Day < stats.CompletedPerDay[j].Day
+	})
+
+	if err := db.blockedTime(stats, project, since); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// blockedTime pairs each task.blocked event with the task's next start,
+// completion, or deletion and averages the spells that ended after since.
+func (db *DB) blockedTime(stats *ThroughputStats, project string, since time.Time) error {
+	// Look back a little before the window so spells that began before it but
+	// ended inside it are still paired.
+	from := since
+	if !from.IsZero() {
+		from = from.Add(-30 * 24 * time.Hour)
+	}
+	transitions, err := db.ListTaskTransitions(from, EventTaskBlocked, EventTaskStarted, EventTaskCompleted, EventTaskDeleted)
+	if err != nil {
+		return err
+	}
+
+	var inProject map[int64]bool
+	if project != "" {
+		inProject = map[int64]bool{}
+		rows, err := db.Query(`SELECT id FROM tasks WHERE project = ?`, project)
+		if err != nil {
+			return fmt.Errorf("query project tasks: %w", err)
+		}
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return fmt.Errorf("scan task id: %w", err)
+			}
+			inProject[id] = true
+		}
+		rows.Close()
+	}
+
+	blockedAt := map[int64]time.Time{}
+	var total time.Duration
+	for _, tr := range transitions {
+		if inProject != nil && !inProject[tr.TaskID] {
+			continue
+		}
+		if tr.EventType == EventTaskBlocked {
+			if _, open := blockedAt[tr.TaskID]; !open {
+				blockedAt[tr.TaskID] = tr.CreatedAt.Time
+			}
+			continue
+		}
+		start, open := blockedAt[tr.TaskID]
+		if !open {
+			continue
+		}
+		delete(blockedAt, tr.TaskID)
+		if tr.CreatedAt.Time.Before(since) {
+			continue
+		}
+		total += tr.CreatedAt.Time.Sub(start)
+		stats.BlockedSamples++
+	}
+	if stats.BlockedSamples > 0 {
+		stats.BlockedTime = total / time.Duration(stats.BlockedSamples)
+	}
+	return nil
+}
+
+// Statuses returns the statuses in StatusCounts in board order, then any
+// others alphabetically.
+func (s *ThroughputStats) Statuses() []string {
+	var out, rest []string
+	seen := map[string]bool{}
+	for _, status := range []string{StatusBacklog, StatusQueued, StatusProcessing, StatusBlocked, StatusDone, StatusArchived} {
+		if _, ok := s.StatusCounts[status]; ok {
+			out = append(out, status)
+			seen[status] = true
+		}
+	}
+	for status := range s.StatusCounts {
+		if !seen[status] {
+			rest = append(rest, status)
+		}
+	}
+	sort.Strings(rest)
+	return append(out, rest...)
+}
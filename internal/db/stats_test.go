@@ -0,0 +1,86 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaskThroughput(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	if err := database.CreateProject(&Project{Name: "other", Path: t.TempDir()}); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+
+	mk := func(title, project, status string) *Task {
+		task := &Task{Title: title, Status: status, Project: project}
+		if err := database.CreateTask(task); err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+		return task
+	}
+	fast := mk("fast", "personal", StatusDone)
+	slow := mk("slow", "personal", StatusDone)
+	moved := mk("moved", "personal", StatusDone)
+	mk("waiting", "personal", StatusBacklog)
+	mk("elsewhere", "other", StatusDone)
+
+	now := time.Now().UTC()
+	setTimes := func(task *Task, created, completed time.Time) {
+		if _, err := database.Exec(`UPDATE tasks SET created_at = ?, completed_at = ? WHERE id = ?`,
+			created.Format("2006-01-02 15:04:05"), completed.Format("2006-01-02 15:04:05"), task.ID); err != nil {
+			t.Fatalf("set times: %v", err)
+		}
+	}
+	setTimes(fast, now.Add(-3*time.Hour), now.Add(-2*time.Hour))
+	setTimes(slow, now.Add(-5*time.Hour), now.Add(-2*time.Hour))
+	setTimes(moved, now.Add(-90*time.Minute), now.Add(-time.Hour))
+	database.RecordTaskMoved(moved.ID, 999, "other")
+
+	// One finished blocked spell of 30 minutes for slow.
+	for _, ev := range []struct {
+		typ string
+		at  time.Time
+	}{
+		{"task.blocked", now.Add(-4 * time.Hour)},
+		{"task.started", now.Add(-4*time.Hour + 30*time.Minute)},
+	} {
+		if _, err := database.Exec(`INSERT INTO event_log (event_type, task_id, created_at) VALUES (?, ?, ?)`,
+			ev.typ, slow.ID, ev.at.Format("2006-01-02 15:04:05")); err != nil {
+			t.Fatalf("insert event: %v", err)
+		}
+	}
+
+	stats, err := database.TaskThroughput("personal", now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("TaskThroughput: %v", err)
+	}
+	if stats.StatusCounts[StatusDone] != 3 || stats.StatusCounts[StatusBacklog] != 1 {
+		t.Errorf("status counts = %v", stats.StatusCounts)
+	}
+	if stats.Completed != 3 {
+		t.Errorf("completed = %d, want 3", stats.Completed)
+	}
+	if stats.MovedExcluded != 1 || stats.CycleTimeSamples != 2 {
+		t.Errorf("moved excluded = %d, samples = %d", stats.MovedExcluded, stats.CycleTimeSamples)
+	}
+	if stats.CycleTime != 2*time.Hour {
+		t.Errorf("cycle time = %v, want 2h", stats.CycleTime)
+	}
+	if stats.BlockedSamples != 1 || stats.BlockedTime != 30*time.Minute {
+		t.Errorf("blocked = %v over %d spells, want 30m over 1", stats.BlockedTime, stats.BlockedSamples)
+	}
+	if len(stats.CompletedPerDay) == 0 {
+		t.Errorf("expected completions per day")
+	}
+
+	// Completions before the window are not counted.
+	stats, err = database.TaskThroughput("personal", now.Add(-90*time.Minute))
+	if err != nil {
+		t.Fatalf("TaskThroughput: %v", err)
+	}
+	if stats.Completed != 1 {
+		t.Errorf("completed in narrow window = %d, want 1", stats.Completed)
+	}
+}
@@ -0,0 +1,190 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ParseTags splits a task's comma-separated Tags field into trimmed, non-empty
+// tags, dropping repeats (compared case-insensitively, keeping the first).
+func ParseTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || containsTag(tags, tag) {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// JoinTags is the inverse of ParseTags.
+func JoinTags(tags []string) string {
+	return strings.Join(tags, ",")
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// TagCount is a distinct tag and the number of tasks carrying it.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// TagCounts returns every tag in use on non-trashed tasks with its task count,
+// most used first. Tags that differ only in case are counted separately so
+// drift like "bug" vs "Bug" is visible.
+func (db *DB) TagCounts() ([]TagCount, error) {
+	rows, err := db.Query(`SELECT tags FROM tasks WHERE COALESCE(tags, '') != '' AND deleted_at IS NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("query tags: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var tags string
+		if err := rows.Scan(&tags); err != nil {
+			return nil, fmt.Errorf("scan tags: %w", err)
+		}
+		seen := map[string]bool{}
+		for _, tag := range strings.Split(tags, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag != "" && !seen[tag] {
+				seen[tag] = true
+				counts[tag]++
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]TagCount, 0, len(counts))
+	for tag, n := range counts {
+		result = append(result, TagCount{Tag: tag, Count: n})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Tag < result[j].Tag
+	})
+	return result, nil
+}
+
+// RenameTag replaces the tag old (exact match) with new on every task, in one
+// transaction, and returns how many tasks changed. A task that already has new
+// keeps a single copy.
+func (db *DB) RenameTag(old, new string) (int, error) {
+	old, new = strings.TrimSpace(old), strings.TrimSpace(new)
+	if old == "" || new == "" {
+		return 0, fmt.Errorf("tag names must not be empty")
+	}
+	if strings.Contains(new, ",") {
+		return 0, fmt.Errorf("tag %q must not contain a comma", new)
+	}
+	return db.rewriteTags(old, func(tags []string) []string {
+		out := make([]string, 0, len(tags))
+		for _, t := range tags {
+			if t == old {
+				t = new
+			}
+			if !containsTag(out, t) {
+				out = append(out, t)
+			}
+		}
+		return out
+	})
+}
+
+// DeleteTag removes the tag (exact match) from every task, in one transaction,
+// and returns how many tasks changed.
+func (db *DB) DeleteTag(tag string) (int, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return 0, fmt.Errorf("tag name must not be empty")
+	}
+	return db.rewriteTags(tag, func(tags []string) []string {
+		out := make([]string, 0, len(tags))
+		for _, t := range tags {
+			if t != tag {
+				out = append(out, t)
+			}
+		}
+		return out
+	})
+}
+
+// rewriteTags applies fn to the tags of every task carrying tag and writes the
+// results back in one transaction. Trashed tasks are included so a restored
+// task matches the rest. The rewrite does not count as an edit: updated_at and
+// task events are left alone.
+func (db *DB) rewriteTags(tag string, fn func([]string) []string) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin tag rewrite: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The LIKE narrows the scan; the exact per-tag check happens in Go.
+	rows, err := tx.Query(`SELECT id, tags FROM tasks WHERE tags LIKE ? ESCAPE '\'`, "%"+escapeLike(tag)+"%")
+	if err != nil {
+		return 0, fmt.Errorf("query tags: %w", err)
+	}
+	updates := map[int64]string{}
+	for rows.Next() {
+		var id int64
+		var tags string
+		if err := rows.Scan(&id, &tags); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan tags: %w", err)
+		}
+		var current []string
+		for _, t := range strings.Split(tags, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				current = append(current, t)
+			}
+		}
+		found := false
+		for _, t := range current {
+			if t == tag {
+				found = true
+				break
+			}
+		}
+		if found {
+			updates[id] = JoinTags(fn(current))
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for id, tags := range updates {
+		if _, err := tx.Exec(`UPDATE tasks SET tags = ? WHERE id = ?`, tags, id); err != nil {
+			return 0, fmt.Errorf("update task #%d tags: %w", id, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tag rewrite: %w", err)
+	}
+	return len(updates), nil
+}
+
+// escapeLike escapes LIKE metacharacters for use with ESCAPE '\'.
+func escapeLike(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "%", `\%`)
+	return strings.ReplaceAll(s, "_", `\_`)
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	got := ParseTags(" bug, Bug ,,ui , bug")
+	if want := []string{"bug", "ui"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseTags = %v, want %v", got, want)
+	}
+	if got := ParseTags(""); got != nil {
+		t.Errorf("ParseTags(\"\") = %v, want nil", got)
+	}
+}
+
+func TestRenameAndDeleteTag(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	tagged := map[string]string{
+		"a": "Bug, ui",
+		"b": "bug,Bug",
+		"c": "debugging",
+		"d": "",
+	}
+	ids := map[string]int64{}
+	for title, tags := range tagged {
+		task := &Task{Title: title, Status: StatusBacklog, Project: "personal", Tags: tags}
+		if err := database.CreateTask(task); err != nil {
+			t.Fatal(err)
+		}
+		ids[title] = task.ID
+	}
+	tagsOf := func(title string) string {
+		task, _ := database.GetTask(ids[title])
+		return task.Tags
+	}
+
+	counts, err := database.TagCounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TagCount{{"Bug", 2}, {"bug", 1}, {"debugging", 1}, {"ui", 1}}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("TagCounts = %v, want %v", counts, want)
+	}
+
+	n, err := database.RenameTag("Bug", "bug")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("renamed on %d tasks, want 2", n)
+	}
+	if got := tagsOf("a"); got != "bug,ui" {
+		t.Errorf("a tags = %q, want whitespace normalized", got)
+	}
+	if got := tagsOf("b"); got != "bug" {
+		t.Errorf("b tags = %q, want the collision collapsed", got)
+	}
+	if got := tagsOf("c"); got != "debugging" {
+		t.Errorf("c tags = %q, want untouched", got)
+	}
+
+	n, err = database.DeleteTag("bug")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || tagsOf("a") != "ui" || tagsOf("b") != "" || tagsOf("c") != "debugging" {
+		t.Errorf("after delete: n=%d a=%q b=%q c=%q", n, tagsOf("a"), tagsOf("b"), tagsOf("c"))
+	}
+
+	if _, err := database.RenameTag("ui", "a,b"); err == nil {
+		t.Error("expected a comma in the new name to be rejected")
+	}
+}
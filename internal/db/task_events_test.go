@@ -2,7 +2,9 @@ package db
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 // setupTestDB creates a temporary test database.
@@ -37,6 +39,7 @@ type MockEventEmitter struct {
 	PinnedTasks    []*Task
 	UnpinnedTasks  []*Task
 	BlockedTasks   []*Task
+	BlockedReasons []string
 	CompletedTasks []*Task
 	Changes        []map[string]interface{}
 }
@@ -62,8 +65,9 @@ func (m *MockEventEmitter) EmitTaskUnpinned(task *Task) {
 	m.UnpinnedTasks = append(m.UnpinnedTasks, task)
 }
 
-func (m *MockEventEmitter) EmitTaskBlocked(task *Task, _ string) {
+func (m *MockEventEmitter) EmitTaskBlocked(task *Task, reason string) {
 	m.BlockedTasks = append(m.BlockedTasks, task)
+	m.BlockedReasons = append(m.BlockedReasons, reason)
 }
 
 func (m *MockEventEmitter) EmitTaskCompleted(task *Task) {
@@ -559,3 +563,99 @@ func TestUpdateTaskStatusEmitsLifecycleEvents(t *testing.T) {
 		t.Errorf("CompletedTasks after no-op: got %d, want 0", got)
 	}
 }
+
+func TestListTaskTransitions(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	start := time.Now().Add(-time.Minute)
+	task := &Task{Title: "Digest me", Status: StatusBacklog, Project: "personal"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range []string{StatusProcessing, StatusBlocked, StatusDone} {
+		if err := database.UpdateTaskStatus(task.ID, status); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	transitions, err := database.ListTaskTransitions(start, "task.created", "task.started", "task.blocked", "task.completed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, tr := range transitions {
+		if tr.TaskID != task.ID {
+			t.Errorf("unexpected task id %d", tr.TaskID)
+		}
+		got = append(got, tr.EventType)
+	}
+	want := []string{"task.created", "task.started", "task.blocked", "task.completed"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("transitions = %v, want %v", got, want)
+	}
+
+	future, err := database.ListTaskTransitions(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(future) != 0 {
+		t.Errorf("expected nothing after the window start, got %d", len(future))
+	}
+}
+
+func TestBlockTaskCarriesReason(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	mockEmitter := &MockEventEmitter{}
+	database.SetEventEmitter(mockEmitter)
+
+	task := &Task{Title: "Needs a decision", Status: StatusProcessing, Project: "personal"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	question := "Should I drop the legacy column?"
+	if err := database.BlockTask(task.ID, BlockedNeedsInput, question); err != nil {
+		t.Fatalf("BlockTask() error = %v", err)
+	}
+
+	got, err := database.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if got.Status != StatusBlocked {
+		t.Errorf("status = %q, want blocked", got.Status)
+	}
+	if len(mockEmitter.BlockedReasons) != 1 || mockEmitter.BlockedReasons[0] != question {
+		t.Errorf("blocked reasons = %q, want [%q]", mockEmitter.BlockedReasons, question)
+	}
+
+	transitions, err := database.ListTaskTransitions(time.Time{}, "task.blocked")
+	if err != nil {
+		t.Fatalf("ListTaskTransitions() error = %v", err)
+	}
+	if len(transitions) != 1 || transitions[0].Message != question {
+		t.Errorf("event log = %+v, want one task.blocked with the question", transitions)
+	}
+}
+
+func TestUpdateTaskStatusBlockedHasNoReason(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	mockEmitter := &MockEventEmitter{}
+	database.SetEventEmitter(mockEmitter)
+
+	task := &Task{Title: "Blocked by hand", Status: StatusProcessing, Project: "personal"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if err := database.UpdateTaskStatus(task.ID, StatusBlocked); err != nil {
+		t.Fatalf("UpdateTaskStatus() error = %v", err)
+	}
+	if len(mockEmitter.BlockedReasons) != 1 || mockEmitter.BlockedReasons[0] != "" {
+		t.Errorf("blocked reasons = %q, want one empty reason", mockEmitter.BlockedReasons)
+	}
+}
@@ -1,12 +1,16 @@
 package db
 
 import (
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 )
@@ -17,6 +21,7 @@ type Task struct {
 	Title           string
 	Body            string
 	Status          string
+	BlockedReason   string // Why a blocked task is blocked: needs_input, needs_permission, error or dependency ("" = unknown or not blocked)
 	Type            string
 	Project         string
 	Executor        string // Task executor: "claude" (default), "codex", "gemini"
@@ -24,24 +29,30 @@ type Task struct {
 	Model           string // Per-task Claude model override ("" = use global/Claude default; otherwise an alias like opus/sonnet/haiku or a full model name)
 	ClaudeConfigDir string // Per-task CLAUDE_CONFIG_DIR override ("" = use the project's/default config dir). Lets a single step route through a different Claude config (e.g. an ollama-backed one) without changing the project.
 	EnvJSON         string // Per-task env overrides for the spawned Claude, stored as a JSON object (e.g. {"ANTHROPIC_BASE_URL":"http://127.0.0.1:11434","ANTHROPIC_AUTH_TOKEN":"ollama"}). Injected as a process-env prefix on the claude command so a step can route through a non-Anthropic proxy (ollama) WITHOUT swapping CLAUDE_CONFIG_DIR — the default config dir (plugins, MCP, trusted worktrees) stays intact and process env wins over stored creds. "" = no overrides.
+	ExecutorArgs    string // Extra flags for the task's executor CLI, stored as a JSON object of allowlisted keys (e.g. {"model":"o3"}); see executor.ValidateExecutorArgs. "" = none.
 	WorktreePath    string
 	BranchName      string
-	Port            int    // Unique port for running the application in this task's worktree
-	ClaudeSessionID string // Claude session ID for resuming conversations
-	DaemonSession   string // tmux daemon session name (e.g., "task-daemon-12345")
-	TmuxWindowID    string // tmux window ID (e.g., "@1234") for unique window identification
-	ClaudePaneID    string // tmux pane ID (e.g., "%1234") for the Claude/executor pane
-	ShellPaneID     string // tmux pane ID (e.g., "%1235") for the shell pane
-	PRURL           string // Pull request URL (if associated with a PR)
-	PRNumber        int    // Pull request number (if associated with a PR)
-	PRInfoJSON      string // Cached PR state as JSON (state, checks, mergeable, etc.)
-	DangerousMode   bool   // Whether task is running in dangerous mode (--dangerously-skip-permissions). Kept for backward compat; PermissionMode is authoritative.
-	PermissionMode  string // Permission mode for execution: "default" (prompt), "accept-edits" (Claude's acceptEdits — auto-accept file edits, still prompts for risky actions), "auto" (Claude Code's auto mode — classifier auto-approves safe actions, blocks risky ones), "dangerous" (skip permissions). Empty falls back to DangerousMode/global default.
-	RemoteControl   bool   // Whether to launch claude with --remote-control (interactive, remote-drivable)
-	Pinned          bool   // Whether the task is pinned to the top of its column
-	Tags            string // Comma-separated tags for categorization (e.g., "customer-support,email,influence-kit")
-	SourceBranch    string // Existing branch to checkout for worktree (e.g., "fix/ui-overflow") instead of creating new branch
-	Summary         string // Distilled summary of what was accomplished (for search and context)
+	Port            int     // Unique port for running the application in this task's worktree
+	ClaudeSessionID string  // Claude session ID for resuming conversations
+	DaemonSession   string  // tmux daemon session name (e.g., "task-daemon-12345")
+	TmuxWindowID    string  // tmux window ID (e.g., "@1234") for unique window identification
+	ClaudePaneID    string  // tmux pane ID (e.g., "%1234") for the Claude/executor pane
+	ShellPaneID     string  // tmux pane ID (e.g., "%1235") for the shell pane
+	PRURL           string  // Pull request URL (if associated with a PR)
+	PRNumber        int     // Pull request number (if associated with a PR)
+	PRInfoJSON      string  // Cached PR state as JSON (state, checks, mergeable, etc.)
+	DangerousMode   bool    // Whether task is running in dangerous mode (--dangerously-skip-permissions). Kept for backward compat; PermissionMode is authoritative.
+	PermissionMode  string  // Permission mode for execution: "default" (prompt), "accept-edits" (Claude's acceptEdits — auto-accept file edits, still prompts for risky actions), "auto" (Claude Code's auto mode — classifier auto-approves safe actions, blocks risky ones), "dangerous" (skip permissions). Empty falls back to DangerousMode/global default.
+	RemoteControl   bool    // Whether to launch claude with --remote-control (interactive, remote-drivable)
+	Pinned          bool    // Whether the task is pinned to the top of its column
+	Tags            string  // Comma-separated tags for categorization (e.g., "customer-support,email,influence-kit")
+	SourceBranch    string  // Existing branch to checkout for worktree (e.g., "fix/ui-overflow") instead of creating new branch
+	Summary         string  // Distilled summary of what was accomplished (for search and context)
+	Assignee        string  // Who owns the task in multi-user deployments (free-form name or host user id; "" = unassigned)
+	Priority        int     // Queue priority: higher runs first; equal priorities run in creation order (default 0)
+	InputTokens     int64   // Prompt tokens (including cache reads and writes) used by the task's Claude sessions
+	OutputTokens    int64   // Output tokens used by the task's Claude sessions
+	CostUSD         float64 // Estimated cost of InputTokens and OutputTokens at list prices
 	CreatedAt       LocalTime
 	UpdatedAt       LocalTime
 	StartedAt       *LocalTime
@@ -55,6 +66,8 @@ type Task struct {
 	ArchiveCommit       string // Commit hash at time of archiving
 	ArchiveWorktreePath string // Original worktree path before archiving
 	ArchiveBranchName   string // Original branch name before archiving
+	// Manual order within its column, set by ty reorder (nil = by time)
+	Position *float64
 }
 
 // Task statuses
@@ -67,6 +80,26 @@ const (
 	StatusArchived   = "archived"   // Archived (hidden from view)
 )
 
+// Blocked reasons say why a task is in StatusBlocked, so a failure can be told
+// apart from a task that is only waiting on the user. Any status change clears
+// the reason; BlockTask sets it.
+const (
+	BlockedNeedsInput      = "needs_input"      // The agent asked a question or finished its turn
+	BlockedNeedsPermission = "needs_permission" // The agent is waiting on a permission prompt
+	BlockedError           = "error"            // The agent or executor failed
+	BlockedDependency      = "dependency"       // Waiting on tasks it depends on
+)
+
+// BlockedReasons returns every blocked reason, for validation and completion.
+func BlockedReasons() []string {
+	return []string{BlockedNeedsInput, BlockedNeedsPermission, BlockedError, BlockedDependency}
+}
+
+// IsValidBlockedReason reports whether s is one of BlockedReasons.
+func IsValidBlockedReason(s string) bool {
+	return slices.Contains(BlockedReasons(), s)
+}
+
 // IsInProgress returns true if the task is actively being worked on.
 func IsInProgress(status string) bool {
 	return status == StatusQueued || status == StatusProcessing
@@ -261,11 +294,24 @@ func (t *Task) EnvMap() map[string]string {
 	return out
 }
 
+// ExecutorArgMap parses a task's ExecutorArgs blob into a map. Like
+// EnvMap, a malformed or empty blob yields an empty (never nil) map.
+func (t *Task) ExecutorArgMap() map[string]string {
+	out := map[string]string{}
+	if strings.TrimSpace(t.ExecutorArgs) == "" {
+		return out
+	}
+	if err := json.Unmarshal([]byte(t.ExecutorArgs), &out); err != nil {
+		return map[string]string{}
+	}
+	return out
+}
+
 // Model overrides are per-task selections for Claude's model (claude --model).
-// An empty value means "no override" — the task uses Claude's global default,
-// leaving the user's global setting untouched. The aliases below are accepted by
-// the Claude CLI's --model flag; a full model name (e.g. "claude-opus-4-8") is
-// also valid and passed through unchanged.
+// An empty value means "no override" — the task uses the project's default
+// model, then the default_model setting, then Claude's own default. The aliases
+// below are accepted by the Claude CLI's --model flag; a full model name (e.g.
+// "claude-opus-4-8") is also valid and passed through unchanged.
 const (
 	ModelFable  = "fable"
 	ModelOpus   = "opus"
@@ -273,6 +319,26 @@ const (
 	ModelHaiku  = "haiku"
 )
 
+// ModelCustomPrefix marks a model override that skips validation, for model
+// IDs the known list doesn't cover yet: "custom:<id>" runs Claude with --model <id>.
+const ModelCustomPrefix = "custom:"
+
+// SettingDefaultModel is the global Claude model tasks run with when neither
+// the task nor its project sets one. Empty means Claude's own default.
+const SettingDefaultModel = "default_model"
+
+// DefaultModel returns the Claude model a task in project runs with when it
+// doesn't pick one: the project's default, else the default_model setting.
+// It is resolved at launch, not stored on the task, so changing either
+// default affects tasks that haven't started yet.
+func (db *DB) DefaultModel(project string) string {
+	if p, err := db.GetProjectByName(project); err == nil && p != nil && p.DefaultModel != "" {
+		return p.DefaultModel
+	}
+	model, _ := db.GetSetting(SettingDefaultModel)
+	return model
+}
+
 // ModelOptions returns the per-task model override aliases offered in the UI.
 // The Claude CLI also accepts full model names, so this is a convenience list,
 // not an exhaustive set. Keep it in sync with the aliases the Claude CLI supports
@@ -282,11 +348,25 @@ func ModelOptions() []string {
 }
 
 // IsValidModel reports whether s is an acceptable per-task model override. The
-// empty string is valid and means "use the global/Claude default" (no per-task
-// override). Any non-empty value is accepted because the Claude CLI validates
-// the model name itself and supports both aliases and full model IDs.
+// empty string is valid and means "no override". Otherwise s must be one of
+// ModelOptions, a full Claude model ID ("claude-..."), or "custom:<id>" to
+// bypass the check for models this list doesn't know about yet.
 func IsValidModel(s string) bool {
-	return true
+	switch {
+	case s == "" || slices.Contains(ModelOptions(), s):
+		return true
+	case strings.HasPrefix(s, ModelCustomPrefix):
+		return strings.TrimSpace(strings.TrimPrefix(s, ModelCustomPrefix)) != ""
+	case strings.HasPrefix(s, "claude-"):
+		return len(s) > len("claude-") && !strings.ContainsAny(s, " \t\n")
+	}
+	return false
+}
+
+// ModelID returns the model name to pass to claude --model for an override,
+// stripping the "custom:" prefix.
+func ModelID(s string) string {
+	return strings.TrimSpace(strings.TrimPrefix(s, ModelCustomPrefix))
 }
 
 // Port allocation constants
@@ -316,11 +396,6 @@ func (db *DB) CreateTask(t *Task) error {
 		t.Project = "personal"
 	}
 
-	// Default to 'claude' executor if not specified
-	if t.Executor == "" {
-		t.Executor = DefaultExecutor()
-	}
-
 	// Validate that the project exists and resolve aliases to canonical name
 	project, err := db.GetProjectByName(t.Project)
 	if err != nil {
@@ -331,6 +406,12 @@ func (db *DB) CreateTask(t *Task) error {
 	}
 	t.Project = project.Name
 
+	// Resolve the executor: explicit task executor, then the project's default,
+	// then the global default (claude).
+	if t.Executor == "" {
+		t.Executor = project.EffectiveExecutor()
+	}
+
 	// Resolve the permission mode: an explicit value wins, otherwise inherit the
 	// project's configured default so tasks start in the right mode without a
 	// manual per-session toggle.
@@ -345,10 +426,15 @@ func (db *DB) CreateTask(t *Task) error {
 	// Keep the legacy boolean consistent with the resolved mode.
 	t.DangerousMode = t.PermissionMode == PermissionModeDangerous
 
+	// Only a blocked task carries a blocked reason.
+	if t.Status != StatusBlocked {
+		t.BlockedReason = ""
+	}
+
 	result, err := db.Exec(`
-		INSERT INTO tasks (title, body, status, type, project, executor, pinned, tags, source_branch, dangerous_mode, permission_mode, remote_control, effort_level, model, claude_config_dir, env)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, t.Title, t.Body, t.Status, t.Type, t.Project, t.Executor, t.Pinned, t.Tags, t.SourceBranch, t.DangerousMode, t.PermissionMode, t.RemoteControl, t.EffortLevel, t.Model, t.ClaudeConfigDir, t.EnvJSON)
+		INSERT INTO tasks (title, body, status, blocked_reason, type, project, executor, pinned, tags, source_branch, dangerous_mode, permission_mode, remote_control, effort_level, model, claude_config_dir, env, executor_args, assignee, priority)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, t.Title, t.Body, t.Status, t.BlockedReason, t.Type, t.Project, t.Executor, t.Pinned, t.Tags, t.SourceBranch, t.DangerousMode, t.PermissionMode, t.RemoteControl, t.EffortLevel, t.Model, t.ClaudeConfigDir, t.EnvJSON, t.ExecutorArgs, t.Assignee, t.Priority)
 	if err != nil {
 		return fmt.Errorf("insert task: %w", err)
 	}
@@ -402,7 +488,8 @@ func (db *DB) GetTask(id int64) (*Task, error) {
 		       COALESCE(claude_pane_id, ''), COALESCE(shell_pane_id, ''),
 		       COALESCE(pr_url, ''), COALESCE(pr_number, 0), COALESCE(pr_info_json, ''),
 		       COALESCE(dangerous_mode, 0), COALESCE(permission_mode, ''), COALESCE(remote_control, 0), COALESCE(pinned, 0), COALESCE(tags, ''),
-		       COALESCE(source_branch, ''), COALESCE(summary, ''), COALESCE(effort_level, ''), COALESCE(model, ''), COALESCE(claude_config_dir, ''), COALESCE(env, ''),
+		       COALESCE(source_branch, ''), COALESCE(summary, ''), COALESCE(effort_level, ''), COALESCE(model, ''), COALESCE(claude_config_dir, ''), COALESCE(env, ''), COALESCE(executor_args, ''), COALESCE(blocked_reason, ''),
+		       COALESCE(assignee, ''), COALESCE(priority, 0), COALESCE(input_tokens, 0), COALESCE(output_tokens, 0), COALESCE(cost_usd, 0), position,
 		       created_at, updated_at, started_at, completed_at,
 		       last_distilled_at, last_accessed_at,
 		       COALESCE(archive_ref, ''), COALESCE(archive_commit, ''),
@@ -414,7 +501,8 @@ func (db *DB) GetTask(id int64) (*Task, error) {
 		&t.DaemonSession, &t.TmuxWindowID, &t.ClaudePaneID, &t.ShellPaneID,
 		&t.PRURL, &t.PRNumber, &t.PRInfoJSON,
 		&t.DangerousMode, &t.PermissionMode, &t.RemoteControl, &t.Pinned, &t.Tags,
-		&t.SourceBranch, &t.Summary, &t.EffortLevel, &t.Model, &t.ClaudeConfigDir, &t.EnvJSON,
+		&t.SourceBranch, &t.Summary, &t.EffortLevel, &t.Model, &t.ClaudeConfigDir, &t.EnvJSON, &t.ExecutorArgs, &t.BlockedReason,
+		&t.Assignee, &t.Priority, &t.InputTokens, &t.OutputTokens, &t.CostUSD, &t.Position,
 		&t.CreatedAt, &t.UpdatedAt, &t.StartedAt, &t.CompletedAt,
 		&t.LastDistilledAt, &t.LastAccessedAt,
 		&t.ArchiveRef, &t.ArchiveCommit, &t.ArchiveWorktreePath, &t.ArchiveBranchName,
@@ -431,9 +519,16 @@ func (db *DB) GetTask(id int64) (*Task, error) {
 // ListTasksOptions defines options for listing tasks.
 type ListTasksOptions struct {
 	Status         string
+	BlockedReason  string // Filter to blocked tasks with this blocked reason (see BlockedReasons)
 	Type           string
 	Project        string
-	Tag            string // Filter to tasks carrying this exact tag (delimiter-safe; "gm:cortex" does not match "gm:cortex-2")
+	Tag            string    // Filter to tasks carrying this tag (whole tag, case-insensitive; "gm:cortex" does not match "gm:cortex-2")
+	Tags           []string  // Filter to tasks carrying every one of these tags, matched like Tag
+	Assignee       string    // Filter to tasks owned by this assignee (exact match)
+	Unassigned     bool      // Filter to tasks with no assignee; ignored when Assignee is set
+	UpdatedBefore  time.Time // Filter to tasks last updated before this time; ignored when zero
+	Since          time.Time // Filter to tasks created at or after this time (done/archived: completed); ignored when zero
+	Until          time.Time // Filter to tasks created before this time (done/archived: completed); ignored when zero
 	Limit          int
 	Offset         int
 	IncludeClosed  bool // Include closed tasks even when Status is empty
@@ -441,28 +536,20 @@ type ListTasksOptions struct {
 	OrderByRecency bool // Sort purely by recency, ignoring pinned-first ordering
 }
 
-// ListTasks retrieves tasks with optional filters.
-func (db *DB) ListTasks(opts ListTasksOptions) ([]*Task, error) {
-	query := `
-		SELECT id, title, body, status, type, project, COALESCE(executor, 'claude'),
-		       worktree_path, branch_name, port, claude_session_id,
-		       COALESCE(daemon_session, ''), COALESCE(tmux_window_id, ''),
-		       COALESCE(claude_pane_id, ''), COALESCE(shell_pane_id, ''),
-		       COALESCE(pr_url, ''), COALESCE(pr_number, 0), COALESCE(pr_info_json, ''),
-		       COALESCE(dangerous_mode, 0), COALESCE(permission_mode, ''), COALESCE(remote_control, 0), COALESCE(pinned, 0), COALESCE(tags, ''),
-		       COALESCE(source_branch, ''), COALESCE(summary, ''), COALESCE(effort_level, ''), COALESCE(model, ''), COALESCE(claude_config_dir, ''), COALESCE(env, ''),
-		       created_at, updated_at, started_at, completed_at,
-		       last_distilled_at, last_accessed_at,
-		       COALESCE(archive_ref, ''), COALESCE(archive_commit, ''),
-		       COALESCE(archive_worktree_path, ''), COALESCE(archive_branch_name, '')
-		FROM tasks WHERE 1=1
-	`
+// listTasksFilter builds the WHERE conditions (each prefixed with " AND") and
+// arguments shared by ListTasks and the CountTasks queries.
+func (db *DB) listTasksFilter(opts ListTasksOptions) (string, []interface{}) {
+	query := ""
 	args := []interface{}{}
 
 	if opts.Status != "" {
 		query += " AND status = ?"
 		args = append(args, opts.Status)
 	}
+	if opts.BlockedReason != "" {
+		query += " AND status = ? AND blocked_reason = ?"
+		args = append(args, StatusBlocked, opts.BlockedReason)
+	}
 	if opts.Type != "" {
 		query += " AND type = ?"
 		args = append(args, opts.Type)
@@ -476,20 +563,45 @@ func (db *DB) ListTasks(opts ListTasksOptions) ([]*Task, error) {
 		query += " AND project = ?"
 		args = append(args, projectName)
 	}
+	// Tags are stored comma-separated (e.g. "a,gm:cortex,b"). A naive
+	// LIKE '%gm:cortex%' would false-match "gm:cortex-2", so normalize the
+	// stored value to ",a,gm:cortex,b," and match the delimited ",tag,".
+	// Escape LIKE metacharacters (\, %, _) in the needle so a tag value
+	// containing them matches literally rather than as a wildcard pattern.
+	// Both sides are lowercased, so matching ignores case.
+	tags := opts.Tags
 	if opts.Tag != "" {
-		// Tags are stored comma-separated (e.g. "a,gm:cortex,b"). A naive
-		// LIKE '%gm:cortex%' would false-match "gm:cortex-2", so normalize the
-		// stored value to ",a,gm:cortex,b," and match the delimited ",tag,".
-		// Escape LIKE metacharacters (\, %, _) in the needle so a tag value
-		// containing them matches literally rather than as a wildcard pattern.
-		needle := strings.ReplaceAll(opts.Tag, " ", "")
-		needle = strings.ReplaceAll(needle, `\`, `\\`)
-		needle = strings.ReplaceAll(needle, "%", `\%`)
-		needle = strings.ReplaceAll(needle, "_", `\_`)
-		query += ` AND (',' || REPLACE(COALESCE(tags, ''), ' ', '') || ',') LIKE ? ESCAPE '\'`
+		tags = append([]string{opts.Tag}, tags...)
+	}
+	for _, tag := range tags {
+		needle := escapeLike(strings.ToLower(strings.ReplaceAll(tag, " ", "")))
+		if needle == "" {
+			continue
+		}
+		query += ` AND LOWER(',' || REPLACE(COALESCE(tags, ''), ' ', '') || ',') LIKE ? ESCAPE '\'`
 		args = append(args, "%,"+needle+",%")
 	}
 
+	if opts.Assignee != "" {
+		query += " AND assignee = ?"
+		args = append(args, opts.Assignee)
+	} else if opts.Unassigned {
+		query += " AND COALESCE(assignee, '') = ''"
+	}
+	if !opts.UpdatedBefore.IsZero() {
+		query += " AND datetime(updated_at) < datetime(?)"
+		args = append(args, opts.UpdatedBefore.UTC().Format("2006-01-02 15:04:05"))
+	}
+	const windowColumn = "datetime(CASE WHEN status IN ('done', 'archived') THEN completed_at ELSE created_at END)"
+	if !opts.Since.IsZero() {
+		query += " AND " + windowColumn + " >= datetime(?)"
+		args = append(args, opts.Since.UTC().Format("2006-01-02 15:04:05"))
+	}
+	if !opts.Until.IsZero() {
+		query += " AND " + windowColumn + " < datetime(?)"
+		args = append(args, opts.Until.UTC().Format("2006-01-02 15:04:05"))
+	}
+
 	// Exclude done and archived by default unless specifically querying for them or includeClosed is set
 	if opts.Status == "" && !opts.IncludeClosed {
 		query += " AND status NOT IN ('done', 'archived')"
@@ -502,17 +614,40 @@ func (db *DB) ListTasks(opts ListTasksOptions) ([]*Task, error) {
 		query += " AND deleted_at IS NULL"
 	}
 
+	return query, args
+}
+
+// ListTasks retrieves tasks with optional filters.
+func (db *DB) ListTasks(opts ListTasksOptions) ([]*Task, error) {
+	query := `
+		SELECT id, title, body, status, type, project, COALESCE(executor, 'claude'),
+		       worktree_path, branch_name, port, claude_session_id,
+		       COALESCE(daemon_session, ''), COALESCE(tmux_window_id, ''),
+		       COALESCE(claude_pane_id, ''), COALESCE(shell_pane_id, ''),
+		       COALESCE(pr_url, ''), COALESCE(pr_number, 0), COALESCE(pr_info_json, ''),
+		       COALESCE(dangerous_mode, 0), COALESCE(permission_mode, ''), COALESCE(remote_control, 0), COALESCE(pinned, 0), COALESCE(tags, ''),
+		       COALESCE(source_branch, ''), COALESCE(summary, ''), COALESCE(effort_level, ''), COALESCE(model, ''), COALESCE(claude_config_dir, ''), COALESCE(env, ''), COALESCE(executor_args, ''), COALESCE(blocked_reason, ''),
+		       COALESCE(assignee, ''), COALESCE(priority, 0), COALESCE(input_tokens, 0), COALESCE(output_tokens, 0), COALESCE(cost_usd, 0), position,
+		       created_at, updated_at, started_at, completed_at,
+		       last_distilled_at, last_accessed_at,
+		       COALESCE(archive_ref, ''), COALESCE(archive_commit, ''),
+		       COALESCE(archive_worktree_path, ''), COALESCE(archive_branch_name, '')
+		FROM tasks WHERE 1=1
+	`
+	where, args := db.listTasksFilter(opts)
+	query += where
+
 	// Sort done/blocked tasks by completed_at (most recently closed first) and
 	// other tasks by created_at (newest first). Use id DESC as secondary sort for
-	// consistency. Pinning takes precedence unless OrderByRecency is set: a capped
-	// slice (e.g. the kanban's Done column) must select the most recent tasks, and
-	// pinned-first selection would let old pinned tasks crowd newer ones out of the
-	// limit entirely.
+	// consistency. Pinning and manual positions (see ReorderTask) take precedence
+	// unless OrderByRecency is set: a capped slice (e.g. the kanban's Done column)
+	// must select the most recent tasks, and pinned-first selection would let old
+	// pinned tasks crowd newer ones out of the limit entirely.
 	recency := " CASE WHEN status IN ('done', 'blocked') THEN completed_at ELSE created_at END DESC, id DESC"
 	if opts.OrderByRecency {
 		query += " ORDER BY" + recency
 	} else {
-		query += " ORDER BY pinned DESC," + recency
+		query += " ORDER BY pinned DESC, position IS NULL, position," + recency
 	}
 
 	if opts.Limit > 0 {
@@ -539,7 +674,8 @@ func (db *DB) ListTasks(opts ListTasksOptions) ([]*Task, error) {
 			&t.DaemonSession, &t.TmuxWindowID, &t.ClaudePaneID, &t.ShellPaneID,
 			&t.PRURL, &t.PRNumber, &t.PRInfoJSON,
 			&t.DangerousMode, &t.PermissionMode, &t.RemoteControl, &t.Pinned, &t.Tags,
-			&t.SourceBranch, &t.Summary, &t.EffortLevel, &t.Model, &t.ClaudeConfigDir, &t.EnvJSON,
+			&t.SourceBranch, &t.Summary, &t.EffortLevel, &t.Model, &t.ClaudeConfigDir, &t.EnvJSON, &t.ExecutorArgs, &t.BlockedReason,
+			&t.Assignee, &t.Priority, &t.InputTokens, &t.OutputTokens, &t.CostUSD, &t.Position,
 			&t.CreatedAt, &t.UpdatedAt, &t.StartedAt, &t.CompletedAt,
 			&t.LastDistilledAt, &t.LastAccessedAt,
 			&t.ArchiveRef, &t.ArchiveCommit, &t.ArchiveWorktreePath, &t.ArchiveBranchName,
@@ -553,6 +689,67 @@ func (db *DB) ListTasks(opts ListTasksOptions) ([]*Task, error) {
 	return tasks, nil
 }
 
+// TaskCount is one group returned by CountTasksBy.
+type TaskCount struct {
+	Key   string
+	Count int
+}
+
+// taskCountColumns are the columns CountTasksBy can group on.
+var taskCountColumns = map[string]string{
+	"status":   "status",
+	"project":  "project",
+	"type":     "type",
+	"executor": "COALESCE(executor, 'claude')",
+	"assignee": "COALESCE(assignee, '')",
+}
+
+// TaskCountFields returns the fields CountTasksBy accepts, sorted.
+func TaskCountFields() []string {
+	fields := make([]string, 0, len(taskCountColumns))
+	for f := range taskCountColumns {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// CountTasks returns how many tasks match opts. Limit and Offset are ignored.
+func (db *DB) CountTasks(opts ListTasksOptions) (int, error) {
+	where, args := db.listTasksFilter(opts)
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM tasks WHERE 1=1"+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count tasks: %w", err)
+	}
+	return count, nil
+}
+
+// CountTasksBy counts the tasks matching opts grouped by field (one of
+// TaskCountFields), largest group first. Limit and Offset are ignored.
+func (db *DB) CountTasksBy(opts ListTasksOptions, field string) ([]TaskCount, error) {
+	column, ok := taskCountColumns[field]
+	if !ok {
+		return nil, fmt.Errorf("cannot count by %q (use one of: %s)", field, strings.Join(TaskCountFields(), ", "))
+	}
+	where, args := db.listTasksFilter(opts)
+	rows, err := db.Query(`SELECT `+column+` AS k, COUNT(*) FROM tasks WHERE 1=1`+where+`
+		GROUP BY k ORDER BY COUNT(*) DESC, k`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("count tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []TaskCount
+	for rows.Next() {
+		var c TaskCount
+		if err := rows.Scan(&c.Key, &c.Count); err != nil {
+			return nil, fmt.Errorf("scan task count: %w", err)
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
 // GetMostRecentlyCreatedTask returns the task with the most recent created_at timestamp.
 // This is used to get the last task's project for defaulting in new task forms.
 func (db *DB) GetMostRecentlyCreatedTask() (*Task, error) {
@@ -564,7 +761,8 @@ func (db *DB) GetMostRecentlyCreatedTask() (*Task, error) {
 		       COALESCE(claude_pane_id, ''), COALESCE(shell_pane_id, ''),
 		       COALESCE(pr_url, ''), COALESCE(pr_number, 0), COALESCE(pr_info_json, ''),
 		       COALESCE(dangerous_mode, 0), COALESCE(permission_mode, ''), COALESCE(remote_control, 0), COALESCE(pinned, 0), COALESCE(tags, ''),
-		       COALESCE(source_branch, ''), COALESCE(summary, ''), COALESCE(effort_level, ''), COALESCE(model, ''), COALESCE(claude_config_dir, ''), COALESCE(env, ''),
+		       COALESCE(source_branch, ''), COALESCE(summary, ''), COALESCE(effort_level, ''), COALESCE(model, ''), COALESCE(claude_config_dir, ''), COALESCE(env, ''), COALESCE(executor_args, ''), COALESCE(blocked_reason, ''),
+		       COALESCE(assignee, ''), COALESCE(priority, 0), COALESCE(input_tokens, 0), COALESCE(output_tokens, 0), COALESCE(cost_usd, 0), position,
 		       created_at, updated_at, started_at, completed_at,
 		       last_distilled_at, last_accessed_at,
 		       COALESCE(archive_ref, ''), COALESCE(archive_commit, ''),
@@ -578,7 +776,8 @@ func (db *DB) GetMostRecentlyCreatedTask() (*Task, error) {
 		&t.DaemonSession, &t.TmuxWindowID, &t.ClaudePaneID, &t.ShellPaneID,
 		&t.PRURL, &t.PRNumber, &t.PRInfoJSON,
 		&t.DangerousMode, &t.PermissionMode, &t.RemoteControl, &t.Pinned, &t.Tags,
-		&t.SourceBranch, &t.Summary, &t.EffortLevel, &t.Model, &t.ClaudeConfigDir, &t.EnvJSON,
+		&t.SourceBranch, &t.Summary, &t.EffortLevel, &t.Model, &t.ClaudeConfigDir, &t.EnvJSON, &t.ExecutorArgs, &t.BlockedReason,
+		&t.Assignee, &t.Priority, &t.InputTokens, &t.OutputTokens, &t.CostUSD, &t.Position,
 		&t.CreatedAt, &t.UpdatedAt, &t.StartedAt, &t.CompletedAt,
 		&t.LastDistilledAt, &t.LastAccessedAt,
 		&t.ArchiveRef, &t.ArchiveCommit, &t.ArchiveWorktreePath, &t.ArchiveBranchName,
@@ -607,7 +806,8 @@ func (db *DB) SearchTasks(query string, limit int) ([]*Task, error) {
 		       COALESCE(claude_pane_id, ''), COALESCE(shell_pane_id, ''),
 		       COALESCE(pr_url, ''), COALESCE(pr_number, 0), COALESCE(pr_info_json, ''),
 		       COALESCE(dangerous_mode, 0), COALESCE(permission_mode, ''), COALESCE(remote_control, 0), COALESCE(pinned, 0), COALESCE(tags, ''),
-		       COALESCE(source_branch, ''), COALESCE(summary, ''), COALESCE(effort_level, ''), COALESCE(model, ''), COALESCE(claude_config_dir, ''), COALESCE(env, ''),
+		       COALESCE(source_branch, ''), COALESCE(summary, ''), COALESCE(effort_level, ''), COALESCE(model, ''), COALESCE(claude_config_dir, ''), COALESCE(env, ''), COALESCE(executor_args, ''), COALESCE(blocked_reason, ''),
+		       COALESCE(assignee, ''), COALESCE(priority, 0), COALESCE(input_tokens, 0), COALESCE(output_tokens, 0), COALESCE(cost_usd, 0), position,
 		       created_at, updated_at, started_at, completed_at,
 		       last_distilled_at, last_accessed_at,
 		       COALESCE(archive_ref, ''), COALESCE(archive_commit, ''),
@@ -640,7 +840,8 @@ func (db *DB) SearchTasks(query string, limit int) ([]*Task, error) {
 			&t.DaemonSession, &t.TmuxWindowID, &t.ClaudePaneID, &t.ShellPaneID,
 			&t.PRURL, &t.PRNumber, &t.PRInfoJSON,
 			&t.DangerousMode, &t.PermissionMode, &t.RemoteControl, &t.Pinned, &t.Tags,
-			&t.SourceBranch, &t.Summary, &t.EffortLevel, &t.Model, &t.ClaudeConfigDir, &t.EnvJSON,
+			&t.SourceBranch, &t.Summary, &t.EffortLevel, &t.Model, &t.ClaudeConfigDir, &t.EnvJSON, &t.ExecutorArgs, &t.BlockedReason,
+			&t.Assignee, &t.Priority, &t.InputTokens, &t.OutputTokens, &t.CostUSD, &t.Position,
 			&t.CreatedAt, &t.UpdatedAt, &t.StartedAt, &t.CompletedAt,
 			&t.LastDistilledAt, &t.LastAccessedAt,
 			&t.ArchiveRef, &t.ArchiveCommit, &t.ArchiveWorktreePath, &t.ArchiveBranchName,
@@ -677,13 +878,116 @@ func (db *DB) MarkTaskStarted(id int64) error {
 func (db *DB) UpdateTaskStatus(id int64, status string) error {
 	// Get old task to track status change
 	oldTask, _ := db.GetTask(id)
-	oldStatus := ""
-	if oldTask != nil {
-		oldStatus = oldTask.Status
+	undo, err := db.statusUndo(status, oldTask)
+	if err != nil {
+		return err
+	}
+
+	query, args := statusUpdateQuery(id, status, "", oldTask)
+	if undo == nil {
+		if _, err := db.Exec(query, args...); err != nil {
+			return fmt.Errorf("update task status: %w", err)
+		}
+	} else {
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("begin status update: %w", err)
+		}
+		defer tx.Rollback()
+		if _, err := tx.Exec(query, args...); err != nil {
+			return fmt.Errorf("update task status: %w", err)
+		}
+		if _, err := undo.write(tx); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("commit status update: %w", err)
+		}
+	}
+
+	db.afterStatusChange(id, oldTask, status, "")
+	return nil
+}
+
+// BlockTask moves a task to blocked like UpdateTaskStatus, recording
+// blockedReason (one of BlockedReasons) on the task and attaching reason
+// (typically the question the agent is waiting on) to the task.blocked event
+// so hooks and notifications can show it without reading the logs.
+func (db *DB) BlockTask(id int64, blockedReason, reason string) error {
+	oldTask, _ := db.GetTask(id)
+
+	query, args := statusUpdateQuery(id, StatusBlocked, blockedReason, oldTask)
+	if _, err := db.Exec(query, args...); err != nil {
+		return fmt.Errorf("update task status: %w", err)
+	}
+
+	db.afterStatusChange(id, oldTask, StatusBlocked, reason)
+	return nil
+}
+
+// UpdateTasksStatus sets status on every task in ids inside one transaction,
+// so either all of them change or none do. The per-task side effects of
+// UpdateTaskStatus (events, pane cleanup, unblocking dependents) run once the
+// transaction has committed.
+func (db *DB) UpdateTasksStatus(ids []int64, status string) error {
+	// Read everything up front: the pool has a single connection, which the
+	// transaction holds until it finishes.
+	oldTasks := make([]*Task, len(ids))
+	for i, id := range ids {
+		task, err := db.GetTask(id)
+		if err != nil {
+			return err
+		}
+		if task == nil {
+			return fmt.Errorf("task #%d not found", id)
+		}
+		oldTasks[i] = task
+	}
+	undo, err := db.statusUndo(status, oldTasks...)
+	if err != nil {
+		return err
 	}
 
-	query := "UPDATE tasks SET status = ?, updated_at = CURRENT_TIMESTAMP"
-	args := []interface{}{status}
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin status update: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i, id := range ids {
+		query, args := statusUpdateQuery(id, status, "", oldTasks[i])
+		if _, err := tx.Exec(query, args...); err != nil {
+			return fmt.Errorf("update task #%d status: %w", id, err)
+		}
+	}
+	if undo != nil {
+		if _, err := undo.write(tx); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit status update: %w", err)
+	}
+
+	for i, id := range ids {
+		db.afterStatusChange(id, oldTasks[i], status, "")
+	}
+	return nil
+}
+
+// statusUpdateQuery builds the UPDATE that moves task id to status, stamping
+// started_at/completed_at as the transition requires and replacing the blocked
+// reason with blockedReason (ignored unless status is blocked). oldTask may be nil.
+func statusUpdateQuery(id int64, status, blockedReason string, oldTask *Task) (string, []interface{}) {
+	if status != StatusBlocked {
+		blockedReason = ""
+	}
+	// Any status change cancels a pending automatic retry; the executor
+	// schedules a fresh one after it moves a failed task to blocked. A manual
+	// position only means something within its column, so moving to another
+	// status clears it.
+	query := "UPDATE tasks SET position = CASE WHEN status = ? THEN position END, status = ?, blocked_reason = ?, updated_at = CURRENT_TIMESTAMP, retry_at = NULL"
+	args := []interface{}{status, status, blockedReason}
 
 	switch status {
 	case StatusProcessing:
@@ -702,12 +1006,48 @@ func (db *DB) UpdateTaskStatus(id int64, status string) error {
 		}
 	}
 
+	// Archiving remembers the status to restore on unarchive (see
+	// UnarchiveTask); leaving archived forgets it.
+	if oldTask != nil && oldTask.Status != status {
+		if status == StatusArchived {
+			query += ", pre_archive_status = ?"
+			args = append(args, oldTask.Status)
+		} else if oldTask.Status == StatusArchived {
+			query += ", pre_archive_status = ''"
+		}
+	}
+
 	query += " WHERE id = ?"
 	args = append(args, id)
+	return query, args
+}
 
-	_, err := db.Exec(query, args...)
-	if err != nil {
-		return fmt.Errorf("update task status: %w", err)
+// statusUndo snapshots the tasks a status change is about to archive, so
+// archiving through any path (ty archive, ty status, bulk edits, the web UI)
+// can be undone. The caller writes the result in the transaction that
+// changes the status; it is nil when no task is being archived.
+func (db *DB) statusUndo(status string, oldTasks ...*Task) (*pendingUndo, error) {
+	if status != StatusArchived {
+		return nil, nil
+	}
+	var archiving []*Task
+	for _, task := range oldTasks {
+		if task != nil && task.Status != StatusArchived {
+			archiving = append(archiving, task)
+		}
+	}
+	if len(archiving) == 0 {
+		return nil, nil
+	}
+	return db.prepareUndo(UndoArchive, archiving...)
+}
+
+// afterStatusChange runs the side effects of a committed status change. reason,
+// when set, explains a move to blocked and is carried on the task.blocked event.
+func (db *DB) afterStatusChange(id int64, oldTask *Task, status, reason string) {
+	oldStatus := ""
+	if oldTask != nil {
+		oldStatus = oldTask.Status
 	}
 
 	// A finished task's executor pane is torn down; its tmux pane ID then becomes
@@ -721,6 +1061,15 @@ func (db *DB) UpdateTaskStatus(id int64, status string) error {
 		}
 	}
 
+	// Track the time a task spends processing (see time_entries.go).
+	if oldStatus != "" && oldStatus != status {
+		if status == StatusProcessing {
+			db.startAutoTimeEntry(id)
+		} else if oldStatus == StatusProcessing {
+			db.stopAutoTimeEntry(id)
+		}
+	}
+
 	// Emit status change event if status actually changed
 	if oldStatus != "" && oldStatus != status {
 		updatedTask, err := db.GetTask(id)
@@ -737,10 +1086,19 @@ func (db *DB) UpdateTaskStatus(id int64, status string) error {
 			// These fire for every caller of UpdateTaskStatus — Claude hooks,
 			// MCP, CLI, TUI, and the executor — as long as an emitter is registered.
 			switch status {
+			case StatusProcessing:
+				// The executor emits task.started itself (hooks included), so
+				// only record the transition for the event log's history.
+				db.recordEvent(EventTaskStarted, updatedTask.ID, updatedTask.Title)
+				db.runProjectActions(ActionTaskStarted, updatedTask)
 			case StatusBlocked:
-				db.emitTaskBlocked(updatedTask, "status change")
+				db.emitTaskBlocked(updatedTask, reason)
+				db.runProjectActions(ActionTaskBlocked, updatedTask)
 			case StatusDone:
 				db.emitTaskCompleted(updatedTask)
+				db.runProjectActions(ActionTaskCompleted, updatedTask)
+			case StatusArchived:
+				db.recordEvent(EventTaskArchived, updatedTask.ID, "from "+oldStatus)
 			}
 		}
 	}
@@ -753,8 +1111,6 @@ func (db *DB) UpdateTaskStatus(id int64, status string) error {
 			log.Printf("ProcessCompletedBlocker(%d): %v", id, err)
 		}
 	}
-
-	return nil
 }
 
 // UpdateTask updates a task's fields.
@@ -767,13 +1123,13 @@ func (db *DB) UpdateTask(t *Task) error {
 			title = ?, body = ?, status = ?, type = ?, project = ?, executor = ?,
 			worktree_path = ?, branch_name = ?, port = ?, claude_session_id = ?,
 			daemon_session = ?, pr_url = ?, pr_number = ?, pr_info_json = ?, dangerous_mode = ?, permission_mode = ?, remote_control = ?,
-			pinned = ?, tags = ?, source_branch = ?, effort_level = ?, model = ?,
+			pinned = ?, tags = ?, source_branch = ?, effort_level = ?, model = ?, assignee = ?, priority = ?,
 			updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
 	`, t.Title, t.Body, t.Status, t.Type, t.Project, t.Executor,
 		t.WorktreePath, t.BranchName, t.Port, t.ClaudeSessionID,
 		t.DaemonSession, t.PRURL, t.PRNumber, t.PRInfoJSON, t.DangerousMode, t.PermissionMode, t.RemoteControl,
-		t.Pinned, t.Tags, t.SourceBranch, t.EffortLevel, t.Model, t.ID)
+		t.Pinned, t.Tags, t.SourceBranch, t.EffortLevel, t.Model, t.Assignee, t.Priority, t.ID)
 	if err != nil {
 		return fmt.Errorf("update task: %w", err)
 	}
@@ -796,6 +1152,12 @@ func (db *DB) UpdateTask(t *Task) error {
 		if oldTask.Project != t.Project {
 			changes["project"] = map[string]string{"old": oldTask.Project, "new": t.Project}
 		}
+		if oldTask.Assignee != t.Assignee {
+			changes["assignee"] = map[string]string{"old": oldTask.Assignee, "new": t.Assignee}
+		}
+		if oldTask.Priority != t.Priority {
+			changes["priority"] = map[string]int{"old": oldTask.Priority, "new": t.Priority}
+		}
 		if len(changes) > 0 {
 			db.emitTaskUpdated(t, changes)
 		}
@@ -827,7 +1189,7 @@ func (db *DB) UpdateTaskPRInfo(taskID int64, prURL string, prNumber int, prInfoJ
 	}
 
 	if prevJSON != prInfoJSON {
-		db.recordEvent("task.updated", taskID, "pr status")
+		db.recordEvent(EventTaskUpdated, taskID, "pr status")
 	}
 	return nil
 }
@@ -1090,18 +1452,39 @@ func (db *DB) DeleteTask(id int64) error {
 // it. The row, worktree and Claude transcript all stay intact and recoverable via
 // RestoreTask until the daemon trash sweep hard-deletes it after the retention
 // window (see Executor.sweepTrashedTasks). Trashed tasks are hidden from the board
-// and every default query. No-op if the task is already trashed.
+// and every default query. The task is snapshotted into the undo log first. No-op
+// if the task is already trashed.
 func (db *DB) SoftDeleteTask(id int64) error {
 	task, _ := db.GetTask(id)
 	title := ""
+	var undo *pendingUndo
 	if task != nil {
 		title = task.Title
+		var trashed bool
+		if err := db.QueryRow(`SELECT deleted_at IS NOT NULL FROM tasks WHERE id = ?`, id).Scan(&trashed); err == nil && !trashed {
+			var err error
+			if undo, err = db.prepareUndo(UndoDelete, task); err != nil {
+				return err
+			}
+		}
 	}
 
-	_, err := db.Exec("UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL", id)
+	tx, err := db.Begin()
 	if err != nil {
+		return fmt.Errorf("begin soft-delete: %w", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL", id); err != nil {
 		return fmt.Errorf("soft-delete task: %w", err)
 	}
+	if undo != nil {
+		if _, err := undo.write(tx); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit soft-delete: %w", err)
+	}
 
 	// Reuse the delete event so listeners (board, web SSE) drop the task from view;
 	// a restore re-emits a normal task-updated event.
@@ -1227,6 +1610,16 @@ func (db *DB) AllocatePort(taskID int64) (int, error) {
 // RetryTask clears logs, appends feedback to body, and re-queues a task.
 // Also clears stale tmux window/pane IDs to prevent duplicate window issues.
 func (db *DB) RetryTask(id int64, feedback string) error {
+	// A manual retry starts the automatic retry budget over.
+	if err := db.ResetRetryCount(id); err != nil {
+		return err
+	}
+	return db.requeueWithFeedback(id, feedback)
+}
+
+// requeueWithFeedback re-queues a task, recording feedback for the resumed
+// session the way a manual retry does.
+func (db *DB) requeueWithFeedback(id int64, feedback string) error {
 	// Add continuation marker to logs
 	db.AppendTaskLog(id, "system", "--- Continuation ---")
 
@@ -1246,7 +1639,8 @@ func (db *DB) RetryTask(id int64, feedback string) error {
 	return db.UpdateTaskStatus(id, StatusQueued)
 }
 
-// GetNextQueuedTask returns the next task to process.
+// GetNextQueuedTask returns the next task to process: the highest priority
+// queued task, oldest first among equal priorities.
 func (db *DB) GetNextQueuedTask() (*Task, error) {
 	t := &Task{}
 	err := db.QueryRow(`
@@ -1256,14 +1650,15 @@ func (db *DB) GetNextQueuedTask() (*Task, error) {
 		       COALESCE(claude_pane_id, ''), COALESCE(shell_pane_id, ''),
 		       COALESCE(pr_url, ''), COALESCE(pr_number, 0), COALESCE(pr_info_json, ''),
 		       COALESCE(dangerous_mode, 0), COALESCE(permission_mode, ''), COALESCE(remote_control, 0), COALESCE(pinned, 0), COALESCE(tags, ''),
-		       COALESCE(source_branch, ''), COALESCE(summary, ''), COALESCE(effort_level, ''), COALESCE(model, ''), COALESCE(claude_config_dir, ''), COALESCE(env, ''),
+		       COALESCE(source_branch, ''), COALESCE(summary, ''), COALESCE(effort_level, ''), COALESCE(model, ''), COALESCE(claude_config_dir, ''), COALESCE(env, ''), COALESCE(executor_args, ''), COALESCE(blocked_reason, ''),
+		       COALESCE(assignee, ''), COALESCE(priority, 0), COALESCE(input_tokens, 0), COALESCE(output_tokens, 0), COALESCE(cost_usd, 0), position,
 		       created_at, updated_at, started_at, completed_at,
 		       last_distilled_at, last_accessed_at,
 		       COALESCE(archive_ref, ''), COALESCE(archive_commit, ''),
 		       COALESCE(archive_worktree_path, ''), COALESCE(archive_branch_name, '')
 		FROM tasks
 		WHERE status = ? AND deleted_at IS NULL
-		ORDER BY created_at ASC
+		ORDER BY COALESCE(priority, 0) DESC, created_at ASC, id ASC
 		LIMIT 1
 	`, StatusQueued).Scan(
 		&t.ID, &t.Title, &t.Body, &t.Status, &t.Type, &t.Project, &t.Executor,
@@ -1271,7 +1666,8 @@ func (db *DB) GetNextQueuedTask() (*Task, error) {
 		&t.DaemonSession, &t.TmuxWindowID, &t.ClaudePaneID, &t.ShellPaneID,
 		&t.PRURL, &t.PRNumber, &t.PRInfoJSON,
 		&t.DangerousMode, &t.PermissionMode, &t.RemoteControl, &t.Pinned, &t.Tags,
-		&t.SourceBranch, &t.Summary, &t.EffortLevel, &t.Model, &t.ClaudeConfigDir, &t.EnvJSON,
+		&t.SourceBranch, &t.Summary, &t.EffortLevel, &t.Model, &t.ClaudeConfigDir, &t.EnvJSON, &t.ExecutorArgs, &t.BlockedReason,
+		&t.Assignee, &t.Priority, &t.InputTokens, &t.OutputTokens, &t.CostUSD, &t.Position,
 		&t.CreatedAt, &t.UpdatedAt, &t.StartedAt, &t.CompletedAt,
 		&t.LastDistilledAt, &t.LastAccessedAt,
 		&t.ArchiveRef, &t.ArchiveCommit, &t.ArchiveWorktreePath, &t.ArchiveBranchName,
@@ -1285,7 +1681,8 @@ func (db *DB) GetNextQueuedTask() (*Task, error) {
 	return t, nil
 }
 
-// GetQueuedTasks returns all queued tasks (waiting to be processed).
+// GetQueuedTasks returns all queued tasks (waiting to be processed) in the
+// order they should run: priority descending, then FIFO by creation time.
 func (db *DB) GetQueuedTasks() ([]*Task, error) {
 	rows, err := db.Query(`
 		SELECT id, title, body, status, type, project, COALESCE(executor, 'claude'),
@@ -1294,14 +1691,15 @@ func (db *DB) GetQueuedTasks() ([]*Task, error) {
 		       COALESCE(claude_pane_id, ''), COALESCE(shell_pane_id, ''),
 		       COALESCE(pr_url, ''), COALESCE(pr_number, 0), COALESCE(pr_info_json, ''),
 		       COALESCE(dangerous_mode, 0), COALESCE(permission_mode, ''), COALESCE(remote_control, 0), COALESCE(pinned, 0), COALESCE(tags, ''),
-		       COALESCE(source_branch, ''), COALESCE(summary, ''), COALESCE(effort_level, ''), COALESCE(model, ''), COALESCE(claude_config_dir, ''), COALESCE(env, ''),
+		       COALESCE(source_branch, ''), COALESCE(summary, ''), COALESCE(effort_level, ''), COALESCE(model, ''), COALESCE(claude_config_dir, ''), COALESCE(env, ''), COALESCE(executor_args, ''), COALESCE(blocked_reason, ''),
+		       COALESCE(assignee, ''), COALESCE(priority, 0), COALESCE(input_tokens, 0), COALESCE(output_tokens, 0), COALESCE(cost_usd, 0), position,
 		       created_at, updated_at, started_at, completed_at,
 		       last_distilled_at, last_accessed_at,
 		       COALESCE(archive_ref, ''), COALESCE(archive_commit, ''),
 		       COALESCE(archive_worktree_path, ''), COALESCE(archive_branch_name, '')
 		FROM tasks
 		WHERE status = ? AND deleted_at IS NULL
-		ORDER BY created_at ASC
+		ORDER BY COALESCE(priority, 0) DESC, created_at ASC, id ASC
 	`, StatusQueued)
 	if err != nil {
 		return nil, fmt.Errorf("query queued tasks: %w", err)
@@ -1317,7 +1715,8 @@ func (db *DB) GetQueuedTasks() ([]*Task, error) {
 			&t.DaemonSession, &t.TmuxWindowID, &t.ClaudePaneID, &t.ShellPaneID,
 			&t.PRURL, &t.PRNumber, &t.PRInfoJSON,
 			&t.DangerousMode, &t.PermissionMode, &t.RemoteControl, &t.Pinned, &t.Tags,
-			&t.SourceBranch, &t.Summary, &t.EffortLevel, &t.Model, &t.ClaudeConfigDir, &t.EnvJSON,
+			&t.SourceBranch, &t.Summary, &t.EffortLevel, &t.Model, &t.ClaudeConfigDir, &t.EnvJSON, &t.ExecutorArgs, &t.BlockedReason,
+			&t.Assignee, &t.Priority, &t.InputTokens, &t.OutputTokens, &t.CostUSD, &t.Position,
 			&t.CreatedAt, &t.UpdatedAt, &t.StartedAt, &t.CompletedAt,
 			&t.LastDistilledAt, &t.LastAccessedAt,
 			&t.ArchiveRef, &t.ArchiveCommit, &t.ArchiveWorktreePath, &t.ArchiveBranchName,
@@ -1338,8 +1737,49 @@ type TaskLog struct {
 	CreatedAt LocalTime
 }
 
-// AppendTaskLog appends a log entry to a task.
+// Log line types for notes a user leaves on a task with `ty note`. They are
+// never sent to the agent and never change the task's status. A pinned note is
+// also shown at the top of the task's detail view.
+const (
+	NoteLineType       = "note"
+	PinnedNoteLineType = "note_pinned"
+)
+
+// GetPinnedNotes returns a task's pinned notes, oldest first.
+func (db *DB) GetPinnedNotes(taskID int64) ([]*TaskLog, error) {
+	rows, err := db.Query(`
+		SELECT id, task_id, line_type, content, created_at
+		FROM task_logs
+		WHERE task_id = ? AND line_type = ?
+		ORDER BY id ASC
+	`, taskID, PinnedNoteLineType)
+	if err != nil {
+		return nil, fmt.Errorf("query pinned notes: %w", err)
+	}
+	defer rows.Close()
+
+	var logs []*TaskLog
+	for rows.Next() {
+		l := &TaskLog{}
+		if err := rows.Scan(&l.ID, &l.TaskID, &l.LineType, &l.Content, &l.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan pinned note: %w", err)
+		}
+		logs = append(logs, l)
+	}
+	return logs, rows.Err()
+}
+
+// AppendTaskLog appends a log entry to a task, through the LogBatcher when
+// BatchTaskLogs is in effect.
 func (db *DB) AppendTaskLog(taskID int64, lineType, content string) error {
+	if db.logBatcher != nil {
+		return db.logBatcher.Append(taskID, lineType, content)
+	}
+	return db.appendTaskLog(taskID, lineType, content)
+}
+
+// appendTaskLog inserts a single log row.
+func (db *DB) appendTaskLog(taskID int64, lineType, content string) error {
 	_, err := db.Exec(`
 		INSERT INTO task_logs (task_id, line_type, content)
 		VALUES (?, ?, ?)
@@ -1656,7 +2096,7 @@ func (db *DB) GetRetryFeedback(taskID int64) (string, error) {
 
 // ProjectAction defines an action that runs on tasks for a project.
 type ProjectAction struct {
-	Trigger      string `json:"trigger"`      // "on_create", "on_status:queued", etc.
+	Trigger      string `json:"trigger"`      // one of ProjectActionTriggers, e.g. "task.completed"
 	Instructions string `json:"instructions"` // prompt/instructions for this action
 }
 
@@ -1674,7 +2114,19 @@ type Project struct {
 	// DefaultPermissionMode is the permission mode new tasks in this project
 	// inherit ("default", "auto", "dangerous"). Empty means use the global default.
 	DefaultPermissionMode string
-	CreatedAt             LocalTime
+	// Executor is the default executor new tasks in this project use when none
+	// is given explicitly. Empty means use the global default (claude).
+	Executor string
+	// DefaultModel is the Claude model tasks in this project run with when they
+	// don't pick one. Empty means use the default_model setting.
+	DefaultModel string
+	// BaseBranch is the branch new task worktrees are created from. Empty
+	// means the repository's default branch.
+	BaseBranch string
+	// WIPLimit caps how many of the project's tasks the daemon runs at once.
+	// Zero means unlimited.
+	WIPLimit  int
+	CreatedAt LocalTime
 }
 
 // UsesWorktrees returns whether this project uses git worktrees for task isolation.
@@ -1683,6 +2135,15 @@ func (p *Project) UsesWorktrees() bool {
 	return p.UseWorktrees
 }
 
+// EffectiveExecutor returns the executor new tasks in this project should use
+// when none is given explicitly, falling back to the global default.
+func (p *Project) EffectiveExecutor() string {
+	if p.Executor != "" {
+		return p.Executor
+	}
+	return DefaultExecutor()
+}
+
 // EffectiveDefaultPermissionMode returns the permission mode new tasks in this
 // project should inherit, falling back to the global default when unset.
 func (p *Project) EffectiveDefaultPermissionMode() string {
@@ -1714,9 +2175,9 @@ func boolToInt(b bool) int {
 func (db *DB) CreateProject(p *Project) error {
 	actionsJSON, _ := json.Marshal(p.Actions)
 	result, err := db.Exec(`
-		INSERT INTO projects (name, path, aliases, instructions, actions, color, claude_config_dir, use_worktrees, default_permission_mode)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, p.Name, p.Path, p.Aliases, p.Instructions, string(actionsJSON), p.Color, p.ClaudeConfigDir, boolToInt(p.UseWorktrees), NormalizePermissionMode(p.DefaultPermissionMode))
+		INSERT INTO projects (name, path, aliases, instructions, actions, color, claude_config_dir, use_worktrees, default_permission_mode, default_executor, default_model, base_branch, wip_limit)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, p.Name, p.Path, p.Aliases, p.Instructions, string(actionsJSON), p.Color, p.ClaudeConfigDir, boolToInt(p.UseWorktrees), NormalizePermissionMode(p.DefaultPermissionMode), p.Executor, p.DefaultModel, p.BaseBranch, p.WIPLimit)
 	if err != nil {
 		return fmt.Errorf("insert project: %w", err)
 	}
@@ -1729,9 +2190,9 @@ func (db *DB) CreateProject(p *Project) error {
 func (db *DB) UpdateProject(p *Project) error {
 	actionsJSON, _ := json.Marshal(p.Actions)
 	_, err := db.Exec(`
-		UPDATE projects SET name = ?, path = ?, aliases = ?, instructions = ?, actions = ?, color = ?, claude_config_dir = ?, use_worktrees = ?, default_permission_mode = ?
+		UPDATE projects SET name = ?, path = ?, aliases = ?, instructions = ?, actions = ?, color = ?, claude_config_dir = ?, use_worktrees = ?, default_permission_mode = ?, default_executor = ?, default_model = ?, base_branch = ?, wip_limit = ?
 		WHERE id = ?
-	`, p.Name, p.Path, p.Aliases, p.Instructions, string(actionsJSON), p.Color, p.ClaudeConfigDir, boolToInt(p.UseWorktrees), NormalizePermissionMode(p.DefaultPermissionMode), p.ID)
+	`, p.Name, p.Path, p.Aliases, p.Instructions, string(actionsJSON), p.Color, p.ClaudeConfigDir, boolToInt(p.UseWorktrees), NormalizePermissionMode(p.DefaultPermissionMode), p.Executor, p.DefaultModel, p.BaseBranch, p.WIPLimit, p.ID)
 	if err != nil {
 		return fmt.Errorf("update project: %w", err)
 	}
@@ -1772,7 +2233,7 @@ func (db *DB) CountTasksByProject(projectName string) (int, error) {
 // ListProjects returns all projects, with "personal" always first.
 func (db *DB) ListProjects() ([]*Project, error) {
 	rows, err := db.Query(`
-		SELECT id, name, path, aliases, instructions, COALESCE(actions, '[]'), COALESCE(color, ''), COALESCE(claude_config_dir, ''), COALESCE(use_worktrees, 1), COALESCE(default_permission_mode, ''), created_at
+		SELECT id, name, path, aliases, instructions, COALESCE(actions, '[]'), COALESCE(color, ''), COALESCE(claude_config_dir, ''), COALESCE(use_worktrees, 1), COALESCE(default_permission_mode, ''), COALESCE(default_executor, ''), COALESCE(default_model, ''), COALESCE(base_branch, ''), COALESCE(wip_limit, 0), created_at
 		FROM projects ORDER BY CASE WHEN name = 'personal' THEN 0 ELSE 1 END, name
 	`)
 	if err != nil {
@@ -1785,7 +2246,7 @@ func (db *DB) ListProjects() ([]*Project, error) {
 		p := &Project{}
 		var actionsJSON string
 		var useWorktrees int
-		if err := rows.Scan(&p.ID, &p.Name, &p.Path, &p.Aliases, &p.Instructions, &actionsJSON, &p.Color, &p.ClaudeConfigDir, &useWorktrees, &p.DefaultPermissionMode, &p.CreatedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.Name, &p.Path, &p.Aliases, &p.Instructions, &actionsJSON, &p.Color, &p.ClaudeConfigDir, &useWorktrees, &p.DefaultPermissionMode, &p.Executor, &p.DefaultModel, &p.BaseBranch, &p.WIPLimit, &p.CreatedAt); err != nil {
 			return nil, fmt.Errorf("scan project: %w", err)
 		}
 		json.Unmarshal([]byte(actionsJSON), &p.Actions)
@@ -1802,9 +2263,9 @@ func (db *DB) GetProjectByName(name string) (*Project, error) {
 	var actionsJSON string
 	var useWorktrees int
 	err := db.QueryRow(`
-		SELECT id, name, path, aliases, instructions, COALESCE(actions, '[]'), COALESCE(color, ''), COALESCE(claude_config_dir, ''), COALESCE(use_worktrees, 1), COALESCE(default_permission_mode, ''), created_at
+		SELECT id, name, path, aliases, instructions, COALESCE(actions, '[]'), COALESCE(color, ''), COALESCE(claude_config_dir, ''), COALESCE(use_worktrees, 1), COALESCE(default_permission_mode, ''), COALESCE(default_executor, ''), COALESCE(default_model, ''), COALESCE(base_branch, ''), COALESCE(wip_limit, 0), created_at
 		FROM projects WHERE name = ?
-	`, name).Scan(&p.ID, &p.Name, &p.Path, &p.Aliases, &p.Instructions, &actionsJSON, &p.Color, &p.ClaudeConfigDir, &useWorktrees, &p.DefaultPermissionMode, &p.CreatedAt)
+	`, name).Scan(&p.ID, &p.Name, &p.Path, &p.Aliases, &p.Instructions, &actionsJSON, &p.Color, &p.ClaudeConfigDir, &useWorktrees, &p.DefaultPermissionMode, &p.Executor, &p.DefaultModel, &p.BaseBranch, &p.WIPLimit, &p.CreatedAt)
 	if err == nil {
 		json.Unmarshal([]byte(actionsJSON), &p.Actions)
 		p.UseWorktrees = useWorktrees != 0
@@ -1815,7 +2276,7 @@ func (db *DB) GetProjectByName(name string) (*Project, error) {
 	}
 
 	// Try alias match
-	rows, err := db.Query(`SELECT id, name, path, aliases, instructions, COALESCE(actions, '[]'), COALESCE(color, ''), COALESCE(claude_config_dir, ''), COALESCE(use_worktrees, 1), COALESCE(default_permission_mode, ''), created_at FROM projects`)
+	rows, err := db.Query(`SELECT id, name, path, aliases, instructions, COALESCE(actions, '[]'), COALESCE(color, ''), COALESCE(claude_config_dir, ''), COALESCE(use_worktrees, 1), COALESCE(default_permission_mode, ''), COALESCE(default_executor, ''), COALESCE(default_model, ''), COALESCE(base_branch, ''), COALESCE(wip_limit, 0), created_at FROM projects`)
 	if err != nil {
 		return nil, fmt.Errorf("query projects: %w", err)
 	}
@@ -1823,7 +2284,7 @@ func (db *DB) GetProjectByName(name string) (*Project, error) {
 
 	for rows.Next() {
 		p := &Project{}
-		if err := rows.Scan(&p.ID, &p.Name, &p.Path, &p.Aliases, &p.Instructions, &actionsJSON, &p.Color, &p.ClaudeConfigDir, &useWorktrees, &p.DefaultPermissionMode, &p.CreatedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.Name, &p.Path, &p.Aliases, &p.Instructions, &actionsJSON, &p.Color, &p.ClaudeConfigDir, &useWorktrees, &p.DefaultPermissionMode, &p.Executor, &p.DefaultModel, &p.BaseBranch, &p.WIPLimit, &p.CreatedAt); err != nil {
 			return nil, fmt.Errorf("scan project: %w", err)
 		}
 		json.Unmarshal([]byte(actionsJSON), &p.Actions)
@@ -1901,7 +2362,16 @@ func (db *DB) SetProjectContext(projectName string, context string) error {
 }
 
 // GetSetting returns a setting value.
+// Secret settings are decrypted transparently (see secrets.go).
 func (db *DB) GetSetting(key string) (string, error) {
+	value, err := db.getRawSetting(key)
+	if err != nil || value == "" || !IsSecretSetting(key) {
+		return value, err
+	}
+	return db.revealSecret(value)
+}
+
+func (db *DB) getRawSetting(key string) (string, error) {
 	var value string
 	err := db.QueryRow("SELECT value FROM settings WHERE key = ?", key).Scan(&value)
 	if err == sql.ErrNoRows {
@@ -1913,8 +2383,18 @@ func (db *DB) GetSetting(key string) (string, error) {
 	return value, nil
 }
 
-// SetSetting sets a setting value.
+// SetSetting sets a setting value. Secret settings are stored according to
+// secret_storage; use SetSecretSetting to learn whether that fell back to
+// plaintext.
 func (db *DB) SetSetting(key, value string) error {
+	if IsSecretSetting(key) {
+		_, err := db.SetSecretSetting(key, value)
+		return err
+	}
+	return db.setRawSetting(key, value)
+}
+
+func (db *DB) setRawSetting(key, value string) error {
 	_, err := db.Exec(`
 		INSERT INTO settings (key, value) VALUES (?, ?)
 		ON CONFLICT(key) DO UPDATE SET value = ?
@@ -1925,7 +2405,8 @@ func (db *DB) SetSetting(key, value string) error {
 	return nil
 }
 
-// GetAllSettings returns all settings as a map.
+// GetAllSettings returns all settings as a map. Secret settings are returned in
+// their stored (possibly encrypted) form.
 func (db *DB) GetAllSettings() (map[string]string, error) {
 	rows, err := db.Query("SELECT key, value FROM settings")
 	if err != nil {
@@ -2153,36 +2634,65 @@ func (db *DB) GetTaskTypeByName(name string) (*TaskType, error) {
 
 // Attachment represents a file attached to a task.
 type Attachment struct {
-	ID        int64
-	TaskID    int64
-	Filename  string
-	MimeType  string
-	Size      int64
-	Data      []byte
-	CreatedAt LocalTime
+	ID       int64
+	TaskID   int64
+	Filename string
+	MimeType string
+	Size     int64
+	Data     []byte
+	// ContentHash is the hex SHA-256 of Data. Rows added before the column
+	// existed have it empty.
+	ContentHash string
+	CreatedAt   LocalTime
+}
+
+// AttachmentHash returns the content hash stored for an attachment's data.
+func AttachmentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
 }
 
 // AddAttachment adds a file attachment to a task.
 func (db *DB) AddAttachment(taskID int64, filename, mimeType string, data []byte) (*Attachment, error) {
+	hash := AttachmentHash(data)
 	result, err := db.Exec(`
-		INSERT INTO task_attachments (task_id, filename, mime_type, size, data)
-		VALUES (?, ?, ?, ?, ?)
-	`, taskID, filename, mimeType, len(data), data)
+		INSERT INTO task_attachments (task_id, filename, mime_type, size, data, content_hash)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, taskID, filename, mimeType, len(data), data, hash)
 	if err != nil {
 		return nil, fmt.Errorf("insert attachment: %w", err)
 	}
 
 	id, _ := result.LastInsertId()
 	return &Attachment{
-		ID:       id,
-		TaskID:   taskID,
-		Filename: filename,
-		MimeType: mimeType,
-		Size:     int64(len(data)),
-		Data:     data,
+		ID:          id,
+		TaskID:      taskID,
+		Filename:    filename,
+		MimeType:    mimeType,
+		Size:        int64(len(data)),
+		Data:        data,
+		ContentHash: hash,
 	}, nil
 }
 
+// FindAttachmentByHash returns the task's attachment whose content hashes to
+// hash (without data), or nil if the task has none.
+func (db *DB) FindAttachmentByHash(taskID int64, hash string) (*Attachment, error) {
+	a := &Attachment{}
+	err := db.QueryRow(`
+		SELECT id, task_id, filename, mime_type, size, content_hash, created_at
+		FROM task_attachments WHERE task_id = ? AND content_hash = ?
+		ORDER BY id LIMIT 1
+	`, taskID, hash).Scan(&a.ID, &a.TaskID, &a.Filename, &a.MimeType, &a.Size, &a.ContentHash, &a.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find attachment: %w", err)
+	}
+	return a, nil
+}
+
 // GetAttachment retrieves an attachment by ID.
 func (db *DB) GetAttachment(id int64) (*Attachment, error) {
 	a := &Attachment{}
@@ -2199,7 +2709,7 @@ func (db *DB) GetAttachment(id int64) (*Attachment, error) {
 // ListAttachments retrieves all attachments for a task (without data for efficiency).
 func (db *DB) ListAttachments(taskID int64) ([]*Attachment, error) {
 	rows, err := db.Query(`
-		SELECT id, task_id, filename, mime_type, size, created_at
+		SELECT id, task_id, filename, mime_type, size, COALESCE(content_hash, ''), created_at
 		FROM task_attachments WHERE task_id = ?
 		ORDER BY created_at ASC
 	`, taskID)
@@ -2211,7 +2721,7 @@ func (db *DB) ListAttachments(taskID int64) ([]*Attachment, error) {
 	var attachments []*Attachment
 	for rows.Next() {
 		a := &Attachment{}
-		if err := rows.Scan(&a.ID, &a.TaskID, &a.Filename, &a.MimeType, &a.Size, &a.CreatedAt); err != nil {
+		if err := rows.Scan(&a.ID, &a.TaskID, &a.Filename, &a.MimeType, &a.Size, &a.ContentHash, &a.CreatedAt); err != nil {
 			return nil, fmt.Errorf("scan attachment: %w", err)
 		}
 		attachments = append(attachments, a)
@@ -2349,7 +2859,8 @@ func (db *DB) GetStaleWorktreeTasks(maxAge time.Duration) ([]*Task, error) {
 		       COALESCE(claude_pane_id, ''), COALESCE(shell_pane_id, ''),
 		       COALESCE(pr_url, ''), COALESCE(pr_number, 0), COALESCE(pr_info_json, ''),
 		       COALESCE(dangerous_mode, 0), COALESCE(permission_mode, ''), COALESCE(remote_control, 0), COALESCE(pinned, 0), COALESCE(tags, ''),
-		       COALESCE(source_branch, ''), COALESCE(summary, ''), COALESCE(effort_level, ''), COALESCE(model, ''), COALESCE(claude_config_dir, ''), COALESCE(env, ''),
+		       COALESCE(source_branch, ''), COALESCE(summary, ''), COALESCE(effort_level, ''), COALESCE(model, ''), COALESCE(claude_config_dir, ''), COALESCE(env, ''), COALESCE(executor_args, ''), COALESCE(blocked_reason, ''),
+		       COALESCE(assignee, ''), COALESCE(priority, 0), COALESCE(input_tokens, 0), COALESCE(output_tokens, 0), COALESCE(cost_usd, 0), position,
 		       created_at, updated_at, started_at, completed_at,
 		       last_distilled_at, last_accessed_at,
 		       COALESCE(archive_ref, ''), COALESCE(archive_commit, ''),
@@ -2377,7 +2888,8 @@ func (db *DB) GetStaleWorktreeTasks(maxAge time.Duration) ([]*Task, error) {
 			&t.DaemonSession, &t.TmuxWindowID, &t.ClaudePaneID, &t.ShellPaneID,
 			&t.PRURL, &t.PRNumber, &t.PRInfoJSON,
 			&t.DangerousMode, &t.PermissionMode, &t.RemoteControl, &t.Pinned, &t.Tags,
-			&t.SourceBranch, &t.Summary, &t.EffortLevel, &t.Model, &t.ClaudeConfigDir, &t.EnvJSON,
+			&t.SourceBranch, &t.Summary, &t.EffortLevel, &t.Model, &t.ClaudeConfigDir, &t.EnvJSON, &t.ExecutorArgs, &t.BlockedReason,
+			&t.Assignee, &t.Priority, &t.InputTokens, &t.OutputTokens, &t.CostUSD, &t.Position,
 			&t.CreatedAt, &t.UpdatedAt, &t.StartedAt, &t.CompletedAt,
 			&t.LastDistilledAt, &t.LastAccessedAt,
 			&t.ArchiveRef, &t.ArchiveCommit, &t.ArchiveWorktreePath, &t.ArchiveBranchName,
@@ -2897,10 +2897,114 @@ func TestListTasksTagFilterEscapesLikeWildcards(t *testing.T) {
 	}
 }
 
+func TestListTasksTagsFilterRequiresAll(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	database, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer database.Close()
+	defer os.Remove(dbPath)
+
+	if err := database.CreateProject(&Project{Name: "test", Path: tmpDir}); err != nil {
+		t.Fatalf("failed to create test project: %v", err)
+	}
+
+	both := &Task{Title: "both", Status: StatusBacklog, Type: TypeCode, Project: "test", Tags: "Bug, UI"}
+	bugOnly := &Task{Title: "bug only", Status: StatusBacklog, Type: TypeCode, Project: "test", Tags: "bug"}
+	debugging := &Task{Title: "debugging", Status: StatusBacklog, Type: TypeCode, Project: "test", Tags: "debugging,ui"}
+	bothAgain := &Task{Title: "both again", Status: StatusBacklog, Type: TypeCode, Project: "test", Tags: "ui,backend,bug"}
+
+	for _, tk := range []*Task{both, bugOnly, debugging, bothAgain} {
+		if err := database.CreateTask(tk); err != nil {
+			t.Fatalf("failed to create task %q: %v", tk.Title, err)
+		}
+	}
+
+	tasks, err := database.ListTasks(ListTasksOptions{Tags: []string{"bug", "ui"}})
+	if err != nil {
+		t.Fatalf("ListTasks with tags filter failed: %v", err)
+	}
+	got := map[int64]bool{}
+	for _, tk := range tasks {
+		got[tk.ID] = true
+	}
+	if len(tasks) != 2 || !got[both.ID] || !got[bothAgain.ID] {
+		t.Errorf("expected only the tasks tagged both bug and ui (case-insensitive), got %d tasks", len(tasks))
+	}
+
+	// The filter is applied in SQL, so a limit counts matching tasks only.
+	limited, err := database.ListTasks(ListTasksOptions{Tags: []string{"BUG"}, Limit: 2})
+	if err != nil {
+		t.Fatalf("ListTasks with limit failed: %v", err)
+	}
+	if len(limited) != 2 {
+		t.Errorf("expected 2 tasks with limit 2, got %d", len(limited))
+	}
+	for _, tk := range limited {
+		if tk.ID == debugging.ID {
+			t.Errorf("tag filter bug must NOT match debugging")
+		}
+	}
+
+	if n, err := database.CountTasks(ListTasksOptions{Tags: []string{"bug"}}); err != nil || n != 3 {
+		t.Errorf("CountTasks(tags=[bug]) = %d, %v; want 3", n, err)
+	}
+}
+
 // TestUpdateTaskStatus_ClearsPaneIDsOnTerminal verifies that finishing a task
 // drops its tmux pane IDs (which tmux is now free to recycle onto another task)
 // while leaving the window ID intact. Guards the pane-ID reuse vector behind the
 // 4324/4822 cross-wiring incident.
+func TestUpdateTasksStatus_RollsBackOnFailure(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	var ids []int64
+	for _, title := range []string{"a", "b", "c"} {
+		task := &Task{Title: title, Status: StatusBacklog, Type: TypeCode, Project: "personal"}
+		if err := db.CreateTask(task); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, task.ID)
+	}
+
+	// Make the update of the last task fail after the first two succeeded.
+	if _, err := db.Exec(`CREATE TRIGGER fail_status BEFORE UPDATE OF status ON tasks
+		WHEN NEW.title = 'c' BEGIN SELECT RAISE(ABORT, 'boom'); END`); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.UpdateTasksStatus(ids, StatusQueued); err == nil {
+		t.Fatal("expected an error from the failing update")
+	}
+	for _, id := range ids {
+		if task, _ := db.GetTask(id); task.Status != StatusBacklog {
+			t.Errorf("task #%d status = %q, want the whole batch rolled back", id, task.Status)
+		}
+	}
+
+	if _, err := db.Exec("DROP TRIGGER fail_status"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.UpdateTasksStatus(ids, StatusDone); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range ids {
+		if task, _ := db.GetTask(id); task.Status != StatusDone || task.CompletedAt == nil {
+			t.Errorf("task #%d = %q (completed_at %v), want done", id, task.Status, task.CompletedAt)
+		}
+	}
+
+	if err := db.UpdateTasksStatus([]int64{ids[0], 9999}, StatusBacklog); err == nil {
+		t.Error("expected an error for a missing task")
+	}
+	if task, _ := db.GetTask(ids[0]); task.Status != StatusDone {
+		t.Errorf("status = %q, want unchanged when a task is missing", task.Status)
+	}
+}
+
 func TestUpdateTaskStatus_ClearsPaneIDsOnTerminal(t *testing.T) {
 	tmpDir := t.TempDir()
 	db, err := Open(filepath.Join(tmpDir, "test.db"))
@@ -2965,3 +3069,166 @@ func TestUpdateTaskStatus_ClearsPaneIDsOnTerminal(t *testing.T) {
 		}
 	})
 }
+
+func TestAssigneePersistenceAndFilter(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	database, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	if err := database.CreateProject(&Project{Name: "test", Path: tmpDir}); err != nil {
+		t.Fatalf("failed to create test project: %v", err)
+	}
+
+	alice := &Task{Title: "alice task", Status: StatusBacklog, Type: TypeCode, Project: "test", Assignee: "alice"}
+	bob := &Task{Title: "bob task", Status: StatusBacklog, Type: TypeCode, Project: "test"}
+	nobody := &Task{Title: "unassigned task", Status: StatusBacklog, Type: TypeCode, Project: "test"}
+	for _, tk := range []*Task{alice, bob, nobody} {
+		if err := database.CreateTask(tk); err != nil {
+			t.Fatalf("failed to create task %q: %v", tk.Title, err)
+		}
+	}
+
+	got, err := database.GetTask(alice.ID)
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	if got.Assignee != "alice" {
+		t.Errorf("expected assignee alice, got %q", got.Assignee)
+	}
+
+	// Assign via UpdateTask.
+	bob.Assignee = "bob"
+	if err := database.UpdateTask(bob); err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+
+	tasks, err := database.ListTasks(ListTasksOptions{Assignee: "bob"})
+	if err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != bob.ID {
+		t.Errorf("expected only bob's task, got %d tasks", len(tasks))
+	}
+
+	tasks, err = database.ListTasks(ListTasksOptions{Unassigned: true})
+	if err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != nobody.ID {
+		t.Errorf("expected only the unassigned task, got %d tasks", len(tasks))
+	}
+
+	// No filter keeps single-user behavior: everything is listed.
+	tasks, err = database.ListTasks(ListTasksOptions{})
+	if err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+	if len(tasks) != 3 {
+		t.Errorf("expected 3 tasks without an assignee filter, got %d", len(tasks))
+	}
+}
+
+func TestCountTasks(t *testing.T) {
+	tmpDir := t.TempDir()
+	database, err := Open(filepath.Join(tmpDir, "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	if err := database.CreateProject(&Project{Name: "test", Path: tmpDir}); err != nil {
+		t.Fatalf("failed to create test project: %v", err)
+	}
+	for _, tk := range []*Task{
+		{Title: "a", Status: StatusBacklog, Type: TypeCode, Project: "test", Tags: "x"},
+		{Title: "b", Status: StatusBacklog, Type: TypeCode, Project: "test"},
+		{Title: "c", Status: StatusQueued, Type: TypeCode, Project: "personal", Tags: "x"},
+		{Title: "d", Status: StatusDone, Type: TypeCode, Project: "test"},
+	} {
+		if err := database.CreateTask(tk); err != nil {
+			t.Fatalf("failed to create task %q: %v", tk.Title, err)
+		}
+	}
+
+	// Same filters as ListTasks: closed tasks are excluded by default and the
+	// list limit doesn't cap the count.
+	if n, err := database.CountTasks(ListTasksOptions{Limit: 1}); err != nil || n != 3 {
+		t.Errorf("CountTasks = %d, %v; want 3", n, err)
+	}
+	if n, err := database.CountTasks(ListTasksOptions{Tag: "x"}); err != nil || n != 2 {
+		t.Errorf("CountTasks(tag x) = %d, %v; want 2", n, err)
+	}
+
+	counts, err := database.CountTasksBy(ListTasksOptions{IncludeClosed: true}, "status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TaskCount{{StatusBacklog, 2}, {StatusDone, 1}, {StatusQueued, 1}}
+	if len(counts) != len(want) {
+		t.Fatalf("CountTasksBy(status) = %+v, want %+v", counts, want)
+	}
+	for i := range want {
+		if counts[i] != want[i] {
+			t.Errorf("CountTasksBy(status)[%d] = %+v, want %+v", i, counts[i], want[i])
+		}
+	}
+
+	if _, err := database.CountTasksBy(ListTasksOptions{}, "title; DROP TABLE tasks"); err == nil {
+		t.Error("expected an unsupported field to be rejected")
+	}
+}
+
+func TestQueuedTasksPriorityOrder(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	// Created in this order within the same second, so FIFO falls back to ID.
+	specs := []struct {
+		title    string
+		priority int
+	}{
+		{"low-a", 0},
+		{"high", 5},
+		{"low-b", 0},
+		{"urgent", 10},
+		{"high-b", 5},
+	}
+	for _, spec := range specs {
+		task := &Task{Title: spec.title, Status: StatusQueued, Project: "personal", Priority: spec.priority}
+		if err := database.CreateTask(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tasks, err := database.GetQueuedTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, task := range tasks {
+		got = append(got, task.Title)
+	}
+	want := []string{"urgent", "high", "high-b", "low-a", "low-b"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("GetQueuedTasks order = %v, want %v", got, want)
+	}
+
+	next, err := database.GetNextQueuedTask()
+	if err != nil || next == nil || next.Title != "urgent" || next.Priority != 10 {
+		t.Errorf("GetNextQueuedTask = %+v, %v; want the urgent task", next, err)
+	}
+
+	// Bumping a task via UpdateTask moves it ahead of the queue.
+	tasks[4].Priority = 20
+	if err := database.UpdateTask(tasks[4]); err != nil {
+		t.Fatal(err)
+	}
+	if next, _ := database.GetNextQueuedTask(); next == nil || next.Title != "low-b" {
+		t.Errorf("expected the bumped task to run next, got %+v", next)
+	}
+}
@@ -0,0 +1,102 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// TaskTemplate is a saved starting point for new tasks. Title and Body may
+// contain {{name}} placeholders that are filled in when a task is created
+// from the template; empty fields fall back to the usual defaults.
+type TaskTemplate struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	Type      string    `json:"type,omitempty"`
+	Executor  string    `json:"executor,omitempty"`
+	Tags      string    `json:"tags,omitempty"`
+	Project   string    `json:"project,omitempty"`
+	CreatedAt LocalTime `json:"created_at"`
+	UpdatedAt LocalTime `json:"updated_at"`
+}
+
+// CreateTaskTemplate saves a new template. Names are unique.
+func (db *DB) CreateTaskTemplate(t *TaskTemplate) error {
+	result, err := db.Exec(`
+		INSERT INTO task_templates (name, title, body, type, executor, tags, project)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, t.Name, t.Title, t.Body, t.Type, t.Executor, t.Tags, t.Project)
+	if err != nil {
+		return fmt.Errorf("insert task template: %w", err)
+	}
+	t.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// UpdateTaskTemplate overwrites the template with t's name.
+func (db *DB) UpdateTaskTemplate(t *TaskTemplate) error {
+	_, err := db.Exec(`
+		UPDATE task_templates
+		SET title = ?, body = ?, type = ?, executor = ?, tags = ?, project = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE name = ?
+	`, t.Title, t.Body, t.Type, t.Executor, t.Tags, t.Project, t.Name)
+	if err != nil {
+		return fmt.Errorf("update task template: %w", err)
+	}
+	return nil
+}
+
+// DeleteTaskTemplate removes a template by name.
+func (db *DB) DeleteTaskTemplate(name string) error {
+	result, err := db.Exec(`DELETE FROM task_templates WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("delete task template: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("template %q not found", name)
+	}
+	return nil
+}
+
+// GetTaskTemplate retrieves a template by name, or nil if there is none.
+func (db *DB) GetTaskTemplate(name string) (*TaskTemplate, error) {
+	t := &TaskTemplate{}
+	err := db.QueryRow(`
+		SELECT id, name, COALESCE(title, ''), COALESCE(body, ''), COALESCE(type, ''),
+		       COALESCE(executor, ''), COALESCE(tags, ''), COALESCE(project, ''),
+		       created_at, updated_at
+		FROM task_templates WHERE name = ?
+	`, name).Scan(&t.ID, &t.Name, &t.Title, &t.Body, &t.Type, &t.Executor, &t.Tags, &t.Project, &t.CreatedAt, &t.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query task template: %w", err)
+	}
+	return t, nil
+}
+
+// ListTaskTemplates returns all templates ordered by name.
+func (db *DB) ListTaskTemplates() ([]*TaskTemplate, error) {
+	rows, err := db.Query(`
+		SELECT id, name, COALESCE(title, ''), COALESCE(body, ''), COALESCE(type, ''),
+		       COALESCE(executor, ''), COALESCE(tags, ''), COALESCE(project, ''),
+		       created_at, updated_at
+		FROM task_templates ORDER BY name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query task templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*TaskTemplate
+	for rows.Next() {
+		t := &TaskTemplate{}
+		if err := rows.Scan(&t.ID, &t.Name, &t.Title, &t.Body, &t.Type, &t.Executor, &t.Tags, &t.Project, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan task template: %w", err)
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
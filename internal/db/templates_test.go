@@ -0,0 +1,46 @@
+package db
+
+import "testing"
+
+func TestTaskTemplateCRUD(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	tmpl := &TaskTemplate{Name: "qa-pr", Title: "QA: PR #{{pr}}", Body: "Check {{pr}}", Type: TypeCode, Tags: "qa"}
+	if err := database.CreateTaskTemplate(tmpl); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.CreateTaskTemplate(&TaskTemplate{Name: "qa-pr"}); err == nil {
+		t.Error("expected duplicate template names to be rejected")
+	}
+
+	got, err := database.GetTaskTemplate("qa-pr")
+	if err != nil || got == nil {
+		t.Fatalf("GetTaskTemplate: %v", err)
+	}
+	if got.Title != tmpl.Title || got.Body != tmpl.Body || got.Tags != "qa" || got.Type != TypeCode {
+		t.Errorf("unexpected template %+v", got)
+	}
+
+	got.Title = "QA: {{pr}}"
+	if err := database.UpdateTaskTemplate(got); err != nil {
+		t.Fatal(err)
+	}
+	templates, err := database.ListTaskTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 1 || templates[0].Title != "QA: {{pr}}" {
+		t.Errorf("unexpected templates %+v", templates)
+	}
+
+	if err := database.DeleteTaskTemplate("qa-pr"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := database.GetTaskTemplate("qa-pr"); got != nil {
+		t.Error("expected the template to be deleted")
+	}
+	if err := database.DeleteTaskTemplate("qa-pr"); err == nil {
+		t.Error("expected an error deleting a missing template")
+	}
+}
@@ -0,0 +1,294 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Time entry sources. Manual entries come from 'ty track start/stop'; auto
+// entries cover the time a task spends processing and are opened and closed
+// by the status change itself.
+const (
+	TimeSourceManual = "manual"
+	TimeSourceAuto   = "auto"
+)
+
+// TimeEntry is one tracked span of work on a task. EndedAt is nil while the
+// entry is open.
+type TimeEntry struct {
+	ID        int64      `json:"id"`
+	TaskID    int64      `json:"task_id"`
+	Source    string     `json:"source"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+}
+
+// Duration returns the entry's length, up to now for an open entry.
+func (e *TimeEntry) Duration(now time.Time) time.Duration {
+	end := now
+	if e.EndedAt != nil {
+		end = *e.EndedAt
+	}
+	if end.Before(e.StartedAt) {
+		return 0
+	}
+	return end.Sub(e.StartedAt)
+}
+
+// StartTimeEntry opens a time entry for a task. A task has at most one open
+// entry, so one already open is stopped first and returned as stopped.
+func (db *DB) StartTimeEntry(taskID int64, source string) (started, stopped *TimeEntry, err error) {
+	stopped, err = db.StopTimeEntry(taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	res, err := db.Exec(`INSERT INTO time_entries (task_id, source, started_at) VALUES (?, ?, ?)`,
+		taskID, source, now.UTC().Format(usageTimeFormat))
+	if err != nil {
+		return nil, nil, fmt.Errorf("insert time entry: %w", err)
+	}
+	id, _ := res.LastInsertId()
+	return &TimeEntry{ID: id, TaskID: taskID, Source: source, StartedAt: now.Truncate(time.Second)}, stopped, nil
+}
+
+// StopTimeEntry closes the task's open time entry and returns it, or returns
+// nil when none is open.
+func (db *DB) StopTimeEntry(taskID int64) (*TimeEntry, error) {
+	entry, err := db.OpenTimeEntry(taskID)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	now := time.Now()
+	if _, err := db.Exec(`UPDATE time_entries SET ended_at = ? WHERE id = ?`, now.UTC().Format(usageTimeFormat), entry.ID); err != nil {
+		return nil, fmt.Errorf("stop time entry: %w", err)
+	}
+	ended := now.Truncate(time.Second)
+	entry.EndedAt = &ended
+	return entry, nil
+}
+
+// OpenTimeEntry returns the task's open time entry, or nil.
+func (db *DB) OpenTimeEntry(taskID int64) (*TimeEntry, error) {
+	entries, err := db.queryTimeEntries(`SELECT id, task_id, source, started_at, ended_at FROM time_entries
+		WHERE task_id = ? AND ended_at IS NULL ORDER BY id DESC LIMIT 1`, taskID)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return entries[0], nil
+}
+
+// TaskTimeEntries returns a task's time entries, oldest first.
+func (db *DB) TaskTimeEntries(taskID int64) ([]*TimeEntry, error) {
+	return db.queryTimeEntries(`SELECT id, task_id, source, started_at, ended_at FROM time_entries
+		WHERE task_id = ? ORDER BY started_at, id`, taskID)
+}
+
+// startAutoTimeEntry opens an auto entry when a task starts processing,
+// unless an entry (e.g. a manual one) is already open.
+func (db *DB) startAutoTimeEntry(taskID int64) {
+	if open, err := db.OpenTimeEntry(taskID); err != nil || open != nil {
+		return
+	}
+	db.StartTimeEntry(taskID, TimeSourceAuto)
+}
+
+// stopAutoTimeEntry closes the task's open auto entry when it leaves
+// processing. A manual entry stays open until 'ty track stop'.
+func (db *DB) stopAutoTimeEntry(taskID int64) {
+	if open, err := db.OpenTimeEntry(taskID); err == nil && open != nil && open.Source == TimeSourceAuto {
+		db.StopTimeEntry(taskID)
+	}
+}
+
+// setTimes fills StartedAt and EndedAt from scanned columns; a NULL ended_at
+// scans as the zero time.
+func (e *TimeEntry) setTimes(started, ended LocalTime) {
+	e.StartedAt = started.Time
+	if !ended.IsZero() {
+		t := ended.Time
+		e.EndedAt = &t
+	}
+}
+
+func (db *DB) queryTimeEntries(query string, args ...interface{}) ([]*TimeEntry, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query time entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*TimeEntry
+	for rows.Next() {
+		var e TimeEntry
+		var started, ended LocalTime
+		if err := rows.Scan(&e.ID, &e.TaskID, &e.Source, &started, &ended); err != nil {
+			return nil, fmt.Errorf("scan time entry: %w", err)
+		}
+		e.setTimes(started, ended)
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
+
+// TrackedTime is a task's tracked time, split by source.
+type TrackedTime struct {
+	Manual time.Duration
+	Auto   time.Duration
+	Open   bool // an entry is still running
+}
+
+// Total returns the manual and auto time together.
+func (t TrackedTime) Total() time.Duration {
+	return t.Manual + t.Auto
+}
+
+// TaskTrackedTime sums a task's time entries, counting an open one up to now.
+func (db *DB) TaskTrackedTime(taskID int64) (TrackedTime, error) {
+	entries, err := db.TaskTimeEntries(taskID)
+	if err != nil {
+		return TrackedTime{}, err
+	}
+	var t TrackedTime
+	now := time.Now()
+	for _, e := range entries {
+		if e.Source == TimeSourceAuto {
+			t.Auto += e.Duration(now)
+		} else {
+			t.Manual += e.Duration(now)
+		}
+		if e.EndedAt == nil {
+			t.Open = true
+		}
+	}
+	return t, nil
+}
+
+// TrackedTotal is tracked time summed over one group (a project or a day).
+type TrackedTotal struct {
+	Key           string `json:"key"`
+	Tasks         int    `json:"tasks"`
+	ManualSeconds int64  `json:"manual_seconds"`
+	AutoSeconds   int64  `json:"auto_seconds"`
+}
+
+// TrackedTimeReport sums tracked time since the given time by project (most
+// time first) and by local calendar day (newest first). Entries are clipped
+// to the window and split at midnight, so each day gets only its share; open
+// entries count up to now. A zero since includes everything; a non-empty
+// project limits it to that project.
+func (db *DB) TrackedTimeReport(project string, since time.Time) (byProject, byDay []TrackedTotal, err error) {
+	query := `SELECT e.id, e.task_id, e.source, e.started_at, e.ended_at, COALESCE(t.project, '')
+		FROM time_entries e LEFT JOIN tasks t ON t.id = e.task_id
+		WHERE 1=1`
+	var args []interface{}
+	if !since.IsZero() {
+		query += ` AND (e.ended_at IS NULL OR e.ended_at >= ?)`
+		args = append(args, since.UTC().Format(usageTimeFormat))
+	}
+	if project != "" {
+		if p, err := db.GetProjectByName(project); err == nil && p != nil {
+			project = p.Name
+		}
+		query += ` AND t.project = ?`
+		args = append(args, project)
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query time entries: %w", err)
+	}
+	type projectEntry struct {
+		TimeEntry
+		project string
+	}
+	var entries []projectEntry
+	for rows.Next() {
+		var e projectEntry
+		var started, ended LocalTime
+		if err := rows.Scan(&e.ID, &e.TaskID, &e.Source, &started, &ended, &e.project); err != nil {
+			rows.Close()
+			return nil, nil, fmt.Errorf("scan time entry: %w", err)
+		}
+		e.setTimes(started, ended)
+		entries = append(entries, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	projects := trackedGroups{}
+	days := trackedGroups{}
+	for _, e := range entries {
+		start, end := e.StartedAt, now
+		if e.EndedAt != nil {
+			end = *e.EndedAt
+		}
+		if !since.IsZero() && start.Before(since) {
+			start = since
+		}
+		if !end.After(start) {
+			continue
+		}
+		projects.add(e.project, e.TaskID, e.Source, end.Sub(start))
+		for start.Before(end) {
+			y, m, d := start.Date()
+			midnight := time.Date(y, m, d+1, 0, 0, 0, 0, start.Location())
+			chunk := end
+			if midnight.Before(end) {
+				chunk = midnight
+			}
+			days.add(start.Format("2006-01-02"), e.TaskID, e.Source, chunk.Sub(start))
+			start = chunk
+		}
+	}
+
+	byProject = projects.totals()
+	sort.SliceStable(byProject, func(i, j int) bool {
+		a, b := byProject[i], byProject[j]
+		if a.ManualSeconds+a.AutoSeconds != b.ManualSeconds+b.AutoSeconds {
+			return a.ManualSeconds+a.AutoSeconds > b.ManualSeconds+b.AutoSeconds
+		}
+		return a.Key < b.Key
+	})
+	byDay = days.totals()
+	sort.SliceStable(byDay, func(i, j int) bool { return byDay[i].Key > byDay[j].Key })
+	return byProject, byDay, nil
+}
+
+// trackedGroups accumulates tracked time per key, counting distinct tasks.
+type trackedGroups map[string]*trackedGroup
+
+type trackedGroup struct {
+	tasks        map[int64]bool
+	manual, auto time.Duration
+}
+
+func (g trackedGroups) add(key string, taskID int64, source string, d time.Duration) {
+	group := g[key]
+	if group == nil {
+		group = &trackedGroup{tasks: map[int64]bool{}}
+		g[key] = group
+	}
+	group.tasks[taskID] = true
+	if source == TimeSourceAuto {
+		group.auto += d
+	} else {
+		group.manual += d
+	}
+}
+
+func (g trackedGroups) totals() []TrackedTotal {
+	out := make([]TrackedTotal, 0, len(g))
+	for key, group := range g {
+		out = append(out, TrackedTotal{
+			Key:           key,
+			Tasks:         len(group.tasks),
+			ManualSeconds: int64(group.manual.Seconds()),
+			AutoSeconds:   int64(group.auto.Seconds()),
+		})
+	}
+	return out
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeEntriesStartStop(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	task := &Task{Title: "Track me", Status: StatusBacklog, Type: TypeCode}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, stopped, err := database.StartTimeEntry(task.ID, TimeSourceManual); err != nil || stopped != nil {
+		t.Fatalf("first start: stopped=%v err=%v", stopped, err)
+	}
+	// Starting again stops the open entry instead of overlapping it.
+	_, stopped, err := database.StartTimeEntry(task.ID, TimeSourceManual)
+	if err != nil || stopped == nil || stopped.EndedAt == nil {
+		t.Fatalf("second start: stopped=%+v err=%v", stopped, err)
+	}
+	entries, err := database.TaskTimeEntries(task.ID)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("entries = %+v, %v", entries, err)
+	}
+	open := 0
+	for _, e := range entries {
+		if e.EndedAt == nil {
+			open++
+		}
+	}
+	if open != 1 {
+		t.Errorf("%d open entries, want 1", open)
+	}
+
+	if e, err := database.StopTimeEntry(task.ID); err != nil || e == nil {
+		t.Fatalf("stop: %+v, %v", e, err)
+	}
+	if e, err := database.StopTimeEntry(task.ID); err != nil || e != nil {
+		t.Errorf("stop with nothing open = %+v, %v; want nil", e, err)
+	}
+	tracked, err := database.TaskTrackedTime(task.ID)
+	if err != nil || tracked.Open || tracked.Auto != 0 {
+		t.Errorf("tracked = %+v, %v", tracked, err)
+	}
+}
+
+func TestTimeEntriesAutoTrackProcessing(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	task := &Task{Title: "Run me", Status: StatusQueued, Type: TypeCode}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.UpdateTaskStatus(task.ID, StatusProcessing); err != nil {
+		t.Fatal(err)
+	}
+	open, err := database.OpenTimeEntry(task.ID)
+	if err != nil || open == nil || open.Source != TimeSourceAuto {
+		t.Fatalf("processing opened %+v, %v; want an auto entry", open, err)
+	}
+	if err := database.UpdateTaskStatus(task.ID, StatusBlocked); err != nil {
+		t.Fatal(err)
+	}
+	if open, _ := database.OpenTimeEntry(task.ID); open != nil {
+		t.Errorf("leaving processing left %+v open", open)
+	}
+
+	// A manual entry running when the task starts processing keeps running.
+	if _, _, err := database.StartTimeEntry(task.ID, TimeSourceManual); err != nil {
+		t.Fatal(err)
+	}
+	database.UpdateTaskStatus(task.ID, StatusProcessing)
+	database.UpdateTaskStatus(task.ID, StatusDone)
+	if open, _ := database.OpenTimeEntry(task.ID); open == nil || open.Source != TimeSourceManual {
+		t.Errorf("open entry = %+v, want the manual one", open)
+	}
+}
+
+func TestTrackedTimeReport(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	if err := database.CreateProject(&Project{Name: "app", Path: t.TempDir()}); err != nil {
+		t.Fatal(err)
+	}
+	a := &Task{Title: "A", Status: StatusBacklog, Type: TypeCode, Project: "app"}
+	b := &Task{Title: "B", Status: StatusBacklog, Type: TypeCode}
+	for _, task := range []*Task{a, b} {
+		if err := database.CreateTask(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A two-hour entry straddling local midnight, and an old one outside the window.
+	midnight := time.Date(2026, 3, 10, 0, 0, 0, 0, time.Local)
+	insert := func(taskID int64, source string, start, end time.Time) {
+		t.Helper()
+		if _, err := database.Exec(`INSERT INTO time_entries (task_id, source, started_at, ended_at) VALUES (?, ?, ?, ?)`,
+			taskID, source, start.UTC().Format(usageTimeFormat), end.UTC().Format(usageTimeFormat)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	insert(a.ID, TimeSourceManual, midnight.Add(-time.Hour), midnight.Add(time.Hour))
+	insert(a.ID, TimeSourceAuto, midnight.Add(2*time.Hour), midnight.Add(3*time.Hour))
+	insert(b.ID, TimeSourceManual, midnight.Add(-72*time.Hour), midnight.Add(-71*time.Hour))
+
+	byProject, byDay, err := database.TrackedTimeReport("", midnight.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byProject) != 1 || byProject[0].Key != "app" || byProject[0].ManualSeconds != 7200 || byProject[0].AutoSeconds != 3600 || byProject[0].Tasks != 1 {
+		t.Errorf("byProject = %+v", byProject)
+	}
+	if len(byDay) != 2 || byDay[0].Key != "2026-03-10" || byDay[0].ManualSeconds != 3600 || byDay[0].AutoSeconds != 3600 ||
+		byDay[1].Key != "2026-03-09" || byDay[1].ManualSeconds != 3600 {
+		t.Errorf("byDay = %+v", byDay)
+	}
+
+	all, _, err := database.TrackedTimeReport("", time.Time{})
+	if err != nil || len(all) != 2 {
+		t.Errorf("unwindowed byProject = %+v, %v", all, err)
+	}
+}
@@ -0,0 +1,384 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Undoable operations. Each snapshots the task (and its logs) into the undo
+// log before it runs, so 'ty undo' can bring the task back.
+const (
+	UndoDelete     = "delete"      // trashed; the row is kept until the trash sweep
+	UndoHardDelete = "hard_delete" // row and worktree removed
+	UndoMove       = "move"        // recreated in another project under a new ID
+	UndoArchive    = "archive"     // worktree saved to a git ref and removed
+)
+
+// SettingUndoRetention is how many undo entries are kept (default
+// DefaultUndoRetention). "0" turns the undo log off.
+const SettingUndoRetention = "undo_retention"
+
+// DefaultUndoRetention is the number of undo entries kept when
+// SettingUndoRetention is unset.
+const DefaultUndoRetention = 50
+
+// UndoEntry is one undoable operation. Snapshot is the task as it was just
+// before the operation.
+type UndoEntry struct {
+	ID int64 `json:"id"`
+	// BatchID groups the entries of one bulk operation (the ID of its first
+	// entry), so 'ty undo' reverts them together. 0 = a single operation.
+	BatchID     int64      `json:"batch_id,omitempty"`
+	Operation   string     `json:"operation"`
+	TaskID      int64      `json:"task_id"`
+	NewTaskID   int64      `json:"new_task_id,omitempty"`
+	Title       string     `json:"title"`
+	Project     string     `json:"project,omitempty"`
+	HadWorktree bool       `json:"had_worktree"`
+	CreatedAt   LocalTime  `json:"created_at"`
+	Snapshot    ExportTask `json:"-"`
+}
+
+// MetadataOnly reports whether undoing the entry can only bring back the task
+// row and its logs: hard deletes and moves remove the worktree outright.
+func (e *UndoEntry) MetadataOnly() bool {
+	return e.HadWorktree && (e.Operation == UndoHardDelete || e.Operation == UndoMove)
+}
+
+// UndoResult reports what an undo restored.
+type UndoResult struct {
+	TaskID       int64 // the restored task; its original ID unless that was taken
+	MetadataOnly bool  // the worktree is gone, only the row and logs came back
+}
+
+// undoRetention returns the configured number of undo entries to keep.
+func (db *DB) undoRetention() int {
+	val, err := db.GetSetting(SettingUndoRetention)
+	if err != nil || strings.TrimSpace(val) == "" {
+		return DefaultUndoRetention
+	}
+	if val == "disabled" {
+		return 0
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(val))
+	if err != nil || n < 0 {
+		return DefaultUndoRetention
+	}
+	return n
+}
+
+// pendingUndo holds undo snapshots taken ahead of an operation, to be
+// written in the same transaction as the operation itself so a failed
+// operation leaves no entry behind.
+type pendingUndo struct {
+	keep    int // retention limit; 0 means the undo log is off
+	entries []pendingUndoEntry
+}
+
+type pendingUndoEntry struct {
+	op       string
+	task     *Task
+	snapshot string
+}
+
+// prepareUndo snapshots tasks and their logs for op. Nothing is written
+// until the result's write is called.
+func (db *DB) prepareUndo(op string, tasks ...*Task) (*pendingUndo, error) {
+	u := &pendingUndo{keep: db.undoRetention()}
+	if u.keep == 0 {
+		return u, nil
+	}
+	for _, task := range tasks {
+		snap := exportTask(task)
+		var err error
+		if snap.Logs, err = db.exportTaskLogs(task.ID); err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(snap)
+		if err != nil {
+			return nil, fmt.Errorf("encode undo snapshot: %w", err)
+		}
+		u.entries = append(u.entries, pendingUndoEntry{op: op, task: task, snapshot: string(data)})
+	}
+	return u, nil
+}
+
+// write inserts the snapshots through tx, as one batch when there are
+// several, and prunes entries beyond the retention limit. It returns the new
+// entries' IDs.
+func (u *pendingUndo) write(tx *sql.Tx) ([]int64, error) {
+	var ids []int64
+	for _, e := range u.entries {
+		res, err := tx.Exec(`
+			INSERT INTO undo_log (operation, task_id, title, project, had_worktree, snapshot)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, e.op, e.task.ID, e.task.Title, e.task.Project, boolToInt(e.task.WorktreePath != ""), e.snapshot)
+		if err != nil {
+			return nil, fmt.Errorf("record undo: %w", err)
+		}
+		id, _ := res.LastInsertId()
+		ids = append(ids, id)
+	}
+	if len(ids) > 1 {
+		if _, err := tx.Exec(`UPDATE undo_log SET batch_id = ? WHERE id >= ? AND id <= ?`, ids[0], ids[0], ids[len(ids)-1]); err != nil {
+			return nil, fmt.Errorf("record undo: %w", err)
+		}
+	}
+	if _, err := tx.Exec(`
+		DELETE FROM undo_log WHERE id NOT IN (SELECT id FROM undo_log ORDER BY id DESC LIMIT ?)
+	`, u.keep); err != nil {
+		return nil, fmt.Errorf("prune undo log: %w", err)
+	}
+	return ids, nil
+}
+
+// RecordUndo snapshots a task and its logs into the undo log ahead of op and
+// returns the entry's ID, or 0 when the undo log is turned off. Entries beyond
+// the retention limit are pruned, oldest first. An operation that fails after
+// this should drop the entry again with DeleteUndo or RevertUndo.
+func (db *DB) RecordUndo(op string, taskID int64) (int64, error) {
+	task, err := db.GetTask(taskID)
+	if err != nil {
+		return 0, err
+	}
+	if task == nil {
+		return 0, fmt.Errorf("task #%d not found", taskID)
+	}
+	u, err := db.prepareUndo(op, task)
+	if err != nil {
+		return 0, err
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin undo: %w", err)
+	}
+	defer tx.Rollback()
+	ids, err := u.write(tx)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit undo: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return ids[0], nil
+}
+
+// SetUndoNewTaskID records the task a move created, so undoing the move can
+// remove it again. A zero entryID (undo log off) is ignored. If the entry
+// can't be updated it is dropped, since undoing it would then leave the new
+// task next to the restored one.
+func (db *DB) SetUndoNewTaskID(entryID, newTaskID int64) error {
+	if entryID == 0 {
+		return nil
+	}
+	if _, err := db.Exec(`UPDATE undo_log SET new_task_id = ? WHERE id = ?`, newTaskID, entryID); err != nil {
+		db.DeleteUndo(entryID)
+		return fmt.Errorf("update undo entry: %w", err)
+	}
+	return nil
+}
+
+// RevertUndo undoes entry entryID straight away, for an operation that failed
+// after removing the task: the task comes back from its snapshot and the
+// entry is removed. A zero entryID (undo log off) is ignored.
+func (db *DB) RevertUndo(entryID int64) error {
+	if entryID == 0 {
+		return nil
+	}
+	entries, err := db.listUndo(`id = ?`, 0, entryID)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("undo entry %d not found", entryID)
+	}
+	_, err = db.Undo(entries[0])
+	return err
+}
+
+// ListUndo returns undo entries, most recent first. A limit <= 0 returns all.
+func (db *DB) ListUndo(limit int) ([]*UndoEntry, error) {
+	return db.listUndo("", limit)
+}
+
+// listUndo returns the undo entries matching where (a SQL condition on
+// undo_log, "" for all, with its args), most recent first. A limit <= 0
+// returns all.
+func (db *DB) listUndo(where string, limit int, args ...interface{}) ([]*UndoEntry, error) {
+	query := `SELECT id, batch_id, operation, task_id, new_task_id, title, project, had_worktree, snapshot, created_at
+		FROM undo_log`
+	if where != "" {
+		query += ` WHERE ` + where
+	}
+	query += ` ORDER BY id DESC`
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query undo log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*UndoEntry
+	for rows.Next() {
+		var e UndoEntry
+		var snapshot string
+		if err := rows.Scan(&e.ID, &e.BatchID, &e.Operation, &e.TaskID, &e.NewTaskID, &e.Title, &e.Project,
+			&e.HadWorktree, &snapshot, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan undo entry: %w", err)
+		}
+		if err := json.Unmarshal([]byte(snapshot), &e.Snapshot); err != nil {
+			return nil, fmt.Errorf("decode undo entry %d: %w", e.ID, err)
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
+
+// LatestUndo returns the most recent undo entry, or nil if there is none.
+func (db *DB) LatestUndo() (*UndoEntry, error) {
+	entries, err := db.ListUndo(1)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return entries[0], nil
+}
+
+// LatestUndoBatch returns the most recent operation's undo entries, most
+// recent first: every entry of a bulk operation, otherwise just the latest
+// entry. It returns nil if the log is empty.
+func (db *DB) LatestUndoBatch() ([]*UndoEntry, error) {
+	latest, err := db.LatestUndo()
+	if err != nil || latest == nil {
+		return nil, err
+	}
+	if latest.BatchID == 0 {
+		return []*UndoEntry{latest}, nil
+	}
+	return db.listUndo(`batch_id = ?`, 0, latest.BatchID)
+}
+
+// DeleteUndo removes an entry from the undo log.
+func (db *DB) DeleteUndo(id int64) error {
+	if _, err := db.Exec(`DELETE FROM undo_log WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("delete undo entry: %w", err)
+	}
+	return nil
+}
+
+// Undo reverts an entry's operation in the database and removes the entry.
+//
+//   - delete: the trashed task is restored in place; if the trash sweep has
+//     since removed it, it is recreated from the snapshot.
+//   - hard_delete, move: the task is recreated from the snapshot (a move's new
+//     task is deleted first), keeping its original ID when that is free.
+//   - archive: the task returns to the status it had. Restoring its worktree
+//     from the archive ref is the caller's job, since it needs the executor.
+//
+// An entry whose operation was already reverted some other way (the task was
+// restored or unarchived by hand) is dropped with an error saying so.
+func (db *DB) Undo(e *UndoEntry) (*UndoResult, error) {
+	var res *UndoResult
+	var err error
+	switch e.Operation {
+	case UndoDelete:
+		res, err = db.undoDelete(e)
+	case UndoHardDelete:
+		res, err = db.restoreUndoSnapshot(e)
+	case UndoMove:
+		if e.NewTaskID != 0 {
+			if moved, _ := db.GetTask(e.NewTaskID); moved != nil {
+				if err := db.DeleteTask(e.NewTaskID); err != nil {
+					return nil, err
+				}
+			}
+		}
+		res, err = db.restoreUndoSnapshot(e)
+	case UndoArchive:
+		res, err = db.undoArchive(e)
+	default:
+		err = fmt.Errorf("unknown undo operation %q", e.Operation)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, db.DeleteUndo(e.ID)
+}
+
+func (db *DB) undoDelete(e *UndoEntry) (*UndoResult, error) {
+	var trashed bool
+	err := db.QueryRow(`SELECT deleted_at IS NOT NULL FROM tasks WHERE id = ?`, e.TaskID).Scan(&trashed)
+	if err == sql.ErrNoRows {
+		return db.restoreUndoSnapshot(e)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("check task #%d: %w", e.TaskID, err)
+	}
+	if !trashed {
+		db.DeleteUndo(e.ID)
+		return nil, fmt.Errorf("task #%d is no longer trashed; dropped it from the undo log", e.TaskID)
+	}
+	if err := db.RestoreTask(e.TaskID); err != nil {
+		return nil, err
+	}
+	return &UndoResult{TaskID: e.TaskID}, nil
+}
+
+func (db *DB) undoArchive(e *UndoEntry) (*UndoResult, error) {
+	task, err := db.GetTask(e.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil || task.Status != StatusArchived {
+		db.DeleteUndo(e.ID)
+		return nil, fmt.Errorf("task #%d is no longer archived; dropped it from the undo log", e.TaskID)
+	}
+	if _, err := db.UnarchiveTask(e.TaskID); err != nil {
+		return nil, err
+	}
+	return &UndoResult{TaskID: e.TaskID, MetadataOnly: e.HadWorktree && !task.HasArchiveState()}, nil
+}
+
+// restoreUndoSnapshot recreates the entry's task and logs from its snapshot,
+// under the original ID unless another task has taken it since.
+func (db *DB) restoreUndoSnapshot(e *UndoEntry) (*UndoResult, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin undo: %w", err)
+	}
+	defer tx.Rollback()
+
+	snap := e.Snapshot
+	var taken int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM tasks WHERE id = ?`, snap.ID).Scan(&taken); err != nil {
+		return nil, fmt.Errorf("check task #%d: %w", snap.ID, err)
+	}
+	if taken > 0 {
+		// Past both the highest live ID and any AUTOINCREMENT has handed out.
+		if err := tx.QueryRow(`SELECT MAX(
+			COALESCE((SELECT MAX(id) FROM tasks), 0),
+			COALESCE((SELECT seq FROM sqlite_sequence WHERE name = 'tasks'), 0)) + 1`).Scan(&snap.ID); err != nil {
+			return nil, fmt.Errorf("allocate task ID: %w", err)
+		}
+	}
+	if err := importTasks(tx, []ExportTask{snap}, false, &ImportResult{}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit undo: %w", err)
+	}
+
+	if task, err := db.GetTask(snap.ID); err == nil && task != nil {
+		db.emitTaskCreated(task)
+	}
+	db.recordEvent(EventTaskRestored, snap.ID, fmt.Sprintf("undo %s of #%d", e.Operation, e.TaskID))
+	return &UndoResult{TaskID: snap.ID, MetadataOnly: e.HadWorktree}, nil
+}
@@ -0,0 +1,201 @@
+package db
+
+import (
+	"testing"
+)
+
+func TestUndoSoftDelete(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	id := mkTask(t, database, "fat-fingered")
+	if err := database.SoftDeleteTask(id); err != nil {
+		t.Fatal(err)
+	}
+	// Trashing a trashed task records nothing more.
+	if err := database.SoftDeleteTask(id); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := database.ListUndo(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Operation != UndoDelete || entries[0].TaskID != id {
+		t.Fatalf("undo log = %+v", entries)
+	}
+
+	res, err := database.Undo(entries[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TaskID != id || res.MetadataOnly {
+		t.Errorf("result = %+v", res)
+	}
+	if !listActiveIDs(t, database)[id] {
+		t.Error("task not back on the board")
+	}
+	if latest, _ := database.LatestUndo(); latest != nil {
+		t.Errorf("entry not removed after undo: %+v", latest)
+	}
+}
+
+func TestUndoHardDeleteRecreatesTaskAndLogs(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	task := &Task{Title: "gone", Body: "details", Status: StatusBlocked, Type: TypeCode, Project: "personal", Tags: "a,b"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	database.AppendTaskLog(task.ID, "output", "hello")
+	if _, err := database.Exec(`UPDATE tasks SET worktree_path = ? WHERE id = ?`, "/tmp/wt", task.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := database.RecordUndo(UndoHardDelete, task.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.DeleteTask(task.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := database.LatestUndo()
+	if err != nil || entry == nil {
+		t.Fatalf("LatestUndo = %v, %v", entry, err)
+	}
+	if !entry.MetadataOnly() {
+		t.Error("hard delete of a task with a worktree should be metadata-only")
+	}
+	res, err := database.Undo(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TaskID != task.ID || !res.MetadataOnly {
+		t.Errorf("result = %+v", res)
+	}
+	got, err := database.GetTask(task.ID)
+	if err != nil || got == nil {
+		t.Fatalf("restored task = %v, %v", got, err)
+	}
+	if got.Title != "gone" || got.Body != "details" || got.Status != StatusBlocked || got.Tags != "a,b" {
+		t.Errorf("restored task = %+v", got)
+	}
+	logs, _ := database.GetTaskLogs(task.ID, 0)
+	found := false
+	for _, l := range logs {
+		if l.Content == "hello" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("logs not restored: %+v", logs)
+	}
+}
+
+func TestUndoMoveUsesNewIDWhenOriginalTaken(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	oldID := mkTask(t, database, "moving")
+	undoID, err := database.RecordUndo(UndoMove, oldID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := database.DeleteTask(oldID); err != nil {
+		t.Fatal(err)
+	}
+	moved := &Task{Title: "moving", Project: "personal", Type: TypeCode}
+	if err := database.CreateTask(moved); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.SetUndoNewTaskID(undoID, moved.ID); err != nil {
+		t.Fatal(err)
+	}
+	// Something else takes the original ID (e.g. an import).
+	if _, err := database.Exec(`INSERT INTO tasks (id, title, status, type, project) VALUES (?, 'squatter', 'backlog', 'code', 'personal')`, oldID); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, _ := database.LatestUndo()
+	if entry == nil || entry.NewTaskID != moved.ID {
+		t.Fatalf("entry = %+v", entry)
+	}
+	res, err := database.Undo(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TaskID == oldID || res.TaskID == moved.ID {
+		t.Errorf("restored as #%d, want a fresh ID", res.TaskID)
+	}
+	if got, _ := database.GetTask(moved.ID); got != nil {
+		t.Error("moved copy not deleted")
+	}
+	if got, _ := database.GetTask(res.TaskID); got == nil || got.Title != "moving" {
+		t.Errorf("restored task = %+v", got)
+	}
+}
+
+func TestUndoArchive(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	task := &Task{Title: "shelved", Status: StatusQueued, Type: TypeCode, Project: "personal"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.ArchiveTask(task.ID); err != nil {
+		t.Fatal(err)
+	}
+	entry, _ := database.LatestUndo()
+	if entry == nil || entry.Operation != UndoArchive {
+		t.Fatalf("entry = %+v", entry)
+	}
+	if _, err := database.Undo(entry); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := database.GetTask(task.ID); got.Status != StatusQueued {
+		t.Errorf("status = %s, want %s", got.Status, StatusQueued)
+	}
+
+	// An archive already reverted by hand is dropped rather than retried.
+	database.ArchiveTask(task.ID)
+	database.UnarchiveTask(task.ID)
+	entry, _ = database.LatestUndo()
+	if _, err := database.Undo(entry); err == nil {
+		t.Error("undid an archive that was already reverted")
+	}
+	if latest, _ := database.LatestUndo(); latest != nil {
+		t.Errorf("stale entry kept: %+v", latest)
+	}
+}
+
+func TestUndoRetention(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	if err := database.SetSetting(SettingUndoRetention, "2"); err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for _, title := range []string{"a", "b", "c"} {
+		id := mkTask(t, database, title)
+		ids = append(ids, id)
+		if err := database.SoftDeleteTask(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, _ := database.ListUndo(0)
+	if len(entries) != 2 || entries[0].TaskID != ids[2] || entries[1].TaskID != ids[1] {
+		t.Errorf("undo log = %+v", entries)
+	}
+
+	if err := database.SetSetting(SettingUndoRetention, "0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.SoftDeleteTask(mkTask(t, database, "d")); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := database.ListUndo(0); len(entries) != 0 {
+		t.Errorf("undo log not off: %+v", entries)
+	}
+}
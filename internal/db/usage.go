@@ -0,0 +1,134 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// usageTimeFormat matches CURRENT_TIMESTAMP so stored times compare as text.
+const usageTimeFormat = "2006-01-02 15:04:05"
+
+// UsageEntry is the token usage of one Claude API message.
+type UsageEntry struct {
+	MessageID    string
+	Model        string
+	InputTokens  int64
+	OutputTokens int64
+	CostUSD      float64
+	CreatedAt    time.Time
+}
+
+// RecordTaskUsage stores usage entries for a task and refreshes the task's
+// totals. Entries already recorded for the task (by message ID) are skipped,
+// so passing a whole transcript again only adds its new messages. It returns
+// the number of entries added.
+func (db *DB) RecordTaskUsage(taskID int64, entries []UsageEntry) (int, error) {
+	if len(entries) == 0 {
+		return 0, nil
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	added := 0
+	for _, e := range entries {
+		if e.MessageID == "" {
+			continue
+		}
+		createdAt := e.CreatedAt
+		if createdAt.IsZero() {
+			createdAt = time.Now()
+		}
+		res, err := tx.Exec(`
+			INSERT OR IGNORE INTO task_usage (task_id, message_id, model, input_tokens, output_tokens, cost_usd, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, taskID, e.MessageID, e.Model, e.InputTokens, e.OutputTokens, e.CostUSD, createdAt.UTC().Format(usageTimeFormat))
+		if err != nil {
+			return 0, fmt.Errorf("insert usage: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			added++
+		}
+	}
+	if added == 0 {
+		return 0, nil
+	}
+
+	_, err = tx.Exec(`
+		UPDATE tasks SET
+			input_tokens = (SELECT COALESCE(SUM(input_tokens), 0) FROM task_usage WHERE task_id = ?),
+			output_tokens = (SELECT COALESCE(SUM(output_tokens), 0) FROM task_usage WHERE task_id = ?),
+			cost_usd = (SELECT COALESCE(SUM(cost_usd), 0) FROM task_usage WHERE task_id = ?)
+		WHERE id = ?
+	`, taskID, taskID, taskID, taskID)
+	if err != nil {
+		return 0, fmt.Errorf("update task usage totals: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+	return added, nil
+}
+
+// UsageTotal is usage summed over one group (a project or a day).
+type UsageTotal struct {
+	Key          string  `json:"key"`
+	Tasks        int     `json:"tasks"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+}
+
+// UsageByProject sums usage recorded since the given time by project, most
+// expensive first. A zero since includes everything; a non-empty project
+// limits it to that project.
+func (db *DB) UsageByProject(project string, since time.Time) ([]UsageTotal, error) {
+	return db.usageTotals(`COALESCE(t.project, '')`, `cost DESC, key`, project, since)
+}
+
+// UsageByDay sums usage recorded since the given time by local calendar day,
+// newest first. A zero since includes everything; a non-empty project limits
+// it to that project.
+func (db *DB) UsageByDay(project string, since time.Time) ([]UsageTotal, error) {
+	return db.usageTotals(`date(u.created_at, 'localtime')`, `key DESC`, project, since)
+}
+
+func (db *DB) usageTotals(keyExpr, orderBy, project string, since time.Time) ([]UsageTotal, error) {
+	query := `
+		SELECT ` + keyExpr + ` AS key, COUNT(DISTINCT u.task_id),
+		       COALESCE(SUM(u.input_tokens), 0), COALESCE(SUM(u.output_tokens), 0),
+		       COALESCE(SUM(u.cost_usd), 0) AS cost
+		FROM task_usage u LEFT JOIN tasks t ON t.id = u.task_id
+		WHERE 1=1`
+	var args []interface{}
+	if !since.IsZero() {
+		query += ` AND u.created_at >= ?`
+		args = append(args, since.UTC().Format(usageTimeFormat))
+	}
+	if project != "" {
+		if p, err := db.GetProjectByName(project); err == nil && p != nil {
+			project = p.Name
+		}
+		query += ` AND t.project = ?`
+		args = append(args, project)
+	}
+	query += ` GROUP BY key ORDER BY ` + orderBy
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query usage: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []UsageTotal
+	for rows.Next() {
+		var u UsageTotal
+		if err := rows.Scan(&u.Key, &u.Tasks, &u.InputTokens, &u.OutputTokens, &u.CostUSD); err != nil {
+			return nil, fmt.Errorf("scan usage: %w", err)
+		}
+		totals = append(totals, u)
+	}
+	return totals, rows.Err()
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordTaskUsage(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	task := &Task{Title: "usage", Status: StatusProcessing, Project: "personal"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+
+	day1 := time.Date(2026, 1, 2, 12, 0, 0, 0, time.Local)
+	day2 := day1.AddDate(0, 0, 1)
+	entries := []UsageEntry{
+		{MessageID: "msg_1", Model: "claude-sonnet-4-5", InputTokens: 1000, OutputTokens: 100, CostUSD: 0.5, CreatedAt: day1},
+		{MessageID: "msg_2", Model: "claude-sonnet-4-5", InputTokens: 2000, OutputTokens: 200, CostUSD: 1.0, CreatedAt: day2},
+	}
+	if n, err := database.RecordTaskUsage(task.ID, entries); err != nil || n != 2 {
+		t.Fatalf("RecordTaskUsage = %d, %v; want 2", n, err)
+	}
+
+	// A resumed session replays earlier messages; they must not count twice.
+	resumed := append(entries, UsageEntry{MessageID: "msg_3", InputTokens: 10, OutputTokens: 1, CostUSD: 0.25, CreatedAt: day2})
+	if n, err := database.RecordTaskUsage(task.ID, resumed); err != nil || n != 1 {
+		t.Fatalf("RecordTaskUsage after resume = %d, %v; want 1", n, err)
+	}
+
+	got, err := database.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	if got.InputTokens != 3010 || got.OutputTokens != 301 || got.CostUSD != 1.75 {
+		t.Errorf("task totals = %d in / %d out / $%v", got.InputTokens, got.OutputTokens, got.CostUSD)
+	}
+
+	byProject, err := database.UsageByProject("", time.Time{})
+	if err != nil {
+		t.Fatalf("UsageByProject: %v", err)
+	}
+	if len(byProject) != 1 || byProject[0].Key != "personal" || byProject[0].Tasks != 1 || byProject[0].CostUSD != 1.75 {
+		t.Errorf("unexpected project totals: %+v", byProject)
+	}
+
+	byDay, err := database.UsageByDay("personal", day2.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("UsageByDay: %v", err)
+	}
+	if len(byDay) != 1 || byDay[0].Key != day2.Format("2006-01-02") || byDay[0].CostUSD != 1.25 {
+		t.Errorf("unexpected day totals: %+v", byDay)
+	}
+}
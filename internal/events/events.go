@@ -2,6 +2,7 @@
 package events
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -12,24 +13,7 @@ import (
 	"time"
 
 	"github.com/bborn/workflow/internal/db"
-)
-
-// Event types for task lifecycle
-const (
-	TaskCreated       = "task.created"
-	TaskUpdated       = "task.updated"
-	TaskDeleted       = "task.deleted"
-	TaskStarted       = "task.started"
-	TaskWorktreeReady = "task.worktree_ready"
-	TaskBlocked       = "task.blocked"       // Task needs input from user
-	TaskAuthRequired  = "task.auth_required" // Executor session needs re-authentication
-	TaskCompleted     = "task.completed"
-	TaskFailed        = "task.failed"
-
-	// RoutineFailed fires when a `ty run <routine>` execution fails (non-zero
-	// exit, env.sh failure, or timeout). Event.Task is nil; routine name, run
-	// ID, exit code, and log path arrive via Metadata.
-	RoutineFailed = "routine.failed"
+	"github.com/bborn/workflow/internal/hooks"
 )
 
 // Event represents a task lifecycle event.
@@ -118,6 +102,8 @@ func (e *Emitter) runHook(event Event) {
 
 	cmd := exec.CommandContext(ctx, hookPath)
 	cmd.Env = env
+	// The full event (task, metadata) as JSON on stdin; see hooks.Payload.
+	cmd.Stdin = bytes.NewReader(hooks.NewPayload(event.Type, event.TaskID, event.Task, event.Message, event.Metadata, event.Timestamp).JSON())
 	_ = cmd.Run() // Ignore errors - hooks are best-effort
 }
 
@@ -155,8 +141,14 @@ func (e *Emitter) EmitTaskWorktreeReady(task *db.Task) {
 	}})
 }
 
+// EmitTaskBlocked emits task.blocked. A non-empty reason — usually the question
+// the agent is waiting on — is also sent as the "question" metadata key.
 func (e *Emitter) EmitTaskBlocked(task *db.Task, reason string) {
-	e.Emit(Event{Type: TaskBlocked, TaskID: task.ID, Task: task, Message: reason})
+	event := Event{Type: TaskBlocked, TaskID: task.ID, Task: task, Message: reason}
+	if reason != "" {
+		event.Metadata = map[string]interface{}{"question": reason}
+	}
+	e.Emit(event)
 }
 
 func (e *Emitter) EmitTaskAuthRequired(task *db.Task, reason string) {
@@ -103,6 +103,31 @@ echo "$WORKTREE_PATH:$WORKTREE_BRANCH:$WORKTREE_PORT" > "` + markerFile + `"
 	}
 }
 
+func TestEmitterTaskBlockedPassesQuestion(t *testing.T) {
+	hooksDir := t.TempDir()
+	markerFile := filepath.Join(hooksDir, "blocked_marker")
+	hookScript := filepath.Join(hooksDir, TaskBlocked)
+
+	script := `#!/bin/sh
+echo "$TASK_METADATA" > "` + markerFile + `"
+`
+	if err := os.WriteFile(hookScript, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	emitter := New(hooksDir)
+	task := &db.Task{ID: 9, Title: "Migrate", Status: db.StatusBlocked}
+	emitter.EmitTaskBlocked(task, "Drop the old table?")
+
+	content, err := waitForFile(t, markerFile, 5*time.Second)
+	if err != nil {
+		t.Fatalf("hook didn't run: %v", err)
+	}
+	if string(content) != `{"question":"Drop the old table?"}`+"\n" {
+		t.Errorf("unexpected hook output: %q", content)
+	}
+}
+
 func TestEmitterNoHooksDir(t *testing.T) {
 	emitter := New("")
 	// Should not panic
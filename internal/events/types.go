@@ -0,0 +1,132 @@
+package events
+
+import (
+	"sort"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+// SchemaVersion is the version of the event shape integrators see: the
+// entries of the HTTP event stream, 'ty events watch' and 'ty events list
+// --json', and the types listed by 'ty events types'. Like the hook payload's
+// version it only changes when a field is removed or changes meaning; new
+// fields and new event types are added without a bump.
+const SchemaVersion = 1
+
+// Event types. This is the canonical list; Types describes each one. The
+// strings are defined in the db package, which sits below this one and writes
+// the event log, so the db and hooks packages emit the same constants.
+const (
+	TaskCreated       = db.EventTaskCreated
+	TaskUpdated       = db.EventTaskUpdated
+	TaskDeleted       = db.EventTaskDeleted
+	TaskStarted       = db.EventTaskStarted
+	TaskWorktreeReady = db.EventTaskWorktreeReady
+	TaskBlocked       = db.EventTaskBlocked      // Task needs input from user
+	TaskAuthRequired  = db.EventTaskAuthRequired // Executor session needs re-authentication
+	TaskCompleted     = db.EventTaskCompleted
+	TaskFailed        = db.EventTaskFailed
+	TaskMoved         = db.EventTaskMoved
+	TaskArchived      = db.EventTaskArchived
+	TaskUnarchived    = db.EventTaskUnarchived
+	TaskRestored      = db.EventTaskRestored
+
+	// TaskDone is the name legacy hook scripts get for TaskCompleted.
+	TaskDone = db.EventTaskDone
+
+	// RoutineFailed fires when a `ty run <routine>` execution fails (non-zero
+	// exit, env.sh failure, or timeout). Event.Task is nil; routine name, run
+	// ID, exit code, and log path arrive via Metadata.
+	RoutineFailed = db.EventRoutineFailed
+)
+
+// Where an event type shows up.
+const (
+	SourceLog  = "log"  // recorded in the event log: ty events list/watch and the HTTP stream
+	SourceHook = "hook" // runs hook scripts, with metadata in the stdin payload
+)
+
+// TypeInfo describes one event type for integrators.
+type TypeInfo struct {
+	Type        string          `json:"type"`
+	Description string          `json:"description"`
+	Sources     []string        `json:"sources"`
+	Metadata    []MetadataField `json:"metadata,omitempty"`
+}
+
+// MetadataField is one key of an event's metadata.
+type MetadataField struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+var types = []TypeInfo{
+	{Type: TaskCreated, Description: "A task was created.", Sources: []string{SourceLog, SourceHook}},
+	{Type: TaskUpdated, Description: "A task's fields changed (edits, pinning, a restore from the trash, PR status).",
+		Sources: []string{SourceLog, SourceHook},
+		Metadata: []MetadataField{
+			{Name: "<field>", Type: "any", Description: "Each changed field with its new value, e.g. pinned, restored"},
+		}},
+	{Type: TaskDeleted, Description: "A task was trashed or deleted; the message is its title.", Sources: []string{SourceLog, SourceHook}},
+	{Type: TaskStarted, Description: "A task started processing.", Sources: []string{SourceLog, SourceHook}},
+	{Type: TaskWorktreeReady, Description: "A task's worktree was created and is ready to use.",
+		Sources: []string{SourceHook},
+		Metadata: []MetadataField{
+			{Name: "worktree_path", Type: "string", Description: "Absolute path of the worktree"},
+			{Name: "branch_name", Type: "string", Description: "Branch checked out in the worktree"},
+			{Name: "port", Type: "int", Description: "Port allocated to the task"},
+		}},
+	{Type: TaskBlocked, Description: "A task needs input; the message is the reason.",
+		Sources: []string{SourceLog, SourceHook},
+		Metadata: []MetadataField{
+			{Name: "question", Type: "string", Description: "What the agent is waiting on, when known"},
+		}},
+	{Type: TaskAuthRequired, Description: "A task's executor session needs re-authentication.", Sources: []string{SourceHook}},
+	{Type: TaskCompleted, Description: "A task was marked done.", Sources: []string{SourceLog, SourceHook}},
+	{Type: TaskDone, Description: "Legacy hook name for task.completed.", Sources: []string{SourceHook}},
+	{Type: TaskFailed, Description: "A task's execution failed; the message is the reason.", Sources: []string{SourceHook}},
+	{Type: TaskMoved, Description: "A task was moved to another project; task_id is the new task.", Sources: []string{SourceLog}},
+	{Type: TaskArchived, Description: "A task was archived; the message names its previous status.", Sources: []string{SourceLog}},
+	{Type: TaskUnarchived, Description: "A task was unarchived; the message names the status it returned to.", Sources: []string{SourceLog}},
+	{Type: TaskRestored, Description: "A deleted or moved task was recreated by ty undo.", Sources: []string{SourceLog}},
+	{Type: RoutineFailed, Description: "A routine run failed; there is no task.",
+		Sources: []string{SourceHook},
+		Metadata: []MetadataField{
+			{Name: "routine", Type: "string", Description: "Routine name"},
+			{Name: "run_id", Type: "int", Description: "ID of the failed run"},
+			{Name: "exit_code", Type: "int", Description: "Exit code of the run"},
+			{Name: "log_path", Type: "string", Description: "Path of the run's log file"},
+		}},
+}
+
+// Types returns every event type ty emits, sorted by name.
+func Types() []TypeInfo {
+	out := append([]TypeInfo(nil), types...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Type < out[j].Type })
+	return out
+}
+
+// LookupType returns the description of an event type, or nil if ty never
+// emits it.
+func LookupType(name string) *TypeInfo {
+	for i := range types {
+		if types[i].Type == name {
+			info := types[i]
+			return &info
+		}
+	}
+	return nil
+}
+
+// LoggedEvent is an event log entry as integrators receive it, stamped with
+// SchemaVersion.
+type LoggedEvent struct {
+	SchemaVersion int `json:"schema_version"`
+	db.LoggedEvent
+}
+
+// Versioned stamps an event log entry with SchemaVersion.
+func Versioned(e db.LoggedEvent) LoggedEvent {
+	return LoggedEvent{SchemaVersion: SchemaVersion, LoggedEvent: e}
+}
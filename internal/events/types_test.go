@@ -0,0 +1,98 @@
+package events
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/hooks"
+)
+
+// TestTypesCoverEmittedEvents checks that every event type the db and hooks
+// packages emit is in the registry, and that internal/db only records events
+// through those constants.
+func TestTypesCoverEmittedEvents(t *testing.T) {
+	dbTypes := map[string]string{
+		"EventTaskCreated":       db.EventTaskCreated,
+		"EventTaskUpdated":       db.EventTaskUpdated,
+		"EventTaskDeleted":       db.EventTaskDeleted,
+		"EventTaskStarted":       db.EventTaskStarted,
+		"EventTaskWorktreeReady": db.EventTaskWorktreeReady,
+		"EventTaskBlocked":       db.EventTaskBlocked,
+		"EventTaskAuthRequired":  db.EventTaskAuthRequired,
+		"EventTaskCompleted":     db.EventTaskCompleted,
+		"EventTaskFailed":        db.EventTaskFailed,
+		"EventTaskMoved":         db.EventTaskMoved,
+		"EventTaskArchived":      db.EventTaskArchived,
+		"EventTaskUnarchived":    db.EventTaskUnarchived,
+		"EventTaskRestored":      db.EventTaskRestored,
+		"EventTaskDone":          db.EventTaskDone,
+		"EventRoutineFailed":     db.EventRoutineFailed,
+	}
+	emitted := []string{
+		hooks.EventTaskBlocked, hooks.EventTaskDone, hooks.EventTaskFailed,
+		hooks.EventTaskStarted, hooks.EventAuthRequired,
+	}
+	for _, v := range dbTypes {
+		emitted = append(emitted, v)
+	}
+	for _, name := range emitted {
+		if LookupType(name) == nil {
+			t.Errorf("event type %q is emitted but missing from Types()", name)
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join("..", "db", "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recordEvent := regexp.MustCompile(`\.recordEvent\(([^,]+),`)
+	calls := 0
+	for _, f := range files {
+		src, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range recordEvent.FindAllStringSubmatch(string(src), -1) {
+			calls++
+			if _, ok := dbTypes[m[1]]; !ok {
+				t.Errorf("%s: recordEvent(%s, ...) should pass one of the db.Event constants listed here", filepath.Base(f), m[1])
+			}
+		}
+	}
+	if calls == 0 {
+		t.Fatal("found no recordEvent calls in internal/db")
+	}
+}
+
+func TestTypesAreSortedAndDescribed(t *testing.T) {
+	types := Types()
+	for i, info := range types {
+		if info.Description == "" || len(info.Sources) == 0 {
+			t.Errorf("%s: missing description or sources", info.Type)
+		}
+		if i > 0 && types[i-1].Type >= info.Type {
+			t.Errorf("types not sorted: %s before %s", types[i-1].Type, info.Type)
+		}
+	}
+	if LookupType("task.nope") != nil {
+		t.Error("LookupType found an unknown type")
+	}
+}
+
+func TestVersionedEventJSON(t *testing.T) {
+	data, err := json.Marshal(Versioned(db.LoggedEvent{ID: 7, EventType: TaskBlocked, TaskID: 42}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["schema_version"] != float64(SchemaVersion) || got["event_type"] != TaskBlocked || got["id"] != float64(7) {
+		t.Errorf("versioned event = %s", data)
+	}
+}
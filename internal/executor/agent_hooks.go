@@ -0,0 +1,31 @@
+package executor
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// codexStatusHookEvents are the Codex lifecycle hooks that report task state
+// back through `ty agent-hook`. Codex has no Notification hook, so a Codex task
+// is marked blocked when its turn ends (Stop) and resumes on the next tool call
+// or prompt.
+var codexStatusHookEvents = []string{"SessionStart", "UserPromptSubmit", "PreToolUse", "PostToolUse", "Stop"}
+
+// setupCodexStatusHooks registers the Codex lifecycle hooks that give Codex
+// tasks the same processing/blocked tracking Claude gets from its hooks. Returns
+// a cleanup that restores the prior hooks.json.
+func (e *Executor) setupCodexStatusHooks(workDir, projectDir string) (func(), error) {
+	bin := resolveTaskBin()
+	hooks := make([]commandHook, 0, len(codexStatusHookEvents))
+	for _, event := range codexStatusHookEvents {
+		hooks = append(hooks, commandHook{
+			Event:   event,
+			Command: fmt.Sprintf("%q agent-hook --executor codex --event %s", bin, event),
+		})
+	}
+	cleanup, err := writeMergedCommandHooks(filepath.Join(workDir, ".codex", "hooks.json"), hooks)
+	if err == nil && projectDir != "" {
+		ensureGitExclude(projectDir, ".codex")
+	}
+	return cleanup, err
+}
@@ -78,7 +78,7 @@ func (e *Executor) checkAuthStuckTasks() {
 		e.logLine(task.ID, "error", reason)
 
 		// Move to blocked so it surfaces on the board and fires task.blocked.
-		if err := e.updateStatus(task.ID, db.StatusBlocked); err != nil {
+		if err := e.blockTask(task.ID, db.BlockedError); err != nil {
 			e.logger.Error("Failed to block auth-stuck task", "task", task.ID, "error", err)
 		}
 
@@ -0,0 +1,102 @@
+package executor
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bborn/workflow/internal/config"
+	"github.com/bborn/workflow/internal/db"
+)
+
+// Backoff between automatic retries of a failed task: the first retry waits
+// autoRetryBaseDelay and each later one doubles it, up to autoRetryMaxDelay.
+const (
+	autoRetryBaseDelay = 30 * time.Second
+	autoRetryMaxDelay  = 30 * time.Minute
+)
+
+// MaxRetries returns the configured max_retries: how many times a failed task
+// is re-queued automatically. 0 (the default, and any invalid value) disables
+// automatic retries.
+func MaxRetries(database *db.DB) int {
+	if val, err := database.GetSetting(config.SettingMaxRetries); err == nil && val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+// AutoRetryDelay returns how long to wait before the attempt-th automatic
+// retry (1-based).
+func AutoRetryDelay(attempt int) time.Duration {
+	delay := autoRetryBaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= autoRetryMaxDelay {
+			return autoRetryMaxDelay
+		}
+	}
+	return delay
+}
+
+// scheduleAutoRetry runs after a task has failed and been moved to blocked.
+// While the task is under max_retries it schedules the next attempt after an
+// exponential backoff; once the budget is spent it leaves the task blocked
+// for good and says so in the log.
+func (e *Executor) scheduleAutoRetry(task *db.Task, now time.Time) {
+	limit := MaxRetries(e.db)
+	if limit == 0 {
+		return
+	}
+	state, err := e.db.GetAutoRetryState(task.ID)
+	if err != nil {
+		e.logger.Warn("auto-retry: failed to read retry state", "task", task.ID, "error", err)
+		return
+	}
+	if state.Disabled {
+		return
+	}
+	if state.Count >= limit {
+		e.logLine(task.ID, "error", fmt.Sprintf("Task failed permanently: exhausted %d retries - use 'ty retry' to try again", limit))
+		return
+	}
+
+	attempt := state.Count + 1
+	delay := AutoRetryDelay(attempt)
+	if err := e.db.ScheduleAutoRetry(task.ID, attempt, now.Add(delay)); err != nil {
+		e.logger.Warn("auto-retry: failed to schedule retry", "task", task.ID, "error", err)
+		return
+	}
+	e.logLine(task.ID, "system", fmt.Sprintf("Auto-retry %d/%d in %s", attempt, limit, delay))
+	e.logger.Info("Scheduled auto-retry", "id", task.ID, "attempt", attempt, "max", limit, "delay", delay)
+}
+
+// runDueAutoRetries re-queues blocked tasks whose automatic retry is due.
+func (e *Executor) runDueAutoRetries(now time.Time) {
+	limit := MaxRetries(e.db)
+	if limit == 0 {
+		return
+	}
+	ids, err := e.db.ListDueAutoRetries(now)
+	if err != nil {
+		e.logger.Warn("auto-retry: failed to list due retries", "error", err)
+		return
+	}
+	for _, id := range ids {
+		state, err := e.db.GetAutoRetryState(id)
+		if err != nil {
+			continue
+		}
+		e.logLine(id, "system", fmt.Sprintf("Auto-retry attempt %d/%d", state.Count, limit))
+		if err := e.db.AutoRetryTask(id, "The previous attempt failed. Review what went wrong and try again."); err != nil {
+			e.logger.Warn("auto-retry: failed to re-queue task", "task", id, "error", err)
+			continue
+		}
+		e.logger.Info("Auto-retrying task", "id", id, "attempt", state.Count)
+	}
+	if len(ids) > 0 {
+		e.TriggerProcessing()
+	}
+}
@@ -0,0 +1,116 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bborn/workflow/internal/config"
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestAutoRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{6, 16 * time.Minute},
+		{7, autoRetryMaxDelay},
+		{50, autoRetryMaxDelay},
+	}
+	for _, tt := range tests {
+		if got := AutoRetryDelay(tt.attempt); got != tt.want {
+			t.Errorf("AutoRetryDelay(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func latestLogContent(t *testing.T, database *db.DB, taskID int64) string {
+	t.Helper()
+	logs, err := database.GetTaskLogs(taskID, 1) // newest first
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) == 0 {
+		return ""
+	}
+	return logs[0].Content
+}
+
+// A failing task is retried with backoff until max_retries is spent, then
+// stays blocked with an "exhausted" log line instead of looping.
+func TestAutoRetryUntilExhausted(t *testing.T) {
+	database := newGuardTestDB(t)
+	exec := New(database, &config.Config{})
+	if err := database.SetSetting(config.SettingMaxRetries, "2"); err != nil {
+		t.Fatal(err)
+	}
+
+	task := &db.Task{Title: "flaky", Status: db.StatusBlocked, Project: "test"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	for attempt := 1; attempt <= 2; attempt++ {
+		exec.scheduleAutoRetry(task, now)
+		state, _ := database.GetAutoRetryState(task.ID)
+		if state.Count != attempt || state.RetryAt == nil {
+			t.Fatalf("attempt %d: state = %+v", attempt, state)
+		}
+
+		// Not due yet: nothing happens.
+		exec.runDueAutoRetries(now)
+		if got, _ := database.GetTask(task.ID); got.Status != db.StatusBlocked {
+			t.Fatalf("attempt %d: retried before the backoff elapsed", attempt)
+		}
+
+		now = now.Add(AutoRetryDelay(attempt) + time.Second)
+		exec.runDueAutoRetries(now)
+		if got, _ := database.GetTask(task.ID); got.Status != db.StatusQueued {
+			t.Fatalf("attempt %d: status = %q, want queued", attempt, got.Status)
+		}
+		// The re-run fails again.
+		database.UpdateTaskStatus(task.ID, db.StatusBlocked)
+	}
+
+	exec.scheduleAutoRetry(task, now)
+	state, _ := database.GetAutoRetryState(task.ID)
+	if state.RetryAt != nil {
+		t.Fatalf("scheduled a retry past max_retries: %+v", state)
+	}
+	if got := latestLogContent(t, database, task.ID); !strings.Contains(got, "exhausted 2 retries") {
+		t.Errorf("last log line = %q, want the exhausted message", got)
+	}
+	exec.runDueAutoRetries(now.Add(time.Hour))
+	if got, _ := database.GetTask(task.ID); got.Status != db.StatusBlocked {
+		t.Errorf("exhausted task should stay blocked, got %q", got.Status)
+	}
+}
+
+func TestAutoRetrySkipped(t *testing.T) {
+	database := newGuardTestDB(t)
+	exec := New(database, &config.Config{})
+
+	task := &db.Task{Title: "flaky", Status: db.StatusBlocked, Project: "test"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+
+	// Disabled by default.
+	exec.scheduleAutoRetry(task, time.Now())
+	if state, _ := database.GetAutoRetryState(task.ID); state.Count != 0 || state.RetryAt != nil {
+		t.Errorf("max_retries unset should not schedule: %+v", state)
+	}
+
+	// Per-task opt-out.
+	database.SetSetting(config.SettingMaxRetries, "3")
+	database.SetNoAutoRetry(task.ID, true)
+	exec.scheduleAutoRetry(task, time.Now())
+	if state, _ := database.GetAutoRetryState(task.ID); state.Count != 0 || state.RetryAt != nil {
+		t.Errorf("--no-auto-retry task should not schedule: %+v", state)
+	}
+}
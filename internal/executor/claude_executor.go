@@ -10,6 +10,7 @@ import (
 	"github.com/charmbracelet/log"
 
 	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/profile"
 )
 
 // shellSingleQuote wraps s in single quotes for safe interpolation into a shell
@@ -169,13 +170,22 @@ func (c *ClaudeExecutor) ResumeProcess(taskID int64) bool {
 
 // BuildCommand returns the shell command to start an interactive Claude session.
 // dbPathEnvPrefix returns "WORKTREE_DB_PATH=<path> " when the daemon runs against a
-// non-default DB (an isolated instance), so the agent and its mcp-server inherit it;
-// empty otherwise so normal commands are unchanged.
+// non-default DB (an isolated instance or a profile), so the agent and its
+// mcp-server inherit it; empty otherwise so normal commands are unchanged. The
+// active profile (or --db) rides along so `ty` run by the agent uses the same
+// tmux namespace.
 func dbPathEnvPrefix() string {
+	prefix := ""
+	if name := profile.Active(); name != "" {
+		prefix = fmt.Sprintf("%s=%q ", profile.Env, name)
+	}
+	if dbPath := profile.ActiveDB(); dbPath != "" {
+		prefix = fmt.Sprintf("%s=%q ", profile.DBEnv, dbPath)
+	}
 	if p := os.Getenv("WORKTREE_DB_PATH"); p != "" {
-		return fmt.Sprintf("WORKTREE_DB_PATH=%q ", p)
+		prefix += fmt.Sprintf("WORKTREE_DB_PATH=%q ", p)
 	}
-	return ""
+	return prefix
 }
 
 func (c *ClaudeExecutor) BuildCommand(task *db.Task, sessionID, prompt string) string {
@@ -183,10 +193,10 @@ func (c *ClaudeExecutor) BuildCommand(task *db.Task, sessionID, prompt string) s
 	dangerousFlag := claudePermissionFlag(task)
 
 	// Build per-task effort override flag (empty = use Claude's global default)
-	effort := effortFlag(task.EffortLevel)
+	effort := effortFlag(claudeEffort(task))
 
-	// Build per-task model override flag (empty = use Claude's global default)
-	model := modelFlag(task.Model)
+	// Build model flag: task override, then project/global default (empty = Claude's own default)
+	model := modelFlag(claudeModel(task, c.executor.defaultModel(task.Project)))
 
 	// Get session ID for environment
 	worktreeSessionID := os.Getenv("WORKTREE_SESSION_ID")
@@ -1,6 +1,11 @@
 package executor
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/github"
+)
 
 func TestDefaultWorktreeCleanupMaxAgeIsShort(t *testing.T) {
 	// Regression test: the default must be short enough that heavy task batches
@@ -11,3 +16,28 @@ func TestDefaultWorktreeCleanupMaxAgeIsShort(t *testing.T) {
 		t.Errorf("DefaultWorktreeCleanupMaxAge is %d hours - must be <= 48h to keep cleanup prompt", hours)
 	}
 }
+
+func TestBranchKeepReason(t *testing.T) {
+	e := &Executor{}
+	openPR := github.MarshalPRInfo(&github.PRInfo{Number: 12, State: github.PRStateOpen})
+	mergedPR := github.MarshalPRInfo(&github.PRInfo{Number: 9, State: github.PRStateMerged})
+
+	tests := []struct {
+		name  string
+		task  *db.Task
+		force bool
+		want  string
+	}{
+		{"no PR", &db.Task{BranchName: "task/1-fix"}, false, ""},
+		{"merged PR", &db.Task{BranchName: "task/1-fix", PRInfoJSON: mergedPR}, false, ""},
+		{"open PR", &db.Task{BranchName: "task/1-fix", PRInfoJSON: openPR}, false, "open PR #12"},
+		{"open PR forced", &db.Task{BranchName: "task/1-fix", PRInfoJSON: openPR}, true, ""},
+		{"checked-out branch", &db.Task{BranchName: "fix/ui", SourceBranch: "fix/ui"}, true, "existing branch the task checked out"},
+	}
+	for _, tt := range tests {
+		// No project dir: rely on the stored PR instead of asking GitHub.
+		if got := e.branchKeepReason(tt.task, "", tt.force); got != tt.want {
+			t.Errorf("%s: branchKeepReason() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
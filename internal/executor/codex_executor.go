@@ -117,6 +117,19 @@ func (c *CodexExecutor) runCodex(ctx context.Context, task *db.Task, workDir, pr
 		}
 	}()
 
+	// Status hooks: report tool use and end of turn back to ty so the task moves
+	// between processing and blocked like a Claude task. Registered after the
+	// guard so the deferred cleanups unwind in reverse order.
+	cleanupStatusHooks, hooksErr := c.executor.setupCodexStatusHooks(workDir, c.executor.getProjectDir(task.Project))
+	if hooksErr != nil {
+		c.logger.Warn("could not set up Codex status hooks", "error", hooksErr)
+	}
+	defer func() {
+		if cleanupStatusHooks != nil {
+			cleanupStatusHooks()
+		}
+	}()
+
 	// Create a temp file for the prompt
 	promptFile, err := os.CreateTemp("", "task-prompt-*.txt")
 	if err != nil {
@@ -149,6 +162,8 @@ func (c *CodexExecutor) runCodex(ctx context.Context, task *db.Task, workDir, pr
 	if task.DangerousMode || os.Getenv("WORKTREE_DANGEROUS_MODE") == "1" {
 		dangerousFlag = "--dangerously-bypass-approvals-and-sandbox "
 	}
+	// Per-task --executor-arg flags (model, profile, reasoning effort)
+	argFlags := executorArgFlags(task, db.ExecutorCodex)
 
 	// Check for existing session to resume (validate file exists first)
 	resumeFlag := ""
@@ -167,8 +182,8 @@ func (c *CodexExecutor) runCodex(ctx context.Context, task *db.Task, workDir, pr
 	}
 
 	envPrefix := claudeEnvPrefix(paths.configDir)
-	script = fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q %scodex %s%s"$(cat %q)"`,
-		task.ID, sessionID, task.Port, task.WorktreePath, envPrefix, dangerousFlag, resumeFlag, promptFile.Name())
+	script = fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q %scodex %s%s%s"$(cat %q)"`,
+		task.ID, sessionID, task.Port, task.WorktreePath, envPrefix, dangerousFlag, argFlags, resumeFlag, promptFile.Name())
 
 	// Create new window in task-daemon session
 	actualSession, tmuxErr := createTmuxWindow(daemonSession, windowName, workDir, script, c.executor.getProjectDir(task.Project), task.ID)
@@ -360,6 +375,8 @@ func (c *CodexExecutor) BuildCommand(task *db.Task, sessionID, prompt string) st
 	if task.DangerousMode || os.Getenv("WORKTREE_DANGEROUS_MODE") == "1" {
 		dangerousFlag = "--dangerously-bypass-approvals-and-sandbox "
 	}
+	// Per-task --executor-arg flags (model, profile, reasoning effort)
+	argFlags := executorArgFlags(task, db.ExecutorCodex)
 
 	// Get session ID for environment
 	worktreeSessionID := os.Getenv("WORKTREE_SESSION_ID")
@@ -379,18 +396,18 @@ func (c *CodexExecutor) BuildCommand(task *db.Task, sessionID, prompt string) st
 		promptFile, err := os.CreateTemp("", "task-prompt-*.txt")
 		if err != nil {
 			c.logger.Error("BuildCommand: failed to create temp file", "error", err)
-			return fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q codex %s%s`,
-				task.ID, worktreeSessionID, task.Port, task.WorktreePath, dangerousFlag, resumeFlag)
+			return fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q codex %s%s%s`,
+				task.ID, worktreeSessionID, task.Port, task.WorktreePath, dangerousFlag, argFlags, resumeFlag)
 		}
 		promptFile.WriteString(prompt)
 		promptFile.Close()
 
-		return fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q codex %s%s"$(cat %q)"; rm -f %q`,
-			task.ID, worktreeSessionID, task.Port, task.WorktreePath, dangerousFlag, resumeFlag, promptFile.Name(), promptFile.Name())
+		return fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q codex %s%s%s"$(cat %q)"; rm -f %q`,
+			task.ID, worktreeSessionID, task.Port, task.WorktreePath, dangerousFlag, argFlags, resumeFlag, promptFile.Name(), promptFile.Name())
 	}
 
-	return fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q codex %s%s`,
-		task.ID, worktreeSessionID, task.Port, task.WorktreePath, dangerousFlag, resumeFlag)
+	return fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q codex %s%s%s`,
+		task.ID, worktreeSessionID, task.Port, task.WorktreePath, dangerousFlag, argFlags, resumeFlag)
 }
 
 // ---- Session and Dangerous Mode Support ----
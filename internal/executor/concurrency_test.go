@@ -0,0 +1,169 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bborn/workflow/internal/config"
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestMaxConcurrentTasksSetting(t *testing.T) {
+	database := newGuardTestDB(t)
+
+	if got := MaxConcurrentTasks(database); got != config.DefaultMaxConcurrentTasks {
+		t.Errorf("unset: got %d, want default %d", got, config.DefaultMaxConcurrentTasks)
+	}
+	for _, bad := range []string{"0", "-2", "lots"} {
+		database.SetSetting(config.SettingMaxConcurrentTasks, bad)
+		if got := MaxConcurrentTasks(database); got != config.DefaultMaxConcurrentTasks {
+			t.Errorf("%q: got %d, want default %d", bad, got, config.DefaultMaxConcurrentTasks)
+		}
+	}
+	database.SetSetting(config.SettingMaxConcurrentTasks, "5")
+	if got := MaxConcurrentTasks(database); got != 5 {
+		t.Errorf("got %d, want 5", got)
+	}
+}
+
+// With max_concurrent_tasks=1 the worker must leave the second queued task
+// alone until the first one finishes, then start it on the next pass.
+func TestProcessNextTaskRespectsConcurrencyLimit(t *testing.T) {
+	database := newGuardTestDB(t)
+	exec := New(database, &config.Config{})
+	if err := database.SetSetting(config.SettingMaxConcurrentTasks, "1"); err != nil {
+		t.Fatal(err)
+	}
+
+	first := &db.Task{Title: "first", Status: db.StatusQueued, Project: "test"}
+	if err := database.CreateTask(first); err != nil {
+		t.Fatal(err)
+	}
+	second := &db.Task{Title: "second", Status: db.StatusQueued, Project: "test"}
+	if err := database.CreateTask(second); err != nil {
+		t.Fatal(err)
+	}
+
+	started := make(chan int64, 2)
+	finish := make(chan struct{})
+	exec.executeTaskFn = func(ctx context.Context, task *db.Task) {
+		started <- task.ID
+		database.UpdateTaskStatus(task.ID, db.StatusProcessing)
+		<-finish
+		database.UpdateTaskStatus(task.ID, db.StatusDone)
+		exec.mu.Lock()
+		delete(exec.runningTasks, task.ID)
+		exec.mu.Unlock()
+	}
+
+	ctx := context.Background()
+	exec.processNextTask(ctx)
+
+	var firstID int64
+	select {
+	case firstID = <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected one task to start")
+	}
+
+	// A second pass while the first task is still running starts nothing.
+	exec.processNextTask(ctx)
+	select {
+	case id := <-started:
+		t.Fatalf("task %d started while task %d was still running", id, firstID)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(finish)
+	deadline := time.Now().Add(2 * time.Second)
+	for exec.IsRunning(firstID) {
+		if time.Now().After(deadline) {
+			t.Fatal("first task never finished")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	exec.processNextTask(ctx)
+	select {
+	case id := <-started:
+		if id == firstID {
+			t.Errorf("expected the other task to start, got %d again", id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("second task did not start after the first completed")
+	}
+}
+
+// Each project's WIP limit caps only its own tasks: a full project leaves its
+// queued tasks waiting while other projects keep starting theirs.
+func TestProcessNextTaskRespectsProjectWIPLimits(t *testing.T) {
+	database := newGuardTestDB(t)
+	exec := New(database, &config.Config{})
+	if err := database.SetSetting(config.SettingMaxConcurrentTasks, "10"); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.CreateProject(&db.Project{Name: "alpha", Path: "/tmp/alpha", WIPLimit: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.CreateProject(&db.Project{Name: "beta", Path: "/tmp/beta", WIPLimit: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	queued := map[string]int{"alpha": 2, "beta": 3, "test": 2}
+	for project, n := range queued {
+		for i := 0; i < n; i++ {
+			task := &db.Task{Title: project, Status: db.StatusQueued, Project: project}
+			if err := database.CreateTask(task); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	started := make(chan *db.Task, 10)
+	finish := make(chan struct{})
+	exec.executeTaskFn = func(ctx context.Context, task *db.Task) {
+		database.UpdateTaskStatus(task.ID, db.StatusProcessing)
+		started <- task
+		<-finish
+	}
+	defer close(finish)
+
+	collect := func() map[string]int {
+		got := map[string]int{}
+		for {
+			select {
+			case task := <-started:
+				got[task.Project]++
+			case <-time.After(200 * time.Millisecond):
+				return got
+			}
+		}
+	}
+
+	exec.processNextTask(context.Background())
+	got := collect()
+	want := map[string]int{"alpha": 1, "beta": 2, "test": 2}
+	for project, n := range want {
+		if got[project] != n {
+			t.Errorf("first pass started %d %s task(s), want %d (all: %v)", got[project], project, n, got)
+		}
+	}
+
+	// With every project at its limit (or drained), another pass starts nothing.
+	exec.processNextTask(context.Background())
+	if got := collect(); len(got) != 0 {
+		t.Errorf("second pass started %v past the WIP limits", got)
+	}
+
+	// Raising alpha's limit lets its waiting task start without touching beta.
+	alpha, _ := database.GetProjectByName("alpha")
+	alpha.WIPLimit = 2
+	if err := database.UpdateProject(alpha); err != nil {
+		t.Fatal(err)
+	}
+	exec.processNextTask(context.Background())
+	if got := collect(); got["alpha"] != 1 || got["beta"] != 0 {
+		t.Errorf("after raising alpha's limit started %v, want one alpha task", got)
+	}
+}
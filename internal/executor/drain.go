@@ -0,0 +1,149 @@
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+// drainPollInterval is how often Drain starts queued tasks and checks whether
+// they have settled, when no finishing task wakes it sooner.
+var drainPollInterval = 2 * time.Second
+
+// DrainedTask is the outcome of one task run by Drain.
+type DrainedTask struct {
+	ID     int64
+	Title  string
+	Status string // final status: backlog (agent finished), done, or blocked
+	Failed bool   // the executor failed, as opposed to the task asking for input
+}
+
+// Drain runs the tasks that are queued when it is called, as the daemon
+// would, and returns once none of them is queued or processing: each one has
+// finished, blocked for input, or failed. Tasks queued after Drain starts,
+// including dependents its tasks unblock, are left for a later run. It does
+// none of the daemon's periodic upkeep (auto-retries, schedules, cleanup), so
+// a failed task stays failed; it is meant for a process that exits
+// afterwards ('ty daemon run-once'), not to be combined with Start.
+//
+// Cancelling ctx interrupts the tasks still processing (blocked ones keep
+// their sessions) and returns where every task stood along with ctx's error.
+func (e *Executor) Drain(ctx context.Context) ([]DrainedTask, error) {
+	queued, err := e.db.GetQueuedTasks()
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[int64]bool, len(queued))
+	for _, t := range queued {
+		ids[t.ID] = true
+	}
+
+	e.mu.Lock()
+	e.drainOnly = ids
+	e.drainFailed = make(map[int64]bool)
+	e.running = true // so Stop flushes the log batcher
+	e.mu.Unlock()
+	defer func() {
+		e.mu.Lock()
+		e.drainOnly = nil
+		e.mu.Unlock()
+	}()
+
+	if len(ids) > 0 {
+		e.logger.Info("Draining queued tasks", "count", len(ids))
+	}
+
+	// Tasks run detached from ctx: a blocked task's session outlives Drain,
+	// and cancelling its context would move it to backlog.
+	taskCtx := context.WithoutCancel(ctx)
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	var drainErr error
+	for drainErr == nil {
+		e.processNextTask(taskCtx)
+		settled, err := e.drainSettled(queued)
+		if err != nil {
+			return nil, err
+		}
+		if settled {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			drainErr = ctx.Err()
+			e.interruptDrain(queued)
+		case <-e.wakeupCh:
+		case <-ticker.C:
+		}
+	}
+
+	e.mu.RLock()
+	failed := e.drainFailed
+	e.mu.RUnlock()
+	results := make([]DrainedTask, 0, len(queued))
+	for _, t := range queued {
+		final, err := e.db.GetTask(t.ID)
+		if err != nil {
+			return nil, err
+		}
+		e.mu.RLock()
+		r := DrainedTask{ID: t.ID, Title: t.Title, Failed: failed[t.ID]}
+		e.mu.RUnlock()
+		if final != nil {
+			r.Status = final.Status
+		}
+		results = append(results, r)
+	}
+	return results, drainErr
+}
+
+// interruptDrain interrupts the drained tasks that are still processing and
+// waits briefly for their runs to wind down.
+func (e *Executor) interruptDrain(tasks []*db.Task) {
+	var interrupted []int64
+	for _, t := range tasks {
+		if current, err := e.db.GetTask(t.ID); err == nil && current != nil && current.Status == db.StatusProcessing {
+			e.Interrupt(t.ID)
+			interrupted = append(interrupted, t.ID)
+		}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for _, id := range interrupted {
+		for e.IsRunning(id) && time.Now().Before(deadline) {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+}
+
+// drainSettled reports whether none of tasks is still queued or processing.
+// A deleted task counts as settled.
+func (e *Executor) drainSettled(tasks []*db.Task) (bool, error) {
+	for _, t := range tasks {
+		current, err := e.db.GetTask(t.ID)
+		if err != nil {
+			return false, err
+		}
+		if current != nil && (current.Status == db.StatusQueued || current.Status == db.StatusProcessing) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// skipForDrain reports whether processNextTask should leave taskID alone
+// because a Drain is running and the task wasn't queued when it started.
+func (e *Executor) skipForDrain(taskID int64) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.drainOnly != nil && !e.drainOnly[taskID]
+}
+
+// recordTaskFailure notes that taskID's run failed, for Drain's report.
+func (e *Executor) recordTaskFailure(taskID int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.drainFailed != nil {
+		e.drainFailed[taskID] = true
+	}
+}
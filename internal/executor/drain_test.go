@@ -0,0 +1,125 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bborn/workflow/internal/config"
+	"github.com/bborn/workflow/internal/db"
+)
+
+// Drain runs the tasks queued when it starts and nothing queued afterwards,
+// returning once each has settled, with failures reported separately from
+// tasks that blocked for input.
+func TestDrainRunsOnlyTasksQueuedAtStart(t *testing.T) {
+	database := newGuardTestDB(t)
+	exec := New(database, &config.Config{})
+	defer func(d time.Duration) { drainPollInterval = d }(drainPollInterval)
+	drainPollInterval = 10 * time.Millisecond
+
+	var tasks []*db.Task
+	for _, title := range []string{"finishes", "asks", "fails"} {
+		task := &db.Task{Title: title, Status: db.StatusQueued, Project: "test"}
+		if err := database.CreateTask(task); err != nil {
+			t.Fatal(err)
+		}
+		tasks = append(tasks, task)
+	}
+	late := &db.Task{Title: "late", Status: db.StatusQueued, Project: "test"}
+	var queueLate sync.Once
+
+	exec.executeTaskFn = func(ctx context.Context, task *db.Task) {
+		defer func() {
+			exec.mu.Lock()
+			delete(exec.runningTasks, task.ID)
+			exec.mu.Unlock()
+		}()
+		// Queued mid-drain: must be left for a later run.
+		queueLate.Do(func() {
+			if err := database.CreateTask(late); err != nil {
+				t.Error(err)
+			}
+		})
+		switch task.Title {
+		case "finishes":
+			database.UpdateTaskStatus(task.ID, db.StatusBacklog)
+		case "asks":
+			database.UpdateTaskStatus(task.ID, db.StatusBlocked)
+		case "fails":
+			database.UpdateTaskStatus(task.ID, db.StatusBlocked)
+			exec.recordTaskFailure(task.ID)
+		default:
+			t.Errorf("task %q should not have run", task.Title)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	results, err := exec.Drain(ctx)
+	if err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %+v", results)
+	}
+	want := map[string]DrainedTask{
+		"finishes": {Status: db.StatusBacklog},
+		"asks":     {Status: db.StatusBlocked},
+		"fails":    {Status: db.StatusBlocked, Failed: true},
+	}
+	for _, r := range results {
+		w := want[r.Title]
+		if r.Status != w.Status || r.Failed != w.Failed {
+			t.Errorf("%s: got status %q failed %v, want %q %v", r.Title, r.Status, r.Failed, w.Status, w.Failed)
+		}
+	}
+
+	if got, _ := database.GetTask(late.ID); got.Status != db.StatusQueued {
+		t.Errorf("late task should still be queued, got %q", got.Status)
+	}
+	// Back to normal once the drain is over.
+	if exec.skipForDrain(late.ID) {
+		t.Error("drain filter left in place after Drain returned")
+	}
+}
+
+func TestDrainCancelled(t *testing.T) {
+	database := newGuardTestDB(t)
+	exec := New(database, &config.Config{})
+	defer func(d time.Duration) { drainPollInterval = d }(drainPollInterval)
+	drainPollInterval = 10 * time.Millisecond
+
+	task := &db.Task{Title: "slow", Status: db.StatusQueued, Project: "test"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	exec.executeTaskFn = func(ctx context.Context, task *db.Task) {
+		// Never finishes on its own; the interrupt moves it to backlog.
+		database.UpdateTaskStatus(task.ID, db.StatusProcessing)
+		exec.mu.Lock()
+		delete(exec.runningTasks, task.ID)
+		exec.mu.Unlock()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	results, err := exec.Drain(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline error, got %v", err)
+	}
+	if len(results) != 1 || results[0].Status != db.StatusBacklog {
+		t.Errorf("expected the running task interrupted to backlog, got %+v", results)
+	}
+}
+
+func TestDrainNothingQueued(t *testing.T) {
+	database := newGuardTestDB(t)
+	exec := New(database, &config.Config{})
+	results, err := exec.Drain(context.Background())
+	if err != nil || len(results) != 0 {
+		t.Errorf("expected no results, got %+v, %v", results, err)
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/bborn/workflow/internal/github"
 	"github.com/bborn/workflow/internal/hooks"
 	"github.com/bborn/workflow/internal/pipeline"
+	"github.com/bborn/workflow/internal/profile"
 )
 
 // TaskEvent represents a change to a task.
@@ -46,6 +47,11 @@ type Executor struct {
 	events  *events.Emitter
 	prCache *github.PRCache
 
+	// logBatcher coalesces the output/tool log rows this process writes via
+	// logLine into one write transaction per window to cut SQLite lock
+	// contention. Hook processes batch their own rows per invocation.
+	logBatcher *db.LogBatcher
+
 	// Executor factory for pluggable backends
 	executorFactory *ExecutorFactory
 
@@ -58,6 +64,14 @@ type Executor struct {
 	// Suspended task tracking
 	suspendedTasks map[int64]time.Time // taskID -> time when suspended
 
+	// Queued tasks held back by their project's WIP limit, so the reason is
+	// logged once rather than on every tick.
+	wipHeld map[int64]bool
+
+	// Set while Drain runs: the tasks it may start, and which of them failed.
+	drainOnly   map[int64]bool
+	drainFailed map[int64]bool
+
 	// Subscribers for real-time log updates (per-task)
 	subsMu sync.RWMutex
 	subs   map[int64][]chan *db.TaskLog
@@ -78,6 +92,14 @@ type Executor struct {
 	// windowExistsFn reports whether a live executor tmux window exists for a
 	// task. Overridable in tests; nil means use tmuxWindowExistsForTask.
 	windowExistsFn func(taskID int64) bool
+
+	// liveWindowsFn lists task windows in the daemon sessions. Overridable in
+	// tests; nil means use listLiveTaskWindows.
+	liveWindowsFn func() map[int64]*liveTaskWindow
+
+	// executeTaskFn runs a task admitted by processNextTask. Overridable in
+	// tests; nil means use executeTask.
+	executeTaskFn func(ctx context.Context, task *db.Task)
 }
 
 // DefaultSuspendIdleTimeout is the default time a blocked task must be idle before being suspended.
@@ -163,6 +185,7 @@ func New(database *db.DB, cfg *config.Config) *Executor {
 		hooks:           hooks.NewSilent(hooks.DefaultHooksDir()),
 		events:          eventsEmitter,
 		prCache:         github.NewPRCache(),
+		logBatcher:      db.NewLogBatcher(database, db.DefaultLogBatchWindow, db.DefaultLogBatchSize),
 		executorFactory: NewExecutorFactory(),
 		stopCh:          make(chan struct{}),
 		wakeupCh:        make(chan struct{}, 1),
@@ -171,6 +194,7 @@ func New(database *db.DB, cfg *config.Config) *Executor {
 		runningTasks:    make(map[int64]bool),
 		cancelFuncs:     make(map[int64]context.CancelFunc),
 		suspendedTasks:  make(map[int64]time.Time),
+		wipHeld:         make(map[int64]bool),
 		silent:          true,
 		executorSlug:    slug,
 		executorName:    display,
@@ -180,27 +204,31 @@ func New(database *db.DB, cfg *config.Config) *Executor {
 	database.SetEventEmitter(eventsEmitter)
 
 	// Register available executors
-	e.executorFactory.Register(NewClaudeExecutor(e))
-	e.executorFactory.Register(NewCodexExecutor(e))
-	e.executorFactory.Register(NewGeminiExecutor(e))
-	e.executorFactory.Register(NewOpenClawExecutor(e))
-	e.executorFactory.Register(NewOpenCodeExecutor(e))
-	e.executorFactory.Register(NewPiExecutor(e))
+	for _, b := range builtinExecutors {
+		e.executorFactory.Register(b.new(e))
+	}
 
 	return e
 }
 
 // NewWithLogging creates an executor that logs to stderr (for daemon mode).
 func NewWithLogging(database *db.DB, cfg *config.Config, w io.Writer) *Executor {
+	return NewWithLogger(database, cfg, log.NewWithOptions(w, log.Options{Prefix: "executor"}))
+}
+
+// NewWithLogger creates an executor that logs through logger, so the daemon
+// can send executor output to the same place (and format) as its own.
+func NewWithLogger(database *db.DB, cfg *config.Config, logger *log.Logger) *Executor {
 	slug, display := detectExecutorIdentity()
 	eventsEmitter := events.New(hooks.DefaultHooksDir())
 	e := &Executor{
 		db:              database,
 		config:          cfg,
-		logger:          log.NewWithOptions(w, log.Options{Prefix: "executor"}),
+		logger:          logger,
 		hooks:           hooks.New(hooks.DefaultHooksDir()),
 		events:          eventsEmitter,
 		prCache:         github.NewPRCache(),
+		logBatcher:      db.NewLogBatcher(database, db.DefaultLogBatchWindow, db.DefaultLogBatchSize),
 		executorFactory: NewExecutorFactory(),
 		stopCh:          make(chan struct{}),
 		wakeupCh:        make(chan struct{}, 1),
@@ -209,6 +237,7 @@ func NewWithLogging(database *db.DB, cfg *config.Config, w io.Writer) *Executor
 		runningTasks:    make(map[int64]bool),
 		cancelFuncs:     make(map[int64]context.CancelFunc),
 		suspendedTasks:  make(map[int64]time.Time),
+		wipHeld:         make(map[int64]bool),
 		silent:          false,
 		executorSlug:    slug,
 		executorName:    display,
@@ -218,12 +247,9 @@ func NewWithLogging(database *db.DB, cfg *config.Config, w io.Writer) *Executor
 	database.SetEventEmitter(eventsEmitter)
 
 	// Register available executors
-	e.executorFactory.Register(NewClaudeExecutor(e))
-	e.executorFactory.Register(NewCodexExecutor(e))
-	e.executorFactory.Register(NewGeminiExecutor(e))
-	e.executorFactory.Register(NewOpenClawExecutor(e))
-	e.executorFactory.Register(NewOpenCodeExecutor(e))
-	e.executorFactory.Register(NewPiExecutor(e))
+	for _, b := range builtinExecutors {
+		e.executorFactory.Register(b.new(e))
+	}
 
 	return e
 }
@@ -257,6 +283,10 @@ func (e *Executor) Start(ctx context.Context) {
 	// Recover stale tmux references on startup (handles crash recovery)
 	e.recoverStaleTmuxRefs()
 
+	// Re-link processing/blocked tasks to executor windows that outlived the
+	// previous daemon, so they reconnect without a manual `ty recover`.
+	e.restoreTaskSessions()
+
 	// Reconcile tasks left in 'processing' with no live executor (e.g. after a
 	// daemon restart killed the executor panes). Without this they stay stuck in
 	// 'processing' forever and the board lies about them still running. The
@@ -279,7 +309,7 @@ func (e *Executor) recoverStaleTmuxRefs() {
 	sessionsOut, err := exec.Command("tmux", "list-sessions", "-F", "#{session_name}").Output()
 	if err == nil {
 		for _, session := range strings.Split(strings.TrimSpace(string(sessionsOut)), "\n") {
-			if strings.HasPrefix(session, "task-daemon-") {
+			if strings.HasPrefix(session, profile.DaemonSessionPrefix()) {
 				activeSessions[session] = true
 			}
 		}
@@ -373,7 +403,7 @@ func (e *Executor) reconcileOrphanedTasks(startup bool) {
 		if !startup {
 			msg = "Executor died - task was 'processing' with no live executor. Moved to blocked; retry to resume."
 		}
-		if err := e.updateStatus(task.ID, db.StatusBlocked); err != nil {
+		if err := e.blockTask(task.ID, db.BlockedError); err != nil {
 			e.logger.Error("Failed to reconcile orphaned task", "id", task.ID, "error", err)
 			continue
 		}
@@ -601,7 +631,7 @@ func tmuxWindowExistsForTask(taskID int64) bool {
 			continue
 		}
 		sessionName, name := parts[0], parts[1]
-		if strings.HasPrefix(sessionName, "task-daemon-") && name == windowName {
+		if strings.HasPrefix(sessionName, profile.DaemonSessionPrefix()) && name == windowName {
 			return true
 		}
 	}
@@ -619,6 +649,10 @@ func (e *Executor) Stop() {
 	close(e.stopCh)
 	e.mu.Unlock()
 
+	if err := e.logBatcher.Stop(); err != nil {
+		e.logger.Error("Failed to flush task logs", "error", err)
+	}
+
 	e.logger.Info("Background executor stopped")
 }
 
@@ -690,7 +724,7 @@ func (e *Executor) SuspendTask(taskID int64) bool {
 	e.mu.Unlock()
 
 	e.logger.Info("Suspended Claude process", "task", taskID, "pid", pid)
-	e.logLine(taskID, "system", "Claude suspended (idle timeout)")
+	e.logLine(taskID, "system", SuspendedLogLine)
 	return true
 }
 
@@ -733,7 +767,7 @@ func (e *Executor) ResumeTask(taskID int64) bool {
 	e.mu.Unlock()
 
 	e.logger.Info("Resumed Claude process", "task", taskID, "pid", pid)
-	e.logLine(taskID, "system", "Claude resumed")
+	e.logLine(taskID, "system", ResumedLogLine)
 	return true
 }
 
@@ -1166,6 +1200,17 @@ func (e *Executor) TriggerProcessing() {
 
 // updateStatus updates task status in DB and broadcasts the change.
 func (e *Executor) updateStatus(taskID int64, status string) error {
+	return e.setStatus(taskID, status, "")
+}
+
+// blockTask moves a task to blocked with blockedReason (one of
+// db.BlockedReasons) and broadcasts the change.
+func (e *Executor) blockTask(taskID int64, blockedReason string) error {
+	return e.setStatus(taskID, db.StatusBlocked, blockedReason)
+}
+
+// setStatus is updateStatus with a blocked reason for moves to blocked.
+func (e *Executor) setStatus(taskID int64, status, blockedReason string) error {
 	// Get old status for event
 	oldTask, _ := e.db.GetTask(taskID)
 	oldStatus := ""
@@ -1173,7 +1218,13 @@ func (e *Executor) updateStatus(taskID int64, status string) error {
 		oldStatus = oldTask.Status
 	}
 
-	if err := e.db.UpdateTaskStatus(taskID, status); err != nil {
+	var err error
+	if status == db.StatusBlocked && blockedReason != "" {
+		err = e.db.BlockTask(taskID, blockedReason, "")
+	} else {
+		err = e.db.UpdateTaskStatus(taskID, status)
+	}
+	if err != nil {
 		return err
 	}
 
@@ -1223,6 +1274,8 @@ func (e *Executor) worker(ctx context.Context) {
 	const prDisplayRefreshInterval = 45 // 90 seconds at 2 second ticks
 	const readyTasksInterval = 8        // 16 seconds at 2 second ticks
 	const orphanReconcileInterval = 30  // 60 seconds at 2 second ticks
+	const autoRetryInterval = 5         // 10 seconds at 2 second ticks
+	const scheduleInterval = 5          // 10 seconds at 2 second ticks
 
 	for {
 		select {
@@ -1262,6 +1315,16 @@ func (e *Executor) worker(ctx context.Context) {
 				e.reconcileOrphanedTasks(false)
 			}
 
+			// Re-queue failed tasks whose automatic retry backoff has elapsed.
+			if tickCount%autoRetryInterval == 0 {
+				e.runDueAutoRetries(time.Now())
+			}
+
+			// Create tasks for schedules whose cron spec has come due.
+			if tickCount%scheduleInterval == 0 {
+				e.runDueSchedules(time.Now())
+			}
+
 			// Safety net for the auto-advance of workflow DAGs: re-queue any step
 			// still waiting on dependencies that have all completed (in case the
 			// one-shot ProcessCompletedBlocker flip was dropped).
@@ -1455,6 +1518,11 @@ func (e *Executor) refreshActivePRInfo() {
 			if err := e.db.UpdateTaskPRInfo(task.ID, merged.URL, merged.Number, github.MarshalPRInfo(&merged)); err != nil {
 				e.logger.Warn("refreshActivePRInfo: failed to persist PR info", "task", task.ID, "error", err)
 			}
+			// Keep the shared pr_cache warm so `ty list --pr` and `ty show`
+			// can skip the network.
+			if err := github.SaveCachedPR(e.db, projectDir, task.BranchName, &merged, time.Now()); err != nil {
+				e.logger.Warn("refreshActivePRInfo: failed to update pr_cache", "task", task.ID, "error", err)
+			}
 		}
 	}
 }
@@ -1671,31 +1739,68 @@ func (e *Executor) getWorktreeCleanupMaxAge() time.Duration {
 	return DefaultWorktreeCleanupMaxAge
 }
 
+// WorktreeCleanupOptions controls CleanupStaleWorktreesManual.
+type WorktreeCleanupOptions struct {
+	MaxAge time.Duration
+	DryRun bool
+	// DeleteBranch also deletes each task's local branch once its worktree is
+	// archived. Branches are kept by default.
+	DeleteBranch bool
+	// Force deletes branches even when they have an open pull request.
+	Force bool
+}
+
+// CleanedWorktree is one task handled by CleanupStaleWorktreesManual and what
+// happened to its branch.
+type CleanedWorktree struct {
+	Task          *db.Task
+	Branch        string
+	BranchDeleted bool
+	// BranchKept explains why a branch was kept when DeleteBranch was set.
+	BranchKept string
+}
+
 // CleanupStaleWorktreesManual runs stale worktree cleanup on demand and returns
-// the list of tasks that were cleaned up. If dryRun is true, no changes are made.
-func (e *Executor) CleanupStaleWorktreesManual(maxAge time.Duration, dryRun bool) ([]*db.Task, error) {
-	tasks, err := e.db.GetStaleWorktreeTasks(maxAge)
+// the tasks that were cleaned up. With DryRun, no changes are made and the
+// result says what would happen.
+func (e *Executor) CleanupStaleWorktreesManual(opts WorktreeCleanupOptions) ([]*CleanedWorktree, error) {
+	tasks, err := e.db.GetStaleWorktreeTasks(opts.MaxAge)
 	if err != nil {
 		return nil, fmt.Errorf("list stale worktree tasks: %w", err)
 	}
 
-	if dryRun {
-		return tasks, nil
+	if opts.DryRun {
+		var planned []*CleanedWorktree
+		for _, task := range tasks {
+			c := &CleanedWorktree{Task: task, Branch: task.BranchName}
+			if opts.DeleteBranch && c.Branch != "" && e.config.ProjectUsesWorktrees(task.Project) {
+				c.BranchKept = e.branchKeepReason(task, e.getProjectDir(task.Project), opts.Force)
+				c.BranchDeleted = c.BranchKept == ""
+			}
+			planned = append(planned, c)
+		}
+		return planned, nil
 	}
 
-	var cleaned []*db.Task
+	var cleaned []*CleanedWorktree
 	for _, task := range tasks {
+		c := &CleanedWorktree{Task: task, Branch: task.BranchName}
+
 		// Skip non-worktree projects
 		if !e.config.ProjectUsesWorktrees(task.Project) {
 			e.db.ClearTaskWorktreePath(task.ID)
-			cleaned = append(cleaned, task)
+			c.Branch = ""
+			cleaned = append(cleaned, c)
 			continue
 		}
 
 		// Skip if worktree path doesn't exist on disk
 		if _, err := os.Stat(task.WorktreePath); os.IsNotExist(err) {
 			e.db.ClearTaskWorktreePath(task.ID)
-			cleaned = append(cleaned, task)
+			if opts.DeleteBranch && c.Branch != "" {
+				c.BranchKept = "worktree was already gone, so its commits weren't archived"
+			}
+			cleaned = append(cleaned, c)
 			continue
 		}
 
@@ -1706,7 +1811,23 @@ func (e *Executor) CleanupStaleWorktreesManual(maxAge time.Duration, dryRun bool
 			)
 			continue
 		}
-		cleaned = append(cleaned, task)
+
+		if opts.DeleteBranch && c.Branch != "" {
+			projectDir := e.getProjectDir(task.Project)
+			c.BranchKept = e.branchKeepReason(task, projectDir, opts.Force)
+			if c.BranchKept == "" {
+				// The archive ref keeps the branch's commits, so unarchiving
+				// recreates the branch from it.
+				cmd := exec.Command("git", "branch", "-D", c.Branch)
+				cmd.Dir = projectDir
+				if output, err := cmd.CombinedOutput(); err != nil {
+					c.BranchKept = strings.TrimSpace(string(output))
+				} else {
+					c.BranchDeleted = true
+				}
+			}
+		}
+		cleaned = append(cleaned, c)
 	}
 
 	// Also run git worktree prune on all project directories to clean up stale git refs
@@ -1715,6 +1836,34 @@ func (e *Executor) CleanupStaleWorktreesManual(maxAge time.Duration, dryRun bool
 	return cleaned, nil
 }
 
+// branchKeepReason returns why worktree cleanup must keep task's branch, or ""
+// when it may be deleted. A branch the task checked out rather than created
+// (--branch) belongs to the user, and one with an open pull request is still
+// under review unless force is set.
+func (e *Executor) branchKeepReason(task *db.Task, projectDir string, force bool) string {
+	if task.SourceBranch != "" && task.SourceBranch == task.BranchName {
+		return "existing branch the task checked out"
+	}
+	if force {
+		return ""
+	}
+	// Prefer GitHub's current answer; fall back to the PR last stored for the
+	// task when gh can't be asked.
+	pr := github.UnmarshalPRInfo(task.PRInfoJSON)
+	if e.prCache != nil && projectDir != "" {
+		if live := e.prCache.GetPRForBranch(projectDir, task.BranchName); live != nil {
+			pr = live
+		}
+	}
+	if pr.IsOpen() {
+		if pr.Number > 0 {
+			return fmt.Sprintf("open PR #%d", pr.Number)
+		}
+		return "open PR"
+	}
+	return ""
+}
+
 // pruneAllProjectWorktrees runs `git worktree prune` on all configured project directories
 // to clean up stale internal git worktree references.
 func (e *Executor) pruneAllProjectWorktrees() {
@@ -1745,7 +1894,35 @@ func (e *Executor) processNextTask(ctx context.Context) {
 		return
 	}
 
+	limit := MaxConcurrentTasks(e.db)
+	wip := e.projectWIP()
+	run := e.executeTaskFn
+	if run == nil {
+		run = e.executeTask
+	}
+
 	for _, task := range tasks {
+		if e.skipForDrain(task.ID) {
+			continue
+		}
+
+		// Respect the concurrency limit: anything past it stays queued and is
+		// picked up on a later tick, or sooner via the wakeup sent when a
+		// running task finishes.
+		e.mu.RLock()
+		full := len(e.runningTasks) >= limit
+		running := e.runningTasks[task.ID]
+		e.mu.RUnlock()
+		if full {
+			return
+		}
+
+		// Likewise leave a task queued while its project is at its WIP limit.
+		if w := wip[task.Project]; w != nil && !running && w.processing >= w.limit {
+			e.holdForWIPLimit(task, w)
+			continue
+		}
+
 		// DAG invariant, last line of defense: never start a task that still has
 		// an incomplete blocker. A queued task should already be ready, but a race
 		// or a stray flip can mis-queue a blocked step; admitQueuedTask reverts any
@@ -1761,11 +1938,84 @@ func (e *Executor) processNextTask(ctx context.Context) {
 			e.mu.Unlock()
 			continue
 		}
+		if len(e.runningTasks) >= limit {
+			e.mu.Unlock()
+			return
+		}
 		e.runningTasks[task.ID] = true
+		delete(e.wipHeld, task.ID)
 		e.mu.Unlock()
 
-		go e.executeTask(ctx, task)
+		if w := wip[task.Project]; w != nil {
+			w.processing++
+		}
+		go run(ctx, task)
+	}
+}
+
+// projectWIPCount is a project's WIP limit and how many of its tasks are
+// processing.
+type projectWIPCount struct {
+	limit      int
+	processing int
+}
+
+// projectWIP returns the processing counts of projects that have a WIP limit,
+// keyed by project name. Projects without a limit are absent.
+func (e *Executor) projectWIP() map[string]*projectWIPCount {
+	projects, err := e.db.ListProjects()
+	if err != nil {
+		e.logger.Error("Failed to list projects for WIP limits", "error", err)
+		return nil
+	}
+	wip := make(map[string]*projectWIPCount)
+	for _, p := range projects {
+		if p.WIPLimit > 0 {
+			wip[p.Name] = &projectWIPCount{limit: p.WIPLimit}
+		}
+	}
+	if len(wip) == 0 {
+		return nil
+	}
+
+	counts, err := e.db.CountTasksBy(db.ListTasksOptions{Status: db.StatusProcessing}, "project")
+	if err != nil {
+		e.logger.Error("Failed to count processing tasks for WIP limits", "error", err)
+		return nil
 	}
+	for _, c := range counts {
+		if w := wip[c.Key]; w != nil {
+			w.processing = c.Count
+		}
+	}
+	return wip
+}
+
+// holdForWIPLimit leaves a queued task alone because its project is at its WIP
+// limit, noting why in the task's log the first time it is held.
+func (e *Executor) holdForWIPLimit(task *db.Task, w *projectWIPCount) {
+	e.mu.Lock()
+	logged := e.wipHeld[task.ID]
+	e.wipHeld[task.ID] = true
+	e.mu.Unlock()
+	if logged {
+		return
+	}
+	e.logger.Info("Project at WIP limit; leaving task queued",
+		"id", task.ID, "project", task.Project, "processing", w.processing, "wip_limit", w.limit)
+	e.logLine(task.ID, "system", fmt.Sprintf(
+		"Waiting to start: project %s is at its WIP limit (%d/%d tasks in progress).", task.Project, w.processing, w.limit))
+}
+
+// MaxConcurrentTasks returns the configured max_concurrent_tasks, falling back
+// to config.DefaultMaxConcurrentTasks when unset or not a positive integer.
+func MaxConcurrentTasks(database *db.DB) int {
+	if val, err := database.GetSetting(config.SettingMaxConcurrentTasks); err == nil && val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n > 0 {
+			return n
+		}
+	}
+	return config.DefaultMaxConcurrentTasks
 }
 
 // admitQueuedTask enforces the workflow DAG invariant at the point of spawn: a
@@ -1792,7 +2042,7 @@ func (e *Executor) admitQueuedTask(task *db.Task) bool {
 		"id", task.ID, "title", task.Title, "open_blockers", open)
 	e.logLine(task.ID, "system", fmt.Sprintf(
 		"Refused to start: %d blocker(s) not yet complete. Reverted to blocked; will re-queue when dependencies finish.", open))
-	if err := e.updateStatus(task.ID, db.StatusBlocked); err != nil {
+	if err := e.blockTask(task.ID, db.BlockedDependency); err != nil {
 		e.logger.Error("Failed to revert mis-queued task to blocked", "id", task.ID, "error", err)
 	}
 	return false
@@ -1836,6 +2086,8 @@ func (e *Executor) executeTask(ctx context.Context, task *db.Task) {
 		delete(e.runningTasks, task.ID)
 		delete(e.cancelFuncs, task.ID)
 		e.mu.Unlock()
+		// A slot just freed up; let the worker start the next queued task.
+		e.TriggerProcessing()
 	}()
 
 	e.logger.Info("Processing task", "id", task.ID, "title", task.Title)
@@ -1866,8 +2118,9 @@ func (e *Executor) executeTask(ctx context.Context, task *db.Task) {
 	if err != nil {
 		e.logger.Error("Failed to setup worktree", "error", err)
 		e.logLine(task.ID, "error", fmt.Sprintf("Failed to setup worktree: %v", err))
-		_ = e.db.UpdateTaskStatus(task.ID, db.StatusBlocked)
+		_ = e.db.BlockTask(task.ID, db.BlockedError, "")
 		e.hooks.OnStatusChange(task, db.StatusBlocked, "Worktree setup failed - cannot execute task safely")
+		e.recordTaskFailure(task.ID)
 		return
 	}
 	e.events.EmitTaskWorktreeReady(task)
@@ -1926,14 +2179,16 @@ func (e *Executor) executeTask(ctx context.Context, task *db.Task) {
 	}
 	if taskExecutor == nil {
 		e.logLine(task.ID, "error", "No executor available")
-		e.updateStatus(task.ID, db.StatusBlocked)
+		e.blockTask(task.ID, db.BlockedError)
+		e.recordTaskFailure(task.ID)
 		return
 	}
 
 	// Check if the executor is available
 	if !taskExecutor.IsAvailable() {
 		e.logLine(task.ID, "error", fmt.Sprintf("Executor '%s' is not installed", executorName))
-		e.updateStatus(task.ID, db.StatusBlocked)
+		e.blockTask(task.ID, db.BlockedError)
+		e.recordTaskFailure(task.ID)
 		return
 	}
 
@@ -2008,19 +2263,21 @@ func (e *Executor) executeTask(ctx context.Context, task *db.Task) {
 		e.hooks.OnStatusChange(task, db.StatusBacklog, "Agent finished - awaiting human review to close")
 		e.events.EmitTaskCompleted(task)
 	} else if result.NeedsInput {
-		e.updateStatus(task.ID, db.StatusBlocked)
+		e.blockTask(task.ID, db.BlockedNeedsInput)
 		// Log the question with special type so UI can display it
 		e.logLine(task.ID, "question", result.Message)
 		e.logLine(task.ID, "system", "Task needs input - use 'r' to retry with your answer")
 		e.hooks.OnStatusChange(task, db.StatusBlocked, result.Message)
 	} else {
-		e.updateStatus(task.ID, db.StatusBlocked)
+		e.blockTask(task.ID, db.BlockedError)
 		msg := fmt.Sprintf("Task failed: %s", result.Message)
 		e.logLine(task.ID, "error", msg)
 		e.hooks.OnStatusChange(task, db.StatusBlocked, msg)
 		// task.blocked already fired via updateStatus → db. Fire task.failed too
 		// so watchers can distinguish "needs input" from "agent died".
 		e.events.EmitTaskFailed(task, result.Message)
+		e.recordTaskFailure(task.ID)
+		e.scheduleAutoRetry(task, time.Now())
 	}
 
 	e.logger.Info("Task finished", "id", task.ID, "success", result.Success)
@@ -2062,12 +2319,7 @@ func (e *Executor) getProjectDir(project string) string {
 // getSuspendIdleTimeout returns the configured idle timeout before suspended blocked tasks.
 // Falls back to DefaultSuspendIdleTimeout if not configured.
 func (e *Executor) getSuspendIdleTimeout() time.Duration {
-	if val, err := e.db.GetSetting(config.SettingIdleSuspendTimeout); err == nil && val != "" {
-		if duration, err := time.ParseDuration(val); err == nil {
-			return duration
-		}
-	}
-	return DefaultSuspendIdleTimeout
+	return SuspendIdleTimeout(e.db)
 }
 
 // getProjectInstructions returns the custom instructions for a project.
@@ -2156,7 +2408,7 @@ func (e *Executor) getAttachmentsSection(taskID int64, paths []string, worktreeP
 func (e *Executor) buildPrompt(task *db.Task, attachmentPaths []string) string {
 	var prompt strings.Builder
 
-	// Check for on_create action (triage/preprocessing)
+	// Check for task.created (on_create) action (triage/preprocessing)
 	// Only run on first execution, not retries
 	if task.StartedAt == nil {
 		if onCreateInstructions := e.getOnCreateInstructions(task); onCreateInstructions != "" {
@@ -2312,7 +2564,13 @@ func (e *Executor) applyTemplateSubstitutions(template string, task *db.Task, pr
 		result = strings.ReplaceAll(result, "{{project_instructions}}", "")
 	}
 
-	result = strings.ReplaceAll(result, "{{memories}}", "")
+	// Memories are only looked up when the template uses them
+	if strings.Contains(result, "{{memories:all}}") {
+		result = strings.ReplaceAll(result, "{{memories:all}}", e.buildMemoriesSection(task.Project, true))
+	}
+	if strings.Contains(result, "{{memories}}") {
+		result = strings.ReplaceAll(result, "{{memories}}", e.buildMemoriesSection(task.Project, false))
+	}
 
 	// Similar tasks are injected after memories (no template placeholder for now)
 	if similarTasks != "" {
@@ -2378,6 +2636,9 @@ func (e *Executor) buildTaskMetadataSection(task *db.Task) string {
 	if task.Tags != "" {
 		parts = append(parts, fmt.Sprintf("Tags: %s", task.Tags))
 	}
+	if issue, _ := e.db.GetTaskIssue(task.ID); issue != nil {
+		parts = append(parts, fmt.Sprintf("Issue: %s", issue.URL))
+	}
 
 	if len(parts) == 0 {
 		return ""
@@ -2387,14 +2648,17 @@ func (e *Executor) buildTaskMetadataSection(task *db.Task) string {
 }
 
 // getOnCreateInstructions returns instructions to prepend for new tasks.
-// Returns the project's on_create action if set, or default triage instructions
+// Returns the project's task.created action if set, or default triage instructions
 // if the task needs basic triage (missing project/type or very short description).
 func (e *Executor) getOnCreateInstructions(task *db.Task) string {
-	// Check if project has an on_create action
+	// Check if project has a task.created (or legacy on_create) action
 	if task.Project != "" {
 		project, _ := e.db.GetProjectByName(task.Project)
 		if project != nil {
-			if action := project.GetAction("on_create"); action != nil {
+			if action := project.GetAction(db.ActionTaskCreated); action != nil {
+				return action.Instructions
+			}
+			if action := project.GetAction(db.ActionOnCreate); action != nil {
 				return action.Instructions
 			}
 		}
@@ -2478,7 +2742,7 @@ var findExistingDaemonSession = func() string {
 	}
 
 	for _, session := range strings.Split(strings.TrimSpace(string(out)), "\n") {
-		if strings.HasPrefix(session, "task-daemon-") {
+		if strings.HasPrefix(session, profile.DaemonSessionPrefix()) {
 			return session
 		}
 	}
@@ -2492,14 +2756,14 @@ func getDaemonSessionName() string {
 	// let a second daemon collide with the live instance's tmux session — an isolated
 	// daemon must land on its own task-daemon-<sid>, never the live one.
 	if sid := os.Getenv("WORKTREE_SESSION_ID"); sid != "" {
-		return fmt.Sprintf("task-daemon-%s", sid)
+		return profile.DaemonSessionPrefix() + sid
 	}
 	// No explicit id: reuse an existing session if one is already up.
 	if existing := findExistingDaemonSession(); existing != "" {
 		return existing
 	}
 	// Otherwise a fresh, PID-based name.
-	return fmt.Sprintf("task-daemon-%d", os.Getpid())
+	return fmt.Sprintf("%s%d", profile.DaemonSessionPrefix(), os.Getpid())
 }
 
 // TmuxWindowName returns the window name for a task.
@@ -2616,7 +2880,7 @@ func KillAllWindowsByNameAllSessions(windowName string) {
 		name := parts[2]
 
 		// Only kill windows in daemon sessions
-		if !strings.HasPrefix(sessionName, "task-daemon-") {
+		if !strings.HasPrefix(sessionName, profile.DaemonSessionPrefix()) {
 			continue
 		}
 
@@ -2691,7 +2955,7 @@ func (e *Executor) CleanupDuplicateWindows(taskID int64) {
 		name := parts[2]
 
 		// Only look at daemon sessions
-		if !strings.HasPrefix(sessionName, "task-daemon-") {
+		if !strings.HasPrefix(sessionName, profile.DaemonSessionPrefix()) {
 			continue
 		}
 
@@ -2967,6 +3231,7 @@ func (e *Executor) setupClaudeHooks(workDir string, taskID int64) (cleanup func(
 	// - PostToolUse: Fires after tool completes - ensures task stays "processing"
 	// - Notification: Fires when Claude is idle or needs permission - marks task "blocked"
 	// - Stop: Fires when Claude finishes responding - marks task "blocked" when waiting for input
+	// - SessionStart/SessionEnd: Log session boundaries; SessionEnd records the final usage/cost
 	hooksConfig := map[string]interface{}{
 		// Pre-approve reading from .claude/attachments/ so Claude can access task attachments
 		// without permission prompts (attachments are written there by prepareAttachments)
@@ -3017,6 +3282,26 @@ func (e *Executor) setupClaudeHooks(workDir string, taskID int64) (cleanup func(
 					},
 				},
 			},
+			"SessionStart": []map[string]interface{}{
+				{
+					"hooks": []map[string]interface{}{
+						{
+							"type":    "command",
+							"command": fmt.Sprintf("%s claude-hook --event SessionStart", taskBin),
+						},
+					},
+				},
+			},
+			"SessionEnd": []map[string]interface{}{
+				{
+					"hooks": []map[string]interface{}{
+						{
+							"type":    "command",
+							"command": fmt.Sprintf("%s claude-hook --event SessionEnd", taskBin),
+						},
+					},
+				},
+			},
 		},
 	}
 
@@ -3149,9 +3434,9 @@ func (e *Executor) runClaude(ctx context.Context, task *db.Task, workDir, prompt
 	// Remote Control: launch claude as a remote-drivable session (claude.ai/code + phone)
 	rcFlag := rcFlag(task)
 	// Build per-task effort override flag (empty = use Claude's global default)
-	effort := effortFlag(task.EffortLevel)
-	// Build per-task model override flag (empty = use Claude's global default)
-	model := modelFlag(task.Model)
+	effort := effortFlag(claudeEffort(task))
+	// Build model flag: task override, then project/global default (empty = Claude's own default)
+	model := modelFlag(claudeModel(task, e.defaultModel(task.Project)))
 	// Build trailing prompt arg - suppressed for Remote Control so claude starts with a blank session
 	promptArg := fmt.Sprintf(`"$(cat %q)"`, promptFile.Name())
 	if task.RemoteControl {
@@ -3320,9 +3605,9 @@ func (e *Executor) runClaudeResume(ctx context.Context, task *db.Task, workDir,
 	// Remote Control: launch claude as a remote-drivable session (claude.ai/code + phone)
 	rcFlag := rcFlag(task)
 	// Build per-task effort override flag (empty = use Claude's global default)
-	effort := effortFlag(task.EffortLevel)
-	// Build per-task model override flag (empty = use Claude's global default)
-	model := modelFlag(task.Model)
+	effort := effortFlag(claudeEffort(task))
+	// Build model flag: task override, then project/global default (empty = Claude's own default)
+	model := modelFlag(claudeModel(task, e.defaultModel(task.Project)))
 	// Build trailing prompt arg - suppressed for Remote Control so claude starts with a blank session
 	promptArg := fmt.Sprintf(`"$(cat %q)"`, feedbackFile.Name())
 	if task.RemoteControl {
@@ -3816,8 +4101,8 @@ func (e *Executor) resumeCodexWithMode(task *db.Task, workDir string, dangerousM
 
 	// Build script with --resume flag
 	envPrefix := claudeEnvPrefix(paths.configDir) + taskEnvPrefix(task)
-	script := fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q %scodex %s--resume %s`,
-		taskID, taskSessionID, task.Port, task.WorktreePath, envPrefix, dangerousFlag, sessionID)
+	script := fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q %scodex %s%s--resume %s`,
+		taskID, taskSessionID, task.Port, task.WorktreePath, envPrefix, dangerousFlag, executorArgFlags(task, db.ExecutorCodex), sessionID)
 
 	actualSession, tmuxErr := createTmuxWindow(daemonSession, windowName, workDir, script, e.getProjectDir(task.Project), task.ID)
 	if tmuxErr != nil {
@@ -3924,8 +4209,8 @@ func (e *Executor) resumeGeminiWithMode(task *db.Task, workDir string, dangerous
 
 	// Build script with --resume flag
 	envPrefix := claudeEnvPrefix(paths.configDir) + taskEnvPrefix(task)
-	script := fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q %sgemini %s--resume %s`,
-		taskID, taskSessionID, task.Port, task.WorktreePath, envPrefix, dangerousFlag, sessionID)
+	script := fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q %sgemini %s%s--resume %s`,
+		taskID, taskSessionID, task.Port, task.WorktreePath, envPrefix, dangerousFlag, executorArgFlags(task, db.ExecutorGemini), sessionID)
 
 	actualSession, tmuxErr := createTmuxWindow(daemonSession, windowName, workDir, script, e.getProjectDir(task.Project), task.ID)
 	if tmuxErr != nil {
@@ -3970,6 +4255,14 @@ func (e *Executor) resumeGeminiWithMode(task *db.Task, workDir string, dangerous
 	return true
 }
 
+// ClaudeProjectDirName returns the directory Claude keeps a workDir's sessions
+// in, under <config dir>/projects. Claude replaces / and . with - (keeping the
+// leading dash): /Users/bruno/foo -> -Users-bruno-foo.
+func ClaudeProjectDirName(workDir string) string {
+	escaped := strings.ReplaceAll(workDir, "/", "-")
+	return strings.ReplaceAll(escaped, ".", "-")
+}
+
 // FindClaudeSessionID finds the most recent claude session ID for a workDir using the default config dir.
 // Exported for use by the UI to check for resumable sessions.
 func FindClaudeSessionID(workDir string) string {
@@ -3982,12 +4275,7 @@ func findClaudeSessionIDImpl(workDir, configDir string) string {
 	// The path is escaped: /Users/bruno/foo -> -Users-bruno-foo
 	baseDir := ResolveClaudeConfigDir(configDir)
 
-	// Escape the workDir path to match Claude's project directory naming
-	// Claude replaces / with - and . with - (keeps leading dash)
-	escapedPath := strings.ReplaceAll(workDir, "/", "-")
-	escapedPath = strings.ReplaceAll(escapedPath, ".", "-")
-
-	projectDir := filepath.Join(baseDir, "projects", escapedPath)
+	projectDir := filepath.Join(baseDir, "projects", ClaudeProjectDirName(workDir))
 
 	// Find the most recent UUID.jsonl file (not agent-*.jsonl)
 	entries, err := os.ReadDir(projectDir)
@@ -4034,9 +4322,7 @@ func ClaudeSessionExists(sessionID, workDir, configDir string) bool {
 	}
 
 	baseDir := ResolveClaudeConfigDir(configDir)
-	escapedPath := strings.ReplaceAll(workDir, "/", "-")
-	escapedPath = strings.ReplaceAll(escapedPath, ".", "-")
-	sessionFile := filepath.Join(baseDir, "projects", escapedPath, sessionID+".jsonl")
+	sessionFile := filepath.Join(baseDir, "projects", ClaudeProjectDirName(workDir), sessionID+".jsonl")
 
 	_, err := os.Stat(sessionFile)
 	return err == nil
@@ -4315,8 +4601,17 @@ func (e *Executor) configureTmuxWindow(windowTarget string) {
 }
 
 func (e *Executor) logLine(taskID int64, lineType, content string) {
-	// Store in database
-	e.db.AppendTaskLog(taskID, lineType, content)
+	// Store in database. Output and tool lines arrive in bursts and are only
+	// read back for display, so they go through the batcher. Everything else
+	// (questions, system markers) is queried by the executor itself, so flush
+	// the batch first to keep ordering and write it synchronously.
+	switch lineType {
+	case "output", "tool":
+		e.logBatcher.Append(taskID, lineType, content)
+	default:
+		e.logBatcher.Flush()
+		e.db.AppendTaskLog(taskID, lineType, content)
+	}
 
 	// Broadcast to subscribers
 	logEntry := &db.TaskLog{
@@ -4557,11 +4852,11 @@ func (e *Executor) setupWorktree(task *db.Task) (string, bool, error) {
 		task.WorktreePath = worktreePath
 		task.BranchName = branchName
 	} else {
-		// Get default branch name
-		defaultBranch := e.getDefaultBranch(projectDir)
+		// Branch off the project's base branch, or the repo's default branch
+		baseBranch := e.worktreeBaseBranch(task, projectDir)
 
 		// Create new branch and worktree
-		cmd := exec.Command("git", "worktree", "add", "-b", branchName, worktreePath, defaultBranch)
+		cmd := exec.Command("git", "worktree", "add", "-b", branchName, worktreePath, baseBranch)
 		cmd.Dir = projectDir
 		output, err := cmd.CombinedOutput()
 		if err != nil {
@@ -5722,6 +6017,26 @@ func gitCurrentBranch(dir string) (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// worktreeBaseBranch returns the ref a task's new worktree branch starts from:
+// the project's configured base branch when it exists locally or on origin,
+// otherwise the repo's default branch.
+func (e *Executor) worktreeBaseBranch(task *db.Task, projectDir string) string {
+	project, err := e.db.GetProjectByName(task.Project)
+	if err != nil || project == nil || project.BaseBranch == "" {
+		return e.getDefaultBranch(projectDir)
+	}
+	for _, ref := range []string{project.BaseBranch, "origin/" + project.BaseBranch} {
+		cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+		cmd.Dir = projectDir
+		if err := cmd.Run(); err == nil {
+			return ref
+		}
+	}
+	defaultBranch := e.getDefaultBranch(projectDir)
+	e.logLine(task.ID, "system", fmt.Sprintf("Base branch %q not found; branching from %s", project.BaseBranch, defaultBranch))
+	return defaultBranch
+}
+
 // getDefaultBranch returns the default branch name for a git repo.
 func (e *Executor) getDefaultBranch(projectDir string) string {
 	// Try to get default branch from remote
@@ -5768,6 +6083,7 @@ func (e *Executor) updateTaskPRInfo(task *db.Task, projectDir string) {
 
 	// Fetch PR info for the branch
 	prInfo := e.prCache.GetPRForBranch(projectDir, task.BranchName)
+	github.SaveCachedPR(e.db, projectDir, task.BranchName, prInfo, time.Now())
 	if prInfo != nil {
 		task.PRURL = prInfo.URL
 		task.PRNumber = prInfo.Number
@@ -6137,6 +6453,12 @@ func (e *Executor) UnarchiveWorktree(task *db.Task) error {
 	return nil
 }
 
+// ClaudeSessionDir returns the directory Claude keeps session files in for a
+// worktree, under configDir (or the default Claude config dir when empty).
+func ClaudeSessionDir(worktreePath, configDir string) string {
+	return filepath.Join(ResolveClaudeConfigDir(configDir), "projects", ClaudeProjectDirName(worktreePath))
+}
+
 // CleanupClaudeSessions removes Claude session files for a given worktree path.
 // Claude stores sessions under CLAUDE_CONFIG_DIR/projects/<escaped-path>/.
 // This should be called when deleting a task to clean up session data.
@@ -6145,14 +6467,7 @@ func CleanupClaudeSessions(worktreePath, configDir string) error {
 		return nil
 	}
 
-	baseDir := ResolveClaudeConfigDir(configDir)
-
-	// Escape the worktree path to match Claude's project directory naming
-	// Claude replaces / with - and . with - (keeps leading dash)
-	escapedPath := strings.ReplaceAll(worktreePath, "/", "-")
-	escapedPath = strings.ReplaceAll(escapedPath, ".", "-")
-
-	projectDir := filepath.Join(baseDir, "projects", escapedPath)
+	projectDir := ClaudeSessionDir(worktreePath, configDir)
 
 	// Check if directory exists
 	if _, err := os.Stat(projectDir); os.IsNotExist(err) {
@@ -6351,12 +6666,12 @@ func (e *Executor) runPi(ctx context.Context, task *db.Task, workDir, prompt str
 	var script string
 	if piSessionExists(sessionPath) {
 		e.logLine(task.ID, "system", fmt.Sprintf("Resuming existing session %s", filepath.Base(sessionPath)))
-		script = fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q pi --session %q --continue "$(cat %q)"`,
-			task.ID, sessionID, task.Port, task.WorktreePath, sessionPath, promptFile.Name())
+		script = fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q pi %s--session %q --continue "$(cat %q)"`,
+			task.ID, sessionID, task.Port, task.WorktreePath, executorArgFlags(task, db.ExecutorPi), sessionPath, promptFile.Name())
 	} else {
 		// Start fresh using the explicit session path
-		script = fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q pi --session %q "$(cat %q)"`,
-			task.ID, sessionID, task.Port, task.WorktreePath, sessionPath, promptFile.Name())
+		script = fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q pi %s--session %q "$(cat %q)"`,
+			task.ID, sessionID, task.Port, task.WorktreePath, executorArgFlags(task, db.ExecutorPi), sessionPath, promptFile.Name())
 	}
 
 	// Create new window in task-daemon session (with retry logic for race conditions)
@@ -6464,8 +6779,8 @@ func (e *Executor) runPiResume(ctx context.Context, task *db.Task, workDir, prom
 		taskSessionID = fmt.Sprintf("%d", os.Getpid())
 	}
 
-	script := fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q pi --session %q --continue "$(cat %q)"`,
-		task.ID, taskSessionID, task.Port, task.WorktreePath, sessionPath, feedbackFile.Name())
+	script := fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q pi %s--session %q --continue "$(cat %q)"`,
+		task.ID, taskSessionID, task.Port, task.WorktreePath, executorArgFlags(task, db.ExecutorPi), sessionPath, feedbackFile.Name())
 
 	// Create new window in task-daemon session (with retry logic for race conditions)
 	actualSession, tmuxErr := createTmuxWindow(daemonSession, windowName, workDir, script, e.getProjectDir(task.Project), task.ID)
@@ -0,0 +1,158 @@
+package executor
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+// executorArg is one key a task may set with `ty create --executor-arg
+// key=value`: the CLI flag it becomes, and optionally the only values it
+// accepts. Values are always shell-single-quoted when rendered, so a key
+// without a value list still can't inject anything; the allowlist keeps users
+// to flags that make sense for a background task.
+type executorArg struct {
+	flag   string   // CLI flag the value is passed with
+	prefix string   // prepended to the value (for "-c key=value" style flags)
+	values []string // allowed values; nil = any
+}
+
+// executorArgs lists the --executor-arg keys each executor accepts. Claude's
+// keys feed the same --model/--effort flags as the task's Model and
+// EffortLevel fields (see claudeModel), which win when both are set.
+var executorArgs = map[string]map[string]executorArg{
+	db.ExecutorClaude: {
+		"model":  {flag: "--model"},
+		"effort": {flag: "--effort", values: db.EffortLevels()},
+	},
+	db.ExecutorCodex: {
+		"model":            {flag: "--model"},
+		"profile":          {flag: "--profile"},
+		"reasoning-effort": {flag: "-c", prefix: "model_reasoning_effort=", values: []string{"minimal", "low", "medium", "high"}},
+	},
+	db.ExecutorGemini: {
+		"model": {flag: "--model"},
+	},
+	db.ExecutorPi: {
+		"model":    {flag: "--model"},
+		"provider": {flag: "--provider"},
+		"thinking": {flag: "--thinking", values: []string{"off", "minimal", "low", "medium", "high", "xhigh"}},
+	},
+	db.ExecutorOpenCode: {
+		"model": {flag: "--model"},
+	},
+	db.ExecutorOpenClaw: {
+		"thinking": {flag: "--thinking", values: []string{"off", "minimal", "low", "medium", "high"}},
+	},
+}
+
+// ExecutorArgKeys returns the --executor-arg keys executorName accepts, sorted.
+func ExecutorArgKeys(executorName string) []string {
+	keys := make([]string, 0, len(executorArgs[executorName]))
+	for k := range executorArgs[executorName] {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ValidateExecutorArgs checks args against executorName's allowlist.
+func ValidateExecutorArgs(executorName string, args map[string]string) error {
+	allowed := executorArgs[executorName]
+	for _, k := range sortedKeys(args) {
+		spec, ok := allowed[k]
+		if !ok {
+			if len(allowed) == 0 {
+				return fmt.Errorf("executor %s does not accept --executor-arg", executorName)
+			}
+			return fmt.Errorf("unknown --executor-arg %q for %s (allowed: %s)", k, executorName, strings.Join(ExecutorArgKeys(executorName), ", "))
+		}
+		if strings.TrimSpace(args[k]) == "" {
+			return fmt.Errorf("--executor-arg %s needs a value", k)
+		}
+		if spec.values != nil && !slices.Contains(spec.values, args[k]) {
+			return fmt.Errorf("invalid --executor-arg %s=%s (allowed: %s)", k, args[k], strings.Join(spec.values, ", "))
+		}
+	}
+	return nil
+}
+
+// executorArgFlags renders task's executor args as CLI flags for executorName,
+// with a trailing space, in key order. Keys or values the executor doesn't
+// allow are dropped rather than trusted: the task's executor may have changed
+// since the args were validated.
+func executorArgFlags(task *db.Task, executorName string) string {
+	if task == nil {
+		return ""
+	}
+	args := task.ExecutorArgMap()
+	allowed := executorArgs[executorName]
+	var b strings.Builder
+	for _, k := range sortedKeys(args) {
+		spec, ok := allowed[k]
+		if !ok || (spec.values != nil && !slices.Contains(spec.values, args[k])) {
+			continue
+		}
+		b.WriteString(spec.flag)
+		b.WriteString(" ")
+		b.WriteString(shellSingleQuote(spec.prefix + args[k]))
+		b.WriteString(" ")
+	}
+	return b.String()
+}
+
+// executorArgValue returns the task's value for key if executorName allows it.
+func executorArgValue(task *db.Task, executorName, key string) string {
+	spec, ok := executorArgs[executorName][key]
+	if !ok {
+		return ""
+	}
+	v := task.ExecutorArgMap()[key]
+	if spec.values != nil && !slices.Contains(spec.values, v) {
+		return ""
+	}
+	return v
+}
+
+// claudeModel returns the model a Claude task runs with: its Model override,
+// else its "model" executor arg, else defaultModel (the project's or global
+// default, see DB.DefaultModel). Any "custom:" prefix is dropped.
+func claudeModel(task *db.Task, defaultModel string) string {
+	if task.Model != "" {
+		return db.ModelID(task.Model)
+	}
+	if v := executorArgValue(task, db.ExecutorClaude, "model"); v != "" {
+		return v
+	}
+	return db.ModelID(defaultModel)
+}
+
+// defaultModel returns the Claude model for tasks in project that don't pick
+// one, or "" when there is no database to ask.
+func (e *Executor) defaultModel(project string) string {
+	if e == nil || e.db == nil {
+		return ""
+	}
+	return e.db.DefaultModel(project)
+}
+
+// claudeEffort returns the effort a Claude task runs with: its EffortLevel
+// override, else its "effort" executor arg.
+func claudeEffort(task *db.Task) string {
+	if task.EffortLevel != "" {
+		return task.EffortLevel
+	}
+	return executorArgValue(task, db.ExecutorClaude, "effort")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,90 @@
+package executor
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bborn/workflow/internal/config"
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestValidateExecutorArgs(t *testing.T) {
+	tests := []struct {
+		executor string
+		args     map[string]string
+		wantErr  string
+	}{
+		{db.ExecutorCodex, map[string]string{"model": "o3", "reasoning-effort": "high"}, ""},
+		{db.ExecutorClaude, map[string]string{"model": "opus", "effort": "max"}, ""},
+		{db.ExecutorClaude, map[string]string{"effort": "extreme"}, "invalid --executor-arg effort=extreme"},
+		{db.ExecutorCodex, map[string]string{"sandbox": "none"}, `unknown --executor-arg "sandbox" for codex`},
+		{db.ExecutorGemini, map[string]string{"model": " "}, "needs a value"},
+		{"custom", map[string]string{"model": "x"}, "does not accept --executor-arg"},
+	}
+	for _, tt := range tests {
+		err := ValidateExecutorArgs(tt.executor, tt.args)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("ValidateExecutorArgs(%s, %v) = %v, want nil", tt.executor, tt.args, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("ValidateExecutorArgs(%s, %v) = %v, want error containing %q", tt.executor, tt.args, err, tt.wantErr)
+		}
+	}
+}
+
+// TestExecutorArgFlags verifies args render as quoted flags in key order, and
+// that keys the executor doesn't allow are dropped even if stored.
+func TestExecutorArgFlags(t *testing.T) {
+	task := &db.Task{ExecutorArgs: `{"reasoning-effort":"high","model":"o3; rm -rf ~","sandbox":"none"}`}
+	want := `--model 'o3; rm -rf ~' -c 'model_reasoning_effort=high' `
+	if got := executorArgFlags(task, db.ExecutorCodex); got != want {
+		t.Errorf("executorArgFlags(codex) = %q, want %q", got, want)
+	}
+	if got := executorArgFlags(task, db.ExecutorOpenClaw); got != "" {
+		t.Errorf("executorArgFlags(openclaw) = %q, want none", got)
+	}
+	if got := executorArgFlags(&db.Task{ExecutorArgs: "not json"}, db.ExecutorCodex); got != "" {
+		t.Errorf("executorArgFlags(malformed) = %q, want none", got)
+	}
+}
+
+// TestBuildCommandExecutorArgs verifies each executor's command carries the
+// task's executor args, and that Claude's Model field wins over a model arg.
+func TestBuildCommandExecutorArgs(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "test-*.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	database, err := db.Open(tmpFile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+	exec := New(database, &config.Config{})
+
+	task := &db.Task{ID: 1, Port: 8080, WorktreePath: "/tmp/test-worktree", ExecutorArgs: `{"model":"big-model"}`}
+	for _, name := range []string{db.ExecutorClaude, db.ExecutorCodex, db.ExecutorGemini, db.ExecutorPi, db.ExecutorOpenCode} {
+		cmd := exec.executorFactory.Get(name).BuildCommand(task, "", "")
+		if !strings.Contains(cmd, "--model 'big-model'") {
+			t.Errorf("%s BuildCommand() = %q, want --model 'big-model'", name, cmd)
+		}
+	}
+
+	task.Model = db.ModelOpus
+	cmd := exec.executorFactory.Get(db.ExecutorClaude).BuildCommand(task, "", "")
+	if !strings.Contains(cmd, "--model 'opus'") || strings.Contains(cmd, "big-model") {
+		t.Errorf("claude BuildCommand() = %q, want only --model 'opus'", cmd)
+	}
+
+	openclaw := exec.executorFactory.Get(db.ExecutorOpenClaw).BuildCommand(&db.Task{ID: 1, ExecutorArgs: `{"thinking":"low"}`}, "", "")
+	if !strings.Contains(openclaw, "--thinking low ") {
+		t.Errorf("openclaw BuildCommand() = %q, want --thinking low", openclaw)
+	}
+}
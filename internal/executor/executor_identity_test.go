@@ -49,3 +49,46 @@ func TestDetectExecutorIdentityUnknown(t *testing.T) {
 		t.Fatalf("expected display Beta, got %q", display)
 	}
 }
+
+func clearExecutorEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range executorEnvKeys {
+		t.Setenv(key, "")
+	}
+}
+
+func TestValidateExecutorBuiltins(t *testing.T) {
+	clearExecutorEnv(t)
+
+	names := ExecutorNames()
+	if len(names) != len(builtinExecutors) {
+		t.Fatalf("expected only built-ins, got %v", names)
+	}
+	for _, b := range builtinExecutors {
+		if err := ValidateExecutor(b.name); err != nil {
+			t.Errorf("built-in %q rejected: %v", b.name, err)
+		}
+		// The table's name must match what the executor registers under.
+		if got := b.new(&Executor{}).Name(); got != b.name {
+			t.Errorf("builtinExecutors entry %q constructs executor named %q", b.name, got)
+		}
+	}
+	if !IsValidExecutor("") {
+		t.Error("empty executor (default) should be valid")
+	}
+	if err := ValidateExecutor("beta"); err == nil {
+		t.Error("expected unknown executor to be rejected")
+	}
+}
+
+func TestValidateExecutorAcceptsConfiguredExecutor(t *testing.T) {
+	clearExecutorEnv(t)
+	t.Setenv("TASK_EXECUTOR", "Beta")
+
+	if err := ValidateExecutor("beta"); err != nil {
+		t.Errorf("executor configured via TASK_EXECUTOR rejected: %v", err)
+	}
+	if names := ExecutorNames(); names[len(names)-1] != "beta" {
+		t.Errorf("expected configured executor last, got %v", names)
+	}
+}
@@ -0,0 +1,82 @@
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bborn/workflow/internal/config"
+	"github.com/bborn/workflow/internal/db"
+)
+
+// Log lines written when an idle blocked task's Claude process is stopped and
+// continued. Other processes (the TUI, `ty show`) can't see the daemon's
+// in-memory suspendedTasks map, so they read suspension state from these.
+const (
+	SuspendedLogLine = "Claude suspended (idle timeout)"
+	ResumedLogLine   = "Claude resumed"
+)
+
+// SuspendIdleTimeout returns the configured idle_suspend_timeout, falling back
+// to DefaultSuspendIdleTimeout when unset or unparseable.
+func SuspendIdleTimeout(database *db.DB) time.Duration {
+	if val, err := database.GetSetting(config.SettingIdleSuspendTimeout); err == nil && val != "" {
+		if duration, err := time.ParseDuration(val); err == nil {
+			return duration
+		}
+	}
+	return DefaultSuspendIdleTimeout
+}
+
+// SuspendStatus is where a blocked task stands relative to idle suspension.
+type SuspendStatus struct {
+	Suspended  bool
+	SuspendsAt time.Time // when the idle sweep will suspend it; zero if Suspended
+}
+
+// IdleSuspendStatus reports the suspend state of a blocked task, mirroring
+// suspendIdleBlockedTasks: UpdatedAt is the last activity and the task becomes
+// eligible once it has been idle for timeout. latest is the task's most recent
+// log line (nil if unknown); a trailing SuspendedLogLine means it is already
+// suspended. ok is false for tasks the sweep never suspends.
+func IdleSuspendStatus(task *db.Task, latest *db.TaskLog, timeout time.Duration) (status SuspendStatus, ok bool) {
+	if task == nil || task.Status != db.StatusBlocked {
+		return SuspendStatus{}, false
+	}
+	if latest != nil && latest.Content == SuspendedLogLine {
+		return SuspendStatus{Suspended: true}, true
+	}
+	if task.UpdatedAt.Time.IsZero() {
+		return SuspendStatus{}, false
+	}
+	return SuspendStatus{SuspendsAt: task.UpdatedAt.Time.Add(timeout)}, true
+}
+
+// Remaining returns how long until suspension, never negative.
+func (s SuspendStatus) Remaining(now time.Time) time.Duration {
+	if s.Suspended {
+		return 0
+	}
+	if d := s.SuspendsAt.Sub(now); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Hint renders the status as a short phrase: "suspended", "suspends in 2h13m",
+// or "suspends soon" once the deadline has passed and the next sweep is due.
+func (s SuspendStatus) Hint(now time.Time) string {
+	if s.Suspended {
+		return "suspended"
+	}
+	remaining := s.Remaining(now)
+	if remaining < time.Minute {
+		return "suspends soon"
+	}
+	remaining = remaining.Round(time.Minute)
+	h := int(remaining / time.Hour)
+	m := int((remaining % time.Hour) / time.Minute)
+	if h == 0 {
+		return fmt.Sprintf("suspends in %dm", m)
+	}
+	return fmt.Sprintf("suspends in %dh%dm", h, m)
+}
@@ -0,0 +1,44 @@
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestIdleSuspendStatus(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	timeout := 6 * time.Hour
+
+	blocked := &db.Task{Status: db.StatusBlocked, UpdatedAt: db.LocalTime{Time: now.Add(-3*time.Hour - 47*time.Minute)}}
+
+	st, ok := IdleSuspendStatus(blocked, &db.TaskLog{Content: "Waiting for input"}, timeout)
+	if !ok || st.Suspended {
+		t.Fatalf("expected pending suspension, got %+v ok=%v", st, ok)
+	}
+	if got := st.Hint(now); got != "suspends in 2h13m" {
+		t.Errorf("expected %q, got %q", "suspends in 2h13m", got)
+	}
+
+	st, ok = IdleSuspendStatus(blocked, &db.TaskLog{Content: SuspendedLogLine}, timeout)
+	if !ok || !st.Suspended || st.Hint(now) != "suspended" {
+		t.Errorf("expected suspended, got %+v ok=%v", st, ok)
+	}
+
+	overdue := &db.Task{Status: db.StatusBlocked, UpdatedAt: db.LocalTime{Time: now.Add(-7 * time.Hour)}}
+	st, _ = IdleSuspendStatus(overdue, nil, timeout)
+	if st.Remaining(now) != 0 || st.Hint(now) != "suspends soon" {
+		t.Errorf("expected overdue task to suspend soon, got %q", st.Hint(now))
+	}
+
+	recent := &db.Task{Status: db.StatusBlocked, UpdatedAt: db.LocalTime{Time: now.Add(-5*time.Hour - 30*time.Minute)}}
+	st, _ = IdleSuspendStatus(recent, nil, timeout)
+	if got := st.Hint(now); got != "suspends in 30m" {
+		t.Errorf("expected %q, got %q", "suspends in 30m", got)
+	}
+
+	if _, ok := IdleSuspendStatus(&db.Task{Status: db.StatusProcessing, UpdatedAt: blocked.UpdatedAt}, nil, timeout); ok {
+		t.Error("expected non-blocked tasks to have no suspend status")
+	}
+}
@@ -0,0 +1,113 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+// Limits on the {{memories}} section so a project with a long memory list
+// can't crowd the task itself out of the context window.
+const (
+	maxMemoryChars         = 500  // a single memory is cut after this many characters
+	maxMemoriesSectionSize = 4000 // the whole section, in characters
+)
+
+// codeMemoryCategories are the categories {{memories}} includes. General
+// notes are left out unless the template asks for {{memories:all}}.
+var codeMemoryCategories = []string{
+	db.MemoryCategoryPattern,
+	db.MemoryCategoryDecision,
+	db.MemoryCategoryGotcha,
+	db.MemoryCategoryContext,
+}
+
+// buildMemoriesSection renders a project's memories grouped by category, for
+// the {{memories}} template variable. With all unset only codeMemoryCategories
+// are included. Newer memories win when the section would exceed
+// maxMemoriesSectionSize; the ones left out are counted in a closing note.
+func (e *Executor) buildMemoriesSection(project string, all bool) string {
+	if project == "" {
+		return ""
+	}
+	memories, err := e.db.ListProjectMemories(project, "")
+	if err != nil || len(memories) == 0 {
+		return ""
+	}
+
+	categories := codeMemoryCategories
+	if all {
+		categories = db.MemoryCategories()
+	}
+	wanted := make(map[string]bool, len(categories))
+	for _, c := range categories {
+		wanted[c] = true
+	}
+
+	// Memories come back oldest first; take from the newest end until the
+	// budget runs out.
+	budget := maxMemoriesSectionSize
+	kept := make(map[int64]bool)
+	omitted := 0
+	for i := len(memories) - 1; i >= 0; i-- {
+		m := memories[i]
+		if !wanted[m.Category] {
+			continue
+		}
+		line := memoryLine(m)
+		if len(line) > budget {
+			omitted++
+			continue
+		}
+		budget -= len(line)
+		kept[m.ID] = true
+	}
+	if len(kept) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## Project Memories\n\n")
+	for _, c := range categories {
+		var lines []string
+		for _, m := range memories {
+			if m.Category == c && kept[m.ID] {
+				lines = append(lines, memoryLine(m))
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "### %s\n\n%s\n", memoryCategoryHeading(c), strings.Join(lines, ""))
+	}
+	if omitted > 0 {
+		fmt.Fprintf(&b, "(%d older memories omitted; run `ty memories list %s` to see them.)\n\n", omitted, project)
+	}
+	return b.String()
+}
+
+// memoryLine formats one memory as a list item, truncated to maxMemoryChars.
+func memoryLine(m *db.ProjectMemory) string {
+	content := strings.Join(strings.Fields(m.Content), " ")
+	if r := []rune(content); len(r) > maxMemoryChars {
+		content = string(r[:maxMemoryChars]) + "…"
+	}
+	return "- " + content + "\n"
+}
+
+// memoryCategoryHeading returns the section heading for a memory category.
+func memoryCategoryHeading(category string) string {
+	switch category {
+	case db.MemoryCategoryPattern:
+		return "Patterns"
+	case db.MemoryCategoryDecision:
+		return "Decisions"
+	case db.MemoryCategoryGotcha:
+		return "Gotchas"
+	case db.MemoryCategoryContext:
+		return "Context"
+	default:
+		return "General"
+	}
+}
@@ -0,0 +1,67 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bborn/workflow/internal/config"
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestMemoriesTemplateVariable(t *testing.T) {
+	database := newGuardTestDB(t)
+	exec := New(database, &config.Config{})
+
+	for _, m := range []*db.ProjectMemory{
+		{Project: "test", Category: db.MemoryCategoryGotcha, Content: "The fixtures dir is generated; don't edit it."},
+		{Project: "test", Category: db.MemoryCategoryPattern, Content: "Wrap errors with %w."},
+		{Project: "test", Category: db.MemoryCategoryGeneral, Content: "Standup is at 10."},
+	} {
+		if err := database.CreateProjectMemory(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	task := &db.Task{ID: 1, Title: "t", Project: "test"}
+
+	got := exec.applyTemplateSubstitutions("Intro\n\n{{memories}}\nTask", task, "", "", "", "")
+	if !strings.Contains(got, "### Patterns\n\n- Wrap errors with %w.") || !strings.Contains(got, "### Gotchas") {
+		t.Errorf("code memories missing:\n%s", got)
+	}
+	if strings.Index(got, "### Patterns") > strings.Index(got, "### Gotchas") {
+		t.Errorf("categories out of order:\n%s", got)
+	}
+	if strings.Contains(got, "Standup") {
+		t.Errorf("general memory included by default:\n%s", got)
+	}
+	if got := exec.applyTemplateSubstitutions("{{memories:all}}", task, "", "", "", ""); !strings.Contains(got, "### General\n\n- Standup is at 10.") {
+		t.Errorf("{{memories:all}} left out general memories:\n%s", got)
+	}
+
+	other := &db.Task{ID: 2, Title: "t", Project: "empty"}
+	if got := exec.applyTemplateSubstitutions("A{{memories}}B", other, "", "", "", ""); got != "AB" {
+		t.Errorf("project without memories: got %q", got)
+	}
+}
+
+func TestMemoriesSectionIsBounded(t *testing.T) {
+	database := newGuardTestDB(t)
+	exec := New(database, &config.Config{})
+
+	long := strings.Repeat("x", 2*maxMemoryChars)
+	for i := 0; i < 40; i++ {
+		if err := database.CreateProjectMemory(&db.ProjectMemory{Project: "test", Category: db.MemoryCategoryPattern, Content: long}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	section := exec.buildMemoriesSection("test", false)
+	if len(section) > maxMemoriesSectionSize+200 {
+		t.Errorf("section is %d chars, want about %d at most", len(section), maxMemoriesSectionSize)
+	}
+	if !strings.Contains(section, "older memories omitted") {
+		t.Errorf("no note about omitted memories:\n%s", section)
+	}
+	if strings.Contains(section, strings.Repeat("x", maxMemoryChars+1)) {
+		t.Error("a single memory was not truncated")
+	}
+}
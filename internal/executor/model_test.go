@@ -60,3 +60,35 @@ func TestBuildCommandModel(t *testing.T) {
 		t.Errorf("BuildCommand() with opus model should contain %q, got %q", "--model 'opus'", cmd)
 	}
 }
+
+// TestClaudeModelCustomPrefix verifies a "custom:" override is passed to
+// Claude without its prefix.
+func TestClaudeModelCustomPrefix(t *testing.T) {
+	task := &db.Task{Model: "custom:claude-next-preview"}
+	if got := claudeModel(task, ""); got != "claude-next-preview" {
+		t.Errorf("claudeModel() = %q, want %q", got, "claude-next-preview")
+	}
+}
+
+// TestClaudeModelResolution verifies the launch-time order: the task's model,
+// then its "model" executor arg, then the project/global default.
+func TestClaudeModelResolution(t *testing.T) {
+	tests := []struct {
+		name         string
+		model        string
+		executorArgs string
+		defaultModel string
+		want         string
+	}{
+		{"task model wins", db.ModelOpus, `{"model":"haiku"}`, db.ModelSonnet, db.ModelOpus},
+		{"executor arg beats default", "", `{"model":"haiku"}`, db.ModelSonnet, db.ModelHaiku},
+		{"falls back to default", "", "", "custom:claude-next", "claude-next"},
+		{"no default anywhere", "", "", "", ""},
+	}
+	for _, tt := range tests {
+		task := &db.Task{Model: tt.model, ExecutorArgs: tt.executorArgs}
+		if got := claudeModel(task, tt.defaultModel); got != tt.want {
+			t.Errorf("%s: claudeModel() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
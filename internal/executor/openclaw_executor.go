@@ -131,7 +131,7 @@ func (o *OpenClawExecutor) runOpenClaw(ctx context.Context, task *db.Task, workD
 		}
 	}
 
-	thinkingFlag := buildOpenClawThinkingFlag()
+	thinkingFlag := buildOpenClawThinkingFlag(task)
 
 	// openclaw tui --session <key> --message "prompt" --thinking <level>
 	script := fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q %sopenclaw tui --session %s %s--message "$(cat %q)"`,
@@ -309,7 +309,7 @@ func (o *OpenClawExecutor) BuildCommand(task *db.Task, sessionID, prompt string)
 		sessionKey = task.ClaudeSessionID
 	}
 
-	thinkingFlag := buildOpenClawThinkingFlag()
+	thinkingFlag := buildOpenClawThinkingFlag(task)
 
 	envVars := fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q`,
 		task.ID, worktreeSessionID, task.Port, task.WorktreePath)
@@ -331,9 +331,13 @@ func (o *OpenClawExecutor) BuildCommand(task *db.Task, sessionID, prompt string)
 		envVars, sessionKey, thinkingFlag)
 }
 
-// buildOpenClawThinkingFlag returns the --thinking flag based on environment config.
-func buildOpenClawThinkingFlag() string {
-	level := strings.TrimSpace(os.Getenv("OPENCLAW_THINKING"))
+// buildOpenClawThinkingFlag returns the --thinking flag: the task's "thinking"
+// executor arg, else the OPENCLAW_THINKING environment setting.
+func buildOpenClawThinkingFlag(task *db.Task) string {
+	level := executorArgValue(task, db.ExecutorOpenClaw, "thinking")
+	if level == "" {
+		level = strings.TrimSpace(os.Getenv("OPENCLAW_THINKING"))
+	}
 	if level == "" {
 		// Default to high thinking for complex tasks
 		level = "high"
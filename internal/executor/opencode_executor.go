@@ -132,18 +132,20 @@ func (o *OpenCodeExecutor) runOpenCode(ctx context.Context, task *db.Task, workD
 	}
 
 	envPrefix := claudeEnvPrefix(paths.configDir)
+	// Per-task --executor-arg flags (model)
+	opencodeCmd := strings.TrimSpace("opencode " + executorArgFlags(task, db.ExecutorOpenCode))
 
 	// Build OpenCode command
 	// OpenCode CLI uses --prompt flag to pass initial prompt to the TUI
 	// The positional [project] argument is for the working directory path, not the prompt
 	// We start opencode in the working directory and pass the prompt via --prompt flag
-	script := fmt.Sprintf(`cd %q && WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q %sopencode`,
-		workDir, task.ID, worktreeSessionID, task.Port, task.WorktreePath, envPrefix)
+	script := fmt.Sprintf(`cd %q && WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q %s%s`,
+		workDir, task.ID, worktreeSessionID, task.Port, task.WorktreePath, envPrefix, opencodeCmd)
 
 	// If we have a prompt, pass it via --prompt flag
 	if prompt != "" {
-		script = fmt.Sprintf(`cd %q && WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q %sopencode --prompt "$(cat %q)"; rm -f %q`,
-			workDir, task.ID, worktreeSessionID, task.Port, task.WorktreePath, envPrefix, promptFile.Name(), promptFile.Name())
+		script = fmt.Sprintf(`cd %q && WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q %s%s --prompt "$(cat %q)"; rm -f %q`,
+			workDir, task.ID, worktreeSessionID, task.Port, task.WorktreePath, envPrefix, opencodeCmd, promptFile.Name(), promptFile.Name())
 	}
 
 	actualSession, tmuxErr := createTmuxWindow(daemonSession, windowName, workDir, script, o.executor.getProjectDir(task.Project), task.ID)
@@ -312,20 +314,21 @@ func (o *OpenCodeExecutor) BuildCommand(task *db.Task, sessionID, prompt string)
 
 	envVars := fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q`,
 		task.ID, worktreeSessionID, task.Port, task.WorktreePath)
+	opencodeCmd := strings.TrimSpace("opencode " + executorArgFlags(task, db.ExecutorOpenCode))
 
 	if prompt != "" {
 		promptFile, err := os.CreateTemp("", "task-prompt-*.txt")
 		if err != nil {
 			o.logger.Error("BuildCommand: failed to create temp file", "error", err)
-			return fmt.Sprintf(`%s opencode`, envVars)
+			return fmt.Sprintf(`%s %s`, envVars, opencodeCmd)
 		}
 		promptFile.WriteString(prompt)
 		promptFile.Close()
-		return fmt.Sprintf(`%s opencode --prompt "$(cat %q)"; rm -f %q`,
-			envVars, promptFile.Name(), promptFile.Name())
+		return fmt.Sprintf(`%s %s --prompt "$(cat %q)"; rm -f %q`,
+			envVars, opencodeCmd, promptFile.Name(), promptFile.Name())
 	}
 
-	return fmt.Sprintf(`%s opencode`, envVars)
+	return fmt.Sprintf(`%s %s`, envVars, opencodeCmd)
 }
 
 // ---- Session and Dangerous Mode Support ----
@@ -91,10 +91,13 @@ func (p *PiExecutor) BuildCommand(task *db.Task, sessionID, prompt string) strin
 	// Ensure session directory exists (for manual runs via BuildCommand)
 	os.MkdirAll(filepath.Dir(sessionPath), 0755)
 
+	// Per-task --executor-arg flags (model, provider, thinking level)
+	argFlags := executorArgFlags(task, db.ExecutorPi)
+
 	// Build command - resume if we have a session ID, otherwise start fresh
 	if sessionID != "" {
-		return fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q pi --session %q --continue`,
-			task.ID, worktreeSessionID, task.Port, task.WorktreePath, sessionPath)
+		return fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q pi %s--session %q --continue`,
+			task.ID, worktreeSessionID, task.Port, task.WorktreePath, argFlags, sessionPath)
 	}
 
 	// Start fresh - if prompt is provided, write to temp file and pass it
@@ -103,18 +106,18 @@ func (p *PiExecutor) BuildCommand(task *db.Task, sessionID, prompt string) strin
 		promptFile, err := os.CreateTemp("", "task-prompt-*.txt")
 		if err != nil {
 			p.logger.Error("BuildCommand: failed to create temp file", "error", err)
-			return fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q pi --session %q`,
-				task.ID, worktreeSessionID, task.Port, task.WorktreePath, sessionPath)
+			return fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q pi %s--session %q`,
+				task.ID, worktreeSessionID, task.Port, task.WorktreePath, argFlags, sessionPath)
 		}
 		promptFile.WriteString(prompt)
 		promptFile.Close()
 
-		return fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q pi --session %q "$(cat %q)"; rm -f %q`,
-			task.ID, worktreeSessionID, task.Port, task.WorktreePath, sessionPath, promptFile.Name(), promptFile.Name())
+		return fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q pi %s--session %q "$(cat %q)"; rm -f %q`,
+			task.ID, worktreeSessionID, task.Port, task.WorktreePath, argFlags, sessionPath, promptFile.Name(), promptFile.Name())
 	}
 
-	return fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q pi --session %q`,
-		task.ID, worktreeSessionID, task.Port, task.WorktreePath, sessionPath)
+	return fmt.Sprintf(`WORKTREE_TASK_ID=%d WORKTREE_SESSION_ID=%s WORKTREE_PORT=%d WORKTREE_PATH=%q pi %s--session %q`,
+		task.ID, worktreeSessionID, task.Port, task.WorktreePath, argFlags, sessionPath)
 }
 
 // ---- Session and Dangerous Mode Support ----
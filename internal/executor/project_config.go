@@ -11,6 +11,9 @@ import (
 // ProjectConfig represents the .taskyou.yml configuration file in a project root.
 type ProjectConfig struct {
 	Worktree WorktreeConfig `yaml:"worktree"`
+	// Editor is the command `ty open` uses for this project's worktrees
+	// (e.g. "code" or "zed"), overriding VISUAL and EDITOR.
+	Editor string `yaml:"editor"`
 }
 
 // WorktreeConfig contains worktree-specific configuration.
@@ -87,6 +90,16 @@ func WorktreeAllowExternalWrites(projectDir string) []string {
 	return config.Worktree.AllowExternalWrites
 }
 
+// ProjectEditor returns the editor configured in the project's .taskyou.yml,
+// or "" if none is configured.
+func ProjectEditor(projectDir string) string {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil || config == nil {
+		return ""
+	}
+	return strings.TrimSpace(config.Editor)
+}
+
 // GetWorktreeInitScript returns the path to the worktree init script for a project.
 // It checks:
 // 1. The init_script configured in .taskyou.yml
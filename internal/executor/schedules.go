@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bborn/workflow/internal/cron"
+	"github.com/bborn/workflow/internal/db"
+)
+
+// runDueSchedules creates a task for every schedule whose cron spec has come
+// due since it last fired (or since it was created). A daemon that was down
+// through several slots fires once, for the latest one, rather than
+// replaying each missed run.
+func (e *Executor) runDueSchedules(now time.Time) {
+	schedules, err := e.db.ListSchedules()
+	if err != nil {
+		e.logger.Warn("schedules: failed to list schedules", "error", err)
+		return
+	}
+	queued := false
+	for _, s := range schedules {
+		spec, err := cron.Parse(s.Spec)
+		if err != nil {
+			e.logger.Warn("schedules: invalid spec", "schedule", s.Name, "spec", s.Spec, "error", err)
+			continue
+		}
+		since := s.CreatedAt.Time
+		if s.LastRunAt != nil {
+			since = s.LastRunAt.Time
+		}
+		slot := spec.Last(since, now)
+		if slot.IsZero() {
+			continue
+		}
+		task, err := e.fireSchedule(s, slot)
+		if err != nil {
+			e.logger.Warn("schedules: failed to create task", "schedule", s.Name, "error", err)
+			continue
+		}
+		if task == nil {
+			continue // another daemon got there first
+		}
+		e.logger.Info("Created scheduled task", "schedule", s.Name, "id", task.ID, "slot", slot)
+		if task.Status == db.StatusQueued {
+			queued = true
+		}
+	}
+	if queued {
+		e.TriggerProcessing()
+	}
+}
+
+// fireSchedule claims slot for s and creates its task. It returns nil with no
+// error when the slot was already claimed, and releases the claim when the
+// task can't be created so a later tick fires the slot.
+func (e *Executor) fireSchedule(s *db.Schedule, slot time.Time) (*db.Task, error) {
+	claimed, err := e.db.ClaimScheduleRun(s.ID, slot)
+	if err != nil || !claimed {
+		return nil, err
+	}
+
+	status := db.StatusBacklog
+	if s.Queue {
+		status = db.StatusQueued
+	}
+	task := &db.Task{
+		Title:    s.Title,
+		Body:     s.Body,
+		Project:  s.Project,
+		Type:     s.Type,
+		Executor: s.Executor,
+		Tags:     s.Tags,
+		Status:   status,
+	}
+	if err := e.db.CreateTask(task); err != nil {
+		// Give the slot back, or the run is lost: the claim already moved
+		// last_run_at past it.
+		if rerr := e.db.ReleaseScheduleRun(s.ID, slot, s.LastRunAt); rerr != nil {
+			e.logger.Warn("schedules: failed to release run", "schedule", s.Name, "error", rerr)
+		}
+		return nil, err
+	}
+	if err := e.db.RecordScheduleRun(s.ID, task.ID, slot); err != nil {
+		e.logger.Warn("schedules: failed to record run", "schedule", s.Name, "task", task.ID, "error", err)
+	}
+	e.logLine(task.ID, "system", fmt.Sprintf("Created by schedule %q (%s) for %s", s.Name, s.Spec, slot.Local().Format("Mon Jan 2 15:04")))
+	return task, nil
+}
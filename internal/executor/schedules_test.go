@@ -0,0 +1,98 @@
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bborn/workflow/internal/config"
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestRunDueSchedules(t *testing.T) {
+	database := newGuardTestDB(t)
+	exec := New(database, &config.Config{})
+
+	hourly := &db.Schedule{Name: "hourly", Spec: "0 * * * *", Title: "Review dependabot PRs", Project: "test", Tags: "deps", Queue: true}
+	if err := database.CreateSchedule(hourly); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.CreateSchedule(&db.Schedule{Name: "broken", Spec: "every monday", Title: "never"}); err != nil {
+		t.Fatal(err)
+	}
+
+	scheduledTasks := func() []*db.Task {
+		t.Helper()
+		tasks, err := database.ListTasks(db.ListTasksOptions{Project: "test", IncludeClosed: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tasks
+	}
+
+	// Not due yet: the first slot after creation is still ahead.
+	exec.runDueSchedules(time.Now())
+	if n := len(scheduledTasks()); n != 0 {
+		t.Fatalf("created %d tasks before the schedule was due", n)
+	}
+
+	// Several missed slots fire once.
+	later := time.Now().Add(3 * time.Hour)
+	exec.runDueSchedules(later)
+	tasks := scheduledTasks()
+	if len(tasks) != 1 {
+		t.Fatalf("got %d tasks after missed slots, want 1", len(tasks))
+	}
+	task := tasks[0]
+	if task.Title != "Review dependabot PRs" || task.Status != db.StatusQueued || task.Tags != "deps" {
+		t.Errorf("scheduled task = %+v", task)
+	}
+	if ids, _ := database.ScheduledTaskIDs([]int64{task.ID}); !ids[task.ID] {
+		t.Error("task not recorded as scheduled")
+	}
+
+	// A restarted daemon at the same time doesn't fire the slot again.
+	New(database, &config.Config{}).runDueSchedules(later)
+	if n := len(scheduledTasks()); n != 1 {
+		t.Fatalf("slot fired again after restart: %d tasks", n)
+	}
+
+	exec.runDueSchedules(later.Add(time.Hour))
+	if n := len(scheduledTasks()); n != 2 {
+		t.Errorf("next slot: got %d tasks, want 2", n)
+	}
+}
+
+func TestFailedScheduledCreateKeepsSlot(t *testing.T) {
+	database := newGuardTestDB(t)
+	exec := New(database, &config.Config{})
+
+	if err := database.CreateSchedule(&db.Schedule{Name: "hourly", Spec: "0 * * * *", Title: "Hourly check", Project: "test"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.Exec(`CREATE TRIGGER fail_insert BEFORE INSERT ON tasks BEGIN SELECT RAISE(ABORT, 'insert failed'); END`); err != nil {
+		t.Fatal(err)
+	}
+
+	later := time.Now().Add(3 * time.Hour)
+	exec.runDueSchedules(later)
+	s, err := database.GetSchedule("hourly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.LastRunAt != nil {
+		t.Fatalf("failed create left the slot claimed at %v", s.LastRunAt)
+	}
+
+	// Once tasks can be created again, the next tick fires the slot.
+	if _, err := database.Exec(`DROP TRIGGER fail_insert`); err != nil {
+		t.Fatal(err)
+	}
+	exec.runDueSchedules(later)
+	tasks, err := database.ListTasks(db.ListTasksOptions{Project: "test", IncludeClosed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 {
+		t.Errorf("got %d tasks after retry, want 1", len(tasks))
+	}
+}
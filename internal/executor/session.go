@@ -11,6 +11,7 @@ import (
 
 	"github.com/bborn/workflow/internal/db"
 	"github.com/bborn/workflow/internal/executorlock"
+	"github.com/bborn/workflow/internal/profile"
 )
 
 // spawnLockTimeout bounds how long a spawner waits for the per-task executor
@@ -198,7 +199,7 @@ func findExistingTaskWindow(ctx context.Context, windowName string) string {
 	}
 	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
 		parts := strings.SplitN(line, ":", 3)
-		if len(parts) == 3 && parts[2] == windowName && strings.HasPrefix(parts[0], "task-daemon-") {
+		if len(parts) == 3 && parts[2] == windowName && strings.HasPrefix(parts[0], profile.DaemonSessionPrefix()) {
 			return parts[0] + ":" + parts[1]
 		}
 	}
@@ -211,13 +212,13 @@ func findOrCreateDaemonSession(ctx context.Context) (string, error) {
 	out, err := exec.CommandContext(ctx, "tmux", "list-sessions", "-F", "#{session_name}").Output()
 	if err == nil {
 		for _, session := range strings.Split(strings.TrimSpace(string(out)), "\n") {
-			if strings.HasPrefix(session, "task-daemon-") {
+			if strings.HasPrefix(session, profile.DaemonSessionPrefix()) {
 				return session, nil
 			}
 		}
 	}
 
-	daemonSession := fmt.Sprintf("task-daemon-%d", os.Getpid())
+	daemonSession := fmt.Sprintf("%s%d", profile.DaemonSessionPrefix(), os.Getpid())
 	// "tail -f /dev/null" keeps the placeholder window alive (empty windows exit immediately).
 	if err := exec.CommandContext(ctx, "tmux", "new-session", "-d", "-s", daemonSession, "-n", "_placeholder", "tail", "-f", "/dev/null").Run(); err != nil {
 		return "", fmt.Errorf("tmux new-session failed: %w", err)
@@ -48,3 +48,10 @@ func TestClaudeSessionExists(t *testing.T) {
 		t.Fatalf("expected false for an empty session ID")
 	}
 }
+
+func TestClaudeProjectDirName(t *testing.T) {
+	got := ClaudeProjectDirName("/Users/someone/Projects/app/.task-worktrees/2167-redesign")
+	if want := "-Users-someone-Projects-app--task-worktrees-2167-redesign"; got != want {
+		t.Errorf("ClaudeProjectDirName = %q, want %q", got, want)
+	}
+}
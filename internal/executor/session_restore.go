@@ -0,0 +1,125 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/profile"
+)
+
+// liveTaskWindow is a task-<id> window found in one of this profile's daemon
+// sessions.
+type liveTaskWindow struct {
+	Session      string
+	WindowID     string
+	ClaudePaneID string // pane .0
+	ShellPaneID  string // pane .1, "" if the window has a single pane
+}
+
+// taskPaneFormat is the list-panes format parseLiveTaskWindows expects.
+const taskPaneFormat = "#{session_name}\t#{window_id}\t#{window_name}\t#{pane_index}\t#{pane_id}"
+
+// listLiveTaskWindows scans every daemon session for task windows. It returns
+// nil if tmux isn't running.
+func listLiveTaskWindows() map[int64]*liveTaskWindow {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "tmux", "list-panes", "-a", "-F", taskPaneFormat).Output()
+	if err != nil {
+		return nil
+	}
+	return parseLiveTaskWindows(string(out), profile.DaemonSessionPrefix())
+}
+
+// parseLiveTaskWindows parses taskPaneFormat output, keeping windows named
+// task-<id> in sessions with the given prefix. If a task somehow has windows in
+// two daemon sessions, the first one listed wins.
+func parseLiveTaskWindows(out, sessionPrefix string) map[int64]*liveTaskWindow {
+	windows := make(map[int64]*liveTaskWindow)
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		parts := strings.Split(line, "\t")
+		if len(parts) != 5 || !strings.HasPrefix(parts[0], sessionPrefix) {
+			continue
+		}
+		session, windowID, windowName, paneIndex, paneID := parts[0], parts[1], parts[2], parts[3], parts[4]
+
+		var taskID int64
+		if _, err := fmt.Sscanf(windowName, "task-%d", &taskID); err != nil || TmuxWindowName(taskID) != windowName {
+			continue
+		}
+		w := windows[taskID]
+		if w == nil {
+			w = &liveTaskWindow{Session: session, WindowID: windowID}
+			windows[taskID] = w
+		} else if w.WindowID != windowID {
+			continue
+		}
+		switch paneIndex {
+		case "0":
+			w.ClaudePaneID = paneID
+		case "1":
+			w.ShellPaneID = paneID
+		}
+	}
+	return windows
+}
+
+// restoreTaskSessions re-links processing and blocked tasks to executor
+// windows that survived a daemon restart (the tmux server outlives the daemon),
+// matching windows to tasks by their task-<id> name. Without it those tasks
+// keep stale or cleared daemon_session/window/pane references until someone
+// runs `ty recover` or opens them in the TUI.
+func (e *Executor) restoreTaskSessions() {
+	listWindows := listLiveTaskWindows
+	if e.liveWindowsFn != nil {
+		listWindows = e.liveWindowsFn
+	}
+	windows := listWindows()
+	if len(windows) == 0 {
+		return
+	}
+
+	restored := 0
+	for _, status := range []string{db.StatusProcessing, db.StatusBlocked} {
+		tasks, err := e.db.ListTasks(db.ListTasksOptions{Status: status, Limit: 1000})
+		if err != nil {
+			e.logger.Error("Failed to list tasks for session restore", "status", status, "error", err)
+			continue
+		}
+		for _, task := range tasks {
+			w := windows[task.ID]
+			if w == nil {
+				continue
+			}
+			if task.DaemonSession == w.Session && task.TmuxWindowID == w.WindowID &&
+				task.ClaudePaneID == w.ClaudePaneID && task.ShellPaneID == w.ShellPaneID {
+				continue
+			}
+			if err := e.restoreTaskSession(task.ID, w); err != nil {
+				e.logger.Warn("Failed to restore task session", "id", task.ID, "error", err)
+				continue
+			}
+			e.logger.Info("Restored task session", "id", task.ID, "session", w.Session, "window", w.WindowID)
+			restored++
+		}
+	}
+
+	if restored > 0 {
+		e.logger.Info("Restored task sessions after restart", "count", restored)
+	}
+}
+
+func (e *Executor) restoreTaskSession(taskID int64, w *liveTaskWindow) error {
+	if err := e.db.UpdateTaskDaemonSession(taskID, w.Session); err != nil {
+		return err
+	}
+	if err := e.db.UpdateTaskWindowID(taskID, w.WindowID); err != nil {
+		return err
+	}
+	return e.db.UpdateTaskPaneIDs(taskID, w.ClaudePaneID, w.ShellPaneID)
+}
@@ -0,0 +1,68 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestParseLiveTaskWindows(t *testing.T) {
+	out := "task-daemon-1\t@1\ttask-7\t0\t%10\n" +
+		"task-daemon-1\t@1\ttask-7\t1\t%11\n" +
+		"task-daemon-1\t@2\ttask-8\t0\t%12\n" +
+		"task-daemon-1\t@3\tscratch\t0\t%13\n" + // not a task window
+		"task-daemon-1\t@4\ttask-9x\t0\t%14\n" + // not exactly task-<id>
+		"task-daemon@work-1\t@5\ttask-10\t0\t%15\n" + // another profile
+		"other\t@6\ttask-11\t0\t%16\n"
+
+	windows := parseLiveTaskWindows(out, "task-daemon-")
+	if len(windows) != 2 {
+		t.Fatalf("expected windows for tasks 7 and 8, got %+v", windows)
+	}
+	if w := windows[7]; w.Session != "task-daemon-1" || w.WindowID != "@1" || w.ClaudePaneID != "%10" || w.ShellPaneID != "%11" {
+		t.Errorf("task 7 window = %+v", w)
+	}
+	if w := windows[8]; w.ClaudePaneID != "%12" || w.ShellPaneID != "" {
+		t.Errorf("task 8 window = %+v", w)
+	}
+}
+
+func TestRestoreTaskSessions(t *testing.T) {
+	exec, database := newTestExecutor(t)
+
+	running := createProcessingTask(t, database, "running")
+	blocked := createProcessingTask(t, database, "blocked")
+	if err := database.UpdateTaskStatus(blocked.ID, db.StatusBlocked); err != nil {
+		t.Fatal(err)
+	}
+	done := createProcessingTask(t, database, "done")
+	if err := database.UpdateTaskStatus(done.ID, db.StatusDone); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.UpdateTaskDaemonSession(running.ID, "task-daemon-old"); err != nil {
+		t.Fatal(err)
+	}
+
+	exec.liveWindowsFn = func() map[int64]*liveTaskWindow {
+		return map[int64]*liveTaskWindow{
+			running.ID: {Session: "task-daemon-2", WindowID: "@1", ClaudePaneID: "%1", ShellPaneID: "%2"},
+			blocked.ID: {Session: "task-daemon-2", WindowID: "@3", ClaudePaneID: "%5"},
+			done.ID:    {Session: "task-daemon-2", WindowID: "@4", ClaudePaneID: "%7"},
+		}
+	}
+
+	exec.restoreTaskSessions()
+
+	got, _ := database.GetTask(running.ID)
+	if got.DaemonSession != "task-daemon-2" || got.TmuxWindowID != "@1" || got.ClaudePaneID != "%1" || got.ShellPaneID != "%2" {
+		t.Errorf("running task not restored: %q %q %q %q", got.DaemonSession, got.TmuxWindowID, got.ClaudePaneID, got.ShellPaneID)
+	}
+	got, _ = database.GetTask(blocked.ID)
+	if got.DaemonSession != "task-daemon-2" || got.TmuxWindowID != "@3" || got.ClaudePaneID != "%5" {
+		t.Errorf("blocked task not restored: %q %q %q", got.DaemonSession, got.TmuxWindowID, got.ClaudePaneID)
+	}
+	got, _ = database.GetTask(done.ID)
+	if got.TmuxWindowID != "" {
+		t.Errorf("done task should be left alone, got window %q", got.TmuxWindowID)
+	}
+}
@@ -3,6 +3,9 @@ package executor
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/bborn/workflow/internal/db"
 )
@@ -81,6 +84,50 @@ type TaskExecutor interface {
 	ResumeSafe(task *db.Task, workDir string) bool
 }
 
+// builtinExecutors lists every executor TaskYou ships with, in display order.
+// Adding one here registers it with each Executor and makes it valid for
+// `ty create`/`ty update` (see ValidateExecutor).
+var builtinExecutors = []struct {
+	name string
+	new  func(*Executor) TaskExecutor
+}{
+	{db.ExecutorClaude, func(e *Executor) TaskExecutor { return NewClaudeExecutor(e) }},
+	{db.ExecutorCodex, func(e *Executor) TaskExecutor { return NewCodexExecutor(e) }},
+	{db.ExecutorGemini, func(e *Executor) TaskExecutor { return NewGeminiExecutor(e) }},
+	{db.ExecutorPi, func(e *Executor) TaskExecutor { return NewPiExecutor(e) }},
+	{db.ExecutorOpenCode, func(e *Executor) TaskExecutor { return NewOpenCodeExecutor(e) }},
+	{db.ExecutorOpenClaw, func(e *Executor) TaskExecutor { return NewOpenClawExecutor(e) }},
+}
+
+// ExecutorNames returns the executor names a task may be assigned: the
+// built-ins, plus the executor configured through TASK_EXECUTOR (or one of its
+// aliases) when that names something else.
+func ExecutorNames() []string {
+	names := make([]string, 0, len(builtinExecutors)+1)
+	for _, b := range builtinExecutors {
+		names = append(names, b.name)
+	}
+	if slug, _ := detectExecutorIdentity(); !slices.Contains(names, slug) {
+		names = append(names, slug)
+	}
+	return names
+}
+
+// IsValidExecutor reports whether name is one of ExecutorNames. The empty
+// string (use the default executor) is valid.
+func IsValidExecutor(name string) bool {
+	return name == "" || slices.Contains(ExecutorNames(), name)
+}
+
+// ValidateExecutor returns an error listing the valid executors when name
+// isn't one of them.
+func ValidateExecutor(name string) error {
+	if IsValidExecutor(name) {
+		return nil
+	}
+	return fmt.Errorf("invalid executor %q. Must be one of: %s", name, strings.Join(ExecutorNames(), ", "))
+}
+
 // ExecutorFactory manages creation of task executors.
 type ExecutorFactory struct {
 	executors map[string]TaskExecutor
@@ -1,8 +1,11 @@
 package executor
 
 import (
+	"os/exec"
+	"path/filepath"
 	"testing"
 
+	"github.com/bborn/workflow/internal/config"
 	"github.com/bborn/workflow/internal/db"
 )
 
@@ -27,3 +30,51 @@ func TestNewWorktreeBranchName(t *testing.T) {
 		t.Errorf("blank pin branch = %q, want %q", got, want)
 	}
 }
+
+func TestWorktreeBaseBranch(t *testing.T) {
+	repo := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"-c", "user.email=test@test.com", "-c", "user.name=Test", "commit", "-q", "--allow-empty", "-m", "init"},
+		{"branch", "develop"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+	e := New(database, &config.Config{})
+
+	project := &db.Project{Name: "app", Path: repo, UseWorktrees: true}
+	if err := database.CreateProject(project); err != nil {
+		t.Fatal(err)
+	}
+	task := &db.Task{Title: "t", Project: "app", Status: db.StatusQueued}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		base string
+		want string
+	}{
+		{"", "main"},           // no base branch: the repo's default
+		{"develop", "develop"}, // configured base branch
+		{"release-9", "main"},  // configured but missing: fall back
+	} {
+		project.BaseBranch = tt.base
+		if err := database.UpdateProject(project); err != nil {
+			t.Fatal(err)
+		}
+		if got := e.worktreeBaseBranch(task, repo); got != tt.want {
+			t.Errorf("base %q: worktreeBaseBranch() = %q, want %q", tt.base, got, tt.want)
+		}
+	}
+}
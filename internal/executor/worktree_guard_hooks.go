@@ -82,6 +82,18 @@ func (e *Executor) setupGeminiWorktreeGuard(workDir, projectDir string) (func(),
 // and returns a cleanup that restores the original file (or removes it if it was
 // created here). Codex (hooks.json) and Gemini (settings.json) share this shape.
 func writeMergedCommandHook(path, event, command string) (func(), error) {
+	return writeMergedCommandHooks(path, []commandHook{{Event: event, Command: command}})
+}
+
+// commandHook is one command hook to register under hooks.<Event>.
+type commandHook struct {
+	Event   string
+	Command string
+}
+
+// writeMergedCommandHooks is writeMergedCommandHook for several events at once,
+// with a single cleanup.
+func writeMergedCommandHooks(path string, add []commandHook) (func(), error) {
 	existingData, existingErr := os.ReadFile(path)
 
 	cfg := map[string]any{}
@@ -95,12 +107,14 @@ func writeMergedCommandHook(path, event, command string) (func(), error) {
 	if hooks == nil {
 		hooks = map[string]any{}
 	}
-	events, _ := hooks[event].([]any)
-	hooks[event] = append(events, map[string]any{
-		"hooks": []any{
-			map[string]any{"type": "command", "command": command},
-		},
-	})
+	for _, h := range add {
+		events, _ := hooks[h.Event].([]any)
+		hooks[h.Event] = append(events, map[string]any{
+			"hooks": []any{
+				map[string]any{"type": "command", "command": h.Command},
+			},
+		})
+	}
 	cfg["hooks"] = hooks
 
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
@@ -137,6 +137,47 @@ func TestSetupWorktreeGuardHookMergePreservesExisting(t *testing.T) {
 	}
 }
 
+// TestSetupCodexStatusHooks verifies that the Codex status hooks are layered on
+// top of the guard hook and that the cleanups, run in reverse, restore a clean
+// worktree.
+func TestSetupCodexStatusHooks(t *testing.T) {
+	e := &Executor{}
+	workDir := t.TempDir()
+
+	cleanupGuard, err := e.setupCodexWorktreeGuard(workDir, "")
+	if err != nil {
+		t.Fatalf("guard setup: %v", err)
+	}
+	cleanupStatus, err := e.setupCodexStatusHooks(workDir, "")
+	if err != nil {
+		t.Fatalf("status setup: %v", err)
+	}
+
+	path := filepath.Join(workDir, ".codex", "hooks.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range codexStatusHookEvents {
+		if !strings.Contains(string(data), "agent-hook --executor codex --event "+event) {
+			t.Errorf("missing %s status hook:\n%s", event, data)
+		}
+	}
+	var cfg map[string]any
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if pre := cfg["hooks"].(map[string]any)["PreToolUse"].([]any); len(pre) != 2 {
+		t.Errorf("expected guard + status PreToolUse hooks, got %d", len(pre))
+	}
+
+	cleanupStatus()
+	cleanupGuard()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("cleanup did not remove hooks.json (err=%v)", err)
+	}
+}
+
 // jsonHookCommand digs the first hook command out of a {"hooks":{<event>:[{"hooks":[{"command":...}]}]}} config.
 func jsonHookCommand(t *testing.T, data []byte, event string) string {
 	t.Helper()
@@ -0,0 +1,119 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// IssueDetails is the metadata needed to seed a task from a GitHub issue
+// (`ty import-issue`).
+type IssueDetails struct {
+	Owner  string
+	Repo   string
+	Number int
+	URL    string
+	Title  string
+	Body   string
+	State  string // "OPEN" or "CLOSED"
+}
+
+type ghIssueResponse struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	State  string `json:"state"`
+}
+
+// issueShorthand matches owner/repo#42.
+var issueShorthand = regexp.MustCompile(`^([A-Za-z0-9_.-]+)/([A-Za-z0-9_.-]+)#(\d+)$`)
+
+// ParseIssueRef extracts owner, repo and number from a GitHub issue URL such
+// as https://github.com/owner/repo/issues/42 or the owner/repo#42 shorthand.
+func ParseIssueRef(raw string) (owner, repo string, number int, err error) {
+	s := strings.TrimSpace(raw)
+	if m := issueShorthand.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[3])
+		if n <= 0 {
+			return "", "", 0, fmt.Errorf("invalid issue number in %s", raw)
+		}
+		return m[1], m[2], n, nil
+	}
+
+	s = strings.TrimPrefix(s, "https://")
+	s = strings.TrimPrefix(s, "http://")
+	s = strings.TrimPrefix(s, "www.")
+	if !strings.HasPrefix(s, "github.com/") {
+		return "", "", 0, fmt.Errorf("not a GitHub issue URL or owner/repo#number: %s", raw)
+	}
+	parts := strings.Split(strings.TrimPrefix(s, "github.com/"), "/")
+	if len(parts) < 4 || parts[0] == "" || parts[1] == "" || parts[2] != "issues" {
+		return "", "", 0, fmt.Errorf("not a GitHub issue URL or owner/repo#number: %s", raw)
+	}
+	n, convErr := strconv.Atoi(strings.SplitN(parts[3], "#", 2)[0])
+	if convErr != nil || n <= 0 {
+		return "", "", 0, fmt.Errorf("invalid issue number in %s", raw)
+	}
+	return parts[0], parts[1], n, nil
+}
+
+// FetchIssue looks up an issue by URL or owner/repo#number using the gh CLI,
+// so private repositories work with the same credentials as the PR status
+// helpers.
+func FetchIssue(ctx context.Context, ref string) (*IssueDetails, error) {
+	owner, repo, number, err := ParseIssueRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := exec.LookPath("gh"); err != nil {
+		return nil, fmt.Errorf("gh CLI not found; install it from https://cli.github.com")
+	}
+
+	cmd := exec.CommandContext(ctx, "gh", "issue", "view", strconv.Itoa(number),
+		"--repo", owner+"/"+repo, "--json", "number,url,title,body,state")
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("gh issue view: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("gh issue view: %w", err)
+	}
+
+	var resp ghIssueResponse
+	if err := json.Unmarshal(output, &resp); err != nil {
+		return nil, fmt.Errorf("parse gh issue view output: %w", err)
+	}
+	return &IssueDetails{
+		Owner:  owner,
+		Repo:   repo,
+		Number: resp.Number,
+		URL:    resp.URL,
+		Title:  resp.Title,
+		Body:   resp.Body,
+		State:  resp.State,
+	}, nil
+}
+
+// CommentOnIssue posts a comment on the issue at issueURL using the gh CLI.
+func CommentOnIssue(ctx context.Context, issueURL, body string) error {
+	if _, _, _, err := ParseIssueRef(issueURL); err != nil {
+		return err
+	}
+	if _, err := exec.LookPath("gh"); err != nil {
+		return fmt.Errorf("gh CLI not found; install it from https://cli.github.com")
+	}
+
+	cmd := exec.CommandContext(ctx, "gh", "issue", "comment", issueURL, "--body", body)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("gh issue comment: %s", msg)
+		}
+		return fmt.Errorf("gh issue comment: %w", err)
+	}
+	return nil
+}
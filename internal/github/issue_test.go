@@ -0,0 +1,37 @@
+package github
+
+import "testing"
+
+func TestParseIssueRef(t *testing.T) {
+	tests := []struct {
+		in      string
+		owner   string
+		repo    string
+		number  int
+		wantErr bool
+	}{
+		{in: "https://github.com/bborn/taskyou/issues/42", owner: "bborn", repo: "taskyou", number: 42},
+		{in: "github.com/o/r/issues/7#issuecomment-1", owner: "o", repo: "r", number: 7},
+		{in: "bborn/taskyou#12", owner: "bborn", repo: "taskyou", number: 12},
+		{in: "https://github.com/o/r/pull/3", wantErr: true},
+		{in: "o/r#0", wantErr: true},
+		{in: "o/r", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		owner, repo, number, err := ParseIssueRef(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseIssueRef(%q) expected error", tt.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseIssueRef(%q) unexpected error: %v", tt.in, err)
+			continue
+		}
+		if owner != tt.owner || repo != tt.repo || number != tt.number {
+			t.Errorf("ParseIssueRef(%q) = %s/%s#%d, want %s/%s#%d", tt.in, owner, repo, number, tt.owner, tt.repo, tt.number)
+		}
+	}
+}
@@ -4,6 +4,7 @@ package github
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os/exec"
 	"strconv"
 	"strings"
@@ -239,6 +240,11 @@ func parseCheckState(checks []ghCheck) CheckState {
 	return CheckStatePassing
 }
 
+// IsOpen reports whether the PR is still open, including drafts.
+func (p *PRInfo) IsOpen() bool {
+	return p != nil && (p.State == PRStateOpen || p.State == PRStateDraft)
+}
+
 // StatusIcon returns a unicode icon representing the PR state.
 func (p *PRInfo) StatusIcon() string {
 	if p == nil {
@@ -516,3 +522,126 @@ func (c *PRCache) GetCachedPR(repoDir, branchName string) *PRInfo {
 	}
 	return nil
 }
+
+// PRDetails is the metadata needed to seed a task from an existing pull
+// request (e.g. `ty create --from-pr`).
+type PRDetails struct {
+	Number            int
+	URL               string
+	Title             string
+	Body              string
+	HeadRefName       string // branch the PR was opened from
+	BaseRefName       string // branch the PR targets
+	IsCrossRepository bool   // head branch lives in a fork, not in origin
+}
+
+type ghPRDetailsResponse struct {
+	Number            int    `json:"number"`
+	URL               string `json:"url"`
+	Title             string `json:"title"`
+	Body              string `json:"body"`
+	HeadRefName       string `json:"headRefName"`
+	BaseRefName       string `json:"baseRefName"`
+	IsCrossRepository bool   `json:"isCrossRepository"`
+}
+
+// ParsePRURL extracts owner, repo and number from a GitHub pull request URL
+// such as https://github.com/owner/repo/pull/42 (trailing path segments like
+// /files are ignored).
+func ParsePRURL(raw string) (owner, repo string, number int, err error) {
+	s := strings.TrimSpace(raw)
+	s = strings.TrimPrefix(s, "https://")
+	s = strings.TrimPrefix(s, "http://")
+	s = strings.TrimPrefix(s, "www.")
+	if !strings.HasPrefix(s, "github.com/") {
+		return "", "", 0, fmt.Errorf("not a GitHub pull request URL: %s", raw)
+	}
+	parts := strings.Split(strings.TrimPrefix(s, "github.com/"), "/")
+	if len(parts) < 4 || parts[0] == "" || parts[1] == "" || parts[2] != "pull" {
+		return "", "", 0, fmt.Errorf("not a GitHub pull request URL: %s", raw)
+	}
+	n, convErr := strconv.Atoi(parts[3])
+	if convErr != nil || n <= 0 {
+		return "", "", 0, fmt.Errorf("invalid pull request number in %s", raw)
+	}
+	return parts[0], parts[1], n, nil
+}
+
+// FetchPRDetails looks up a pull request by URL using the gh CLI. Unlike the
+// PR status helpers above it returns an error instead of nil, since the caller
+// is acting on an explicit user request and should say why it failed.
+func FetchPRDetails(ctx context.Context, prURL string) (*PRDetails, error) {
+	if _, _, _, err := ParsePRURL(prURL); err != nil {
+		return nil, err
+	}
+	if _, err := exec.LookPath("gh"); err != nil {
+		return nil, fmt.Errorf("gh CLI not found; install it from https://cli.github.com")
+	}
+
+	cmd := exec.CommandContext(ctx, "gh", "pr", "view", prURL,
+		"--json", "number,url,title,body,headRefName,baseRefName,isCrossRepository")
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("gh pr view: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("gh pr view: %w", err)
+	}
+
+	var resp ghPRDetailsResponse
+	if err := json.Unmarshal(output, &resp); err != nil {
+		return nil, fmt.Errorf("parse gh pr view output: %w", err)
+	}
+	return &PRDetails{
+		Number:            resp.Number,
+		URL:               resp.URL,
+		Title:             resp.Title,
+		Body:              resp.Body,
+		HeadRefName:       resp.HeadRefName,
+		BaseRefName:       resp.BaseRefName,
+		IsCrossRepository: resp.IsCrossRepository,
+	}, nil
+}
+
+// CreatePROptions describes a pull request for CreatePR.
+type CreatePROptions struct {
+	Head  string // branch to open the PR from (already pushed)
+	Base  string // branch to merge into ("" = the repository default)
+	Title string
+	Body  string
+	Draft bool
+}
+
+// CreatePR opens a pull request with the gh CLI from repoDir and returns its
+// number and URL. Like FetchPRDetails it reports why it failed.
+func CreatePR(ctx context.Context, repoDir string, opts CreatePROptions) (number int, url string, err error) {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return 0, "", fmt.Errorf("gh CLI not found; install it from https://cli.github.com")
+	}
+
+	args := []string{"pr", "create", "--head", opts.Head, "--title", opts.Title, "--body", opts.Body}
+	if opts.Base != "" {
+		args = append(args, "--base", opts.Base)
+	}
+	if opts.Draft {
+		args = append(args, "--draft")
+	}
+	cmd := exec.CommandContext(ctx, "gh", args...)
+	cmd.Dir = repoDir
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return 0, "", fmt.Errorf("gh pr create: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return 0, "", fmt.Errorf("gh pr create: %w", err)
+	}
+
+	// gh prints the new PR's URL as the last line of its output.
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	url = strings.TrimSpace(lines[len(lines)-1])
+	_, _, number, err = ParsePRURL(url)
+	if err != nil {
+		return 0, "", fmt.Errorf("gh pr create: unexpected output %q", strings.TrimSpace(string(output)))
+	}
+	return number, url, nil
+}
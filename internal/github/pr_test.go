@@ -380,3 +380,37 @@ func TestNeedsReconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePRURL(t *testing.T) {
+	tests := []struct {
+		in      string
+		owner   string
+		repo    string
+		number  int
+		wantErr bool
+	}{
+		{in: "https://github.com/bborn/taskyou/pull/42", owner: "bborn", repo: "taskyou", number: 42},
+		{in: "github.com/o/r/pull/7/files", owner: "o", repo: "r", number: 7},
+		{in: "https://www.github.com/o/r/pull/1", owner: "o", repo: "r", number: 1},
+		{in: "https://github.com/o/r/issues/3", wantErr: true},
+		{in: "https://gitlab.com/o/r/pull/3", wantErr: true},
+		{in: "https://github.com/o/r/pull/abc", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		owner, repo, number, err := ParsePRURL(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParsePRURL(%q) expected error", tt.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParsePRURL(%q) unexpected error: %v", tt.in, err)
+			continue
+		}
+		if owner != tt.owner || repo != tt.repo || number != tt.number {
+			t.Errorf("ParsePRURL(%q) = %s/%s#%d, want %s/%s#%d", tt.in, owner, repo, number, tt.owner, tt.repo, tt.number)
+		}
+	}
+}
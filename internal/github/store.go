@@ -0,0 +1,73 @@
+package github
+
+import (
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+// DefaultPRCacheTTL is how long a PR lookup stored in the database is used
+// before it is fetched again, when pr_cache_ttl is unset.
+const DefaultPRCacheTTL = 60 * time.Second
+
+// SaveCachedPR stores a PR lookup for branch of repo in the database's
+// pr_cache. A nil info records that the branch has no PR.
+func SaveCachedPR(database *db.DB, repo, branch string, info *PRInfo, fetchedAt time.Time) error {
+	entry := &db.PRCacheEntry{Repo: repo, Branch: branch, FetchedAt: fetchedAt}
+	if info != nil {
+		entry.Number = info.Number
+		entry.URL = info.URL
+		entry.State = string(info.State)
+		entry.CheckState = string(info.CheckState)
+		entry.Mergeable = info.Mergeable
+		entry.InfoJSON = MarshalPRInfo(info)
+	}
+	return database.PutPRCache(entry)
+}
+
+// LoadCachedPR returns the PR lookup stored for branch of repo. found is
+// false when nothing is cached; info is nil when the branch had no PR.
+func LoadCachedPR(database *db.DB, repo, branch string) (info *PRInfo, fetchedAt time.Time, found bool) {
+	entry, err := database.GetPRCache(repo, branch)
+	if err != nil || entry == nil {
+		return nil, time.Time{}, false
+	}
+	if entry.Number > 0 {
+		info = UnmarshalPRInfo(entry.InfoJSON)
+		if info == nil {
+			info = &PRInfo{
+				Number:     entry.Number,
+				URL:        entry.URL,
+				State:      PRState(entry.State),
+				CheckState: CheckState(entry.CheckState),
+				Mergeable:  entry.Mergeable,
+			}
+		}
+	}
+	return info, entry.FetchedAt, true
+}
+
+// LookupPR returns the PR for branch, preferring the database cache while it
+// is younger than ttl. Otherwise (or with refresh) it fetches live from
+// repoDir and stores the result under repo. When the live fetch finds
+// nothing - gh missing or offline look the same as "no PR" - a stale cached
+// PR is returned rather than nothing.
+func (c *PRCache) LookupPR(database *db.DB, repo, repoDir, branch string, ttl time.Duration, refresh bool) *PRInfo {
+	if branch == "" {
+		return nil
+	}
+	cached, fetchedAt, found := LoadCachedPR(database, repo, branch)
+	if found && !refresh && time.Since(fetchedAt) < ttl {
+		return cached
+	}
+
+	if refresh {
+		c.InvalidateCache(repoDir, branch)
+	}
+	info := c.GetPRForBranch(repoDir, branch)
+	if info == nil && cached != nil {
+		return cached
+	}
+	SaveCachedPR(database, repo, branch, info, time.Now())
+	return info
+}
@@ -0,0 +1,47 @@
+package github
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestLookupPRUsesFreshCache(t *testing.T) {
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	info := &PRInfo{Number: 5, URL: "https://github.com/o/r/pull/5", State: PRStateOpen, CheckState: CheckStatePassing}
+	if err := SaveCachedPR(database, "/repo", "feature", info, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _, found := LoadCachedPR(database, "/repo", "feature")
+	if !found || got == nil || got.Number != 5 || got.CheckState != CheckStatePassing {
+		t.Fatalf("LoadCachedPR = %+v, %v", got, found)
+	}
+
+	// A fresh entry is served without fetching (the repo dir doesn't exist,
+	// so a live fetch would find nothing).
+	cache := NewPRCache()
+	if pr := cache.LookupPR(database, "/repo", "/nonexistent", "feature", time.Minute, false); pr == nil || pr.Number != 5 {
+		t.Errorf("fresh lookup = %+v, want cached PR #5", pr)
+	}
+
+	// A stale entry whose refetch finds nothing is still returned.
+	SaveCachedPR(database, "/repo", "feature", info, time.Now().Add(-time.Hour))
+	if pr := cache.LookupPR(database, "/repo", "/nonexistent", "feature", time.Minute, false); pr == nil || pr.Number != 5 {
+		t.Errorf("stale lookup = %+v, want cached PR #5", pr)
+	}
+
+	// "No PR" is cached too.
+	SaveCachedPR(database, "/repo", "no-pr", nil, time.Now())
+	got, _, found = LoadCachedPR(database, "/repo", "no-pr")
+	if !found || got != nil {
+		t.Errorf("no-PR entry = %+v, %v", got, found)
+	}
+}
@@ -2,6 +2,7 @@
 package hooks
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
@@ -17,11 +18,11 @@ import (
 
 // Event types for hooks
 const (
-	EventTaskBlocked  = "task.blocked"
-	EventTaskDone     = "task.done"
-	EventTaskFailed   = "task.failed"
-	EventTaskStarted  = "task.started"
-	EventAuthRequired = "task.auth_required" // Executor session needs re-authentication
+	EventTaskBlocked  = db.EventTaskBlocked
+	EventTaskDone     = db.EventTaskDone
+	EventTaskFailed   = db.EventTaskFailed
+	EventTaskStarted  = db.EventTaskStarted
+	EventAuthRequired = db.EventTaskAuthRequired // Executor session needs re-authentication
 )
 
 // Runner executes hooks for task events.
@@ -66,14 +67,18 @@ func (r *Runner) Plugins() []Plugin { return r.plugins }
 // Run executes every hook registered for the given event: the legacy
 // single-script hook in hooksDir (named after the event), plus the matching
 // hook from each loaded plugin. All run concurrently in the background.
+//
+// Each script gets the event as a JSON Payload on stdin and the basics as
+// TASK_* environment variables.
 func (r *Runner) Run(event string, task *db.Task, message string) {
 	baseEnv := taskEnv(event, task, message)
+	stdin := NewPayload(event, task.ID, task, message, nil, time.Now()).JSON()
 
 	// Legacy single-script hook: ~/.config/task/hooks/<event>
 	if r.hooksDir != "" {
 		hookPath := filepath.Join(r.hooksDir, event)
 		if fi, err := os.Stat(hookPath); err == nil && !fi.IsDir() {
-			r.runScript(event, "", hookPath, r.hooksDir, baseEnv)
+			r.runScript(event, "", hookPath, r.hooksDir, baseEnv, stdin)
 		}
 	}
 
@@ -89,7 +94,7 @@ func (r *Runner) Run(event string, task *db.Task, message string) {
 			fmt.Sprintf("TASK_PLUGIN_NAME=%s", p.Name),
 			fmt.Sprintf("TASK_PLUGIN_DIR=%s", p.Dir),
 		)
-		r.runScript(event, p.Name, script, p.Dir, env)
+		r.runScript(event, p.Name, script, p.Dir, env, stdin)
 	}
 }
 
@@ -101,6 +106,7 @@ func taskEnv(event string, task *db.Task, message string) []string {
 		fmt.Sprintf("TASK_STATUS=%s", task.Status),
 		fmt.Sprintf("TASK_PROJECT=%s", task.Project),
 		fmt.Sprintf("TASK_TYPE=%s", task.Type),
+		fmt.Sprintf("TASK_TAGS=%s", task.Tags),
 		fmt.Sprintf("TASK_MESSAGE=%s", message),
 		fmt.Sprintf("TASK_EVENT=%s", event),
 		fmt.Sprintf("WORKTREE_PATH=%s", task.WorktreePath),
@@ -111,7 +117,7 @@ func taskEnv(event string, task *db.Task, message string) []string {
 // by the goroutine (not the caller) so the context isn't cancelled the instant
 // Run returns — which would otherwise kill the hook before it could do anything.
 // plugin is "" for the legacy hook, or the plugin name for a plugin hook.
-func (r *Runner) runScript(event, plugin, scriptPath, workDir string, env []string) {
+func (r *Runner) runScript(event, plugin, scriptPath, workDir string, env []string, stdin []byte) {
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
@@ -119,6 +125,7 @@ func (r *Runner) runScript(event, plugin, scriptPath, workDir string, env []stri
 		cmd := exec.CommandContext(ctx, scriptPath)
 		cmd.Dir = workDir
 		cmd.Env = env
+		cmd.Stdin = bytes.NewReader(stdin)
 
 		output, err := cmd.CombinedOutput()
 		if err != nil {
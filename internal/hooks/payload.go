@@ -0,0 +1,153 @@
+package hooks
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/github"
+)
+
+// PayloadVersion is the schema version of Payload. It only changes when a
+// field is removed or changes meaning; new fields are added without a bump.
+const PayloadVersion = 1
+
+// Payload is the JSON document every hook script (legacy event hooks and
+// plugin hooks) receives on stdin. The TASK_* environment variables carry a
+// subset of the same data for scripts that don't parse JSON.
+//
+// Example:
+//
+//	{
+//	  "version": 1,
+//	  "event": "task.blocked",
+//	  "timestamp": "2026-01-02T15:04:05Z",
+//	  "task_id": 42,
+//	  "message": "Waiting for permission: Bash",
+//	  "task": {"id": 42, "title": "Fix login", "status": "blocked", "tags": ["auth"], ...},
+//	  "metadata": {...}
+//	}
+type Payload struct {
+	Version   int                    `json:"version"`
+	Event     string                 `json:"event"`
+	Timestamp time.Time              `json:"timestamp"`
+	TaskID    int64                  `json:"task_id,omitempty"`
+	Message   string                 `json:"message,omitempty"`
+	Task      *TaskPayload           `json:"task,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// TaskPayload is the task as hooks see it. Field names match `ty show --json`
+// where the two overlap.
+type TaskPayload struct {
+	ID          int64      `json:"id"`
+	Title       string     `json:"title"`
+	Body        string     `json:"body"`
+	Status      string     `json:"status"`
+	Type        string     `json:"type"`
+	Project     string     `json:"project"`
+	Tags        []string   `json:"tags"`
+	Executor    string     `json:"executor"`
+	Assignee    string     `json:"assignee,omitempty"`
+	Pinned      bool       `json:"pinned"`
+	Branch      string     `json:"branch,omitempty"`
+	Worktree    string     `json:"worktree,omitempty"`
+	Port        int        `json:"port,omitempty"`
+	Summary     string     `json:"summary,omitempty"`
+	PR          *PRPayload `json:"pr,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Dangerous   bool       `json:"dangerous_mode"`
+}
+
+// PRPayload is the task's pull request as last cached by the daemon.
+type PRPayload struct {
+	Number     int    `json:"number"`
+	URL        string `json:"url"`
+	State      string `json:"state,omitempty"`
+	CheckState string `json:"check_state,omitempty"`
+	Mergeable  string `json:"mergeable,omitempty"`
+}
+
+// NewPayload builds the stdin payload for an event. task may be nil (e.g.
+// task.deleted, routine.failed); taskID is then used on its own.
+func NewPayload(event string, taskID int64, task *db.Task, message string, metadata map[string]interface{}, at time.Time) *Payload {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	p := &Payload{
+		Version:   PayloadVersion,
+		Event:     event,
+		Timestamp: at,
+		TaskID:    taskID,
+		Message:   message,
+		Metadata:  metadata,
+	}
+	if task != nil {
+		p.TaskID = task.ID
+		p.Task = newTaskPayload(task)
+	}
+	return p
+}
+
+func newTaskPayload(t *db.Task) *TaskPayload {
+	tp := &TaskPayload{
+		ID:        t.ID,
+		Title:     t.Title,
+		Body:      t.Body,
+		Status:    t.Status,
+		Type:      t.Type,
+		Project:   t.Project,
+		Tags:      splitTags(t.Tags),
+		Executor:  t.Executor,
+		Assignee:  t.Assignee,
+		Pinned:    t.Pinned,
+		Branch:    t.BranchName,
+		Worktree:  t.WorktreePath,
+		Port:      t.Port,
+		Summary:   t.Summary,
+		CreatedAt: t.CreatedAt.Time,
+		UpdatedAt: t.UpdatedAt.Time,
+		Dangerous: t.IsDangerous(),
+	}
+	if t.StartedAt != nil {
+		started := t.StartedAt.Time
+		tp.StartedAt = &started
+	}
+	if t.CompletedAt != nil {
+		completed := t.CompletedAt.Time
+		tp.CompletedAt = &completed
+	}
+	if t.PRURL != "" || t.PRNumber != 0 {
+		tp.PR = &PRPayload{Number: t.PRNumber, URL: t.PRURL}
+		if info := github.UnmarshalPRInfo(t.PRInfoJSON); info != nil {
+			tp.PR.State = string(info.State)
+			tp.PR.CheckState = string(info.CheckState)
+			tp.PR.Mergeable = info.Mergeable
+		}
+	}
+	return tp
+}
+
+func splitTags(tags string) []string {
+	out := []string{}
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// JSON encodes the payload. Encoding a Payload can't fail in practice; on the
+// off chance it does, hooks get an empty object rather than no stdin at all.
+func (p *Payload) JSON() []byte {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return []byte("{}")
+	}
+	return data
+}
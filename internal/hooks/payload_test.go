@@ -0,0 +1,96 @@
+package hooks
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+func TestNewPayload_Task(t *testing.T) {
+	at := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	task := &db.Task{
+		ID:         42,
+		Title:      "Fix login",
+		Status:     db.StatusBlocked,
+		Project:    "myapp",
+		Tags:       "auth, urgent,,",
+		BranchName: "task/42-fix-login",
+		PRURL:      "https://github.com/o/r/pull/17",
+		PRNumber:   17,
+	}
+
+	p := NewPayload("task.blocked", 0, task, "needs input", map[string]interface{}{"reason": "permission"}, at)
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(p.JSON(), &got); err != nil {
+		t.Fatalf("payload is not valid JSON: %v", err)
+	}
+	if got["version"] != float64(PayloadVersion) || got["event"] != "task.blocked" || got["task_id"] != float64(42) {
+		t.Errorf("unexpected envelope: %v", got)
+	}
+	if got["timestamp"] != "2026-01-02T15:04:05Z" {
+		t.Errorf("timestamp = %v", got["timestamp"])
+	}
+	if md, _ := got["metadata"].(map[string]interface{}); md["reason"] != "permission" {
+		t.Errorf("metadata = %v", got["metadata"])
+	}
+
+	tp := got["task"].(map[string]interface{})
+	if tp["project"] != "myapp" || tp["branch"] != "task/42-fix-login" {
+		t.Errorf("unexpected task: %v", tp)
+	}
+	tags, _ := tp["tags"].([]interface{})
+	if len(tags) != 2 || tags[0] != "auth" || tags[1] != "urgent" {
+		t.Errorf("tags = %v, want [auth urgent]", tp["tags"])
+	}
+	if pr, _ := tp["pr"].(map[string]interface{}); pr["number"] != float64(17) {
+		t.Errorf("pr = %v", tp["pr"])
+	}
+}
+
+func TestNewPayload_NoTask(t *testing.T) {
+	p := NewPayload("task.deleted", 7, nil, "", nil, time.Time{})
+	if p.TaskID != 7 || p.Task != nil {
+		t.Errorf("expected task_id 7 and no task, got %+v", p)
+	}
+	if p.Timestamp.IsZero() {
+		t.Error("expected a timestamp to be filled in")
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(p.JSON(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got["task"]; ok {
+		t.Errorf("expected no task key, got %v", got)
+	}
+}
+
+func TestRunner_WritesPayloadToStdin(t *testing.T) {
+	hooksDir := t.TempDir()
+	out := filepath.Join(t.TempDir(), "payload.json")
+
+	// Write to a temp file first so the marker only appears once complete.
+	script := "#!/bin/sh\ncat > \"" + out + ".tmp\" && mv \"" + out + ".tmp\" \"" + out + "\"\n"
+	if err := os.WriteFile(filepath.Join(hooksDir, "task.done"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	r := newRunner(hooksDir, t.TempDir(), log.NewWithOptions(os.Stderr, log.Options{Level: log.FatalLevel}))
+	r.Run("task.done", &db.Task{ID: 42, Title: "t", Tags: "a,b"}, "done")
+	waitForFile(t, out)
+
+	var p Payload
+	if err := json.Unmarshal([]byte(readFile(t, out)), &p); err != nil {
+		t.Fatalf("hook stdin is not a payload: %v", err)
+	}
+	if p.Event != "task.done" || p.Task == nil || p.Task.ID != 42 || len(p.Task.Tags) != 2 {
+		t.Errorf("unexpected payload: %+v", p)
+	}
+}
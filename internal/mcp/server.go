@@ -417,8 +417,8 @@ func (s *Server) handleToolCall(id interface{}, params *toolCallParams) {
 		// Log the question
 		s.db.AppendTaskLog(s.taskID, "question", question)
 
-		// Update task status to blocked
-		s.db.UpdateTaskStatus(s.taskID, db.StatusBlocked)
+		// Update task status to blocked, carrying the question on the event
+		s.db.BlockTask(s.taskID, db.BlockedNeedsInput, question)
 
 		// Trigger callback
 		if s.onNeedsInput != nil {
@@ -0,0 +1,199 @@
+// Package notify shows native desktop notifications for task events. The
+// daemon runs a Watcher that follows the event_log, so notifications fire no
+// matter which process (CLI, TUI, Claude hook, MCP) changed the task.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/bborn/workflow/internal/config"
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/events"
+)
+
+// Events lists the event types notify_on accepts. "task.done" is accepted as
+// an alias for task.completed, matching the hook name.
+var Events = []string{events.TaskCreated, events.TaskStarted, events.TaskBlocked, events.TaskCompleted}
+
+// ParseEventList parses a comma-separated notify_on value into event types,
+// rejecting anything not in Events.
+func ParseEventList(value string) ([]string, error) {
+	var types []string
+	for _, part := range strings.Split(value, ",") {
+		t := strings.ToLower(strings.TrimSpace(part))
+		if t == "" {
+			continue
+		}
+		if t == events.TaskDone {
+			t = events.TaskCompleted
+		}
+		if !slices.Contains(Events, t) {
+			return nil, fmt.Errorf("unknown event %q (use %s)", t, strings.Join(Events, ", "))
+		}
+		if !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	if len(types) == 0 {
+		return nil, fmt.Errorf("no events given (use %s)", strings.Join(Events, ", "))
+	}
+	return types, nil
+}
+
+// Notification is a single desktop notification.
+type Notification struct {
+	Title string
+	Body  string
+}
+
+// Send shows n with osascript on macOS or notify-send on Linux. It is a
+// no-op when the notifier binary isn't installed or the platform has none.
+func Send(n Notification) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		if _, err := exec.LookPath("osascript"); err != nil {
+			return nil
+		}
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(n.Body), appleScriptString(n.Title))
+		cmd = exec.Command("osascript", "-e", script)
+	case "linux":
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return nil
+		}
+		cmd = exec.Command("notify-send", n.Title, n.Body)
+	default:
+		return nil
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", cmd.Args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// Build returns the notification for an event_log entry. task may be nil if
+// the task has since been deleted.
+func Build(task *db.Task, event db.LoggedEvent) Notification {
+	title := ""
+	if task != nil {
+		title = task.Title
+	}
+	var n Notification
+	switch event.EventType {
+	case events.TaskBlocked:
+		n.Title = fmt.Sprintf("TaskYou: #%d needs input", event.TaskID)
+		n.Body = title
+		// The event message is the pending question, when the blocker gave one.
+		if q := strings.TrimSpace(event.Message); q != "" && q != "status change" {
+			n.Body = strings.TrimSpace(title + "\n" + q)
+		}
+	case events.TaskCompleted:
+		n.Title = fmt.Sprintf("TaskYou: #%d done", event.TaskID)
+		n.Body = title
+	case events.TaskStarted:
+		n.Title = fmt.Sprintf("TaskYou: #%d started", event.TaskID)
+		n.Body = title
+	default:
+		n.Title = fmt.Sprintf("TaskYou: #%d %s", event.TaskID, strings.TrimPrefix(event.EventType, "task."))
+		n.Body = title
+	}
+	if n.Body == "" {
+		n.Body = event.Message
+	}
+	return n
+}
+
+// PollInterval is how often the Watcher checks the event_log.
+const PollInterval = 2 * time.Second
+
+// Watcher follows the event_log and sends a notification for each event the
+// notify_on setting selects, while notifications_enabled is "true". Settings
+// are re-read on every poll so changes apply without restarting the daemon.
+type Watcher struct {
+	db     *db.DB
+	logger *log.Logger
+	lastID int64
+
+	// send delivers notifications. Overridable in tests; defaults to Send.
+	send func(Notification) error
+}
+
+// NewWatcher returns a Watcher that only reports events logged after it was
+// created.
+func NewWatcher(database *db.DB, logger *log.Logger) *Watcher {
+	lastID, err := database.LatestEventID()
+	if err != nil {
+		logger.Warn("notifications: could not read event log", "error", err)
+	}
+	return &Watcher{db: database, logger: logger, lastID: lastID, send: Send}
+}
+
+// Run polls until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.Poll(); err != nil {
+				w.logger.Warn("notifications: poll failed", "error", err)
+			}
+		}
+	}
+}
+
+// Poll sends notifications for events logged since the previous poll. Events
+// seen while notifications are disabled are skipped, not queued up.
+func (w *Watcher) Poll() error {
+	events, err := w.db.ListEventsAfter(w.lastID)
+	if err != nil {
+		return err
+	}
+	if len(events) > 0 {
+		w.lastID = events[len(events)-1].ID
+	}
+	if enabled, _ := w.db.GetSetting(config.SettingNotificationsEnabled); enabled != "true" {
+		return nil
+	}
+
+	types := w.notifyOn()
+	for _, event := range events {
+		if !slices.Contains(types, event.EventType) {
+			continue
+		}
+		task, _ := w.db.GetTask(event.TaskID)
+		if err := w.send(Build(task, event)); err != nil {
+			w.logger.Debug("notifications: send failed", "event", event.EventType, "task", event.TaskID, "error", err)
+		}
+	}
+	return nil
+}
+
+// notifyOn returns the event types to notify on, falling back to
+// config.DefaultNotifyOn when notify_on is unset or invalid.
+func (w *Watcher) notifyOn() []string {
+	if v, _ := w.db.GetSetting(config.SettingNotifyOn); v != "" {
+		if types, err := ParseEventList(v); err == nil {
+			return types
+		}
+	}
+	types, _ := ParseEventList(config.DefaultNotifyOn)
+	return types
+}
@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/bborn/workflow/internal/config"
+	"github.com/bborn/workflow/internal/db"
+)
+
+func newTestWatcher(t *testing.T) (*Watcher, *db.DB, *[]Notification) {
+	t.Helper()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	var sent []Notification
+	w := NewWatcher(database, log.New(io.Discard))
+	w.send = func(n Notification) error {
+		sent = append(sent, n)
+		return nil
+	}
+	return w, database, &sent
+}
+
+func TestParseEventList(t *testing.T) {
+	got, err := ParseEventList(" task.blocked, task.done ,task.blocked")
+	if err != nil {
+		t.Fatalf("ParseEventList() error = %v", err)
+	}
+	if strings.Join(got, ",") != "task.blocked,task.completed" {
+		t.Errorf("got %v", got)
+	}
+	for _, bad := range []string{"", " , ", "task.exploded"} {
+		if _, err := ParseEventList(bad); err == nil {
+			t.Errorf("ParseEventList(%q) should fail", bad)
+		}
+	}
+}
+
+func TestBuildBlockedIncludesQuestion(t *testing.T) {
+	task := &db.Task{ID: 42, Title: "Migrate users"}
+	n := Build(task, db.LoggedEvent{EventType: "task.blocked", TaskID: 42, Message: "Drop the old table?"})
+	if !strings.Contains(n.Title, "#42") {
+		t.Errorf("title %q should include the task ID", n.Title)
+	}
+	if n.Body != "Migrate users\nDrop the old table?" {
+		t.Errorf("body = %q", n.Body)
+	}
+
+	n = Build(task, db.LoggedEvent{EventType: "task.blocked", TaskID: 42, Message: "status change"})
+	if n.Body != "Migrate users" {
+		t.Errorf("generic block should not show a question, got %q", n.Body)
+	}
+}
+
+func TestWatcherDisabledByDefault(t *testing.T) {
+	w, database, sent := newTestWatcher(t)
+
+	task := &db.Task{Title: "Quiet", Status: db.StatusProcessing, Project: "personal"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	database.BlockTask(task.ID, db.BlockedNeedsInput, "Which branch?")
+
+	if err := w.Poll(); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if len(*sent) != 0 {
+		t.Errorf("sent %d notifications while disabled", len(*sent))
+	}
+
+	// Events seen while disabled are not replayed once enabled.
+	database.SetSetting(config.SettingNotificationsEnabled, "true")
+	if err := w.Poll(); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if len(*sent) != 0 {
+		t.Errorf("replayed %d old notifications", len(*sent))
+	}
+}
+
+func TestWatcherNotifiesSelectedEvents(t *testing.T) {
+	w, database, sent := newTestWatcher(t)
+	database.SetSetting(config.SettingNotificationsEnabled, "true")
+
+	task := &db.Task{Title: "Ship it", Status: db.StatusProcessing, Project: "personal"}
+	if err := database.CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	database.BlockTask(task.ID, db.BlockedNeedsInput, "Deploy to prod?")
+	database.UpdateTaskStatus(task.ID, db.StatusDone)
+
+	if err := w.Poll(); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	// Default notify_on: blocked and completed, not created/updated.
+	if len(*sent) != 2 {
+		t.Fatalf("sent %d notifications, want 2: %+v", len(*sent), *sent)
+	}
+	if !strings.Contains((*sent)[0].Body, "Deploy to prod?") {
+		t.Errorf("blocked notification missing question: %+v", (*sent)[0])
+	}
+	if !strings.Contains((*sent)[1].Title, "done") {
+		t.Errorf("expected done notification, got %+v", (*sent)[1])
+	}
+
+	// Narrow to completions only.
+	*sent = nil
+	database.SetSetting(config.SettingNotifyOn, "task.done")
+	database.UpdateTaskStatus(task.ID, db.StatusBlocked)
+	database.UpdateTaskStatus(task.ID, db.StatusDone)
+	if err := w.Poll(); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if len(*sent) != 1 || !strings.Contains((*sent)[0].Title, "done") {
+		t.Errorf("want one done notification, got %+v", *sent)
+	}
+}
@@ -433,7 +433,7 @@ func Create(database *db.DB, opts Options) (*Result, error) {
 		if rootNames[s.Name] {
 			continue
 		}
-		if err := database.UpdateTaskStatus(byName[s.Name].ID, db.StatusBlocked); err != nil {
+		if err := database.BlockTask(byName[s.Name].ID, db.BlockedDependency, ""); err != nil {
 			return nil, fmt.Errorf("block %s step: %w", s.Name, err)
 		}
 		byName[s.Name].Status = db.StatusBlocked
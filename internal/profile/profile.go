@@ -0,0 +1,269 @@
+// Package profile maps named profiles ("work", "personal") to separate task
+// databases and namespaces the tmux sessions of each, so two profiles can run a
+// daemon and TUI side by side without seeing or clobbering each other's tasks.
+//
+// A profile is activated once per process (`ty --profile work ...`) by exporting
+// TY_PROFILE and WORKTREE_DB_PATH. It needn't be registered first: an unknown
+// name gets ~/.local/share/task/<name>.db. An arbitrary database (`ty --db
+// path`) likewise exports TY_DB and WORKTREE_DB_PATH. Everything downstream —
+// db.DefaultPath, the daemon pid file, spawned daemons, tmux windows and the
+// hooks/MCP servers they run — already follows WORKTREE_DB_PATH or inherits
+// the environment, so the profile only has to be resolved here.
+package profile
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// Env holds the active profile name. Empty means the default profile.
+const Env = "TY_PROFILE"
+
+// DBEnv holds a database path chosen with --db instead of a profile.
+const DBEnv = "TY_DB"
+
+// dbPathEnv is the DB override db.DefaultPath honors.
+const dbPathEnv = "WORKTREE_DB_PATH"
+
+// Profile is a named task database.
+type Profile struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// ValidateName rejects names that would be unsafe in file paths or tmux
+// session names.
+func ValidateName(name string) error {
+	if name == "default" {
+		return fmt.Errorf("profile name %q is reserved", name)
+	}
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q (use letters, digits, '-' and '_')", name)
+	}
+	return nil
+}
+
+// ConfigPath returns the profiles file, ~/.config/task/profiles.json.
+func ConfigPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "task", "profiles.json")
+}
+
+// DefaultDBPath is the database of a profile that wasn't registered, and where
+// `ty profiles add` puts one when no path is given: ~/.local/share/task/<name>.db,
+// next to the default tasks.db. The pid file is named after the profile, so
+// the two don't collide.
+func DefaultDBPath(name string) string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "share", "task", name+".db")
+}
+
+// Load returns the configured profiles sorted by name. A missing file is an
+// empty list.
+func Load() ([]Profile, error) {
+	data, err := os.ReadFile(ConfigPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read profiles: %w", err)
+	}
+	var profiles []Profile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", ConfigPath(), err)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles, nil
+}
+
+func save(profiles []Profile) error {
+	path := ConfigPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create config dir: %w", err)
+	}
+	data, err := json.MarshalIndent(profiles, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("write profiles: %w", err)
+	}
+	return nil
+}
+
+// Get returns the named profile, or nil if it isn't configured.
+func Get(name string) (*Profile, error) {
+	profiles, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	for i := range profiles {
+		if profiles[i].Name == name {
+			return &profiles[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Add registers a profile. An empty dbPath uses DefaultDBPath.
+func Add(name, dbPath string) (*Profile, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	profiles, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range profiles {
+		if p.Name == name {
+			return nil, fmt.Errorf("profile %q already exists", name)
+		}
+	}
+	if dbPath == "" {
+		dbPath = DefaultDBPath(name)
+	}
+	abs, err := filepath.Abs(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("resolve path: %w", err)
+	}
+	p := Profile{Name: name, Path: abs}
+	if err := save(append(profiles, p)); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Remove unregisters a profile. The database file is left in place.
+func Remove(name string) error {
+	profiles, err := Load()
+	if err != nil {
+		return err
+	}
+	kept := profiles[:0]
+	found := false
+	for _, p := range profiles {
+		if p.Name == name {
+			found = true
+			continue
+		}
+		kept = append(kept, p)
+	}
+	if !found {
+		return fmt.Errorf("profile %q not found", name)
+	}
+	return save(kept)
+}
+
+// Active returns the active profile name ("" for the default profile).
+func Active() string {
+	return os.Getenv(Env)
+}
+
+// Activate makes name the active profile for this process and every child it
+// spawns. A registered profile uses its configured path and any other valid
+// name uses DefaultDBPath. An explicit WORKTREE_DB_PATH is replaced by the
+// profile's path; see ActivateFromEnv for when it isn't.
+func Activate(name string) error {
+	p, err := Get(name)
+	if err != nil {
+		return err
+	}
+	path := ""
+	if p != nil {
+		path = p.Path
+	} else {
+		if err := ValidateName(name); err != nil {
+			return err
+		}
+		path = DefaultDBPath(name)
+	}
+	if err := os.Setenv(Env, name); err != nil {
+		return err
+	}
+	os.Unsetenv(DBEnv)
+	return os.Setenv(dbPathEnv, path)
+}
+
+// ActivateFromEnv activates the database the environment selects, for a
+// process started without --profile or --db. TY_DB comes first, then an
+// explicit WORKTREE_DB_PATH, which is left alone, then TY_PROFILE. Processes a
+// profile spawns inherit a matching TY_PROFILE and WORKTREE_DB_PATH, so they
+// resolve to the same database either way.
+func ActivateFromEnv() error {
+	switch {
+	case ActiveDB() != "":
+		return ActivateDB(ActiveDB())
+	case os.Getenv(dbPathEnv) != "":
+		return nil
+	case Active() != "":
+		return Activate(Active())
+	}
+	return nil
+}
+
+// ActiveDB returns the database chosen with --db, or "" if none.
+func ActiveDB() string {
+	return os.Getenv(DBEnv)
+}
+
+// ActivateDB makes path the database for this process and every child it
+// spawns, without a registered profile. It takes the place of any active
+// profile.
+func ActivateDB(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("resolve path: %w", err)
+	}
+	if err := os.Setenv(DBEnv, abs); err != nil {
+		return err
+	}
+	os.Unsetenv(Env)
+	return os.Setenv(dbPathEnv, abs)
+}
+
+// DBTag is a short, stable name for the database chosen with --db, used to
+// namespace its tmux sessions and daemon pid file. It is "" unless --db is
+// active.
+func DBTag() string {
+	path := ActiveDB()
+	if path == "" {
+		return ""
+	}
+	sum := sha1.Sum([]byte(path))
+	return hex.EncodeToString(sum[:4])
+}
+
+// DaemonSessionPrefix returns the tmux session prefix holding task windows for
+// the active profile: "task-daemon-" by default, "task-daemon@work-" for the
+// "work" profile, and "task-daemon_<DBTag>-" for a --db database. The '@' and
+// '_' keep the default profile's "task-daemon-" prefix from matching another
+// profile's sessions.
+func DaemonSessionPrefix() string {
+	return sessionPrefix("task-daemon")
+}
+
+// UISessionPrefix is DaemonSessionPrefix for the TUI's task-ui sessions.
+func UISessionPrefix() string {
+	return sessionPrefix("task-ui")
+}
+
+func sessionPrefix(base string) string {
+	if name := Active(); name != "" {
+		return base + "@" + name + "-"
+	}
+	if tag := DBTag(); tag != "" {
+		return base + "_" + tag + "-"
+	}
+	return base + "-"
+}
@@ -0,0 +1,187 @@
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupProfileEnv(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	t.Setenv(Env, "")
+	t.Setenv(dbPathEnv, "")
+	t.Setenv(DBEnv, "")
+	return dir
+}
+
+func TestAddListRemove(t *testing.T) {
+	home := setupProfileEnv(t)
+
+	if profiles, err := Load(); err != nil || len(profiles) != 0 {
+		t.Fatalf("expected no profiles, got %v, %v", profiles, err)
+	}
+
+	work, err := Add("work", "")
+	if err != nil {
+		t.Fatalf("add work: %v", err)
+	}
+	if want := filepath.Join(home, ".local", "share", "task", "work.db"); work.Path != want {
+		t.Errorf("expected default path %s, got %s", want, work.Path)
+	}
+	if _, err := Add("personal", filepath.Join(home, "p.db")); err != nil {
+		t.Fatalf("add personal: %v", err)
+	}
+	if _, err := Add("work", ""); err == nil {
+		t.Error("expected duplicate add to fail")
+	}
+	if _, err := Add("bad name", ""); err == nil {
+		t.Error("expected invalid name to fail")
+	}
+
+	profiles, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles) != 2 || profiles[0].Name != "personal" || profiles[1].Name != "work" {
+		t.Fatalf("expected [personal work], got %+v", profiles)
+	}
+
+	if err := Remove("personal"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := Remove("personal"); err == nil {
+		t.Error("expected removing a missing profile to fail")
+	}
+	if p, _ := Get("personal"); p != nil {
+		t.Errorf("expected personal to be gone, got %+v", p)
+	}
+}
+
+func TestActivateNamespacesSessions(t *testing.T) {
+	setupProfileEnv(t)
+
+	if DaemonSessionPrefix() != "task-daemon-" || UISessionPrefix() != "task-ui-" {
+		t.Fatalf("unexpected default prefixes %q %q", DaemonSessionPrefix(), UISessionPrefix())
+	}
+
+	if err := Activate("bad name"); err == nil {
+		t.Fatal("expected activating an invalid profile name to fail")
+	}
+
+	work, err := Add("work", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Activate("work"); err != nil {
+		t.Fatalf("activate: %v", err)
+	}
+	if Active() != "work" || os.Getenv(dbPathEnv) != work.Path {
+		t.Errorf("expected work active with DB %s, got %q / %q", work.Path, Active(), os.Getenv(dbPathEnv))
+	}
+
+	prefix := DaemonSessionPrefix()
+	if prefix != "task-daemon@work-" {
+		t.Errorf("unexpected daemon prefix %q", prefix)
+	}
+	// The default profile's prefix must not claim this profile's sessions.
+	if strings.HasPrefix(prefix+"123", "task-daemon-") {
+		t.Error("default prefix matches a profile session")
+	}
+}
+
+func TestActivateDB(t *testing.T) {
+	setupProfileEnv(t)
+
+	work, err := Add("work", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Activate("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "side.db")
+	if err := ActivateDB(path); err != nil {
+		t.Fatalf("activate db: %v", err)
+	}
+	if Active() != "" {
+		t.Errorf("--db should replace the active profile, got %q", Active())
+	}
+	if ActiveDB() != path || os.Getenv(dbPathEnv) != path {
+		t.Errorf("expected DB %s, got %q / %q", path, ActiveDB(), os.Getenv(dbPathEnv))
+	}
+
+	prefix := DaemonSessionPrefix()
+	if prefix != "task-daemon_"+DBTag()+"-" || len(DBTag()) != 8 {
+		t.Errorf("unexpected daemon prefix %q", prefix)
+	}
+	if strings.HasPrefix(prefix+"123", "task-daemon-") {
+		t.Error("default prefix matches a --db session")
+	}
+
+	// A different database gets a different namespace.
+	if err := ActivateDB(filepath.Join(dir, "other.db")); err != nil {
+		t.Fatal(err)
+	}
+	if DaemonSessionPrefix() == prefix {
+		t.Error("two databases share a session prefix")
+	}
+
+	// Activating a profile again clears --db.
+	if err := Activate("work"); err != nil {
+		t.Fatal(err)
+	}
+	if ActiveDB() != "" || DBTag() != "" || os.Getenv(dbPathEnv) != work.Path {
+		t.Errorf("expected work active, got db %q, path %q", ActiveDB(), os.Getenv(dbPathEnv))
+	}
+}
+
+func TestActivateUnregisteredProfile(t *testing.T) {
+	home := setupProfileEnv(t)
+
+	if err := Activate("side"); err != nil {
+		t.Fatalf("activate: %v", err)
+	}
+	want := filepath.Join(home, ".local", "share", "task", "side.db")
+	if Active() != "side" || os.Getenv(dbPathEnv) != want {
+		t.Errorf("expected side active with DB %s, got %q / %q", want, Active(), os.Getenv(dbPathEnv))
+	}
+}
+
+func TestActivateFromEnvPrecedence(t *testing.T) {
+	home := setupProfileEnv(t)
+	explicit := filepath.Join(home, "explicit.db")
+	side := filepath.Join(home, "side.db")
+
+	// TY_PROFILE alone selects the profile's database.
+	t.Setenv(Env, "work")
+	if err := ActivateFromEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if got := os.Getenv(dbPathEnv); got != DefaultDBPath("work") {
+		t.Errorf("TY_PROFILE: expected %s, got %s", DefaultDBPath("work"), got)
+	}
+
+	// An explicit WORKTREE_DB_PATH beats TY_PROFILE.
+	t.Setenv(dbPathEnv, explicit)
+	if err := ActivateFromEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if got := os.Getenv(dbPathEnv); got != explicit {
+		t.Errorf("WORKTREE_DB_PATH: expected %s, got %s", explicit, got)
+	}
+
+	// TY_DB beats both.
+	t.Setenv(DBEnv, side)
+	if err := ActivateFromEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if got := os.Getenv(dbPathEnv); got != side || Active() != "" {
+		t.Errorf("TY_DB: expected %s with no profile, got %s / %q", side, got, Active())
+	}
+}
@@ -101,6 +101,9 @@ type KeyMap struct {
 	OpenBrowser key.Binding
 	// Open PR
 	OpenPR key.Binding
+	// Reorder the selected task within its column
+	MoveTaskUp   key.Binding
+	MoveTaskDown key.Binding
 }
 
 // ShortHelp returns key bindings to show in the mini help.
@@ -117,7 +120,7 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 		{k.Enter, k.New, k.Queue, k.QueueDangerous, k.Close},
 		{k.Retry, k.Archive, k.Delete, k.OpenWorktree, k.OpenBrowser},
 		{k.Filter, k.CommandPalette, k.Settings, k.Routines},
-		{k.ChangeStatus, k.TogglePin, k.Refresh, k.Help},
+		{k.ChangeStatus, k.TogglePin, k.MoveTaskUp, k.MoveTaskDown, k.Refresh, k.Help},
 		{k.Quit},
 	}
 }
@@ -277,6 +280,14 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("G"),
 			key.WithHelp("G", "open PR"),
 		),
+		MoveTaskUp: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", "move task up"),
+		),
+		MoveTaskDown: key.NewBinding(
+			key.WithKeys("J"),
+			key.WithHelp("J", "move task down"),
+		),
 	}
 }
 
@@ -341,6 +352,8 @@ func ApplyKeybindingsConfig(km KeyMap, cfg *config.KeybindingsConfig) KeyMap {
 	km.CollapseDone = applyBinding(km.CollapseDone, cfg.CollapseDone)
 	km.OpenBrowser = applyBinding(km.OpenBrowser, cfg.OpenBrowser)
 	km.OpenPR = applyBinding(km.OpenPR, cfg.OpenPR)
+	km.MoveTaskUp = applyBinding(km.MoveTaskUp, cfg.MoveTaskUp)
+	km.MoveTaskDown = applyBinding(km.MoveTaskDown, cfg.MoveTaskDown)
 
 	return km
 }
@@ -1018,6 +1031,9 @@ func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.kanban.SetRunningProcesses(running)
 		m.kanban.SetTasksNeedingInput(m.tasksNeedingInput)
 		m.kanban.SetBlockedByDeps(msg.blockedByDeps)
+		if m.db != nil {
+			m.kanban.SetIdleSuspendTimeout(executor.SuspendIdleTimeout(m.db))
+		}
 
 		// Refresh per-agent activity lines for live mode (cheap no-op when off).
 		m.refreshLatestActivity()
@@ -1205,12 +1221,22 @@ func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Task was moved successfully - stay on dashboard
 			m.selectedTask = msg.newTask
 			m.notification = fmt.Sprintf("%s Task moved to %s as #%d", IconDone(), msg.newTask.Project, msg.newTask.ID)
+			if msg.warning != "" {
+				m.notification += " (" + msg.warning + ")"
+			}
 			m.notifyUntil = time.Now().Add(5 * time.Second)
 			cmds = append(cmds, m.loadTasks())
 		} else {
 			m.err = msg.err
 		}
 
+	case taskReorderedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			break
+		}
+		cmds = append(cmds, m.loadTasks())
+
 	case taskPinnedMsg:
 		if msg.err != nil {
 			m.err = msg.err
@@ -2020,6 +2046,12 @@ func (m *AppModel) updateDashboard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case key.Matches(msg, m.keys.MoveTaskUp):
+		return m, m.moveTaskInColumn(-1)
+
+	case key.Matches(msg, m.keys.MoveTaskDown):
+		return m, m.moveTaskInColumn(1)
+
 	case key.Matches(msg, m.keys.Retry):
 		if task := m.kanban.SelectedTask(); task != nil {
 			// Allow retry for blocked, done, or backlog tasks
@@ -4175,6 +4207,10 @@ type taskPinnedMsg struct {
 	err  error
 }
 
+type taskReorderedMsg struct {
+	err error
+}
+
 type taskSummaryMsg struct {
 	taskID  int64
 	summary string
@@ -4500,7 +4536,7 @@ func (m *AppModel) archiveTask(id int64) tea.Cmd {
 		}
 
 		// Update status to archived immediately for instant UI feedback
-		err = database.UpdateTaskStatus(id, db.StatusArchived)
+		err = database.ArchiveTask(id)
 		if err != nil {
 			return taskArchivedMsg{err: err}
 		}
@@ -4547,8 +4583,8 @@ func (m *AppModel) unarchiveTask(id int64) tea.Cmd {
 			}
 		}
 
-		// Update status back to backlog (user can then queue it if they want)
-		err = database.UpdateTaskStatus(id, db.StatusBacklog)
+		// Restore the status the task had before it was archived
+		_, err = database.UnarchiveTask(id)
 		if err == nil {
 			if task, _ := database.GetTask(id); task != nil {
 				exec.NotifyTaskChange("status_changed", task)
@@ -4773,6 +4809,7 @@ func (m *AppModel) latestChoicePrompt(taskID int64) (string, bool) {
 type taskMovedMsg struct {
 	newTask *db.Task
 	oldID   int64
+	warning string // the move worked but something around it didn't
 	err     error
 }
 
@@ -4782,6 +4819,12 @@ func (m *AppModel) moveTaskToProject(newTaskData *db.Task, oldTask *db.Task) tea
 	database := m.db
 	exec := m.executor
 	return func() tea.Msg {
+		// Snapshot the old task so 'ty undo' can bring it back
+		undoID, err := database.RecordUndo(db.UndoMove, oldTask.ID)
+		if err != nil {
+			return taskMovedMsg{err: err}
+		}
+
 		// First, clean up the old task's resources
 
 		// Kill Claude process to free memory
@@ -4807,8 +4850,9 @@ func (m *AppModel) moveTaskToProject(newTaskData *db.Task, oldTask *db.Task) tea
 		}
 
 		// Delete the old task from database
-		err := database.DeleteTask(oldTask.ID)
+		err = database.DeleteTask(oldTask.ID)
 		if err != nil {
+			database.DeleteUndo(undoID)
 			return taskMovedMsg{err: fmt.Errorf("delete old task: %w", err)}
 		}
 
@@ -4830,14 +4874,22 @@ func (m *AppModel) moveTaskToProject(newTaskData *db.Task, oldTask *db.Task) tea
 
 		err = database.CreateTask(newTaskData)
 		if err != nil {
-			return taskMovedMsg{err: fmt.Errorf("create new task: %w", err)}
+			// Put the old task back rather than leave the move half done.
+			if rerr := database.RevertUndo(undoID); rerr != nil {
+				return taskMovedMsg{err: fmt.Errorf("create new task: %w (restoring #%d also failed: %v)", err, oldTask.ID, rerr)}
+			}
+			return taskMovedMsg{err: fmt.Errorf("create new task: %w (task #%d was restored)", err, oldTask.ID)}
+		}
+		warning := ""
+		if err := database.SetUndoNewTaskID(undoID, newTaskData.ID); err != nil {
+			warning = "this move can't be undone: " + err.Error()
 		}
 
 		// Notify about the changes
 		exec.NotifyTaskChange("deleted", oldTask)
 		exec.NotifyTaskChange("created", newTaskData)
 
-		return taskMovedMsg{newTask: newTaskData, oldID: oldTask.ID, err: nil}
+		return taskMovedMsg{newTask: newTaskData, oldID: oldTask.ID, warning: warning}
 	}
 }
 
@@ -4879,6 +4931,20 @@ func (m *AppModel) toggleTaskPinned(id int64) tea.Cmd {
 	}
 }
 
+// moveTaskInColumn swaps the selected task with its neighbour dir rows away
+// (-1 up, 1 down) by saving a manual position for it (see db.ReorderTask).
+func (m *AppModel) moveTaskInColumn(dir int) tea.Cmd {
+	task := m.kanban.SelectedTask()
+	neighbor := m.kanban.NeighborTask(dir)
+	if task == nil || neighbor == nil {
+		return nil
+	}
+	database := m.db
+	return func() tea.Msg {
+		return taskReorderedMsg{err: database.ReorderTask(task.ID, neighbor.ID, dir > 0)}
+	}
+}
+
 func (m *AppModel) retryTaskWithAttachments(id int64, feedback string, attachmentPaths []string, dangerous bool) tea.Cmd {
 	database := m.db
 	exec := m.executor
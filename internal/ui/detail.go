@@ -25,6 +25,7 @@ import (
 	"github.com/bborn/workflow/internal/executor"
 	"github.com/bborn/workflow/internal/github"
 	"github.com/bborn/workflow/internal/pipeline"
+	"github.com/bborn/workflow/internal/profile"
 	"github.com/bborn/workflow/internal/qmd"
 )
 
@@ -132,15 +133,18 @@ func isShellCommand(cmd string) bool {
 
 // DetailModel represents the task detail view.
 type DetailModel struct {
-	task     *db.Task
-	logs     []*db.TaskLog
-	database *db.DB
-	executor *executor.Executor
-	viewport viewport.Model
-	width    int
-	height   int
-	ready    bool
-	prInfo   *github.PRInfo
+	task *db.Task
+	logs []*db.TaskLog
+	// pinnedNotes are loaded separately from logs so they stay visible however
+	// long the log grows.
+	pinnedNotes []*db.TaskLog
+	database    *db.DB
+	executor    *executor.Executor
+	viewport    viewport.Model
+	width       int
+	height      int
+	ready       bool
+	prInfo      *github.PRInfo
 
 	// Task position in column (1-indexed)
 	positionInColumn int
@@ -277,9 +281,10 @@ type paneWaitForExecutorMsg struct{}
 
 // logsLoadedMsg is sent when async log loading completes.
 type logsLoadedMsg struct {
-	taskID   int64
-	logs     []*db.TaskLog
-	logCount int
+	taskID      int64
+	logs        []*db.TaskLog
+	pinnedNotes []*db.TaskLog
+	logCount    int
 }
 
 type spinnerTickMsg struct{}
@@ -660,7 +665,8 @@ func (m *DetailModel) Refresh() tea.Cmd {
 		database := m.database
 		cmd = func() tea.Msg {
 			logs, _ := database.GetTaskLogs(taskID, 500)
-			return logsLoadedMsg{taskID: taskID, logs: logs, logCount: logCount}
+			pinnedNotes, _ := database.GetPinnedNotes(taskID)
+			return logsLoadedMsg{taskID: taskID, logs: logs, pinnedNotes: pinnedNotes, logCount: logCount}
 		}
 	}
 
@@ -733,6 +739,7 @@ func (m *DetailModel) HandleLogsLoaded(msg logsLoadedMsg) {
 	}
 	if msg.logs != nil {
 		m.logs = msg.logs
+		m.pinnedNotes = msg.pinnedNotes
 		m.lastLogCount = msg.logCount
 		if m.ready {
 			m.setViewportContent()
@@ -777,6 +784,7 @@ func NewDetailModel(t *db.Task, database *db.DB, exec *executor.Executor, width,
 	// Load logs
 	logs, _ := database.GetTaskLogs(t.ID, 100)
 	m.logs = logs
+	m.pinnedNotes, _ = database.GetPinnedNotes(t.ID)
 
 	m.initViewport()
 
@@ -1178,7 +1186,7 @@ func (m *DetailModel) findTaskWindow() string {
 		sessionName, windowID, name := parts[0], parts[1], parts[2]
 
 		// Only look in daemon sessions
-		if !strings.HasPrefix(sessionName, "task-daemon-") {
+		if !strings.HasPrefix(sessionName, profile.DaemonSessionPrefix()) {
 			continue
 		}
 
@@ -3185,6 +3193,21 @@ func (m *DetailModel) renderContent() string {
 	// Dimmed style for unfocused content
 	dimmedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280"))
 
+	// Pinned notes (ty note --pin) come first so they can't be missed.
+	if len(m.pinnedNotes) > 0 {
+		b.WriteString(Bold.Render("Pinned Notes"))
+		b.WriteString("\n\n")
+		for _, note := range m.pinnedNotes {
+			line := "📌 " + note.Content
+			if !m.focused {
+				line = dimmedStyle.Render(line)
+			}
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
 	// Description
 	if t.Body != "" && strings.TrimSpace(t.Body) != "" {
 		// Labels always use full opacity for clarity and accessibility
@@ -3364,6 +3387,10 @@ func (m *DetailModel) renderContent() string {
 				icon = "👤"
 			case "output":
 				icon = "📤"
+			case db.NoteLineType:
+				icon = "📝"
+			case db.PinnedNoteLineType:
+				icon = "📌"
 			}
 
 			var line string
@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 
@@ -27,6 +28,24 @@ func emptyColumnMessage(status string) string {
 	}
 }
 
+// blockedReasonBadge returns the card badge for a blocked task's reason: a red
+// "✗" for errors, a yellow "?" for input and "!" for permission prompts. It
+// returns "" for other tasks and for dependency waits (shown by the lock icon).
+func blockedReasonBadge(task *db.Task) (string, lipgloss.Color) {
+	if task.Status != db.StatusBlocked {
+		return "", ""
+	}
+	switch task.BlockedReason {
+	case db.BlockedError:
+		return "✗", ColorError
+	case db.BlockedNeedsInput:
+		return "?", ColorWarning
+	case db.BlockedNeedsPermission:
+		return "!", ColorWarning
+	}
+	return "", ""
+}
+
 // KanbanColumn represents a column in the kanban board.
 type KanbanColumn struct {
 	Title  string
@@ -79,6 +98,10 @@ type KanbanBoard struct {
 	// processing tasks.
 	latestActivity map[int64]*db.TaskLog
 	spinnerFrame   int
+
+	// idleSuspendTimeout drives the "suspends in …" hint on blocked cards
+	// (0 = don't show it).
+	idleSuspendTimeout time.Duration
 }
 
 // cardHeight is the number of vertical lines a task card occupies, including
@@ -529,6 +552,21 @@ func (k *KanbanBoard) MoveDown() {
 	k.ensureSelectedVisible()
 }
 
+// NeighborTask returns the task offset rows from the selection in the current
+// column (-1 is the one above), or nil past either end. Unlike MoveUp and
+// MoveDown it does not wrap.
+func (k *KanbanBoard) NeighborTask(offset int) *db.Task {
+	if k.selectedCol >= len(k.columns) {
+		return nil
+	}
+	col := k.columns[k.selectedCol]
+	row := k.selectedRow + offset
+	if row < 0 || row >= len(col.Tasks) {
+		return nil
+	}
+	return col.Tasks[row]
+}
+
 // JumpToPinned moves selection to the first pinned task in the current column.
 // If there are no pinned tasks, moves to the top of the column.
 func (k *KanbanBoard) JumpToPinned() {
@@ -780,6 +818,7 @@ func (k *KanbanBoard) renderSignature() uint64 {
 func (k *KanbanBoard) hashTaskCard(h *sigHasher, t *db.Task) {
 	h.u64(uint64(t.ID))
 	h.str(t.Status)
+	h.str(t.BlockedReason)
 	h.str(t.Project)
 	h.str(t.Title)
 	h.boolean(t.Pinned)
@@ -810,6 +849,9 @@ func (k *KanbanBoard) hashTaskCard(h *sigHasher, t *db.Task) {
 		h.boolean(false)
 	}
 	h.int(taskElapsedMinutes(t))
+	if t.Status == db.StatusBlocked {
+		h.int(int(k.idleSuspendTimeout / time.Minute))
+	}
 	if t.Status == db.StatusProcessing {
 		h.int(k.spinnerFrame)
 	}
@@ -1315,6 +1357,15 @@ func (k *KanbanBoard) renderTaskCard(task *db.Task, width int, isSelected bool)
 			indicators = append(indicators, FgStyle(ColorCode).Render("●"))
 		}
 	}
+	// Blocked reason badge, so a failed task reads differently from one that
+	// is only waiting on the user. Dependencies already show the lock icon.
+	if glyph, color := blockedReasonBadge(task); glyph != "" {
+		if isSelected {
+			indicators = append(indicators, glyph)
+		} else {
+			indicators = append(indicators, FgStyle(color).Render(glyph))
+		}
+	}
 	if task.Pinned {
 		if isSelected {
 			indicators = append(indicators, IconPin())
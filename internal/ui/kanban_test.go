@@ -1517,3 +1517,24 @@ func TestKanbanBoard_LeakedOriginColumnSnapsFocus(t *testing.T) {
 		t.Logf("Confirmed: leaked originColumn snaps focus to col %d (expected kanban behavior)", board.selectedCol)
 	}
 }
+
+// TestKanbanBoard_NeighborTask tests the tasks the reorder keys swap with.
+func TestKanbanBoard_NeighborTask(t *testing.T) {
+	board := NewKanbanBoard(100, 50)
+	board.SetTasks([]*db.Task{
+		{ID: 1, Title: "Task 1", Status: db.StatusBacklog},
+		{ID: 2, Title: "Task 2", Status: db.StatusBacklog},
+	})
+
+	board.selectedRow = 0
+	if n := board.NeighborTask(-1); n != nil {
+		t.Errorf("NeighborTask(-1) at the top = %v, want nil (no wrap)", n)
+	}
+	if n := board.NeighborTask(1); n == nil || n.ID != 2 {
+		t.Errorf("NeighborTask(1) = %v, want task 2", n)
+	}
+	board.selectedRow = 1
+	if n := board.NeighborTask(1); n != nil {
+		t.Errorf("NeighborTask(1) at the bottom = %v, want nil (no wrap)", n)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/executor"
 )
 
 // The board renders a live sub-line on every card: an activity line for
@@ -21,6 +22,12 @@ func (k *KanbanBoard) SetLatestActivity(activity map[int64]*db.TaskLog) {
 	k.latestActivity = activity
 }
 
+// SetIdleSuspendTimeout sets the idle_suspend_timeout used to show when a
+// blocked task will be suspended.
+func (k *KanbanBoard) SetIdleSuspendTimeout(timeout time.Duration) {
+	k.idleSuspendTimeout = timeout
+}
+
 // AdvanceSpinner moves the running-task spinner to its next animation frame.
 func (k *KanbanBoard) AdvanceSpinner() {
 	k.spinnerFrame++
@@ -102,6 +109,11 @@ func (k *KanbanBoard) subLineContent(task *db.Task) (string, lipgloss.Color) {
 		if k.NeedsInput(task.ID) {
 			return IconBlocked() + " needs your input", ColorWarning
 		}
+		if k.idleSuspendTimeout > 0 {
+			if st, ok := executor.IdleSuspendStatus(task, k.latestActivity[task.ID], k.idleSuspendTimeout); ok {
+				return taskAgeHint(task) + " · " + st.Hint(time.Now()), ColorMuted
+			}
+		}
 		return taskAgeHint(task), ColorMuted
 	default:
 		return taskAgeHint(task), ColorMuted
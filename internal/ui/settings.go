@@ -421,7 +421,7 @@ func (m *SettingsModel) showTaskTypeForm(taskType *db.TaskType) (*SettingsModel,
 			huh.NewText().
 				Key("instructions").
 				Title("Prompt Template").
-				Description("Use {{title}}, {{body}}, {{project}}, {{project_instructions}}, {{attachments}}, {{history}}").
+				Description("Use {{title}}, {{body}}, {{project}}, {{project_instructions}}, {{memories}}, {{attachments}}, {{history}}").
 				Placeholder("Instructions...").
 				CharLimit(10000).
 				Value(&m.taskTypeFormInstructions),
@@ -0,0 +1,125 @@
+// Package usage reads token usage out of Claude Code session transcripts and
+// estimates what it cost.
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bborn/workflow/internal/db"
+)
+
+// price is a model's list price in USD per million tokens.
+type price struct {
+	input, output, cacheWrite, cacheRead float64
+}
+
+// prices maps model name prefixes to list prices, most specific first.
+// Unknown models are priced like Sonnet.
+var prices = []struct {
+	prefix string
+	price  price
+}{
+	{"claude-opus-4-5", price{5, 25, 6.25, 0.50}},
+	{"claude-opus-4-6", price{5, 25, 6.25, 0.50}},
+	{"claude-opus-4", price{15, 75, 18.75, 1.50}},
+	{"claude-3-opus", price{15, 75, 18.75, 1.50}},
+	{"claude-haiku-4", price{1, 5, 1.25, 0.10}},
+	{"claude-3-5-haiku", price{0.80, 4, 1, 0.08}},
+	{"claude-3-haiku", price{0.25, 1.25, 0.30, 0.03}},
+}
+
+var defaultPrice = price{3, 15, 3.75, 0.30}
+
+func priceFor(model string) price {
+	for _, p := range prices {
+		if strings.HasPrefix(model, p.prefix) {
+			return p.price
+		}
+	}
+	return defaultPrice
+}
+
+// Cost estimates the cost in USD of one message's usage.
+func Cost(model string, input, cacheWrite, cacheRead, output int64) float64 {
+	p := priceFor(model)
+	return (float64(input)*p.input +
+		float64(cacheWrite)*p.cacheWrite +
+		float64(cacheRead)*p.cacheRead +
+		float64(output)*p.output) / 1e6
+}
+
+// transcriptLine is the part of a transcript .jsonl entry that carries usage.
+type transcriptLine struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Message   struct {
+		ID    string `json:"id"`
+		Model string `json:"model"`
+		Usage *struct {
+			InputTokens              int64 `json:"input_tokens"`
+			CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+			OutputTokens             int64 `json:"output_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// ParseClaudeTranscript returns one entry per assistant message in a Claude
+// Code transcript. Claude writes a line per content block, each repeating the
+// message's usage, so lines are merged by message ID. Lines without usage
+// (user turns, summaries, synthetic messages) and malformed lines are skipped.
+func ParseClaudeTranscript(r io.Reader) ([]db.UsageEntry, error) {
+	var entries []db.UsageEntry
+	index := make(map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var line transcriptLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		u := line.Message.Usage
+		if line.Type != "assistant" || line.Message.ID == "" || u == nil {
+			continue
+		}
+		input := u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+		if input == 0 && u.OutputTokens == 0 {
+			continue
+		}
+		entry := db.UsageEntry{
+			MessageID:    line.Message.ID,
+			Model:        line.Message.Model,
+			InputTokens:  input,
+			OutputTokens: u.OutputTokens,
+			CostUSD:      Cost(line.Message.Model, u.InputTokens, u.CacheCreationInputTokens, u.CacheReadInputTokens, u.OutputTokens),
+			CreatedAt:    line.Timestamp,
+		}
+		if i, ok := index[entry.MessageID]; ok {
+			// Later lines of a streamed message can carry a larger output count.
+			if entry.OutputTokens >= entries[i].OutputTokens {
+				entry.CreatedAt = entries[i].CreatedAt
+				entries[i] = entry
+			}
+			continue
+		}
+		index[entry.MessageID] = len(entries)
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// ParseClaudeTranscriptFile is ParseClaudeTranscript for a transcript path.
+func ParseClaudeTranscriptFile(path string) ([]db.UsageEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseClaudeTranscript(f)
+}
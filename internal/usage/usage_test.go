@@ -0,0 +1,46 @@
+package usage
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseClaudeTranscript(t *testing.T) {
+	transcript := strings.Join([]string{
+		`{"type":"user","message":{"role":"user","content":"hi"}}`,
+		// One message streamed as two content blocks repeating its usage.
+		`{"type":"assistant","timestamp":"2026-01-02T10:00:00Z","message":{"id":"msg_1","model":"claude-sonnet-4-5","usage":{"input_tokens":100,"cache_creation_input_tokens":1000,"cache_read_input_tokens":2000,"output_tokens":5}}}`,
+		`{"type":"assistant","timestamp":"2026-01-02T10:00:01Z","message":{"id":"msg_1","model":"claude-sonnet-4-5","usage":{"input_tokens":100,"cache_creation_input_tokens":1000,"cache_read_input_tokens":2000,"output_tokens":50}}}`,
+		`{"type":"assistant","timestamp":"2026-01-02T10:01:00Z","message":{"id":"msg_2","model":"claude-opus-4-1","usage":{"input_tokens":10,"output_tokens":20}}}`,
+		// No usage, synthetic zero usage, and garbage are ignored.
+		`{"type":"assistant","message":{"id":"msg_3","model":"claude-sonnet-4-5"}}`,
+		`{"type":"assistant","message":{"id":"msg_4","model":"<synthetic>","usage":{"input_tokens":0,"output_tokens":0}}}`,
+		`{"type":"summary","summary":"x"}`,
+		`not json`,
+	}, "\n")
+
+	entries, err := ParseClaudeTranscript(strings.NewReader(transcript))
+	if err != nil {
+		t.Fatalf("ParseClaudeTranscript: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %+v", len(entries), entries)
+	}
+
+	first := entries[0]
+	if first.MessageID != "msg_1" || first.InputTokens != 3100 || first.OutputTokens != 50 {
+		t.Errorf("unexpected merged entry: %+v", first)
+	}
+	if first.CreatedAt.Format("15:04:05") != "10:00:00" {
+		t.Errorf("expected the first line's timestamp, got %v", first.CreatedAt)
+	}
+	wantCost := (100*3 + 1000*3.75 + 2000*0.30 + 50*15) / 1e6
+	if math.Abs(first.CostUSD-wantCost) > 1e-12 {
+		t.Errorf("cost = %v, want %v", first.CostUSD, wantCost)
+	}
+
+	if got, want := entries[1].CostUSD, (10*15+20*75)/1e6; math.Abs(got-want) > 1e-12 {
+		t.Errorf("opus cost = %v, want %v", got, want)
+	}
+}
@@ -21,21 +21,44 @@ type BoardColumn struct {
 	Label  string       `json:"label"`
 	Count  int          `json:"count"`
 	Tasks  []BoardEntry `json:"tasks"`
+	// WIP lists, for the In Progress column, each project with a WIP limit
+	// and how many of its tasks are processing.
+	WIP []BoardWIP `json:"wip,omitempty"`
+}
+
+// BoardWIP is a project's processing count against its WIP limit.
+type BoardWIP struct {
+	Project    string `json:"project"`
+	Processing int    `json:"processing"`
+	Limit      int    `json:"limit"`
 }
 
 // BoardEntry is a single task card in the board.
 type BoardEntry struct {
-	ID      int64         `json:"id"`
-	Title   string        `json:"title"`
-	Project string        `json:"project"`
-	Type    string        `json:"type"`
-	Pinned  bool          `json:"pinned"`
-	AgeHint string        `json:"age_hint"`
-	PR      *prStatusJSON `json:"pr,omitempty"`
+	ID       int64  `json:"id"`
+	Title    string `json:"title"`
+	Project  string `json:"project"`
+	Type     string `json:"type"`
+	Pinned   bool   `json:"pinned"`
+	Priority int    `json:"priority,omitempty"`
+	// BlockedReason says why a blocked task is blocked (see db.BlockedReasons).
+	BlockedReason string        `json:"blocked_reason,omitempty"`
+	AgeHint       string        `json:"age_hint"`
+	PR            *prStatusJSON `json:"pr,omitempty"`
 }
 
-// BuildBoardSnapshot groups tasks into kanban columns.
+// BuildBoardSnapshot groups tasks into kanban columns. Archived tasks are left
+// out.
 func BuildBoardSnapshot(tasks []*db.Task, limit int) BoardSnapshot {
+	return buildBoardSnapshot(tasks, limit, false)
+}
+
+// BuildBoardSnapshotWithArchived is BuildBoardSnapshot plus an Archived column.
+func BuildBoardSnapshotWithArchived(tasks []*db.Task, limit int) BoardSnapshot {
+	return buildBoardSnapshot(tasks, limit, true)
+}
+
+func buildBoardSnapshot(tasks []*db.Task, limit int, includeArchived bool) BoardSnapshot {
 	sections := []struct {
 		status string
 		label  string
@@ -45,10 +68,16 @@ func BuildBoardSnapshot(tasks []*db.Task, limit int) BoardSnapshot {
 		{db.StatusBlocked, "Blocked"},
 		{db.StatusDone, "Done"},
 	}
+	if includeArchived {
+		sections = append(sections, struct {
+			status string
+			label  string
+		}{db.StatusArchived, "Archived"})
+	}
 
 	grouped := make(map[string][]*db.Task)
 	for _, task := range tasks {
-		if task.Status == db.StatusArchived {
+		if task.Status == db.StatusArchived && !includeArchived {
 			continue
 		}
 		status := task.Status
@@ -74,13 +103,16 @@ func BuildBoardSnapshot(tasks []*db.Task, limit int) BoardSnapshot {
 				break
 			}
 			entry := BoardEntry{
-				ID:      task.ID,
-				Title:   truncateTitle(task.Title, 80),
-				Project: task.Project,
-				Type:    task.Type,
-				Pinned:  task.Pinned,
-				AgeHint: boardAgeHint(task),
-				PR:      toPRStatusJSON(task.PRInfoJSON),
+				ID:       task.ID,
+				Title:    truncateTitle(task.Title, 80),
+				Project:  task.Project,
+				Type:     task.Type,
+				Pinned:   task.Pinned,
+				Priority: task.Priority,
+				AgeHint:  boardAgeHint(task),
+				PR:       toPRStatusJSON(task.PRInfoJSON),
+
+				BlockedReason: task.BlockedReason,
 			}
 			column.Tasks = append(column.Tasks, entry)
 		}
@@ -90,11 +122,25 @@ func BuildBoardSnapshot(tasks []*db.Task, limit int) BoardSnapshot {
 	return snapshot
 }
 
+// sortTasksForBoard orders a column: pinned tasks first, then tasks placed by
+// hand ('ty reorder') in their order, then higher priority, then most recent
+// activity.
 func sortTasksForBoard(tasks []*db.Task) {
 	sort.SliceStable(tasks, func(i, j int) bool {
 		if tasks[i].Pinned != tasks[j].Pinned {
 			return tasks[i].Pinned
 		}
+		if pi, pj := tasks[i].Position, tasks[j].Position; pi != nil || pj != nil {
+			if pi == nil || pj == nil {
+				return pi != nil
+			}
+			if *pi != *pj {
+				return *pi < *pj
+			}
+		}
+		if tasks[i].Priority != tasks[j].Priority {
+			return tasks[i].Priority > tasks[j].Priority
+		}
 		return boardReferenceTime(tasks[i]).After(boardReferenceTime(tasks[j]))
 	})
 }
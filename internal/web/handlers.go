@@ -98,10 +98,15 @@ func (s *Server) handleListTasks(w http.ResponseWriter, r *http.Request) {
 		Status:        q.Get("status"),
 		Type:          q.Get("type"),
 		Project:       q.Get("project"),
+		Assignee:      q.Get("assignee"),
 		Limit:         limit,
 		Offset:        offset,
 		IncludeClosed: q.Get("all") == "true",
 	}
+	// ?unassigned=true scopes to tasks nobody owns (e.g. a shared triage view).
+	if q.Get("unassigned") == "true" {
+		opts.Unassigned = true
+	}
 
 	tasks, err := s.db.ListTasks(opts)
 	if err != nil {
@@ -126,6 +131,7 @@ type createTaskRequest struct {
 	Tags           string `json:"tags"`
 	Pinned         bool   `json:"pinned"`
 	PermissionMode string `json:"permission_mode"`
+	Assignee       string `json:"assignee"`
 }
 
 func (s *Server) handleCreateTask(w http.ResponseWriter, r *http.Request) {
@@ -172,6 +178,7 @@ func (s *Server) handleCreateTask(w http.ResponseWriter, r *http.Request) {
 		Tags:           req.Tags,
 		Pinned:         req.Pinned,
 		PermissionMode: db.NormalizePermissionMode(req.PermissionMode),
+		Assignee:       req.Assignee,
 	}
 
 	if err := s.db.CreateTask(task); err != nil {
@@ -217,6 +224,7 @@ type updateTaskRequest struct {
 	PermissionMode *string `json:"permission_mode"`
 	EffortLevel    *string `json:"effort_level"`
 	Model          *string `json:"model"`
+	Assignee       *string `json:"assignee"`
 }
 
 func (s *Server) handleUpdateTask(w http.ResponseWriter, r *http.Request) {
@@ -265,6 +273,9 @@ func (s *Server) handleUpdateTask(w http.ResponseWriter, r *http.Request) {
 	if req.Model != nil {
 		task.Model = *req.Model
 	}
+	if req.Assignee != nil {
+		task.Assignee = *req.Assignee
+	}
 
 	if err := s.db.UpdateTask(task); err != nil {
 		jsonErr(w, "failed to update task", http.StatusInternalServerError)
@@ -1043,6 +1054,8 @@ type taskJSON struct {
 	Executor       string        `json:"executor"`
 	Pinned         bool          `json:"pinned"`
 	Tags           string        `json:"tags"`
+	Assignee       string        `json:"assignee,omitempty"`
+	Priority       int           `json:"priority,omitempty"`
 	PermissionMode string        `json:"permission_mode"`
 	BranchName     string        `json:"branch_name"`
 	Port           int           `json:"port,omitempty"`
@@ -1097,6 +1110,8 @@ func toTaskJSON(t *db.Task) *taskJSON {
 		Executor:       t.Executor,
 		Pinned:         t.Pinned,
 		Tags:           t.Tags,
+		Assignee:       t.Assignee,
+		Priority:       t.Priority,
 		PermissionMode: t.EffectivePermissionMode(),
 		BranchName:     t.BranchName,
 		Port:           t.Port,
@@ -15,6 +15,7 @@ import (
 
 	"github.com/bborn/workflow/internal/autocomplete"
 	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/profile"
 )
 
 // SessionManager is the subset of executor functionality the API needs to
@@ -341,7 +342,7 @@ func (s *Server) findTaskWindowTarget(taskID int64) string {
 	windowName := fmt.Sprintf("task-%d", taskID)
 	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
 		parts := strings.SplitN(line, ":", 3)
-		if len(parts) == 3 && parts[2] == windowName && strings.HasPrefix(parts[0], "task-daemon-") {
+		if len(parts) == 3 && parts[2] == windowName && strings.HasPrefix(parts[0], profile.DaemonSessionPrefix()) {
 			return parts[0] + ":" + parts[1]
 		}
 	}
@@ -0,0 +1,136 @@
+package web
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/executor"
+)
+
+// metadataResponse bundles the lookup lists a create/edit form needs (the same
+// data `ty` shell completion draws from) so the SPA can populate its dropdowns
+// and tag suggestions in one request instead of four.
+type metadataResponse struct {
+	Projects  []metadataProject  `json:"projects"`
+	Types     []metadataType     `json:"types"`
+	Tags      []string           `json:"tags"`
+	Executors []metadataExecutor `json:"executors"`
+	Statuses  []string           `json:"statuses"`
+}
+
+type metadataProject struct {
+	Name  string `json:"name"`
+	Path  string `json:"path,omitempty"`
+	Color string `json:"color,omitempty"`
+}
+
+type metadataType struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+}
+
+type metadataExecutor struct {
+	Name      string `json:"name"`
+	Available bool   `json:"available"`
+	Default   bool   `json:"default"`
+}
+
+func (s *Server) buildMetadata() (*metadataResponse, error) {
+	meta := &metadataResponse{
+		Projects:  []metadataProject{},
+		Types:     []metadataType{},
+		Tags:      []string{},
+		Executors: []metadataExecutor{},
+		Statuses: []string{
+			db.StatusBacklog, db.StatusQueued, db.StatusProcessing,
+			db.StatusBlocked, db.StatusDone, db.StatusArchived,
+		},
+	}
+
+	projects, err := s.db.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		meta.Projects = append(meta.Projects, metadataProject{Name: p.Name, Path: p.Path, Color: p.Color})
+	}
+
+	types, err := s.db.ListTaskTypes()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range types {
+		label := t.Label
+		if label == "" {
+			label = t.Name
+		}
+		meta.Types = append(meta.Types, metadataType{Name: t.Name, Label: label})
+	}
+
+	tags, err := s.db.GetTagsList()
+	if err != nil {
+		return nil, err
+	}
+	// GetTagsList iterates a map; sort so the ETag is stable across requests.
+	sort.Strings(tags)
+	meta.Tags = append(meta.Tags, tags...)
+
+	// Without a SessionManager (e.g. `ty serve` without a daemon-owned
+	// executor), list every executor ty knows and assume each is available.
+	all := executor.ExecutorNames()
+	available := make(map[string]bool)
+	if s.sessions != nil {
+		all = s.sessions.AllExecutors()
+		for _, name := range s.sessions.AvailableExecutors() {
+			available[name] = true
+		}
+	} else {
+		for _, name := range all {
+			available[name] = true
+		}
+	}
+	defaultExecutor := db.DefaultExecutor()
+	for _, name := range all {
+		meta.Executors = append(meta.Executors, metadataExecutor{
+			Name:      name,
+			Available: available[name],
+			Default:   name == defaultExecutor,
+		})
+	}
+
+	return meta, nil
+}
+
+// handleMetadata serves projects, types, tags, executors and statuses in one
+// payload. The body is hashed into a strong ETag so a browser polling it gets
+// a cheap 304 until something actually changes.
+func (s *Server) handleMetadata(w http.ResponseWriter, r *http.Request) {
+	meta, err := s.buildMetadata()
+	if err != nil {
+		jsonErr(w, "failed to load metadata", http.StatusInternalServerError)
+		return
+	}
+
+	body, err := json.Marshal(meta)
+	if err != nil {
+		jsonErr(w, "failed to encode metadata", http.StatusInternalServerError)
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if match := r.Header.Get("If-None-Match"); match != "" && match == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+	w.Write([]byte("\n"))
+}
@@ -0,0 +1,114 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/executor"
+)
+
+func TestHandleMetadata(t *testing.T) {
+	sessions := &mockSessions{
+		available: []string{"claude"},
+		all:       []string{"claude", "codex"},
+	}
+	srv, database, _ := setupServerWithSessions(t, sessions)
+	if err := database.CreateProject(&db.Project{Name: "webapp", Path: "/tmp/webapp"}); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	createTestTask(t, database, &db.Task{Title: "a", Status: db.StatusBacklog, Project: "webapp", Tags: "ui, bug"})
+	createTestTask(t, database, &db.Task{Title: "b", Status: db.StatusBacklog, Project: "webapp", Tags: "bug"})
+
+	req := httptest.NewRequest("GET", "/api/metadata", nil)
+	w := httptest.NewRecorder()
+	srv.handleMetadata(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if w.Header().Get("ETag") == "" {
+		t.Fatal("expected an ETag header")
+	}
+
+	var meta metadataResponse
+	if err := json.NewDecoder(w.Body).Decode(&meta); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	foundProject := false
+	for _, p := range meta.Projects {
+		if p.Name == "webapp" && p.Path == "/tmp/webapp" {
+			foundProject = true
+		}
+	}
+	if !foundProject {
+		t.Errorf("expected webapp project in %+v", meta.Projects)
+	}
+	if len(meta.Types) == 0 {
+		t.Error("expected built-in task types")
+	}
+	if len(meta.Tags) != 2 || meta.Tags[0] != "bug" || meta.Tags[1] != "ui" {
+		t.Errorf("expected sorted tags [bug ui], got %v", meta.Tags)
+	}
+	if len(meta.Executors) != 2 {
+		t.Fatalf("expected 2 executors, got %d", len(meta.Executors))
+	}
+	if !meta.Executors[0].Available || meta.Executors[1].Available {
+		t.Errorf("unexpected availability: %+v", meta.Executors)
+	}
+	if len(meta.Statuses) == 0 {
+		t.Error("expected statuses")
+	}
+}
+
+func TestHandleMetadata_ETagNotModified(t *testing.T) {
+	srv, database, _ := setupServer(t)
+
+	get := func(etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/metadata", nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		w := httptest.NewRecorder()
+		srv.handleMetadata(w, req)
+		return w
+	}
+
+	first := get("")
+	etag := first.Header().Get("ETag")
+
+	if w := get(etag); w.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for matching ETag, got %d", w.Code)
+	}
+
+	// A new tag changes the payload, so the old ETag must no longer match.
+	createTestTask(t, database, &db.Task{Title: "x", Status: db.StatusBacklog, Tags: "fresh"})
+	w := get(etag)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 after data changed, got %d", w.Code)
+	}
+	if w.Header().Get("ETag") == etag {
+		t.Error("expected ETag to change after data changed")
+	}
+}
+
+func TestBuildMetadata_NoSessionsListsEveryExecutor(t *testing.T) {
+	srv, _, _ := setupServer(t)
+
+	meta, err := srv.buildMetadata()
+	if err != nil {
+		t.Fatalf("buildMetadata: %v", err)
+	}
+	names := executor.ExecutorNames()
+	if len(meta.Executors) != len(names) {
+		t.Fatalf("expected %d executors, got %+v", len(names), meta.Executors)
+	}
+	for i, e := range meta.Executors {
+		if e.Name != names[i] || !e.Available {
+			t.Errorf("executor %d = %+v, want available %s", i, e, names[i])
+		}
+	}
+}
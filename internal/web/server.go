@@ -61,7 +61,8 @@ func cors(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PATCH, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, If-None-Match")
+		w.Header().Set("Access-Control-Expose-Headers", "ETag")
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
 			return
@@ -129,6 +130,7 @@ func New(cfg Config) *Server {
 	mux.HandleFunc("PATCH /api/settings", s.handleUpdateSettings)
 	mux.HandleFunc("GET /api/executors", s.handleListExecutors)
 	mux.HandleFunc("POST /api/autocomplete", s.handleAutocomplete)
+	mux.HandleFunc("GET /api/metadata", s.handleMetadata)
 
 	// Dependencies
 	mux.HandleFunc("GET /api/tasks/{id}/deps", s.handleGetDeps)
@@ -161,6 +163,7 @@ func New(cfg Config) *Server {
 
 	// Events
 	mux.HandleFunc("GET /api/events", s.handleListEvents)
+	mux.HandleFunc("GET /api/events/stream", s.handleEventStream)
 
 	// Status
 	mux.HandleFunc("GET /api/status", s.handleStatus)
@@ -1,6 +1,7 @@
 package web
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -152,6 +153,29 @@ func TestHandleBoard_WithTasks(t *testing.T) {
 	}
 }
 
+func TestSortTasksForBoard_PinnedThenPriority(t *testing.T) {
+	now := time.Now()
+	at := func(ago time.Duration) db.LocalTime { return db.LocalTime{Time: now.Add(-ago)} }
+	tasks := []*db.Task{
+		{ID: 1, Status: db.StatusBacklog, CreatedAt: at(time.Minute)},
+		{ID: 2, Status: db.StatusBacklog, Priority: 5, CreatedAt: at(time.Hour)},
+		{ID: 3, Status: db.StatusBacklog, Pinned: true, CreatedAt: at(2 * time.Hour)},
+		{ID: 4, Status: db.StatusBacklog, Priority: 5, CreatedAt: at(time.Second)},
+	}
+	sortTasksForBoard(tasks)
+
+	var got []int64
+	for _, task := range tasks {
+		got = append(got, task.ID)
+	}
+	want := []int64{3, 4, 2, 1}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("order = %v, want %v", got, want)
+		}
+	}
+}
+
 // --- Tasks CRUD ---
 
 func TestHandleListTasks(t *testing.T) {
@@ -732,6 +756,21 @@ func TestBuildBoardSnapshot(t *testing.T) {
 	}
 }
 
+func TestBuildBoardSnapshotWithArchived(t *testing.T) {
+	tasks := []*db.Task{
+		{ID: 1, Title: "T1", Status: db.StatusBacklog},
+		{ID: 4, Title: "T4", Status: db.StatusArchived},
+	}
+	snap := BuildBoardSnapshotWithArchived(tasks, 50)
+	if len(snap.Columns) != 5 {
+		t.Fatalf("expected 5 columns, got %d", len(snap.Columns))
+	}
+	archived := snap.Columns[4]
+	if archived.Status != db.StatusArchived || archived.Count != 1 || archived.Tasks[0].ID != 4 {
+		t.Errorf("unexpected archived column: %+v", archived)
+	}
+}
+
 // --- CORS ---
 
 func TestCORS(t *testing.T) {
@@ -753,3 +792,39 @@ func TestCORS(t *testing.T) {
 func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
+
+// --- Event stream ---
+
+func TestHandleEventStream_SendsEventsAfterID(t *testing.T) {
+	srv, database, _ := setupServer(t)
+
+	first := &db.Task{Title: "Before", Status: db.StatusBacklog}
+	if err := database.CreateTask(first); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	afterID, err := database.LatestEventID()
+	if err != nil {
+		t.Fatalf("LatestEventID: %v", err)
+	}
+	second := &db.Task{Title: "After", Status: db.StatusBacklog}
+	if err := database.CreateTask(second); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest("GET", fmt.Sprintf("/api/events/stream?after=%d", afterID), nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	srv.handleEventStream(w, req)
+
+	body := w.Body.String()
+	if !strings.Contains(body, "event: event\n") {
+		t.Fatalf("expected an event message, got:\n%s", body)
+	}
+	if strings.Contains(body, `"message":"Before"`) {
+		t.Errorf("stream replayed an event at or before ?after:\n%s", body)
+	}
+	if !strings.Contains(body, `"event_type":"task.created"`) || !strings.Contains(body, `"message":"After"`) {
+		t.Errorf("expected the task.created event for the second task, got:\n%s", body)
+	}
+}
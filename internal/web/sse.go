@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/bborn/workflow/internal/db"
+	"github.com/bborn/workflow/internal/events"
 )
 
 func (s *Server) handleTaskStream(w http.ResponseWriter, r *http.Request) {
@@ -127,6 +128,62 @@ func (s *Server) handleBoardStream(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleEventStream streams each new event_log row as an SSE "event" message
+// whose data is the row as JSON. Pass ?after=<id> to resume after a known
+// event; otherwise only events logged after the request are sent.
+func (s *Server) handleEventStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	lastEventID, err := strconv.ParseInt(r.URL.Query().Get("after"), 10, 64)
+	if err != nil {
+		if lastEventID, err = s.db.LatestEventID(); err != nil {
+			http.Error(w, "failed to read event log", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprintf(w, "event: heartbeat\ndata: {}\n\n")
+			flusher.Flush()
+		case <-ticker.C:
+			logged, err := s.db.ListEventsAfter(lastEventID)
+			if err != nil {
+				continue
+			}
+			for _, event := range logged {
+				data, _ := json.Marshal(events.Versioned(event))
+				fmt.Fprintf(w, "id: %d\nevent: event\ndata: %s\n\n", event.ID, data)
+				lastEventID = event.ID
+			}
+			if len(logged) > 0 {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
 func (s *Server) sendBoardEvent(w http.ResponseWriter, flusher http.Flusher) {
 	tasks, err := s.db.ListTasks(db.ListTasksOptions{IncludeClosed: true, Limit: 500})
 	if err != nil {